@@ -0,0 +1,405 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/pmezard/go-difflib/difflib"
+	"github.com/qq1060656096/drugo/cmd/drugo/internal/tpl"
+	"github.com/qq1060656096/drugo/pkg/gomod"
+	"github.com/spf13/cobra"
+	"golang.org/x/tools/go/ast/astutil"
+)
+
+// The module hosting the built-in service providers, at the version generated projects require.
+const (
+	providerModule  = "github.com/qq1060656096/drugo-provider"
+	providerVersion = "v0.0.8"
+)
+
+// drugoPackage is the import path of the package that creates drugo apps.
+const drugoPackage = "github.com/qq1060656096/drugo/drugo"
+
+// serviceProvider describes a service `drugo add service` can wire into a project.
+type serviceProvider struct {
+	Name    string // service and config section name, e.g. "db"
+	Module  string // module required in go.mod
+	Version string // version of Module
+	Import  string // package whose New creates the service
+	Config  string // template name of conf/<Name>.yaml, empty when the service has no config
+}
+
+// serviceCatalog lists the providers known to `drugo add service`, sorted by name.
+// Supporting another provider only needs an entry here.
+var serviceCatalog = []serviceProvider{
+	{Name: "db", Module: providerModule, Version: providerVersion, Import: providerModule + "/dbsvc", Config: tpl.ProjectDbYaml},
+	{Name: "gin", Module: providerModule, Version: providerVersion, Import: providerModule + "/ginsrv", Config: tpl.ProjectGinYaml},
+	{Name: "i18n", Module: providerModule, Version: providerVersion, Import: providerModule + "/i18nsvc", Config: tpl.ProjectI18nYaml},
+	{Name: "redis", Module: providerModule, Version: providerVersion, Import: providerModule + "/redissvc", Config: tpl.ProjectRedisYaml},
+}
+
+// lookupProvider returns the catalog entry named name.
+func lookupProvider(name string) (serviceProvider, bool) {
+	for _, p := range serviceCatalog {
+		if p.Name == name {
+			return p, true
+		}
+	}
+	return serviceProvider{}, false
+}
+
+// catalogNames returns the names of all catalog entries.
+func catalogNames() []string {
+	names := make([]string, 0, len(serviceCatalog))
+	for _, p := range serviceCatalog {
+		names = append(names, p.Name)
+	}
+	return names
+}
+
+// fileChange is a file `drugo add service` creates or modifies.
+type fileChange struct {
+	Path    string // path relative to the project root
+	Old     []byte // current content, nil when the file is created
+	New     []byte // new content
+	Created bool
+}
+
+// addCmd and addServiceCmd help texts are set by localize.
+var addCmd = &cobra.Command{
+	Use: "add",
+}
+
+var addServiceCmd = &cobra.Command{
+	Example: `  drugo add service redis
+  drugo add service db --dry-run
+  drugo add service i18n --main cmd/api`,
+	Args: cobra.ExactArgs(1),
+	RunE: runAddService,
+}
+
+func init() {
+	rootCmd.AddCommand(addCmd)
+	addCmd.AddCommand(addServiceCmd)
+	addServiceFlags(addServiceCmd)
+}
+
+// addServiceFlags registers the flags read by runAddService on c.
+func addServiceFlags(c *cobra.Command) {
+	c.Flags().Bool("dry-run", false, "")
+	c.Flags().String("main", "", "")
+}
+
+func runAddService(cmd *cobra.Command, args []string) error {
+	name := strings.ToLower(args[0])
+	provider, ok := lookupProvider(name)
+	if !ok {
+		return newError(msgAddUnknown, args[0], strings.Join(catalogNames(), ", "))
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		return newError(msgWdFailed, err)
+	}
+	projectRoot, ok := gomod.FindGoModRoot(wd)
+	if !ok {
+		return newError(msgNotInProject, wd)
+	}
+	modPath, err := gomod.ModuleName(projectRoot)
+	if err != nil {
+		return newError(msgGoModFailed, err)
+	}
+	mainPkg, err := resolveMainPackage(cmd, projectRoot)
+	if err != nil {
+		return err
+	}
+
+	changes, err := planAddService(projectRoot, modPath, mainPkg, provider)
+	if err != nil {
+		return err
+	}
+
+	out := cmd.OutOrStdout()
+	if len(changes) == 0 {
+		fmt.Fprint(out, msg(msgAddUpToDate, provider.Name))
+		return nil
+	}
+	if dryRun, _ := cmd.Flags().GetBool("dry-run"); dryRun {
+		for _, c := range changes {
+			if err := writeUnifiedDiff(out, c); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	fmt.Fprint(out, msg(msgAddSuccess, provider.Name))
+	requireAdded := false
+	for _, c := range changes {
+		if err := os.WriteFile(filepath.Join(projectRoot, c.Path), c.New, 0644); err != nil {
+			return newError(msgFileFailed, c.Path, err)
+		}
+		if c.Created {
+			fmt.Fprint(out, msg(msgAddCreated, c.Path))
+		} else {
+			fmt.Fprint(out, msg(msgAddModified, c.Path))
+		}
+		requireAdded = requireAdded || c.Path == "go.mod"
+	}
+	if requireAdded {
+		fmt.Fprint(out, msg(msgAddNext))
+	}
+	return nil
+}
+
+// planAddService returns the changes wiring provider into the project at projectRoot,
+// whose main package is mainPkg. Steps that are already done produce no change.
+func planAddService(projectRoot, modPath, mainPkg string, provider serviceProvider) ([]fileChange, error) {
+	var changes []fileChange
+
+	gomodPath := filepath.Join(projectRoot, "go.mod")
+	data, err := os.ReadFile(gomodPath)
+	if err != nil {
+		return nil, newError(msgGoModFailed, err)
+	}
+	updated, changed, err := gomod.AddRequire(data, provider.Module, provider.Version)
+	if err != nil {
+		return nil, newError(msgGoModFailed, err)
+	}
+	if changed {
+		changes = append(changes, fileChange{Path: "go.mod", Old: data, New: updated})
+	}
+
+	mainFile, err := findMainFile(filepath.Join(projectRoot, filepath.FromSlash(mainPkg)))
+	if err != nil {
+		return nil, err
+	}
+	rel := path.Join(mainPkg, filepath.Base(mainFile))
+	src, err := os.ReadFile(mainFile)
+	if err != nil {
+		return nil, newError(msgAddMainFailed, rel, err)
+	}
+	edited, err := addServiceToMain(src, provider)
+	if err != nil {
+		return nil, newError(msgAddMainFailed, rel, err)
+	}
+	if !bytes.Equal(src, edited) {
+		changes = append(changes, fileChange{Path: rel, Old: src, New: edited})
+	}
+
+	if provider.Config != "" {
+		confRel := path.Join(configDir, provider.Name+".yaml")
+		if _, err := os.Stat(filepath.Join(projectRoot, filepath.FromSlash(confRel))); os.IsNotExist(err) {
+			var buf bytes.Buffer
+			t, err := template.New(confRel).Parse(tpl.Get(provider.Config))
+			if err != nil {
+				return nil, newError(msgTplParse, confRel, err)
+			}
+			if err := t.Execute(&buf, ProjectData{Name: filepath.Base(projectRoot), ModPath: modPath}); err != nil {
+				return nil, newError(msgTplExecFailed, confRel, err)
+			}
+			changes = append(changes, fileChange{Path: confRel, New: buf.Bytes(), Created: true})
+		}
+	}
+	return changes, nil
+}
+
+// findMainFile returns the file in dir that declares func main.
+func findMainFile(dir string) (string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return "", newError(msgAddNoMain, dir)
+	}
+	fset := token.NewFileSet()
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || filepath.Ext(name) != ".go" || strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+		file := filepath.Join(dir, name)
+		f, err := parser.ParseFile(fset, file, nil, parser.SkipObjectResolution)
+		if err != nil {
+			continue
+		}
+		for _, decl := range f.Decls {
+			if fn, ok := decl.(*ast.FuncDecl); ok && fn.Recv == nil && fn.Name.Name == "main" {
+				return file, nil
+			}
+		}
+	}
+	return "", newError(msgAddNoMain, dir)
+}
+
+// addServiceToMain registers provider in the drugo app created by src and imports its package.
+// The registration is placed after the last drugo.WithService option; src is returned unchanged
+// when both the import and the registration are present. The result is gofmt'ed.
+func addServiceToMain(src []byte, provider serviceProvider) ([]byte, error) {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "main.go", src, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+	drugoName, ok := importName(f, drugoPackage)
+	if !ok {
+		return nil, fmt.Errorf("%s is not imported", drugoPackage)
+	}
+	call := findAppCall(f, drugoName)
+	if call == nil {
+		return nil, fmt.Errorf("no %s.MustNewApp, %s.New or %s.NewE call", drugoName, drugoName, drugoName)
+	}
+	pkgName, imported := importName(f, provider.Import)
+	if !imported {
+		pkgName = path.Base(provider.Import)
+	}
+	if imported && hasRegistration(call, drugoName, pkgName) {
+		return src, nil
+	}
+
+	out := src
+	if !hasRegistration(call, drugoName, pkgName) {
+		out = insertOption(fset, src, call, isWithService(drugoName), fmt.Sprintf("%s.WithService(%s.New())", drugoName, pkgName))
+	}
+	if !imported {
+		if f, err = parser.ParseFile(fset, "main.go", out, parser.ParseComments); err != nil {
+			return nil, err
+		}
+		astutil.AddImport(fset, f, provider.Import)
+		var buf bytes.Buffer
+		if err := format.Node(&buf, fset, f); err != nil {
+			return nil, err
+		}
+		out = buf.Bytes()
+	}
+	return format.Source(out)
+}
+
+// importName returns the name under which f imports importPath.
+func importName(f *ast.File, importPath string) (string, bool) {
+	for _, spec := range f.Imports {
+		if p, err := strconv.Unquote(spec.Path.Value); err != nil || p != importPath {
+			continue
+		}
+		if spec.Name != nil {
+			return spec.Name.Name, true
+		}
+		return path.Base(importPath), true
+	}
+	return "", false
+}
+
+// findAppCall returns the first call of drugo.MustNewApp, drugo.New or drugo.NewE in f,
+// where drugoName is the name the drugo package is imported as.
+func findAppCall(f *ast.File, drugoName string) *ast.CallExpr {
+	var found *ast.CallExpr
+	ast.Inspect(f, func(n ast.Node) bool {
+		if found != nil {
+			return false
+		}
+		if call, ok := n.(*ast.CallExpr); ok {
+			switch selectorName(call.Fun, drugoName) {
+			case "MustNewApp", "New", "NewE":
+				found = call
+			}
+		}
+		return true
+	})
+	return found
+}
+
+// selectorName returns Sel of expr when expr is pkg.Sel, or "" otherwise.
+func selectorName(expr ast.Expr, pkg string) string {
+	sel, ok := expr.(*ast.SelectorExpr)
+	if !ok {
+		return ""
+	}
+	if x, ok := sel.X.(*ast.Ident); !ok || x.Name != pkg {
+		return ""
+	}
+	return sel.Sel.Name
+}
+
+// isWithService returns a matcher for drugo.WithService(...) options.
+func isWithService(drugoName string) func(ast.Expr) bool {
+	return func(e ast.Expr) bool {
+		call, ok := e.(*ast.CallExpr)
+		return ok && selectorName(call.Fun, drugoName) == "WithService"
+	}
+}
+
+// hasRegistration reports whether call has a drugo.WithService(pkg.New()) option.
+func hasRegistration(call *ast.CallExpr, drugoName, pkg string) bool {
+	for _, arg := range call.Args {
+		if !isWithService(drugoName)(arg) {
+			continue
+		}
+		inner := arg.(*ast.CallExpr)
+		if len(inner.Args) != 1 {
+			continue
+		}
+		if newCall, ok := inner.Args[0].(*ast.CallExpr); ok && selectorName(newCall.Fun, pkg) == "New" {
+			return true
+		}
+	}
+	return false
+}
+
+// insertOption inserts option into the arguments of call, after the last argument matching after
+// or after the last argument when none matches. Options of multi-line calls get their own line
+// with the indentation of the argument they follow, so comments stay attached to their lines.
+func insertOption(fset *token.FileSet, src []byte, call *ast.CallExpr, after func(ast.Expr) bool, option string) []byte {
+	insert := func(offset int, text string) []byte {
+		out := make([]byte, 0, len(src)+len(text))
+		out = append(out, src[:offset]...)
+		out = append(out, text...)
+		return append(out, src[offset:]...)
+	}
+
+	if len(call.Args) == 0 {
+		return insert(fset.Position(call.Lparen).Offset+1, option)
+	}
+	anchor := call.Args[len(call.Args)-1]
+	for _, arg := range call.Args {
+		if after(arg) {
+			anchor = arg
+		}
+	}
+	end := fset.Position(anchor.End())
+	if fset.Position(call.Rparen).Line == end.Line {
+		return insert(end.Offset, ", "+option)
+	}
+
+	start := fset.Position(anchor.Pos())
+	line := src[start.Offset-(start.Column-1) : start.Offset]
+	indent := line[:len(line)-len(bytes.TrimLeft(line, " \t"))]
+	lineEnd := bytes.IndexByte(src[end.Offset:], '\n')
+	if lineEnd < 0 {
+		return insert(end.Offset, ", "+option)
+	}
+	return insert(end.Offset+lineEnd+1, string(indent)+option+",\n")
+}
+
+// writeUnifiedDiff writes the unified diff of c to w.
+func writeUnifiedDiff(w io.Writer, c fileChange) error {
+	from, a := "a/"+c.Path, difflib.SplitLines(string(c.Old))
+	if c.Created {
+		from, a = "/dev/null", nil
+	}
+	return difflib.WriteUnifiedDiff(w, difflib.UnifiedDiff{
+		A:        a,
+		B:        difflib.SplitLines(string(c.New)),
+		FromFile: from,
+		ToFile:   "b/" + c.Path,
+		Context:  3,
+	})
+}
@@ -0,0 +1,173 @@
+package cmd
+
+import (
+	"bytes"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupAddProject generates a project with `drugo new`, drops the drugo-provider requirement
+// and conf/i18n.yaml, and changes into it.
+func setupAddProject(t *testing.T) string {
+	t.Helper()
+	t.Chdir(t.TempDir())
+	require.NoError(t, createProject("shop", "github.com/acme/shop", "v1.0.0"))
+	root, err := filepath.Abs("shop")
+	require.NoError(t, err)
+
+	gomodPath := filepath.Join(root, "go.mod")
+	data, err := os.ReadFile(gomodPath)
+	require.NoError(t, err)
+	data = []byte(strings.Replace(string(data), "\t"+providerModule+" "+providerVersion+"\n", "", 1))
+	require.NoError(t, os.WriteFile(gomodPath, data, 0644))
+	require.NoError(t, os.Remove(filepath.Join(root, "conf", "i18n.yaml")))
+
+	t.Chdir(root)
+	return root
+}
+
+// runAddServiceArgs runs `drugo add service` with args and returns its stdout.
+func runAddServiceArgs(t *testing.T, args ...string) (string, error) {
+	t.Helper()
+	var out bytes.Buffer
+	c := &cobra.Command{}
+	addServiceFlags(c)
+	c.SetOut(&out)
+	require.NoError(t, c.Flags().Parse(args))
+	err := runAddService(c, c.Flags().Args())
+	return out.String(), err
+}
+
+// countRegistrations parses the main.go at path and counts the drugo.WithService(pkg.New()) options.
+func countRegistrations(t *testing.T, path, pkg string) int {
+	t.Helper()
+	f, err := parser.ParseFile(token.NewFileSet(), path, nil, parser.AllErrors)
+	require.NoError(t, err)
+	call := findAppCall(f, "drugo")
+	require.NotNil(t, call)
+	n := 0
+	for _, arg := range call.Args {
+		if hasRegistration(&ast.CallExpr{Args: []ast.Expr{arg}}, "drugo", pkg) {
+			n++
+		}
+	}
+	return n
+}
+
+// TestRunAddService tests wiring a provider into a generated project, and that running it again changes nothing.
+func TestRunAddService(t *testing.T) {
+	useLang(t, langEn)
+	root := setupAddProject(t)
+	mainPath := filepath.Join(root, "cmd", "app", "main.go")
+
+	out, err := runAddServiceArgs(t, "i18n")
+	require.NoError(t, err)
+	assert.Equal(t, `Added service i18n:
+  modified go.mod
+  modified cmd/app/main.go
+  created  conf/i18n.yaml
+
+Next: run go mod tidy to download the dependencies
+`, out)
+
+	gomodData, err := os.ReadFile(filepath.Join(root, "go.mod"))
+	require.NoError(t, err)
+	assert.Contains(t, string(gomodData), providerModule+" "+providerVersion)
+	assert.FileExists(t, filepath.Join(root, "conf", "i18n.yaml"))
+	assert.Equal(t, 1, countRegistrations(t, mainPath, "i18nsvc"))
+	assert.Equal(t, 1, countRegistrations(t, mainPath, "redissvc"))
+	first, err := os.ReadFile(mainPath)
+	require.NoError(t, err)
+	assert.Contains(t, string(first), "\n\t\"github.com/qq1060656096/drugo-provider/i18nsvc\"\n")
+	assert.Contains(t, string(first), "drugo.WithService(redissvc.New()),\n\t\tdrugo.WithService(i18nsvc.New()),\n")
+
+	out, err = runAddServiceArgs(t, "i18n")
+	require.NoError(t, err)
+	assert.Equal(t, "Service i18n is already wired in, nothing to change\n", out)
+	second, err := os.ReadFile(mainPath)
+	require.NoError(t, err)
+	assert.Equal(t, string(first), string(second))
+	assert.Equal(t, 1, countRegistrations(t, mainPath, "i18nsvc"))
+}
+
+// TestRunAddService_DryRun tests that --dry-run prints unified diffs and writes nothing.
+func TestRunAddService_DryRun(t *testing.T) {
+	useLang(t, langEn)
+	root := setupAddProject(t)
+	mainPath := filepath.Join(root, "cmd", "app", "main.go")
+	before, err := os.ReadFile(mainPath)
+	require.NoError(t, err)
+
+	out, err := runAddServiceArgs(t, "i18n", "--dry-run")
+	require.NoError(t, err)
+	assert.Contains(t, out, "--- a/go.mod\n+++ b/go.mod\n")
+	assert.Contains(t, out, "+\t"+providerModule+" "+providerVersion+"\n")
+	assert.Contains(t, out, "--- a/cmd/app/main.go\n+++ b/cmd/app/main.go\n")
+	assert.Contains(t, out, "+\t\tdrugo.WithService(i18nsvc.New()),\n")
+	assert.Contains(t, out, "--- /dev/null\n+++ b/conf/i18n.yaml\n")
+
+	after, err := os.ReadFile(mainPath)
+	require.NoError(t, err)
+	assert.Equal(t, before, after)
+	assert.NoFileExists(t, filepath.Join(root, "conf", "i18n.yaml"))
+}
+
+// TestRunAddService_Unknown tests that unknown providers list the catalog.
+func TestRunAddService_Unknown(t *testing.T) {
+	useLang(t, langEn)
+	_, err := runAddServiceArgs(t, "kafka")
+	assert.Equal(t, string(msgAddUnknown), errorID(err))
+	assert.Contains(t, err.Error(), "db, gin, i18n, redis")
+}
+
+// TestAddServiceToMain tests editing main packages that are not generated by `drugo new`.
+func TestAddServiceToMain(t *testing.T) {
+	redis, _ := lookupProvider("redis")
+	tests := []struct {
+		name string
+		src  string
+		want string
+	}{
+		{
+			name: "single line call",
+			src:  "package main\n\nimport \"github.com/qq1060656096/drugo/drugo\"\n\nfunc main() {\n\tdrugo.MustNewApp(drugo.WithRoot(\".\"))\n}\n",
+			want: "drugo.MustNewApp(drugo.WithRoot(\".\"), drugo.WithService(redissvc.New()))",
+		},
+		{
+			name: "no options",
+			src:  "package main\n\nimport \"github.com/qq1060656096/drugo/drugo\"\n\nfunc main() {\n\tdrugo.New()\n}\n",
+			want: "drugo.New(drugo.WithService(redissvc.New()))",
+		},
+		{
+			name: "aliased imports",
+			src: "package main\n\nimport (\n\td \"github.com/qq1060656096/drugo/drugo\"\n\tcache \"github.com/qq1060656096/drugo-provider/redissvc\"\n)\n\n" +
+				"func main() {\n\td.MustNewApp(\n\t\td.WithRoot(\".\"), // project root\n\t)\n\t_ = cache.New\n}\n",
+			want: "\t\td.WithRoot(\".\"), // project root\n\t\td.WithService(cache.New()),\n\t)",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := addServiceToMain([]byte(tt.src), redis)
+			require.NoError(t, err)
+			assert.Contains(t, string(out), tt.want)
+			_, err = parser.ParseFile(token.NewFileSet(), "main.go", out, parser.AllErrors)
+			require.NoError(t, err)
+
+			again, err := addServiceToMain(out, redis)
+			require.NoError(t, err)
+			assert.Equal(t, string(out), string(again))
+		})
+	}
+
+	_, err := addServiceToMain([]byte("package main\n\nfunc main() {}\n"), redis)
+	assert.Error(t, err)
+}
@@ -0,0 +1,147 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/qq1060656096/drugo/config"
+	"github.com/qq1060656096/drugo/pkg/gomod"
+	"github.com/spf13/cobra"
+)
+
+// configDir is the configuration directory of a generated project, relative to the project root.
+const configDir = "conf"
+
+// envVar selects the environment layer of the configuration, matching the one read by drugo applications.
+const envVar = "DRUGO_ENV"
+
+// bootReportFile is the default boot report path, relative to the project root, matching drugo.DefaultBootReportFile.
+const bootReportFile = "runtime/boot-report.json"
+
+// configCmd, configExportCmd and configUnusedCmd help texts are set by localize.
+var configCmd = &cobra.Command{
+	Use: "config",
+}
+
+var configExportCmd = &cobra.Command{
+	Use: "export",
+	Example: `  drugo config export --redact
+  drugo config export -f json -o support/config.json --env prod`,
+	Args: cobra.NoArgs,
+	RunE: runConfigExport,
+}
+
+var configUnusedCmd = &cobra.Command{
+	Use: "unused",
+	Example: `  drugo config unused
+  drugo config unused --report var/boot-report.json`,
+	Args: cobra.NoArgs,
+	RunE: runConfigUnused,
+}
+
+func init() {
+	rootCmd.AddCommand(configCmd)
+	configCmd.AddCommand(configExportCmd, configUnusedCmd)
+	addConfigExportFlags(configExportCmd)
+	addConfigUnusedFlags(configUnusedCmd)
+}
+
+// addConfigExportFlags registers the flags read by runConfigExport on c.
+func addConfigExportFlags(c *cobra.Command) {
+	c.Flags().Bool("redact", false, "")
+	c.Flags().StringP("format", "f", config.FormatYAML, "")
+	c.Flags().StringP("out", "o", "", "")
+	c.Flags().String("env", "", "")
+}
+
+func runConfigExport(cmd *cobra.Command, args []string) error {
+	wd, err := os.Getwd()
+	if err != nil {
+		return newError(msgWdFailed, err)
+	}
+	projectRoot, ok := gomod.FindGoModRoot(wd)
+	if !ok {
+		return newError(msgNotInProject, wd)
+	}
+
+	redact, _ := cmd.Flags().GetBool("redact")
+	format, _ := cmd.Flags().GetString("format")
+	out, _ := cmd.Flags().GetString("out")
+	env, _ := cmd.Flags().GetString("env")
+	if env == "" {
+		env = os.Getenv(envVar)
+	}
+
+	m, err := config.NewManager(filepath.Join(projectRoot, configDir), config.WithEnvironment(env, ""))
+	if err != nil {
+		return newError(msgConfigLoadFailed, err)
+	}
+
+	if out == "" {
+		if err := m.Export(cmd.OutOrStdout(), format, redact); err != nil {
+			return newError(msgConfigExportFailed, err)
+		}
+		return nil
+	}
+	if !filepath.IsAbs(out) {
+		out = filepath.Join(projectRoot, out)
+	}
+	if err := m.ExportFile(out, format, redact); err != nil {
+		return newError(msgConfigExportFailed, err)
+	}
+	fmt.Fprint(cmd.OutOrStdout(), msg(msgConfigExportSuccess, out))
+	return nil
+}
+
+// addConfigUnusedFlags registers the flags read by runConfigUnused on c.
+func addConfigUnusedFlags(c *cobra.Command) {
+	c.Flags().String("report", bootReportFile, "")
+}
+
+// unusedReport is the part of drugo.BootReport read by runConfigUnused.
+// UnusedConfigKeys is null when the application did not enable drugo.WithConfigUsageTracking.
+type unusedReport struct {
+	Time             string
+	UnusedConfigKeys *[]string
+}
+
+func runConfigUnused(cmd *cobra.Command, args []string) error {
+	wd, err := os.Getwd()
+	if err != nil {
+		return newError(msgWdFailed, err)
+	}
+	projectRoot, ok := gomod.FindGoModRoot(wd)
+	if !ok {
+		return newError(msgNotInProject, wd)
+	}
+
+	path, _ := cmd.Flags().GetString("report")
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(projectRoot, path)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return newError(msgConfigReportFailed, err)
+	}
+	var report unusedReport
+	if err := json.Unmarshal(data, &report); err != nil {
+		return newError(msgConfigReportFailed, fmt.Errorf("%s: %w", path, err))
+	}
+	if report.UnusedConfigKeys == nil {
+		return newError(msgConfigUnusedUntracked, path)
+	}
+
+	out := cmd.OutOrStdout()
+	keys := *report.UnusedConfigKeys
+	if len(keys) == 0 {
+		fmt.Fprint(out, msg(msgConfigUnusedNone, report.Time))
+		return nil
+	}
+	fmt.Fprint(out, msg(msgConfigUnusedHeader, len(keys), report.Time))
+	for _, key := range keys {
+		fmt.Fprintf(out, "  %s\n", key)
+	}
+	return nil
+}
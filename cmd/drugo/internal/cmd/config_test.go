@@ -0,0 +1,150 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/qq1060656096/drugo/config"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.yaml.in/yaml/v3"
+)
+
+// setupConfigProject creates a project with a base and a prod config layer and changes into it.
+func setupConfigProject(t *testing.T) string {
+	t.Helper()
+	root := t.TempDir()
+	files := map[string]string{
+		"go.mod":            "module github.com/acme/shop\n\ngo 1.25\n",
+		"conf/db.yaml":      "db:\n  host: localhost\n  password: s3cret\n",
+		"conf/app.yaml":     "app:\n  name: shop\n",
+		"conf/prod/db.yaml": "db:\n  host: db.prod\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(root, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	}
+	t.Chdir(filepath.Join(root, "conf"))
+	return root
+}
+
+// runConfigExportArgs runs `drugo config export` with args and returns its stdout.
+func runConfigExportArgs(t *testing.T, args ...string) (string, error) {
+	t.Helper()
+	var out bytes.Buffer
+	c := &cobra.Command{}
+	addConfigExportFlags(c)
+	c.SetOut(&out)
+	require.NoError(t, c.Flags().Parse(args))
+	err := runConfigExport(c, nil)
+	return out.String(), err
+}
+
+// TestRunConfigExport tests exporting the merged configuration to stdout and to a file.
+func TestRunConfigExport(t *testing.T) {
+	useLang(t, langEn)
+	t.Setenv(envVar, "")
+	root := setupConfigProject(t)
+
+	t.Run("yaml to stdout", func(t *testing.T) {
+		out, err := runConfigExportArgs(t, "--redact")
+		require.NoError(t, err)
+		assert.Contains(t, out, "# config dir: "+filepath.Join(root, "conf"))
+
+		var settings map[string]any
+		require.NoError(t, yaml.Unmarshal([]byte(out), &settings))
+		db := settings["db"].(map[string]any)
+		assert.Equal(t, "localhost", db["host"])
+		assert.Equal(t, config.RedactedValue, db["password"])
+	})
+
+	t.Run("environment layer", func(t *testing.T) {
+		t.Setenv(envVar, "prod")
+		out, err := runConfigExportArgs(t, "-f", "json")
+		require.NoError(t, err)
+
+		var doc struct {
+			Meta     config.ExportHeader `json:"meta"`
+			Settings map[string]any      `json:"settings"`
+		}
+		require.NoError(t, json.Unmarshal([]byte(out), &doc))
+		assert.Equal(t, "prod", doc.Meta.Environment)
+		assert.False(t, doc.Meta.Redacted)
+		db := doc.Settings["db"].(map[string]any)
+		assert.Equal(t, "db.prod", db["host"])
+		assert.Equal(t, "s3cret", db["password"])
+	})
+
+	t.Run("out file", func(t *testing.T) {
+		out, err := runConfigExportArgs(t, "--redact", "-o", "support/config.yaml")
+		require.NoError(t, err)
+		path := filepath.Join(root, "support", "config.yaml")
+		assert.Equal(t, "Exported configuration to "+path+"\n", out)
+
+		data, err := os.ReadFile(path)
+		require.NoError(t, err)
+		assert.NotContains(t, string(data), "s3cret")
+	})
+
+	t.Run("unsupported format", func(t *testing.T) {
+		_, err := runConfigExportArgs(t, "-f", "toml")
+		assert.Equal(t, string(msgConfigExportFailed), errorID(err))
+		assert.True(t, config.IsUnsupportedFormat(err))
+	})
+}
+
+// TestRunConfigExport_NotInProject tests the error returned outside a Drugo project.
+func TestRunConfigExport_NotInProject(t *testing.T) {
+	t.Chdir(t.TempDir())
+	_, err := runConfigExportArgs(t)
+	assert.Equal(t, string(msgNotInProject), errorID(err))
+}
+
+// runConfigUnusedArgs runs `drugo config unused` with args and returns its stdout.
+func runConfigUnusedArgs(t *testing.T, args ...string) (string, error) {
+	t.Helper()
+	var out bytes.Buffer
+	c := &cobra.Command{}
+	addConfigUnusedFlags(c)
+	c.SetOut(&out)
+	require.NoError(t, c.Flags().Parse(args))
+	err := runConfigUnused(c, nil)
+	return out.String(), err
+}
+
+// TestRunConfigUnused tests printing the unused keys recorded in the boot report.
+func TestRunConfigUnused(t *testing.T) {
+	useLang(t, langEn)
+	root := setupConfigProject(t)
+	writeReport := func(name, content string) {
+		path := filepath.Join(root, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	}
+
+	_, err := runConfigUnusedArgs(t)
+	assert.Equal(t, string(msgConfigReportFailed), errorID(err))
+
+	writeReport(bootReportFile, `{"Time": "2026-01-02T03:04:05Z", "UnusedConfigKeys": ["db.legacy", "db.typo_host"]}`)
+	out, err := runConfigUnusedArgs(t)
+	require.NoError(t, err)
+	assert.Equal(t, "2 configuration keys were not read (boot report captured at 2026-01-02T03:04:05Z):\n  db.legacy\n  db.typo_host\n", out)
+
+	writeReport("var/report.json", `{"Time": "2026-01-02T03:04:05Z", "UnusedConfigKeys": []}`)
+	out, err = runConfigUnusedArgs(t, "--report", "var/report.json")
+	require.NoError(t, err)
+	assert.Equal(t, "All configuration keys were read (boot report captured at 2026-01-02T03:04:05Z)\n", out)
+
+	writeReport(bootReportFile, `{"Time": "2026-01-02T03:04:05Z", "UnusedConfigKeys": null}`)
+	_, err = runConfigUnusedArgs(t)
+	assert.Equal(t, string(msgConfigUnusedUntracked), errorID(err))
+
+	writeReport(bootReportFile, `{`)
+	_, err = runConfigUnusedArgs(t)
+	assert.Equal(t, string(msgConfigReportFailed), errorID(err))
+}
@@ -0,0 +1,662 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/format"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"io"
+	"maps"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/qq1060656096/drugo/pkg/gomod"
+	"github.com/spf13/cobra"
+	"golang.org/x/tools/go/packages"
+)
+
+// defaultServicesFile is the accessor file written by `drugo generate services`, relative to the project root.
+const defaultServicesFile = "internal/pkg/services/services.go"
+
+// Import paths of the framework packages the generator recognizes and references.
+const (
+	drugoImport  = frameworkModule + "/drugo"
+	kernelImport = frameworkModule + "/kernel"
+)
+
+// servicesLoadMode lists the main packages and their dependencies with their export data. The packages
+// the generator inspects are type-checked by typeCheck with the importer of the running toolchain,
+// which reads the export data the go command writes without parsing every dependency from source.
+const servicesLoadMode = packages.NeedName | packages.NeedFiles | packages.NeedCompiledGoFiles |
+	packages.NeedImports | packages.NeedDeps | packages.NeedExportFile
+
+// serviceOptions are the drugo options that register services, mapped to the index of the name
+// argument (-1 when the service's own Name is used) and the index of the first service argument.
+// WithServices registers every argument from the service index on.
+var serviceOptions = map[string]struct{ name, service int }{
+	"WithService":         {-1, 0},
+	"WithNameService":     {0, 1},
+	"WithOptionalService": {-1, 0},
+	"WithServiceConfig":   {-1, 0},
+	"WithServiceErr":      {-1, 0},
+	"WithServices":        {-1, 0},
+}
+
+// generateCmd and generateServicesCmd help texts are set by localize.
+var generateCmd = &cobra.Command{
+	Use: "generate",
+}
+
+var generateServicesCmd = &cobra.Command{
+	Use: "services",
+	Example: `  drugo generate services
+  drugo generate services --check`,
+	Args: cobra.NoArgs,
+	RunE: runGenerateServices,
+}
+
+func init() {
+	rootCmd.AddCommand(generateCmd)
+	generateCmd.AddCommand(generateServicesCmd)
+	addGenerateServicesFlags(generateServicesCmd)
+}
+
+// addGenerateServicesFlags registers the flags read by runGenerateServices on c.
+func addGenerateServicesFlags(c *cobra.Command) {
+	c.Flags().StringP("output", "o", defaultServicesFile, "")
+	c.Flags().Bool("check", false, "")
+}
+
+func runGenerateServices(cmd *cobra.Command, args []string) error {
+	wd, err := os.Getwd()
+	if err != nil {
+		return newError(msgWdFailed, err)
+	}
+	projectRoot := gomod.ProjectRoot(wd)
+	if projectRoot == "" {
+		return newError(msgNotInProject, wd)
+	}
+	modPath, err := gomod.ModuleName(projectRoot)
+	if err != nil {
+		return newError(msgGoModFailed, err)
+	}
+	output, _ := cmd.Flags().GetString("output")
+	if !filepath.IsAbs(output) {
+		output = filepath.Join(projectRoot, output)
+	}
+	rel, err := filepath.Rel(projectRoot, filepath.Dir(output))
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return newError(msgServicesOutside, output)
+	}
+	pkgPath := path.Join(modPath, filepath.ToSlash(rel))
+
+	gen, err := generateServices(projectRoot, pkgPath)
+	if err != nil {
+		return err
+	}
+
+	out := cmd.OutOrStdout()
+	if check, _ := cmd.Flags().GetBool("check"); check {
+		current, err := os.ReadFile(output)
+		if err != nil || !bytes.Equal(current, gen.source) {
+			return newError(msgServicesStale, output)
+		}
+		fmt.Fprint(out, msg(msgServicesUpToDate, output))
+	} else {
+		if err := os.MkdirAll(filepath.Dir(output), 0755); err != nil {
+			return newError(msgDirFailed, filepath.Dir(output), err)
+		}
+		if err := os.WriteFile(output, gen.source, 0644); err != nil {
+			return newError(msgFileFailed, output, err)
+		}
+		fmt.Fprint(out, msg(msgServicesSuccess, output, len(gen.services)))
+	}
+	if len(gen.warnings) > 0 {
+		fmt.Fprint(out, msg(msgServicesWarnings, len(gen.warnings)))
+		for _, w := range gen.warnings {
+			fmt.Fprintf(out, "  - %s\n", w)
+		}
+	}
+	return nil
+}
+
+// serviceAccessor is a registered service the generator writes accessors for.
+type serviceAccessor struct {
+	Name     string     // service name
+	Func     string     // accessor function name
+	Type     types.Type // concrete type, nil when it cannot be resolved
+	Location string     // registration file:line relative to the project root
+}
+
+// generatedServices is the result of generateServices.
+type generatedServices struct {
+	source   []byte
+	services []serviceAccessor
+	warnings []string // registrations without accessors or with any-typed accessors
+}
+
+// generateServices scans the main packages of the project for drugo service registrations and
+// renders the accessor file of package pkgPath.
+func generateServices(projectRoot, pkgPath string) (*generatedServices, error) {
+	mains, err := gomod.MainPackages(projectRoot, gomod.WithCmdFirst())
+	if err != nil {
+		return nil, newError(msgMainScanFailed, err)
+	}
+	var patterns []string
+	for _, p := range mains {
+		if !p.HasMainFunc {
+			continue
+		}
+		rel, err := filepath.Rel(projectRoot, p.Dir)
+		if err != nil {
+			return nil, newError(msgMainScanFailed, err)
+		}
+		patterns = append(patterns, "./"+filepath.ToSlash(rel))
+	}
+	if len(patterns) == 0 {
+		return nil, newError(msgServicesNoMain, projectRoot)
+	}
+
+	s := &serviceScanner{
+		root:    projectRoot,
+		pkgPath: pkgPath,
+		fset:    token.NewFileSet(),
+		byName:  map[string]int{},
+		typed:   map[string]*typedPackage{},
+	}
+	if err := s.scan(patterns); err != nil {
+		return nil, newError(msgServicesFailed, err)
+	}
+	src, err := renderServices(pkgPath, s.services)
+	if err != nil {
+		return nil, newError(msgServicesFailed, err)
+	}
+	return &generatedServices{source: src, services: s.services, warnings: s.warnings}, nil
+}
+
+// serviceScanner collects the service registrations of the main packages.
+type serviceScanner struct {
+	root     string
+	pkgPath  string // import path of the generated package, to check the types are importable from it
+	fset     *token.FileSet
+	services []serviceAccessor
+	byName   map[string]int // index in services by service name
+	warnings []string
+
+	listed map[string]*packages.Package // main packages and their dependencies, by package path
+	typed  map[string]*typedPackage     // packages type-checked so far, by package path
+
+	// pending are the registrations named by the Name method of their type, resolved after the scan
+	pending []pendingService
+}
+
+// pendingService is a WithService-style registration whose name comes from the Name method of its type.
+type pendingService struct {
+	typ      types.Type
+	ctor     *types.Func       // function whose result was registered, nil if not a call
+	lit      *ast.CompositeLit // literal registered in the main package, e.g. &mailer{name: "mail"}
+	info     *types.Info       // type information of lit
+	expr     string
+	location string
+}
+
+// typedPackage is a package type-checked from source.
+type typedPackage struct {
+	files []*ast.File
+	types *types.Package
+	info  *types.Info
+}
+
+// scan loads and type-checks the main packages and records their service registrations.
+func (s *serviceScanner) scan(patterns []string) error {
+	cfg := &packages.Config{Mode: servicesLoadMode, Dir: s.root}
+	mains, err := packages.Load(cfg, patterns...)
+	if err != nil {
+		return err
+	}
+	s.listed = map[string]*packages.Package{}
+	packages.Visit(mains, nil, func(p *packages.Package) {
+		s.listed[p.PkgPath] = p
+	})
+	for _, p := range mains {
+		if len(p.Errors) > 0 {
+			return p.Errors[0]
+		}
+		pkg, err := s.typeCheck(p.PkgPath)
+		if err != nil {
+			return err
+		}
+		for _, f := range pkg.files {
+			ast.Inspect(f, func(n ast.Node) bool {
+				if call, ok := n.(*ast.CallExpr); ok {
+					s.registration(pkg, call)
+				}
+				return true
+			})
+		}
+	}
+	return s.resolvePending()
+}
+
+// typeCheck parses a listed package and type-checks it against the export data of its imports.
+func (s *serviceScanner) typeCheck(pkgPath string) (*typedPackage, error) {
+	if pkg, ok := s.typed[pkgPath]; ok {
+		return pkg, nil
+	}
+	p := s.listed[pkgPath]
+	if p == nil {
+		return nil, fmt.Errorf("package %s not loaded", pkgPath)
+	}
+	pkg := &typedPackage{info: &types.Info{
+		Types: map[ast.Expr]types.TypeAndValue{},
+		Uses:  map[*ast.Ident]types.Object{},
+	}}
+	for _, file := range p.CompiledGoFiles {
+		f, err := parser.ParseFile(s.fset, file, nil, parser.SkipObjectResolution)
+		if err != nil {
+			return nil, err
+		}
+		pkg.files = append(pkg.files, f)
+	}
+	lookup := func(path string) (io.ReadCloser, error) {
+		dep := p.Imports[path]
+		if dep == nil || dep.ExportFile == "" {
+			return nil, fmt.Errorf("no export data for %s", path)
+		}
+		return os.Open(dep.ExportFile)
+	}
+	conf := types.Config{Importer: importer.ForCompiler(s.fset, "gc", lookup)}
+	tp, err := conf.Check(pkgPath, s.fset, pkg.files, pkg.info)
+	if err != nil {
+		return nil, err
+	}
+	pkg.types = tp
+	s.typed[pkgPath] = pkg
+	return pkg, nil
+}
+
+// registration records the services registered by call if it is one of the serviceOptions.
+func (s *serviceScanner) registration(pkg *typedPackage, call *ast.CallExpr) {
+	fn := calledFunc(pkg.info, call)
+	if fn == nil || fn.Pkg() == nil || fn.Pkg().Path() != drugoImport {
+		return
+	}
+	opt, ok := serviceOptions[fn.Name()]
+	if !ok || len(call.Args) <= opt.service || call.Ellipsis.IsValid() {
+		return
+	}
+	last := opt.service
+	if fn.Name() == "WithServices" {
+		last = len(call.Args) - 1
+	}
+	for _, arg := range call.Args[opt.service : last+1] {
+		location := s.location(arg.Pos())
+		typ := serviceType(pkg.info, arg)
+		if opt.name >= 0 {
+			name, ok := constString(pkg.info, call.Args[opt.name])
+			if !ok {
+				s.warnf("%s: %s", location, msg(msgServicesNameDynamic, types.ExprString(call.Args[opt.name])))
+				continue
+			}
+			s.add(name, typ, location)
+			continue
+		}
+		p := pendingService{typ: typ, expr: types.ExprString(arg), location: location}
+		switch e := ast.Unparen(arg).(type) {
+		case *ast.CallExpr:
+			p.ctor = calledFunc(pkg.info, e)
+		case *ast.UnaryExpr:
+			if lit, ok := ast.Unparen(e.X).(*ast.CompositeLit); ok && e.Op == token.AND {
+				p.lit, p.info = lit, pkg.info
+			}
+		case *ast.CompositeLit:
+			p.lit, p.info = e, pkg.info
+		}
+		s.pending = append(s.pending, p)
+	}
+}
+
+// resolvePending resolves the names of the pending registrations from the source of the packages that
+// declare their types: the Name method must return a constant, or a field of the receiver that the
+// constructor sets to a constant in a composite literal of the type.
+func (s *serviceScanner) resolvePending() error {
+	for _, p := range s.pending {
+		name, ok := s.nameOf(p)
+		if !ok {
+			s.warnf("%s: %s", p.location, msg(msgServicesNameUnknown, p.expr))
+			continue
+		}
+		s.add(name, p.typ, p.location)
+	}
+	return nil
+}
+
+// nameOf returns the service name of a pending registration.
+func (s *serviceScanner) nameOf(p pendingService) (string, bool) {
+	obj := namedObj(p.typ)
+	if obj == nil || obj.Pkg() == nil {
+		return "", false
+	}
+	pkg, err := s.typeCheck(obj.Pkg().Path())
+	if err != nil {
+		return "", false
+	}
+	method := findMethod(pkg, obj.Name(), "Name")
+	if method == nil || len(method.Body.List) != 1 {
+		return "", false
+	}
+	ret, ok := method.Body.List[0].(*ast.ReturnStmt)
+	if !ok || len(ret.Results) != 1 {
+		return "", false
+	}
+	if name, ok := constString(pkg.info, ret.Results[0]); ok {
+		return name, true
+	}
+
+	// return s.name: the name is the field value set where the service was created
+	sel, ok := ret.Results[0].(*ast.SelectorExpr)
+	if !ok || method.Recv == nil || len(method.Recv.List[0].Names) == 0 {
+		return "", false
+	}
+	if x, ok := sel.X.(*ast.Ident); !ok || x.Name != method.Recv.List[0].Names[0].Name {
+		return "", false
+	}
+	field := sel.Sel.Name
+	if p.lit != nil {
+		return keyedString(p.info, p.lit, field)
+	}
+	if p.ctor == nil || p.ctor.Pkg() == nil || p.ctor.Pkg().Path() != obj.Pkg().Path() {
+		return "", false
+	}
+	ctor := findFunc(pkg, p.ctor.Name())
+	if ctor == nil {
+		return "", false
+	}
+	var names []string
+	ast.Inspect(ctor.Body, func(n ast.Node) bool {
+		lit, ok := n.(*ast.CompositeLit)
+		if !ok || namedObj(pkg.info.TypeOf(lit)) != pkg.types.Scope().Lookup(obj.Name()) {
+			return true
+		}
+		if name, ok := keyedString(pkg.info, lit, field); ok && !slices.Contains(names, name) {
+			names = append(names, name)
+		}
+		return true
+	})
+	if len(names) != 1 {
+		return "", false
+	}
+	return names[0], true
+}
+
+// add records a service, degrading it to an any-typed accessor when its type cannot be referenced
+// from the generated package or differs between registrations.
+func (s *serviceScanner) add(name string, typ types.Type, location string) {
+	if typ != nil && !s.importable(typ) {
+		typ = nil
+	}
+	if i, ok := s.byName[name]; ok {
+		prev := &s.services[i]
+		// main packages are type-checked separately, so compare the types by their qualified names
+		if prev.Type != nil && (typ == nil || typeKey(prev.Type) != typeKey(typ)) {
+			s.warnf("%s: %s", location, msg(msgServicesTypeConflict, name, prev.Location))
+			prev.Type = nil
+		}
+		return
+	}
+	fn := accessorName(name)
+	for _, other := range s.services {
+		if other.Func == fn {
+			s.warnf("%s: %s", location, msg(msgServicesFuncConflict, name, other.Name, fn))
+			return
+		}
+	}
+	if typ == nil {
+		s.warnf("%s: %s", location, msg(msgServicesTypeUnknown, name))
+	}
+	s.byName[name] = len(s.services)
+	s.services = append(s.services, serviceAccessor{Name: name, Func: fn, Type: typ, Location: location})
+}
+
+// importable reports whether every named type in typ is exported and can be imported by the generated package.
+func (s *serviceScanner) importable(typ types.Type) bool {
+	ok := true
+	types.TypeString(typ, func(p *types.Package) string {
+		if p.Name() == "main" {
+			ok = false
+		}
+		if i := strings.LastIndex(p.Path(), "/internal"); i >= 0 {
+			rest := p.Path()[i+len("/internal"):]
+			if (rest == "" || rest[0] == '/') && !strings.HasPrefix(s.pkgPath, p.Path()[:i]+"/") {
+				ok = false
+			}
+		}
+		return p.Path()
+	})
+	if obj := namedObj(typ); obj != nil && !obj.Exported() {
+		ok = false
+	}
+	return ok
+}
+
+func (s *serviceScanner) warnf(format string, args ...any) {
+	s.warnings = append(s.warnings, fmt.Sprintf(format, args...))
+}
+
+// location returns the file:line of pos relative to the project root.
+func (s *serviceScanner) location(pos token.Pos) string {
+	p := s.fset.Position(pos)
+	file := p.Filename
+	if rel, err := filepath.Rel(s.root, file); err == nil {
+		file = filepath.ToSlash(rel)
+	}
+	return file + ":" + strconv.Itoa(p.Line)
+}
+
+// typeKey returns typ qualified by full package paths.
+func typeKey(typ types.Type) string {
+	return types.TypeString(typ, (*types.Package).Path)
+}
+
+// calledFunc returns the package-level function called by call, nil for methods, closures and conversions.
+func calledFunc(info *types.Info, call *ast.CallExpr) *types.Func {
+	fun := ast.Unparen(call.Fun)
+	if index, ok := fun.(*ast.IndexExpr); ok { // explicit instantiation
+		fun = index.X
+	}
+	var ident *ast.Ident
+	switch fun := fun.(type) {
+	case *ast.Ident:
+		ident = fun
+	case *ast.SelectorExpr:
+		ident = fun.Sel
+	default:
+		return nil
+	}
+	fn, ok := info.Uses[ident].(*types.Func)
+	if !ok || fn.Type().(*types.Signature).Recv() != nil {
+		return nil
+	}
+	return fn
+}
+
+// serviceType returns the static type of a registered service expression, nil for interfaces.
+// A constructor call returning (service, error), as passed to WithServiceErr, yields the first result.
+func serviceType(info *types.Info, expr ast.Expr) types.Type {
+	typ := info.TypeOf(expr)
+	if tuple, ok := typ.(*types.Tuple); ok && tuple.Len() > 0 {
+		typ = tuple.At(0).Type()
+	}
+	if typ == nil || types.IsInterface(typ) {
+		return nil
+	}
+	return typ
+}
+
+// namedObj returns the type name of typ or of the type it points to.
+func namedObj(typ types.Type) *types.TypeName {
+	if typ == nil {
+		return nil
+	}
+	if ptr, ok := typ.(*types.Pointer); ok {
+		typ = ptr.Elem()
+	}
+	if named, ok := typ.(*types.Named); ok {
+		return named.Obj()
+	}
+	return nil
+}
+
+// constString returns the value of a constant string expression.
+func constString(info *types.Info, expr ast.Expr) (string, bool) {
+	tv, ok := info.Types[expr]
+	if !ok || tv.Value == nil || tv.Value.Kind() != constant.String {
+		return "", false
+	}
+	return constant.StringVal(tv.Value), true
+}
+
+// keyedString returns the constant string value of field in a keyed composite literal.
+func keyedString(info *types.Info, lit *ast.CompositeLit, field string) (string, bool) {
+	for _, elt := range lit.Elts {
+		kv, ok := elt.(*ast.KeyValueExpr)
+		if !ok {
+			continue
+		}
+		if key, ok := kv.Key.(*ast.Ident); ok && key.Name == field {
+			return constString(info, kv.Value)
+		}
+	}
+	return "", false
+}
+
+// findMethod returns the declaration of method name on type recv in pkg.
+func findMethod(pkg *typedPackage, recv, name string) *ast.FuncDecl {
+	for _, f := range pkg.files {
+		for _, decl := range f.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if ok && fn.Recv != nil && len(fn.Recv.List) == 1 && fn.Body != nil &&
+				fn.Name.Name == name && receiverName(fn.Recv.List[0].Type) == recv {
+				return fn
+			}
+		}
+	}
+	return nil
+}
+
+// findFunc returns the declaration of the package-level function name in pkg.
+func findFunc(pkg *typedPackage, name string) *ast.FuncDecl {
+	for _, f := range pkg.files {
+		for _, decl := range f.Decls {
+			if fn, ok := decl.(*ast.FuncDecl); ok && fn.Recv == nil && fn.Body != nil && fn.Name.Name == name {
+				return fn
+			}
+		}
+	}
+	return nil
+}
+
+// accessorName converts a service name to an exported Go identifier: "gin" -> "Gin", "order-db" -> "OrderDb".
+func accessorName(name string) string {
+	var b strings.Builder
+	for _, part := range strings.FieldsFunc(name, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		b.WriteString(toTitle(part))
+	}
+	id := b.String()
+	if id == "" || !unicode.IsLetter([]rune(id)[0]) {
+		id = "Service" + id
+	}
+	return id
+}
+
+// renderServices renders the accessor file of package pkgPath, sorted by service name.
+func renderServices(pkgPath string, services []serviceAccessor) ([]byte, error) {
+	services = slices.Clone(services)
+	slices.SortFunc(services, func(a, b serviceAccessor) int { return strings.Compare(a.Name, b.Name) })
+
+	// assign each imported package a unique name, in import path order so the output is stable
+	pkgNames := map[string]string{}
+	for _, svc := range services {
+		if svc.Type != nil {
+			types.TypeString(svc.Type, func(p *types.Package) string {
+				pkgNames[p.Path()] = p.Name()
+				return ""
+			})
+		}
+	}
+	paths := slices.Sorted(maps.Keys(pkgNames))
+	names := map[string]string{kernelImport: "kernel"}
+	used := map[string]bool{"kernel": true, path.Base(pkgPath): true}
+	imports := []string{strconv.Quote(kernelImport)}
+	for _, p := range paths {
+		if p == kernelImport || p == pkgPath {
+			continue
+		}
+		name := pkgNames[p]
+		for i := 2; used[name]; i++ {
+			name = pkgNames[p] + strconv.Itoa(i)
+		}
+		used[name] = true
+		names[p] = name
+		spec := strconv.Quote(p)
+		if name != path.Base(p) {
+			spec = name + " " + spec
+		}
+		imports = append(imports, spec)
+	}
+	slices.SortFunc(imports, func(a, b string) int {
+		return strings.Compare(importPath(a), importPath(b))
+	})
+	qualifier := func(p *types.Package) string {
+		if p.Path() == pkgPath {
+			return ""
+		}
+		return names[p.Path()]
+	}
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "// Code generated by drugo generate services. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&b, "// Package %s 提供应用注册的服务的类型化访问函数，服务名称与类型在生成时确定。\n", path.Base(pkgPath))
+	fmt.Fprintf(&b, "// 服务的名称或类型变化后重新执行 drugo generate services，调用方会在编译时而不是运行时发现不一致。\n")
+	fmt.Fprintf(&b, "package %s\n\nimport (\n", path.Base(pkgPath))
+	for _, imp := range imports {
+		fmt.Fprintf(&b, "\t%s\n", imp)
+	}
+	fmt.Fprintf(&b, ")\n")
+	for _, svc := range services {
+		typ := "any"
+		if svc.Type != nil {
+			typ = types.TypeString(svc.Type, qualifier)
+		}
+		file, _, _ := strings.Cut(svc.Location, ":")
+		name := strconv.Quote(svc.Name)
+		fmt.Fprintf(&b, "\n// %s 返回名称为 %s 的服务，在 %s 中注册", svc.Func, name, file)
+		if svc.Type == nil {
+			fmt.Fprintf(&b, "；无法确定服务的类型，返回 any")
+		}
+		fmt.Fprintf(&b, "\nfunc %s(k kernel.Kernel) (%s, error) {\n\treturn kernel.GetService[%s](k, %s)\n}\n", svc.Func, typ, typ, name)
+		fmt.Fprintf(&b, "\n// Must%s 与 %s 相同，获取失败时 panic\n", svc.Func, svc.Func)
+		fmt.Fprintf(&b, "func Must%s(k kernel.Kernel) %s {\n\treturn kernel.MustGetService[%s](k, %s)\n}\n", svc.Func, typ, typ, name)
+	}
+	return format.Source(b.Bytes())
+}
+
+// importPath returns the path of an import spec with an optional name.
+func importPath(spec string) string {
+	if _, p, ok := strings.Cut(spec, " "); ok {
+		return p
+	}
+	return spec
+}
@@ -0,0 +1,162 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mailerService 是 fixture 项目中 Name 方法返回常量的服务
+const mailerService = `package mailer
+
+import "context"
+
+type Mailer struct{}
+
+func New() *Mailer { return &Mailer{} }
+
+func (m *Mailer) Name() string                  { return "mail" }
+func (m *Mailer) Boot(ctx context.Context) error  { return nil }
+func (m *Mailer) Close(ctx context.Context) error { return nil }
+`
+
+// workerMain 是 fixture 项目的第二个 main 包：与 cmd/app 重复注册 gin，其他服务以配置段、显式名称、接口类型与 main 包内的类型注册
+const workerMain = `package main
+
+import (
+	"context"
+
+	"github.com/acme/app/internal/pkg/mailer"
+	"github.com/qq1060656096/drugo-provider/ginsrv"
+	"github.com/qq1060656096/drugo/drugo"
+	"github.com/qq1060656096/drugo/kernel"
+)
+
+type local struct{ name string }
+
+func (l *local) Name() string                  { return l.name }
+func (l *local) Boot(ctx context.Context) error  { return nil }
+func (l *local) Close(ctx context.Context) error { return nil }
+
+func newCache() kernel.Service { return &local{name: "cache"} }
+
+func main() {
+	var svc kernel.Service = newCache()
+	drugo.New(
+		drugo.WithServices(ginsrv.New()),
+		drugo.WithServiceConfig(mailer.New(), "smtp"),
+		drugo.WithNameService("cache", newCache()),
+		drugo.WithService(&local{name: "local"}),
+		drugo.WithService(svc),
+	)
+}
+`
+
+// useServices 使用生成的访问函数，编译通过即说明访问函数的名称与类型正确
+const useServices = `package main
+
+import (
+	"github.com/acme/app/internal/pkg/mailer"
+	"github.com/acme/app/internal/pkg/services"
+	"github.com/qq1060656096/drugo-provider/dbsvc"
+	"github.com/qq1060656096/drugo-provider/ginsrv"
+	"github.com/qq1060656096/drugo-provider/redissvc"
+	"github.com/qq1060656096/drugo/drugo"
+)
+
+func main() {
+	app := drugo.New()
+	var _ *ginsrv.GinService = services.MustGin(app)
+	var _ *dbsvc.DbService = services.MustDb(app)
+	var _ *redissvc.RedisService = services.MustRedis(app)
+	var _ *mailer.Mailer = services.MustMail(app)
+	var _ any = services.MustCache(app)
+	_, _ = services.Local(app)
+}
+`
+
+// TestGenerateServices 对 fixture 项目执行生成器，检查访问函数的名称、类型、警告与确定性，并编译使用访问函数的代码。
+// 需要模块缓存或网络。
+func TestGenerateServices(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping service generation in short mode")
+	}
+	useLang(t, langEn)
+	local := frameworkRoot(t)
+	t.Chdir(t.TempDir())
+	require.NoError(t, createProject("app", "github.com/acme/app", "v0.0.0"))
+	root, err := filepath.Abs("app")
+	require.NoError(t, err)
+	writeFile := func(rel, content string) {
+		t.Helper()
+		p := filepath.Join(root, rel)
+		require.NoError(t, os.MkdirAll(filepath.Dir(p), 0755))
+		require.NoError(t, os.WriteFile(p, []byte(content), 0644))
+	}
+	writeFile("internal/pkg/mailer/mailer.go", mailerService)
+	writeFile("cmd/worker/main.go", workerMain)
+	var out bytes.Buffer
+	require.NoError(t, VerifyProject(context.Background(), root, VerifyOptions{ReplaceLocal: local, Output: &out}), out.String())
+
+	gen, err := generateServices(root, "github.com/acme/app/internal/pkg/services")
+	require.NoError(t, err)
+	src := string(gen.source)
+	assert.Contains(t, src, "// Code generated by drugo generate services. DO NOT EDIT.\n")
+	assert.Contains(t, src, "func Gin(k kernel.Kernel) (*ginsrv.GinService, error) {\n\treturn kernel.GetService[*ginsrv.GinService](k, \"gin\")\n}")
+	assert.Contains(t, src, "func MustGin(k kernel.Kernel) *ginsrv.GinService {\n\treturn kernel.MustGetService[*ginsrv.GinService](k, \"gin\")\n}")
+	assert.Contains(t, src, "func Db(k kernel.Kernel) (*dbsvc.DbService, error)")
+	assert.Contains(t, src, "func Redis(k kernel.Kernel) (*redissvc.RedisService, error)")
+	assert.Contains(t, src, "func Mail(k kernel.Kernel) (*mailer.Mailer, error)")
+	assert.Contains(t, src, "func Cache(k kernel.Kernel) (any, error) {\n\treturn kernel.GetService[any](k, \"cache\")\n}")
+	assert.Contains(t, src, "func Local(k kernel.Kernel) (any, error)")
+
+	var names []string
+	for _, svc := range gen.services {
+		names = append(names, svc.Name)
+	}
+	assert.ElementsMatch(t, []string{"gin", "db", "redis", "mail", "cache", "local"}, names)
+	require.Len(t, gen.warnings, 3)
+	assert.Contains(t, gen.warnings[0], `cannot resolve the type of service "cache"`)
+	assert.Contains(t, gen.warnings[1], `cannot resolve the type of service "local"`)
+	assert.Regexp(t, `^cmd/worker/main.go:\d+: cannot resolve the service name of svc`, gen.warnings[2])
+
+	// 生成的文件可以编译，访问函数的类型与调用方一致
+	writeFile("internal/pkg/services/services.go", src)
+	writeFile("cmd/check/main.go", useServices)
+	out.Reset()
+	require.NoError(t, runGo(context.Background(), root, &out, nil, "vet", "./..."), out.String())
+
+	// 相同的源码生成相同的文件，--check 通过
+	t.Chdir(root)
+	c := &cobra.Command{}
+	addGenerateServicesFlags(c)
+	out.Reset()
+	c.SetOut(&out)
+	require.NoError(t, c.Flags().Parse([]string{"--check"}))
+	require.NoError(t, runGenerateServices(c, nil))
+	assert.Contains(t, out.String(), "are up to date")
+
+	writeFile("internal/pkg/services/services.go", src+"\n")
+	err = runGenerateServices(c, nil)
+	assert.Equal(t, string(msgServicesStale), errorID(err))
+}
+
+// TestAccessorName 测试服务名称到导出标识符的转换
+func TestAccessorName(t *testing.T) {
+	tests := map[string]string{
+		"gin":       "Gin",
+		"order-db":  "OrderDb",
+		"redis_bi":  "RedisBi",
+		"2fa":       "Service2fa",
+		"grpc.user": "GrpcUser",
+	}
+	for name, want := range tests {
+		assert.Equal(t, want, accessorName(name), name)
+	}
+}
@@ -0,0 +1,1134 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Supported languages.
+const (
+	langZh = "zh"
+	langEn = "en"
+)
+
+// langEnvVar selects the CLI language when --lang is not given.
+const langEnvVar = "DRUGO_LANG"
+
+// lang is the language used by msg, defaulting to Chinese to preserve existing output.
+var lang = langZh
+
+// msgID is a stable message identifier. IDs never change between releases,
+// so scripted callers can match on them regardless of the selected language.
+type msgID string
+
+// message holds the translations of a single message.
+type message struct {
+	zh string
+	en string
+}
+
+// Message IDs.
+const (
+	msgRootShort    msgID = "root.short"
+	msgRootLong     msgID = "root.long"
+	msgFlagLang     msgID = "flag.lang"
+	msgFlagOffline  msgID = "flag.offline"
+	msgHelpFlag     msgID = "flag.help"
+	msgVersionFlag  msgID = "flag.version"
+	msgLangInvalid  msgID = "lang.invalid"
+	msgCompleteUse  msgID = "completion.use"
+	msgCompleteShrt msgID = "completion.short"
+	msgCompleteLong msgID = "completion.long"
+	msgShellInvalid msgID = "completion.shell_invalid"
+
+	msgNewUse          msgID = "new.use"
+	msgNewShort        msgID = "new.short"
+	msgNewLong         msgID = "new.long"
+	msgNewFlagMod      msgID = "new.flag.mod"
+	msgNewFlagVerify   msgID = "new.flag.verify"
+	msgNewFlagKeep     msgID = "new.flag.keep_on_failure"
+	msgNewFlagLocal    msgID = "new.flag.replace_local"
+	msgNewVerifying    msgID = "new.verifying"
+	msgNewVerified     msgID = "new.verified"
+	msgNewCreating     msgID = "new.creating"
+	msgNewSuccess      msgID = "new.success"
+	msgProjectExists   msgID = "project.exists"
+	msgProjectFailed   msgID = "project.create_failed"
+	msgProjectEmpty    msgID = "project.name_empty"
+	msgProjectChars    msgID = "project.name_invalid"
+	msgNewVerifyFailed msgID = "project.verify_failed"
+	msgNewFlagTidy     msgID = "new.flag.tidy"
+	msgNewTidying      msgID = "new.tidying"
+	msgNewTidyFailed   msgID = "project.tidy_failed"
+	msgNewReplaced     msgID = "new.replaced"
+	msgNewOffline      msgID = "new.offline"
+	msgNewUnpinned     msgID = "new.offline.unpinned"
+	msgNewOfflineWarn  msgID = "new.offline.warning"
+	msgNewOfflineSkip  msgID = "new.offline.skipped"
+	msgNewOfflineNone  msgID = "new.offline.none"
+
+	msgModuleShort       msgID = "module.short"
+	msgModuleLong        msgID = "module.long"
+	msgModuleNewUse      msgID = "module.new.use"
+	msgModuleNewShort    msgID = "module.new.short"
+	msgModuleNewLong     msgID = "module.new.long"
+	msgModuleFlagKind    msgID = "module.flag.kind"
+	msgModuleCreating    msgID = "module.creating"
+	msgModuleSuccess     msgID = "module.success"
+	msgWorkerSuccess     msgID = "module.worker_success"
+	msgGrpcSuccess       msgID = "module.grpc_success"
+	msgModuleExists      msgID = "module.exists"
+	msgModuleNotExists   msgID = "module.not_exists"
+	msgModuleConfExists  msgID = "module.conf_exists"
+	msgModuleFailed      msgID = "module.create_failed"
+	msgModuleKindInvalid msgID = "module.kind_invalid"
+	msgModuleFlagLayout  msgID = "module.flag.layout"
+	msgModuleLayoutOK    msgID = "module.layout_success"
+	msgLayoutInvalid     msgID = "layout.invalid"
+	msgProjectMetaFailed msgID = "project.meta_failed"
+	msgModuleFlagMain    msgID = "module.flag.main"
+	msgMainScanFailed    msgID = "main.scan_failed"
+	msgMainAmbiguous     msgID = "main.ambiguous"
+	msgMainInvalid       msgID = "main.invalid"
+	msgModuleFlagData    msgID = "module.flag.data"
+	msgDataInvalid       msgID = "module.data_invalid"
+	msgDataGorm          msgID = "module.data_gorm"
+	msgDataGormRequire   msgID = "module.data_gorm_require"
+
+	msgAPIUse      msgID = "api.use"
+	msgAPIShort    msgID = "api.short"
+	msgAPILong     msgID = "api.long"
+	msgAPICreating msgID = "api.creating"
+	msgAPISuccess  msgID = "api.success"
+	msgAPIFailed   msgID = "api.create_failed"
+	msgAPILayoutOK msgID = "api.layout_success"
+	msgAPIFile     msgID = "api.file_created"
+	msgFileExists  msgID = "file.exists"
+
+	msgOpenAPIUse        msgID = "openapi.use"
+	msgOpenAPIShort      msgID = "openapi.short"
+	msgOpenAPILong       msgID = "openapi.long"
+	msgOpenAPIFlagOutput msgID = "openapi.flag.output"
+	msgOpenAPIFlagTitle  msgID = "openapi.flag.title"
+	msgOpenAPISuccess    msgID = "openapi.success"
+	msgOpenAPIWarnings   msgID = "openapi.warnings"
+	msgOpenAPIFailed     msgID = "openapi.failed"
+
+	msgRoutesUse        msgID = "routes.use"
+	msgRoutesShort      msgID = "routes.short"
+	msgRoutesLong       msgID = "routes.long"
+	msgRoutesFlagJSON   msgID = "routes.flag.json"
+	msgRoutesFlagStrict msgID = "routes.flag.strict"
+	msgRoutesEmpty      msgID = "routes.empty"
+	msgRoutesDuplicates msgID = "routes.duplicates"
+	msgRoutesConflict   msgID = "routes.conflict"
+	msgRoutesFailed     msgID = "routes.failed"
+
+	msgGenerateShort        msgID = "generate.short"
+	msgGenerateLong         msgID = "generate.long"
+	msgServicesShort        msgID = "generate.services.short"
+	msgServicesLong         msgID = "generate.services.long"
+	msgServicesFlagOutput   msgID = "generate.services.flag.output"
+	msgServicesFlagCheck    msgID = "generate.services.flag.check"
+	msgServicesSuccess      msgID = "generate.services.success"
+	msgServicesUpToDate     msgID = "generate.services.up_to_date"
+	msgServicesWarnings     msgID = "generate.services.warnings"
+	msgServicesNameDynamic  msgID = "generate.services.name_dynamic"
+	msgServicesNameUnknown  msgID = "generate.services.name_unknown"
+	msgServicesTypeUnknown  msgID = "generate.services.type_unknown"
+	msgServicesTypeConflict msgID = "generate.services.type_conflict"
+	msgServicesFuncConflict msgID = "generate.services.func_conflict"
+	msgServicesNoMain       msgID = "generate.services.no_main"
+	msgServicesOutside      msgID = "generate.services.outside"
+	msgServicesStale        msgID = "generate.services.stale"
+	msgServicesFailed       msgID = "generate.services.failed"
+
+	msgLintShort  msgID = "lint.short"
+	msgLintLong   msgID = "lint.long"
+	msgLintOK     msgID = "lint.success"
+	msgLintFailed msgID = "lint.failed"
+
+	msgConfigShort            msgID = "config.short"
+	msgConfigLong             msgID = "config.long"
+	msgConfigExportShort      msgID = "config.export.short"
+	msgConfigExportLong       msgID = "config.export.long"
+	msgConfigExportFlagRedact msgID = "config.export.flag.redact"
+	msgConfigExportFlagFormat msgID = "config.export.flag.format"
+	msgConfigExportFlagOut    msgID = "config.export.flag.out"
+	msgConfigExportFlagEnv    msgID = "config.export.flag.env"
+	msgConfigExportSuccess    msgID = "config.export.success"
+	msgConfigLoadFailed       msgID = "config.load_failed"
+	msgConfigExportFailed     msgID = "config.export_failed"
+	msgConfigUnusedShort      msgID = "config.unused.short"
+	msgConfigUnusedLong       msgID = "config.unused.long"
+	msgConfigUnusedFlagReport msgID = "config.unused.flag.report"
+	msgConfigUnusedHeader     msgID = "config.unused.header"
+	msgConfigUnusedNone       msgID = "config.unused.none"
+	msgConfigUnusedUntracked  msgID = "config.unused.untracked"
+	msgConfigReportFailed     msgID = "config.report_failed"
+
+	msgAddShort        msgID = "add.short"
+	msgAddLong         msgID = "add.long"
+	msgAddServiceUse   msgID = "add.service.use"
+	msgAddServiceShort msgID = "add.service.short"
+	msgAddServiceLong  msgID = "add.service.long"
+	msgAddFlagDryRun   msgID = "add.service.flag.dry_run"
+	msgAddFlagMain     msgID = "add.service.flag.main"
+	msgAddSuccess      msgID = "add.service.success"
+	msgAddCreated      msgID = "add.service.created"
+	msgAddModified     msgID = "add.service.modified"
+	msgAddNext         msgID = "add.service.next"
+	msgAddUpToDate     msgID = "add.service.up_to_date"
+	msgAddUnknown      msgID = "add.service.unknown"
+	msgAddNoMain       msgID = "add.service.no_main"
+	msgAddMainFailed   msgID = "add.service.main_failed"
+
+	msgFieldModule msgID = "field.module_name"
+	msgFieldAPI    msgID = "field.api_name"
+	msgNameEmpty   msgID = "name.empty"
+	msgNameInvalid msgID = "name.invalid"
+	msgNameChars   msgID = "name.chars"
+
+	msgWdFailed      msgID = "wd.failed"
+	msgNotInProject  msgID = "project.not_found"
+	msgGoModFailed   msgID = "gomod.read_failed"
+	msgDirFailed     msgID = "dir.create_failed"
+	msgFileFailed    msgID = "file.create_failed"
+	msgTplParse      msgID = "template.parse_failed"
+	msgTplExecFailed msgID = "template.exec_failed"
+)
+
+// messages is the message catalog.
+var messages = map[msgID]message{
+	msgRootShort: {
+		zh: "Drugo 是一个轻量级、模块化的 Go 应用程序框架 CLI 工具",
+		en: "CLI tool for Drugo, a lightweight and modular Go application framework",
+	},
+	msgRootLong: {
+		zh: `Drugo CLI 是 Drugo 框架的命令行工具。
+它帮助你创建新项目和模块，并提供标准的目录结构。
+
+用法:
+  drugo new <项目名称>           创建一个新的 Drugo 项目
+  drugo module new <模块名称>    在现有项目中创建新模块
+  drugo module new <模块名称> --kind worker 创建后台任务模块
+  drugo module new <模块名称> --kind grpc 创建 gRPC 服务模块
+  drugo module new-api <模块名称> <API名称> 在现有模块中创建新的 API 结构
+  drugo openapi                  根据 API 处理器注解生成 docs/openapi.yaml
+  drugo routes                   静态分析并列出所有模块注册的路由
+  drugo config export --redact   导出合并后的完整配置（敏感项已脱敏）
+  drugo lint-templates           检查内置模板能否生成合法的 Go 与 YAML
+  drugo completion <shell>       生成 shell 自动补全脚本
+
+示例:
+  drugo new myapp                创建一个名为 'myapp' 的新项目
+  drugo module new user          创建一个带有 CRUD 模板的 user 模块
+  drugo module new-api user address 在 user 模块中创建 address API`,
+		en: `Drugo CLI is the command line tool for the Drugo framework.
+It helps you create new projects and modules with a standard directory layout.
+
+Usage:
+  drugo new <project-name>       Create a new Drugo project
+  drugo module new <module-name> Create a new module in the current project
+  drugo module new <module-name> --kind worker Create a background worker module
+  drugo module new <module-name> --kind grpc Create a gRPC service module
+  drugo module new-api <module-name> <api-name> Create a new API in an existing module
+  drugo openapi                  Generate docs/openapi.yaml from the API handler annotations
+  drugo routes                   List the routes registered by all modules, found by static analysis
+  drugo config export --redact   Export the merged configuration with sensitive values redacted
+  drugo lint-templates           Check that the embedded templates render valid Go and YAML
+  drugo completion <shell>       Generate a shell completion script
+
+Examples:
+  drugo new myapp                Create a new project named 'myapp'
+  drugo module new user          Create a user module with CRUD templates
+  drugo module new-api user address Create the address API in the user module`,
+	},
+	msgFlagLang: {
+		zh: "输出语言: zh 或 en (默认读取 DRUGO_LANG 或 LANG)",
+		en: "output language: zh or en (defaults to DRUGO_LANG or LANG)",
+	},
+	msgFlagOffline: {
+		zh: "离线模式: 不访问网络，go 命令以 GOPROXY=off 运行 (也可以设置 DRUGO_OFFLINE=1)",
+		en: "offline mode: never access the network, go commands run with GOPROXY=off (or set DRUGO_OFFLINE=1)",
+	},
+	msgHelpFlag:    {zh: "显示帮助信息", en: "help for this command"},
+	msgVersionFlag: {zh: "显示版本信息", en: "version for drugo"},
+	msgLangInvalid: {
+		zh: "不支持的语言 %q，可选值: zh, en",
+		en: "unsupported language %q, valid values: zh, en",
+	},
+	msgCompleteUse:  {zh: "completion [bash|zsh|fish|powershell]", en: "completion [bash|zsh|fish|powershell]"},
+	msgCompleteShrt: {zh: "生成 shell 自动补全脚本", en: "Generate a shell completion script"},
+	msgCompleteLong: {
+		zh: `为指定的 shell 生成自动补全脚本并输出到标准输出。
+
+加载方式:
+  bash:       source <(drugo completion bash)
+  zsh:        drugo completion zsh > "${fpath[1]}/_drugo"
+  fish:       drugo completion fish | source
+  powershell: drugo completion powershell | Out-String | Invoke-Expression`,
+		en: `Generate the autocompletion script for the given shell and write it to stdout.
+
+To load completions:
+  bash:       source <(drugo completion bash)
+  zsh:        drugo completion zsh > "${fpath[1]}/_drugo"
+  fish:       drugo completion fish | source
+  powershell: drugo completion powershell | Out-String | Invoke-Expression`,
+	},
+	msgShellInvalid: {
+		zh: "不支持的 shell %q，可选值: bash, zsh, fish, powershell",
+		en: "unsupported shell %q, valid values: bash, zsh, fish, powershell",
+	},
+
+	msgNewUse:   {zh: "new <项目名称>", en: "new <project-name>"},
+	msgNewShort: {zh: "创建一个新的 Drugo 项目", en: "Create a new Drugo project"},
+	msgNewLong: {
+		zh: `创建一个带有标准目录结构的新 Drugo 项目。
+
+项目将在名为 <项目名称> 的新目录中创建。
+如果未指定 --mod，将使用 <项目名称> 作为模块路径。
+
+目录结构:
+` + projectTree("<项目名称>"),
+		en: `Create a new Drugo project with the standard directory layout.
+
+The project is created in a new directory named <project-name>.
+If --mod is not given, <project-name> is used as the module path.
+
+Layout:
+` + projectTree("<project-name>"),
+	},
+	msgNewFlagMod: {
+		zh: "go 模块路径 (默认: <项目名称>)",
+		en: "go module path (default: <project-name>)",
+	},
+	msgNewFlagVerify: {
+		zh: "生成后运行 go vet 校验项目能否编译",
+		en: "run go vet after generating to verify the project compiles",
+	},
+	msgNewFlagKeep: {
+		zh: "校验失败时保留项目目录",
+		en: "keep the project directory when verification fails",
+	},
+	msgNewFlagLocal: {
+		zh: "在 go.mod 中将 drugo 模块 replace 到本地路径（用于未发布的框架改动或无网络环境）",
+		en: "replace the drugo module with a local checkout in go.mod (for unreleased framework changes or builds without network access)",
+	},
+	msgNewFlagTidy: {
+		zh: "生成后运行 go mod tidy 下载依赖",
+		en: "run go mod tidy after generating to download the dependencies",
+	},
+	msgNewTidying: {
+		zh: "正在整理项目 %q 的依赖 (go mod tidy)...\n",
+		en: "Running go mod tidy in project %q...\n",
+	},
+	msgNewTidyFailed: {
+		zh: "项目 %q 运行 go mod tidy 失败: %v",
+		en: "project %q go mod tidy failed: %v",
+	},
+	msgNewReplaced: {
+		zh: "已将 %s replace 到 %s\n",
+		en: "Replaced %s with %s\n",
+	},
+	msgNewOffline: {
+		zh: "离线模式: 不访问网络 (GOPROXY=off)，依赖必须已在模块缓存中或通过 --replace-local 指向本地路径\n",
+		en: "Offline mode: network access is disabled (GOPROXY=off), dependencies must already be in the module cache or replaced with --replace-local\n",
+	},
+	msgNewUnpinned: {
+		zh: "警告: 当前 drugo 不是发布版本，go.mod 依赖 %s %s，离线构建需要使用 --replace-local\n",
+		en: "Warning: this drugo build is not a release, go.mod requires %s %s; offline builds need --replace-local\n",
+	},
+	msgNewOfflineWarn: {
+		zh: "警告: 离线模式下无法获取依赖，跳过 %s: %v\n",
+		en: "Warning: dependencies are unavailable offline, skipping %s: %v\n",
+	},
+	msgNewOfflineSkip: {
+		zh: "离线模式下跳过的步骤: %s (联网后请重新运行)\n",
+		en: "Skipped in offline mode: %s (run again with network access)\n",
+	},
+	msgNewOfflineNone: {
+		zh: "离线模式: 没有跳过任何步骤\n",
+		en: "Offline mode: no steps were skipped\n",
+	},
+	msgNewVerifying: {
+		zh: "正在校验项目 %q...\n",
+		en: "Verifying project %q...\n",
+	},
+	msgNewVerified: {
+		zh: "项目 %q 校验通过\n",
+		en: "Project %q verified\n",
+	},
+	msgNewCreating: {
+		zh: "正在创建项目 %q，模块路径为 %q...\n",
+		en: "Creating project %q with module path %q...\n",
+	},
+	msgNewSuccess: {
+		zh: `
+项目 %[1]q 创建成功！
+
+下一步:
+  cd %[1]s
+  go mod tidy
+  make run
+
+`,
+		en: `
+Project %[1]q created successfully!
+
+Next steps:
+  cd %[1]s
+  go mod tidy
+  make run
+
+`,
+	},
+	msgProjectExists: {zh: "目录 %q 已存在", en: "directory %q already exists"},
+	msgNewVerifyFailed: {
+		zh: "项目 %q 校验失败: %v",
+		en: "project %q verification failed: %v",
+	},
+	msgProjectFailed: {zh: "创建项目失败: %v", en: "failed to create project: %v"},
+	msgProjectEmpty:  {zh: "项目名称不能为空", en: "project name must not be empty"},
+	msgProjectChars: {
+		zh: "项目名称不能包含空格或路径分隔符",
+		en: "project name must not contain spaces or path separators",
+	},
+
+	msgModuleShort: {zh: "模块管理", en: "Manage modules"},
+	msgModuleLong:  {zh: "管理 Drugo 项目中的模块。", en: "Manage modules in a Drugo project."},
+	msgModuleNewUse: {
+		zh: "new <模块名称>",
+		en: "new <module-name>",
+	},
+	msgModuleNewShort: {
+		zh: "在当前 Drugo 项目中创建新模块",
+		en: "Create a new module in the current Drugo project",
+	},
+	msgModuleNewLong: {
+		zh: `在当前项目中创建具有标准 CRUD 结构的新模块。
+
+模块将在 internal/<模块名称>/ 目录中创建，包含:
+  - api/       HTTP 处理器和路由注册
+  - biz/       业务逻辑和领域实体
+  - data/      数据访问层（仓储实现）
+  - service/   服务层（DTO 和编排）
+
+使用 --kind worker 创建后台任务模块（kernel.Runner），包含:
+  - worker/    后台任务服务（读取 <模块名称> 配置段）
+  - biz/       业务逻辑
+  以及配置文件 conf/<模块名称>.yaml
+
+使用 --kind grpc 创建 gRPC 服务模块，包含:
+  - proto/     服务定义（<模块名称>.proto）和 go:generate 指令
+  - grpc/      gRPC 服务实现（通过 pkg/grpcreg 自动注册）
+  - biz/       业务逻辑和领域实体
+  - data/      数据访问层（仓储实现）
+
+使用 --layout 选择 CRUD 模块的目录结构预设（worker 和 grpc 模块始终使用 drugo 结构）:
+  - drugo               internal/<模块名称>/{api,biz,data,service}，默认值
+  - flat                internal/<模块名称>/ 下的单个包，文件以层名为后缀
+  - handler-logic-repo  app/<模块名称>/{handler,logic,repo}
+未指定 --layout 时读取项目根目录 .drugo.yaml 中的 layout 配置，使仓库内的模块保持一致。
+
+此命令必须在 Drugo 项目根目录（go.mod 所在位置）运行。`,
+		en: `Create a new module with the standard CRUD layout in the current project.
+
+The module is created in internal/<module-name>/ and contains:
+  - api/       HTTP handlers and route registration
+  - biz/       business logic and domain entities
+  - data/      data access layer (repository implementations)
+  - service/   service layer (DTOs and orchestration)
+
+Use --kind worker to create a background worker module (kernel.Runner) containing:
+  - worker/    worker service (reads the <module-name> config section)
+  - biz/       business logic
+  and the config file conf/<module-name>.yaml
+
+Use --kind grpc to create a gRPC service module containing:
+  - proto/     service definition (<module-name>.proto) and go:generate directive
+  - grpc/      gRPC server implementation (registered through pkg/grpcreg)
+  - biz/       business logic and domain entities
+  - data/      data access layer (repository implementations)
+
+Use --layout to select the directory layout preset of a CRUD module (worker and grpc modules always use the drugo layout):
+  - drugo               internal/<module-name>/{api,biz,data,service}, the default
+  - flat                a single package in internal/<module-name>/, files suffixed by layer
+  - handler-logic-repo  app/<module-name>/{handler,logic,repo}
+Without --layout the layout key of .drugo.yaml in the project root is used, keeping all modules of a repository consistent.
+
+This command must be run from a Drugo project root (where go.mod is).`,
+	},
+	msgModuleFlagKind: {zh: "模块类型: api、worker 或 grpc", en: "module kind: api, worker or grpc"},
+	msgModuleCreating: {zh: "正在 %s 中创建模块 %q...\n", en: "Creating module %[2]q in %[1]s...\n"},
+	msgModuleSuccess: {
+		zh: `
+模块 %[1]q 创建成功！
+
+结构:
+  internal/%[1]s/
+  ├── api/
+  │   └── %[1]s.go      # HTTP 处理器和路由
+  ├── biz/
+  │   └── %[1]s.go      # 业务逻辑
+  ├── data/
+  │   └── %[1]s.go      # 数据仓储
+  └── service/
+      └── %[1]s.go      # 服务层
+
+下一步:
+  1. 在 main 包 %[3]s 中导入模块:
+     import _ "%[2]s/internal/%[1]s/api"
+  2. 根据需要自定义生成的代码。
+
+`,
+		en: `
+Module %[1]q created successfully!
+
+Layout:
+  internal/%[1]s/
+  ├── api/
+  │   └── %[1]s.go      # HTTP handlers and routes
+  ├── biz/
+  │   └── %[1]s.go      # business logic
+  ├── data/
+  │   └── %[1]s.go      # data repository
+  └── service/
+      └── %[1]s.go      # service layer
+
+Next steps:
+  1. Import the module in main package %[3]s:
+     import _ "%[2]s/internal/%[1]s/api"
+  2. Customize the generated code as needed.
+
+`,
+	},
+	msgWorkerSuccess: {
+		zh: `
+模块 %[1]q 创建成功！
+
+结构:
+  internal/%[1]s/
+  ├── worker/
+  │   └── %[1]s.go      # 后台任务服务（kernel.Runner）
+  └── biz/
+      └── %[1]s.go      # 业务逻辑
+  conf/
+  └── %[1]s.yaml        # 模块配置
+
+下一步:
+  1. 在 main 包 %[3]s 中注册服务:
+     import %[1]sworker "%[2]s/internal/%[1]s/worker"
+     drugo.WithService(%[1]sworker.New()),
+  2. 根据需要自定义生成的代码。
+
+`,
+		en: `
+Module %[1]q created successfully!
+
+Layout:
+  internal/%[1]s/
+  ├── worker/
+  │   └── %[1]s.go      # worker service (kernel.Runner)
+  └── biz/
+      └── %[1]s.go      # business logic
+  conf/
+  └── %[1]s.yaml        # module config
+
+Next steps:
+  1. Register the service in main package %[3]s:
+     import %[1]sworker "%[2]s/internal/%[1]s/worker"
+     drugo.WithService(%[1]sworker.New()),
+  2. Customize the generated code as needed.
+
+`,
+	},
+	msgGrpcSuccess: {
+		zh: `
+模块 %[1]q 创建成功！
+
+结构:
+  internal/%[1]s/
+  ├── proto/
+  │   ├── %[1]s.proto   # 服务定义
+  │   └── generate.go   # go:generate 指令
+  ├── grpc/
+  │   └── %[1]s.go      # gRPC 服务实现
+  ├── biz/
+  │   └── %[1]s.go      # 业务逻辑
+  └── data/
+      └── %[1]s.go      # 数据仓储
+
+下一步:
+  1. 安装 protoc 插件:
+     go install google.golang.org/protobuf/cmd/protoc-gen-go@latest
+     go install google.golang.org/grpc/cmd/protoc-gen-go-grpc@latest
+  2. 生成代码:
+     go generate ./internal/%[1]s/proto
+  3. 删除 internal/%[1]s/grpc/%[1]s.go 第一行的 //go:build protoc 约束，并添加依赖:
+     go get google.golang.org/grpc
+  4. 在 main 包 %[3]s 中导入模块:
+     import _ "%[2]s/internal/%[1]s/grpc"
+  5. 创建 grpc.Server 后注册服务:
+     grpcreg.Default().Setup(server)
+
+`,
+		en: `
+Module %[1]q created successfully!
+
+Layout:
+  internal/%[1]s/
+  ├── proto/
+  │   ├── %[1]s.proto   # service definition
+  │   └── generate.go   # go:generate directive
+  ├── grpc/
+  │   └── %[1]s.go      # gRPC server implementation
+  ├── biz/
+  │   └── %[1]s.go      # business logic
+  └── data/
+      └── %[1]s.go      # data repository
+
+Next steps:
+  1. Install the protoc plugins:
+     go install google.golang.org/protobuf/cmd/protoc-gen-go@latest
+     go install google.golang.org/grpc/cmd/protoc-gen-go-grpc@latest
+  2. Generate the code:
+     go generate ./internal/%[1]s/proto
+  3. Remove the //go:build protoc constraint from internal/%[1]s/grpc/%[1]s.go and add the dependency:
+     go get google.golang.org/grpc
+  4. Import the module in main package %[3]s:
+     import _ "%[2]s/internal/%[1]s/grpc"
+  5. Register the services after creating the grpc.Server:
+     grpcreg.Default().Setup(server)
+
+`,
+	},
+	msgModuleExists: {zh: "模块 %q 已存在于 %s", en: "module %q already exists at %s"},
+	msgModuleNotExists: {
+		zh: "模块 %[1]q 不存在于 %[2]s，请先使用 'drugo module new %[1]s' 创建模块",
+		en: "module %[1]q does not exist at %[2]s, create it first with 'drugo module new %[1]s'",
+	},
+	msgModuleConfExists: {zh: "配置文件 %q 已存在", en: "config file %q already exists"},
+	msgModuleFailed:     {zh: "创建模块失败: %v", en: "failed to create module: %v"},
+	msgModuleKindInvalid: {
+		zh: "不支持的模块类型 %q，可选值: %s",
+		en: "unsupported module kind %q, valid values: %s",
+	},
+	msgModuleFlagData: {
+		zh: "数据层实现: memory（内存 map，默认）或 gorm（通过 db 服务使用 GORM，api 与 grpc 模块有效）",
+		en: "data layer implementation: memory (in-memory map, the default) or gorm (GORM through the db service, api and grpc modules)",
+	},
+	msgDataInvalid: {
+		zh: "不支持的数据层实现 %q，可选值: %s",
+		en: "unsupported data layer %q, valid values: %s",
+	},
+	msgDataGorm: {
+		zh: `数据层使用 GORM: 需要在 main 包中注册 db 服务（drugo.WithService(dbsvc.New())），并在 conf/db.yaml 中配置 default 分组的 default 实例；
+表结构可以在 Boot 之后调用生成的 Migrate%[1]s(ctx) 创建。
+
+`,
+		en: `The data layer uses GORM: register the db service in the main package (drugo.WithService(dbsvc.New())) and configure the default instance of the default group in conf/db.yaml;
+create the table by calling the generated Migrate%[1]s(ctx) after Boot.
+
+`,
+	},
+	msgDataGormRequire: {
+		zh: "go.mod 尚未依赖 %[1]s，请运行 go mod tidy（或 go get %[1]s）添加依赖。\n\n",
+		en: "go.mod does not require %[1]s yet, run go mod tidy (or go get %[1]s) to add it.\n\n",
+	},
+	msgModuleFlagLayout: {
+		zh: "模块目录结构预设: drugo、flat 或 handler-logic-repo（默认读取 .drugo.yaml 的 layout）",
+		en: "module layout preset: drugo, flat or handler-logic-repo (defaults to layout in .drugo.yaml)",
+	},
+	msgModuleLayoutOK: {
+		zh: `
+模块 %[1]q 创建成功（目录结构: %[2]s）！
+
+文件:
+%[3]s
+下一步:
+  1. 在 main 包 %[5]s 中导入模块:
+     import _ "%[4]s"
+  2. 根据需要自定义生成的代码。
+
+`,
+		en: `
+Module %[1]q created successfully (layout: %[2]s)!
+
+Files:
+%[3]s
+Next steps:
+  1. Import the module in main package %[5]s:
+     import _ "%[4]s"
+  2. Customize the generated code as needed.
+
+`,
+	},
+	msgModuleFlagMain: {
+		zh: "导入模块的 main 包目录，相对项目根目录（默认自动检测）",
+		en: "directory of the main package importing the module, relative to the project root (detected by default)",
+	},
+	msgMainScanFailed: {zh: "查找 main 包失败: %v", en: "failed to find main packages: %v"},
+	msgMainAmbiguous: {
+		zh: "检测到多个 main 包: %s，请使用 --main 指定导入模块的 main 包",
+		en: "multiple main packages found: %s, choose the one importing the module with --main",
+	},
+	msgMainInvalid: {
+		zh: "%q 不是 main 包，可选值: %s",
+		en: "%q is not a main package, valid values: %s",
+	},
+	msgLayoutInvalid: {
+		zh: "不支持的目录结构 %q，可选值: %s",
+		en: "unsupported layout %q, valid values: %s",
+	},
+	msgProjectMetaFailed: {zh: "读取项目元数据文件 %s 失败: %v", en: "failed to read project metadata file %s: %v"},
+
+	msgAPIUse:   {zh: "new-api <模块名称> <API名称>", en: "new-api <module-name> <api-name>"},
+	msgAPIShort: {zh: "在现有模块中创建新的 API 结构", en: "Create a new API in an existing module"},
+	msgAPILong: {
+		zh: `在现有模块中快速生成符合项目结构的 API 相关文件。
+
+命令格式：drugo module new-api <module_name> <api_name>
+
+参数说明：
+  <module_name>: 指定目标模块的名称（例如：goods）。该模块必须已存在于项目中。
+  <api_name>:    指定要创建的新 API 名称（例如：category）。
+
+生成的文件结构：
+  internal/<module_name>/
+  ├── api/
+  │   └── <api_name>.go    # API 层定义
+  ├── biz/
+  │   └── <api_name>.go    # 业务逻辑层
+  ├── data/
+  │   └── <api_name>.go    # 数据访问层
+  └── service/
+  │   └── <api_name>.go    # 服务层
+
+模块使用其他目录结构时，通过 --layout 或 .drugo.yaml 的 layout 指定，文件按该结构放置。`,
+		en: `Generate the API files of an existing module following the project layout.
+
+Usage: drugo module new-api <module_name> <api_name>
+
+Arguments:
+  <module_name>: name of the target module (e.g. goods). The module must already exist.
+  <api_name>:    name of the new API (e.g. category).
+
+Generated files:
+  internal/<module_name>/
+  ├── api/
+  │   └── <api_name>.go    # API layer
+  ├── biz/
+  │   └── <api_name>.go    # business logic layer
+  ├── data/
+  │   └── <api_name>.go    # data access layer
+  └── service/
+  │   └── <api_name>.go    # service layer
+
+For modules using another layout, select it with --layout or the layout key of .drugo.yaml and the files follow that layout.`,
+	},
+	msgAPICreating: {zh: "正在模块 %q 中创建 API %q...\n", en: "Creating API %[2]q in module %[1]q...\n"},
+	msgAPISuccess: {
+		zh: `
+API %[1]q 创建成功！
+
+结构:
+  internal/%[2]s/
+  ├── api/
+  │   └── %[1]s.go
+  ├── biz/
+  │   └── %[1]s.go
+  ├── data/
+  │   └── %[1]s.go
+  └── service/
+      └── %[1]s.go
+
+`,
+		en: `
+API %[1]q created successfully!
+
+Layout:
+  internal/%[2]s/
+  ├── api/
+  │   └── %[1]s.go
+  ├── biz/
+  │   └── %[1]s.go
+  ├── data/
+  │   └── %[1]s.go
+  └── service/
+      └── %[1]s.go
+
+`,
+	},
+	msgAPIFailed: {zh: "创建 API 失败: %v", en: "failed to create API: %v"},
+	msgAPILayoutOK: {
+		zh: "\nAPI %[1]q 已在模块 %[2]q 中创建成功（目录结构: %[3]s）！\n\n",
+		en: "\nAPI %[1]q created successfully in module %[2]q (layout: %[3]s)!\n\n",
+	},
+	msgAPIFile: {zh: "创建文件: %s\n", en: "Created file: %s\n"},
+	msgFileExists: {
+		zh: "文件 %q 已存在，请先删除或使用不同名称",
+		en: "file %q already exists, remove it or use a different name",
+	},
+
+	msgOpenAPIUse:   {zh: "openapi", en: "openapi"},
+	msgOpenAPIShort: {zh: "根据 API 处理器注解生成 OpenAPI 文档", en: "Generate an OpenAPI spec from the API handler annotations"},
+	msgOpenAPILong: {
+		zh: `扫描项目中所有模块的 API 处理器，根据注解生成 OpenAPI 3.0 文档，默认写入 docs/openapi.yaml。
+
+生成的 API 处理器在注释中带有以下注解：
+  // @route GET /user/address/:id    请求方法与 gin 路径（必需）
+  // @query ListAddressRequest       通过 form 标签绑定的查询参数结构体
+  // @body CreateAddressRequest      JSON 请求体结构体
+  // @success 200 AddressResponse    成功状态码与响应数据结构体（包装在 code/message/data 中）
+
+结构体的字段及 json、form、binding 标签会被转换为 schema；
+无法识别的字段类型使用通用的 object schema，并在最后列出警告。
+生成的项目通过 router.RegisterDocs 在 /docs 提供 Swagger UI，在 /docs/openapi.yaml 提供该文档。`,
+		en: `Scan the API handlers of all modules in the project and generate an OpenAPI 3.0 spec
+from their annotations, written to docs/openapi.yaml by default.
+
+Generated API handlers carry these annotations in their doc comments:
+  // @route GET /user/address/:id    HTTP method and gin path (required)
+  // @query ListAddressRequest       struct bound from the query string via form tags
+  // @body CreateAddressRequest      struct bound from the JSON body
+  // @success 200 AddressResponse    status code and response data struct (wrapped in code/message/data)
+
+Struct fields and their json, form and binding tags become schemas;
+unsupported field types fall back to a generic object schema and are listed as warnings at the end.
+Generated projects serve Swagger UI at /docs and the spec at /docs/openapi.yaml via router.RegisterDocs.`,
+	},
+	msgOpenAPIFlagOutput: {zh: "输出文件，相对路径基于项目根目录", en: "output file, relative to the project root"},
+	msgOpenAPIFlagTitle:  {zh: "文档标题，默认使用模块路径的最后一段", en: "spec title, defaults to the last element of the module path"},
+	msgOpenAPISuccess:    {zh: "已生成 OpenAPI 文档 %s（%d 个接口）\n", en: "Generated OpenAPI spec %s (%d operations)\n"},
+	msgOpenAPIWarnings:   {zh: "%d 个警告：\n", en: "%d warnings:\n"},
+	msgOpenAPIFailed:     {zh: "生成 OpenAPI 文档失败: %v", en: "failed to generate OpenAPI spec: %v"},
+
+	msgRoutesUse:   {zh: "routes", en: "routes"},
+	msgRoutesShort: {zh: "静态分析并列出所有模块注册的路由", en: "List the routes registered by all modules"},
+	msgRoutesLong: {
+		zh: `不运行应用，通过解析源码列出项目中所有模块注册的 gin 路由，按路径排序输出 METHOD、PATH、MODULE、HANDLER。
+
+能识别的写法：
+  - 路由参数（gin.IRouter、gin.IRoutes、*gin.Engine、*gin.RouterGroup）及 gin.New()/gin.Default()
+  - group := r.Group("/prefix")，可以嵌套，也可以将分组传给模块内的其他函数
+  - group.GET/POST/PUT/DELETE/PATCH/HEAD/OPTIONS/Any/Handle 的字面量路径，以及由字符串常量拼接的路径
+
+无法静态解析的路径显示为 <dynamic> 并附带源码位置。
+不同模块注册了相同的 METHOD 与 PATH 时以红色标出，使用 --strict 时以非零状态码退出，适合在 CI 中检查路由冲突。`,
+		en: `List the gin routes registered by all modules of the project by parsing the source, without
+running the application, sorted by path as METHOD, PATH, MODULE and HANDLER.
+
+Recognized patterns:
+  - router parameters (gin.IRouter, gin.IRoutes, *gin.Engine, *gin.RouterGroup) and gin.New()/gin.Default()
+  - group := r.Group("/prefix"), nested or passed to other functions of the module
+  - literal paths of group.GET/POST/PUT/DELETE/PATCH/HEAD/OPTIONS/Any/Handle, and paths built from string constants
+
+Paths that cannot be resolved statically are shown as <dynamic> with their source location.
+Routes with the same METHOD and PATH in different modules are highlighted in red; with --strict
+the command exits non-zero, which suits route conflict checks in CI.`,
+	},
+	msgRoutesFlagJSON:   {zh: "以 JSON 数组输出", en: "print the routes as a JSON array"},
+	msgRoutesFlagStrict: {zh: "存在跨模块重复的路由时以非零状态码退出", en: "exit non-zero if a route is registered by more than one module"},
+	msgRoutesEmpty:      {zh: "未找到路由\n", en: "No routes found\n"},
+	msgRoutesDuplicates: {zh: "\n%d 条路由在多个模块中重复注册\n", en: "\n%d routes are registered by more than one module\n"},
+	msgRoutesConflict:   {zh: "%d 条路由在多个模块中重复注册", en: "%d routes are registered by more than one module"},
+	msgRoutesFailed:     {zh: "分析路由失败: %v", en: "failed to analyze routes: %v"},
+
+	msgGenerateShort: {zh: "生成代码", en: "Generate code"},
+	msgGenerateLong: {
+		zh: "根据项目源码生成代码，需要在 Drugo 项目中运行。",
+		en: "Generate code from the project source, run from inside a Drugo project.",
+	},
+	msgServicesShort: {zh: "生成类型化的服务访问函数", en: "Generate typed service accessors"},
+	msgServicesLong: {
+		zh: `加载项目的 main 包，找出 drugo.WithService、WithNameService 等选项注册的服务，
+确定每个服务的名称与具体类型（沿着构造函数的返回类型跨包解析），生成 internal/pkg/services/services.go：
+
+  func Gin(k kernel.Kernel) (*ginsrv.GinService, error)
+  func MustGin(k kernel.Kernel) *ginsrv.GinService
+
+访问函数写死了正确的名称与类型，服务重命名或类型变化后重新生成，错误的调用会在编译时而不是运行时暴露。
+无法确定类型的服务生成返回 any 的访问函数，无法确定名称的服务不生成访问函数，两者都会输出警告。
+输出按服务名称排序，相同的源码总是生成相同的文件；--check 只检查文件是否最新，适合在 CI 中使用。`,
+		en: `Load the main packages of the project, find the services registered with drugo.WithService,
+WithNameService and related options, resolve the name and concrete type of each service (following the
+constructor's return type across packages) and generate internal/pkg/services/services.go:
+
+  func Gin(k kernel.Kernel) (*ginsrv.GinService, error)
+  func MustGin(k kernel.Kernel) *ginsrv.GinService
+
+The accessors hard-code the right name and type: regenerate after renaming a service or changing its
+type, and wrong call sites fail to compile instead of panicking in production.
+Services whose type cannot be resolved get an any-typed accessor, services whose name cannot be resolved
+get none; both are reported as warnings. Accessors are sorted by service name, so the same source always
+generates the same file; --check only verifies the file is up to date, which suits CI.`,
+	},
+	msgServicesFlagOutput:   {zh: "输出文件，相对路径基于项目根目录，必须位于项目中", en: "output file, relative to the project root, must be inside the project"},
+	msgServicesFlagCheck:    {zh: "不写入文件，文件不是最新时以非零状态码退出", en: "do not write the file, exit non-zero if it is out of date"},
+	msgServicesSuccess:      {zh: "已生成服务访问函数 %s（%d 个服务）\n", en: "Generated service accessors %s (%d services)\n"},
+	msgServicesUpToDate:     {zh: "服务访问函数 %s 已是最新\n", en: "Service accessors %s are up to date\n"},
+	msgServicesWarnings:     {zh: "%d 个警告：\n", en: "%d warnings:\n"},
+	msgServicesNameDynamic:  {zh: "服务名称 %s 不是常量，未生成访问函数", en: "service name %s is not a constant, no accessor generated"},
+	msgServicesNameUnknown:  {zh: "无法确定 %s 的服务名称，未生成访问函数，可以改用 drugo.WithNameService", en: "cannot resolve the service name of %s, no accessor generated; use drugo.WithNameService"},
+	msgServicesTypeUnknown:  {zh: "无法确定服务 %q 的类型，访问函数返回 any", en: "cannot resolve the type of service %q, its accessor returns any"},
+	msgServicesTypeConflict: {zh: "服务 %q 的类型与 %s 中的注册不同，访问函数返回 any", en: "service %q has a different type than registered at %s, its accessor returns any"},
+	msgServicesFuncConflict: {zh: "服务 %q 与 %q 的访问函数名称都是 %s，未生成访问函数", en: "services %q and %q both map to accessor %s, no accessor generated"},
+	msgServicesNoMain:       {zh: "%s 中没有 main 包", en: "no main package found in %s"},
+	msgServicesOutside:      {zh: "输出文件 %s 不在项目中", en: "output file %s is outside the project"},
+	msgServicesStale:        {zh: "%s 不是最新的，请执行 drugo generate services", en: "%s is out of date, run drugo generate services"},
+	msgServicesFailed:       {zh: "生成服务访问函数失败: %v", en: "failed to generate service accessors: %v"},
+
+	msgLintShort: {zh: "检查内置模板能否生成合法的 Go 与 YAML", en: "Check that the embedded templates render valid Go and YAML"},
+	msgLintLong: {
+		zh: `使用代表性的项目、模块与 API 数据执行所有内置模板（CRUD 模块模板会针对每种布局执行），并校验输出：
+Go 模板的输出必须能被 go/parser 解析，且 gofmt 的结果稳定；YAML 模板的输出必须能被反序列化。
+失败时输出模板名称、出错的行列号及上下文，并以非零状态码退出，可用于 CI。`,
+		en: `Execute every embedded template with representative project, module and API data (CRUD module
+templates once per layout) and validate the output: Go templates must parse with go/parser and format
+stably with gofmt, YAML templates must unmarshal. Failures report the template name, the line and column
+and a context snippet, and the command exits non-zero so CI catches regressions.`,
+	},
+	msgLintOK:     {zh: "全部 %d 个模板检查通过\n", en: "All %d templates passed\n"},
+	msgLintFailed: {zh: "%d 个模板检查失败", en: "%d template checks failed"},
+
+	msgConfigShort: {zh: "查看项目配置", en: "Inspect the project configuration"},
+	msgConfigLong: {
+		zh: "查看项目 conf/ 目录中的配置，需要在 Drugo 项目中运行。",
+		en: "Inspect the configuration in the conf/ directory of the project, run from inside a Drugo project.",
+	},
+	msgConfigExportShort: {zh: "导出合并后的完整配置", en: "Export the merged configuration"},
+	msgConfigExportLong: {
+		zh: `加载项目的 conf/ 目录，按应用启动时的规则合并（包括 --env 或 DRUGO_ENV 选择的环境层），
+将合并后的完整配置以 YAML 或 JSON 写入标准输出或 --out 指定的文件，用于问题排查。
+
+输出按键排序，相同配置的输出除导出时间外完全一致；头部记录导出时间、配置目录以及各来源文件的校验和。
+--redact 会将 password、secret、token、dsn 等敏感配置项替换为 ******，发送给他人之前请务必使用。`,
+		en: `Load the conf/ directory of the project, merge it the way the application does at startup
+(including the environment layer selected by --env or DRUGO_ENV) and write the merged configuration
+as YAML or JSON to stdout or to the file given by --out, for troubleshooting.
+
+Keys are sorted, so the same configuration always produces the same output apart from the export time;
+the header records the export time, the config directory and the checksum of every source file.
+--redact replaces sensitive keys such as password, secret, token and dsn with ******; always use it before sharing the output.`,
+	},
+	msgConfigExportFlagRedact: {zh: "替换敏感配置项", en: "replace sensitive values"},
+	msgConfigExportFlagFormat: {zh: "输出格式: yaml 或 json", en: "output format: yaml or json"},
+	msgConfigExportFlagOut:    {zh: "输出文件，相对路径基于项目根目录，默认写入标准输出", en: "output file, relative to the project root, defaults to stdout"},
+	msgConfigExportFlagEnv:    {zh: "配置环境，默认读取 DRUGO_ENV", en: "configuration environment, defaults to DRUGO_ENV"},
+	msgConfigExportSuccess:    {zh: "已导出配置 %s\n", en: "Exported configuration to %s\n"},
+	msgConfigLoadFailed:       {zh: "加载配置失败: %v", en: "failed to load configuration: %v"},
+	msgConfigExportFailed:     {zh: "导出配置失败: %v", en: "failed to export configuration: %v"},
+	msgConfigUnusedShort:      {zh: "列出应用启动时没有读取的配置项", en: "List configuration keys the application did not read at startup"},
+	msgConfigUnusedLong: {
+		zh: `读取应用写入的启动报告（默认为 runtime/boot-report.json），列出 Boot 结束时没有被读取过的配置项，
+通常是拼写错误或已经不再使用的配置。
+
+应用需要启用 drugo.WithConfigUsageTracking 与 drugo.WithBootReportFile，并至少启动过一次：
+只有通过 config.Config、Manager.Unmarshal 等类型化方法读取的配置项会被记录，
+注入给 kernel.Configurable 服务的配置段整体视为已读取。`,
+		en: `Read the boot report written by the application (runtime/boot-report.json by default) and list the
+configuration keys that had not been read when Boot finished, usually typos or settings that are no longer used.
+
+The application must enable drugo.WithConfigUsageTracking and drugo.WithBootReportFile and have started at least once:
+only keys read through typed accessors such as config.Config and Manager.Unmarshal are recorded,
+and sections injected into kernel.Configurable services count as read as a whole.`,
+	},
+	msgConfigUnusedFlagReport: {zh: "启动报告文件，相对路径基于项目根目录", en: "boot report file, relative to the project root"},
+	msgConfigUnusedHeader:     {zh: "%d 个配置项没有被读取（启动报告采集于 %s）:\n", en: "%d configuration keys were not read (boot report captured at %s):\n"},
+	msgConfigUnusedNone:       {zh: "所有配置项都已被读取（启动报告采集于 %s）\n", en: "All configuration keys were read (boot report captured at %s)\n"},
+	msgConfigUnusedUntracked:  {zh: "启动报告 %s 没有配置读取记录，请在应用中启用 drugo.WithConfigUsageTracking", en: "boot report %s has no configuration usage data, enable drugo.WithConfigUsageTracking in the application"},
+	msgConfigReportFailed:     {zh: "读取启动报告失败: %v", en: "failed to read the boot report: %v"},
+
+	msgAddShort: {zh: "向项目中添加组件", en: "Add components to the project"},
+	msgAddLong:  {zh: "向已有的 Drugo 项目中添加组件，需要在 Drugo 项目中运行。", en: "Add components to an existing Drugo project, run from inside a Drugo project."},
+	msgAddServiceUse: {
+		zh: "service <服务名称>",
+		en: "service <name>",
+	},
+	msgAddServiceShort: {zh: "将内置的服务 provider 接入项目", en: "Wire a built-in service provider into the project"},
+	msgAddServiceLong: {
+		zh: `将内置目录中的服务 provider 接入已有项目，可选值: ` + strings.Join(catalogNames(), ", ") + `。
+
+执行以下步骤，已经完成的步骤会跳过，重复执行不会产生重复的内容：
+  - 在 go.mod 中添加 provider 模块的 require
+  - 在 main 包（见 --main）中导入 provider 包，并在 drugo.MustNewApp 中添加 drugo.WithService(xxx.New())
+  - conf/<服务名称>.yaml 不存在时生成默认配置
+
+--dry-run 以 unified diff 输出将要进行的修改，不写入任何文件。`,
+		en: `Wire a service provider from the built-in catalog into an existing project, valid values: ` + strings.Join(catalogNames(), ", ") + `.
+
+The command runs the following steps, skipping the ones already done, so running it again changes nothing:
+  - add the require line of the provider module to go.mod
+  - import the provider package in the main package (see --main) and add drugo.WithService(xxx.New()) to drugo.MustNewApp
+  - write the default conf/<name>.yaml when it does not exist
+
+--dry-run prints the changes as unified diffs without writing any file.`,
+	},
+	msgAddFlagDryRun: {zh: "以 unified diff 输出修改，不写入文件", en: "print the changes as unified diffs instead of writing them"},
+	msgAddFlagMain: {
+		zh: "创建应用的 main 包目录，相对于项目根目录（默认自动检测）",
+		en: "directory of the main package creating the app, relative to the project root (detected by default)",
+	},
+	msgAddSuccess:    {zh: "已添加服务 %s:\n", en: "Added service %s:\n"},
+	msgAddCreated:    {zh: "  新建 %s\n", en: "  created  %s\n"},
+	msgAddModified:   {zh: "  修改 %s\n", en: "  modified %s\n"},
+	msgAddNext:       {zh: "\n下一步: 运行 go mod tidy 下载依赖\n", en: "\nNext: run go mod tidy to download the dependencies\n"},
+	msgAddUpToDate:   {zh: "服务 %s 已经接入，无需修改\n", en: "Service %s is already wired in, nothing to change\n"},
+	msgAddUnknown:    {zh: "未知的服务 %q，可选值: %s", en: "unknown service %q, valid values: %s"},
+	msgAddNoMain:     {zh: "%s 中没有声明 func main 的文件", en: "no file in %s declares func main"},
+	msgAddMainFailed: {zh: "修改 %s 失败: %v", en: "failed to update %s: %v"},
+
+	msgFieldModule: {zh: "模块名称", en: "module name"},
+	msgFieldAPI:    {zh: "API名称", en: "API name"},
+	msgNameEmpty:   {zh: "%s不能为空", en: "%s must not be empty"},
+	msgNameInvalid: {
+		zh: "%s不能包含空格、点号、连字符或路径分隔符",
+		en: "%s must not contain spaces, dots, hyphens or path separators",
+	},
+	msgNameChars: {zh: "%s只能包含字母和数字", en: "%s may only contain letters and digits"},
+
+	msgWdFailed: {zh: "获取工作目录失败: %v", en: "failed to get working directory: %v"},
+	msgNotInProject: {
+		zh: "不在 %s 目录中，请在 Drugo 项目根目录运行",
+		en: "%s is not inside a Drugo project, run this command from the project root",
+	},
+	msgGoModFailed:   {zh: "读取 go.mod 失败: %v", en: "failed to read go.mod: %v"},
+	msgDirFailed:     {zh: "创建目录 %q 失败: %v", en: "failed to create directory %q: %v"},
+	msgFileFailed:    {zh: "创建文件 %q 失败: %v", en: "failed to create file %q: %v"},
+	msgTplParse:      {zh: "解析模板 %q 失败: %v", en: "failed to parse template %q: %v"},
+	msgTplExecFailed: {zh: "执行模板 %q 失败: %v", en: "failed to execute template %q: %v"},
+}
+
+// projectTree returns the generated project layout used in the help text of `drugo new`.
+func projectTree(name string) string {
+	return `  ` + name + `/
+  ├── cmd/
+  │   └── app/
+  │       └── main.go
+  ├── conf/
+  │   ├── db.yaml
+  │   ├── gin.yaml
+  │   ├── i18n.yaml
+  │   ├── log.yaml
+  │   └── redis.yaml
+  ├── internal/
+  ├── locales/
+  │   ├── en/
+  │   │   └── app.en.yml
+  │   └── zh/
+  │       └── app.zh.yml
+  ├── runtime/
+  │   └── logs/
+  ├── go.mod
+  ├── Makefile
+  ├── .gitignore
+  └── README.md`
+}
+
+// msg returns the message for id in the current language, formatted with args.
+func msg(id msgID, args ...any) string {
+	m, ok := messages[id]
+	if !ok {
+		return string(id)
+	}
+	text := m.zh
+	if lang == langEn {
+		text = m.en
+	}
+	if len(args) == 0 {
+		return text
+	}
+	return fmt.Sprintf(text, args...)
+}
+
+// cliError is an error whose text comes from the message catalog.
+// The stable message ID is included in the text so scripts can match on it.
+type cliError struct {
+	id  msgID
+	msg string
+	err error
+}
+
+func (e *cliError) Error() string {
+	return fmt.Sprintf("[%s] %s", e.id, e.msg)
+}
+
+func (e *cliError) Unwrap() error {
+	return e.err
+}
+
+// ID returns the stable message ID of the error.
+func (e *cliError) ID() string {
+	return string(e.id)
+}
+
+// newError creates a cliError for id. The first error in args, if any, is kept as the cause.
+func newError(id msgID, args ...any) error {
+	e := &cliError{id: id, msg: msg(id, args...)}
+	for _, arg := range args {
+		if err, ok := arg.(error); ok {
+			e.err = err
+			break
+		}
+	}
+	return e
+}
+
+// errorID returns the message ID of the outermost cliError in err, or "" if there is none.
+func errorID(err error) string {
+	var e *cliError
+	if errors.As(err, &e) {
+		return e.ID()
+	}
+	return ""
+}
+
+// resolveLang picks the CLI language: the --lang flag, then DRUGO_LANG, then the LANG locale.
+// It falls back to Chinese when nothing matches.
+func resolveLang(flagValue string) (string, error) {
+	if flagValue != "" {
+		l, ok := normalizeLang(flagValue)
+		if !ok {
+			return langZh, newError(msgLangInvalid, flagValue)
+		}
+		return l, nil
+	}
+	for _, key := range []string{langEnvVar, "LC_ALL", "LANG"} {
+		if l, ok := normalizeLang(os.Getenv(key)); ok {
+			return l, nil
+		}
+	}
+	return langZh, nil
+}
+
+// normalizeLang maps values such as "en", "en-US" and "en_US.UTF-8" to a supported language.
+func normalizeLang(value string) (string, bool) {
+	value = strings.ToLower(value)
+	switch {
+	case strings.HasPrefix(value, langZh):
+		return langZh, true
+	case strings.HasPrefix(value, langEn):
+		return langEn, true
+	}
+	return "", false
+}
+
+// langFromArgs extracts the value of --lang from raw arguments. It runs before cobra parses
+// flags so that help texts can be localized.
+func langFromArgs(args []string) string {
+	for i, arg := range args {
+		if arg == "--" {
+			break
+		}
+		if v, ok := strings.CutPrefix(arg, "--lang="); ok {
+			return v
+		}
+		if arg == "--lang" && i+1 < len(args) {
+			return args[i+1]
+		}
+	}
+	return ""
+}
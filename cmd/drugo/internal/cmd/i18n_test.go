@@ -0,0 +1,141 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// useLang switches the CLI language for the duration of a test.
+func useLang(t *testing.T, l string) {
+	t.Helper()
+	prev := lang
+	lang = l
+	t.Cleanup(func() { lang = prev })
+}
+
+// TestResolveLang tests the --lang, DRUGO_LANG and LANG precedence.
+func TestResolveLang(t *testing.T) {
+	t.Setenv("LC_ALL", "")
+
+	t.Run("default zh", func(t *testing.T) {
+		t.Setenv(langEnvVar, "")
+		t.Setenv("LANG", "C.UTF-8")
+		l, err := resolveLang("")
+		require.NoError(t, err)
+		assert.Equal(t, langZh, l)
+	})
+
+	t.Run("LANG fallback", func(t *testing.T) {
+		t.Setenv(langEnvVar, "")
+		t.Setenv("LANG", "en_US.UTF-8")
+		l, err := resolveLang("")
+		require.NoError(t, err)
+		assert.Equal(t, langEn, l)
+	})
+
+	t.Run("DRUGO_LANG over LANG", func(t *testing.T) {
+		t.Setenv(langEnvVar, "zh-CN")
+		t.Setenv("LANG", "en_US.UTF-8")
+		l, err := resolveLang("")
+		require.NoError(t, err)
+		assert.Equal(t, langZh, l)
+	})
+
+	t.Run("flag over env", func(t *testing.T) {
+		t.Setenv(langEnvVar, "zh")
+		l, err := resolveLang("en-US")
+		require.NoError(t, err)
+		assert.Equal(t, langEn, l)
+	})
+
+	t.Run("invalid flag", func(t *testing.T) {
+		_, err := resolveLang("fr")
+		assert.Equal(t, string(msgLangInvalid), errorID(err))
+	})
+}
+
+// TestLangFromArgs tests extracting --lang before cobra parses flags.
+func TestLangFromArgs(t *testing.T) {
+	assert.Equal(t, "en", langFromArgs([]string{"new", "--lang", "en", "app"}))
+	assert.Equal(t, "zh", langFromArgs([]string{"--lang=zh", "new"}))
+	assert.Equal(t, "", langFromArgs([]string{"new", "--", "--lang=en"}))
+	assert.Equal(t, "", langFromArgs([]string{"new", "app"}))
+}
+
+// TestMsg tests both language variants of representative messages.
+func TestMsg(t *testing.T) {
+	cases := []struct {
+		id   msgID
+		args []any
+		zh   string
+		en   string
+	}{
+		{msgNewShort, nil, "创建一个新的 Drugo 项目", "Create a new Drugo project"},
+		{msgProjectExists, []any{"app"}, `目录 "app" 已存在`, `directory "app" already exists`},
+		{msgNameEmpty, []any{"模块名称"}, "模块名称不能为空", "模块名称 must not be empty"},
+		{msgModuleCreating, []any{"/src", "user"}, "正在 /src 中创建模块 \"user\"...\n", "Creating module \"user\" in /src...\n"},
+		{msgShellInvalid, []any{"tcsh"}, `不支持的 shell "tcsh"，可选值: bash, zsh, fish, powershell`, `unsupported shell "tcsh", valid values: bash, zsh, fish, powershell`},
+	}
+
+	for _, tc := range cases {
+		t.Run(string(tc.id), func(t *testing.T) {
+			t.Setenv(langEnvVar, "zh")
+			l, err := resolveLang("")
+			require.NoError(t, err)
+			useLang(t, l)
+			assert.Equal(t, tc.zh, msg(tc.id, tc.args...))
+
+			t.Setenv(langEnvVar, "en")
+			l, err = resolveLang("")
+			require.NoError(t, err)
+			lang = l
+			assert.Equal(t, tc.en, msg(tc.id, tc.args...))
+		})
+	}
+}
+
+// TestMessagesComplete ensures every message has both translations.
+func TestMessagesComplete(t *testing.T) {
+	for id, m := range messages {
+		assert.NotEmpty(t, m.zh, id)
+		assert.NotEmpty(t, m.en, id)
+	}
+}
+
+// TestNewError tests that errors carry stable IDs and keep their cause.
+func TestNewError(t *testing.T) {
+	useLang(t, langEn)
+	cause := errors.New("disk full")
+
+	err := newError(msgProjectFailed, newError(msgDirFailed, "app/conf", cause))
+	assert.Equal(t, string(msgProjectFailed), errorID(err))
+	assert.ErrorIs(t, err, cause)
+	assert.Equal(t, `[project.create_failed] failed to create project: [dir.create_failed] failed to create directory "app/conf": disk full`, err.Error())
+
+	lang = langZh
+	assert.Equal(t, "[project.name_empty] 项目名称不能为空", validateProjectName("").Error())
+	assert.Equal(t, "", errorID(cause))
+}
+
+// TestRunCompletion tests generating completion scripts.
+func TestRunCompletion(t *testing.T) {
+	for _, shell := range completionShells {
+		t.Run(shell, func(t *testing.T) {
+			var out bytes.Buffer
+			completionCmd.SetOut(&out)
+			t.Cleanup(func() { completionCmd.SetOut(os.Stdout) })
+
+			require.NoError(t, runCompletion(completionCmd, []string{shell}))
+			assert.Contains(t, out.String(), "drugo")
+		})
+	}
+
+	err := runCompletion(&cobra.Command{}, []string{"tcsh"})
+	assert.Equal(t, string(msgShellInvalid), errorID(err))
+}
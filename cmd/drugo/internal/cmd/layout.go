@@ -0,0 +1,210 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"text/template"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// Layout presets.
+const (
+	layoutDrugo            = "drugo"
+	layoutFlat             = "flat"
+	layoutHandlerLogicRepo = "handler-logic-repo"
+)
+
+// Template keys of the CRUD module layers.
+const (
+	layerAPI     = "api"
+	layerBiz     = "biz"
+	layerData    = "data"
+	layerService = "service"
+)
+
+// projectMetaFile is the project metadata file read from the project root.
+// Its "layout" key selects the default module layout of the project.
+const projectMetaFile = ".drugo.yaml"
+
+// LayoutLayer places one template layer of a module.
+type LayoutLayer struct {
+	Key     string // template key: api, biz, data or service
+	Subdir  string // directory relative to the module directory, empty for the module directory itself
+	Package string // package name, empty means the module name
+	File    string // file name pattern, %s is replaced by the module or API name
+}
+
+// Layout describes where the module generator puts each layer of a CRUD module.
+type Layout struct {
+	Name   string
+	Root   string // directory holding the modules, relative to the project root
+	Layers []LayoutLayer
+}
+
+// layouts lists the available layout presets, the first one is the default.
+var layouts = []Layout{
+	{
+		Name: layoutDrugo,
+		Root: "internal",
+		Layers: []LayoutLayer{
+			{Key: layerAPI, Subdir: "api", Package: "api", File: "%s.go"},
+			{Key: layerBiz, Subdir: "biz", Package: "biz", File: "%s.go"},
+			{Key: layerData, Subdir: "data", Package: "data", File: "%s.go"},
+			{Key: layerService, Subdir: "service", Package: "service", File: "%s.go"},
+		},
+	},
+	{
+		Name: layoutFlat,
+		Root: "internal",
+		Layers: []LayoutLayer{
+			{Key: layerAPI, File: "%s_api.go"},
+			{Key: layerBiz, File: "%s_biz.go"},
+			{Key: layerData, File: "%s_data.go"},
+			{Key: layerService, File: "%s_service.go"},
+		},
+	},
+	{
+		Name: layoutHandlerLogicRepo,
+		Root: "app",
+		Layers: []LayoutLayer{
+			{Key: layerAPI, Subdir: "handler", Package: "handler", File: "%s.go"},
+			{Key: layerBiz, Subdir: "logic", Package: "logic", File: "%s.go"},
+			{Key: layerService, Subdir: "logic", Package: "logic", File: "%s_service.go"},
+			{Key: layerData, Subdir: "repo", Package: "repo", File: "%s.go"},
+		},
+	},
+}
+
+// layoutNames returns the names of all layout presets.
+func layoutNames() []string {
+	names := make([]string, 0, len(layouts))
+	for _, l := range layouts {
+		names = append(names, l.Name)
+	}
+	return names
+}
+
+// findLayout returns the layout preset with the given name.
+func findLayout(name string) (Layout, error) {
+	for _, l := range layouts {
+		if l.Name == name {
+			return l, nil
+		}
+	}
+	return Layout{}, newError(msgLayoutInvalid, name, strings.Join(layoutNames(), ", "))
+}
+
+// resolveLayout returns the layout selected by the --layout flag of cmd,
+// falling back to the project default in .drugo.yaml and then to the drugo layout.
+func resolveLayout(cmd *cobra.Command, projectRoot string) (Layout, error) {
+	if f := cmd.Flags().Lookup("layout"); f != nil && f.Changed {
+		return findLayout(f.Value.String())
+	}
+	name, err := projectLayout(projectRoot)
+	if err != nil {
+		return Layout{}, err
+	}
+	if name == "" {
+		return layouts[0], nil
+	}
+	return findLayout(name)
+}
+
+// projectLayout reads the default layout name from the project metadata file,
+// returning an empty name when the file does not exist.
+func projectLayout(projectRoot string) (string, error) {
+	file := filepath.Join(projectRoot, projectMetaFile)
+	if _, err := os.Stat(file); os.IsNotExist(err) {
+		return "", nil
+	}
+	v := viper.New()
+	v.SetConfigFile(file)
+	if err := v.ReadInConfig(); err != nil {
+		return "", newError(msgProjectMetaFailed, file, err)
+	}
+	return v.GetString("layout"), nil
+}
+
+// ModuleDir returns the directory of the module in the project.
+func (l Layout) ModuleDir(projectRoot, module string) string {
+	return filepath.Join(projectRoot, l.Root, module)
+}
+
+// Layer returns the layer with the given template key.
+func (l Layout) Layer(key string) LayoutLayer {
+	i := slices.IndexFunc(l.Layers, func(layer LayoutLayer) bool { return layer.Key == key })
+	if i < 0 {
+		panic(fmt.Sprintf("layout %q has no %q layer", l.Name, key))
+	}
+	return l.Layers[i]
+}
+
+// Dirs returns the distinct directories of the module, in layer order.
+func (l Layout) Dirs(projectRoot, module string) []string {
+	var dirs []string
+	for _, layer := range l.Layers {
+		dir := filepath.Join(l.ModuleDir(projectRoot, module), layer.Subdir)
+		if !slices.Contains(dirs, dir) {
+			dirs = append(dirs, dir)
+		}
+	}
+	return dirs
+}
+
+// FilePath returns the path of the file generated for name in the layer.
+func (l Layout) FilePath(projectRoot, module string, layer LayoutLayer, name string) string {
+	return filepath.Join(l.ModuleDir(projectRoot, module), layer.Subdir, fmt.Sprintf(layer.File, name))
+}
+
+// ImportPath returns the import path of the layer's package.
+func (l Layout) ImportPath(modPath, module string, layer LayoutLayer) string {
+	return path.Join(modPath, l.Root, module, layer.Subdir)
+}
+
+// PackageName returns the package name of the layer.
+func (l Layout) PackageName(module string, layer LayoutLayer) string {
+	if layer.Package == "" {
+		return module
+	}
+	return layer.Package
+}
+
+// Funcs returns the template functions used to render a file of the given layer:
+//
+//	{{imports "biz" "service"}}  import lines of the layers in other packages
+//	{{q "biz"}}                  package qualifier of a layer, empty in the same package
+func (l Layout) Funcs(modPath, module string, current LayoutLayer) template.FuncMap {
+	samePackage := func(key string) bool {
+		return l.Layer(key).Subdir == current.Subdir
+	}
+	return template.FuncMap{
+		"imports": func(keys ...string) string {
+			var b strings.Builder
+			for _, key := range keys {
+				if samePackage(key) {
+					continue
+				}
+				layer := l.Layer(key)
+				importPath := l.ImportPath(modPath, module, layer)
+				b.WriteString("\n\t")
+				if pkg := l.PackageName(module, layer); pkg != path.Base(importPath) {
+					b.WriteString(pkg + " ")
+				}
+				b.WriteString(fmt.Sprintf("%q", importPath))
+			}
+			return b.String()
+		},
+		"q": func(key string) string {
+			if samePackage(key) {
+				return ""
+			}
+			return l.PackageName(module, l.Layer(key)) + "."
+		},
+	}
+}
@@ -0,0 +1,115 @@
+package cmd
+
+import (
+	"go/parser"
+	"go/token"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestCreateModule_Layouts 测试按每个目录结构预设生成模块和 API：
+// 目录结构、包声明以及模块内的导入路径保持一致
+func TestCreateModule_Layouts(t *testing.T) {
+	const modPath = "github.com/acme/app"
+	wantDirs := map[string][]string{
+		layoutDrugo:            {"internal/user/api", "internal/user/biz", "internal/user/data", "internal/user/service"},
+		layoutFlat:             {"internal/user"},
+		layoutHandlerLogicRepo: {"app/user/handler", "app/user/logic", "app/user/repo"},
+	}
+
+	for _, layout := range layouts {
+		t.Run(layout.Name, func(t *testing.T) {
+			root := t.TempDir()
+			require.NoError(t, createModule(root, modPath, "user", layout, dataMemory))
+			require.NoError(t, createModuleApi(io.Discard, root, modPath, "user", "address", layout, dataMemory))
+
+			var dirs []string
+			for _, dir := range layout.Dirs(root, "user") {
+				rel, err := filepath.Rel(root, dir)
+				require.NoError(t, err)
+				dirs = append(dirs, filepath.ToSlash(rel))
+				assert.DirExists(t, dir)
+			}
+			assert.Equal(t, wantDirs[layout.Name], dirs)
+
+			// 模块内每个目录对应一个包
+			packages := map[string]string{}
+			for _, layer := range layout.Layers {
+				packages[layout.ImportPath(modPath, "user", layer)] = layout.PackageName("user", layer)
+			}
+
+			fset := token.NewFileSet()
+			for _, layer := range layout.Layers {
+				for _, name := range []string{"user", "address"} {
+					path := layout.FilePath(root, "user", layer, name)
+					f, err := parser.ParseFile(fset, path, nil, parser.AllErrors)
+					require.NoError(t, err, path)
+					assert.Equal(t, layout.PackageName("user", layer), f.Name.Name, path)
+
+					self := layout.ImportPath(modPath, "user", layer)
+					for _, imp := range f.Imports {
+						importPath, err := strconv.Unquote(imp.Path.Value)
+						require.NoError(t, err)
+						if !strings.HasPrefix(importPath, modPath+"/") {
+							continue
+						}
+						pkg, ok := packages[importPath]
+						require.True(t, ok, "%s imports %s outside the module", path, importPath)
+						assert.NotEqual(t, self, importPath, "%s imports its own package", path)
+						if imp.Name != nil {
+							assert.Equal(t, pkg, imp.Name.Name, path)
+						} else {
+							assert.Equal(t, pkg, filepath.Base(importPath), path)
+						}
+					}
+				}
+			}
+		})
+	}
+}
+
+// TestResolveLayout 测试 --layout、.drugo.yaml 与默认值的优先级
+func TestResolveLayout(t *testing.T) {
+	newCmd := func(args ...string) *cobra.Command {
+		c := &cobra.Command{}
+		c.Flags().String("layout", layoutDrugo, "")
+		require.NoError(t, c.Flags().Parse(args))
+		return c
+	}
+
+	t.Run("default", func(t *testing.T) {
+		l, err := resolveLayout(newCmd(), t.TempDir())
+		require.NoError(t, err)
+		assert.Equal(t, layoutDrugo, l.Name)
+	})
+
+	t.Run("project default", func(t *testing.T) {
+		root := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(root, projectMetaFile), []byte("layout: flat\n"), 0644))
+		l, err := resolveLayout(newCmd(), root)
+		require.NoError(t, err)
+		assert.Equal(t, layoutFlat, l.Name)
+
+		l, err = resolveLayout(newCmd("--layout", layoutHandlerLogicRepo), root)
+		require.NoError(t, err)
+		assert.Equal(t, layoutHandlerLogicRepo, l.Name)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		_, err := resolveLayout(newCmd("--layout", "mvc"), t.TempDir())
+		assert.Equal(t, string(msgLayoutInvalid), errorID(err))
+
+		root := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(root, projectMetaFile), []byte("layout: [\n"), 0644))
+		_, err = resolveLayout(newCmd(), root)
+		assert.Equal(t, string(msgProjectMetaFailed), errorID(err))
+	})
+}
@@ -0,0 +1,250 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/format"
+	"go/parser"
+	"go/scanner"
+	"go/token"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/qq1060656096/drugo/cmd/drugo/internal/tpl"
+	"github.com/spf13/cobra"
+	"go.yaml.in/yaml/v3"
+)
+
+// snippetContext is the number of lines shown before and after the failing line of a lint issue.
+const snippetContext = 2
+
+// lintTemplatesCmd help texts are set by localize.
+var lintTemplatesCmd = &cobra.Command{
+	Use:  "lint-templates",
+	Args: cobra.NoArgs,
+	RunE: runLintTemplates,
+}
+
+func init() {
+	rootCmd.AddCommand(lintTemplatesCmd)
+}
+
+func runLintTemplates(cmd *cobra.Command, args []string) error {
+	issues := LintTemplates()
+	if len(issues) > 0 {
+		for _, issue := range issues {
+			fmt.Fprintln(cmd.ErrOrStderr(), issue)
+		}
+		return newError(msgLintFailed, len(issues))
+	}
+	fmt.Fprint(cmd.OutOrStdout(), msg(msgLintOK, len(tpl.Templates)))
+	return nil
+}
+
+// TemplateIssue describes a template that failed linting.
+type TemplateIssue struct {
+	Template string // registered template name
+	Variant  string // fixture variant, e.g. the layout of a CRUD module template
+	Line     int    // 1-based line in the rendered output (or the template for parse errors), 0 if unknown
+	Column   int    // 1-based column, 0 if unknown
+	Message  string
+	Snippet  string // lines around Line, the failing line marked with ">"
+}
+
+// String formats the issue as "name [variant] line:col: message" followed by the snippet.
+func (i TemplateIssue) String() string {
+	var b strings.Builder
+	b.WriteString(i.Template)
+	if i.Variant != "" {
+		fmt.Fprintf(&b, " [%s]", i.Variant)
+	}
+	if i.Line > 0 {
+		fmt.Fprintf(&b, " %d:%d", i.Line, i.Column)
+	}
+	b.WriteString(": " + i.Message)
+	if i.Snippet != "" {
+		b.WriteString("\n" + i.Snippet)
+	}
+	return b.String()
+}
+
+// lintFixture is the data a template is executed with during linting.
+type lintFixture struct {
+	variant string
+	data    any
+	funcs   template.FuncMap
+}
+
+// Representative fixture values, chosen so that names exercise the title-casing and package paths.
+const (
+	lintProject = "shop"
+	lintModPath = "github.com/acme/shop"
+	lintModule  = "order"
+	lintAPI     = "item"
+)
+
+// lintFixtures returns the fixtures for the named template, selected by its name prefix
+// (see the tpl name constants). CRUD module templates are rendered once per layout,
+// since layouts change package names and imports.
+func lintFixtures(name string) ([]lintFixture, error) {
+	group, file, _ := strings.Cut(name, "/")
+	moduleData := ModuleData{Name: lintModule, NameTitle: toTitle(lintModule), ModPath: lintModPath}
+	switch group {
+	case "project":
+		return []lintFixture{{data: ProjectData{Name: lintProject, ModPath: lintModPath, Version: "v1.0.0"}}}, nil
+	case "worker", "grpc":
+		return []lintFixture{{data: moduleData}}, nil
+	case "module", "module-api":
+		// "data.gorm.go" is the gorm variant of the data layer
+		key, _, _ := strings.Cut(strings.TrimSuffix(file, path.Ext(file)), ".")
+		var fixtures []lintFixture
+		for _, layout := range layouts {
+			layer := layout.Layer(key)
+			funcs := layout.Funcs(lintModPath, lintModule, layer)
+			pkg := layout.PackageName(lintModule, layer)
+			var data any
+			if group == "module" {
+				data = ModuleData{Name: lintModule, NameTitle: toTitle(lintModule), ModPath: lintModPath, Package: pkg}
+			} else {
+				data = ModuleApiData{Name: lintAPI, NameTitle: toTitle(lintAPI), ModuleName: lintModule, ModPath: lintModPath, Package: pkg}
+			}
+			fixtures = append(fixtures, lintFixture{variant: "layout " + layout.Name, data: data, funcs: funcs})
+		}
+		return fixtures, nil
+	default:
+		return nil, fmt.Errorf("no lint fixture for template group %q", group)
+	}
+}
+
+// LintTemplates executes every registered template with its fixtures and validates the output:
+// Go templates must parse with go/parser and format stably with gofmt, YAML templates must unmarshal.
+// It returns the issues found, sorted by template name.
+func LintTemplates() []TemplateIssue {
+	var issues []TemplateIssue
+	for _, name := range tpl.Names() {
+		issues = append(issues, lintTemplate(tpl.Templates[name])...)
+	}
+	return issues
+}
+
+// lintTemplate lints a single template with all of its fixtures.
+func lintTemplate(t tpl.Template) []TemplateIssue {
+	fixtures, err := lintFixtures(t.Name)
+	if err != nil {
+		return []TemplateIssue{{Template: t.Name, Message: err.Error()}}
+	}
+	var issues []TemplateIssue
+	for _, f := range fixtures {
+		if issue := lintRender(t, f); issue != nil {
+			issue.Template, issue.Variant = t.Name, f.variant
+			issues = append(issues, *issue)
+		}
+	}
+	return issues
+}
+
+// lintRender renders t with the fixture f and validates the output according to t.Kind.
+func lintRender(t tpl.Template, f lintFixture) *TemplateIssue {
+	parsed, err := template.New(t.Name).Funcs(f.funcs).Parse(t.Content)
+	if err != nil {
+		return templateIssue(t.Content, err)
+	}
+	var buf bytes.Buffer
+	if err := parsed.Execute(&buf, f.data); err != nil {
+		return templateIssue(t.Content, err)
+	}
+	out := buf.Bytes()
+
+	switch t.Kind {
+	case tpl.KindGo:
+		return lintGo(t.Name, out)
+	case tpl.KindYAML:
+		var v any
+		if err := yaml.Unmarshal(out, &v); err != nil {
+			return newIssue(out, yamlErrorLine(err), 0, err.Error())
+		}
+	}
+	return nil
+}
+
+// lintGo checks that out parses as Go and that gofmt is idempotent on it.
+func lintGo(name string, out []byte) *TemplateIssue {
+	if _, err := parser.ParseFile(token.NewFileSet(), name, out, parser.ParseComments); err != nil {
+		var list scanner.ErrorList
+		if errors.As(err, &list) && len(list) > 0 {
+			return newIssue(out, list[0].Pos.Line, list[0].Pos.Column, list[0].Msg)
+		}
+		return newIssue(out, 0, 0, err.Error())
+	}
+	formatted, err := format.Source(out)
+	if err != nil {
+		return newIssue(out, 0, 0, "gofmt: "+err.Error())
+	}
+	again, err := format.Source(formatted)
+	if err != nil || !bytes.Equal(formatted, again) {
+		return newIssue(formatted, firstDiffLine(formatted, again), 0, "gofmt output is not stable")
+	}
+	return nil
+}
+
+var (
+	templateErrorPos = regexp.MustCompile(`^template: [^:]+:(\d+)(?::(\d+))?:`)
+	yamlErrorPos     = regexp.MustCompile(`line (\d+)`)
+)
+
+// templateIssue converts a text/template parse or execution error into an issue
+// whose snippet points into the template source.
+func templateIssue(content string, err error) *TemplateIssue {
+	line, col := 0, 0
+	if m := templateErrorPos.FindStringSubmatch(err.Error()); m != nil {
+		line, _ = strconv.Atoi(m[1])
+		col, _ = strconv.Atoi(m[2])
+	}
+	return newIssue([]byte(content), line, col, err.Error())
+}
+
+// yamlErrorLine returns the line reported by a yaml error, 0 if there is none.
+func yamlErrorLine(err error) int {
+	if m := yamlErrorPos.FindStringSubmatch(err.Error()); m != nil {
+		line, _ := strconv.Atoi(m[1])
+		return line
+	}
+	return 0
+}
+
+// newIssue returns an issue at line:col of src with a context snippet.
+func newIssue(src []byte, line, col int, message string) *TemplateIssue {
+	return &TemplateIssue{Line: line, Column: col, Message: message, Snippet: snippet(src, line)}
+}
+
+// snippet returns the lines of src around line, each prefixed with its number, the failing line marked with ">".
+func snippet(src []byte, line int) string {
+	lines := strings.Split(string(src), "\n")
+	if line < 1 || line > len(lines) {
+		return ""
+	}
+	var b strings.Builder
+	for i := max(1, line-snippetContext); i <= min(len(lines), line+snippetContext); i++ {
+		marker := " "
+		if i == line {
+			marker = ">"
+		}
+		fmt.Fprintf(&b, "  %s %4d | %s\n", marker, i, lines[i-1])
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// firstDiffLine returns the first 1-based line that differs between a and b.
+func firstDiffLine(a, b []byte) int {
+	la, lb := strings.Split(string(a), "\n"), strings.Split(string(b), "\n")
+	for i := 0; i < len(la) && i < len(lb); i++ {
+		if la[i] != lb[i] {
+			return i + 1
+		}
+	}
+	return min(len(la), len(lb))
+}
@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/qq1060656096/drugo/cmd/drugo/internal/tpl"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestLintTemplates lints every registered template, so template drift fails `go test` as well as CI.
+func TestLintTemplates(t *testing.T) {
+	for _, issue := range LintTemplates() {
+		t.Error(issue)
+	}
+}
+
+// TestLintTemplate_Issues tests that failures report the template, the position and a context snippet.
+func TestLintTemplate_Issues(t *testing.T) {
+	t.Run("go parse error", func(t *testing.T) {
+		issues := lintTemplate(tpl.Template{
+			Name:    tpl.ModuleApiService,
+			Content: "package {{.Package}}\n\nfunc New{{.NameTitle}}() {\n\treturn }}\n}\n",
+			Kind:    tpl.KindGo,
+		})
+		require.Len(t, issues, len(layouts), "module templates are linted once per layout")
+		issue := issues[0]
+		assert.Equal(t, tpl.ModuleApiService, issue.Template)
+		assert.Equal(t, "layout drugo", issue.Variant)
+		assert.Equal(t, 4, issue.Line)
+		assert.Equal(t, 10, issue.Column)
+		assert.Contains(t, issue.Snippet, ">    4 | \treturn }}")
+		assert.Contains(t, issue.Snippet, "     3 | func NewItem() {")
+		assert.Contains(t, issue.String(), "module-api/service.go [layout drugo] 4:10: ")
+	})
+
+	t.Run("yaml error", func(t *testing.T) {
+		issues := lintTemplate(tpl.Template{
+			Name:    tpl.WorkerYaml,
+			Content: "{{.Name}}:\n  interval: 5s\n  bad: [\n",
+			Kind:    tpl.KindYAML,
+		})
+		require.Len(t, issues, 1)
+		assert.Positive(t, issues[0].Line)
+		assert.Contains(t, issues[0].Message, "yaml:")
+		assert.Contains(t, issues[0].Snippet, "order:")
+	})
+
+	t.Run("template error", func(t *testing.T) {
+		issues := lintTemplate(tpl.Template{
+			Name:    tpl.ProjectMain,
+			Content: "package main\n\n// {{.Missing}}\n",
+			Kind:    tpl.KindGo,
+		})
+		require.Len(t, issues, 1)
+		assert.Equal(t, 3, issues[0].Line)
+		assert.Contains(t, issues[0].Message, "Missing")
+		assert.Contains(t, issues[0].Snippet, ">    3 | // {{.Missing}}")
+	})
+
+	t.Run("unknown group", func(t *testing.T) {
+		issues := lintTemplate(tpl.Template{Name: "plugin/x.go", Content: "package x\n", Kind: tpl.KindGo})
+		require.Len(t, issues, 1)
+		assert.Contains(t, issues[0].Message, `no lint fixture for template group "plugin"`)
+	})
+
+	t.Run("other kind is only executed", func(t *testing.T) {
+		assert.Empty(t, lintTemplate(tpl.Template{Name: tpl.ProjectReadme, Content: "# {{.Name}} {", Kind: tpl.KindOther}))
+	})
+}
+
+// TestRunLintTemplates tests the command output and that a failing template makes it return an error.
+func TestRunLintTemplates(t *testing.T) {
+	useLang(t, langEn)
+	var out, errOut bytes.Buffer
+	c := &cobra.Command{}
+	c.SetOut(&out)
+	c.SetErr(&errOut)
+	require.NoError(t, runLintTemplates(c, nil))
+	assert.Contains(t, out.String(), "templates passed")
+
+	orig := tpl.Templates[tpl.ProjectGinYaml]
+	broken := orig
+	broken.Content = "gin:\n  http: [\n"
+	tpl.Templates[tpl.ProjectGinYaml] = broken
+	t.Cleanup(func() { tpl.Templates[tpl.ProjectGinYaml] = orig })
+
+	err := runLintTemplates(c, nil)
+	require.Error(t, err)
+	assert.Equal(t, string(msgLintFailed), errorID(err))
+	assert.Contains(t, errOut.String(), tpl.ProjectGinYaml)
+}
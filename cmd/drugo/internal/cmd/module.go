@@ -1,9 +1,14 @@
 package cmd
 
 import (
+	"bytes"
 	"fmt"
+	"go/format"
+	"io"
 	"os"
+	"path"
 	"path/filepath"
+	"slices"
 	"strings"
 	"text/template"
 	"unicode"
@@ -11,29 +16,61 @@ import (
 	"github.com/qq1060656096/drugo/cmd/drugo/internal/tpl"
 	"github.com/qq1060656096/drugo/pkg/gomod"
 	"github.com/spf13/cobra"
+	"golang.org/x/mod/modfile"
 )
 
-var moduleCmd = &cobra.Command{
-	Use:   "module",
-	Short: "模块管理",
-	Long:  `管理 Drugo 项目中的模块。`,
+// 模块类型
+const (
+	moduleKindAPI    = "api"
+	moduleKindWorker = "worker"
+	moduleKindGrpc   = "grpc"
+)
+
+// Data layer implementations selected by --data.
+const (
+	dataMemory = "memory"
+	dataGorm   = "gorm"
+)
+
+// gormModule is the module the gorm data layer imports.
+const gormModule = "gorm.io/gorm"
+
+var (
+	// Module flags
+	moduleKind   string
+	moduleLayout string
+	moduleData   string
+)
+
+// moduleTemplates maps the layer keys of a CRUD module to their template names.
+var moduleTemplates = map[string]string{
+	layerAPI:     tpl.ModuleAPI,
+	layerBiz:     tpl.ModuleBiz,
+	layerData:    tpl.ModuleData,
+	layerService: tpl.ModuleService,
 }
 
-var moduleNewCmd = &cobra.Command{
-	Use:   "new <模块名称>",
-	Short: "在当前 Drugo 项目中创建新模块",
-	Long: `在当前项目中创建具有标准 CRUD 结构的新模块。
+// moduleDataTemplates maps the --data values to the data layer templates of a CRUD module.
+var moduleDataTemplates = map[string]string{
+	dataMemory: tpl.ModuleData,
+	dataGorm:   tpl.ModuleDataGorm,
+}
 
-模块将在 internal/<模块名称>/ 目录中创建，包含:
-  - api/       HTTP 处理器和路由注册
-  - biz/       业务逻辑和领域实体
-  - data/      数据访问层（仓储实现）
-  - service/   服务层（DTO 和编排）
+// moduleCmd and moduleNewCmd help texts are set by localize.
+var moduleCmd = &cobra.Command{
+	Use: "module",
+}
 
-此命令必须在 Drugo 项目根目录（go.mod 所在位置）运行。`,
+var moduleNewCmd = &cobra.Command{
 	Example: `  drugo module new user
   drugo module new order
-  drugo module new product`,
+  drugo module new product
+  drugo module new consumer --kind worker
+  drugo module new account --kind grpc
+  drugo module new order --layout flat
+  drugo module new product --layout handler-logic-repo
+  drugo module new billing --main cmd/worker
+  drugo module new invoice --data gorm`,
 	Args: cobra.ExactArgs(1),
 	RunE: runNewModule,
 }
@@ -41,6 +78,10 @@ var moduleNewCmd = &cobra.Command{
 func init() {
 	rootCmd.AddCommand(moduleCmd)
 	moduleCmd.AddCommand(moduleNewCmd)
+	moduleNewCmd.Flags().StringVarP(&moduleKind, "kind", "k", moduleKindAPI, "")
+	moduleNewCmd.Flags().StringVarP(&moduleLayout, "layout", "l", layoutDrugo, "")
+	moduleNewCmd.Flags().String("main", "", "")
+	moduleNewCmd.Flags().StringVar(&moduleData, "data", dataMemory, "")
 }
 
 func runNewModule(cmd *cobra.Command, args []string) error {
@@ -50,80 +91,270 @@ func runNewModule(cmd *cobra.Command, args []string) error {
 	if err := validateModuleName(moduleName); err != nil {
 		return err
 	}
+	if err := validateModuleKind(moduleKind); err != nil {
+		return err
+	}
+	if err := validateDataVariant(moduleData); err != nil {
+		return err
+	}
 
 	// Find project root (where go.mod exists)
 	wd, err := os.Getwd()
 	if err != nil {
-		return fmt.Errorf("获取工作目录失败: %w", err)
+		return newError(msgWdFailed, err)
 	}
 
 	projectRoot := gomod.ProjectRoot(wd)
 	if projectRoot == "" {
-		return fmt.Errorf("不在 %s 目录中，请在 Drugo 项目根目录运行", wd)
+		return newError(msgNotInProject, wd)
 	}
 
 	// Get module path from go.mod
 	modPath, err := gomod.ModuleName(projectRoot)
 	if err != nil {
-		return fmt.Errorf("读取 go.mod 失败: %w", err)
+		return newError(msgGoModFailed, err)
+	}
+
+	// Worker and gRPC modules always use the drugo layout
+	layout := layouts[0]
+	if moduleKind == moduleKindAPI {
+		if layout, err = resolveLayout(cmd, projectRoot); err != nil {
+			return err
+		}
+	}
+
+	mainPkg, err := resolveMainPackage(cmd, projectRoot)
+	if err != nil {
+		return err
 	}
 
 	// Check if module already exists
-	modulePath := filepath.Join(projectRoot, "internal", moduleName)
+	modulePath := layout.ModuleDir(projectRoot, moduleName)
 	if _, err := os.Stat(modulePath); err == nil {
-		return fmt.Errorf("模块 %q 已存在于 %s", moduleName, modulePath)
+		return newError(msgModuleExists, moduleName, modulePath)
+	}
+
+	out := cmd.OutOrStdout()
+	fmt.Fprint(out, msg(msgModuleCreating, projectRoot, moduleName))
+
+	if moduleKind == moduleKindWorker {
+		confPath := filepath.Join(projectRoot, "conf", moduleName+".yaml")
+		if _, err := os.Stat(confPath); err == nil {
+			return newError(msgModuleConfExists, confPath)
+		}
+		if err := createWorkerModule(projectRoot, modPath, moduleName); err != nil {
+			// Clean up on failure
+			os.RemoveAll(modulePath)
+			os.Remove(confPath)
+			return newError(msgModuleFailed, err)
+		}
+		fmt.Fprint(out, msg(msgWorkerSuccess, moduleName, modPath, mainPkg))
+		return nil
 	}
 
-	fmt.Printf("正在 %s 中创建模块 %q...\n", projectRoot, moduleName)
+	if moduleKind == moduleKindGrpc {
+		if err := createGrpcModule(projectRoot, modPath, moduleName, moduleData); err != nil {
+			// Clean up on failure
+			os.RemoveAll(modulePath)
+			return newError(msgModuleFailed, err)
+		}
+		fmt.Fprint(out, msg(msgGrpcSuccess, moduleName, modPath, mainPkg))
+		printDataHint(out, projectRoot, moduleName, moduleData)
+		return nil
+	}
 
 	// Create module structure
-	if err := createModule(projectRoot, modPath, moduleName); err != nil {
+	if err := createModule(projectRoot, modPath, moduleName, layout, moduleData); err != nil {
 		// Clean up on failure
 		os.RemoveAll(modulePath)
-		return fmt.Errorf("创建模块失败: %w", err)
+		return newError(msgModuleFailed, err)
+	}
+	defer printDataHint(out, projectRoot, moduleName, moduleData)
+
+	if layout.Name == layoutDrugo {
+		fmt.Fprint(out, msg(msgModuleSuccess, moduleName, modPath, mainPkg))
+		return nil
+	}
+
+	var files strings.Builder
+	for _, layer := range layout.Layers {
+		rel, _ := filepath.Rel(projectRoot, layout.FilePath(projectRoot, moduleName, layer, moduleName))
+		fmt.Fprintf(&files, "  %s\n", filepath.ToSlash(rel))
 	}
+	apiImport := layout.ImportPath(modPath, moduleName, layout.Layer(layerAPI))
+	fmt.Fprint(out, msg(msgModuleLayoutOK, moduleName, layout.Name, files.String(), apiImport, mainPkg))
+
+	return nil
+}
+
+func validateModuleName(name string) error {
+	return validateName(name, msg(msgFieldModule))
+}
 
-	fmt.Printf(`
-模块 %q 创建成功！
+// defaultMainPackage is the main package directory of scaffolded projects,
+// used in the next-step hints when the project has no main package yet.
+const defaultMainPackage = "cmd/app"
+
+// resolveMainPackage returns the directory, relative to projectRoot, of the main
+// package that should import a new module. The --main flag wins and must name a
+// detected main package; otherwise the single detected main package is used.
+// Several candidates without --main are an error, since guessing would point
+// users at the wrong entrypoint.
+func resolveMainPackage(cmd *cobra.Command, projectRoot string) (string, error) {
+	pkgs, err := gomod.MainPackages(projectRoot, gomod.WithCmdFirst())
+	if err != nil {
+		return "", newError(msgMainScanFailed, err)
+	}
+	var candidates []string
+	for _, p := range pkgs {
+		if !p.HasMainFunc {
+			continue
+		}
+		rel, err := filepath.Rel(projectRoot, p.Dir)
+		if err != nil {
+			return "", newError(msgMainScanFailed, err)
+		}
+		candidates = append(candidates, filepath.ToSlash(rel))
+	}
 
-结构:
-  internal/%s/
-  ├── api/
-  │   └── %s.go      # HTTP 处理器和路由
-  ├── biz/
-  │   └── %s.go      # 业务逻辑
-  ├── data/
-  │   └── %s.go      # 数据仓储
-  └── service/
-      └── %s.go      # 服务层
+	if f := cmd.Flags().Lookup("main"); f != nil && f.Changed {
+		want := path.Clean(filepath.ToSlash(f.Value.String()))
+		if slices.Contains(candidates, want) {
+			return want, nil
+		}
+		return "", newError(msgMainInvalid, f.Value.String(), strings.Join(candidates, ", "))
+	}
+	switch len(candidates) {
+	case 0:
+		return defaultMainPackage, nil
+	case 1:
+		return candidates[0], nil
+	default:
+		return "", newError(msgMainAmbiguous, strings.Join(candidates, ", "))
+	}
+}
 
-下一步:
-  1. 在 cmd/app/main.go 中导入模块:
-     import _ "%s/internal/%s/api"
-  2. 根据需要自定义生成的代码。
+func validateModuleKind(kind string) error {
+	switch kind {
+	case moduleKindAPI, moduleKindWorker, moduleKindGrpc:
+		return nil
+	default:
+		return newError(msgModuleKindInvalid, kind, strings.Join([]string{moduleKindAPI, moduleKindWorker, moduleKindGrpc}, ", "))
+	}
+}
 
-`, moduleName, moduleName, moduleName, moduleName, moduleName, moduleName, modPath, moduleName)
+// validateDataVariant checks the --data value.
+func validateDataVariant(data string) error {
+	if _, ok := moduleDataTemplates[data]; ok {
+		return nil
+	}
+	return newError(msgDataInvalid, data, strings.Join([]string{dataMemory, dataGorm}, ", "))
+}
+
+// layerTemplate returns the template name of layer, using dataTemplates[data] for the data layer.
+func layerTemplate(templates, dataTemplates map[string]string, key, data string) string {
+	if key == layerData {
+		return dataTemplates[data]
+	}
+	return templates[key]
+}
+
+// printDataHint prints the setup the gorm data layer of entity needs, including the
+// gorm.io/gorm requirement when go.mod in projectRoot does not list it yet.
+func printDataHint(out io.Writer, projectRoot, entity, data string) {
+	if data != dataGorm {
+		return
+	}
+	fmt.Fprint(out, msg(msgDataGorm, toTitle(entity)))
+	if !requiresModule(projectRoot, gormModule) {
+		fmt.Fprint(out, msg(msgDataGormRequire, gormModule))
+	}
+}
+
+// requiresModule reports whether go.mod in projectRoot requires modulePath, directly or indirectly.
+func requiresModule(projectRoot, modulePath string) bool {
+	data, err := os.ReadFile(filepath.Join(projectRoot, "go.mod"))
+	if err != nil {
+		return false
+	}
+	f, err := modfile.ParseLax("go.mod", data, nil)
+	if err != nil {
+		return false
+	}
+	for _, r := range f.Require {
+		if r.Mod.Path == modulePath {
+			return true
+		}
+	}
+	return false
+}
+
+// createModule creates a CRUD module placed according to layout, with the data layer selected by dataVariant.
+func createModule(projectRoot, modPath, moduleName string, layout Layout, dataVariant string) error {
+	for _, dir := range layout.Dirs(projectRoot, moduleName) {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return newError(msgDirFailed, dir, err)
+		}
+	}
+
+	for _, layer := range layout.Layers {
+		fileData := ModuleData{
+			Name:      moduleName,
+			NameTitle: toTitle(moduleName),
+			ModPath:   modPath,
+			Package:   layout.PackageName(moduleName, layer),
+		}
+		path := layout.FilePath(projectRoot, moduleName, layer, moduleName)
+		funcs := layout.Funcs(modPath, moduleName, layer)
+		name := layerTemplate(moduleTemplates, moduleDataTemplates, layer.Key, dataVariant)
+		if err := createModuleFileFromTemplate(path, tpl.Get(name), fileData, funcs); err != nil {
+			return err
+		}
+	}
 
 	return nil
 }
 
-func validateModuleName(name string) error {
-	if name == "" {
-		return fmt.Errorf("模块名称不能为空")
+func createWorkerModule(projectRoot, modPath, moduleName string) error {
+	data := ModuleData{
+		Name:      moduleName,
+		NameTitle: toTitle(moduleName),
+		ModPath:   modPath,
 	}
-	if strings.ContainsAny(name, " \t\n/\\.-") {
-		return fmt.Errorf("模块名称不能包含空格、点号、连字符或路径分隔符")
+
+	basePath := filepath.Join(projectRoot, "internal", moduleName)
+
+	// Create directories
+	dirs := []string{
+		filepath.Join(basePath, "worker"),
+		filepath.Join(basePath, "biz"),
+		filepath.Join(projectRoot, "conf"),
 	}
-	// Check if starts with a letter
-	for _, r := range name {
-		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
-			return fmt.Errorf("模块名称只能包含字母和数字")
+
+	for _, dir := range dirs {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return newError(msgDirFailed, dir, err)
 		}
 	}
+
+	// Create files from templates
+	files := map[string]string{
+		filepath.Join(basePath, "worker", moduleName+".go"):    tpl.Get(tpl.WorkerWorker),
+		filepath.Join(basePath, "biz", moduleName+".go"):       tpl.Get(tpl.WorkerBiz),
+		filepath.Join(projectRoot, "conf", moduleName+".yaml"): tpl.Get(tpl.WorkerYaml),
+	}
+
+	for path, tplContent := range files {
+		if err := createModuleFileFromTemplate(path, tplContent, data, nil); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
-func createModule(projectRoot, modPath, moduleName string) error {
+// createGrpcModule creates a gRPC module, with the data layer selected by dataVariant.
+func createGrpcModule(projectRoot, modPath, moduleName, dataVariant string) error {
 	data := ModuleData{
 		Name:      moduleName,
 		NameTitle: toTitle(moduleName),
@@ -134,28 +365,40 @@ func createModule(projectRoot, modPath, moduleName string) error {
 
 	// Create directories
 	dirs := []string{
-		filepath.Join(basePath, "api"),
 		filepath.Join(basePath, "biz"),
 		filepath.Join(basePath, "data"),
-		filepath.Join(basePath, "service"),
+		filepath.Join(basePath, "proto"),
+		filepath.Join(basePath, "grpc"),
 	}
 
 	for _, dir := range dirs {
 		if err := os.MkdirAll(dir, 0755); err != nil {
-			return fmt.Errorf("创建目录 %q 失败: %w", dir, err)
+			return newError(msgDirFailed, dir, err)
+		}
+	}
+
+	// The biz and data layers are shared with the drugo layout of CRUD modules
+	layout := layouts[0]
+	for _, key := range []string{layerBiz, layerData} {
+		layer := layout.Layer(key)
+		fileData := data
+		fileData.Package = layout.PackageName(moduleName, layer)
+		path := layout.FilePath(projectRoot, moduleName, layer, moduleName)
+		name := layerTemplate(moduleTemplates, moduleDataTemplates, key, dataVariant)
+		if err := createModuleFileFromTemplate(path, tpl.Get(name), fileData, layout.Funcs(modPath, moduleName, layer)); err != nil {
+			return err
 		}
 	}
 
 	// Create files from templates
 	files := map[string]string{
-		filepath.Join(basePath, "api", moduleName+".go"):     tpl.ModuleAPITpl,
-		filepath.Join(basePath, "biz", moduleName+".go"):     tpl.ModuleBizTpl,
-		filepath.Join(basePath, "data", moduleName+".go"):    tpl.ModuleDataTpl,
-		filepath.Join(basePath, "service", moduleName+".go"): tpl.ModuleServiceTpl,
+		filepath.Join(basePath, "proto", moduleName+".proto"): tpl.Get(tpl.GrpcProto),
+		filepath.Join(basePath, "proto", "generate.go"):       tpl.Get(tpl.GrpcGenerate),
+		filepath.Join(basePath, "grpc", moduleName+".go"):     tpl.Get(tpl.GrpcServer),
 	}
 
 	for path, tplContent := range files {
-		if err := createModuleFileFromTemplate(path, tplContent, data); err != nil {
+		if err := createModuleFileFromTemplate(path, tplContent, data, nil); err != nil {
 			return err
 		}
 	}
@@ -163,20 +406,28 @@ func createModule(projectRoot, modPath, moduleName string) error {
 	return nil
 }
 
-func createModuleFileFromTemplate(path, tplContent string, data ModuleData) error {
-	f, err := os.Create(path)
+// createModuleFileFromTemplate renders tplContent with data and funcs into path.
+// Generated Go files are gofmt'ed, since layouts may drop import lines or qualifiers.
+func createModuleFileFromTemplate(path, tplContent string, data any, funcs template.FuncMap) error {
+	t, err := template.New(filepath.Base(path)).Funcs(funcs).Parse(tplContent)
 	if err != nil {
-		return fmt.Errorf("创建文件 %q 失败: %w", path, err)
+		return newError(msgTplParse, path, err)
 	}
-	defer f.Close()
 
-	tpl, err := template.New(filepath.Base(path)).Parse(tplContent)
-	if err != nil {
-		return fmt.Errorf("解析模板 %q 失败: %w", path, err)
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, data); err != nil {
+		return newError(msgTplExecFailed, path, err)
+	}
+
+	content := buf.Bytes()
+	if filepath.Ext(path) == ".go" {
+		if content, err = format.Source(content); err != nil {
+			return newError(msgTplExecFailed, path, err)
+		}
 	}
 
-	if err := tpl.Execute(f, data); err != nil {
-		return fmt.Errorf("执行模板 %q 失败: %w", path, err)
+	if err := os.WriteFile(path, content, 0644); err != nil {
+		return newError(msgFileFailed, path, err)
 	}
 
 	return nil
@@ -187,6 +438,7 @@ type ModuleData struct {
 	Name      string // lowercase module name (e.g., "user")
 	NameTitle string // title case module name (e.g., "User")
 	ModPath   string // go module path (e.g., "github.com/myorg/myapp")
+	Package   string // package name of the generated file (e.g., "biz")
 }
 
 // toTitle converts a string to title case (first letter uppercase).
@@ -0,0 +1,190 @@
+package cmd
+
+import (
+	"bytes"
+	"go/parser"
+	"go/token"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestCreateWorkerModule 测试生成 worker 模块并校验生成的 Go 代码可以被解析
+func TestCreateWorkerModule(t *testing.T) {
+	root := t.TempDir()
+
+	require.NoError(t, createWorkerModule(root, "github.com/acme/app", "consumer"))
+
+	goFiles := []string{
+		filepath.Join(root, "internal", "consumer", "worker", "consumer.go"),
+		filepath.Join(root, "internal", "consumer", "biz", "consumer.go"),
+	}
+	fset := token.NewFileSet()
+	for _, path := range goFiles {
+		f, err := parser.ParseFile(fset, path, nil, parser.AllErrors)
+		require.NoError(t, err, path)
+		assert.NotNil(t, f)
+	}
+
+	conf, err := os.ReadFile(filepath.Join(root, "conf", "consumer.yaml"))
+	require.NoError(t, err)
+	assert.Contains(t, string(conf), "consumer:")
+}
+
+// TestCreateGrpcModule 测试生成 grpc 模块：生成的 Go 代码可以被解析，
+// 且在未生成 protobuf 代码时项目仍能通过 go vet（服务实现被 protoc 构建约束排除）
+func TestCreateGrpcModule(t *testing.T) {
+	root := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(root, "go.mod"), []byte("module github.com/acme/app\n\ngo 1.25\n"), 0644))
+
+	require.NoError(t, createGrpcModule(root, "github.com/acme/app", "account", dataMemory))
+
+	base := filepath.Join(root, "internal", "account")
+	goFiles := []string{
+		filepath.Join(base, "biz", "account.go"),
+		filepath.Join(base, "data", "account.go"),
+		filepath.Join(base, "proto", "generate.go"),
+		filepath.Join(base, "grpc", "account.go"),
+	}
+	fset := token.NewFileSet()
+	for _, path := range goFiles {
+		f, err := parser.ParseFile(fset, path, nil, parser.AllErrors)
+		require.NoError(t, err, path)
+		assert.NotNil(t, f)
+	}
+
+	proto, err := os.ReadFile(filepath.Join(base, "proto", "account.proto"))
+	require.NoError(t, err)
+	assert.Contains(t, string(proto), `option go_package = "github.com/acme/app/internal/account/proto;accountpb";`)
+	assert.Contains(t, string(proto), "service AccountService {")
+
+	if testing.Short() {
+		t.Skip("skipping go vet in short mode")
+	}
+	cmd := exec.Command("go", "vet", "./...")
+	cmd.Dir = root
+	cmd.Env = append(os.Environ(), "GOFLAGS=-mod=mod", "GOWORK=off")
+	out, err := cmd.CombinedOutput()
+	require.NoError(t, err, string(out))
+}
+
+// TestCreateModule_GormData 测试 --data gorm 生成的数据层：每种目录结构下都可以被解析，
+// 实现同一个 biz 仓库接口，并包含迁移、未找到错误的转换与分页查询
+func TestCreateModule_GormData(t *testing.T) {
+	for _, layout := range layouts {
+		t.Run(layout.Name, func(t *testing.T) {
+			root := t.TempDir()
+			modPath := "github.com/acme/app"
+			require.NoError(t, createModule(root, modPath, "user", layout, dataGorm))
+			require.NoError(t, createModuleApi(io.Discard, root, modPath, "user", "address", layout, dataGorm))
+
+			data := layout.Layer(layerData)
+			for _, entity := range []string{"user", "address"} {
+				path := layout.FilePath(root, "user", data, entity)
+				f, err := parser.ParseFile(token.NewFileSet(), path, nil, parser.AllErrors)
+				require.NoError(t, err, path)
+				var imports []string
+				for _, spec := range f.Imports {
+					imports = append(imports, spec.Path.Value)
+				}
+				assert.Contains(t, imports, `"gorm.io/gorm"`)
+
+				src, err := os.ReadFile(path)
+				require.NoError(t, err)
+				title := toTitle(entity)
+				assert.Contains(t, string(src), "func New"+title+"Repo() ")
+				assert.Contains(t, string(src), "func Migrate"+title+"(ctx context.Context) error {")
+				assert.Contains(t, string(src), "func (r *"+entity+"Repo) Migrate(ctx context.Context) error {")
+				assert.Contains(t, string(src), "Err"+title+"NotFound")
+				assert.Contains(t, string(src), "errors.Is(err, gorm.ErrRecordNotFound)")
+				assert.Contains(t, string(src), ".Offset((page - 1) * pageSize).Limit(pageSize)")
+				assert.Contains(t, string(src), ".Count(&total)")
+				assert.Contains(t, string(src), `gorm:"column:name;`)
+			}
+		})
+	}
+}
+
+// TestPrintDataHint 测试 gorm 数据层的提示：go.mod 未依赖 gorm.io/gorm 时提示添加依赖
+func TestPrintDataHint(t *testing.T) {
+	useLang(t, langEn)
+	root := t.TempDir()
+	goMod := filepath.Join(root, "go.mod")
+	require.NoError(t, os.WriteFile(goMod, []byte("module github.com/acme/app\n\ngo 1.25\n"), 0644))
+
+	var out bytes.Buffer
+	printDataHint(&out, root, "user", dataMemory)
+	assert.Empty(t, out.String())
+
+	printDataHint(&out, root, "user", dataGorm)
+	assert.Contains(t, out.String(), "MigrateUser(ctx)")
+	assert.Contains(t, out.String(), "go.mod does not require gorm.io/gorm yet")
+
+	require.NoError(t, os.WriteFile(goMod, []byte("module github.com/acme/app\n\ngo 1.25\n\nrequire gorm.io/gorm v1.31.1 // indirect\n"), 0644))
+	out.Reset()
+	printDataHint(&out, root, "user", dataGorm)
+	assert.NotContains(t, out.String(), "does not require")
+
+	assert.NoError(t, validateDataVariant(dataGorm))
+	assert.Equal(t, string(msgDataInvalid), errorID(validateDataVariant("sqlx")))
+}
+
+// TestValidateModuleKind 测试模块类型校验
+func TestValidateModuleKind(t *testing.T) {
+	assert.NoError(t, validateModuleKind(moduleKindAPI))
+	assert.NoError(t, validateModuleKind(moduleKindWorker))
+	assert.NoError(t, validateModuleKind(moduleKindGrpc))
+	assert.Error(t, validateModuleKind("grpc2"))
+	assert.Error(t, validateModuleKind(""))
+}
+
+// TestResolveMainPackage 测试导入模块的 main 包的选择：唯一结果、--main 与多个 main 包时报错
+func TestResolveMainPackage(t *testing.T) {
+	newCmd := func(args ...string) *cobra.Command {
+		c := &cobra.Command{}
+		c.Flags().String("main", "", "")
+		require.NoError(t, c.Flags().Parse(args))
+		return c
+	}
+	newProject := func(t *testing.T, mains ...string) string {
+		root := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(root, "go.mod"), []byte("module github.com/acme/app\n"), 0644))
+		for _, dir := range mains {
+			require.NoError(t, os.MkdirAll(filepath.Join(root, dir), 0755))
+			require.NoError(t, os.WriteFile(filepath.Join(root, dir, "main.go"), []byte("package main\n\nfunc main() {}\n"), 0644))
+		}
+		return root
+	}
+
+	t.Run("没有 main 包时使用默认目录", func(t *testing.T) {
+		main, err := resolveMainPackage(newCmd(), newProject(t))
+		require.NoError(t, err)
+		assert.Equal(t, defaultMainPackage, main)
+	})
+
+	t.Run("唯一的 main 包", func(t *testing.T) {
+		main, err := resolveMainPackage(newCmd(), newProject(t, "cmd/server"))
+		require.NoError(t, err)
+		assert.Equal(t, "cmd/server", main)
+	})
+
+	t.Run("多个 main 包", func(t *testing.T) {
+		root := newProject(t, "cmd/api", "cmd/worker")
+		_, err := resolveMainPackage(newCmd(), root)
+		assert.Equal(t, string(msgMainAmbiguous), errorID(err))
+		assert.Contains(t, err.Error(), "cmd/api, cmd/worker")
+
+		main, err := resolveMainPackage(newCmd("--main", "./cmd/worker/"), root)
+		require.NoError(t, err)
+		assert.Equal(t, "cmd/worker", main)
+
+		_, err = resolveMainPackage(newCmd("--main", "cmd/app"), root)
+		assert.Equal(t, string(msgMainInvalid), errorID(err))
+	})
+}
@@ -1,6 +1,8 @@
 package cmd
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -13,49 +15,30 @@ import (
 
 var (
 	// Project flags
-	projectModPath string
+	projectModPath       string
+	projectVerify        bool
+	projectKeepOnFailure bool
+	projectReplaceLocal  string
+	projectTidy          bool
 )
 
+// newCmd help texts are set by localize.
 var newCmd = &cobra.Command{
-	Use:   "new <项目名称>",
-	Short: "创建一个新的 Drugo 项目",
-	Long: `创建一个带有标准目录结构的新 Drugo 项目。
-
-项目将在名为 <项目名称> 的新目录中创建。
-如果未指定 --mod，将使用 "github.com/<项目名称>" 作为模块路径。
-
-目录结构:
-  <项目名称>/
-  ├── cmd/
-  │   └── app/
-  │       └── main.go
-  ├── conf/
-  │   ├── db.yaml
-  │   ├── gin.yaml
-  │   ├── i18n.yaml
-  │   ├── log.yaml
-  │   └── redis.yaml
-  ├── internal/
-  ├── locales/
-  │   ├── en/
-  │   │   └── app.en.yml
-  │   └── zh/
-  │       └── app.zh.yml
-  ├── runtime/
-  │   └── logs/
-  ├── go.mod
-  ├── Makefile
-  ├── .gitignore
-  └── README.md`,
 	Example: `  drugo new myapp
-  drugo new myapp --mod github.com/myorg/myapp`,
+  drugo new myapp --mod github.com/myorg/myapp
+  drugo new myapp --verify --replace-local ../drugo
+  drugo new myapp --offline --tidy --replace-local ../drugo`,
 	Args: cobra.ExactArgs(1),
 	RunE: runNew,
 }
 
 func init() {
 	rootCmd.AddCommand(newCmd)
-	newCmd.Flags().StringVarP(&projectModPath, "mod", "m", "", "go 模块路径 (默认: github.com/<项目名称>)")
+	newCmd.Flags().StringVarP(&projectModPath, "mod", "m", "", "")
+	newCmd.Flags().BoolVar(&projectVerify, "verify", false, "")
+	newCmd.Flags().BoolVar(&projectKeepOnFailure, "keep-on-failure", false, "")
+	newCmd.Flags().StringVar(&projectReplaceLocal, "replace-local", "", "")
+	newCmd.Flags().BoolVar(&projectTidy, "tidy", false, "")
 }
 
 func runNew(cmd *cobra.Command, args []string) error {
@@ -69,42 +52,110 @@ func runNew(cmd *cobra.Command, args []string) error {
 	// Set module path if not specified
 	modPath := projectModPath
 	if modPath == "" {
-		modPath = fmt.Sprintf("%s", projectName)
+		modPath = projectName
 	}
 
 	// Check if directory exists
 	if _, err := os.Stat(projectName); err == nil {
-		return fmt.Errorf("目录 %q 已存在", projectName)
+		return newError(msgProjectExists, projectName)
 	}
 
-	fmt.Printf("正在创建项目 %q，模块路径为 %q...\n", projectName, modPath)
-	version := getVersion()
+	out := cmd.OutOrStdout()
+	offline := offlineMode()
+	if offline {
+		fmt.Fprint(out, msg(msgNewOffline))
+	}
+	fmt.Fprint(out, msg(msgNewCreating, projectName, modPath))
+	version := frameworkVersion()
 	// Create project structure
 	if err := createProject(projectName, modPath, version); err != nil {
 		// Clean up on failure
 		os.RemoveAll(projectName)
-		return fmt.Errorf("创建项目失败: %w", err)
+		return newError(msgProjectFailed, err)
+	}
+	if projectReplaceLocal != "" {
+		local, err := replaceFramework(projectName, projectReplaceLocal)
+		if err != nil {
+			os.RemoveAll(projectName)
+			return newError(msgProjectFailed, err)
+		}
+		fmt.Fprint(out, msg(msgNewReplaced, frameworkModule, local))
+	} else if offline && version == unpinnedVersion {
+		fmt.Fprint(out, msg(msgNewUnpinned, frameworkModule, version))
 	}
 
-	fmt.Printf(`
-项目 %q 创建成功！
-
-下一步:
-  cd %s
-  go mod tidy
-  make run
+	skipped, err := runNewSteps(cmd, projectName, offline)
+	if err != nil {
+		return err
+	}
 
-`, projectName, projectName)
+	fmt.Fprint(out, msg(msgNewSuccess, projectName))
+	if offline {
+		if len(skipped) > 0 {
+			fmt.Fprint(out, msg(msgNewOfflineSkip, strings.Join(skipped, ", ")))
+		} else {
+			fmt.Fprint(out, msg(msgNewOfflineNone))
+		}
+	}
 
 	return nil
 }
 
+// runNewSteps runs the optional go command steps (--tidy, --verify) for a new project.
+// In offline mode a step that fails because dependencies are unavailable is reported as a
+// warning and returned in skipped instead of failing the command.
+func runNewSteps(cmd *cobra.Command, projectName string, offline bool) (skipped []string, err error) {
+	out := cmd.OutOrStdout()
+	steps := []struct {
+		enabled bool
+		name    string
+		args    []string
+		running msgID
+		done    msgID
+		failed  msgID
+	}{
+		{projectTidy, "go mod tidy", []string{"mod", "tidy"}, msgNewTidying, "", msgNewTidyFailed},
+		{projectVerify, "go vet ./...", nil, msgNewVerifying, msgNewVerified, msgNewVerifyFailed},
+	}
+	for _, step := range steps {
+		if !step.enabled {
+			continue
+		}
+		fmt.Fprint(out, msg(step.running, projectName))
+		opts := VerifyOptions{ReplaceLocal: projectReplaceLocal, Args: step.args, Output: cmd.ErrOrStderr(), Offline: offline}
+		err := VerifyProject(cmdContext(cmd), projectName, opts)
+		switch {
+		case err == nil:
+			if step.done != "" {
+				fmt.Fprint(out, msg(step.done, projectName))
+			}
+		case errors.Is(err, ErrModulesUnavailable):
+			fmt.Fprint(cmd.ErrOrStderr(), msg(msgNewOfflineWarn, step.name, err))
+			skipped = append(skipped, step.name)
+		default:
+			if !projectKeepOnFailure {
+				os.RemoveAll(projectName)
+			}
+			return nil, newError(step.failed, projectName, err)
+		}
+	}
+	return skipped, nil
+}
+
+// cmdContext returns the command context, or context.Background when the command was not executed through cobra.
+func cmdContext(cmd *cobra.Command) context.Context {
+	if ctx := cmd.Context(); ctx != nil {
+		return ctx
+	}
+	return context.Background()
+}
+
 func validateProjectName(name string) error {
 	if name == "" {
-		return fmt.Errorf("项目名称不能为空")
+		return newError(msgProjectEmpty)
 	}
 	if strings.ContainsAny(name, " \t\n/\\") {
-		return fmt.Errorf("项目名称不能包含空格或路径分隔符")
+		return newError(msgProjectChars)
 	}
 	return nil
 }
@@ -129,28 +180,28 @@ func createProject(name, modPath, version string) error {
 
 	for _, dir := range dirs {
 		if err := os.MkdirAll(dir, 0755); err != nil {
-			return fmt.Errorf("创建目录 %q 失败: %w", dir, err)
+			return newError(msgDirFailed, dir, err)
 		}
 	}
 
 	// Create files from templates
 	files := map[string]string{
-		filepath.Join(name, "cmd", "app", "main.go"):       tpl.MainGoTpl,
-		filepath.Join(name, "conf", "app.yaml"):            tpl.AppYamlTpl,
-		filepath.Join(name, "conf", "gin.yaml"):            tpl.GinYamlTpl,
-		filepath.Join(name, "conf", "i18n.yaml"):           tpl.I18nYamlTpl,
-		filepath.Join(name, "conf", "log.yaml"):            tpl.LogYamlTpl,
-		filepath.Join(name, "conf", "db.yaml"):             tpl.DbYamlTpl,
-		filepath.Join(name, "conf", "redis.yaml"):          tpl.RedisYamlTpl,
-		filepath.Join(name, "configs", "app.go"):           tpl.ConfigsAppConfigTpl,
-		filepath.Join(name, "go.mod"):                      tpl.GoModTpl,
-		filepath.Join(name, "Makefile"):                    tpl.MakefileTpl,
-		filepath.Join(name, ".gitignore"):                  tpl.GitignoreTpl,
-		filepath.Join(name, "README.md"):                   tpl.ReadmeTpl,
-		filepath.Join(name, ".air.toml"):                   tpl.AirTomlTpl,
+		filepath.Join(name, "cmd", "app", "main.go"):       tpl.Get(tpl.ProjectMain),
+		filepath.Join(name, "conf", "app.yaml"):            tpl.Get(tpl.ProjectAppYaml),
+		filepath.Join(name, "conf", "gin.yaml"):            tpl.Get(tpl.ProjectGinYaml),
+		filepath.Join(name, "conf", "i18n.yaml"):           tpl.Get(tpl.ProjectI18nYaml),
+		filepath.Join(name, "conf", "log.yaml"):            tpl.Get(tpl.ProjectLogYaml),
+		filepath.Join(name, "conf", "db.yaml"):             tpl.Get(tpl.ProjectDbYaml),
+		filepath.Join(name, "conf", "redis.yaml"):          tpl.Get(tpl.ProjectRedisYaml),
+		filepath.Join(name, "configs", "app.go"):           tpl.Get(tpl.ProjectAppConfig),
+		filepath.Join(name, "go.mod"):                      tpl.Get(tpl.ProjectGoMod),
+		filepath.Join(name, "Makefile"):                    tpl.Get(tpl.ProjectMakefile),
+		filepath.Join(name, ".gitignore"):                  tpl.Get(tpl.ProjectGitignore),
+		filepath.Join(name, "README.md"):                   tpl.Get(tpl.ProjectReadme),
+		filepath.Join(name, ".air.toml"):                   tpl.Get(tpl.ProjectAirToml),
 		filepath.Join(name, "runtime", "logs", ".gitkeep"): "",
-		filepath.Join(name, "locales", "en", "app.en.yml"): tpl.LocaleEnYmlTpl,
-		filepath.Join(name, "locales", "zh", "app.zh.yml"): tpl.LocaleZhYmlTpl,
+		filepath.Join(name, "locales", "en", "app.en.yml"): tpl.Get(tpl.ProjectLocaleEn),
+		filepath.Join(name, "locales", "zh", "app.zh.yml"): tpl.Get(tpl.ProjectLocaleZh),
 	}
 
 	for path, tplContent := range files {
@@ -165,7 +216,7 @@ func createProject(name, modPath, version string) error {
 func createFileFromTemplate(path, tplContent string, data any) error {
 	f, err := os.Create(path)
 	if err != nil {
-		return fmt.Errorf("创建文件 %q 失败: %w", path, err)
+		return newError(msgFileFailed, path, err)
 	}
 	defer f.Close()
 
@@ -175,11 +226,11 @@ func createFileFromTemplate(path, tplContent string, data any) error {
 
 	tpl, err := template.New(filepath.Base(path)).Parse(tplContent)
 	if err != nil {
-		return fmt.Errorf("解析模板 %q 失败: %w", path, err)
+		return newError(msgTplParse, path, err)
 	}
 
 	if err := tpl.Execute(f, data); err != nil {
-		return fmt.Errorf("执行模板 %q 失败: %w", path, err)
+		return newError(msgTplExecFailed, path, err)
 	}
 
 	return nil
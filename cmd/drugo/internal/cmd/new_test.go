@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var update = flag.Bool("update", false, "update golden files")
+
+// TestRunNew_Golden compares the success output of `drugo new` with golden files in both languages.
+func TestRunNew_Golden(t *testing.T) {
+	for _, l := range []string{langZh, langEn} {
+		t.Run(l, func(t *testing.T) {
+			useLang(t, l)
+			golden, err := filepath.Abs(filepath.Join("testdata", "new_success."+l+".golden"))
+			require.NoError(t, err)
+			t.Chdir(t.TempDir())
+
+			var out bytes.Buffer
+			c := &cobra.Command{}
+			c.SetOut(&out)
+			require.NoError(t, runNew(c, []string{"myapp"}))
+			assert.FileExists(t, filepath.Join("myapp", "cmd", "app", "main.go"))
+
+			if *update {
+				require.NoError(t, os.WriteFile(golden, out.Bytes(), 0644))
+			}
+			want, err := os.ReadFile(golden)
+			require.NoError(t, err)
+			assert.Equal(t, string(want), out.String())
+		})
+	}
+}
+
+// TestRunNew_Exists tests the error returned when the project directory already exists.
+func TestRunNew_Exists(t *testing.T) {
+	useLang(t, langEn)
+	t.Chdir(t.TempDir())
+	require.NoError(t, os.Mkdir("myapp", 0755))
+
+	err := runNew(&cobra.Command{}, []string{"myapp"})
+	assert.Equal(t, string(msgProjectExists), errorID(err))
+	assert.Equal(t, `[project.exists] directory "myapp" already exists`, err.Error())
+}
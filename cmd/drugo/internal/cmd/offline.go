@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"golang.org/x/mod/modfile"
+	"golang.org/x/mod/semver"
+)
+
+// offlineEnvVar enables offline mode like --offline.
+const offlineEnvVar = "DRUGO_OFFLINE"
+
+// unpinnedVersion is the framework version written to go.mod when the CLI was not built
+// from a released module version. It only resolves together with --replace-local.
+const unpinnedVersion = "v0.0.0"
+
+// offlineFlag is the global --offline flag.
+var offlineFlag bool
+
+// offlineMode reports whether the CLI must not access the network: --offline, or a true DRUGO_OFFLINE.
+func offlineMode() bool {
+	if offlineFlag {
+		return true
+	}
+	on, _ := strconv.ParseBool(os.Getenv(offlineEnvVar))
+	return on
+}
+
+// frameworkVersion returns the drugo version pinned in generated go.mod files: the CLI's own
+// module version from its build info, so the project uses the same framework release without
+// asking the module proxy for the latest one. Development builds fall back to unpinnedVersion.
+func frameworkVersion() string {
+	v := getVersion()
+	if !semver.IsValid(v) {
+		return unpinnedVersion
+	}
+	// build metadata such as +dirty is not a valid module version
+	return v[:len(v)-len(semver.Build(v))]
+}
+
+// replaceFramework adds a replace directive to the go.mod in dir pointing the drugo module
+// at the local checkout, and returns the absolute path of the checkout.
+func replaceFramework(dir, local string) (string, error) {
+	local, err := filepath.Abs(local)
+	if err != nil {
+		return "", err
+	}
+	path := filepath.Join(dir, "go.mod")
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	f, err := modfile.Parse(path, content, nil)
+	if err != nil {
+		return "", fmt.Errorf("parse %s: %w", path, err)
+	}
+	if err := f.AddReplace(frameworkModule, "", local, ""); err != nil {
+		return "", err
+	}
+	f.Cleanup()
+	content, err = f.Format()
+	if err != nil {
+		return "", err
+	}
+	return local, os.WriteFile(path, content, 0644)
+}
@@ -0,0 +1,119 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/mod/modfile"
+)
+
+// hermeticGoEnv points the go command at an empty GOPATH and module cache with GOPROXY=off,
+// so nothing can be resolved from outside the test.
+func hermeticGoEnv(t *testing.T) {
+	gopath := t.TempDir()
+	t.Setenv("GOPATH", gopath)
+	t.Setenv("GOMODCACHE", filepath.Join(gopath, "pkg", "mod"))
+	t.Setenv("GOPROXY", "off")
+	t.Setenv("GOFLAGS", "")
+	t.Setenv("GOTOOLCHAIN", "local")
+}
+
+// TestOfflineMode tests the --offline flag and DRUGO_OFFLINE.
+func TestOfflineMode(t *testing.T) {
+	t.Cleanup(func() { offlineFlag = false })
+
+	for value, want := range map[string]bool{"": false, "0": false, "no": false, "1": true, "true": true} {
+		t.Setenv(offlineEnvVar, value)
+		assert.Equal(t, want, offlineMode(), "DRUGO_OFFLINE=%q", value)
+	}
+	t.Setenv(offlineEnvVar, "")
+	offlineFlag = true
+	assert.True(t, offlineMode())
+}
+
+// TestRunNew_Offline tests that in offline mode tidy and verify failures caused by unavailable
+// modules are warnings, the project is kept, and the summary lists the skipped steps.
+func TestRunNew_Offline(t *testing.T) {
+	useLang(t, langEn)
+	hermeticGoEnv(t)
+	local := frameworkRoot(t)
+	t.Cleanup(func() {
+		offlineFlag, projectTidy, projectVerify, projectReplaceLocal = false, false, false, ""
+	})
+
+	for _, replace := range []string{"", local} {
+		t.Chdir(t.TempDir())
+		offlineFlag, projectTidy, projectVerify, projectReplaceLocal = true, true, true, replace
+
+		var out, errOut bytes.Buffer
+		c := &cobra.Command{}
+		c.SetOut(&out)
+		c.SetErr(&errOut)
+		require.NoError(t, runNew(c, []string{"myapp"}), errOut.String())
+
+		assert.Contains(t, out.String(), "Offline mode: network access is disabled (GOPROXY=off)")
+		assert.Contains(t, errOut.String(), "Warning: dependencies are unavailable offline, skipping go mod tidy")
+		assert.Contains(t, errOut.String(), "Warning: dependencies are unavailable offline, skipping go vet ./...")
+		assert.Contains(t, out.String(), "Project \"myapp\" created successfully!")
+		assert.Contains(t, out.String(), "Skipped in offline mode: go mod tidy, go vet ./... (run again with network access)")
+
+		content, err := os.ReadFile(filepath.Join("myapp", "go.mod"))
+		require.NoError(t, err)
+		f, err := modfile.Parse("go.mod", content, nil)
+		require.NoError(t, err)
+		var version string
+		for _, r := range f.Require {
+			if r.Mod.Path == frameworkModule {
+				version = r.Mod.Version
+			}
+		}
+		assert.Equal(t, frameworkVersion(), version)
+
+		if replace == "" {
+			assert.Empty(t, f.Replace)
+			if version == unpinnedVersion {
+				assert.Contains(t, out.String(), "Warning: this drugo build is not a release")
+			}
+			continue
+		}
+		require.Len(t, f.Replace, 1)
+		assert.Equal(t, frameworkModule, f.Replace[0].Old.Path)
+		assert.Equal(t, local, f.Replace[0].New.Path)
+		assert.Contains(t, out.String(), "Replaced "+frameworkModule+" with "+local)
+	}
+}
+
+// TestRunNew_OfflineNothingSkipped tests the offline summary when no go command step ran.
+func TestRunNew_OfflineNothingSkipped(t *testing.T) {
+	useLang(t, langEn)
+	t.Chdir(t.TempDir())
+	t.Setenv(offlineEnvVar, "1")
+
+	var out bytes.Buffer
+	c := &cobra.Command{}
+	c.SetOut(&out)
+	require.NoError(t, runNew(c, []string{"myapp"}))
+	assert.Contains(t, out.String(), "Offline mode: no steps were skipped")
+}
+
+// TestVerifyProject_OfflineCompileError tests that compile errors are not mistaken for unavailable modules.
+func TestVerifyProject_OfflineCompileError(t *testing.T) {
+	hermeticGoEnv(t)
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module example.com/broken\n\ngo 1.25\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "main.go"), []byte("package main\n\nfunc main() { undefined() }\n"), 0644))
+
+	err := VerifyProject(context.Background(), dir, VerifyOptions{Offline: true})
+	require.Error(t, err)
+	assert.NotErrorIs(t, err, ErrModulesUnavailable)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "main.go"), []byte("package main\n\nimport _ \"example.org/missing\"\n\nfunc main() {}\n"), 0644))
+	err = VerifyProject(context.Background(), dir, VerifyOptions{Offline: true})
+	assert.ErrorIs(t, err, ErrModulesUnavailable)
+}
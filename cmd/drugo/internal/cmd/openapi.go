@@ -0,0 +1,603 @@
+package cmd
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/qq1060656096/drugo/pkg/gomod"
+	"github.com/spf13/cobra"
+	"go.yaml.in/yaml/v3"
+)
+
+// defaultOpenAPIFile is the spec file written by `drugo openapi`, relative to the project root.
+const defaultOpenAPIFile = "docs/openapi.yaml"
+
+// Handler annotations read by the OpenAPI generator. The generated API templates
+// put them in the doc comment of each handler method:
+//
+//	// @route GET /user/address/:id
+//	// @query ListAddressRequest
+//	// @body CreateAddressRequest
+//	// @success 200 AddressResponse
+//
+// @route is required and takes the HTTP method and the gin path. @body and @query
+// name the request struct bound from the JSON body or the query string.
+// @success takes the status code and an optional response struct, which the handler
+// wraps in the {code, message, data} envelope. Struct names are resolved within the module.
+const (
+	annotationRoute   = "@route"
+	annotationBody    = "@body"
+	annotationQuery   = "@query"
+	annotationSuccess = "@success"
+)
+
+// openapiCmd help texts are set by localize.
+var openapiCmd = &cobra.Command{
+	Example: `  drugo openapi
+  drugo openapi -o api/openapi.yaml --title shop`,
+	Args: cobra.NoArgs,
+	RunE: runOpenAPI,
+}
+
+func init() {
+	rootCmd.AddCommand(openapiCmd)
+	openapiCmd.Flags().StringP("output", "o", defaultOpenAPIFile, "")
+	openapiCmd.Flags().String("title", "", "")
+	openapiCmd.Flags().StringP("layout", "l", layoutDrugo, "")
+}
+
+func runOpenAPI(cmd *cobra.Command, args []string) error {
+	wd, err := os.Getwd()
+	if err != nil {
+		return newError(msgWdFailed, err)
+	}
+	projectRoot := gomod.ProjectRoot(wd)
+	if projectRoot == "" {
+		return newError(msgNotInProject, wd)
+	}
+	modPath, err := gomod.ModuleName(projectRoot)
+	if err != nil {
+		return newError(msgGoModFailed, err)
+	}
+	layout, err := resolveLayout(cmd, projectRoot)
+	if err != nil {
+		return err
+	}
+
+	title, _ := cmd.Flags().GetString("title")
+	if title == "" {
+		title = path.Base(modPath)
+	}
+	spec, err := generateOpenAPI(projectRoot, layout, title)
+	if err != nil {
+		return newError(msgOpenAPIFailed, err)
+	}
+	data, err := spec.marshal()
+	if err != nil {
+		return newError(msgOpenAPIFailed, err)
+	}
+
+	output, _ := cmd.Flags().GetString("output")
+	if !filepath.IsAbs(output) {
+		output = filepath.Join(projectRoot, output)
+	}
+	if err := os.MkdirAll(filepath.Dir(output), 0755); err != nil {
+		return newError(msgDirFailed, err)
+	}
+	if err := os.WriteFile(output, data, 0644); err != nil {
+		return newError(msgFileFailed, err)
+	}
+
+	out := cmd.OutOrStdout()
+	fmt.Fprint(out, msg(msgOpenAPISuccess, output, spec.operations))
+	if len(spec.warnings) > 0 {
+		fmt.Fprint(out, msg(msgOpenAPIWarnings, len(spec.warnings)))
+		for _, w := range spec.warnings {
+			fmt.Fprintf(out, "  - %s\n", w)
+		}
+	}
+	return nil
+}
+
+// openAPISpec is the generated OpenAPI 3.0 document. Only the parts the generator emits are modeled.
+type openAPISpec struct {
+	OpenAPI    string                                 `yaml:"openapi"`
+	Info       openAPIInfo                            `yaml:"info"`
+	Paths      map[string]map[string]openAPIOperation `yaml:"paths"`
+	Components openAPIComponents                      `yaml:"components"`
+
+	operations int      // number of generated operations
+	warnings   []string // unsupported types that degraded to generic objects
+}
+
+type openAPIInfo struct {
+	Title   string `yaml:"title"`
+	Version string `yaml:"version"`
+}
+
+type openAPIOperation struct {
+	OperationID string                     `yaml:"operationId"`
+	Tags        []string                   `yaml:"tags,omitempty"`
+	Parameters  []openAPIParameter         `yaml:"parameters,omitempty"`
+	RequestBody *openAPIBody               `yaml:"requestBody,omitempty"`
+	Responses   map[string]openAPIResponse `yaml:"responses"`
+}
+
+type openAPIParameter struct {
+	Name     string         `yaml:"name"`
+	In       string         `yaml:"in"`
+	Required bool           `yaml:"required,omitempty"`
+	Schema   *openAPISchema `yaml:"schema"`
+}
+
+type openAPIBody struct {
+	Required bool                        `yaml:"required,omitempty"`
+	Content  map[string]openAPIMediaType `yaml:"content"`
+}
+
+type openAPIResponse struct {
+	Description string                      `yaml:"description"`
+	Content     map[string]openAPIMediaType `yaml:"content,omitempty"`
+}
+
+type openAPIMediaType struct {
+	Schema *openAPISchema `yaml:"schema"`
+}
+
+type openAPIComponents struct {
+	Schemas map[string]*openAPISchema `yaml:"schemas,omitempty"`
+}
+
+// openAPISchema is a JSON schema object. Properties keep the field declaration order.
+type openAPISchema struct {
+	Ref                  string            `yaml:"$ref,omitempty"`
+	Type                 string            `yaml:"type,omitempty"`
+	Format               string            `yaml:"format,omitempty"`
+	Items                *openAPISchema    `yaml:"items,omitempty"`
+	Properties           openAPIProperties `yaml:"properties,omitempty"`
+	AdditionalProperties *openAPISchema    `yaml:"additionalProperties,omitempty"`
+	Required             []string          `yaml:"required,omitempty"`
+}
+
+// openAPIProperty is a named schema property.
+type openAPIProperty struct {
+	Name   string
+	Schema *openAPISchema
+}
+
+// openAPIProperties is an ordered list of schema properties, marshaled as a YAML mapping.
+type openAPIProperties []openAPIProperty
+
+// MarshalYAML implements yaml.Marshaler.
+func (p openAPIProperties) MarshalYAML() (any, error) {
+	node := &yaml.Node{Kind: yaml.MappingNode}
+	for _, prop := range p {
+		value := &yaml.Node{}
+		if err := value.Encode(prop.Schema); err != nil {
+			return nil, err
+		}
+		node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: prop.Name}, value)
+	}
+	return node, nil
+}
+
+// marshal returns the YAML encoding of the spec.
+func (s *openAPISpec) marshal() ([]byte, error) {
+	var b strings.Builder
+	enc := yaml.NewEncoder(&b)
+	enc.SetIndent(2)
+	if err := enc.Encode(s); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	return []byte(b.String()), nil
+}
+
+// apiRoute is a handler annotated with @route.
+type apiRoute struct {
+	Handler  string // receiver type and method, e.g. AddressHandler.Create
+	Method   string
+	Path     string // gin path, e.g. /user/address/:id
+	Body     string
+	Query    string
+	Status   int
+	Response string
+}
+
+// apiModule holds the annotated routes and struct types of one module.
+type apiModule struct {
+	Name    string
+	Routes  []apiRoute
+	Structs map[string]*ast.StructType
+}
+
+// generateOpenAPI builds the OpenAPI spec from the annotated handlers of every module under the layout root.
+func generateOpenAPI(projectRoot string, layout Layout, title string) (*openAPISpec, error) {
+	spec := &openAPISpec{
+		OpenAPI: "3.0.3",
+		Info:    openAPIInfo{Title: title, Version: "1.0.0"},
+		Paths:   map[string]map[string]openAPIOperation{},
+	}
+
+	root := filepath.Join(projectRoot, layout.Root)
+	entries, err := os.ReadDir(root)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		mod, err := parseAPIModule(filepath.Join(root, entry.Name()), entry.Name())
+		if err != nil {
+			return nil, err
+		}
+		b := &schemaBuilder{module: mod, spec: spec}
+		for _, route := range mod.Routes {
+			b.addRoute(route)
+		}
+		b.emitComponents()
+	}
+	slices.Sort(spec.warnings)
+	spec.warnings = slices.Compact(spec.warnings)
+	return spec, nil
+}
+
+// parseAPIModule parses all non-test Go files under dir, collecting annotated routes and struct types.
+func parseAPIModule(dir, name string) (*apiModule, error) {
+	mod := &apiModule{Name: name, Structs: map[string]*ast.StructType{}}
+	fset := token.NewFileSet()
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || filepath.Ext(p) != ".go" || strings.HasSuffix(p, "_test.go") {
+			return nil
+		}
+		f, err := parser.ParseFile(fset, p, nil, parser.ParseComments)
+		if err != nil {
+			return err
+		}
+		for _, decl := range f.Decls {
+			switch decl := decl.(type) {
+			case *ast.GenDecl:
+				for _, s := range decl.Specs {
+					if ts, ok := s.(*ast.TypeSpec); ok {
+						if st, ok := ts.Type.(*ast.StructType); ok {
+							mod.Structs[ts.Name.Name] = st
+						}
+					}
+				}
+			case *ast.FuncDecl:
+				route, ok, err := parseRouteAnnotations(decl)
+				if err != nil {
+					return fmt.Errorf("%s: %w", fset.Position(decl.Pos()), err)
+				}
+				if ok {
+					mod.Routes = append(mod.Routes, route)
+				}
+			}
+		}
+		return nil
+	})
+	return mod, err
+}
+
+// parseRouteAnnotations reads the handler annotations from the doc comment of fn.
+// ok is false when fn has no @route annotation.
+func parseRouteAnnotations(fn *ast.FuncDecl) (route apiRoute, ok bool, err error) {
+	if fn.Doc == nil {
+		return route, false, nil
+	}
+	route.Handler = fn.Name.Name
+	if fn.Recv != nil && len(fn.Recv.List) > 0 {
+		route.Handler = receiverName(fn.Recv.List[0].Type) + "." + fn.Name.Name
+	}
+	route.Status = 200
+
+	for _, line := range strings.Split(fn.Doc.Text(), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		switch fields[0] {
+		case annotationRoute:
+			if len(fields) != 3 {
+				return route, false, fmt.Errorf("%s: want %s METHOD PATH", route.Handler, annotationRoute)
+			}
+			route.Method, route.Path = strings.ToLower(fields[1]), fields[2]
+			ok = true
+		case annotationBody, annotationQuery:
+			if len(fields) != 2 {
+				return route, false, fmt.Errorf("%s: want %s TYPE", route.Handler, fields[0])
+			}
+			if fields[0] == annotationBody {
+				route.Body = fields[1]
+			} else {
+				route.Query = fields[1]
+			}
+		case annotationSuccess:
+			if len(fields) < 2 || len(fields) > 3 {
+				return route, false, fmt.Errorf("%s: want %s CODE [TYPE]", route.Handler, annotationSuccess)
+			}
+			if route.Status, err = strconv.Atoi(fields[1]); err != nil {
+				return route, false, fmt.Errorf("%s: invalid status %q", route.Handler, fields[1])
+			}
+			if len(fields) == 3 {
+				route.Response = fields[2]
+			}
+		}
+	}
+	return route, ok, nil
+}
+
+// receiverName returns the type name of a method receiver.
+func receiverName(expr ast.Expr) string {
+	if star, ok := expr.(*ast.StarExpr); ok {
+		expr = star.X
+	}
+	if ident, ok := expr.(*ast.Ident); ok {
+		return ident.Name
+	}
+	return types.ExprString(expr)
+}
+
+// schemaBuilder converts the routes and struct types of a module into OpenAPI objects.
+type schemaBuilder struct {
+	module *apiModule
+	spec   *openAPISpec
+	refs   []string // referenced struct names, in order of first use
+}
+
+// addRoute adds the operation of route to the spec.
+func (b *schemaBuilder) addRoute(route apiRoute) {
+	op := openAPIOperation{
+		OperationID: b.module.Name + "." + route.Handler,
+		Tags:        []string{b.module.Name},
+		Responses:   map[string]openAPIResponse{},
+	}
+
+	var segments []string
+	for _, seg := range strings.Split(route.Path, "/") {
+		if strings.HasPrefix(seg, ":") || strings.HasPrefix(seg, "*") {
+			name := seg[1:]
+			op.Parameters = append(op.Parameters, openAPIParameter{
+				Name: name, In: "path", Required: true, Schema: pathParamSchema(name),
+			})
+			seg = "{" + name + "}"
+		}
+		segments = append(segments, seg)
+	}
+
+	if route.Query != "" {
+		op.Parameters = append(op.Parameters, b.queryParams(route.Query)...)
+	}
+	if route.Body != "" {
+		op.RequestBody = &openAPIBody{
+			Required: true,
+			Content:  map[string]openAPIMediaType{"application/json": {Schema: b.typeRef(route.Body, route.Handler)}},
+		}
+	}
+
+	envelope := &openAPISchema{Type: "object", Properties: openAPIProperties{
+		{Name: "code", Schema: &openAPISchema{Type: "integer"}},
+		{Name: "message", Schema: &openAPISchema{Type: "string"}},
+	}}
+	if route.Response != "" {
+		envelope.Properties = append(envelope.Properties, openAPIProperty{Name: "data", Schema: b.typeRef(route.Response, route.Handler)})
+	}
+	op.Responses[strconv.Itoa(route.Status)] = openAPIResponse{
+		Description: "success",
+		Content:     map[string]openAPIMediaType{"application/json": {Schema: envelope}},
+	}
+
+	p := strings.Join(segments, "/")
+	if b.spec.Paths[p] == nil {
+		b.spec.Paths[p] = map[string]openAPIOperation{}
+	}
+	b.spec.Paths[p][route.Method] = op
+	b.spec.operations++
+}
+
+// pathParamSchema returns the schema of a path parameter: id and *_id are int64 as parsed by
+// the generated handlers, everything else is a string.
+func pathParamSchema(name string) *openAPISchema {
+	if name == "id" || strings.HasSuffix(name, "_id") {
+		return &openAPISchema{Type: "integer", Format: "int64"}
+	}
+	return &openAPISchema{Type: "string"}
+}
+
+// queryParams returns the query parameters bound from the fields of struct name via their form tags.
+func (b *schemaBuilder) queryParams(name string) []openAPIParameter {
+	st, ok := b.module.Structs[name]
+	if !ok {
+		b.warn("%s: unknown query type %s", b.module.Name, name)
+		return nil
+	}
+	var params []openAPIParameter
+	for _, field := range st.Fields.List {
+		for _, ident := range field.Names {
+			if !ident.IsExported() {
+				continue
+			}
+			key, skip := tagName(field, "form", ident.Name)
+			if skip {
+				continue
+			}
+			params = append(params, openAPIParameter{
+				Name:     key,
+				In:       "query",
+				Required: hasRequiredBinding(field),
+				Schema:   b.fieldSchema(field.Type, name+"."+ident.Name),
+			})
+		}
+	}
+	return params
+}
+
+// typeRef returns a reference to the component schema of struct name, or a generic object with a warning.
+func (b *schemaBuilder) typeRef(name, where string) *openAPISchema {
+	if _, ok := b.module.Structs[name]; !ok {
+		b.warn("%s.%s: unknown type %s, using object", b.module.Name, where, name)
+		return &openAPISchema{Type: "object"}
+	}
+	if !slices.Contains(b.refs, name) {
+		b.refs = append(b.refs, name)
+	}
+	return &openAPISchema{Ref: "#/components/schemas/" + b.componentName(name)}
+}
+
+// componentName returns the component schema name of struct name, qualified by the module.
+func (b *schemaBuilder) componentName(name string) string {
+	return b.module.Name + "." + name
+}
+
+// emitComponents adds the schemas of all referenced structs, including nested ones, to the spec.
+func (b *schemaBuilder) emitComponents() {
+	for i := 0; i < len(b.refs); i++ {
+		name := b.refs[i]
+		if b.spec.Components.Schemas == nil {
+			b.spec.Components.Schemas = map[string]*openAPISchema{}
+		}
+		b.spec.Components.Schemas[b.componentName(name)] = b.structSchema(name, b.module.Structs[name], nil)
+	}
+}
+
+// structSchema returns the object schema of st. Embedded structs of the module are inlined;
+// seen guards against embedding cycles.
+func (b *schemaBuilder) structSchema(name string, st *ast.StructType, seen []string) *openAPISchema {
+	s := &openAPISchema{Type: "object"}
+	for _, field := range st.Fields.List {
+		if len(field.Names) == 0 {
+			embedded := receiverName(field.Type)
+			inner, ok := b.module.Structs[embedded]
+			if !ok || slices.Contains(seen, embedded) {
+				b.warn("%s.%s: unsupported embedded type %s", b.module.Name, name, types.ExprString(field.Type))
+				continue
+			}
+			is := b.structSchema(embedded, inner, append(seen, name))
+			s.Properties = append(s.Properties, is.Properties...)
+			s.Required = append(s.Required, is.Required...)
+			continue
+		}
+		for _, ident := range field.Names {
+			if !ident.IsExported() {
+				continue
+			}
+			key, skip := tagName(field, "json", ident.Name)
+			if skip {
+				continue
+			}
+			s.Properties = append(s.Properties, openAPIProperty{
+				Name:   key,
+				Schema: b.fieldSchema(field.Type, name+"."+ident.Name),
+			})
+			if hasRequiredBinding(field) {
+				s.Required = append(s.Required, key)
+			}
+		}
+	}
+	return s
+}
+
+// basicSchemas maps Go basic types to their schemas.
+var basicSchemas = map[string]openAPISchema{
+	"string":  {Type: "string"},
+	"bool":    {Type: "boolean"},
+	"int":     {Type: "integer"},
+	"int8":    {Type: "integer", Format: "int32"},
+	"int16":   {Type: "integer", Format: "int32"},
+	"int32":   {Type: "integer", Format: "int32"},
+	"int64":   {Type: "integer", Format: "int64"},
+	"uint":    {Type: "integer"},
+	"uint8":   {Type: "integer", Format: "int32"},
+	"uint16":  {Type: "integer", Format: "int32"},
+	"uint32":  {Type: "integer", Format: "int64"},
+	"uint64":  {Type: "integer", Format: "int64"},
+	"float32": {Type: "number", Format: "float"},
+	"float64": {Type: "number", Format: "double"},
+	"any":     {Type: "object"},
+}
+
+// fieldSchema returns the schema of a field type. where names the field in warnings.
+func (b *schemaBuilder) fieldSchema(expr ast.Expr, where string) *openAPISchema {
+	switch t := expr.(type) {
+	case *ast.Ident:
+		if s, ok := basicSchemas[t.Name]; ok {
+			return &s
+		}
+		if _, ok := b.module.Structs[t.Name]; ok {
+			return b.typeRef(t.Name, where)
+		}
+	case *ast.StarExpr:
+		return b.fieldSchema(t.X, where)
+	case *ast.ArrayType:
+		if ident, ok := t.Elt.(*ast.Ident); ok && ident.Name == "byte" {
+			return &openAPISchema{Type: "string", Format: "byte"}
+		}
+		return &openAPISchema{Type: "array", Items: b.fieldSchema(t.Elt, where)}
+	case *ast.MapType:
+		return &openAPISchema{Type: "object", AdditionalProperties: b.fieldSchema(t.Value, where)}
+	case *ast.SelectorExpr:
+		if types.ExprString(t) == "time.Time" {
+			return &openAPISchema{Type: "string", Format: "date-time"}
+		}
+		// a struct from another layer of the module, e.g. biz.User
+		if _, ok := b.module.Structs[t.Sel.Name]; ok {
+			return b.typeRef(t.Sel.Name, where)
+		}
+	case *ast.InterfaceType:
+		return &openAPISchema{Type: "object"}
+	}
+	b.warn("%s.%s: unsupported type %s, using object", b.module.Name, where, types.ExprString(expr))
+	return &openAPISchema{Type: "object"}
+}
+
+// warn records a generator warning.
+func (b *schemaBuilder) warn(format string, args ...any) {
+	b.spec.warnings = append(b.spec.warnings, fmt.Sprintf(format, args...))
+}
+
+// tagName returns the name of field in the given struct tag, falling back to the Go field name.
+// skip is true when the tag is "-".
+func tagName(field *ast.Field, key, fallback string) (name string, skip bool) {
+	tag := structTag(field).Get(key)
+	if tag == "-" {
+		return "", true
+	}
+	if name, _, _ = strings.Cut(tag, ","); name == "" {
+		name = fallback
+	}
+	return name, false
+}
+
+// hasRequiredBinding reports whether the gin binding tag of field contains required.
+func hasRequiredBinding(field *ast.Field) bool {
+	return slices.Contains(strings.Split(structTag(field).Get("binding"), ","), "required")
+}
+
+// structTag returns the struct tag of field.
+func structTag(field *ast.Field) reflect.StructTag {
+	if field.Tag == nil {
+		return ""
+	}
+	tag, err := strconv.Unquote(field.Tag.Value)
+	if err != nil {
+		return ""
+	}
+	return reflect.StructTag(tag)
+}
@@ -0,0 +1,144 @@
+package cmd
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.yaml.in/yaml/v3"
+)
+
+// TestGenerateOpenAPI 测试根据生成的模块生成 OpenAPI 文档：五个 CRUD 接口、参数与 schema 字段
+func TestGenerateOpenAPI(t *testing.T) {
+	const modPath = "github.com/acme/shop"
+	for _, layout := range layouts {
+		t.Run(layout.Name, func(t *testing.T) {
+			root := t.TempDir()
+			require.NoError(t, createModule(root, modPath, "user", layout, dataMemory))
+			require.NoError(t, createModuleApi(io.Discard, root, modPath, "user", "address", layout, dataMemory))
+
+			spec, err := generateOpenAPI(root, layout, "shop")
+			require.NoError(t, err)
+			assert.Equal(t, 10, spec.operations)
+			assert.Empty(t, spec.warnings)
+
+			data, err := spec.marshal()
+			require.NoError(t, err)
+			var doc map[string]any
+			require.NoError(t, yaml.Unmarshal(data, &doc))
+			assert.Equal(t, "3.0.3", doc["openapi"])
+
+			paths := doc["paths"].(map[string]any)
+			collection := paths["/user/address"].(map[string]any)
+			item := paths["/user/address/{id}"].(map[string]any)
+			assert.ElementsMatch(t, []string{"get", "post"}, keys(collection))
+			assert.ElementsMatch(t, []string{"get", "put", "delete"}, keys(item))
+			assert.Contains(t, paths, "/user/user")
+			assert.Contains(t, paths, "/user/user/{id}")
+
+			// :id 路径参数
+			get := item["get"].(map[string]any)
+			param := get["parameters"].([]any)[0].(map[string]any)
+			assert.Equal(t, map[string]any{"name": "id", "in": "path", "required": true,
+				"schema": map[string]any{"type": "integer", "format": "int64"}}, param)
+
+			// 分页查询参数
+			list := collection["get"].(map[string]any)
+			var query []string
+			for _, p := range list["parameters"].([]any) {
+				assert.Equal(t, "query", p.(map[string]any)["in"])
+				query = append(query, p.(map[string]any)["name"].(string))
+			}
+			assert.Equal(t, []string{"page", "page_size"}, query)
+
+			// 创建接口的请求体与 201 响应
+			create := collection["post"].(map[string]any)
+			assert.Equal(t, "user.AddressHandler.Create", create["operationId"])
+			body := create["requestBody"].(map[string]any)["content"].(map[string]any)["application/json"].(map[string]any)
+			assert.Equal(t, "#/components/schemas/user.CreateAddressRequest", body["schema"].(map[string]any)["$ref"])
+			resp := create["responses"].(map[string]any)["201"].(map[string]any)
+			envelope := resp["content"].(map[string]any)["application/json"].(map[string]any)["schema"].(map[string]any)
+			assert.Equal(t, "#/components/schemas/user.AddressResponse",
+				envelope["properties"].(map[string]any)["data"].(map[string]any)["$ref"])
+
+			schemas := doc["components"].(map[string]any)["schemas"].(map[string]any)
+			createReq := schemas["user.CreateAddressRequest"].(map[string]any)
+			assert.Equal(t, []any{"name"}, createReq["required"])
+			addr := schemas["user.AddressResponse"].(map[string]any)["properties"].(map[string]any)
+			assert.Equal(t, map[string]any{"type": "integer", "format": "int64"}, addr["id"])
+			assert.Equal(t, map[string]any{"type": "string"}, addr["name"])
+			listResp := schemas["user.ListAddressResponse"].(map[string]any)["properties"].(map[string]any)
+			assert.Equal(t, map[string]any{"type": "array",
+				"items": map[string]any{"$ref": "#/components/schemas/user.AddressResponse"}}, listResp["list"])
+		})
+	}
+}
+
+// TestGenerateOpenAPI_Warnings 测试无法识别的字段类型降级为 object 并产生警告
+func TestGenerateOpenAPI_Warnings(t *testing.T) {
+	root := t.TempDir()
+	dir := filepath.Join(root, "internal", "report", "api")
+	require.NoError(t, os.MkdirAll(dir, 0755))
+	src := `package api
+
+import "time"
+
+type ReportResponse struct {
+	At      time.Time         ` + "`json:\"at\"`" + `
+	Tags    map[string]string ` + "`json:\"tags\"`" + `
+	Updates chan int          ` + "`json:\"updates\"`" + `
+	secret  string
+	Skip    string ` + "`json:\"-\"`" + `
+}
+
+// Get 获取报表
+//
+// @route GET /report/:report_id
+// @success 200 ReportResponse
+func Get() {}
+
+// Export 导出报表
+//
+// @route POST /report/export
+// @body ExportRequest
+// @success 202
+func Export() {}
+`
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "report.go"), []byte(src), 0644))
+
+	spec, err := generateOpenAPI(root, layouts[0], "report")
+	require.NoError(t, err)
+	assert.Equal(t, 2, spec.operations)
+	assert.Equal(t, []string{
+		"report.Export: unknown type ExportRequest, using object",
+		"report.ReportResponse.Updates: unsupported type chan int, using object",
+	}, spec.warnings)
+
+	props := spec.Components.Schemas["report.ReportResponse"].Properties
+	var names []string
+	for _, p := range props {
+		names = append(names, p.Name)
+	}
+	assert.Equal(t, []string{"at", "tags", "updates"}, names)
+	assert.Equal(t, "date-time", props[0].Schema.Format)
+	assert.Equal(t, "object", props[2].Schema.Type)
+	assert.Equal(t, "integer", spec.Paths["/report/{report_id}"]["get"].Parameters[0].Schema.Type)
+	assert.Contains(t, spec.Paths["/report/export"]["post"].Responses, "202")
+
+	// 注解格式错误时返回错误
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "bad.go"), []byte("package api\n\n// @route GET\nfunc Bad() {}\n"), 0644))
+	_, err = generateOpenAPI(root, layouts[0], "report")
+	assert.ErrorContains(t, err, "@route METHOD PATH")
+}
+
+// keys 返回 map 的所有键
+func keys(m map[string]any) []string {
+	result := make([]string, 0, len(m))
+	for k := range m {
+		result = append(result, k)
+	}
+	return result
+}
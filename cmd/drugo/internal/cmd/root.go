@@ -12,37 +12,139 @@ import (
 // Version is the current version of drugo CLI.
 var Version = "dev"
 
+var (
+	// Global flags
+	langFlag string
+)
+
 var rootCmd = &cobra.Command{
-	Use:   "drugo",
-	Short: "Drugo 是一个轻量级、模块化的 Go 应用程序框架 CLI 工具",
-	Long: `Drugo CLI 是 Drugo 框架的命令行工具。
-它帮助你创建新项目和模块，并提供标准的目录结构。
-
-用法:
-  drugo new <项目名称>           创建一个新的 Drugo 项目
-  drugo module new <模块名称>    在现有项目中创建新模块
-  drugo module new-api <模块名称> <API名称> 在现有模块中创建新的 API 结构
-
-示例:
-  drugo new myapp                创建一个名为 'myapp' 的新项目
-  drugo module new user          创建一个带有 CRUD 模板的 user 模块
-  drugo module new-api user address 在 user 模块中创建 address API`,
+	Use:     "drugo",
 	Version: getVersion(),
 }
 
 // Execute runs the root command.
 func Execute() error {
+	l, err := resolveLang(langFromArgs(os.Args[1:]))
+	lang = l
+	localize()
+	if err != nil {
+		rootCmd.PrintErrln("Error:", err)
+		return err
+	}
 	return rootCmd.Execute()
 }
 
 func init() {
 	rootCmd.SetOut(os.Stdout)
 	rootCmd.SetErr(os.Stderr)
+	rootCmd.PersistentFlags().StringVar(&langFlag, "lang", "", "")
+	rootCmd.PersistentFlags().BoolVar(&offlineFlag, "offline", false, "")
 
 	// Add version template
 	rootCmd.SetVersionTemplate(fmt.Sprintf("drugo version %s\n", getVersion()))
 }
 
+// localize applies the current language to all command help texts and flag usages.
+// It must run after all commands have been registered.
+func localize() {
+	rootCmd.Short = msg(msgRootShort)
+	rootCmd.Long = msg(msgRootLong)
+	rootCmd.PersistentFlags().Lookup("lang").Usage = msg(msgFlagLang)
+	rootCmd.PersistentFlags().Lookup("offline").Usage = msg(msgFlagOffline)
+
+	completionCmd.Use = msg(msgCompleteUse)
+	completionCmd.Short = msg(msgCompleteShrt)
+	completionCmd.Long = msg(msgCompleteLong)
+
+	newCmd.Use = msg(msgNewUse)
+	newCmd.Short = msg(msgNewShort)
+	newCmd.Long = msg(msgNewLong)
+	newCmd.Flags().Lookup("mod").Usage = msg(msgNewFlagMod)
+	newCmd.Flags().Lookup("verify").Usage = msg(msgNewFlagVerify)
+	newCmd.Flags().Lookup("keep-on-failure").Usage = msg(msgNewFlagKeep)
+	newCmd.Flags().Lookup("replace-local").Usage = msg(msgNewFlagLocal)
+	newCmd.Flags().Lookup("tidy").Usage = msg(msgNewFlagTidy)
+
+	moduleCmd.Short = msg(msgModuleShort)
+	moduleCmd.Long = msg(msgModuleLong)
+	moduleNewCmd.Use = msg(msgModuleNewUse)
+	moduleNewCmd.Short = msg(msgModuleNewShort)
+	moduleNewCmd.Long = msg(msgModuleNewLong)
+	moduleNewCmd.Flags().Lookup("kind").Usage = msg(msgModuleFlagKind)
+	moduleNewCmd.Flags().Lookup("layout").Usage = msg(msgModuleFlagLayout)
+	moduleNewCmd.Flags().Lookup("main").Usage = msg(msgModuleFlagMain)
+	moduleNewCmd.Flags().Lookup("data").Usage = msg(msgModuleFlagData)
+
+	moduleApiCmd.Use = msg(msgAPIUse)
+	moduleApiCmd.Short = msg(msgAPIShort)
+	moduleApiCmd.Long = msg(msgAPILong)
+	moduleApiCmd.Flags().Lookup("layout").Usage = msg(msgModuleFlagLayout)
+	moduleApiCmd.Flags().Lookup("data").Usage = msg(msgModuleFlagData)
+
+	openapiCmd.Use = msg(msgOpenAPIUse)
+	openapiCmd.Short = msg(msgOpenAPIShort)
+	openapiCmd.Long = msg(msgOpenAPILong)
+	openapiCmd.Flags().Lookup("output").Usage = msg(msgOpenAPIFlagOutput)
+	openapiCmd.Flags().Lookup("title").Usage = msg(msgOpenAPIFlagTitle)
+	openapiCmd.Flags().Lookup("layout").Usage = msg(msgModuleFlagLayout)
+
+	routesCmd.Use = msg(msgRoutesUse)
+	routesCmd.Short = msg(msgRoutesShort)
+	routesCmd.Long = msg(msgRoutesLong)
+	routesCmd.Flags().Lookup("json").Usage = msg(msgRoutesFlagJSON)
+	routesCmd.Flags().Lookup("strict").Usage = msg(msgRoutesFlagStrict)
+	routesCmd.Flags().Lookup("layout").Usage = msg(msgModuleFlagLayout)
+
+	generateCmd.Short = msg(msgGenerateShort)
+	generateCmd.Long = msg(msgGenerateLong)
+	generateServicesCmd.Short = msg(msgServicesShort)
+	generateServicesCmd.Long = msg(msgServicesLong)
+	generateServicesCmd.Flags().Lookup("output").Usage = msg(msgServicesFlagOutput)
+	generateServicesCmd.Flags().Lookup("check").Usage = msg(msgServicesFlagCheck)
+
+	lintTemplatesCmd.Short = msg(msgLintShort)
+	lintTemplatesCmd.Long = msg(msgLintLong)
+
+	configCmd.Short = msg(msgConfigShort)
+	configCmd.Long = msg(msgConfigLong)
+	configExportCmd.Short = msg(msgConfigExportShort)
+	configExportCmd.Long = msg(msgConfigExportLong)
+	configExportCmd.Flags().Lookup("redact").Usage = msg(msgConfigExportFlagRedact)
+	configExportCmd.Flags().Lookup("format").Usage = msg(msgConfigExportFlagFormat)
+	configExportCmd.Flags().Lookup("out").Usage = msg(msgConfigExportFlagOut)
+	configExportCmd.Flags().Lookup("env").Usage = msg(msgConfigExportFlagEnv)
+	configUnusedCmd.Short = msg(msgConfigUnusedShort)
+	configUnusedCmd.Long = msg(msgConfigUnusedLong)
+	configUnusedCmd.Flags().Lookup("report").Usage = msg(msgConfigUnusedFlagReport)
+
+	addCmd.Short = msg(msgAddShort)
+	addCmd.Long = msg(msgAddLong)
+	addServiceCmd.Use = msg(msgAddServiceUse)
+	addServiceCmd.Short = msg(msgAddServiceShort)
+	addServiceCmd.Long = msg(msgAddServiceLong)
+	addServiceCmd.Flags().Lookup("dry-run").Usage = msg(msgAddFlagDryRun)
+	addServiceCmd.Flags().Lookup("main").Usage = msg(msgAddFlagMain)
+
+	localizeDefaultFlags(rootCmd)
+}
+
+// localizeDefaultFlags localizes the help and version flags cobra adds to every command.
+func localizeDefaultFlags(c *cobra.Command) {
+	c.InitDefaultHelpFlag()
+	if f := c.Flags().Lookup("help"); f != nil {
+		f.Usage = msg(msgHelpFlag)
+	}
+	if c.Version != "" {
+		c.InitDefaultVersionFlag()
+		if f := c.Flags().Lookup("version"); f != nil {
+			f.Usage = msg(msgVersionFlag)
+		}
+	}
+	for _, sub := range c.Commands() {
+		localizeDefaultFlags(sub)
+	}
+}
+
 func getVersion() string {
 	version, _ := gomod.MainVersion()
 	if version == "" {
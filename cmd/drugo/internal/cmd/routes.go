@@ -0,0 +1,582 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/qq1060656096/drugo/pkg/gomod"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+// dynamicPath is shown in place of a route path the analyzer cannot resolve statically.
+const dynamicPath = "<dynamic>"
+
+// Import paths that mark a package outside the layout root as registering routes.
+const (
+	routerImport = frameworkModule + "/pkg/router"
+	ginImport    = "github.com/gin-gonic/gin"
+)
+
+// routeMethods maps the gin router methods to the HTTP method they register.
+var routeMethods = map[string]string{
+	"GET":     "GET",
+	"POST":    "POST",
+	"PUT":     "PUT",
+	"DELETE":  "DELETE",
+	"PATCH":   "PATCH",
+	"HEAD":    "HEAD",
+	"OPTIONS": "OPTIONS",
+	"Any":     "ANY",
+}
+
+// routerTypes are the parameter types treated as a gin router rooted at "/".
+var routerTypes = []string{"gin.IRouter", "gin.IRoutes", "*gin.Engine", "*gin.RouterGroup"}
+
+// routesCmd help texts are set by localize.
+var routesCmd = &cobra.Command{
+	Example: `  drugo routes
+  drugo routes --strict --json > routes.json`,
+	Args: cobra.NoArgs,
+	RunE: runRoutes,
+}
+
+func init() {
+	rootCmd.AddCommand(routesCmd)
+	addRoutesFlags(routesCmd)
+}
+
+// addRoutesFlags registers the flags read by runRoutes on c.
+func addRoutesFlags(c *cobra.Command) {
+	c.Flags().Bool("json", false, "")
+	c.Flags().Bool("strict", false, "")
+	c.Flags().StringP("layout", "l", layoutDrugo, "")
+}
+
+func runRoutes(cmd *cobra.Command, args []string) error {
+	wd, err := os.Getwd()
+	if err != nil {
+		return newError(msgWdFailed, err)
+	}
+	projectRoot := gomod.ProjectRoot(wd)
+	if projectRoot == "" {
+		return newError(msgNotInProject, wd)
+	}
+	layout, err := resolveLayout(cmd, projectRoot)
+	if err != nil {
+		return err
+	}
+
+	routes, err := analyzeRoutes(projectRoot, layout)
+	if err != nil {
+		return newError(msgRoutesFailed, err)
+	}
+	duplicates := markDuplicateRoutes(routes)
+
+	out := cmd.OutOrStdout()
+	if asJSON, _ := cmd.Flags().GetBool("json"); asJSON {
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		if routes == nil {
+			routes = []routeEntry{}
+		}
+		if err := enc.Encode(routes); err != nil {
+			return newError(msgRoutesFailed, err)
+		}
+	} else if len(routes) == 0 {
+		fmt.Fprint(out, msg(msgRoutesEmpty))
+	} else {
+		writeRoutesTable(out, routes, colorEnabled(out))
+		if duplicates > 0 {
+			fmt.Fprint(out, msg(msgRoutesDuplicates, duplicates))
+		}
+	}
+
+	if strict, _ := cmd.Flags().GetBool("strict"); strict && duplicates > 0 {
+		return newError(msgRoutesConflict, duplicates)
+	}
+	return nil
+}
+
+// routeEntry is a route found by the analyzer.
+type routeEntry struct {
+	Method    string `json:"method"`
+	Path      string `json:"path"` // dynamicPath when the path or a group prefix is not a constant
+	Module    string `json:"module"`
+	Handler   string `json:"handler"`
+	Location  string `json:"location"` // file:line relative to the project root
+	Duplicate bool   `json:"duplicate,omitempty"`
+}
+
+// writeRoutesTable writes routes as a METHOD, PATH, MODULE, HANDLER table.
+// Duplicate routes are printed in red when color is set; dynamic paths show their source location.
+func writeRoutesTable(w io.Writer, routes []routeEntry, color bool) {
+	var buf bytes.Buffer
+	tw := tabwriter.NewWriter(&buf, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "METHOD\tPATH\tMODULE\tHANDLER")
+	for _, r := range routes {
+		p := r.Path
+		if p == dynamicPath {
+			p += " (" + r.Location + ")"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", r.Method, p, r.Module, r.Handler)
+	}
+	tw.Flush()
+
+	// the header is the first line, route i is on line i+1; color whole lines so tabwriter widths stay right
+	lines := strings.SplitAfter(buf.String(), "\n")
+	for i, line := range lines {
+		if color && i > 0 && i <= len(routes) && routes[i-1].Duplicate {
+			line = "\x1b[31m" + strings.TrimSuffix(line, "\n") + "\x1b[0m\n"
+		}
+		io.WriteString(w, line)
+	}
+}
+
+// colorEnabled reports whether w is a terminal and NO_COLOR is not set.
+func colorEnabled(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	return ok && os.Getenv("NO_COLOR") == "" && term.IsTerminal(int(f.Fd()))
+}
+
+// markDuplicateRoutes marks the routes whose method and path are registered by more than one module
+// and returns the number of marked routes. Dynamic paths are never duplicates.
+func markDuplicateRoutes(routes []routeEntry) int {
+	modules := map[string]map[string]struct{}{}
+	key := func(r routeEntry) string { return r.Method + " " + r.Path }
+	for _, r := range routes {
+		if r.Path == dynamicPath {
+			continue
+		}
+		if modules[key(r)] == nil {
+			modules[key(r)] = map[string]struct{}{}
+		}
+		modules[key(r)][r.Module] = struct{}{}
+	}
+	n := 0
+	for i := range routes {
+		if r := routes[i]; r.Path != dynamicPath && len(modules[key(r)]) > 1 {
+			routes[i].Duplicate = true
+			n++
+		}
+	}
+	return n
+}
+
+// analyzeRoutes statically finds the gin routes registered by the project: every Go file of the modules
+// under the layout root, and any other package importing pkg/router or gin. Routes are sorted by path,
+// then method and module.
+func analyzeRoutes(projectRoot string, layout Layout) ([]routeEntry, error) {
+	fset := token.NewFileSet()
+	modules := map[string]*routeModule{}
+	var names []string
+	layoutRoot := filepath.Join(projectRoot, layout.Root)
+
+	err := filepath.WalkDir(projectRoot, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			name := d.Name()
+			if p != projectRoot && (name == "vendor" || name == "testdata" ||
+				strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_") || gomod.HasGoMod(p)) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if filepath.Ext(p) != ".go" || strings.HasSuffix(p, "_test.go") {
+			return nil
+		}
+
+		module, inLayout := routeModuleName(projectRoot, layoutRoot, p)
+		f, err := parser.ParseFile(fset, p, nil, parser.SkipObjectResolution)
+		if err != nil {
+			return err
+		}
+		if !inLayout && !importsRouter(f) {
+			return nil
+		}
+		m := modules[module]
+		if m == nil {
+			m = &routeModule{name: module, consts: map[string]ast.Expr{}}
+			modules[module] = m
+			names = append(names, module)
+		}
+		m.files = append(m.files, f)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var routes []routeEntry
+	for _, name := range names {
+		routes = append(routes, modules[name].analyze(fset, projectRoot)...)
+	}
+	slices.SortStableFunc(routes, func(a, b routeEntry) int {
+		if c := strings.Compare(a.Path, b.Path); c != 0 {
+			return c
+		}
+		if c := strings.Compare(a.Method, b.Method); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Module, b.Module)
+	})
+	return routes, nil
+}
+
+// routeModuleName returns the module a file belongs to: the first directory under the layout root,
+// or the slash-separated package directory relative to the project root for files outside it.
+func routeModuleName(projectRoot, layoutRoot, file string) (module string, inLayout bool) {
+	if rel, err := filepath.Rel(layoutRoot, filepath.Dir(file)); err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		if first, _, _ := strings.Cut(filepath.ToSlash(rel), "/"); first != "." {
+			return first, true
+		}
+	}
+	rel, err := filepath.Rel(projectRoot, filepath.Dir(file))
+	if err != nil {
+		return filepath.Dir(file), false
+	}
+	return filepath.ToSlash(rel), false
+}
+
+// importsRouter reports whether f imports pkg/router or gin.
+func importsRouter(f *ast.File) bool {
+	for _, imp := range f.Imports {
+		if p, _ := strconv.Unquote(imp.Path.Value); p == routerImport || p == ginImport {
+			return true
+		}
+	}
+	return false
+}
+
+// routePrefix is the path a router variable registers under.
+type routePrefix struct {
+	path    string
+	dynamic bool
+}
+
+// routeModule holds the parsed files of one module.
+type routeModule struct {
+	name   string
+	files  []*ast.File
+	consts map[string]ast.Expr // package-level constants, for paths built from named constants
+}
+
+// analyze returns the routes registered by the functions of the module.
+//
+// Router parameters of a function start at "/", unless the function is called within the module with
+// a router argument that has a prefix, e.g. h.RegisterRoutes(r.Group("/v1")): its routes are then
+// registered under every such prefix. Callees are matched by name only.
+func (m *routeModule) analyze(fset *token.FileSet, projectRoot string) []routeEntry {
+	var funcs []*ast.FuncDecl
+	for _, f := range m.files {
+		for _, decl := range f.Decls {
+			switch decl := decl.(type) {
+			case *ast.FuncDecl:
+				if decl.Body != nil {
+					funcs = append(funcs, decl)
+				}
+			case *ast.GenDecl:
+				if decl.Tok != token.CONST {
+					continue
+				}
+				for _, spec := range decl.Specs {
+					vs := spec.(*ast.ValueSpec)
+					for i, name := range vs.Names {
+						if i < len(vs.Values) {
+							m.consts[name.Name] = vs.Values[i]
+						}
+					}
+				}
+			}
+		}
+	}
+
+	// propagate prefixes passed to functions of the module until nothing changes; the bound
+	// guards against recursive registration functions growing the prefix forever
+	callPrefixes := map[string][]routePrefix{}
+	var routes []routeEntry
+	for range 8 {
+		routes = nil
+		next := map[string][]routePrefix{}
+		for _, fn := range funcs {
+			prefixes := callPrefixes[fn.Name.Name]
+			if len(prefixes) == 0 {
+				prefixes = []routePrefix{{path: "/"}}
+			}
+			for _, prefix := range prefixes {
+				a := &routeAnalyzer{module: m, fset: fset, root: projectRoot, fn: fn, vars: map[string]routePrefix{}}
+				a.bindParams(fn.Type, prefix)
+				ast.Inspect(fn.Body, a.visit)
+				routes = append(routes, a.routes...)
+				for name, ps := range a.calls {
+					for _, p := range ps {
+						if !slices.Contains(next[name], p) {
+							next[name] = append(next[name], p)
+						}
+					}
+				}
+			}
+		}
+		if routePrefixesEqual(next, callPrefixes) {
+			break
+		}
+		callPrefixes = next
+	}
+	return routes
+}
+
+// routePrefixesEqual reports whether two call prefix maps are equal, ignoring order.
+func routePrefixesEqual(a, b map[string][]routePrefix) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for name, ps := range a {
+		if len(ps) != len(b[name]) {
+			return false
+		}
+		for _, p := range ps {
+			if !slices.Contains(b[name], p) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// routeAnalyzer finds the routes registered by one function for one prefix of its router parameters.
+type routeAnalyzer struct {
+	module *routeModule
+	fset   *token.FileSet
+	root   string
+	fn     *ast.FuncDecl
+	vars   map[string]routePrefix   // router variables in scope; shadowing is not tracked
+	routes []routeEntry             // routes found
+	calls  map[string][]routePrefix // prefixed routers passed to other functions, by callee name
+}
+
+// bindParams binds the router parameters of a function type to prefix.
+func (a *routeAnalyzer) bindParams(ft *ast.FuncType, prefix routePrefix) {
+	if ft.Params == nil {
+		return
+	}
+	for _, field := range ft.Params.List {
+		if !slices.Contains(routerTypes, types.ExprString(field.Type)) {
+			continue
+		}
+		for _, name := range field.Names {
+			a.vars[name.Name] = prefix
+		}
+	}
+}
+
+// visit is the ast.Inspect callback.
+func (a *routeAnalyzer) visit(n ast.Node) bool {
+	switch n := n.(type) {
+	case *ast.FuncLit:
+		// routers received by closures, e.g. in router.RegisterGroup, start at "/"
+		a.bindParams(n.Type, routePrefix{path: "/"})
+	case *ast.AssignStmt:
+		for i, lhs := range n.Lhs {
+			ident, ok := lhs.(*ast.Ident)
+			if !ok || len(n.Rhs) != len(n.Lhs) {
+				continue
+			}
+			if prefix, ok := a.router(n.Rhs[i]); ok {
+				a.vars[ident.Name] = prefix
+			}
+		}
+	case *ast.ValueSpec:
+		for i, name := range n.Names {
+			if i < len(n.Values) {
+				if prefix, ok := a.router(n.Values[i]); ok {
+					a.vars[name.Name] = prefix
+				}
+			}
+		}
+	case *ast.CallExpr:
+		a.call(n)
+	}
+	return true
+}
+
+// call records the route registered by call, or the prefixed routers it passes to another function.
+func (a *routeAnalyzer) call(call *ast.CallExpr) {
+	var callee string
+	switch fun := call.Fun.(type) {
+	case *ast.SelectorExpr:
+		callee = fun.Sel.Name
+		if base, ok := a.router(fun.X); ok {
+			if a.route(call, base, fun.Sel.Name) {
+				return
+			}
+		}
+	case *ast.Ident:
+		callee = fun.Name
+	default:
+		return
+	}
+	for _, arg := range call.Args {
+		if prefix, ok := a.router(arg); ok && (prefix.dynamic || prefix.path != "/") {
+			if a.calls == nil {
+				a.calls = map[string][]routePrefix{}
+			}
+			a.calls[callee] = append(a.calls[callee], prefix)
+		}
+	}
+}
+
+// route records a route registered on base with the router method name, reporting whether it was one.
+func (a *routeAnalyzer) route(call *ast.CallExpr, base routePrefix, name string) bool {
+	args := call.Args
+	method, ok := routeMethods[name]
+	if name == "Handle" && len(args) >= 1 {
+		method, ok = a.constString(args[0])
+		if !ok {
+			method, ok = dynamicPath, true
+		}
+		args = args[1:]
+	}
+	if !ok || len(args) == 0 {
+		return false
+	}
+
+	entry := routeEntry{
+		Method:   strings.ToUpper(method),
+		Path:     dynamicPath,
+		Module:   a.module.name,
+		Location: a.location(call.Pos()),
+	}
+	if rel, ok := a.constString(args[0]); ok && !base.dynamic {
+		entry.Path = joinRoutePath(base.path, rel)
+	}
+	if len(args) > 1 {
+		entry.Handler = a.handlerName(args[len(args)-1])
+	}
+	a.routes = append(a.routes, entry)
+	return true
+}
+
+// router returns the prefix of expr if it is a router: a router variable, a Group call on a router,
+// or gin.New()/gin.Default().
+func (a *routeAnalyzer) router(expr ast.Expr) (routePrefix, bool) {
+	switch e := expr.(type) {
+	case *ast.ParenExpr:
+		return a.router(e.X)
+	case *ast.Ident:
+		prefix, ok := a.vars[e.Name]
+		return prefix, ok
+	case *ast.CallExpr:
+		sel, ok := e.Fun.(*ast.SelectorExpr)
+		if !ok {
+			return routePrefix{}, false
+		}
+		if s := types.ExprString(sel); s == "gin.New" || s == "gin.Default" {
+			return routePrefix{path: "/"}, true
+		}
+		if sel.Sel.Name != "Group" || len(e.Args) == 0 {
+			return routePrefix{}, false
+		}
+		base, ok := a.router(sel.X)
+		if !ok {
+			return routePrefix{}, false
+		}
+		rel, ok := a.constString(e.Args[0])
+		if base.dynamic || !ok {
+			return routePrefix{dynamic: true}, true
+		}
+		return routePrefix{path: joinRoutePath(base.path, rel)}, true
+	}
+	return routePrefix{}, false
+}
+
+// constString resolves a string literal, a module constant or a concatenation of them.
+func (a *routeAnalyzer) constString(expr ast.Expr) (string, bool) {
+	return a.resolveString(expr, 0)
+}
+
+func (a *routeAnalyzer) resolveString(expr ast.Expr, depth int) (string, bool) {
+	if depth > 16 {
+		return "", false
+	}
+	switch e := expr.(type) {
+	case *ast.BasicLit:
+		if e.Kind != token.STRING {
+			return "", false
+		}
+		s, err := strconv.Unquote(e.Value)
+		return s, err == nil
+	case *ast.ParenExpr:
+		return a.resolveString(e.X, depth+1)
+	case *ast.BinaryExpr:
+		if e.Op != token.ADD {
+			return "", false
+		}
+		x, ok := a.resolveString(e.X, depth+1)
+		if !ok {
+			return "", false
+		}
+		y, ok := a.resolveString(e.Y, depth+1)
+		return x + y, ok
+	case *ast.Ident:
+		if v, ok := a.module.consts[e.Name]; ok {
+			return a.resolveString(v, depth+1)
+		}
+	}
+	return "", false
+}
+
+// handlerName returns the display name of a handler: Type.Method for methods of the enclosing
+// receiver, the source expression otherwise.
+func (a *routeAnalyzer) handlerName(expr ast.Expr) string {
+	switch e := expr.(type) {
+	case *ast.FuncLit:
+		return "func literal"
+	case *ast.SelectorExpr:
+		if x, ok := e.X.(*ast.Ident); ok && a.fn.Recv != nil && len(a.fn.Recv.List) > 0 {
+			recv := a.fn.Recv.List[0]
+			if len(recv.Names) > 0 && recv.Names[0].Name == x.Name {
+				return receiverName(recv.Type) + "." + e.Sel.Name
+			}
+		}
+	}
+	return types.ExprString(expr)
+}
+
+// location returns the file:line of pos relative to the project root.
+func (a *routeAnalyzer) location(pos token.Pos) string {
+	p := a.fset.Position(pos)
+	file := p.Filename
+	if rel, err := filepath.Rel(a.root, file); err == nil {
+		file = filepath.ToSlash(rel)
+	}
+	return file + ":" + strconv.Itoa(p.Line)
+}
+
+// joinRoutePath joins a group prefix and a relative path the way gin does,
+// keeping the trailing slash of rel.
+func joinRoutePath(base, rel string) string {
+	if rel == "" {
+		return base
+	}
+	joined := path.Join(base, rel)
+	if strings.HasSuffix(rel, "/") && !strings.HasSuffix(joined, "/") {
+		joined += "/"
+	}
+	return joined
+}
@@ -0,0 +1,197 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// legacyRoutes 是 order 模块中与 user 模块冲突的路由文件，同时包含常量拼接与动态路径
+const legacyRoutes = `package order
+
+import "github.com/gin-gonic/gin"
+
+const legacyPrefix = "/user"
+
+type LegacyHandler struct{}
+
+func (h *LegacyHandler) List(c *gin.Context) {}
+
+func (h *LegacyHandler) RegisterRoutes(r gin.IRouter, version string) {
+	group := r.Group(legacyPrefix + "/user")
+	group.GET("", h.List)
+	registerV2(group.Group("/v2"))
+	r.Group("/" + version).GET("/legacy", h.List)
+}
+
+func registerV2(r gin.IRouter) {
+	r.Handle("POST", "/import/", func(c *gin.Context) {})
+}
+`
+
+// setupRoutesProject 生成 user 与 order 两个模块，order 模块中的 legacy 路由与 user 模块冲突，
+// 并将工作目录切换到项目根目录
+func setupRoutesProject(t *testing.T) string {
+	t.Helper()
+	const modPath = "github.com/acme/shop"
+	root := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(root, "go.mod"), []byte("module "+modPath+"\n\ngo 1.25\n"), 0644))
+	require.NoError(t, createModule(root, modPath, "user", layouts[0], dataMemory))
+	require.NoError(t, createModule(root, modPath, "order", layouts[0], dataMemory))
+	dir := filepath.Join(root, "internal", "order", "api")
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "legacy.go"), []byte(legacyRoutes), 0644))
+	t.Chdir(root)
+	return root
+}
+
+// runRoutesArgs 以 args 执行 `drugo routes` 并返回标准输出
+func runRoutesArgs(t *testing.T, args ...string) (string, error) {
+	t.Helper()
+	var out bytes.Buffer
+	c := &cobra.Command{}
+	addRoutesFlags(c)
+	c.SetOut(&out)
+	require.NoError(t, c.Flags().Parse(args))
+	err := runRoutes(c, nil)
+	return out.String(), err
+}
+
+// TestAnalyzeRoutes 测试各目录布局下生成的模块与 API 的路由、处理器名称与源码位置
+func TestAnalyzeRoutes(t *testing.T) {
+	const modPath = "github.com/acme/shop"
+	for _, layout := range layouts {
+		t.Run(layout.Name, func(t *testing.T) {
+			root := t.TempDir()
+			require.NoError(t, createModule(root, modPath, "user", layout, dataMemory))
+			require.NoError(t, createModuleApi(io.Discard, root, modPath, "user", "address", layout, dataMemory))
+
+			routes, err := analyzeRoutes(root, layout)
+			require.NoError(t, err)
+			var got []string
+			for _, r := range routes {
+				got = append(got, r.Method+" "+r.Path+" "+r.Module+" "+r.Handler)
+				assert.Regexp(t, `^`+layout.Root+`/user/.+\.go:\d+$`, r.Location)
+				assert.False(t, r.Duplicate)
+			}
+			assert.Equal(t, []string{
+				"GET /user/address user AddressHandler.List",
+				"POST /user/address user AddressHandler.Create",
+				"DELETE /user/address/:id user AddressHandler.Delete",
+				"GET /user/address/:id user AddressHandler.Get",
+				"PUT /user/address/:id user AddressHandler.Update",
+				"GET /user/user user UserHandler.List",
+				"POST /user/user user UserHandler.Create",
+				"DELETE /user/user/:id user UserHandler.Delete",
+				"GET /user/user/:id user UserHandler.Get",
+				"PUT /user/user/:id user UserHandler.Update",
+			}, got)
+		})
+	}
+}
+
+// TestRunRoutes 测试路由表输出、跨模块重复路由、动态路径以及 --strict 与 --json
+func TestRunRoutes(t *testing.T) {
+	useLang(t, langEn)
+	setupRoutesProject(t)
+
+	t.Run("table", func(t *testing.T) {
+		out, err := runRoutesArgs(t)
+		require.NoError(t, err)
+		lines := strings.Split(out, "\n")
+		assert.Regexp(t, `^METHOD\s+PATH\s+MODULE\s+HANDLER$`, lines[0])
+		assert.Contains(t, out, "2 routes are registered by more than one module")
+
+		var conflicts, dynamic []string
+		for _, line := range lines {
+			if strings.Contains(line, "GET") && strings.Contains(line, "/user/user ") {
+				conflicts = append(conflicts, strings.Join(strings.Fields(line), " "))
+			}
+			if strings.Contains(line, dynamicPath) {
+				dynamic = append(dynamic, strings.Join(strings.Fields(line), " "))
+			}
+		}
+		assert.Equal(t, []string{
+			"GET /user/user order LegacyHandler.List",
+			"GET /user/user user UserHandler.List",
+		}, conflicts)
+		assert.Equal(t, []string{
+			"GET <dynamic> (internal/order/api/legacy.go:15) order LegacyHandler.List",
+		}, dynamic)
+		// 分组传给模块内的其他函数时保留前缀
+		assert.Regexp(t, `POST\s+/user/user/v2/import/\s+order\s+func literal`, out)
+		// 输出不是终端时不着色
+		assert.NotContains(t, out, "\x1b[")
+	})
+
+	t.Run("strict", func(t *testing.T) {
+		out, err := runRoutesArgs(t, "--strict")
+		assert.Equal(t, string(msgRoutesConflict), errorID(err))
+		assert.Contains(t, err.Error(), "2 routes are registered by more than one module")
+		assert.Contains(t, out, "/user/user")
+	})
+
+	t.Run("json", func(t *testing.T) {
+		out, err := runRoutesArgs(t, "--json")
+		require.NoError(t, err)
+		var routes []routeEntry
+		require.NoError(t, json.Unmarshal([]byte(out), &routes))
+		assert.Len(t, routes, 13)
+
+		var duplicates []string
+		for _, r := range routes {
+			if r.Duplicate {
+				duplicates = append(duplicates, r.Module+" "+r.Handler)
+			}
+		}
+		assert.Equal(t, []string{"order LegacyHandler.List", "user UserHandler.List"}, duplicates)
+	})
+}
+
+// TestRunRoutes_NoConflict 测试没有重复路由时 --strict 正常退出
+func TestRunRoutes_NoConflict(t *testing.T) {
+	useLang(t, langEn)
+	root := setupRoutesProject(t)
+	require.NoError(t, os.Remove(filepath.Join(root, "internal", "order", "api", "legacy.go")))
+
+	out, err := runRoutesArgs(t, "--strict")
+	require.NoError(t, err)
+	assert.NotContains(t, out, "more than one module")
+	assert.Contains(t, out, "/order/order/:id")
+}
+
+// TestWriteRoutesTable_Color 测试着色时只有重复路由所在的行为红色
+func TestWriteRoutesTable_Color(t *testing.T) {
+	var out bytes.Buffer
+	writeRoutesTable(&out, []routeEntry{
+		{Method: "GET", Path: "/a", Module: "x", Handler: "A.Get"},
+		{Method: "GET", Path: "/b", Module: "x", Handler: "B.Get", Duplicate: true},
+	}, true)
+	lines := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+	require.Len(t, lines, 3)
+	assert.NotContains(t, lines[1], "\x1b[31m")
+	assert.True(t, strings.HasPrefix(lines[2], "\x1b[31mGET"))
+	assert.True(t, strings.HasSuffix(lines[2], "\x1b[0m"))
+}
+
+// TestJoinRoutePath 测试分组前缀与相对路径的拼接与 gin 一致
+func TestJoinRoutePath(t *testing.T) {
+	tests := []struct{ base, rel, want string }{
+		{"/", "", "/"},
+		{"/", "/user", "/user"},
+		{"/user", "", "/user"},
+		{"/user", "/:id", "/user/:id"},
+		{"/user", "list/", "/user/list/"},
+		{"/user/", "/", "/user/"},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, joinRoutePath(tt.base, tt.rel), "%q + %q", tt.base, tt.rel)
+	}
+}
@@ -0,0 +1,108 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// frameworkModule is the module path of the drugo framework.
+const frameworkModule = "github.com/qq1060656096/drugo"
+
+// ErrModulesUnavailable is returned by VerifyProject in offline mode when the go command
+// failed because a required module is neither in the module cache nor replaced locally.
+var ErrModulesUnavailable = errors.New("modules unavailable offline")
+
+// resolutionFailures are go command messages that mean a module could not be resolved
+// without network access, as opposed to a compile error in the project.
+var resolutionFailures = []string{
+	"GOPROXY=off",
+	"missing go.sum entry",
+	"cannot find module providing package",
+	"no required module provides package",
+}
+
+// VerifyOptions configures VerifyProject.
+type VerifyOptions struct {
+	// ReplaceLocal, when set, adds a replace directive pointing the drugo module
+	// at this local checkout, so templates can be verified against unreleased framework changes.
+	ReplaceLocal string
+	// Args are the go command arguments used for verification, default "vet ./...".
+	Args []string
+	// Output receives the streamed go command output; nil discards it.
+	Output io.Writer
+	// Offline runs the go command with GOPROXY=off so nothing is downloaded. Failures caused by
+	// modules missing from the module cache wrap ErrModulesUnavailable.
+	Offline bool
+}
+
+// VerifyProject checks that the generated project in dir compiles by running
+// `go vet ./...` (or opts.Args) with GOFLAGS=-mod=mod, so missing dependencies
+// are resolved and recorded in go.mod/go.sum.
+func VerifyProject(ctx context.Context, dir string, opts VerifyOptions) error {
+	var env []string
+	if opts.Offline {
+		env = []string{"GOPROXY=off"}
+	}
+	out := opts.Output
+	if out == nil {
+		out = io.Discard
+	}
+
+	if opts.ReplaceLocal != "" {
+		local, err := filepath.Abs(opts.ReplaceLocal)
+		if err != nil {
+			return err
+		}
+		if _, err := os.Stat(filepath.Join(local, "go.mod")); err != nil {
+			return fmt.Errorf("replace-local %s: %w", local, err)
+		}
+		if err := runGo(ctx, dir, out, env, "mod", "edit", "-replace", frameworkModule+"="+local); err != nil {
+			return err
+		}
+	}
+
+	args := opts.Args
+	if len(args) == 0 {
+		args = []string{"vet", "./..."}
+	}
+	if !opts.Offline {
+		return runGo(ctx, dir, out, env, args...)
+	}
+	var captured bytes.Buffer
+	err := runGo(ctx, dir, io.MultiWriter(out, &captured), env, args...)
+	if err != nil && isResolutionFailure(captured.String()) {
+		return fmt.Errorf("%w: %w", ErrModulesUnavailable, err)
+	}
+	return err
+}
+
+// isResolutionFailure reports whether the go command output shows a module resolution failure.
+func isResolutionFailure(output string) bool {
+	for _, s := range resolutionFailures {
+		if strings.Contains(output, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// runGo runs the go command in dir with GOFLAGS=-mod=mod, GOWORK=off and env added to the environment,
+// streaming its output to out.
+func runGo(ctx context.Context, dir string, out io.Writer, env []string, args ...string) error {
+	c := exec.CommandContext(ctx, "go", args...)
+	c.Dir = dir
+	c.Env = append(append(os.Environ(), "GOFLAGS=-mod=mod", "GOWORK=off"), env...)
+	c.Stdout = out
+	c.Stderr = out
+	if err := c.Run(); err != nil {
+		return fmt.Errorf("go %v: %w", args, err)
+	}
+	return nil
+}
@@ -0,0 +1,125 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/qq1060656096/drugo/pkg/gomod"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// frameworkRoot returns the local drugo checkout this test runs in.
+func frameworkRoot(t *testing.T) string {
+	t.Helper()
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	root := gomod.ProjectRoot(wd)
+	require.NotEmpty(t, root)
+	return root
+}
+
+// TestVerifyProject_Templates compiles generated projects against the local framework checkout,
+// so template drift shows up as a test failure. It needs the module cache or network access.
+func TestVerifyProject_Templates(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping template verification in short mode")
+	}
+	local := frameworkRoot(t)
+
+	tests := []struct {
+		name    string
+		modules func(t *testing.T, root string)
+	}{
+		{name: "project"},
+		{
+			name: "project with modules",
+			modules: func(t *testing.T, root string) {
+				require.NoError(t, createModule(root, "github.com/acme/app", "user", layouts[0], dataMemory))
+				require.NoError(t, createWorkerModule(root, "github.com/acme/app", "consumer"))
+				require.NoError(t, createGrpcModule(root, "github.com/acme/app", "account", dataMemory))
+			},
+		},
+		{
+			name: "project with layout presets",
+			modules: func(t *testing.T, root string) {
+				for _, layout := range layouts {
+					module := strings.ReplaceAll(layout.Name, "-", "")
+					require.NoError(t, createModule(root, "github.com/acme/app", module, layout, dataMemory))
+					require.NoError(t, createModuleApi(io.Discard, root, "github.com/acme/app", module, "item", layout, dataMemory))
+				}
+			},
+		},
+		{
+			name: "project with gorm data layer",
+			modules: func(t *testing.T, root string) {
+				for _, layout := range layouts {
+					module := strings.ReplaceAll(layout.Name, "-", "") + "db"
+					require.NoError(t, createModule(root, "github.com/acme/app", module, layout, dataGorm))
+					require.NoError(t, createModuleApi(io.Discard, root, "github.com/acme/app", module, "item", layout, dataGorm))
+				}
+				require.NoError(t, createGrpcModule(root, "github.com/acme/app", "account", dataGorm))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Chdir(t.TempDir())
+			require.NoError(t, createProject("app", "github.com/acme/app", "v0.0.0"))
+			root, err := filepath.Abs("app")
+			require.NoError(t, err)
+			if tt.modules != nil {
+				tt.modules(t, root)
+			}
+
+			var out bytes.Buffer
+			err = VerifyProject(context.Background(), root, VerifyOptions{ReplaceLocal: local, Output: &out})
+			require.NoError(t, err, out.String())
+		})
+	}
+}
+
+// TestVerifyProject_Failure tests that compile errors are reported with the go command output.
+func TestVerifyProject_Failure(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module example.com/broken\n\ngo 1.25\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "main.go"), []byte("package main\n\nfunc main() { undefined() }\n"), 0644))
+
+	var out bytes.Buffer
+	err := VerifyProject(context.Background(), dir, VerifyOptions{Output: &out})
+	require.Error(t, err)
+	assert.Contains(t, out.String(), "undefined")
+
+	err = VerifyProject(context.Background(), dir, VerifyOptions{ReplaceLocal: filepath.Join(dir, "missing")})
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
+
+// TestRunNew_VerifyFailure tests that a failed verification removes the project unless --keep-on-failure is set.
+func TestRunNew_VerifyFailure(t *testing.T) {
+	useLang(t, langEn)
+	t.Cleanup(func() {
+		projectVerify, projectKeepOnFailure, projectReplaceLocal = false, false, ""
+	})
+
+	for _, keep := range []bool{false, true} {
+		t.Chdir(t.TempDir())
+		projectVerify, projectKeepOnFailure, projectReplaceLocal = true, keep, "does-not-exist"
+
+		var out bytes.Buffer
+		c := &cobra.Command{}
+		c.SetOut(&out)
+		c.SetErr(&out)
+		err := runNew(c, []string{"myapp"})
+		assert.Equal(t, string(msgNewVerifyFailed), errorID(err))
+		assert.Contains(t, out.String(), "Verifying project")
+
+		_, statErr := os.Stat("myapp")
+		assert.Equal(t, keep, statErr == nil, "keep-on-failure=%v", keep)
+	}
+}
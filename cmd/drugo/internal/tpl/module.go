@@ -2,17 +2,15 @@ package tpl
 
 // Module templates for generating CRUD module structure.
 
-const ModuleAPITpl = `package api
+// The @route, @body, @query and @success annotations of the handlers are read by `drugo openapi`.
+const ModuleAPITpl = `package {{.Package}}
 
 import (
 	"net/http"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
-	"{{.ModPath}}/internal/{{.Name}}/biz"
-	"{{.ModPath}}/internal/{{.Name}}/data"
-	"{{.ModPath}}/internal/{{.Name}}/service"
-	"github.com/qq1060656096/drugo/pkg/router"
+	"github.com/qq1060656096/drugo/pkg/router"{{imports "biz" "data" "service"}}
 )
 
 func init() {
@@ -25,15 +23,15 @@ func init() {
 
 // {{.NameTitle}}Handler {{.Name}} API 处理器
 type {{.NameTitle}}Handler struct {
-	svc *service.{{.NameTitle}}Service
+	svc *{{q "service"}}{{.NameTitle}}Service
 }
 
 // New{{.NameTitle}}Handler 创建 {{.NameTitle}}Handler 实例
 func New{{.NameTitle}}Handler() *{{.NameTitle}}Handler {
 	// 依赖注入: data -> biz -> service
-	repo := data.New{{.NameTitle}}Repo()
-	uc := biz.New{{.NameTitle}}Usecase(repo)
-	svc := service.New{{.NameTitle}}Service(uc)
+	repo := {{q "data"}}New{{.NameTitle}}Repo()
+	uc := {{q "biz"}}New{{.NameTitle}}Usecase(repo)
+	svc := {{q "service"}}New{{.NameTitle}}Service(uc)
 	return &{{.NameTitle}}Handler{svc: svc}
 }
 
@@ -50,9 +48,12 @@ func (h *{{.NameTitle}}Handler) RegisterRoutes(r gin.IRouter) {
 }
 
 // Create 创建{{.Name}}
-// POST /{{.Name}}
+//
+// @route POST /{{.Name}}/{{.Name}}
+// @body Create{{.NameTitle}}Request
+// @success 201 {{.NameTitle}}Response
 func (h *{{.NameTitle}}Handler) Create(c *gin.Context) {
-	var req service.Create{{.NameTitle}}Request
+	var req {{q "service"}}Create{{.NameTitle}}Request
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
 			"code":    400,
@@ -75,7 +76,9 @@ func (h *{{.NameTitle}}Handler) Create(c *gin.Context) {
 }
 
 // Get 获取{{.Name}}详情
-// GET /{{.Name}}/:id
+//
+// @route GET /{{.Name}}/{{.Name}}/:id
+// @success 200 {{.NameTitle}}Response
 func (h *{{.NameTitle}}Handler) Get(c *gin.Context) {
 	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
 	if err != nil {
@@ -100,7 +103,10 @@ func (h *{{.NameTitle}}Handler) Get(c *gin.Context) {
 }
 
 // Update 更新{{.Name}}
-// PUT /{{.Name}}/:id
+//
+// @route PUT /{{.Name}}/{{.Name}}/:id
+// @body Update{{.NameTitle}}Request
+// @success 200 {{.NameTitle}}Response
 func (h *{{.NameTitle}}Handler) Update(c *gin.Context) {
 	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
 	if err != nil {
@@ -111,7 +117,7 @@ func (h *{{.NameTitle}}Handler) Update(c *gin.Context) {
 		return
 	}
 
-	var req service.Update{{.NameTitle}}Request
+	var req {{q "service"}}Update{{.NameTitle}}Request
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
 			"code":    400,
@@ -134,7 +140,9 @@ func (h *{{.NameTitle}}Handler) Update(c *gin.Context) {
 }
 
 // Delete 删除{{.Name}}
-// DELETE /{{.Name}}/:id
+//
+// @route DELETE /{{.Name}}/{{.Name}}/:id
+// @success 200
 func (h *{{.NameTitle}}Handler) Delete(c *gin.Context) {
 	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
 	if err != nil {
@@ -157,9 +165,12 @@ func (h *{{.NameTitle}}Handler) Delete(c *gin.Context) {
 }
 
 // List 获取{{.Name}}列表
-// GET /{{.Name}}
+//
+// @route GET /{{.Name}}/{{.Name}}
+// @query List{{.NameTitle}}Request
+// @success 200 List{{.NameTitle}}Response
 func (h *{{.NameTitle}}Handler) List(c *gin.Context) {
-	var req service.List{{.NameTitle}}Request
+	var req {{q "service"}}List{{.NameTitle}}Request
 	if err := c.ShouldBindQuery(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
 			"code":    400,
@@ -183,14 +194,14 @@ func (h *{{.NameTitle}}Handler) List(c *gin.Context) {
 
 // handleError 统一错误处理
 func handleError(c *gin.Context, err error) {
-	if service.IsNotFound(err) {
+	if {{q "service"}}IsNotFound(err) {
 		c.JSON(http.StatusNotFound, gin.H{
 			"code":    404,
 			"message": "not found",
 		})
 		return
 	}
-	if service.IsInvalidParams(err) {
+	if {{q "service"}}IsInvalidParams(err) {
 		c.JSON(http.StatusBadRequest, gin.H{
 			"code":    400,
 			"message": "invalid params",
@@ -204,7 +215,7 @@ func handleError(c *gin.Context, err error) {
 }
 `
 
-const ModuleBizTpl = `package biz
+const ModuleBizTpl = `package {{.Package}}
 
 import (
 	"context"
@@ -297,32 +308,31 @@ func (uc *{{.NameTitle}}Usecase) List(ctx context.Context, page, pageSize int) (
 }
 `
 
-const ModuleDataTpl = `package data
+const ModuleDataTpl = `package {{.Package}}
 
 import (
 	"context"
 	"sync"
-
-	"{{.ModPath}}/internal/{{.Name}}/biz"
+{{imports "biz"}}
 )
 
-// {{.Name}}Repo 实现 biz.{{.NameTitle}}Repo 接口，使用内存存储
+// {{.Name}}Repo 实现 {{q "biz"}}{{.NameTitle}}Repo 接口，使用内存存储
 type {{.Name}}Repo struct {
 	mu    sync.RWMutex
-	items map[int64]*biz.{{.NameTitle}}
+	items map[int64]*{{q "biz"}}{{.NameTitle}}
 	maxID int64
 }
 
 // New{{.NameTitle}}Repo 创建 {{.NameTitle}}Repo 实例
-func New{{.NameTitle}}Repo() biz.{{.NameTitle}}Repo {
+func New{{.NameTitle}}Repo() {{q "biz"}}{{.NameTitle}}Repo {
 	return &{{.Name}}Repo{
-		items: make(map[int64]*biz.{{.NameTitle}}),
+		items: make(map[int64]*{{q "biz"}}{{.NameTitle}}),
 		maxID: 0,
 	}
 }
 
 // Create 创建{{.Name}}
-func (r *{{.Name}}Repo) Create(ctx context.Context, entity *biz.{{.NameTitle}}) (*biz.{{.NameTitle}}, error) {
+func (r *{{.Name}}Repo) Create(ctx context.Context, entity *{{q "biz"}}{{.NameTitle}}) (*{{q "biz"}}{{.NameTitle}}, error) {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
@@ -333,24 +343,24 @@ func (r *{{.Name}}Repo) Create(ctx context.Context, entity *biz.{{.NameTitle}})
 }
 
 // Get 根据 ID 获取{{.Name}}
-func (r *{{.Name}}Repo) Get(ctx context.Context, id int64) (*biz.{{.NameTitle}}, error) {
+func (r *{{.Name}}Repo) Get(ctx context.Context, id int64) (*{{q "biz"}}{{.NameTitle}}, error) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
 	entity, ok := r.items[id]
 	if !ok {
-		return nil, biz.Err{{.NameTitle}}NotFound
+		return nil, {{q "biz"}}Err{{.NameTitle}}NotFound
 	}
 	return entity, nil
 }
 
 // Update 更新{{.Name}}
-func (r *{{.Name}}Repo) Update(ctx context.Context, entity *biz.{{.NameTitle}}) (*biz.{{.NameTitle}}, error) {
+func (r *{{.Name}}Repo) Update(ctx context.Context, entity *{{q "biz"}}{{.NameTitle}}) (*{{q "biz"}}{{.NameTitle}}, error) {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
 	if _, ok := r.items[entity.ID]; !ok {
-		return nil, biz.Err{{.NameTitle}}NotFound
+		return nil, {{q "biz"}}Err{{.NameTitle}}NotFound
 	}
 	r.items[entity.ID] = entity
 	return entity, nil
@@ -362,19 +372,19 @@ func (r *{{.Name}}Repo) Delete(ctx context.Context, id int64) error {
 	defer r.mu.Unlock()
 
 	if _, ok := r.items[id]; !ok {
-		return biz.Err{{.NameTitle}}NotFound
+		return {{q "biz"}}Err{{.NameTitle}}NotFound
 	}
 	delete(r.items, id)
 	return nil
 }
 
 // List 获取{{.Name}}列表
-func (r *{{.Name}}Repo) List(ctx context.Context, page, pageSize int) ([]*biz.{{.NameTitle}}, int64, error) {
+func (r *{{.Name}}Repo) List(ctx context.Context, page, pageSize int) ([]*{{q "biz"}}{{.NameTitle}}, int64, error) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
 	total := int64(len(r.items))
-	items := make([]*biz.{{.NameTitle}}, 0, len(r.items))
+	items := make([]*{{q "biz"}}{{.NameTitle}}, 0, len(r.items))
 	for _, item := range r.items {
 		items = append(items, item)
 	}
@@ -382,7 +392,7 @@ func (r *{{.Name}}Repo) List(ctx context.Context, page, pageSize int) ([]*biz.{{
 	// 简单分页
 	start := (page - 1) * pageSize
 	if start >= len(items) {
-		return []*biz.{{.NameTitle}}{}, total, nil
+		return []*{{q "biz"}}{{.NameTitle}}{}, total, nil
 	}
 	end := start + pageSize
 	if end > len(items) {
@@ -392,13 +402,12 @@ func (r *{{.Name}}Repo) List(ctx context.Context, page, pageSize int) ([]*biz.{{
 }
 `
 
-const ModuleServiceTpl = `package service
+const ModuleServiceTpl = `package {{.Package}}
 
 import (
 	"context"
 	"errors"
-
-	"{{.ModPath}}/internal/{{.Name}}/biz"
+{{imports "biz"}}
 )
 
 // Create{{.NameTitle}}Request 创建{{.Name}}请求
@@ -431,11 +440,11 @@ type List{{.NameTitle}}Response struct {
 
 // {{.NameTitle}}Service {{.Name}}服务
 type {{.NameTitle}}Service struct {
-	uc *biz.{{.NameTitle}}Usecase
+	uc *{{q "biz"}}{{.NameTitle}}Usecase
 }
 
 // New{{.NameTitle}}Service 创建 {{.NameTitle}}Service 实例
-func New{{.NameTitle}}Service(uc *biz.{{.NameTitle}}Usecase) *{{.NameTitle}}Service {
+func New{{.NameTitle}}Service(uc *{{q "biz"}}{{.NameTitle}}Usecase) *{{.NameTitle}}Service {
 	return &{{.NameTitle}}Service{uc: uc}
 }
 
@@ -488,7 +497,7 @@ func (s *{{.NameTitle}}Service) List(ctx context.Context, req *List{{.NameTitle}
 }
 
 // toResponse 转换为响应结构
-func toResponse(entity *biz.{{.NameTitle}}) *{{.NameTitle}}Response {
+func toResponse(entity *{{q "biz"}}{{.NameTitle}}) *{{.NameTitle}}Response {
 	return &{{.NameTitle}}Response{
 		ID:   entity.ID,
 		Name: entity.Name,
@@ -497,11 +506,11 @@ func toResponse(entity *biz.{{.NameTitle}}) *{{.NameTitle}}Response {
 
 // IsNotFound 判断是否为未找到错误
 func IsNotFound(err error) bool {
-	return errors.Is(err, biz.Err{{.NameTitle}}NotFound)
+	return errors.Is(err, {{q "biz"}}Err{{.NameTitle}}NotFound)
 }
 
 // IsInvalidParams 判断是否为参数错误
 func IsInvalidParams(err error) bool {
-	return errors.Is(err, biz.ErrInvalidParams)
+	return errors.Is(err, {{q "biz"}}ErrInvalidParams)
 }
 `
@@ -0,0 +1,149 @@
+package tpl
+
+// ModuleDataGormTpl is the data layer of CRUD modules generated with --data gorm. It is shared by
+// `drugo module new` and `drugo module new-api`, since both implement the same biz repository interface.
+// Every declaration is prefixed with the entity name, so several repositories can live in one package.
+const ModuleDataGormTpl = `package {{.Package}}
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/qq1060656096/drugo-provider/dbsvc"
+	"github.com/qq1060656096/drugo/kernel"
+	"gorm.io/gorm"{{imports "biz"}}
+)
+
+// {{.Name}}Model {{.Name}}数据库模型
+type {{.Name}}Model struct {
+	ID        int64     ` + "`gorm:\"column:id;primaryKey;autoIncrement\"`" + `
+	Name      string    ` + "`gorm:\"column:name;type:varchar(255);not null;default:''\"`" + `
+	CreatedAt time.Time ` + "`gorm:\"column:created_at\"`" + `
+	UpdatedAt time.Time ` + "`gorm:\"column:updated_at\"`" + `
+	// TODO: 与 {{q "biz"}}{{.NameTitle}} 同步添加更多字段
+}
+
+// TableName 返回{{.Name}}的表名
+func ({{.Name}}Model) TableName() string {
+	return "{{.Name}}"
+}
+
+// {{.Name}}FromEntity 将业务实体转换为数据库模型
+func {{.Name}}FromEntity(entity *{{q "biz"}}{{.NameTitle}}) *{{.Name}}Model {
+	return &{{.Name}}Model{
+		ID:   entity.ID,
+		Name: entity.Name,
+	}
+}
+
+// toEntity 将数据库模型转换为业务实体
+func (m *{{.Name}}Model) toEntity() *{{q "biz"}}{{.NameTitle}} {
+	return &{{q "biz"}}{{.NameTitle}}{
+		ID:   m.ID,
+		Name: m.Name,
+	}
+}
+
+// {{.Name}}Repo 实现 {{q "biz"}}{{.NameTitle}}Repo 接口，使用 GORM 存储。
+// 数据库连接在每次调用时通过 ctx 中的内核从 db 服务获取（见 drugo.GinMiddleware），
+// 对应 conf/db.yaml 中的 db.<group>.<name>
+type {{.Name}}Repo struct {
+	group string // 数据库分组
+	name  string // 分组内的数据库实例
+}
+
+// New{{.NameTitle}}Repo 创建 {{.NameTitle}}Repo 实例
+func New{{.NameTitle}}Repo() {{q "biz"}}{{.NameTitle}}Repo {
+	return new{{.NameTitle}}Repo()
+}
+
+// new{{.NameTitle}}Repo 返回使用 default 分组中 default 实例的 {{.Name}}Repo
+func new{{.NameTitle}}Repo() *{{.Name}}Repo {
+	return &{{.Name}}Repo{group: "default", name: "default"}
+}
+
+// Migrate{{.NameTitle}} 根据 {{.Name}}Model 自动迁移表结构，ctx 需要携带内核，
+// 可以在 Boot 完成后或一次性任务中调用，例如 Migrate{{.NameTitle}}(kernel.WithContext(ctx, app))
+func Migrate{{.NameTitle}}(ctx context.Context) error {
+	return new{{.NameTitle}}Repo().Migrate(ctx)
+}
+
+// Migrate 根据 {{.Name}}Model 自动迁移表结构
+func (r *{{.Name}}Repo) Migrate(ctx context.Context) error {
+	return r.db(ctx).AutoMigrate(&{{.Name}}Model{})
+}
+
+// db 返回 ctx 中的内核所绑定的 db 服务的数据库连接
+func (r *{{.Name}}Repo) db(ctx context.Context) *gorm.DB {
+	svc := kernel.MustServiceFromContext[*dbsvc.DbService](ctx, dbsvc.Name)
+	return svc.Manager().MustGroup(r.group).MustGet(ctx, r.name).WithContext(ctx)
+}
+
+// Create 创建{{.Name}}
+func (r *{{.Name}}Repo) Create(ctx context.Context, entity *{{q "biz"}}{{.NameTitle}}) (*{{q "biz"}}{{.NameTitle}}, error) {
+	model := {{.Name}}FromEntity(entity)
+	if err := r.db(ctx).Create(model).Error; err != nil {
+		return nil, err
+	}
+	return model.toEntity(), nil
+}
+
+// Get 根据 ID 获取{{.Name}}
+func (r *{{.Name}}Repo) Get(ctx context.Context, id int64) (*{{q "biz"}}{{.NameTitle}}, error) {
+	var model {{.Name}}Model
+	if err := r.db(ctx).First(&model, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, {{q "biz"}}Err{{.NameTitle}}NotFound
+		}
+		return nil, err
+	}
+	return model.toEntity(), nil
+}
+
+// Update 更新{{.Name}}
+func (r *{{.Name}}Repo) Update(ctx context.Context, entity *{{q "biz"}}{{.NameTitle}}) (*{{q "biz"}}{{.NameTitle}}, error) {
+	model := {{.Name}}FromEntity(entity)
+	err := r.db(ctx).Model(&{{.Name}}Model{ID: model.ID}).Updates(map[string]any{
+		"name": model.Name,
+	}).Error
+	if err != nil {
+		return nil, err
+	}
+	// 取值未变化时受影响的行数同样为 0，因此重新查询，记录不存在时返回 {{q "biz"}}Err{{.NameTitle}}NotFound
+	return r.Get(ctx, model.ID)
+}
+
+// Delete 删除{{.Name}}
+func (r *{{.Name}}Repo) Delete(ctx context.Context, id int64) error {
+	result := r.db(ctx).Delete(&{{.Name}}Model{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return {{q "biz"}}Err{{.NameTitle}}NotFound
+	}
+	return nil
+}
+
+// List 获取{{.Name}}列表
+func (r *{{.Name}}Repo) List(ctx context.Context, page, pageSize int) ([]*{{q "biz"}}{{.NameTitle}}, int64, error) {
+	// Session 使 Count 与 Find 各自从同一个查询条件开始构造语句
+	db := r.db(ctx).Model(&{{.Name}}Model{}).Session(&gorm.Session{})
+
+	var total int64
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var models []*{{.Name}}Model
+	if err := db.Order("id").Offset((page - 1) * pageSize).Limit(pageSize).Find(&models).Error; err != nil {
+		return nil, 0, err
+	}
+	items := make([]*{{q "biz"}}{{.NameTitle}}, 0, len(models))
+	for _, model := range models {
+		items = append(items, model.toEntity())
+	}
+	return items, total, nil
+}
+`
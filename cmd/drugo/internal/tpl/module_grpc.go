@@ -0,0 +1,191 @@
+package tpl
+
+// Module templates for generating gRPC module structure.
+// The biz and data layers reuse ModuleBizTpl and ModuleDataTpl.
+
+const ModuleGrpcProtoTpl = `syntax = "proto3";
+
+package {{.Name}};
+
+option go_package = "{{.ModPath}}/internal/{{.Name}}/proto;{{.Name}}pb";
+
+// {{.NameTitle}}Service {{.Name}} CRUD 服务
+service {{.NameTitle}}Service {
+  rpc Create{{.NameTitle}}(Create{{.NameTitle}}Request) returns ({{.NameTitle}});
+  rpc Get{{.NameTitle}}(Get{{.NameTitle}}Request) returns ({{.NameTitle}});
+  rpc Update{{.NameTitle}}(Update{{.NameTitle}}Request) returns ({{.NameTitle}});
+  rpc Delete{{.NameTitle}}(Delete{{.NameTitle}}Request) returns (Delete{{.NameTitle}}Response);
+  rpc List{{.NameTitle}}(List{{.NameTitle}}Request) returns (List{{.NameTitle}}Response);
+}
+
+// {{.NameTitle}} {{.Name}}实体
+message {{.NameTitle}} {
+  int64 id = 1;
+  string name = 2;
+}
+
+// Create{{.NameTitle}}Request 创建{{.Name}}请求
+message Create{{.NameTitle}}Request {
+  string name = 1;
+}
+
+// Get{{.NameTitle}}Request 获取{{.Name}}请求
+message Get{{.NameTitle}}Request {
+  int64 id = 1;
+}
+
+// Update{{.NameTitle}}Request 更新{{.Name}}请求
+message Update{{.NameTitle}}Request {
+  int64 id = 1;
+  string name = 2;
+}
+
+// Delete{{.NameTitle}}Request 删除{{.Name}}请求
+message Delete{{.NameTitle}}Request {
+  int64 id = 1;
+}
+
+// Delete{{.NameTitle}}Response 删除{{.Name}}响应
+message Delete{{.NameTitle}}Response {}
+
+// List{{.NameTitle}}Request {{.Name}}列表请求
+message List{{.NameTitle}}Request {
+  int32 page = 1;
+  int32 page_size = 2;
+}
+
+// List{{.NameTitle}}Response {{.Name}}列表响应
+message List{{.NameTitle}}Response {
+  int64 total = 1;
+  repeated {{.NameTitle}} list = 2;
+}
+`
+
+const ModuleGrpcGenerateTpl = `// Package {{.Name}}pb 存放由 {{.Name}}.proto 生成的 gRPC 代码。
+//
+// 生成代码需要安装 protoc 以及插件：
+//
+//	go install google.golang.org/protobuf/cmd/protoc-gen-go@latest
+//	go install google.golang.org/grpc/cmd/protoc-gen-go-grpc@latest
+//
+// 然后在项目根目录执行：
+//
+//	go generate ./internal/{{.Name}}/proto
+package {{.Name}}pb
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative {{.Name}}.proto
+`
+
+const ModuleGrpcServerTpl = `//go:build protoc
+
+// 本文件依赖 protoc 生成的 {{.Name}}pb 代码，默认不参与编译。
+// 执行 go generate ./internal/{{.Name}}/proto 生成代码后，删除第一行的构建约束
+// （或使用 go build -tags protoc 编译）即可启用。
+
+package grpc
+
+import (
+	"context"
+	"errors"
+
+	"{{.ModPath}}/internal/{{.Name}}/biz"
+	"{{.ModPath}}/internal/{{.Name}}/data"
+	{{.Name}}pb "{{.ModPath}}/internal/{{.Name}}/proto"
+	"github.com/qq1060656096/drugo/pkg/grpcreg"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func init() {
+	// 自动注册{{.Name}}模块 gRPC 服务
+	grpcreg.Default().Register(Register{{.NameTitle}}Server)
+}
+
+// Register{{.NameTitle}}Server 将 {{.NameTitle}}Server 注册到 s
+func Register{{.NameTitle}}Server(s grpc.ServiceRegistrar) {
+	{{.Name}}pb.Register{{.NameTitle}}ServiceServer(s, New{{.NameTitle}}Server())
+}
+
+// {{.NameTitle}}Server {{.Name}} gRPC 服务实现
+type {{.NameTitle}}Server struct {
+	{{.Name}}pb.Unimplemented{{.NameTitle}}ServiceServer
+	uc *biz.{{.NameTitle}}Usecase
+}
+
+// New{{.NameTitle}}Server 创建 {{.NameTitle}}Server 实例
+func New{{.NameTitle}}Server() *{{.NameTitle}}Server {
+	// 依赖注入: data -> biz
+	repo := data.New{{.NameTitle}}Repo()
+	uc := biz.New{{.NameTitle}}Usecase(repo)
+	return &{{.NameTitle}}Server{uc: uc}
+}
+
+// Create{{.NameTitle}} 创建{{.Name}}
+func (s *{{.NameTitle}}Server) Create{{.NameTitle}}(ctx context.Context, req *{{.Name}}pb.Create{{.NameTitle}}Request) (*{{.Name}}pb.{{.NameTitle}}, error) {
+	entity, err := s.uc.Create(ctx, req.GetName())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return toProto(entity), nil
+}
+
+// Get{{.NameTitle}} 获取{{.Name}}详情
+func (s *{{.NameTitle}}Server) Get{{.NameTitle}}(ctx context.Context, req *{{.Name}}pb.Get{{.NameTitle}}Request) (*{{.Name}}pb.{{.NameTitle}}, error) {
+	entity, err := s.uc.Get(ctx, req.GetId())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return toProto(entity), nil
+}
+
+// Update{{.NameTitle}} 更新{{.Name}}
+func (s *{{.NameTitle}}Server) Update{{.NameTitle}}(ctx context.Context, req *{{.Name}}pb.Update{{.NameTitle}}Request) (*{{.Name}}pb.{{.NameTitle}}, error) {
+	entity, err := s.uc.Update(ctx, req.GetId(), req.GetName())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return toProto(entity), nil
+}
+
+// Delete{{.NameTitle}} 删除{{.Name}}
+func (s *{{.NameTitle}}Server) Delete{{.NameTitle}}(ctx context.Context, req *{{.Name}}pb.Delete{{.NameTitle}}Request) (*{{.Name}}pb.Delete{{.NameTitle}}Response, error) {
+	if err := s.uc.Delete(ctx, req.GetId()); err != nil {
+		return nil, toStatus(err)
+	}
+	return &{{.Name}}pb.Delete{{.NameTitle}}Response{}, nil
+}
+
+// List{{.NameTitle}} 获取{{.Name}}列表
+func (s *{{.NameTitle}}Server) List{{.NameTitle}}(ctx context.Context, req *{{.Name}}pb.List{{.NameTitle}}Request) (*{{.Name}}pb.List{{.NameTitle}}Response, error) {
+	items, total, err := s.uc.List(ctx, int(req.GetPage()), int(req.GetPageSize()))
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	list := make([]*{{.Name}}pb.{{.NameTitle}}, 0, len(items))
+	for _, item := range items {
+		list = append(list, toProto(item))
+	}
+	return &{{.Name}}pb.List{{.NameTitle}}Response{Total: total, List: list}, nil
+}
+
+// toProto 转换为 protobuf 消息
+func toProto(entity *biz.{{.NameTitle}}) *{{.Name}}pb.{{.NameTitle}} {
+	return &{{.Name}}pb.{{.NameTitle}}{
+		Id:   entity.ID,
+		Name: entity.Name,
+	}
+}
+
+// toStatus 将业务错误转换为 gRPC 状态码
+func toStatus(err error) error {
+	switch {
+	case errors.Is(err, biz.Err{{.NameTitle}}NotFound):
+		return status.Error(codes.NotFound, "not found")
+	case errors.Is(err, biz.ErrInvalidParams):
+		return status.Error(codes.InvalidArgument, "invalid params")
+	default:
+		return status.Error(codes.Internal, "internal server error")
+	}
+}
+`
@@ -0,0 +1,72 @@
+package tpl
+
+import (
+	"strings"
+	"testing"
+	"text/template"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestModuleGrpcTpl 测试 grpc 模板的字段替换
+func TestModuleGrpcTpl(t *testing.T) {
+	data := struct {
+		Name      string
+		NameTitle string
+		ModPath   string
+	}{
+		Name:      "account",
+		NameTitle: "Account",
+		ModPath:   "github.com/acme/app",
+	}
+
+	tests := []struct {
+		name     string
+		tpl      string
+		contains []string
+	}{
+		{
+			name: "proto",
+			tpl:  ModuleGrpcProtoTpl,
+			contains: []string{
+				"package account;",
+				`option go_package = "github.com/acme/app/internal/account/proto;accountpb";`,
+				"service AccountService {",
+				"rpc GetAccount(GetAccountRequest) returns (Account);",
+			},
+		},
+		{
+			name:     "generate",
+			tpl:      ModuleGrpcGenerateTpl,
+			contains: []string{"package accountpb", "//go:generate protoc", "account.proto"},
+		},
+		{
+			name: "server",
+			tpl:  ModuleGrpcServerTpl,
+			contains: []string{
+				"//go:build protoc",
+				`accountpb "github.com/acme/app/internal/account/proto"`,
+				"grpcreg.Default().Register(RegisterAccountServer)",
+				"accountpb.UnimplementedAccountServiceServer",
+				"codes.NotFound",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpl, err := template.New(tt.name).Parse(tt.tpl)
+			require.NoError(t, err)
+
+			var sb strings.Builder
+			require.NoError(t, tmpl.Execute(&sb, data))
+			out := sb.String()
+
+			assert.NotContains(t, out, "{{")
+			for _, want := range tt.contains {
+				assert.Contains(t, out, want)
+			}
+		})
+	}
+}
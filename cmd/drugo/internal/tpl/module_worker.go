@@ -0,0 +1,119 @@
+package tpl
+
+// Module worker templates for generating background worker (kernel.Runner) module structure.
+
+const ModuleWorkerTpl = `package worker
+
+import (
+	"context"
+	"time"
+
+	"{{.ModPath}}/internal/{{.Name}}/biz"
+	"github.com/qq1060656096/drugo/kernel"
+	"go.uber.org/zap"
+)
+
+// Name {{.Name}} worker 的服务名称，同时也是配置段和日志业务名称
+const Name = "{{.Name}}"
+
+// DefaultInterval 默认的任务轮询间隔
+const DefaultInterval = 5 * time.Second
+
+var (
+	_ kernel.Runner      = (*{{.NameTitle}}Worker)(nil)
+	_ kernel.LoggerAware = (*{{.NameTitle}}Worker)(nil)
+)
+
+// Config {{.Name}} worker 配置，对应 conf/{{.Name}}.yaml 中的 {{.Name}} 配置段
+type Config struct {
+	Interval time.Duration ` + "`mapstructure:\"interval\"`" + ` // 任务轮询间隔
+}
+
+// {{.NameTitle}}Worker {{.Name}} 后台任务服务
+type {{.NameTitle}}Worker struct {
+	name   string
+	config Config
+	logger *zap.Logger
+	uc     *biz.{{.NameTitle}}Usecase
+}
+
+// New 创建 {{.NameTitle}}Worker 实例
+func New() *{{.NameTitle}}Worker {
+	return &{{.NameTitle}}Worker{name: Name}
+}
+
+// Name 返回服务名称
+func (w *{{.NameTitle}}Worker) Name() string {
+	return w.name
+}
+
+// SetLogger 接收框架注入的 logger（业务名称为服务名称），在 Boot 之前调用
+func (w *{{.NameTitle}}Worker) SetLogger(l *zap.Logger) {
+	w.logger = l
+}
+
+// Boot 读取配置并初始化依赖
+func (w *{{.NameTitle}}Worker) Boot(ctx context.Context) error {
+	k := kernel.MustFromContext(ctx)
+
+	if err := k.Config().Unmarshal(w.Name(), &w.config); err != nil {
+		return err
+	}
+	if w.config.Interval <= 0 {
+		w.config.Interval = DefaultInterval
+	}
+
+	w.uc = biz.New{{.NameTitle}}Usecase()
+	return nil
+}
+
+// Run 按配置的间隔循环处理任务，直到 ctx 取消
+func (w *{{.NameTitle}}Worker) Run(ctx context.Context) error {
+	ticker := time.NewTicker(w.config.Interval)
+	defer ticker.Stop()
+
+	w.logger.Info("worker started", zap.Duration("interval", w.config.Interval))
+	for {
+		select {
+		case <-ctx.Done():
+			w.logger.Info("worker stopped")
+			return nil
+		case <-ticker.C:
+			if err := w.uc.Process(ctx); err != nil {
+				w.logger.Error("worker process failed", zap.Error(err))
+			}
+		}
+	}
+}
+
+// Close 释放资源
+func (w *{{.NameTitle}}Worker) Close(ctx context.Context) error {
+	return nil
+}
+`
+
+const ModuleWorkerBizTpl = `package biz
+
+import (
+	"context"
+)
+
+// {{.NameTitle}}Usecase {{.Name}} 后台任务业务逻辑
+type {{.NameTitle}}Usecase struct {
+}
+
+// New{{.NameTitle}}Usecase 创建 {{.NameTitle}}Usecase 实例
+func New{{.NameTitle}}Usecase() *{{.NameTitle}}Usecase {
+	return &{{.NameTitle}}Usecase{}
+}
+
+// Process 处理一批任务
+func (uc *{{.NameTitle}}Usecase) Process(ctx context.Context) error {
+	// TODO: 从队列中拉取并处理任务
+	return nil
+}
+`
+
+const ModuleWorkerYamlTpl = `{{.Name}}:
+  interval: 5s # 任务轮询间隔
+`
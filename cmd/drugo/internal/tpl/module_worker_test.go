@@ -0,0 +1,66 @@
+package tpl
+
+import (
+	"strings"
+	"testing"
+	"text/template"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestModuleWorkerTpl 测试 worker 模板的字段替换
+func TestModuleWorkerTpl(t *testing.T) {
+	data := struct {
+		Name      string
+		NameTitle string
+		ModPath   string
+	}{
+		Name:      "consumer",
+		NameTitle: "Consumer",
+		ModPath:   "github.com/acme/app",
+	}
+
+	tests := []struct {
+		name     string
+		tpl      string
+		contains []string
+	}{
+		{
+			name: "worker",
+			tpl:  ModuleWorkerTpl,
+			contains: []string{
+				`"github.com/acme/app/internal/consumer/biz"`,
+				`const Name = "consumer"`,
+				"type ConsumerWorker struct",
+				"biz.NewConsumerUsecase()",
+			},
+		},
+		{
+			name:     "biz",
+			tpl:      ModuleWorkerBizTpl,
+			contains: []string{"type ConsumerUsecase struct", "func NewConsumerUsecase()"},
+		},
+		{
+			name:     "yaml",
+			tpl:      ModuleWorkerYamlTpl,
+			contains: []string{"consumer:\n  interval: 5s"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpl, err := template.New(tt.name).Parse(tt.tpl)
+			require.NoError(t, err)
+
+			var sb strings.Builder
+			require.NoError(t, tmpl.Execute(&sb, data))
+			out := sb.String()
+
+			assert.NotContains(t, out, "{{")
+			for _, want := range tt.contains {
+				assert.Contains(t, out, want)
+			}
+		})
+	}
+}
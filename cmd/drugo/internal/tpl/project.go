@@ -8,7 +8,9 @@ const MainGoTpl = `package main
 
 import (
 	"context"
+	"fmt"
 	"os"
+	"path/filepath"
 	"{{.ModPath}}/configs"
 
 	"github.com/gin-gonic/gin"
@@ -23,7 +25,6 @@ import (
 	drugoConfig "github.com/qq1060656096/drugo/config"
 	"github.com/qq1060656096/drugo/pkg/gomod"
 	"github.com/qq1060656096/drugo/pkg/router"
-	"go.uber.org/zap"
 )
 
 func main() {
@@ -40,34 +41,44 @@ func main() {
 		drugo.WithService(dbsvc.New()),
 		drugo.WithService(redissvc.New()),
 		//drugo.WithService(i18nsvc.New()),
+		// 部署相关的服务可以放在独立的 provider 包中，在 init 里调用 drugo.RegisterProvider 注册，
+		// 并用构建标签控制是否编译进二进制（go build -tags exporter），无需修改 main.go：
+		//
+		//	//go:build exporter
+		//	package exporter
+		//	func init() { drugo.RegisterProvider("exporter", New) }
+		//
+		// 然后在这里启用已编译进来的 provider，"./app providers" 可以列出所有已注册的 provider：
+		//drugo.WithAllRegisteredProviders(),
 	)
 	drugo.SetApp(app)
 	//biapi.Init("public", "test_common")
 	ginService := drugo.MustGetService[*ginsrv.GinService](app, "gin")
 	engine := ginService.Engine()
 
-	// 示例路由
-	router.Default().Register(func(r *gin.Engine) {
-		r.GET("/health", func(c *gin.Context) {
-			app.Logger().MustGet("gin").Info("health", zap.String("url", c.Request.URL.String()))
-			c.JSON(200, gin.H{"status": "ok"})
-		})
-	})
+	// 健康检查路由：/healthz（存活）与 /readyz（就绪）
+	router.RegisterHealth(router.Default(), router.HealthOptions{})
+	// API 文档路由：/docs（Swagger UI）与 /docs/openapi.yaml，文档由 drugo openapi 生成
+	router.RegisterDocs(router.Default(), router.DocsOptions{File: filepath.Join(root, "docs", "openapi.yaml")})
 
 	// 加载应用配置
 	appConfig := drugoConfig.MustConfig[configs.AppConfig](app.Config(), "app")
-	engine.Use(func(c *gin.Context) {
-		c.Set(drugo.Name, app)
+	// 将应用注入 gin 上下文和请求上下文，service/biz/data 层可以通过 ctx 获取服务与 logger
+	engine.Use(app.GinMiddleware(), func(c *gin.Context) {
 		c.Set(configs.AppConfigName, &appConfig)
 		c.Next()
 	})
+	// 模块通过 router.RequireMiddleware 声明的中间件需要在 Setup 之前提供，例如：
+	// router.ProvideMiddleware(router.MiddlewareAuth, auth.Middleware())
 	// 自动注册所有模块路由
 	router.Default().Setup(engine)
 
-	err = app.Serve(ctx)
-	if err != nil {
-		panic(err)
+	// 分发子命令：无参数时启动服务，也支持 routes、config、providers 等内置命令
+	if err := app.Execute(ctx, os.Args); err != nil {
+		fmt.Fprintln(os.Stderr, err)
 	}
+	// 按运行结果设置进程退出码：正常停机 0，启动失败或运行出错 1，停机超时 2（见 drugo.WithExitCodes）
+	drugo.Exit(app)
 }
 `
 
@@ -99,6 +110,8 @@ const GinYamlTpl = `gin:
 
 const LogYamlTpl = `log:
   level: info # 全局日志级别，可选值：debug / info / warn / error / dpanic / panic / fatal
+  stacktrace_level: error # 达到该级别的日志附带 stacktrace 字段，留空不记录堆栈，不受运行时调整日志级别影响
+  development: false # 开发模式，开启后 dpanic 级别的日志会触发 panic
   outputs: # 输出目标列表，可配置多个输出，支持 outputs.console 和 outputs.file
     - type: console        # 控制台输出
       format: text         # 输出格式，可选值：json / text
@@ -110,6 +123,12 @@ const LogYamlTpl = `log:
         max_backups: 10    # 最大保留的旧文件数量
         max_age: 30        # 最大保留天数
         compress: true     # 是否压缩旧日志（gzip）
+  # labels: # 按业务名称为每条日志添加静态字段，"*" 应用于所有业务，同名字段以具体业务为准
+  #   "*":
+  #     service: {{.Name}}
+  #   payments:
+  #     team: payments
+  #     pci: "true"
 `
 
 const GoModTpl = `module {{.ModPath}}
@@ -0,0 +1,66 @@
+package tpl
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"text/template"
+	"time"
+
+	"github.com/qq1060656096/drugo/config"
+	"github.com/qq1060656096/drugo/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ginConfig 对应 GinYamlTpl 中的 gin 配置段
+type ginConfig struct {
+	ShutdownTimeout time.Duration `mapstructure:"shutdown_timeout"`
+	ReadTimeout     time.Duration `mapstructure:"read_timeout"`
+	WriteTimeout    time.Duration `mapstructure:"write_timeout"`
+	IdleTimeout     time.Duration `mapstructure:"idle_timeout"`
+	HTTP            struct {
+		Port int `mapstructure:"port"`
+	} `mapstructure:"http"`
+}
+
+// TestConfigTemplates_Unmarshal 测试项目模板中的配置能被反序列化为带类型的结构体
+func TestConfigTemplates_Unmarshal(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"gin.yaml":    GinYamlTpl,
+		"log.yaml":    LogYamlTpl,
+		"worker.yaml": ModuleWorkerYamlTpl,
+	}
+	for name, content := range files {
+		tmpl, err := template.New(name).Parse(content)
+		require.NoError(t, err)
+		var sb strings.Builder
+		require.NoError(t, tmpl.Execute(&sb, map[string]string{"Name": "worker"}))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(sb.String()), 0644))
+	}
+	m := config.MustNewManager(dir)
+
+	gin := config.MustConfig[ginConfig](m, "gin")
+	assert.Equal(t, 30*time.Second, gin.ShutdownTimeout)
+	assert.Equal(t, 15*time.Second, gin.ReadTimeout)
+	assert.Equal(t, 15*time.Second, gin.WriteTimeout)
+	assert.Equal(t, 60*time.Second, gin.IdleTimeout)
+	assert.Equal(t, 18001, gin.HTTP.Port)
+
+	var logCfg log.Config
+	require.NoError(t, m.Unmarshal("log", &logCfg))
+	require.Len(t, logCfg.Outputs, 2)
+	file := logCfg.Outputs[1].File
+	require.NotNil(t, file)
+	assert.Equal(t, 100, file.MaxSize)
+	assert.Equal(t, 10, file.MaxBackups)
+	assert.Equal(t, 30, file.MaxAge)
+
+	var worker struct {
+		Interval time.Duration `mapstructure:"interval"`
+	}
+	require.NoError(t, m.Unmarshal("worker", &worker))
+	assert.Equal(t, 5*time.Second, worker.Interval)
+}
@@ -0,0 +1,137 @@
+package tpl
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Kind is the content kind of a template, which decides how `drugo lint-templates` validates its output.
+type Kind int
+
+const (
+	// KindOther templates are only executed.
+	KindOther Kind = iota
+	// KindGo templates must render to Go source that parses and formats stably.
+	KindGo
+	// KindYAML templates must render to valid YAML.
+	KindYAML
+)
+
+// String returns the kind name.
+func (k Kind) String() string {
+	switch k {
+	case KindGo:
+		return "go"
+	case KindYAML:
+		return "yaml"
+	default:
+		return "other"
+	}
+}
+
+// Template is a registered template.
+type Template struct {
+	Name    string
+	Content string
+	Kind    Kind
+}
+
+// Template names. The prefix selects the data a template is executed with:
+// "project/" templates get the project data, "module/", "worker/" and "grpc/" templates
+// get the module data and "module-api/" templates get the module API data. A second extension
+// before ".go" names a variant of a layer template, e.g. "module/data.gorm.go" for --data gorm.
+const (
+	ProjectMain       = "project/main.go"
+	ProjectAppYaml    = "project/conf/app.yaml"
+	ProjectGinYaml    = "project/conf/gin.yaml"
+	ProjectI18nYaml   = "project/conf/i18n.yaml"
+	ProjectLogYaml    = "project/conf/log.yaml"
+	ProjectDbYaml     = "project/conf/db.yaml"
+	ProjectRedisYaml  = "project/conf/redis.yaml"
+	ProjectAppConfig  = "project/configs/app.go"
+	ProjectGoMod      = "project/go.mod"
+	ProjectMakefile   = "project/Makefile"
+	ProjectGitignore  = "project/.gitignore"
+	ProjectReadme     = "project/README.md"
+	ProjectAirToml    = "project/.air.toml"
+	ProjectLocaleEn   = "project/locales/app.en.yml"
+	ProjectLocaleZh   = "project/locales/app.zh.yml"
+	ModuleAPI         = "module/api.go"
+	ModuleBiz         = "module/biz.go"
+	ModuleData        = "module/data.go"
+	ModuleDataGorm    = "module/data.gorm.go"
+	ModuleService     = "module/service.go"
+	ModuleApiAPI      = "module-api/api.go"
+	ModuleApiBiz      = "module-api/biz.go"
+	ModuleApiData     = "module-api/data.go"
+	ModuleApiDataGorm = "module-api/data.gorm.go"
+	ModuleApiService  = "module-api/service.go"
+	WorkerWorker      = "worker/worker.go"
+	WorkerBiz         = "worker/biz.go"
+	WorkerYaml        = "worker/conf.yaml"
+	GrpcProto         = "grpc/proto.proto"
+	GrpcGenerate      = "grpc/generate.go"
+	GrpcServer        = "grpc/server.go"
+)
+
+// Templates maps template names to the registered templates. Generation code looks
+// templates up here (see Get), so every generated file is covered by `drugo lint-templates`.
+var Templates = map[string]Template{}
+
+func init() {
+	for _, t := range []Template{
+		{ProjectMain, MainGoTpl, KindGo},
+		{ProjectAppYaml, AppYamlTpl, KindYAML},
+		{ProjectGinYaml, GinYamlTpl, KindYAML},
+		{ProjectI18nYaml, I18nYamlTpl, KindYAML},
+		{ProjectLogYaml, LogYamlTpl, KindYAML},
+		{ProjectDbYaml, DbYamlTpl, KindYAML},
+		{ProjectRedisYaml, RedisYamlTpl, KindYAML},
+		{ProjectAppConfig, ConfigsAppConfigTpl, KindGo},
+		{ProjectGoMod, GoModTpl, KindOther},
+		{ProjectMakefile, MakefileTpl, KindOther},
+		{ProjectGitignore, GitignoreTpl, KindOther},
+		{ProjectReadme, ReadmeTpl, KindOther},
+		{ProjectAirToml, AirTomlTpl, KindOther},
+		{ProjectLocaleEn, LocaleEnYmlTpl, KindYAML},
+		{ProjectLocaleZh, LocaleZhYmlTpl, KindYAML},
+		{ModuleAPI, ModuleAPITpl, KindGo},
+		{ModuleBiz, ModuleBizTpl, KindGo},
+		{ModuleData, ModuleDataTpl, KindGo},
+		{ModuleDataGorm, ModuleDataGormTpl, KindGo},
+		{ModuleService, ModuleServiceTpl, KindGo},
+		{ModuleApiAPI, ModuleApiApiTpl, KindGo},
+		{ModuleApiBiz, ModuleApiBizTpl, KindGo},
+		{ModuleApiData, ModuleApiDataTpl, KindGo},
+		{ModuleApiDataGorm, ModuleDataGormTpl, KindGo},
+		{ModuleApiService, ModuleApiServiceTpl, KindGo},
+		{WorkerWorker, ModuleWorkerTpl, KindGo},
+		{WorkerBiz, ModuleWorkerBizTpl, KindGo},
+		{WorkerYaml, ModuleWorkerYamlTpl, KindYAML},
+		{GrpcProto, ModuleGrpcProtoTpl, KindOther},
+		{GrpcGenerate, ModuleGrpcGenerateTpl, KindGo},
+		{GrpcServer, ModuleGrpcServerTpl, KindGo},
+	} {
+		Templates[t.Name] = t
+	}
+}
+
+// Get returns the content of the named template. It panics on unknown names,
+// so generation code cannot use a template that is not registered.
+func Get(name string) string {
+	t, ok := Templates[name]
+	if !ok {
+		panic(fmt.Sprintf("tpl: unknown template %q", name))
+	}
+	return t.Content
+}
+
+// Names returns the sorted names of all registered templates.
+func Names() []string {
+	names := make([]string, 0, len(Templates))
+	for name := range Templates {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,24 @@
+package tpl
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestRegistry 测试注册表中的模板名称与内容一致，且名称带有用于选择数据的前缀
+func TestRegistry(t *testing.T) {
+	names := Names()
+	assert.Len(t, names, len(Templates))
+	assert.IsIncreasing(t, names)
+	for _, name := range names {
+		tmpl := Templates[name]
+		assert.Equal(t, name, tmpl.Name)
+		assert.NotEmpty(t, tmpl.Content, name)
+		assert.True(t, strings.Contains(name, "/"), "template %s has no group prefix", name)
+	}
+	assert.Equal(t, MainGoTpl, Get(ProjectMain))
+	assert.Equal(t, "go", KindGo.String())
+	assert.PanicsWithValue(t, `tpl: unknown template "nope"`, func() { Get("nope") })
+}
@@ -0,0 +1,56 @@
+package config
+
+import (
+	"reflect"
+	"sort"
+
+	"github.com/spf13/viper"
+)
+
+// Changes 描述一次配置重载前后业务配置段的变化。
+type Changes struct {
+	Added    []string // 新增的业务配置名称
+	Removed  []string // 被删除的业务配置名称
+	Modified []string // 内容发生变化的业务配置名称
+	Details  []Change // 逐项的变化明细，见 Diff
+}
+
+// Empty 报告本次重载是否没有任何业务配置发生变化。
+func (c Changes) Empty() bool {
+	return len(c.Added) == 0 && len(c.Removed) == 0 && len(c.Modified) == 0
+}
+
+// LastChanges 返回最近一次成功重载的配置变化。
+// 在 OnReload 回调中调用可以得知哪些业务配置被新增、删除或修改，
+// 例如在配置文件被删除后释放对应的资源；Details 给出逐项的变化明细。
+func (m *Manager) LastChanges() Changes {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.lastChanges
+}
+
+// diffSettings 比较两份根配置的顶级业务配置，返回有序的变化列表及逐项的变化明细。
+func diffSettings(before, after *viper.Viper) Changes {
+	old, cur := allSettings(before), allSettings(after)
+
+	c := Changes{Details: Diff(old, cur)}
+	for name, value := range cur {
+		prev, ok := old[name]
+		switch {
+		case !ok:
+			c.Added = append(c.Added, name)
+		case !reflect.DeepEqual(prev, value):
+			c.Modified = append(c.Modified, name)
+		}
+	}
+	for name := range old {
+		if _, ok := cur[name]; !ok {
+			c.Removed = append(c.Removed, name)
+		}
+	}
+
+	sort.Strings(c.Added)
+	sort.Strings(c.Removed)
+	sort.Strings(c.Modified)
+	return c
+}
@@ -0,0 +1,30 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestDiffSettings 测试重载前后业务配置的变化计算。
+func TestDiffSettings(t *testing.T) {
+	before := viper.New()
+	before.Set("app", map[string]any{"name": "a"})
+	before.Set("db", map[string]any{"host": "localhost"})
+	before.Set("cache", map[string]any{"size": 1})
+
+	after := viper.New()
+	after.Set("app", map[string]any{"name": "b"})
+	after.Set("cache", map[string]any{"size": 1})
+	after.Set("queue", map[string]any{"topic": "jobs"})
+
+	c := diffSettings(before, after)
+	assert.Equal(t, []string{"queue"}, c.Added)
+	assert.Equal(t, []string{"db"}, c.Removed)
+	assert.Equal(t, []string{"app"}, c.Modified)
+	assert.False(t, c.Empty())
+
+	assert.True(t, diffSettings(after, after).Empty())
+	assert.Equal(t, []string{"app", "cache", "queue"}, diffSettings(nil, after).Added)
+}
@@ -0,0 +1,73 @@
+package config
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+
+	"github.com/spf13/viper"
+)
+
+// Checksum 返回业务配置 name 的内容校验和（规范化后的配置内容的 SHA-256，十六进制编码）。
+// 校验和在加载及每次成功重载时更新，与键的顺序无关；内容不变时多次重载得到相同的校验和。
+// name 忽略大小写。配置不存在时返回 ErrNotFound。
+func (m *Manager) Checksum(name string) (string, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	sums := m.checksums
+	if sums == nil {
+		sums, _ = computeChecksums(m.root)
+	}
+	sum, ok := sums[canonicalName(name)]
+	if !ok {
+		return "", fmt.Errorf("%w: %q", ErrNotFound, name)
+	}
+	return sum, nil
+}
+
+// ChangedSince 报告业务配置 name 的内容是否与 checksum 对应的内容不同。
+// 适用于自行轮询、按需重建派生状态（例如路由表）的场景，无需注册 OnReload 回调。
+// 配置不存在时返回 ErrNotFound。
+func (m *Manager) ChangedSince(name string, checksum string) (bool, error) {
+	sum, err := m.Checksum(name)
+	if err != nil {
+		return false, err
+	}
+	return sum != checksum, nil
+}
+
+// RootChecksum 返回全部配置内容的校验和，任何业务配置变化都会改变该值。
+func (m *Manager) RootChecksum() string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if m.checksums == nil {
+		_, root := computeChecksums(m.root)
+		return root
+	}
+	return m.rootChecksum
+}
+
+// computeChecksums 计算每个顶层配置及全部配置的校验和。
+func computeChecksums(root *viper.Viper) (map[string]string, string) {
+	settings := root.AllSettings()
+	sums := make(map[string]string, len(settings))
+	for name, value := range settings {
+		sums[name] = checksum(value)
+	}
+	return sums, checksum(settings)
+}
+
+// checksum 计算配置值的 SHA-256。
+// encoding/json 会按键排序递归编码 map，因此结果与 map 的遍历顺序无关。
+func checksum(value any) string {
+	data, err := json.Marshal(value)
+	if err != nil {
+		// 无法编码为 JSON 的值（极少见）使用 fmt 的输出，fmt 同样按键排序打印 map
+		data = fmt.Appendf(nil, "%#v", value)
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
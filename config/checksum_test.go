@@ -0,0 +1,73 @@
+package config
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestManager_Checksum 测试校验和在内容不变时保持稳定，在嵌套值变化时改变
+func TestManager_Checksum(t *testing.T) {
+	tempDir := t.TempDir()
+	createTestFile(t, tempDir, "app.yaml", "app:\n  name: demo\n  db:\n    host: localhost\n    port: 3306\n")
+	createTestFile(t, tempDir, "cache.yaml", "cache:\n  ttl: 60\n")
+	m := MustNewManager(tempDir)
+
+	app, err := m.Checksum("app")
+	require.NoError(t, err)
+	assert.Len(t, app, 64)
+	cache, err := m.Checksum("cache")
+	require.NoError(t, err)
+	root := m.RootChecksum()
+
+	// 内容不变时 Reset 后校验和不变
+	require.NoError(t, m.Reset())
+	got, err := m.Checksum("app")
+	require.NoError(t, err)
+	assert.Equal(t, app, got)
+	assert.Equal(t, root, m.RootChecksum())
+
+	// 键顺序不同但内容相同时校验和相同
+	createTestFile(t, tempDir, "app.yaml", "app:\n  db:\n    port: 3306\n    host: localhost\n  name: demo\n")
+	require.NoError(t, m.Reset())
+	changed, err := m.ChangedSince("app", app)
+	require.NoError(t, err)
+	assert.False(t, changed)
+
+	// 嵌套值变化
+	createTestFile(t, tempDir, "app.yaml", "app:\n  name: demo\n  db:\n    host: localhost\n    port: 3307\n")
+	require.NoError(t, m.Reset())
+	changed, err = m.ChangedSince("app", app)
+	require.NoError(t, err)
+	assert.True(t, changed)
+	assert.NotEqual(t, root, m.RootChecksum())
+
+	// 其他业务配置不受影响
+	changed, err = m.ChangedSince("cache", cache)
+	require.NoError(t, err)
+	assert.False(t, changed)
+
+	_, err = m.Checksum("missing")
+	assert.True(t, IsNotFound(err))
+	_, err = m.ChangedSince("missing", app)
+	assert.True(t, IsNotFound(err))
+}
+
+// BenchmarkManager_Checksum 测试大配置段计算校验和的开销
+func BenchmarkManager_Checksum(b *testing.B) {
+	section := make(map[string]any, 1000)
+	for i := 0; i < 1000; i++ {
+		section[fmt.Sprintf("key_%d", i)] = map[string]any{
+			"enabled": i%2 == 0,
+			"weight":  i,
+			"tags":    []any{"a", "b", "c"},
+		}
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		checksum(section)
+	}
+}
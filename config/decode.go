@@ -0,0 +1,152 @@
+package config
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-viper/mapstructure/v2"
+	"github.com/spf13/viper"
+)
+
+// byteUnits 是字节大小字符串支持的单位，按 1024 进制换算，单位不区分大小写。
+var byteUnits = []struct {
+	suffix string
+	size   int64
+}{
+	{"kib", 1 << 10}, {"mib", 1 << 20}, {"gib", 1 << 30}, {"tib", 1 << 40},
+	{"kb", 1 << 10}, {"mb", 1 << 20}, {"gb", 1 << 30}, {"tb", 1 << 40},
+	{"k", 1 << 10}, {"m", 1 << 20}, {"g", 1 << 30}, {"t", 1 << 40},
+	{"b", 1},
+}
+
+// DecodeHook 返回 Manager 反序列化配置时使用的标准 mapstructure 解码钩子：
+//   - "30s" 等字符串转换为 time.Duration
+//   - RFC3339 字符串转换为 time.Time
+//   - 逗号分隔的字符串转换为切片
+//   - "100MB" 等字节大小字符串转换为整数（按 1024 进制）
+//
+// 直接调用 viper.Viper.Unmarshal 时可通过 viper.DecodeHook(DecodeHook()) 使用同样的规则。
+func DecodeHook() mapstructure.DecodeHookFunc {
+	return mapstructure.ComposeDecodeHookFunc(
+		mapstructure.StringToTimeDurationHookFunc(),
+		mapstructure.StringToTimeHookFunc(time.RFC3339),
+		mapstructure.StringToSliceHookFunc(","),
+		StringToByteSizeHookFunc(),
+	)
+}
+
+// StringToByteSizeHookFunc 返回将 "100MB"、"512k"、"1GiB" 等字节大小字符串转换为整数的解码钩子。
+// 仅作用于带有单位的字符串与整数类型（time.Duration 除外）的目标字段：不带单位的字符串（例如环境变量覆盖的 "512"）
+// 原样交给后续解码按整数解析，保留完整精度。带有单位但无法解析、不是整数字节或超出目标类型范围时返回错误。
+func StringToByteSizeHookFunc() mapstructure.DecodeHookFuncType {
+	durationType := reflect.TypeOf(time.Duration(0))
+	return func(f reflect.Type, t reflect.Type, data any) (any, error) {
+		if f.Kind() != reflect.String || t == durationType {
+			return data, nil
+		}
+		switch t.Kind() {
+		case reflect.Int, reflect.Int32, reflect.Int64, reflect.Uint, reflect.Uint32, reflect.Uint64:
+		default:
+			return data, nil
+		}
+		s := data.(string)
+		if !hasByteUnit(s) {
+			return data, nil
+		}
+		size, ok := parseByteSize(s)
+		if !ok {
+			return nil, fmt.Errorf("invalid byte size %q", s)
+		}
+		target := reflect.New(t).Elem()
+		if (target.CanInt() && target.OverflowInt(size)) || (target.CanUint() && target.OverflowUint(uint64(size))) {
+			return nil, fmt.Errorf("byte size %q overflows %s", s, t)
+		}
+		return size, nil
+	}
+}
+
+// hasByteUnit 报告字符串 s 是否以字节大小单位结尾，例如 "100MB"、"512 k"。
+func hasByteUnit(s string) bool {
+	s = strings.ToLower(strings.TrimSpace(s))
+	for _, u := range byteUnits {
+		if strings.HasSuffix(s, u.suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseByteSize 解析字节大小字符串，例如 "100MB"、"1.5G"、"512"。
+// 整数部分按整数解析以保留完整精度；小数换算后必须是整数字节，结果超出 int64 范围时解析失败。
+func parseByteSize(s string) (int64, bool) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if s == "" {
+		return 0, false
+	}
+	multiplier := int64(1)
+	for _, u := range byteUnits {
+		if strings.HasSuffix(s, u.suffix) {
+			s = strings.TrimSpace(strings.TrimSuffix(s, u.suffix))
+			multiplier = u.size
+			break
+		}
+	}
+	if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+		if n < 0 || n > math.MaxInt64/multiplier {
+			return 0, false
+		}
+		return n * multiplier, true
+	}
+	n, err := strconv.ParseFloat(s, 64)
+	if err != nil || n < 0 {
+		return 0, false
+	}
+	size := n * float64(multiplier)
+	// NaN 与 Trunc 的结果不相等；1<<63 是第一个超出 int64 范围的浮点数
+	if size != math.Trunc(size) || size >= 1<<63 {
+		return 0, false
+	}
+	return int64(size), true
+}
+
+// Unmarshal 使用 DecodeHook 将 v 反序列化到 out。
+// 适用于已经持有 *viper.Viper 的场景，例如 kernel.Configurable 服务的 Configure。
+func Unmarshal(v *viper.Viper, out any) error {
+	return v.Unmarshal(out, viper.DecodeHook(DecodeHook()))
+}
+
+// UnmarshalStrict 与 Unmarshal 相同，但配置中存在 out 未定义的字段时返回错误。
+func UnmarshalStrict(v *viper.Viper, out any) error {
+	return v.UnmarshalExact(out, viper.DecodeHook(DecodeHook()))
+}
+
+// Unmarshal 将业务配置 name 反序列化到 out，使用 DecodeHook 中的标准解码钩子。
+// 推荐使用此方法或 Config 代替直接调用 viper.Viper.Unmarshal，
+// 以保证 "30s"、"100MB" 等字符串在所有反序列化路径上得到一致的解析。
+func (m *Manager) Unmarshal(name string, out any) error {
+	v, err := m.Get(name)
+	if err != nil {
+		return err
+	}
+	if err := m.unmarshal(v, out, false, name); err != nil {
+		return fmt.Errorf("config %q: unmarshal: %w", name, err)
+	}
+	return nil
+}
+
+// UnmarshalStrict 与 Manager.Unmarshal 相同，但配置中存在 out 未定义的字段时返回错误，
+// 可用于发现配置项拼写错误。
+func (m *Manager) UnmarshalStrict(name string, out any) error {
+	v, err := m.Get(name)
+	if err != nil {
+		return err
+	}
+	if err := m.unmarshal(v, out, true, name); err != nil {
+		return fmt.Errorf("config %q: unmarshal: %w", name, err)
+	}
+	return nil
+}
@@ -0,0 +1,120 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// decodeTarget 覆盖所有标准解码钩子的目标结构
+type decodeTarget struct {
+	Timeout  time.Duration `mapstructure:"timeout"`
+	Since    time.Time     `mapstructure:"since"`
+	Hosts    []string      `mapstructure:"hosts"`
+	MaxBody  int64         `mapstructure:"max_body"`
+	Buffer   int           `mapstructure:"buffer"`
+	Attempts int           `mapstructure:"attempts"`
+}
+
+// TestManager_Unmarshal 测试 Manager.Unmarshal 与 Config 使用标准解码钩子
+func TestManager_Unmarshal(t *testing.T) {
+	tempDir := t.TempDir()
+	createTestFile(t, tempDir, "app.yaml", `server:
+  timeout: 30s
+  since: "2024-01-02T03:04:05Z"
+  hosts: "a.example.com,b.example.com"
+  max_body: 100MB
+  buffer: 4k
+  attempts: 3
+  unknown: true
+`)
+	m := MustNewManager(tempDir)
+
+	want := decodeTarget{
+		Timeout:  30 * time.Second,
+		Since:    time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		Hosts:    []string{"a.example.com", "b.example.com"},
+		MaxBody:  100 << 20,
+		Buffer:   4 << 10,
+		Attempts: 3,
+	}
+
+	var got decodeTarget
+	require.NoError(t, m.Unmarshal("server", &got))
+	assert.Equal(t, want, got)
+
+	cfg, err := Config[decodeTarget](m, "server")
+	require.NoError(t, err)
+	assert.Equal(t, want, cfg)
+
+	// 严格模式发现未定义的字段
+	err = m.UnmarshalStrict("server", &got)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unknown")
+
+	assert.True(t, IsNotFound(m.Unmarshal("missing", &got)))
+}
+
+// TestParseByteSize 测试字节大小字符串的解析
+func TestParseByteSize(t *testing.T) {
+	tests := []struct {
+		in   string
+		want int64
+		ok   bool
+	}{
+		{"512", 512, true},
+		{"10b", 10, true},
+		{"100MB", 100 << 20, true},
+		{"1.5 GiB", 3 << 29, true},
+		{"2t", 2 << 40, true},
+		{"", 0, false},
+		{"abc", 0, false},
+		{"-1MB", 0, false},
+		{"9007199254740993b", 1<<53 + 1, true},
+		{"0.5k", 512, true},
+		{"1.5b", 0, false},
+		{"9999999tb", 0, false},
+		{"1e400k", 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := parseByteSize(tt.in)
+		assert.Equal(t, tt.ok, ok, tt.in)
+		assert.Equal(t, tt.want, got, tt.in)
+	}
+}
+
+// TestStringToByteSizeHookFunc 测试只有带单位的字符串被换算，不带单位的字符串按整数解析，超出目标类型范围时报错
+func TestStringToByteSizeHookFunc(t *testing.T) {
+	type target struct {
+		Big   int64  `mapstructure:"big"`
+		Count int    `mapstructure:"count"`
+		Small int32  `mapstructure:"small"`
+		Usize uint32 `mapstructure:"usize"`
+	}
+	decode := func(settings map[string]any) (target, error) {
+		v := viper.New()
+		for k, val := range settings {
+			v.Set(k, val)
+		}
+		var out target
+		err := Unmarshal(v, &out)
+		return out, err
+	}
+
+	// 环境变量覆盖的值总是字符串，大于 2^53 的整数不能丢失精度
+	got, err := decode(map[string]any{"big": "9007199254740993", "small": "1GiB", "usize": "3GiB"})
+	require.NoError(t, err)
+	assert.Equal(t, target{Big: 1<<53 + 1, Small: 1 << 30, Usize: 3 << 30}, got)
+
+	_, err = decode(map[string]any{"count": "1.9"})
+	assert.Error(t, err, "fractional plain numbers are not truncated")
+	_, err = decode(map[string]any{"count": "1.5b"})
+	assert.ErrorContains(t, err, "invalid byte size")
+	_, err = decode(map[string]any{"small": "2GiB"})
+	assert.ErrorContains(t, err, "overflows int32")
+	_, err = decode(map[string]any{"usize": "4GiB"})
+	assert.ErrorContains(t, err, "overflows uint32")
+}
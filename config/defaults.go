@@ -0,0 +1,245 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// ValueOrigin 表示配置项取值的来源，见 Describe。
+type ValueOrigin string
+
+const (
+	// OriginFile 表示取值来自配置，包括配置文件、环境层、额外配置目录与远程配置。
+	OriginFile ValueOrigin = "file"
+	// OriginDefault 表示配置中没有该配置项，取值来自 DeclareDefaults 声明的默认值。
+	OriginDefault ValueOrigin = "default"
+)
+
+// DeclareDefaults 声明业务配置 name 的默认值，键是相对于业务配置的点分路径（例如 "pool.size"），
+// 值为 map[string]any 时按嵌套配置展开，因此 {"pool": {"size": 10}} 与 {"pool.size": 10} 等价。
+//
+// 每次加载（Reset、热加载）时，配置中不存在的配置项会被填入默认值，业务配置整体不存在时同样会被创建，
+// 因此 Get、Config、Root 与 Export 看到的都是包含默认值的生效配置，DeclareSpec 的校验也覆盖默认值。
+// 是否存在按合并后的原始配置数据判断：显式配置的 0、false 或空字符串会被保留，不会被默认值覆盖；
+// 路径中间的配置项不是嵌套配置（例如 pool: 5 与默认值 pool.size）时同样保留显式配置。
+// 通过 Describe 可以区分取值来自配置还是默认值。
+//
+// DeclareDefaults 会立即将默认值应用到当前配置：配置发生变化时作为新的一代生效（Reason 为 ReloadDefaults），
+// 之后按热加载的规则调用 OnReload 回调与 WatchKey 的监听函数，返回它们的错误。
+// 再次调用时替换之前的声明，之前声明的默认值不再生效。
+// 路径为空或包含空的段时返回 ErrInvalidKeyPath，同一配置项重复声明、或同时声明了某个配置项与它的子配置项时
+// 返回 ErrInvalidOption；应用默认值之后不满足 DeclareSpec 的规格时返回 ErrSpecMismatch。这些情况下声明不生效。
+func (m *Manager) DeclareDefaults(name string, defaults map[string]any) error {
+	flat := make(map[string]any)
+	if err := flattenDefaults(name, nil, defaults, flat); err != nil {
+		return err
+	}
+	keys := slices.Sorted(maps.Keys(flat))
+	for i := 1; i < len(keys); i++ {
+		if strings.HasPrefix(keys[i], keys[i-1]+".") {
+			return fmt.Errorf("%w: defaults %s: %q conflicts with %q", ErrInvalidOption, name, keys[i-1], keys[i])
+		}
+	}
+
+	name = canonicalName(name)
+	m.specMu.Lock()
+	if m.defaults == nil {
+		m.defaults = make(map[string]map[string]any)
+	}
+	prev, declared := m.defaults[name]
+	m.defaults[name] = flat
+	m.specMu.Unlock()
+
+	err := m.reapplyDefaults()
+	if err != nil && IsSpecMismatch(err) {
+		m.specMu.Lock()
+		if declared {
+			m.defaults[name] = prev
+		} else {
+			delete(m.defaults, name)
+		}
+		m.specMu.Unlock()
+	}
+	return err
+}
+
+// flattenDefaults 将嵌套的默认值展开为小写的点分路径，写入 flat。
+func flattenDefaults(name string, prefix []string, defaults map[string]any, flat map[string]any) error {
+	for key, value := range defaults {
+		path := append(slices.Clone(prefix), strings.Split(strings.ToLower(key), ".")...)
+		if slices.Contains(path, "") {
+			return fmt.Errorf("%w: defaults %s: %q", ErrInvalidKeyPath, name, key)
+		}
+		if nested, ok := value.(map[string]any); ok && len(nested) > 0 {
+			if err := flattenDefaults(name, path, nested, flat); err != nil {
+				return err
+			}
+			continue
+		}
+		dotted := strings.Join(path, ".")
+		if _, ok := flat[dotted]; ok {
+			return fmt.Errorf("%w: defaults %s: %q is declared twice", ErrInvalidOption, name, dotted)
+		}
+		flat[dotted] = deepCopy(value)
+	}
+	return nil
+}
+
+// applyDefaults 将声明的默认值写入 root 中不存在的配置项，返回每个业务配置中取值来自默认值的配置项。
+// 在 load 中执行，因此每次加载都会重新应用。
+func (m *Manager) applyDefaults(root *viper.Viper) map[string][]string {
+	m.specMu.Lock()
+	defaults := maps.Clone(m.defaults)
+	m.specMu.Unlock()
+
+	defaulted := make(map[string][]string)
+	for _, name := range slices.Sorted(maps.Keys(defaults)) {
+		var section map[string]any
+		if root.IsSet(name) {
+			var ok bool
+			// 复制一份再修改，root 中的取值可能被历史配置引用
+			if section, ok = deepCopy(root.Get(name)).(map[string]any); !ok {
+				continue // 业务配置本身是显式配置的标量值
+			}
+		} else {
+			section = make(map[string]any)
+		}
+
+		var applied []string
+		for _, key := range slices.Sorted(maps.Keys(defaults[name])) {
+			if setMissing(section, strings.Split(key, "."), defaults[name][key]) {
+				applied = append(applied, key)
+			}
+		}
+		if len(applied) > 0 {
+			root.Set(name, section)
+			defaulted[name] = applied
+		}
+	}
+	return defaulted
+}
+
+// setMissing 在 settings 中沿 keys 创建缺少的嵌套配置并写入 value，配置项已经存在时返回 false。
+func setMissing(settings map[string]any, keys []string, value any) bool {
+	for _, key := range keys[:len(keys)-1] {
+		next, ok := settings[key]
+		if !ok {
+			next = make(map[string]any)
+			settings[key] = next
+		}
+		if settings, ok = next.(map[string]any); !ok {
+			return false
+		}
+	}
+	last := keys[len(keys)-1]
+	if _, ok := settings[last]; ok {
+		return false
+	}
+	settings[last] = deepCopy(value)
+	return true
+}
+
+// deleteKey 从 settings 中删除 keys 对应的配置项，并删除因此变为空的嵌套配置。
+func deleteKey(settings map[string]any, keys []string) {
+	if len(keys) > 1 {
+		if nested, ok := settings[keys[0]].(map[string]any); ok {
+			deleteKey(nested, keys[1:])
+			if len(nested) == 0 {
+				delete(settings, keys[0])
+			}
+		}
+		return
+	}
+	delete(settings, keys[0])
+}
+
+// reapplyDefaults 去掉当前配置中来自默认值的配置项，按当前的声明重新应用默认值。
+// 配置发生变化时作为新的一代生效，并调用 OnReload 回调与 WatchKey 的监听函数。
+func (m *Manager) reapplyDefaults() error {
+	m.mu.Lock()
+	before := m.root
+	settings := deepCopy(before.AllSettings()).(map[string]any)
+	for name, keys := range m.defaulted {
+		section, ok := settings[name].(map[string]any)
+		if !ok {
+			continue
+		}
+		for _, key := range keys {
+			deleteKey(section, strings.Split(key, "."))
+		}
+		if len(section) == 0 {
+			delete(settings, name)
+		}
+	}
+	root := viper.New()
+	for name, value := range settings {
+		root.Set(name, value)
+	}
+	defaulted := m.applyDefaults(root)
+	if err := m.checkSpecs(root, m.sources); err != nil {
+		m.mu.Unlock()
+		return err
+	}
+
+	checksums, rootChecksum := computeChecksums(root)
+	if rootChecksum == m.rootChecksum {
+		m.defaulted = defaulted
+		m.mu.Unlock()
+		return nil
+	}
+	m.pushHistoryLocked()
+	m.root = root
+	m.defaulted = defaulted
+	m.configs = make(map[string]*viper.Viper)
+	m.checksums, m.rootChecksum = checksums, rootChecksum
+	m.generation++
+	m.loadedAt = m.clock().Now()
+	m.reason = ReloadDefaults
+	m.restoredFrom = 0
+	m.mu.Unlock()
+
+	err := errors.Join(m.notifyReload(before)...)
+	m.setLastReloadError(err)
+	return err
+}
+
+// Describe 返回业务配置 name 中每个配置项取值的来源，键是小写的点分路径，只包含叶子配置项。
+// name 忽略大小写，业务配置不存在时返回 ErrNotFound。
+func (m *Manager) Describe(name string) (map[string]ValueOrigin, error) {
+	if m == nil {
+		return nil, fmt.Errorf("%w: %q", ErrNotFound, name)
+	}
+	name = canonicalName(name)
+	m.mu.RLock()
+	root, defaulted := m.root, m.defaulted[name]
+	m.mu.RUnlock()
+	if !root.IsSet(name) {
+		return nil, fmt.Errorf("%w: %q", ErrNotFound, name)
+	}
+
+	origins := make(map[string]ValueOrigin)
+	if section, ok := root.Get(name).(map[string]any); ok {
+		describeLeaves(section, "", origins)
+	}
+	for _, key := range defaulted {
+		origins[key] = OriginDefault
+	}
+	return origins, nil
+}
+
+// describeLeaves 将 settings 中的叶子配置项记为 OriginFile。
+func describeLeaves(settings map[string]any, prefix string, origins map[string]ValueOrigin) {
+	for key, value := range settings {
+		path := prefix + key
+		if nested, ok := value.(map[string]any); ok && len(nested) > 0 {
+			describeLeaves(nested, path+".", origins)
+			continue
+		}
+		origins[path] = OriginFile
+	}
+}
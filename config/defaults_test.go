@@ -0,0 +1,108 @@
+package config
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestManager_DeclareDefaults 测试缺少的配置项填入默认值，显式配置的零值保留，并通过 Describe 区分来源
+func TestManager_DeclareDefaults(t *testing.T) {
+	dir := t.TempDir()
+	createTestFile(t, dir, "db.yaml", `db:
+  host: localhost
+  pool_size: 0
+  debug: false
+`)
+	m, err := NewManager(dir)
+	require.NoError(t, err)
+
+	require.NoError(t, m.DeclareDefaults("DB", map[string]any{
+		"pool_size": 10,
+		"debug":     true,
+		"timeout":   "5s",
+		"retry":     map[string]any{"max": 3, "backoff": "1s"},
+	}))
+	assert.Equal(t, ReloadDefaults, m.ReloadReason())
+
+	db := m.MustGet("db")
+	assert.Equal(t, 0, db.GetInt("pool_size"), "explicit zero is preserved")
+	assert.False(t, db.GetBool("debug"), "explicit false is preserved")
+	assert.Equal(t, "5s", db.GetString("timeout"))
+	assert.Equal(t, 3, db.GetInt("retry.max"))
+	assert.Equal(t, "localhost", m.Root().GetString("db.host"))
+	assert.Equal(t, "1s", m.Root().GetString("db.retry.backoff"))
+
+	// 类型化配置同样包含默认值
+	type dbConfig struct {
+		Host  string
+		Retry struct{ Max int }
+	}
+	cfg, err := Config[dbConfig](m, "db")
+	require.NoError(t, err)
+	assert.Equal(t, 3, cfg.Retry.Max)
+
+	var out bytes.Buffer
+	require.NoError(t, m.Export(&out, "yaml", false))
+	assert.Contains(t, out.String(), "timeout: 5s")
+
+	origins, err := m.Describe("db")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]ValueOrigin{
+		"host":          OriginFile,
+		"pool_size":     OriginFile,
+		"debug":         OriginFile,
+		"timeout":       OriginDefault,
+		"retry.max":     OriginDefault,
+		"retry.backoff": OriginDefault,
+	}, origins)
+}
+
+// TestManager_DeclareDefaults_Reload 测试重载后重新应用默认值，配置文件中新增的配置项覆盖默认值
+func TestManager_DeclareDefaults_Reload(t *testing.T) {
+	dir := t.TempDir()
+	createTestFile(t, dir, "db.yaml", "db:\n  host: localhost\n")
+	m, err := NewManager(dir)
+	require.NoError(t, err)
+	require.NoError(t, m.DeclareDefaults("db", map[string]any{"pool.size": 10}))
+	require.NoError(t, m.DeclareDefaults("cache", map[string]any{"ttl": "1m"}))
+
+	createTestFile(t, dir, "db.yaml", "db:\n  host: db.internal\n")
+	require.NoError(t, m.Reset())
+	assert.Equal(t, "db.internal", m.MustGet("db").GetString("host"))
+	assert.Equal(t, 10, m.MustGet("db").GetInt("pool.size"))
+	assert.Equal(t, "1m", m.MustGet("cache").GetString("ttl"), "missing sections are created from defaults")
+
+	createTestFile(t, dir, "db.yaml", "db:\n  host: db.internal\n  pool:\n    size: 0\n")
+	require.NoError(t, m.Reset())
+	assert.Equal(t, 0, m.MustGet("db").GetInt("pool.size"))
+	origins, err := m.Describe("db")
+	require.NoError(t, err)
+	assert.Equal(t, OriginFile, origins["pool.size"])
+
+	// 再次声明替换之前的默认值
+	require.NoError(t, m.DeclareDefaults("cache", map[string]any{"size": 100}))
+	cache := m.MustGet("cache")
+	assert.False(t, cache.IsSet("ttl"))
+	assert.Equal(t, 100, cache.GetInt("size"))
+}
+
+// TestManager_DeclareDefaults_Invalid 测试无效的声明不生效
+func TestManager_DeclareDefaults_Invalid(t *testing.T) {
+	dir := t.TempDir()
+	createTestFile(t, dir, "app.yaml", "app:\n  name: demo\n")
+	m, err := NewManager(dir)
+	require.NoError(t, err)
+
+	assert.True(t, IsInvalidKeyPath(m.DeclareDefaults("app", map[string]any{"pool..size": 1})))
+	assert.True(t, IsInvalidOption(m.DeclareDefaults("app", map[string]any{"pool": 1, "pool.size": 2})))
+	assert.True(t, IsInvalidOption(m.DeclareDefaults("app", map[string]any{"pool": map[string]any{"size": 1}, "pool.size": 2})))
+
+	require.NoError(t, m.DeclareSpec("app", map[string]Kind{"port": KindInt}))
+	assert.True(t, IsSpecMismatch(m.DeclareDefaults("app", map[string]any{"port": "http"})))
+	assert.False(t, m.MustGet("app").IsSet("port"))
+	require.NoError(t, m.Reset(), "rejected defaults are not kept")
+	assert.Equal(t, uint64(1), m.Generation())
+}
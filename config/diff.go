@@ -0,0 +1,137 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"text/tabwriter"
+)
+
+// ChangeKind 是单个配置项的变化类型。
+type ChangeKind string
+
+const (
+	ChangeAdded    ChangeKind = "added"    // 新增的配置项
+	ChangeRemoved  ChangeKind = "removed"  // 被删除的配置项
+	ChangeModified ChangeKind = "modified" // 值或类型发生变化的配置项
+)
+
+// Change 描述单个配置项的变化。Old 与 New 已按 Redact 的规则脱敏，
+// 新增时 Old 为 nil，删除时 New 为 nil。
+type Change struct {
+	Path string     `json:"path"`          // 点分隔的键路径，例如 "database.primary.host"
+	Kind ChangeKind `json:"kind"`          // 变化类型
+	Old  any        `json:"old,omitempty"` // 变化前的值
+	New  any        `json:"new,omitempty"` // 变化后的值
+}
+
+// Diff 递归比较两份配置，返回从 old 到 new 的变化列表，按键路径逐级排序，结果是确定的。
+// 两边都是 map 的配置项会继续向下比较；列表与其他值作为整体比较，
+// 整个子树新增或删除时只记录一条变化。
+// 键路径中任意一段包含敏感关键字（见 IsSensitiveKey）时，Old 与 New 替换为 RedactedValue，
+// 其余值中的敏感项按 Redact 的规则脱敏。
+func Diff(old, new map[string]any) []Change {
+	var changes []Change
+	diffMaps(nil, old, new, &changes)
+	return changes
+}
+
+// diffMaps 比较 prefix 下的两个 map，并将变化追加到 changes。
+func diffMaps(prefix []string, old, new map[string]any, changes *[]Change) {
+	keys := make([]string, 0, len(old)+len(new))
+	for key := range old {
+		keys = append(keys, key)
+	}
+	for key := range new {
+		if _, ok := old[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		path := append(prefix[:len(prefix):len(prefix)], key)
+		prev, hadPrev := old[key]
+		next, hasNext := new[key]
+		switch {
+		case !hadPrev:
+			*changes = append(*changes, newChange(path, ChangeAdded, nil, next))
+		case !hasNext:
+			*changes = append(*changes, newChange(path, ChangeRemoved, prev, nil))
+		default:
+			prevMap, ok1 := prev.(map[string]any)
+			nextMap, ok2 := next.(map[string]any)
+			if ok1 && ok2 {
+				diffMaps(path, prevMap, nextMap, changes)
+			} else if !reflect.DeepEqual(prev, next) {
+				*changes = append(*changes, newChange(path, ChangeModified, prev, next))
+			}
+		}
+	}
+}
+
+// newChange 创建一条脱敏后的变化记录。
+func newChange(path []string, kind ChangeKind, old, new any) Change {
+	return Change{
+		Path: strings.Join(path, "."),
+		Kind: kind,
+		Old:  redactChangeValue(path, old),
+		New:  redactChangeValue(path, new),
+	}
+}
+
+// redactChangeValue 对键路径 path 上的值 v 脱敏，返回不与配置共享底层 map 与列表的副本。
+func redactChangeValue(path []string, v any) any {
+	if v == nil {
+		return nil
+	}
+	for _, key := range path {
+		if IsSensitiveKey(key) {
+			return RedactedValue
+		}
+	}
+	return redactValue(v)
+}
+
+// DiffAgainstDisk 重新加载配置目录（包括环境层、额外目录与远程配置源），
+// 返回从内存中的根配置到新加载配置的变化，用于在关闭热加载时检测配置漂移。
+// 它不会替换内存中的配置，也不会调用任何回调；加载失败时返回对应的错误。
+func (m *Manager) DiffAgainstDisk() ([]Change, error) {
+	fresh, _, _, err := m.load()
+	if err != nil {
+		return nil, err
+	}
+	return Diff(allSettings(m.Root()), fresh.AllSettings()), nil
+}
+
+// String 以对齐的文本格式渲染所有配置项的变化，每行一项，没有变化时返回 "no changes"。
+func (c Changes) String() string {
+	if len(c.Details) == 0 {
+		return "no changes"
+	}
+	var b strings.Builder
+	w := tabwriter.NewWriter(&b, 0, 4, 2, ' ', 0)
+	for _, change := range c.Details {
+		switch change.Kind {
+		case ChangeAdded:
+			fmt.Fprintf(w, "+\t%s\t%s\n", change.Path, formatChangeValue(change.New))
+		case ChangeRemoved:
+			fmt.Fprintf(w, "-\t%s\t%s\n", change.Path, formatChangeValue(change.Old))
+		default:
+			fmt.Fprintf(w, "~\t%s\t%s -> %s\n", change.Path, formatChangeValue(change.Old), formatChangeValue(change.New))
+		}
+	}
+	w.Flush()
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// formatChangeValue 以 JSON 格式渲染配置值，无法编码时回退到 %v。
+func formatChangeValue(v any) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+	return string(data)
+}
@@ -0,0 +1,135 @@
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestDiff 测试嵌套 map、列表替换、类型变化以及路径的确定顺序。
+func TestDiff(t *testing.T) {
+	old := map[string]any{
+		"app": map[string]any{"name": "a", "port": "8080"},
+		"db": map[string]any{
+			"primary": map[string]any{"host": "db1", "pool": 10},
+			"replica": map[string]any{"host": "db2"},
+		},
+		"features": map[string]any{"regions": []any{"eu", "us"}},
+		"legacy":   map[string]any{"enabled": true},
+	}
+	new := map[string]any{
+		"app": map[string]any{"name": "a", "port": 8080},
+		"db": map[string]any{
+			"primary": map[string]any{"host": "db3", "pool": 10, "timeout": "5s"},
+			"replica": "disabled",
+		},
+		"features": map[string]any{"regions": []any{"eu", "us", "ap"}},
+		"queue":    map[string]any{"topic": "jobs"},
+	}
+
+	assert.Equal(t, []Change{
+		{Path: "app.port", Kind: ChangeModified, Old: "8080", New: 8080},
+		{Path: "db.primary.host", Kind: ChangeModified, Old: "db1", New: "db3"},
+		{Path: "db.primary.timeout", Kind: ChangeAdded, New: "5s"},
+		{Path: "db.replica", Kind: ChangeModified, Old: map[string]any{"host": "db2"}, New: "disabled"},
+		{Path: "features.regions", Kind: ChangeModified, Old: []any{"eu", "us"}, New: []any{"eu", "us", "ap"}},
+		{Path: "legacy", Kind: ChangeRemoved, Old: map[string]any{"enabled": true}},
+		{Path: "queue", Kind: ChangeAdded, New: map[string]any{"topic": "jobs"}},
+	}, Diff(old, new))
+
+	assert.Empty(t, Diff(old, old))
+	assert.Empty(t, Diff(nil, nil))
+}
+
+// TestDiff_Redact 测试敏感配置项在变化明细与渲染结果中被脱敏，且明细不共享配置的底层数据。
+func TestDiff_Redact(t *testing.T) {
+	old := map[string]any{
+		"db":    map[string]any{"password": "old-secret", "host": "db1"},
+		"cache": map[string]any{"nodes": []any{"n1"}},
+	}
+	new := map[string]any{
+		"db":    map[string]any{"password": "new-secret", "host": "db1"},
+		"cache": map[string]any{"nodes": []any{"n1", "n2"}},
+		"mq":    map[string]any{"url": "amqp://mq", "token": "t0k"},
+	}
+
+	changes := Diff(old, new)
+	assert.Equal(t, []Change{
+		{Path: "cache.nodes", Kind: ChangeModified, Old: []any{"n1"}, New: []any{"n1", "n2"}},
+		{Path: "db.password", Kind: ChangeModified, Old: RedactedValue, New: RedactedValue},
+		{Path: "mq", Kind: ChangeAdded, New: map[string]any{"url": "amqp://mq", "token": RedactedValue}},
+	}, changes)
+
+	text := Changes{Details: changes}.String()
+	assert.NotContains(t, text, "secret")
+	assert.NotContains(t, text, "t0k")
+	assert.Equal(t, `~  cache.nodes  ["n1"] -> ["n1","n2"]
+~  db.password  "******" -> "******"
++  mq           {"token":"******","url":"amqp://mq"}`, text)
+	assert.Equal(t, "no changes", Changes{}.String())
+
+	changes[0].New.([]any)[0] = "mutated"
+	assert.Equal(t, "n1", new["cache"].(map[string]any)["nodes"].([]any)[0])
+}
+
+// TestChange_JSON 测试变化明细的 JSON 编码。
+func TestChange_JSON(t *testing.T) {
+	data, err := json.Marshal([]Change{
+		{Path: "app.port", Kind: ChangeModified, Old: "8080", New: 8080},
+		{Path: "queue", Kind: ChangeAdded, New: map[string]any{"topic": "jobs"}},
+		{Path: "legacy.enabled", Kind: ChangeRemoved, Old: false},
+	})
+	require.NoError(t, err)
+	assert.JSONEq(t, `[
+		{"path": "app.port", "kind": "modified", "old": "8080", "new": 8080},
+		{"path": "queue", "kind": "added", "new": {"topic": "jobs"}},
+		{"path": "legacy.enabled", "kind": "removed", "old": false}
+	]`, string(data))
+}
+
+// TestManager_DiffAgainstDisk 测试检测加载之后被修改的配置目录，且不替换内存中的配置。
+func TestManager_DiffAgainstDisk(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "app.yaml")
+	require.NoError(t, os.WriteFile(file, []byte("app:\n  name: a\n  secret: s1\n"), 0644))
+	m := MustNewManager(dir)
+
+	changes, err := m.DiffAgainstDisk()
+	require.NoError(t, err)
+	assert.Empty(t, changes)
+
+	require.NoError(t, os.WriteFile(file, []byte("app:\n  name: b\n  secret: s2\n"), 0644))
+	changes, err = m.DiffAgainstDisk()
+	require.NoError(t, err)
+	assert.Equal(t, []Change{
+		{Path: "app.name", Kind: ChangeModified, Old: "a", New: "b"},
+		{Path: "app.secret", Kind: ChangeModified, Old: RedactedValue, New: RedactedValue},
+	}, changes)
+	assert.Equal(t, "a", m.Root().GetString("app.name"))
+
+	require.NoError(t, os.WriteFile(file, []byte("invalid: yaml: ["), 0644))
+	_, err = m.DiffAgainstDisk()
+	assert.Error(t, err)
+}
+
+// TestManager_LastChanges_Details 测试重载后 LastChanges 携带逐项的变化明细。
+func TestManager_LastChanges_Details(t *testing.T) {
+	dir := t.TempDir()
+	writeFeatures(t, dir, "features:\n  search: v1\n")
+	m := MustNewManager(dir)
+
+	var got Changes
+	m.OnReload(func(m *Manager) error {
+		got = m.LastChanges()
+		return nil
+	})
+	writeFeatures(t, dir, "features:\n  search: v2\n")
+	m.handleReload()
+
+	assert.Equal(t, []string{"features"}, got.Modified)
+	assert.Equal(t, []Change{{Path: "features.search", Kind: ChangeModified, Old: "v1", New: "v2"}}, got.Details)
+}
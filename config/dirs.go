@@ -0,0 +1,106 @@
+package config
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/viper"
+)
+
+// MergeStrategy 决定额外配置目录（见 WithExtraDirs）中的业务配置与之前目录中同名业务配置的合并方式。
+type MergeStrategy int
+
+const (
+	// MergeStrict 不允许不同目录定义同名业务配置，冲突时返回 ErrDuplicateKey，错误中包含两个文件的路径。
+	MergeStrict MergeStrategy = iota
+	// MergeOverride 后加载的目录整体替换同名业务配置。
+	MergeOverride
+	// MergeDeep 后加载的目录深度合并到同名业务配置之上，冲突的键以后加载的目录为准。
+	MergeDeep
+)
+
+// String 返回合并策略的名称。
+func (s MergeStrategy) String() string {
+	switch s {
+	case MergeStrict:
+		return "strict"
+	case MergeOverride:
+		return "override"
+	case MergeDeep:
+		return "deep"
+	default:
+		return fmt.Sprintf("MergeStrategy(%d)", int(s))
+	}
+}
+
+// WithExtraDirs 在主配置目录之后按顺序加载 dirs 中的配置文件（conf.d 模式），
+// 各目录之间按 WithMergeStrategy 设置的策略合并，默认 MergeStrict。
+// 额外目录在主目录及其环境层之后加载，在远程配置层之前；不存在的额外目录会被跳过并输出提示，
+// 主目录仍然必须存在。Watch 同时监听所有在调用时已存在的额外目录，Reset 重新加载所有目录。
+func WithExtraDirs(dirs ...string) Option {
+	return func(o *options) {
+		o.extraDirs = append(o.extraDirs, dirs...)
+	}
+}
+
+// WithMergeStrategy 设置额外配置目录与之前目录之间的合并策略，见 MergeStrategy。
+func WithMergeStrategy(s MergeStrategy) Option {
+	return func(o *options) {
+		o.mergeStrategy = s
+	}
+}
+
+// SourceFile 返回定义业务配置 name 的配置文件路径，name 忽略大小写。
+// 业务配置由多个文件合并而成时（环境层或 MergeDeep），返回优先级最高的文件；
+// 业务配置不存在或只来自远程配置层时返回空字符串和 false。
+func (m *Manager) SourceFile(name string) (string, bool) {
+	if m == nil {
+		return "", false
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	path, ok := m.sources[canonicalName(name)]
+	return path, ok
+}
+
+// extraDirs 返回额外配置目录列表。
+func (m *Manager) extraDirs() []string {
+	if m.opts == nil {
+		return nil
+	}
+	return m.opts.extraDirs
+}
+
+// loadExtraDirs 按顺序加载所有额外配置目录并合并到 root 之上，sources 记录每个业务配置的来源文件。
+func (m *Manager) loadExtraDirs(root *viper.Viper, sources map[string]string) error {
+	for _, dir := range m.extraDirs() {
+		if _, err := os.Stat(dir); os.IsNotExist(err) {
+			m.logger().Printf("config: extra directory %s does not exist, skipped", dir)
+			continue
+		}
+
+		layerSources := make(map[string]string)
+		layer, err := m.loadConfigs(dir, layerSources)
+		if err != nil {
+			return err
+		}
+
+		for name, value := range layer.AllSettings() {
+			if root.IsSet(name) {
+				switch m.opts.mergeStrategy {
+				case MergeStrict:
+					return duplicateKeyError(name, sources[name], layerSources[name])
+				case MergeDeep:
+					base, ok1 := root.Get(name).(map[string]any)
+					override, ok2 := value.(map[string]any)
+					if ok1 && ok2 {
+						value = deepMerge(base, override)
+					}
+				}
+			}
+			root.Set(name, value)
+			sources[name] = layerSources[name]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,118 @@
+package config
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeDirsFixture 写入主目录与两个额外目录，db 在三个目录中都有定义
+func writeDirsFixture(t *testing.T) (primary, extra1, extra2 string) {
+	t.Helper()
+	primary, extra1, extra2 = t.TempDir(), t.TempDir(), t.TempDir()
+	createTestConfigFile(t, primary, "app.yml", map[string]interface{}{
+		"app": map[string]interface{}{"name": "demo"},
+	})
+	createTestConfigFile(t, primary, "db.yml", map[string]interface{}{
+		"db": map[string]interface{}{"host": "db.local", "port": 3306},
+	})
+	createTestConfigFile(t, extra1, "10-db.yml", map[string]interface{}{
+		"db": map[string]interface{}{"host": "db.extra1"},
+	})
+	createTestConfigFile(t, extra2, "20-db.yml", map[string]interface{}{
+		"db": map[string]interface{}{"user": "ops"},
+	})
+	return primary, extra1, extra2
+}
+
+// TestManager_WithExtraDirs_Merge 测试额外目录按顺序合并，后加载的目录优先
+func TestManager_WithExtraDirs_Merge(t *testing.T) {
+	primary, extra1, extra2 := writeDirsFixture(t)
+
+	t.Run("deep", func(t *testing.T) {
+		m, err := NewManager(primary, WithExtraDirs(extra1, extra2), WithMergeStrategy(MergeDeep))
+		require.NoError(t, err)
+
+		db := m.MustGet("db")
+		assert.Equal(t, "db.extra1", db.GetString("host"))
+		assert.Equal(t, 3306, db.GetInt("port"))
+		assert.Equal(t, "ops", db.GetString("user"))
+		assert.Equal(t, []string{"app", "db"}, m.List())
+
+		source, ok := m.SourceFile("db")
+		assert.True(t, ok)
+		assert.Equal(t, filepath.Join(extra2, "20-db.yml"), source)
+		source, _ = m.SourceFile("app")
+		assert.Equal(t, filepath.Join(primary, "app.yml"), source)
+		_, ok = m.SourceFile("missing")
+		assert.False(t, ok)
+	})
+
+	t.Run("override", func(t *testing.T) {
+		m, err := NewManager(primary, WithExtraDirs(extra1, extra2), WithMergeStrategy(MergeOverride))
+		require.NoError(t, err)
+
+		db := m.MustGet("db")
+		assert.Equal(t, "ops", db.GetString("user"))
+		assert.False(t, db.IsSet("host"))
+		assert.False(t, db.IsSet("port"))
+	})
+}
+
+// TestManager_WithExtraDirs_StrictConflict 测试默认严格模式下跨目录的重复业务配置返回错误
+func TestManager_WithExtraDirs_StrictConflict(t *testing.T) {
+	primary, extra1, extra2 := writeDirsFixture(t)
+
+	_, err := NewManager(primary, WithExtraDirs(extra1, extra2))
+	require.Error(t, err)
+	assert.True(t, IsDuplicateKey(err))
+	assert.Contains(t, err.Error(), filepath.Join(primary, "db.yml"))
+	assert.Contains(t, err.Error(), filepath.Join(extra1, "10-db.yml"))
+}
+
+// TestManager_WithExtraDirs_Missing 测试不存在的额外目录被跳过，主目录仍然必须存在
+func TestManager_WithExtraDirs_Missing(t *testing.T) {
+	primary, extra1, _ := writeDirsFixture(t)
+	missing := filepath.Join(t.TempDir(), "conf.d")
+
+	m, err := NewManager(primary, WithExtraDirs(missing, extra1), WithMergeStrategy(MergeDeep))
+	require.NoError(t, err)
+	assert.Equal(t, "db.extra1", m.MustGet("db").GetString("host"))
+	assert.Equal(t, []string{missing, extra1}, m.Options().ExtraDirs)
+
+	_, err = NewManager(missing, WithExtraDirs(extra1))
+	assert.True(t, IsDirRead(err))
+
+	_, err = NewManager(primary, WithExtraDirs(""), WithMergeStrategy(MergeStrategy(9)))
+	assert.True(t, IsInvalidOption(err))
+}
+
+// TestManager_WithExtraDirs_Watch 测试额外目录中的文件变化触发热加载
+func TestManager_WithExtraDirs_Watch(t *testing.T) {
+	primary, extra1, extra2 := writeDirsFixture(t)
+	m, err := NewManager(primary, WithExtraDirs(extra1, extra2), WithMergeStrategy(MergeDeep),
+		WithWatchDebounce(10*time.Millisecond))
+	require.NoError(t, err)
+	require.NoError(t, m.Watch())
+	defer m.StopWatch()
+
+	time.Sleep(100 * time.Millisecond) // 给监听器时间启动
+	createTestConfigFile(t, extra2, "20-db.yml", map[string]interface{}{
+		"db": map[string]interface{}{"user": "admin"},
+	})
+
+	assert.Eventually(t, func() bool {
+		db, err := m.Get("db")
+		return err == nil && db.GetString("user") == "admin" && db.GetString("host") == "db.extra1"
+	}, 2*time.Second, 10*time.Millisecond)
+
+	// Reset 同样重新加载所有目录
+	createTestConfigFile(t, extra1, "10-db.yml", map[string]interface{}{
+		"db": map[string]interface{}{"host": "db.reset"},
+	})
+	require.NoError(t, m.Reset())
+	assert.Equal(t, "db.reset", m.MustGet("db").GetString("host"))
+}
@@ -0,0 +1,120 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// EnvPlaceholder 是环境子目录模式中的环境名称占位符。
+const EnvPlaceholder = "{env}"
+
+// DefaultEnvSubdirPattern 是默认的环境子目录模式，即 <configDir>/<env>。
+const DefaultEnvSubdirPattern = EnvPlaceholder
+
+// WithEnvironment 启用按环境分层的配置。
+// Manager 先加载 configDir 中的基础配置，再加载环境子目录中的配置并深度合并到基础配置之上，
+// 冲突时环境层优先；同一层内的重复业务配置仍然返回 ErrDuplicateKey。
+// pattern 是相对 configDir 的子目录模式，其中 {env} 会被替换为 env，为空时使用 DefaultEnvSubdirPattern。
+// 环境子目录不存在时视为空的环境层。env 为空时不启用分层。
+func WithEnvironment(env, pattern string) Option {
+	return func(o *options) {
+		o.env = env
+		o.envPattern = pattern
+	}
+}
+
+// WithEnvironmentKey 在没有通过 WithEnvironment 指定环境时，从基础配置（configDir 中的文件）的配置项 key
+// （例如 "app.env"）读取环境名称，基础配置与环境层在同一次加载中完成。
+// 环境名称只在 NewManager 中读取一次，之后热加载修改该配置项不会切换环境层；配置项不存在或为空时不启用分层。
+// pattern 与 WithEnvironment 的相同。
+func WithEnvironmentKey(key, pattern string) Option {
+	return func(o *options) {
+		o.envKey = key
+		o.envPattern = pattern
+	}
+}
+
+// validEnvName 报告 env 能否作为环境子目录的名称。
+func validEnvName(env string) bool {
+	return !strings.ContainsAny(env, `/\`) && env != "." && env != ".."
+}
+
+// resolveEnvironment 从基础配置 base 中读取 WithEnvironmentKey 指定的环境名称，只在 NewManager 的加载中调用。
+func (m *Manager) resolveEnvironment(base *viper.Viper) error {
+	env := base.GetString(m.opts.envKey)
+	if !validEnvName(env) {
+		return fmt.Errorf("%w: environment %q from %s is not a directory name", ErrInvalidOption, env, m.opts.envKey)
+	}
+	m.opts.env = env
+	return nil
+}
+
+// Environment 返回当前选择的环境名称，未启用环境分层时返回空字符串。
+func (m *Manager) Environment() string {
+	if m.opts == nil {
+		return ""
+	}
+	return m.opts.env
+}
+
+// envDir 返回环境层配置目录，未启用环境分层时返回空字符串。
+func (m *Manager) envDir() string {
+	env := m.Environment()
+	if env == "" {
+		return ""
+	}
+	pattern := m.opts.envPattern
+	if pattern == "" {
+		pattern = DefaultEnvSubdirPattern
+	}
+	return filepath.Join(m.configDir, strings.ReplaceAll(pattern, EnvPlaceholder, env))
+}
+
+// loadEnvironment 加载环境层配置并深度合并到 root 之上，sources 中的来源更新为环境层文件。
+func (m *Manager) loadEnvironment(root *viper.Viper, sources map[string]string) error {
+	dir := m.envDir()
+	if dir == "" {
+		return nil
+	}
+	if _, err := os.Stat(dir); os.IsNotExist(err) {
+		return nil
+	}
+
+	layerSources := make(map[string]string)
+	layer, err := m.loadConfigs(dir, layerSources)
+	if err != nil {
+		return err
+	}
+
+	for name, value := range layer.AllSettings() {
+		base, ok1 := root.Get(name).(map[string]any)
+		override, ok2 := value.(map[string]any)
+		if ok1 && ok2 {
+			value = deepMerge(base, override)
+		}
+		root.Set(name, value)
+		sources[name] = layerSources[name]
+	}
+	return nil
+}
+
+// deepMerge 将 override 递归合并到 base 的副本中并返回，冲突时 override 优先。
+func deepMerge(base, override map[string]any) map[string]any {
+	result := make(map[string]any, len(base)+len(override))
+	for k, v := range base {
+		result[k] = v
+	}
+	for k, v := range override {
+		b, ok1 := result[k].(map[string]any)
+		o, ok2 := v.(map[string]any)
+		if ok1 && ok2 {
+			v = deepMerge(b, o)
+		}
+		result[k] = v
+	}
+	return result
+}
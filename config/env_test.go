@@ -0,0 +1,182 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeEnvFixture 写入 base + prod 两层配置
+func writeEnvFixture(t *testing.T, prodDir string) string {
+	t.Helper()
+	dir := t.TempDir()
+	createTestConfigFile(t, dir, "app.yml", map[string]interface{}{
+		"app": map[string]interface{}{
+			"name":  "demo",
+			"debug": true,
+		},
+	})
+	createTestConfigFile(t, dir, "db.yml", map[string]interface{}{
+		"db": map[string]interface{}{
+			"host": "localhost",
+			"pool": map[string]interface{}{"max_open": 10, "max_idle": 2},
+		},
+	})
+
+	envDir := filepath.Join(dir, prodDir)
+	require.NoError(t, os.MkdirAll(envDir, 0755))
+	createTestConfigFile(t, envDir, "app.yml", map[string]interface{}{
+		"app": map[string]interface{}{"debug": false},
+	})
+	createTestConfigFile(t, envDir, "db.yml", map[string]interface{}{
+		"db": map[string]interface{}{
+			"host": "db.prod",
+			"pool": map[string]interface{}{"max_open": 100},
+		},
+	})
+	createTestConfigFile(t, envDir, "sentry.yml", map[string]interface{}{
+		"sentry": map[string]interface{}{"dsn": "https://sentry.example.com/1"},
+	})
+	return dir
+}
+
+// TestManager_WithEnvironment 测试环境层深度合并到基础配置之上
+func TestManager_WithEnvironment(t *testing.T) {
+	dir := writeEnvFixture(t, "prod")
+
+	m, err := NewManager(dir, WithEnvironment("prod", ""))
+	require.NoError(t, err)
+	assert.Equal(t, "prod", m.Environment())
+
+	// 标量覆盖
+	app := m.MustGet("app")
+	assert.False(t, app.GetBool("debug"))
+	assert.Equal(t, "demo", app.GetString("name"))
+
+	// 嵌套合并
+	db := m.MustGet("db")
+	assert.Equal(t, "db.prod", db.GetString("host"))
+	assert.Equal(t, 100, db.GetInt("pool.max_open"))
+	assert.Equal(t, 2, db.GetInt("pool.max_idle"))
+
+	// 仅存在于环境层的配置段
+	assert.Equal(t, []string{"app", "db", "sentry"}, m.List())
+	assert.Equal(t, "https://sentry.example.com/1", m.MustGet("sentry").GetString("dsn"))
+
+	// Reset 重新应用同样的分层
+	require.NoError(t, m.Reset())
+	assert.Equal(t, "db.prod", m.MustGet("db").GetString("host"))
+}
+
+// TestManager_WithEnvironment_Pattern 测试自定义环境子目录模式
+func TestManager_WithEnvironment_Pattern(t *testing.T) {
+	dir := writeEnvFixture(t, "env-prod")
+
+	m, err := NewManager(dir, WithEnvironment("prod", "env-{env}"))
+	require.NoError(t, err)
+	assert.Equal(t, "db.prod", m.MustGet("db").GetString("host"))
+}
+
+// TestManager_WithEnvironment_Edges 测试环境层缺失及层内重复的情况
+func TestManager_WithEnvironment_Edges(t *testing.T) {
+	t.Run("environment directory missing", func(t *testing.T) {
+		dir := writeEnvFixture(t, "prod")
+		m, err := NewManager(dir, WithEnvironment("staging", ""))
+		require.NoError(t, err)
+		assert.Equal(t, "staging", m.Environment())
+		assert.Equal(t, "localhost", m.MustGet("db").GetString("host"))
+	})
+
+	t.Run("no environment", func(t *testing.T) {
+		dir := writeEnvFixture(t, "prod")
+		m, err := NewManager(dir)
+		require.NoError(t, err)
+		assert.Empty(t, m.Environment())
+		assert.Equal(t, []string{"app", "db"}, m.List())
+	})
+
+	t.Run("duplicate key within environment layer", func(t *testing.T) {
+		dir := writeEnvFixture(t, "prod")
+		createTestConfigFile(t, filepath.Join(dir, "prod"), "db2.yml", map[string]interface{}{
+			"db": map[string]interface{}{"host": "other"},
+		})
+		_, err := NewManager(dir, WithEnvironment("prod", ""))
+		assert.True(t, IsDuplicateKey(err))
+	})
+}
+
+// TestManager_WithEnvironment_Watch 测试监听覆盖环境层目录
+func TestManager_WithEnvironment_Watch(t *testing.T) {
+	dir := writeEnvFixture(t, "prod")
+	m, err := NewManager(dir, WithEnvironment("prod", ""))
+	require.NoError(t, err)
+	require.NoError(t, m.Watch())
+	defer m.StopWatch()
+
+	time.Sleep(100 * time.Millisecond) // 给监听器时间启动
+	createTestConfigFile(t, filepath.Join(dir, "prod"), "db.yml", map[string]interface{}{
+		"db": map[string]interface{}{"host": "db2.prod"},
+	})
+
+	assert.Eventually(t, func() bool {
+		db, err := m.Get("db")
+		return err == nil && db.GetString("host") == "db2.prod" && db.GetInt("pool.max_idle") == 2
+	}, 2*time.Second, 10*time.Millisecond)
+}
+
+// TestManager_WithEnvironment_WatchCreated 测试 Watch 之后才创建的环境层目录同样被监听
+func TestManager_WithEnvironment_WatchCreated(t *testing.T) {
+	dir := writeEnvFixture(t, "prod")
+	m, err := NewManager(dir, WithEnvironment("staging", ""))
+	require.NoError(t, err)
+	require.NoError(t, m.Watch())
+	defer m.StopWatch()
+
+	time.Sleep(100 * time.Millisecond) // 给监听器时间启动
+	staging := filepath.Join(dir, "staging")
+	require.NoError(t, os.Mkdir(staging, 0755))
+	createTestConfigFile(t, staging, "db.yml", map[string]interface{}{
+		"db": map[string]interface{}{"host": "db.staging"},
+	})
+	assert.Eventually(t, func() bool {
+		return m.MustGet("db").GetString("host") == "db.staging"
+	}, 2*time.Second, 10*time.Millisecond)
+
+	// 目录已经加入监听，之后的修改同样触发重载
+	createTestConfigFile(t, staging, "db.yml", map[string]interface{}{
+		"db": map[string]interface{}{"host": "db2.staging"},
+	})
+	assert.Eventually(t, func() bool {
+		return m.MustGet("db").GetString("host") == "db2.staging"
+	}, 2*time.Second, 10*time.Millisecond)
+}
+
+// TestManager_WithEnvironmentKey 测试从基础配置读取环境名称，WithEnvironment 优先
+func TestManager_WithEnvironmentKey(t *testing.T) {
+	dir := writeEnvFixture(t, "prod")
+	createTestConfigFile(t, dir, "env.yml", map[string]interface{}{
+		"runtime": map[string]interface{}{"env": "prod", "bad": "../prod"},
+	})
+
+	m, err := NewManager(dir, WithEnvironmentKey("runtime.env", ""))
+	require.NoError(t, err)
+	assert.Equal(t, "prod", m.Environment())
+	assert.Equal(t, "db.prod", m.MustGet("db").GetString("host"))
+
+	m, err = NewManager(dir, WithEnvironmentKey("runtime.env", ""), WithEnvironment("staging", ""))
+	require.NoError(t, err)
+	assert.Equal(t, "staging", m.Environment())
+	assert.Equal(t, "localhost", m.MustGet("db").GetString("host"))
+
+	m, err = NewManager(dir, WithEnvironmentKey("runtime.missing", ""))
+	require.NoError(t, err)
+	assert.Empty(t, m.Environment())
+	assert.Equal(t, "localhost", m.MustGet("db").GetString("host"))
+
+	_, err = NewManager(dir, WithEnvironmentKey("runtime.bad", ""))
+	assert.ErrorIs(t, err, ErrInvalidOption)
+}
@@ -1,7 +1,12 @@
 // Package config 提供配置管理功能，支持从 YAML 文件加载多业务配置。
 package config
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+)
 
 // 配置操作的哨兵错误。
 var (
@@ -16,6 +21,42 @@ var (
 
 	// ErrDuplicateKey 表示检测到重复的配置键。
 	ErrDuplicateKey = errors.New("config: duplicate key")
+
+	// ErrRemoteRead 表示读取远程配置源失败。
+	ErrRemoteRead = errors.New("config: remote read failed")
+
+	// ErrCallbackPanic 表示配置重载回调发生了 panic。
+	ErrCallbackPanic = errors.New("config: reload callback panic")
+
+	// ErrEmptyConfig 表示启用 WithRequireNonEmpty 时加载到的配置为空。
+	ErrEmptyConfig = errors.New("config: empty config")
+
+	// ErrInvalidOption 表示传给 NewManager 的选项无效。
+	ErrInvalidOption = errors.New("config: invalid option")
+
+	// ErrWatcherFailed 表示文件监听器意外退出。
+	ErrWatcherFailed = errors.New("config: watcher failed")
+
+	// ErrWatchStopped 表示在 StopWatch 之后调用了 Watch 或 WatchRemote。
+	ErrWatchStopped = errors.New("config: watch stopped")
+
+	// ErrUnsupportedFormat 表示导出格式不受支持。
+	ErrUnsupportedFormat = errors.New("config: unsupported format")
+
+	// ErrSpecMismatch 表示配置项的类型与 DeclareSpec 声明的类型不符且无法无损转换。
+	ErrSpecMismatch = errors.New("config: spec mismatch")
+
+	// ErrKeyCase 表示启用 WithStrictKeyCase 时配置文件包含非小写的顶级键。
+	ErrKeyCase = errors.New("config: top-level key is not lower-case")
+
+	// ErrInvalidKeyPath 表示传给 WatchKey 的键路径为空或包含空的段。
+	ErrInvalidKeyPath = errors.New("config: invalid key path")
+
+	// ErrGenerationNotFound 表示传给 Rollback 的配置代数不在历史记录中。
+	ErrGenerationNotFound = errors.New("config: generation not found")
+
+	// ErrFlagNotFound 表示请求的功能开关不在 flags 配置中。
+	ErrFlagNotFound = errors.New("config: flag not found")
 )
 
 // IsNotFound 判断错误是否为配置不存在错误。
@@ -41,3 +82,159 @@ func IsFileRead(err error) bool {
 func IsDuplicateKey(err error) bool {
 	return errors.Is(err, ErrDuplicateKey)
 }
+
+// IsRemoteRead 判断错误是否为远程配置读取失败错误。
+// 它使用 errors.Is 进行判断，因此可以正确处理包装的错误。
+func IsRemoteRead(err error) bool {
+	return errors.Is(err, ErrRemoteRead)
+}
+
+// IsCallbackPanic 判断错误是否为重载回调 panic 错误。
+// 它使用 errors.Is 进行判断，因此可以正确处理包装的错误。
+func IsCallbackPanic(err error) bool {
+	return errors.Is(err, ErrCallbackPanic)
+}
+
+// IsEmptyConfig 判断错误是否为空配置错误。
+// 它使用 errors.Is 进行判断，因此可以正确处理包装的错误。
+func IsEmptyConfig(err error) bool {
+	return errors.Is(err, ErrEmptyConfig)
+}
+
+// IsInvalidOption 判断错误是否为无效选项错误。
+// 它使用 errors.Is 进行判断，因此可以正确处理包装的错误。
+func IsInvalidOption(err error) bool {
+	return errors.Is(err, ErrInvalidOption)
+}
+
+// IsWatcherFailed 判断错误是否为文件监听器失效错误。
+// 它使用 errors.Is 进行判断，因此可以正确处理包装的错误。
+func IsWatcherFailed(err error) bool {
+	return errors.Is(err, ErrWatcherFailed)
+}
+
+// IsWatchStopped 判断错误是否为监听已停止错误。
+// 它使用 errors.Is 进行判断，因此可以正确处理包装的错误。
+func IsWatchStopped(err error) bool {
+	return errors.Is(err, ErrWatchStopped)
+}
+
+// IsUnsupportedFormat 判断错误是否为导出格式不受支持错误。
+// 它使用 errors.Is 进行判断，因此可以正确处理包装的错误。
+func IsUnsupportedFormat(err error) bool {
+	return errors.Is(err, ErrUnsupportedFormat)
+}
+
+// IsSpecMismatch 判断错误是否为配置类型与声明的规格不符错误。
+// 它使用 errors.Is 进行判断，因此可以正确处理包装的错误。
+func IsSpecMismatch(err error) bool {
+	return errors.Is(err, ErrSpecMismatch)
+}
+
+// IsKeyCase 判断错误是否为顶级键不是小写错误。
+// 它使用 errors.Is 进行判断，因此可以正确处理包装的错误。
+func IsKeyCase(err error) bool {
+	return errors.Is(err, ErrKeyCase)
+}
+
+// IsInvalidKeyPath 判断错误是否为键路径无效错误。
+// 它使用 errors.Is 进行判断，因此可以正确处理包装的错误。
+func IsInvalidKeyPath(err error) bool {
+	return errors.Is(err, ErrInvalidKeyPath)
+}
+
+// IsGenerationNotFound 判断错误是否为配置代数不在历史记录中错误。
+// 它使用 errors.Is 进行判断，因此可以正确处理包装的错误。
+func IsGenerationNotFound(err error) bool {
+	return errors.Is(err, ErrGenerationNotFound)
+}
+
+// IsFlagNotFound 判断错误是否为功能开关不存在错误。
+// 它使用 errors.Is 进行判断，因此可以正确处理包装的错误。
+func IsFlagNotFound(err error) bool {
+	return errors.Is(err, ErrFlagNotFound)
+}
+
+// FileError 是读取或解析单个配置文件失败的错误，记录文件路径以及 YAML 错误中的行号。
+// 它同时包装了 ErrFileRead 与原始错误，可以通过 IsFileRead 判断，并通过 errors.As 获取文件与行号：
+//
+//	var fe *config.FileError
+//	if errors.As(err, &fe) {
+//		fmt.Println(fe.Path(), fe.Line())
+//	}
+type FileError struct {
+	path string
+	line int   // 出错的行号，无法确定时为 0
+	err  error // 原始错误
+}
+
+// yamlLinePattern 匹配 yaml 错误信息中的行号，例如 "yaml: line 12: did not find expected key"
+var yamlLinePattern = regexp.MustCompile(`yaml: line (\d+):`)
+
+// newFileError 创建文件 path 的 FileError，行号从 err 的 YAML 错误信息中解析。
+func newFileError(path string, err error) *FileError {
+	e := &FileError{path: path, err: err}
+	if m := yamlLinePattern.FindStringSubmatch(err.Error()); m != nil {
+		e.line, _ = strconv.Atoi(m[1])
+	}
+	return e
+}
+
+// Path 返回出错的配置文件路径。
+func (e *FileError) Path() string {
+	return e.path
+}
+
+// Line 返回出错的行号，无法确定时返回 0。
+func (e *FileError) Line() int {
+	return e.line
+}
+
+// Cause 返回读取或解析文件时的原始错误。
+func (e *FileError) Cause() error {
+	return e.err
+}
+
+// Error 实现 error 接口，例如 "config: file read failed: conf/app.yaml:12: <原始错误>"。
+func (e *FileError) Error() string {
+	if e.line > 0 {
+		return fmt.Sprintf("%v: %s:%d: %v", ErrFileRead, e.path, e.line, e.err)
+	}
+	return fmt.Sprintf("%v: %s: %v", ErrFileRead, e.path, e.err)
+}
+
+// Unwrap 返回 ErrFileRead 与原始错误。
+func (e *FileError) Unwrap() []error {
+	return []error{ErrFileRead, e.err}
+}
+
+// DuplicateKeyError 是同一个业务配置（顶级键，忽略大小写）被定义了两次的错误，记录两处的原始拼写与文件路径。
+// 它包装了 ErrDuplicateKey，可以通过 IsDuplicateKey 判断，并通过 errors.As 获取两个文件。
+type DuplicateKeyError struct {
+	name                  string // 规范形式的业务配置名称
+	firstKey, secondKey   string // 两处的原始拼写
+	firstFile, secondFile string // 两处所在的文件，同一文件中重复时相同
+}
+
+// Name 返回重复的业务配置名称（小写）。
+func (e *DuplicateKeyError) Name() string {
+	return e.name
+}
+
+// Files 返回先定义与后定义该业务配置的文件，同一文件中重复时两者相同。
+func (e *DuplicateKeyError) Files() (first, second string) {
+	return e.firstFile, e.secondFile
+}
+
+// Error 实现 error 接口。
+func (e *DuplicateKeyError) Error() string {
+	if e.firstFile == e.secondFile {
+		return fmt.Sprintf("%v: %q and %q in %s", ErrDuplicateKey, e.firstKey, e.secondKey, e.firstFile)
+	}
+	return fmt.Sprintf("%v: %q in %s and %q in %s", ErrDuplicateKey, e.firstKey, e.firstFile, e.secondKey, e.secondFile)
+}
+
+// Unwrap 返回 ErrDuplicateKey。
+func (e *DuplicateKeyError) Unwrap() error {
+	return ErrDuplicateKey
+}
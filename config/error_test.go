@@ -3,6 +3,8 @@ package config
 import (
 	"errors"
 	"fmt"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -323,6 +325,53 @@ func TestErrorFunctions_ConcurrentAccess(t *testing.T) {
 	}
 }
 
+// TestFileError 测试语法错误的配置文件返回带路径与行号的 FileError
+func TestFileError(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("app:\n  name: demo\n  port: 8080\n bad\n"), 0644))
+
+	_, err := NewManager(dir)
+	require.Error(t, err)
+	assert.True(t, IsFileRead(err))
+
+	var fe *FileError
+	require.True(t, errors.As(err, &fe))
+	assert.Equal(t, path, fe.Path())
+	assert.Equal(t, 3, fe.Line())
+	assert.Error(t, fe.Cause())
+	assert.Contains(t, err.Error(), path+":3: ")
+}
+
+// TestFileError_NoLine 测试无法确定行号时 Line 返回 0，且错误信息中不包含行号
+func TestFileError_NoLine(t *testing.T) {
+	cause := errors.New("permission denied")
+	fe := newFileError("conf/app.yaml", cause)
+	assert.Equal(t, 0, fe.Line())
+	assert.Equal(t, "config: file read failed: conf/app.yaml: permission denied", fe.Error())
+	assert.ErrorIs(t, fe, ErrFileRead)
+	assert.ErrorIs(t, fe, cause)
+}
+
+// TestDuplicateKeyError 测试跨文件重复定义时错误同时记录两个文件
+func TestDuplicateKeyError(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "a.yaml"), []byte("db:\n  host: a\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "b.yaml"), []byte("DB:\n  host: b\n"), 0644))
+
+	_, err := NewManager(dir)
+	require.Error(t, err)
+	assert.True(t, IsDuplicateKey(err))
+
+	var de *DuplicateKeyError
+	require.True(t, errors.As(err, &de))
+	assert.Equal(t, "db", de.Name())
+	first, second := de.Files()
+	assert.Equal(t, filepath.Join(dir, "a.yaml"), first)
+	assert.Equal(t, filepath.Join(dir, "b.yaml"), second)
+	assert.Equal(t, fmt.Sprintf("config: duplicate key: %q in %s and %q in %s", "db", first, "DB", second), err.Error())
+}
+
 // BenchmarkIsNotFound 基准测试 IsNotFound 函数
 func BenchmarkIsNotFound(b *testing.B) {
 	err := ErrNotFound
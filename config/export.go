@@ -0,0 +1,171 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"go.yaml.in/yaml/v3"
+)
+
+// 导出格式
+const (
+	FormatYAML = "yaml"
+	FormatJSON = "json"
+)
+
+// exportNow 返回导出头部使用的时间，测试中可以替换
+var exportNow = time.Now
+
+// ExportHeader 描述导出内容的来源，YAML 格式写为头部注释，JSON 格式写入 "meta" 字段。
+type ExportHeader struct {
+	Generated    time.Time      `json:"generated"`             // 导出时间
+	ConfigDir    string         `json:"config_dir"`            // 配置目录
+	Environment  string         `json:"environment,omitempty"` // 运行环境，未启用环境分层时为空
+	Redacted     bool           `json:"redacted"`              // 是否已脱敏
+	RootChecksum string         `json:"root_checksum"`         // 全部配置内容的校验和
+	Files        []ExportSource `json:"files"`                 // 各业务配置的来源文件与校验和，按名称排序
+}
+
+// ExportSource 描述一个业务配置的来源文件与内容校验和。
+type ExportSource struct {
+	Name     string `json:"name"`           // 业务配置名称
+	Path     string `json:"path,omitempty"` // 来源文件，无法确定时为空（例如远程配置）
+	Checksum string `json:"checksum"`       // 内容校验和，见 Manager.Checksum
+}
+
+// Export 将进程实际使用的合并后配置（Root().AllSettings()）写入 w，用于问题排查时提供完整配置。
+// format 支持 FormatYAML 与 FormatJSON，两者都按键排序输出，相同配置的输出除导出时间外完全一致。
+// redact 为 true 时按 Redact 的规则替换敏感配置项。
+//
+// YAML 格式以注释形式的头部开头（导出时间、配置目录、来源文件及校验和），其余内容可以直接解析回配置；
+// JSON 不支持注释，输出为 {"meta": 头部, "settings": 配置} 形式的对象。
+func (m *Manager) Export(w io.Writer, format string, redact bool) error {
+	header, settings := m.exportSnapshot(redact)
+
+	var buf bytes.Buffer
+	switch strings.ToLower(format) {
+	case FormatYAML, "yml":
+		writeYAMLHeader(&buf, header)
+		enc := yaml.NewEncoder(&buf)
+		enc.SetIndent(2)
+		if err := enc.Encode(settings); err != nil {
+			return fmt.Errorf("config: export yaml: %w", err)
+		}
+		if err := enc.Close(); err != nil {
+			return fmt.Errorf("config: export yaml: %w", err)
+		}
+	case FormatJSON:
+		enc := json.NewEncoder(&buf)
+		enc.SetIndent("", "  ")
+		doc := struct {
+			Meta     ExportHeader   `json:"meta"`
+			Settings map[string]any `json:"settings"`
+		}{header, settings}
+		if err := enc.Encode(doc); err != nil {
+			return fmt.Errorf("config: export json: %w", err)
+		}
+	default:
+		return fmt.Errorf("%w: '%s' (supported formats: %s, %s)", ErrUnsupportedFormat, format, FormatYAML, FormatJSON)
+	}
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// ExportFile 与 Export 相同，但写入 path：先写入同目录下的临时文件再重命名，
+// 读取方不会看到写了一半的文件。目录不存在时自动创建。
+func (m *Manager) ExportFile(path, format string, redact bool) error {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("config: export file: %w", err)
+	}
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("config: export file: %w", err)
+	}
+	defer os.Remove(tmp.Name()) // 重命名成功后删除会失败，忽略
+
+	if err := m.Export(tmp, format, redact); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("config: export file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("config: export file: %w", err)
+	}
+	if err := os.Chmod(tmp.Name(), 0600); err != nil {
+		return fmt.Errorf("config: export file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("config: export file: %w", err)
+	}
+	return nil
+}
+
+// exportSnapshot 在同一次加载的快照上读取配置、来源文件与校验和
+func (m *Manager) exportSnapshot(redact bool) (ExportHeader, map[string]any) {
+	m.mu.RLock()
+	root := m.root
+	settings := root.AllSettings()
+	sums, rootSum := m.checksums, m.rootChecksum
+	sources := make(map[string]string, len(m.sources))
+	for name, path := range m.sources {
+		sources[name] = path
+	}
+	m.mu.RUnlock()
+
+	if sums == nil {
+		sums, rootSum = computeChecksums(root)
+	}
+
+	header := ExportHeader{
+		Generated:    exportNow().UTC().Truncate(time.Second),
+		ConfigDir:    m.configDir,
+		Environment:  m.Environment(),
+		Redacted:     redact,
+		RootChecksum: rootSum,
+	}
+	names := make([]string, 0, len(settings))
+	for name := range settings {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		header.Files = append(header.Files, ExportSource{Name: name, Path: sources[name], Checksum: sums[name]})
+	}
+
+	if redact {
+		settings = Redact(settings)
+	}
+	return header, settings
+}
+
+// writeYAMLHeader 以 YAML 注释写入导出头部
+func writeYAMLHeader(w io.Writer, h ExportHeader) {
+	fmt.Fprintln(w, "# drugo config export")
+	fmt.Fprintf(w, "# generated: %s\n", h.Generated.Format(time.RFC3339))
+	fmt.Fprintf(w, "# config dir: %s\n", h.ConfigDir)
+	if h.Environment != "" {
+		fmt.Fprintf(w, "# environment: %s\n", h.Environment)
+	}
+	fmt.Fprintf(w, "# redacted: %t\n", h.Redacted)
+	fmt.Fprintf(w, "# root checksum: %s\n", h.RootChecksum)
+	fmt.Fprintln(w, "# files:")
+	for _, f := range h.Files {
+		path := f.Path
+		if path == "" {
+			path = "-"
+		}
+		fmt.Fprintf(w, "#   %s %s sha256:%s\n", f.Name, path, f.Checksum)
+	}
+}
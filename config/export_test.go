@@ -0,0 +1,150 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.yaml.in/yaml/v3"
+)
+
+// newExportTestManager 创建包含敏感配置项的配置管理器
+func newExportTestManager(t *testing.T) (*Manager, string) {
+	t.Helper()
+	dir := t.TempDir()
+	createTestFile(t, dir, "db.yaml", "db:\n  host: localhost\n  port: 3306\n  password: s3cret\n  replicas:\n    - dsn: root:pw@tcp(r1)/app\n      weight: 2\n")
+	createTestFile(t, dir, "app.yaml", "app:\n  name: demo\n  tags: [a, b]\n  debug: true\n")
+	return MustNewManager(dir), dir
+}
+
+// TestManager_Export_YAML 测试 YAML 导出的头部、确定性输出与往返解析
+func TestManager_Export_YAML(t *testing.T) {
+	m, dir := newExportTestManager(t)
+
+	var first, second bytes.Buffer
+	require.NoError(t, m.Export(&first, FormatYAML, false))
+	require.NoError(t, m.Export(&second, FormatYAML, false))
+	assert.Equal(t, stripGenerated(first.String()), stripGenerated(second.String()), "相同配置的输出应该一致")
+
+	out := first.String()
+	assert.True(t, strings.HasPrefix(out, "# drugo config export\n"))
+	assert.Contains(t, out, "# config dir: "+dir)
+	assert.Contains(t, out, "# root checksum: "+m.RootChecksum())
+	appSum, err := m.Checksum("app")
+	require.NoError(t, err)
+	assert.Contains(t, out, "#   app "+filepath.Join(dir, "app.yaml")+" sha256:"+appSum)
+	assert.Less(t, strings.Index(out, "#   app "), strings.Index(out, "#   db "), "文件列表按名称排序")
+	assert.Less(t, strings.Index(out, "\napp:\n"), strings.Index(out, "\ndb:\n"), "顶层键按名称排序")
+
+	var parsed map[string]any
+	require.NoError(t, yaml.Unmarshal(first.Bytes(), &parsed))
+	assert.Equal(t, m.Root().AllSettings(), parsed)
+}
+
+// TestManager_Export_Redact 测试脱敏后 password 与 dsn 等配置项被替换
+func TestManager_Export_Redact(t *testing.T) {
+	m, _ := newExportTestManager(t)
+
+	for _, format := range []string{FormatYAML, FormatJSON} {
+		var buf bytes.Buffer
+		require.NoError(t, m.Export(&buf, format, true))
+		out := buf.String()
+		assert.NotContains(t, out, "s3cret", format)
+		assert.NotContains(t, out, "root:pw", format)
+		assert.Contains(t, out, RedactedValue, format)
+		assert.Contains(t, out, "localhost", format)
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, m.Export(&buf, FormatYAML, true))
+	var parsed map[string]any
+	require.NoError(t, yaml.Unmarshal(buf.Bytes(), &parsed))
+	db := parsed["db"].(map[string]any)
+	assert.Equal(t, RedactedValue, db["password"])
+	assert.Equal(t, map[string]any{"dsn": RedactedValue, "weight": 2}, db["replicas"].([]any)[0])
+}
+
+// TestManager_Export_JSON 测试 JSON 导出的 meta 与 settings
+func TestManager_Export_JSON(t *testing.T) {
+	m, dir := newExportTestManager(t)
+	defer func(now func() time.Time) { exportNow = now }(exportNow)
+	exportNow = func() time.Time { return time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC) }
+
+	var first, second bytes.Buffer
+	require.NoError(t, m.Export(&first, "JSON", false))
+	require.NoError(t, m.Export(&second, FormatJSON, false))
+	assert.Equal(t, first.String(), second.String())
+
+	var doc struct {
+		Meta     ExportHeader   `json:"meta"`
+		Settings map[string]any `json:"settings"`
+	}
+	require.NoError(t, json.Unmarshal(first.Bytes(), &doc))
+	assert.Equal(t, dir, doc.Meta.ConfigDir)
+	assert.Equal(t, "2026-01-02T03:04:05Z", doc.Meta.Generated.Format(time.RFC3339))
+	require.Len(t, doc.Meta.Files, 2)
+	assert.Equal(t, "app", doc.Meta.Files[0].Name)
+	assert.Equal(t, filepath.Join(dir, "db.yaml"), doc.Meta.Files[1].Path)
+	assert.Equal(t, "demo", doc.Settings["app"].(map[string]any)["name"])
+}
+
+// TestManager_Export_UnsupportedFormat 测试不支持的格式
+func TestManager_Export_UnsupportedFormat(t *testing.T) {
+	m, _ := newExportTestManager(t)
+	var buf bytes.Buffer
+	err := m.Export(&buf, "toml", false)
+	assert.True(t, IsUnsupportedFormat(err))
+	assert.Zero(t, buf.Len(), "出错时不应写入任何内容")
+}
+
+// TestManager_ExportFile 测试写入文件并且不留下临时文件
+func TestManager_ExportFile(t *testing.T) {
+	m, _ := newExportTestManager(t)
+	out := filepath.Join(t.TempDir(), "bundle", "config.yaml")
+
+	require.NoError(t, m.ExportFile(out, FormatYAML, true))
+	data, err := os.ReadFile(out)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "# redacted: true")
+
+	entries, err := os.ReadDir(filepath.Dir(out))
+	require.NoError(t, err)
+	assert.Len(t, entries, 1, "不应留下临时文件")
+
+	// 格式错误时不覆盖已有文件
+	assert.Error(t, m.ExportFile(out, "toml", true))
+	after, err := os.ReadFile(out)
+	require.NoError(t, err)
+	assert.Equal(t, data, after)
+}
+
+// TestRedact 测试脱敏规则不修改原配置
+func TestRedact(t *testing.T) {
+	settings := map[string]any{
+		"API_Token": "t",
+		"nested":    map[string]any{"db_password": "p", "host": "h"},
+	}
+	redacted := Redact(settings)
+	assert.Equal(t, RedactedValue, redacted["API_Token"])
+	assert.Equal(t, map[string]any{"db_password": RedactedValue, "host": "h"}, redacted["nested"])
+	assert.Equal(t, "p", settings["nested"].(map[string]any)["db_password"])
+	assert.True(t, IsSensitiveKey("PrivATE_KEY"))
+	assert.False(t, IsSensitiveKey("host"))
+}
+
+// stripGenerated 去掉导出头部中随时间变化的行
+func stripGenerated(s string) string {
+	var lines []string
+	for _, line := range strings.Split(s, "\n") {
+		if !strings.HasPrefix(line, "# generated: ") {
+			lines = append(lines, line)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,81 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// fallbackKeySep 分隔回退链缓存键中的各个名称，不会出现在 YAML 键中
+const fallbackKeySep = "\x00"
+
+// GetWithFallback 返回按回退链合并后的业务配置，例如
+// GetWithFallback("redis.cart", "redis.defaults") 返回的配置中，
+// redis.cart 未设置的项从 redis.defaults 继承。
+//
+// 越靠前的名称优先级越高，嵌套的 map 按键深度合并；不存在的名称会被跳过，
+// 只有所有名称都不存在时才返回 ErrNotFound。
+// 合并结果以整条回退链为键缓存，与 Get 一样在 Reset 和热加载后重新构建。
+// 没有 fallbacks 时等同于 Get。
+func (m *Manager) GetWithFallback(name string, fallbacks ...string) (*viper.Viper, error) {
+	if len(fallbacks) == 0 {
+		return m.Get(name)
+	}
+	names := append([]string{name}, fallbacks...)
+	for i, n := range names {
+		names[i] = canonicalName(n)
+	}
+	if m == nil {
+		return nil, fmt.Errorf("%w: %q", ErrNotFound, names)
+	}
+
+	return m.cached(strings.Join(names, fallbackKeySep), func(root *viper.Viper) (*viper.Viper, error) {
+		var merged map[string]any
+		found := false
+		// 从优先级最低的名称开始合并，靠前的名称覆盖靠后的名称
+		for i := len(names) - 1; i >= 0; i-- {
+			sub := root.Sub(names[i])
+			if sub == nil {
+				continue
+			}
+			found = true
+			merged = deepMerge(merged, sub.AllSettings())
+		}
+		if !found {
+			return nil, fmt.Errorf("%w: %q", ErrNotFound, names)
+		}
+
+		v := viper.New()
+		if err := v.MergeConfigMap(merged); err != nil {
+			return nil, fmt.Errorf("config %q: merge fallbacks: %w", names, err)
+		}
+		return v, nil
+	})
+}
+
+// ConfigWithFallback 与 Config 相同，但按 GetWithFallback 的回退链合并配置后再反序列化为类型 T。
+// 启用 WithUsageTracking 时，回退链中每个业务配置的对应配置项都记录为已读取。
+//
+// 示例：
+//
+//	cfg, err := ConfigWithFallback[RedisConfig](manager, "redis.cart", "redis.defaults")
+func ConfigWithFallback[T any](m *Manager, name string, fallbacks ...string) (cfg T, err error) {
+	v, err := m.GetWithFallback(name, fallbacks...)
+	if err != nil {
+		return cfg, err
+	}
+	if err := m.unmarshal(v, &cfg, false, append([]string{name}, fallbacks...)...); err != nil {
+		return cfg, fmt.Errorf("config %q: unmarshal: %w", name, err)
+	}
+	return cfg, nil
+}
+
+// MustConfigWithFallback 与 ConfigWithFallback 相同，但失败时直接 panic。
+func MustConfigWithFallback[T any](m *Manager, name string, fallbacks ...string) T {
+	cfg, err := ConfigWithFallback[T](m, name, fallbacks...)
+	if err != nil {
+		panic(err)
+	}
+	return cfg
+}
@@ -0,0 +1,136 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const fallbackConfig = `redis:
+  defaults:
+    addr: localhost:6379
+    db: 0
+    timeout: 3s
+    pool:
+      size: 10
+      idle: 2
+  cart:
+    db: 2
+    pool:
+      size: 50
+`
+
+// newFallbackManager 创建使用 fallbackConfig 的 Manager
+func newFallbackManager(t *testing.T) (*Manager, string) {
+	t.Helper()
+	dir := t.TempDir()
+	file := filepath.Join(dir, "app.yaml")
+	require.NoError(t, os.WriteFile(file, []byte(fallbackConfig), 0644))
+	return MustNewManager(dir, WithWatchDebounce(10*time.Millisecond)), file
+}
+
+// TestManager_GetWithFallback 测试回退链的合并规则
+func TestManager_GetWithFallback(t *testing.T) {
+	m, _ := newFallbackManager(t)
+
+	v, err := m.GetWithFallback("redis.cart", "redis.defaults")
+	require.NoError(t, err)
+	// 标量：靠前的名称优先
+	assert.Equal(t, 2, v.GetInt("db"))
+	// 只存在于回退配置中的键
+	assert.Equal(t, "localhost:6379", v.GetString("addr"))
+	assert.Equal(t, 3*time.Second, v.GetDuration("timeout"))
+	// 嵌套 map 深度合并
+	assert.Equal(t, 50, v.GetInt("pool.size"))
+	assert.Equal(t, 2, v.GetInt("pool.idle"))
+
+	// 合并结果被缓存，且不影响各自的配置
+	again, err := m.GetWithFallback("redis.cart", "redis.defaults")
+	require.NoError(t, err)
+	assert.Same(t, v, again)
+	assert.False(t, m.MustGet("redis.cart").IsSet("addr"))
+
+	// 不存在的名称被跳过
+	v, err = m.GetWithFallback("redis.order", "redis.defaults")
+	require.NoError(t, err)
+	assert.Equal(t, 0, v.GetInt("db"))
+	assert.Equal(t, 10, v.GetInt("pool.size"))
+
+	// 所有名称都不存在时返回 ErrNotFound
+	_, err = m.GetWithFallback("redis.order", "redis.missing")
+	assert.True(t, IsNotFound(err))
+
+	// 没有回退时等同于 Get
+	v, err = m.GetWithFallback("redis.cart")
+	require.NoError(t, err)
+	assert.Same(t, m.MustGet("redis.cart"), v)
+
+	var nilManager *Manager
+	_, err = nilManager.GetWithFallback("redis.cart", "redis.defaults")
+	assert.True(t, IsNotFound(err))
+}
+
+// TestManager_GetWithFallback_Reload 测试热加载后合并结果重新构建
+func TestManager_GetWithFallback_Reload(t *testing.T) {
+	m, file := newFallbackManager(t)
+
+	v, err := m.GetWithFallback("redis.cart", "redis.defaults")
+	require.NoError(t, err)
+	assert.Equal(t, "localhost:6379", v.GetString("addr"))
+
+	require.NoError(t, m.Watch())
+	defer m.StopWatch()
+	time.Sleep(100 * time.Millisecond) // 给监听器时间启动
+
+	updated := `redis:
+  defaults:
+    addr: redis:6379
+  cart:
+    db: 5
+`
+	require.NoError(t, os.WriteFile(file, []byte(updated), 0644))
+	require.Eventually(t, func() bool {
+		v, err := m.GetWithFallback("redis.cart", "redis.defaults")
+		return err == nil && v.GetString("addr") == "redis:6379"
+	}, 2*time.Second, 10*time.Millisecond)
+
+	v, err = m.GetWithFallback("redis.cart", "redis.defaults")
+	require.NoError(t, err)
+	assert.Equal(t, 5, v.GetInt("db"))
+	assert.False(t, v.IsSet("pool"))
+}
+
+// TestConfigWithFallback 测试泛型辅助函数的回退语义
+func TestConfigWithFallback(t *testing.T) {
+	type redisConfig struct {
+		Addr    string
+		DB      int
+		Timeout time.Duration
+		Pool    struct {
+			Size int
+			Idle int
+		}
+	}
+	m, _ := newFallbackManager(t)
+
+	cfg, err := ConfigWithFallback[redisConfig](m, "redis.cart", "redis.defaults")
+	require.NoError(t, err)
+	assert.Equal(t, "localhost:6379", cfg.Addr)
+	assert.Equal(t, 2, cfg.DB)
+	assert.Equal(t, 3*time.Second, cfg.Timeout)
+	assert.Equal(t, 50, cfg.Pool.Size)
+	assert.Equal(t, 2, cfg.Pool.Idle)
+
+	_, err = ConfigWithFallback[redisConfig](m, "redis.order", "redis.missing")
+	assert.True(t, IsNotFound(err))
+
+	_, err = ConfigWithFallback[struct{ Pool int }](m, "redis.cart", "redis.defaults")
+	assert.ErrorContains(t, err, `config "redis.cart": unmarshal`)
+
+	assert.Equal(t, 2, MustConfigWithFallback[redisConfig](m, "redis.cart", "redis.defaults").DB)
+	assert.Panics(t, func() { MustConfigWithFallback[redisConfig](m, "redis.order", "redis.missing") })
+}
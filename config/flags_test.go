@@ -0,0 +1,173 @@
+package config
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const flagsYAML = `flags:
+  new_checkout:
+    enabled: true
+    percent: 30
+    allow: [vip]
+    deny: [banned]
+  dark_mode:
+    enabled: true
+  legacy:
+    enabled: false
+    allow: [vip]
+  zero:
+    enabled: true
+    percent: 0
+    allow: [vip]
+  full:
+    enabled: true
+    percent: 100
+    deny: [banned]
+app:
+  port: 8080
+`
+
+// TestManager_Flag 测试开关的 allow/deny 优先级、enabled 总开关与灰度比例边界
+func TestManager_Flag(t *testing.T) {
+	dir := t.TempDir()
+	writeYAML(t, dir, "app.yaml", flagsYAML)
+	m, err := NewManager(dir)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"dark_mode", "full", "legacy", "new_checkout", "zero"}, m.Flags())
+
+	eval := func(name, key string) bool {
+		t.Helper()
+		f, err := m.Flag(name)
+		require.NoError(t, err)
+		return f.EvalBool(key)
+	}
+
+	tests := []struct {
+		flag, key string
+		want      bool
+	}{
+		{"new_checkout", "vip", true},     // allow 名单始终开启
+		{"new_checkout", "banned", false}, // deny 名单始终关闭
+		{"dark_mode", "anyone", true},     // 没有 percent 时取 enabled
+		{"legacy", "vip", false},          // enabled 为 false 时 allow 名单也关闭
+		{"zero", "anyone", false},         // percent 0 对所有人关闭
+		{"zero", "vip", true},             // percent 0 时 allow 名单仍然开启
+		{"full", "anyone", true},          // percent 100 对所有人开启
+		{"full", "banned", false},         // percent 100 时 deny 名单仍然关闭
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, eval(tt.flag, tt.key), "%s(%s)", tt.flag, tt.key)
+	}
+
+	f, err := m.Flag("New_Checkout")
+	require.NoError(t, err)
+	assert.Equal(t, "new_checkout", f.Name())
+
+	_, err = m.Flag("missing")
+	assert.True(t, IsFlagNotFound(err))
+	assert.ErrorContains(t, err, `"missing"`)
+	_, err = m.Flag("new_checkout.enabled")
+	assert.True(t, IsFlagNotFound(err))
+	assert.False(t, Flag{}.EvalBool("anyone"))
+}
+
+// TestManager_Flag_Bucketing 测试分桶是确定的：同一个 key 的结果始终相同，开启比例接近 percent，
+// 提高比例只会增加开启的 key
+func TestManager_Flag_Bucketing(t *testing.T) {
+	for i := range 100 {
+		key := fmt.Sprintf("user-%d", i)
+		assert.Equal(t, flagBucket("new_checkout", key), flagBucket("new_checkout", key))
+	}
+	assert.Equal(t, 91, flagBucket("new_checkout", "user-1"), "bucketing must not change between releases")
+
+	dir := t.TempDir()
+	writeYAML(t, dir, "app.yaml", flagsYAML)
+	m, err := NewManager(dir)
+	require.NoError(t, err)
+	f, err := m.Flag("new_checkout")
+	require.NoError(t, err)
+
+	enabled := make(map[string]bool)
+	for i := range 10000 {
+		key := fmt.Sprintf("user-%d", i)
+		if f.EvalBool(key) {
+			enabled[key] = true
+		}
+		assert.Equal(t, enabled[key], f.EvalBool(key))
+	}
+	assert.InDelta(t, 3000, len(enabled), 300)
+
+	// 热加载提高比例后，之前开启的 key 仍然开启
+	writeYAML(t, dir, "app.yaml", "flags:\n  new_checkout:\n    enabled: true\n    percent: 60\n")
+	require.NoError(t, m.Reset())
+	for key := range enabled {
+		require.True(t, f.EvalBool(key), key)
+	}
+}
+
+// TestManager_Flag_Reload 测试已经获取的 Flag 在重载后立即使用新的配置，开关被删除后返回 false
+func TestManager_Flag_Reload(t *testing.T) {
+	dir := t.TempDir()
+	writeYAML(t, dir, "app.yaml", "flags:\n  beta:\n    enabled: true\n    percent: 0\n")
+	m, err := NewManager(dir)
+	require.NoError(t, err)
+	f, err := m.Flag("beta")
+	require.NoError(t, err)
+	assert.False(t, f.EvalBool("user-1"))
+
+	writeYAML(t, dir, "app.yaml", "flags:\n  beta:\n    enabled: true\n    percent: 100\n    deny: [user-2]\n")
+	m.handleReload()
+	assert.True(t, f.EvalBool("user-1"))
+	assert.False(t, f.EvalBool("user-2"))
+
+	writeYAML(t, dir, "app.yaml", "flags:\n  other:\n    enabled: true\n")
+	m.handleReload()
+	assert.False(t, f.EvalBool("user-1"))
+	assert.Equal(t, []string{"other"}, m.Flags())
+	_, err = m.Flag("beta")
+	assert.True(t, IsFlagNotFound(err))
+}
+
+// TestManager_FlagStats 测试求值次数的累计与统计回调
+func TestManager_FlagStats(t *testing.T) {
+	dir := t.TempDir()
+	writeYAML(t, dir, "app.yaml", flagsYAML)
+
+	type call struct {
+		name   string
+		result bool
+		stats  FlagStats
+	}
+	var calls []call
+	m, err := NewManager(dir, WithFlagStatsHook(func(name string, result bool, stats FlagStats) {
+		calls = append(calls, call{name, result, stats})
+	}), WithUsageTracking())
+	require.NoError(t, err)
+	assert.Empty(t, m.FlagStats())
+
+	f, err := m.Flag("new_checkout")
+	require.NoError(t, err)
+	f.EvalBool("vip")
+	f.EvalBool("banned")
+	f.EvalBool("vip")
+
+	assert.Equal(t, map[string]FlagStats{"new_checkout": {Evaluations: 3, True: 2, False: 1}}, m.FlagStats())
+	require.Len(t, calls, 3)
+	assert.Equal(t, call{"new_checkout", false, FlagStats{Evaluations: 2, True: 1, False: 1}}, calls[1])
+
+	// 被求值的开关视为已读取
+	unused := m.UnusedKeys(0)
+	assert.NotContains(t, unused, "flags.new_checkout.percent")
+	assert.Contains(t, unused, "flags.dark_mode.enabled")
+
+	var nilManager *Manager
+	assert.Empty(t, nilManager.Flags())
+	assert.Empty(t, nilManager.FlagStats())
+	_, err = nilManager.Flag("beta")
+	assert.True(t, IsFlagNotFound(err))
+}
@@ -1,10 +1,8 @@
 package config
 
-import "fmt"
-
 // Config 获取指定 name 对应的配置，并反序列化为泛型类型 T。
 //
-// 该方法会从 Manager 中读取配置项，然后将其反序列化到指定类型。
+// 该方法会从 Manager 中读取配置项，然后使用 DecodeHook 中的标准解码钩子反序列化到指定类型。
 // 适用于将配置直接映射为结构体或基础类型。
 //
 // 参数：
@@ -29,15 +27,7 @@ import "fmt"
 //	    panic(err)
 //	}
 func Config[T any](m *Manager, name string) (cfg T, err error) {
-	v, err := m.Get(name)
-	if err != nil {
-		return
-	}
-
-	if err = v.Unmarshal(&cfg); err != nil {
-		err = fmt.Errorf("config %q: unmarshal: %w", name, err)
-	}
-
+	err = m.Unmarshal(name, &cfg)
 	return
 }
 
@@ -0,0 +1,198 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// DefaultHistorySize 是未设置 WithHistorySize 时保留的历史配置代数。
+const DefaultHistorySize = 3
+
+// ReloadReason 是当前这一代配置产生的原因，在 OnReload 回调中通过 Manager.ReloadReason 读取。
+type ReloadReason string
+
+const (
+	// ReloadInitial 表示 NewManager 首次加载的配置。
+	ReloadInitial ReloadReason = "initial"
+	// ReloadFiles 表示 Reset 或热加载重新读取配置文件（以及环境层和远程层）得到的配置。
+	ReloadFiles ReloadReason = "reload"
+	// ReloadRollback 表示 Rollback 恢复的历史配置。
+	ReloadRollback ReloadReason = "rollback"
+	// ReloadDefaults 表示 DeclareDefaults 将默认值应用到当前配置得到的配置。
+	ReloadDefaults ReloadReason = "defaults"
+)
+
+// WithHistorySize 设置保留的历史配置代数，默认为 DefaultHistorySize，为 0 时不保留历史，不能为负数。
+// 每次成功重载前的配置进入历史记录，超过 n 代时丢弃最旧的一代，内存占用与 n 成正比。
+func WithHistorySize(n int) Option {
+	return func(o *options) {
+		o.historySize = n
+	}
+}
+
+// GenerationInfo 描述一代配置，由 Manager.History 返回。
+type GenerationInfo struct {
+	Generation   uint64            // 配置代数，每次 Reset、热加载或 Rollback 递增
+	LoadedAt     time.Time         // 这一代配置生效的时间
+	Reason       ReloadReason      // 这一代配置产生的原因
+	RestoredFrom uint64            // Reason 为 ReloadRollback 时被恢复的配置代数
+	RootChecksum string            // 全部配置内容的校验和，见 Manager.RootChecksum
+	Checksums    map[string]string // 各业务配置的校验和，见 Manager.Checksum
+	Sources      map[string]string // 各业务配置的来源文件
+	Current      bool              // 是否为当前生效的配置
+}
+
+// generation 是一代配置的快照。根配置替换后不会再被修改，因此快照直接引用它而不复制。
+type generation struct {
+	info      GenerationInfo
+	root      *viper.Viper
+	sources   map[string]string
+	defaulted map[string][]string
+	checksum  map[string]string
+}
+
+// snapshotLocked 返回当前配置的快照，调用方需要持有 m.mu。
+func (m *Manager) snapshotLocked() generation {
+	return generation{
+		info: GenerationInfo{
+			Generation:   m.generation,
+			LoadedAt:     m.loadedAt,
+			Reason:       m.reason,
+			RestoredFrom: m.restoredFrom,
+			RootChecksum: m.rootChecksum,
+		},
+		root:      m.root,
+		sources:   m.sources,
+		defaulted: m.defaulted,
+		checksum:  m.checksums,
+	}
+}
+
+// pushHistoryLocked 将当前配置放入历史记录，超过 WithHistorySize 时丢弃最旧的一代。
+// 在替换根配置之前调用，调用方需要持有 m.mu。
+func (m *Manager) pushHistoryLocked() {
+	size := DefaultHistorySize
+	if m.opts != nil {
+		size = m.opts.historySize
+	}
+	if size == 0 {
+		return
+	}
+	m.history = append(m.history, m.snapshotLocked())
+	if over := len(m.history) - size; over > 0 {
+		// 复制到新切片，使被丢弃的快照可以被回收
+		m.history = append([]generation(nil), m.history[over:]...)
+	}
+}
+
+// Generation 返回当前配置的代数：NewManager 加载的配置为 0，每次 Reset、热加载或 Rollback 递增。
+func (m *Manager) Generation() uint64 {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.generation
+}
+
+// ReloadReason 返回当前这一代配置产生的原因。
+// 在 OnReload 回调中调用可以区分文件变化引起的重载与 Rollback。
+func (m *Manager) ReloadReason() ReloadReason {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.reason
+}
+
+// History 返回当前配置以及保留的历史配置，按代数从新到旧排列，第一项是当前配置（Current 为 true）。
+func (m *Manager) History() []GenerationInfo {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	infos := make([]GenerationInfo, 0, len(m.history)+1)
+	current := m.snapshotLocked()
+	current.info.Current = true
+	infos = append(infos, current.publicInfo())
+	for i := len(m.history) - 1; i >= 0; i-- {
+		infos = append(infos, m.history[i].publicInfo())
+	}
+	return infos
+}
+
+// publicInfo 返回带有校验和与来源文件副本的 GenerationInfo。
+func (g generation) publicInfo() GenerationInfo {
+	info := g.info
+	info.Checksums = maps.Clone(g.checksum)
+	info.Sources = maps.Clone(g.sources)
+	return info
+}
+
+// Rollback 将 History 中代数为 gen 的历史配置恢复为当前配置，用于热加载了能够解析但内容错误的配置之后快速恢复。
+//
+// 恢复的配置作为新的一代生效（代数递增，Reason 为 ReloadRollback），缓存的业务配置被清空，
+// 回滚前的配置进入历史记录，因此可以再次 Rollback 回到它。之后按热加载的规则调用 OnReload 注册的回调
+// 与 WatchKey 的监听函数，返回它们的错误。配置文件不会被修改：下一次文件变化引起的热加载会重新读取文件，
+// 需要在修复文件之前保持回滚后的配置时调用 PinCurrent。
+//
+// gen 不在历史记录中时返回 ErrGenerationNotFound；gen 是当前配置时不做任何事。
+func (m *Manager) Rollback(gen uint64) error {
+	m.mu.Lock()
+	if gen == m.generation {
+		m.mu.Unlock()
+		return nil
+	}
+	var target *generation
+	for i := range m.history {
+		if m.history[i].info.Generation == gen {
+			target = &m.history[i]
+			break
+		}
+	}
+	if target == nil {
+		m.mu.Unlock()
+		return fmt.Errorf("%w: %d", ErrGenerationNotFound, gen)
+	}
+	restored := *target
+	before := m.root
+	m.pushHistoryLocked()
+
+	m.root = restored.root
+	m.sources = restored.sources
+	m.defaulted = restored.defaulted
+	m.checksums, m.rootChecksum = restored.checksum, restored.info.RootChecksum
+	m.configs = make(map[string]*viper.Viper)
+	m.generation++
+	m.loadedAt = m.clock().Now()
+	m.reason = ReloadRollback
+	m.restoredFrom = gen
+	m.mu.Unlock()
+
+	m.logger().Printf("config rolled back to generation %d", gen)
+	err := errors.Join(m.notifyReload(before)...)
+	m.setLastReloadError(err)
+	return err
+}
+
+// PinCurrent 锁定当前配置：锁定期间文件变化与远程配置变化不会触发热加载，直到调用 Unpin。
+// 通常在 Rollback 之后调用，在修复配置文件期间保持回滚后的配置。
+// 被跳过的变化不会在 Unpin 时补做，Unpin 之后的下一次变化会重新读取全部配置文件；
+// 也可以在 Unpin 之后调用 Reset 立即重新加载。直接调用 Reset 与 Rollback 不受锁定影响。
+func (m *Manager) PinCurrent() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.pinned = true
+}
+
+// Unpin 解除 PinCurrent 的锁定。
+func (m *Manager) Unpin() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.pinned = false
+}
+
+// Pinned 报告当前配置是否被 PinCurrent 锁定。
+func (m *Manager) Pinned() bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.pinned
+}
@@ -0,0 +1,162 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/qq1060656096/drugo/pkg/clock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeAppConfig 将 app.port 写入 dir 中的 app.yml
+func writeAppConfig(t *testing.T, dir string, port int) {
+	t.Helper()
+	createTestConfigFile(t, dir, "app.yml", map[string]interface{}{"app": map[string]interface{}{"port": port}})
+}
+
+// TestManager_Rollback 测试热加载错误配置之后回滚：配置、缓存、代数、回调与历史记录
+func TestManager_Rollback(t *testing.T) {
+	dir := t.TempDir()
+	writeAppConfig(t, dir, 8080)
+	fake := clock.NewFake(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+	m, err := NewManager(dir, WithClock(fake), WithLogger(&recordLogger{}))
+	require.NoError(t, err)
+	good := m.RootChecksum()
+	assert.Equal(t, 8080, m.MustGet("app").GetInt("port"))
+
+	var reasons []ReloadReason
+	var ports []int
+	m.OnReload(func(m *Manager) error {
+		reasons = append(reasons, m.ReloadReason())
+		ports = append(ports, m.MustGet("app").GetInt("port"))
+		return nil
+	})
+
+	// 能够解析但内容错误的配置被热加载
+	fake.Advance(time.Minute)
+	writeAppConfig(t, dir, 1)
+	m.handleReload()
+	require.Equal(t, 1, m.MustGet("app").GetInt("port"))
+	require.Equal(t, uint64(1), m.Generation())
+
+	require.NoError(t, m.Rollback(0))
+	assert.Equal(t, 8080, m.MustGet("app").GetInt("port"), "缓存的业务配置应当被清空")
+	assert.Equal(t, good, m.RootChecksum())
+	assert.Equal(t, uint64(2), m.Generation())
+	assert.Equal(t, ReloadRollback, m.ReloadReason())
+	assert.Equal(t, []ReloadReason{ReloadFiles, ReloadRollback}, reasons)
+	assert.Equal(t, []int{1, 8080}, ports)
+	assert.Equal(t, []string{"app"}, m.LastChanges().Modified)
+
+	// 配置文件不会被修改
+	disk, err := NewManager(dir)
+	require.NoError(t, err)
+	assert.Equal(t, 1, disk.MustGet("app").GetInt("port"))
+
+	// 回滚前的配置进入历史记录，可以再次回滚到它
+	history := m.History()
+	require.Len(t, history, 3)
+	assert.Equal(t, uint64(2), history[0].Generation)
+	assert.True(t, history[0].Current)
+	assert.Equal(t, ReloadRollback, history[0].Reason)
+	assert.Equal(t, uint64(1), history[1].Generation)
+	assert.Equal(t, ReloadFiles, history[1].Reason)
+	assert.Equal(t, uint64(0), history[2].Generation)
+	assert.Equal(t, ReloadInitial, history[2].Reason)
+	assert.Equal(t, good, history[2].RootChecksum)
+	assert.Equal(t, fake.Now().Add(-time.Minute), history[2].LoadedAt)
+	assert.Contains(t, history[2].Sources["app"], "app.yml")
+
+	require.NoError(t, m.Rollback(1))
+	assert.Equal(t, 1, m.MustGet("app").GetInt("port"))
+	assert.Equal(t, uint64(1), m.History()[0].RestoredFrom)
+
+	// 不在历史记录中的代数
+	err = m.Rollback(42)
+	assert.True(t, IsGenerationNotFound(err))
+	// 回滚到当前配置不做任何事
+	require.NoError(t, m.Rollback(m.Generation()))
+	assert.Equal(t, uint64(3), m.Generation())
+}
+
+// TestManager_PinCurrent 测试锁定期间文件变化不替换回滚后的配置，解除锁定后的文件变化重新加载
+func TestManager_PinCurrent(t *testing.T) {
+	dir := t.TempDir()
+	writeAppConfig(t, dir, 8080)
+	logger := &recordLogger{}
+	m, err := NewManager(dir, WithLogger(logger))
+	require.NoError(t, err)
+	reloads := 0
+	m.OnReload(func(m *Manager) error {
+		reloads++
+		return nil
+	})
+
+	writeAppConfig(t, dir, 1)
+	m.handleReload()
+	require.NoError(t, m.Rollback(0))
+	m.PinCurrent()
+	assert.True(t, m.Pinned())
+
+	// 锁定期间修改文件不会触发重载
+	writeAppConfig(t, dir, 2)
+	m.handleReload()
+	assert.Equal(t, 8080, m.MustGet("app").GetInt("port"))
+	assert.Equal(t, uint64(2), m.Generation())
+	assert.Equal(t, 2, reloads)
+	assert.True(t, logger.contains("generation 2 is pinned"))
+
+	// 解除锁定后的文件变化重新读取全部配置文件
+	m.Unpin()
+	assert.False(t, m.Pinned())
+	writeAppConfig(t, dir, 9090)
+	m.handleReload()
+	assert.Equal(t, 9090, m.MustGet("app").GetInt("port"))
+	assert.Equal(t, ReloadFiles, m.ReloadReason())
+	assert.Equal(t, 3, reloads)
+}
+
+// TestManager_History_Bounded 测试历史记录的长度不超过 WithHistorySize，最旧的一代被丢弃
+func TestManager_History_Bounded(t *testing.T) {
+	dir := t.TempDir()
+	writeAppConfig(t, dir, 8000)
+
+	t.Run("default", func(t *testing.T) {
+		m, err := NewManager(dir)
+		require.NoError(t, err)
+		for range 5 {
+			require.NoError(t, m.Reset())
+		}
+		history := m.History()
+		require.Len(t, history, DefaultHistorySize+1)
+		var gens []uint64
+		for _, h := range history {
+			gens = append(gens, h.Generation)
+		}
+		assert.Equal(t, []uint64{5, 4, 3, 2}, gens)
+		assert.True(t, IsGenerationNotFound(m.Rollback(0)))
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		m, err := NewManager(dir, WithHistorySize(0))
+		require.NoError(t, err)
+		require.NoError(t, m.Reset())
+		require.Len(t, m.History(), 1)
+		assert.True(t, IsGenerationNotFound(m.Rollback(0)))
+	})
+
+	t.Run("negative", func(t *testing.T) {
+		_, err := NewManager(dir, WithHistorySize(-1))
+		assert.True(t, IsInvalidOption(err))
+	})
+
+	t.Run("copies", func(t *testing.T) {
+		m, err := NewManager(dir)
+		require.NoError(t, err)
+		m.History()[0].Checksums["app"] = "changed"
+		sum, err := m.Checksum("app")
+		require.NoError(t, err)
+		assert.NotEqual(t, "changed", sum)
+	})
+}
@@ -0,0 +1,99 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"go.yaml.in/yaml/v3"
+)
+
+// WithStrictKeyCase 要求所有配置文件的顶级键（业务配置名称）都是小写。
+// 默认情况下 "Database" 与 "database" 视为同一个业务配置；启用后包含非小写顶级键的文件
+// 返回 ErrKeyCase，错误中包含原始拼写与文件路径，热加载时同样保留之前的配置。
+func WithStrictKeyCase() Option {
+	return func(o *options) {
+		o.strictKeyCase = true
+	}
+}
+
+// canonicalName 返回业务配置名称的规范形式。
+// viper 将所有键转换为小写，List、Checksum、SourceFile 以及 Get 的缓存都使用规范形式。
+func canonicalName(name string) string {
+	return strings.ToLower(name)
+}
+
+// strictKeyCase 报告是否启用了 WithStrictKeyCase。
+func (m *Manager) strictKeyCase() bool {
+	return m.opts != nil && m.opts.strictKeyCase
+}
+
+// topLevelKeys 按出现顺序返回 YAML 文件中顶级键的原始拼写，
+// viper 读取后键已被转换为小写，因此需要单独解析文件。
+func topLevelKeys(path string) ([]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	if len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		return nil, nil
+	}
+	mapping := doc.Content[0]
+	keys := make([]string, 0, len(mapping.Content)/2)
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		keys = append(keys, mapping.Content[i].Value)
+	}
+	return keys, nil
+}
+
+// checkFileKeys 检查文件中的顶级键：同一文件中仅大小写不同的键返回 ErrDuplicateKey，
+// 启用 WithStrictKeyCase 时非小写的键返回 ErrKeyCase。
+func (m *Manager) checkFileKeys(path string) error {
+	keys, err := topLevelKeys(path)
+	if err != nil {
+		return newFileError(path, err)
+	}
+	seen := make(map[string]string, len(keys))
+	for _, key := range keys {
+		name := canonicalName(key)
+		if m.strictKeyCase() && key != name {
+			return fmt.Errorf("%w: %q in %s, use %q", ErrKeyCase, key, path, name)
+		}
+		if prev, ok := seen[name]; ok {
+			return &DuplicateKeyError{name: name, firstKey: prev, secondKey: key, firstFile: path, secondFile: path}
+		}
+		seen[name] = key
+	}
+	return nil
+}
+
+// originalKey 返回 path 中与 name 忽略大小写后相同的顶级键的原始拼写，
+// 文件无法读取或不包含该键时返回 name。
+func originalKey(path, name string) string {
+	keys, err := topLevelKeys(path)
+	if err != nil {
+		return name
+	}
+	for _, key := range keys {
+		if strings.EqualFold(key, name) {
+			return key
+		}
+	}
+	return name
+}
+
+// duplicateKeyError 返回业务配置 name 同时定义在 first 与 second 两个文件中的错误，
+// 错误中包含两个文件中的原始拼写，例如 "Database" in a.yaml and "database" in b.yaml。
+func duplicateKeyError(name, first, second string) error {
+	return &DuplicateKeyError{
+		name:       name,
+		firstKey:   originalKey(first, name),
+		secondKey:  originalKey(second, name),
+		firstFile:  first,
+		secondFile: second,
+	}
+}
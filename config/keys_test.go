@@ -0,0 +1,88 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeYAML 在 dir 中写入名为 name 的 YAML 文件
+func writeYAML(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	return path
+}
+
+// TestManager_DuplicateKey_CaseInsensitive 测试不同文件中仅大小写不同的业务配置视为重复，错误包含原始拼写与文件路径
+func TestManager_DuplicateKey_CaseInsensitive(t *testing.T) {
+	dir := t.TempDir()
+	a := writeYAML(t, dir, "a.yaml", "Database:\n  host: a\n")
+	b := writeYAML(t, dir, "b.yaml", "database:\n  host: b\n")
+
+	_, err := NewManager(dir)
+	require.True(t, IsDuplicateKey(err))
+	assert.EqualError(t, err, `config: duplicate key: "Database" in `+a+` and "database" in `+b)
+}
+
+// TestManager_DuplicateKey_SameFile 测试同一文件中仅大小写不同的顶级键视为重复
+func TestManager_DuplicateKey_SameFile(t *testing.T) {
+	dir := t.TempDir()
+	path := writeYAML(t, dir, "app.yaml", "Redis:\n  host: a\nredis:\n  port: 6379\n")
+
+	_, err := NewManager(dir)
+	require.True(t, IsDuplicateKey(err))
+	assert.EqualError(t, err, `config: duplicate key: "Redis" and "redis" in `+path)
+}
+
+// TestManager_Get_MixedCase 测试任意大小写的名称都返回规范名称下缓存的同一实例
+func TestManager_Get_MixedCase(t *testing.T) {
+	dir := t.TempDir()
+	path := writeYAML(t, dir, "db.yaml", "DataBase:\n  Host: localhost\n")
+
+	m, err := NewManager(dir)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"database"}, m.List())
+
+	lower := m.MustGet("database")
+	assert.Same(t, lower, m.MustGet("DataBase"))
+	assert.Same(t, lower, m.MustGet("DATABASE"))
+	assert.Equal(t, "localhost", lower.GetString("host"))
+
+	m.mu.RLock()
+	assert.Len(t, m.configs, 1)
+	m.mu.RUnlock()
+
+	source, ok := m.SourceFile("DataBase")
+	assert.True(t, ok)
+	assert.Equal(t, path, source)
+
+	sum, err := m.Checksum("database")
+	require.NoError(t, err)
+	mixed, err := m.Checksum("DataBase")
+	require.NoError(t, err)
+	assert.Equal(t, sum, mixed)
+}
+
+// TestWithStrictKeyCase 测试严格模式拒绝非小写的顶级键，热加载时保留之前的配置
+func TestWithStrictKeyCase(t *testing.T) {
+	dir := t.TempDir()
+	path := writeYAML(t, dir, "app.yaml", "app:\n  name: demo\n")
+
+	m, err := NewManager(dir, WithStrictKeyCase())
+	require.NoError(t, err)
+
+	writeYAML(t, dir, "app.yaml", "app:\n  name: demo\nCache:\n  size: 1\n")
+	err = m.Reset()
+	require.True(t, IsKeyCase(err))
+	assert.EqualError(t, err, `config: top-level key is not lower-case: "Cache" in `+path+`, use "cache"`)
+	assert.Equal(t, []string{"app"}, m.List(), "previous config is kept")
+
+	// 未启用严格模式时同样的文件可以正常加载
+	m, err = NewManager(dir)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"app", "cache"}, m.List())
+}
@@ -0,0 +1,41 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Logger 是 Manager 输出运行日志（热加载失败、监听器重启、可选远程配置不可用等）使用的接口，
+// 标准库的 *log.Logger 满足该接口。
+type Logger interface {
+	Printf(format string, args ...any)
+}
+
+// stderrLogger 是默认的 Logger，将日志逐行写入标准错误输出。
+type stderrLogger struct{}
+
+func (stderrLogger) Printf(format string, args ...any) {
+	msg := fmt.Sprintf(format, args...)
+	if !strings.HasSuffix(msg, "\n") {
+		msg += "\n"
+	}
+	fmt.Fprint(os.Stderr, msg)
+}
+
+// WithLogger 设置 Manager 输出运行日志使用的 Logger，默认写入标准错误输出，l 为 nil 时保持默认。
+func WithLogger(l Logger) Option {
+	return func(o *options) {
+		if l != nil {
+			o.logger = l
+		}
+	}
+}
+
+// logger 返回 Manager 使用的 Logger。
+func (m *Manager) logger() Logger {
+	if m == nil || m.opts == nil || m.opts.logger == nil {
+		return stderrLogger{}
+	}
+	return m.opts.logger
+}
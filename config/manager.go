@@ -1,32 +1,107 @@
 package config
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"runtime/debug"
 	"sort"
+	"strconv"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/fsnotify/fsnotify"
+	"github.com/qq1060656096/drugo/pkg/clock"
 	"github.com/spf13/viper"
+	"golang.org/x/sync/singleflight"
 )
 
 // ReloadCallback 是配置重载时调用的回调函数类型。
 // 如果回调返回 error，错误会被记录但不会停止热加载。
 type ReloadCallback func(m *Manager) error
 
+// ReloadErrorCallback 是配置重载失败时调用的回调函数类型，err 为重载失败的原因。
+type ReloadErrorCallback func(m *Manager, err error)
+
+// DefaultReloadPriority 是 OnReload 注册回调时使用的默认优先级。
+const DefaultReloadPriority = 0
+
+// stopWatchTimeout 是 StopWatch 等待监听协程退出的最长时间，测试中可以调小。
+var stopWatchTimeout = 5 * time.Second
+
+// DefaultWatchDebounce 是文件监听的默认防抖间隔。
+// 一次保存通常会产生多个文件系统事件，间隔内的事件只会触发一次重载。
+const DefaultWatchDebounce = 50 * time.Millisecond
+
+// reloadCallback 记录回调函数及其优先级。
+type reloadCallback struct {
+	priority int
+	fn       ReloadCallback
+}
+
 // Manager 管理配置加载和缓存，支持多业务配置。
+//
+// Watch 与 WatchRemote 各启动一个后台协程，重载以及 OnReload、OnReloadError、WatchKey 注册的回调
+// 都在这些协程中执行。协程归 Manager 所有，由 StopWatch 停止：StopWatch 返回后不会再有回调被调用。
 type Manager struct {
 	mu        sync.RWMutex
 	root      *viper.Viper
 	configs   map[string]*viper.Viper
 	configDir string
+	sources   map[string]string   // 业务配置名称到来源文件的映射，与 root 一起替换
+	defaulted map[string][]string // 各业务配置中取值来自默认值的配置项，与 root 一起替换
+
+	// 懒加载相关字段：loading 保证同一业务配置只被构建一次，
+	// generation 在每次 Reset 时递增，避免旧配置写入新缓存
+	loading    singleflight.Group
+	generation uint64
+
+	// 校验和在加载及每次成功重载时更新
+	checksums    map[string]string
+	rootChecksum string
+
+	// 当前这一代配置的元数据与历史配置，见 History 与 Rollback
+	loadedAt     time.Time
+	reason       ReloadReason
+	restoredFrom uint64
+	history      []generation // 按代数从旧到新排列，长度不超过 WithHistorySize
+	pinned       bool         // PinCurrent 锁定期间热加载不替换配置
 
 	// 热加载相关字段
 	watcher         *fsnotify.Watcher
 	watcherDone     chan struct{}
+	watcherExited   chan struct{} // 监听协程退出时关闭
 	watcherStopOnce sync.Once
-	reloadCallbacks []ReloadCallback
+	watchStopped    atomic.Bool // StopWatch 已被调用，重载不再执行
+	reloadCallbacks []reloadCallback
+	errorCallbacks  []ReloadErrorCallback
+	lastReloadErr   error
+	lastChanges     Changes
+	keyWatchers     []*keyWatcher
+
+	watchStats WatcherStats
+
+	// 类型规格与默认值，由 specMu 单独保护：load 在持有 mu 时读取
+	specMu   sync.Mutex
+	specs    map[string]map[string]Kind
+	defaults map[string]map[string]any // 见 DeclareDefaults
+
+	// envFromKey 表示 NewManager 的加载需要从基础配置读取环境名称（见 WithEnvironmentKey），
+	// 只在 NewManager 返回之前为 true，之后的加载只读取它
+	envFromKey bool
+
+	// 远程配置相关字段
+	opts              *options
+	remoteWatchDone   chan struct{}
+	remoteWatchExited chan struct{} // 远程轮询协程退出时关闭
+
+	usage *usageTracker // 配置项读取记录，未启用 WithUsageTracking 时为 nil
+
+	// 功能开关的累计求值次数，见 FlagStats
+	flagMu    sync.Mutex
+	flagStats map[string]FlagStats
 }
 
 var (
@@ -50,21 +125,39 @@ func Default() *Manager {
 
 // NewManager 创建一个新的 Manager，从 configDir 读取配置文件。
 // 它读取目录中所有 .yml 和 .yaml 文件并合并它们。
-func NewManager(configDir string) (*Manager, error) {
-	root, err := loadConfigs(configDir)
-	if err != nil {
+// 通过 opts 可以叠加远程配置源等额外的配置层。
+// 加载配置之前会校验所有选项，存在无效选项时返回用 errors.Join 合并的全部错误，
+// 每个错误都包装了 ErrInvalidOption。
+func NewManager(configDir string, opts ...Option) (*Manager, error) {
+	o := newOptions(opts)
+	if err := o.validate(); err != nil {
 		return nil, err
 	}
-	return &Manager{
-		root:      root,
+	m := &Manager{
 		configs:   make(map[string]*viper.Viper),
 		configDir: configDir,
-	}, nil
+		opts:      o,
+		usage:     newUsageTracker(o),
+	}
+
+	m.envFromKey = o.env == "" && o.envKey != ""
+	root, sources, defaulted, err := m.load()
+	m.envFromKey = false
+	if err != nil {
+		return nil, err
+	}
+	m.root = root
+	m.sources = sources
+	m.defaulted = defaulted
+	m.checksums, m.rootChecksum = computeChecksums(root)
+	m.loadedAt = m.clock().Now()
+	m.reason = ReloadInitial
+	return m, nil
 }
 
 // MustNewManager 类似于 NewManager，但如果发生错误会 panic。
-func MustNewManager(configDir string) *Manager {
-	m, err := NewManager(configDir)
+func MustNewManager(configDir string, opts ...Option) *Manager {
+	m, err := NewManager(configDir, opts...)
 	if err != nil {
 		panic(err)
 	}
@@ -72,31 +165,62 @@ func MustNewManager(configDir string) *Manager {
 }
 
 // Get 返回指定业务名称的配置。
-// 它使用双重检查锁定来保证线程安全和缓存。
+// name 忽略大小写，Get("Database") 与 Get("database") 返回同一个缓存实例。
+// 首次获取时从根配置构建并缓存子配置，并发的首次获取只会构建一次并返回同一实例；
+// 构建过程不持有全局写锁，不同业务配置之间互不阻塞。
+// 业务配置不存在时返回 *NotFoundError，它包装了 ErrNotFound，
+// 存在拼写相近的业务配置时错误信息中包含建议（见 SuggestionsFromError）。
 func (m *Manager) Get(name string) (*viper.Viper, error) {
+	// nil Manager（例如 kernel.BaseKernel 的默认 Config）视为没有任何配置
+	if m == nil {
+		return nil, fmt.Errorf("%w: %q", ErrNotFound, name)
+	}
+
+	return m.cached(canonicalName(name), func(root *viper.Viper) (*viper.Viper, error) {
+		sub := root.Sub(name)
+		if sub == nil {
+			return nil, newNotFoundError(name, sortedKeys(root.AllSettings()))
+		}
+		return sub, nil
+	})
+}
+
+// cached 返回缓存键 key 对应的配置，未缓存时使用 build 基于当前根配置构建并缓存。
+// 同一代配置中相同 key 的并发构建只会执行一次；缓存在 Reset（包括热加载）时清空。
+func (m *Manager) cached(key string, build func(root *viper.Viper) (*viper.Viper, error)) (*viper.Viper, error) {
 	// 快速路径：使用读锁检查缓存。
 	m.mu.RLock()
-	v, ok := m.configs[name]
+	v, ok := m.configs[key]
+	root, gen := m.root, m.generation
 	m.mu.RUnlock()
 	if ok {
 		return v, nil
 	}
 
-	// 慢速路径：获取写锁并再次检查。
-	m.mu.Lock()
-	defer m.mu.Unlock()
-
-	if v, ok = m.configs[name]; ok {
-		return v, nil
-	}
+	// 慢速路径：同一代配置中的同名请求合并为一次构建。
+	flightKey := strconv.FormatUint(gen, 10) + "/" + key
+	result, err, _ := m.loading.Do(flightKey, func() (any, error) {
+		sub, err := build(root)
+		if err != nil {
+			return nil, err
+		}
 
-	sub := m.root.Sub(name)
-	if sub == nil {
-		return nil, fmt.Errorf("%w: %q", ErrNotFound, name)
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		// 构建期间发生了 Reset 时不写入缓存，返回基于旧配置构建的实例
+		if m.generation != gen {
+			return sub, nil
+		}
+		if cached, ok := m.configs[key]; ok {
+			return cached, nil
+		}
+		m.configs[key] = sub
+		return sub, nil
+	})
+	if err != nil {
+		return nil, err
 	}
-
-	m.configs[name] = sub
-	return sub, nil
+	return result.(*viper.Viper), nil
 }
 
 // MustGet 类似于 Get，但如果发生错误会 panic。
@@ -110,13 +234,34 @@ func (m *Manager) MustGet(name string) *viper.Viper {
 
 // Root 返回包含所有业务配置的根配置。
 func (m *Manager) Root() *viper.Viper {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
 	return m.root
 }
 
-// List 返回根配置中所有可用业务配置名称的有序列表，
+// Transaction 使用同一份根配置快照执行 fn，用于需要同时读取多个业务配置的场景。
+// 分别调用 Get 读取多个业务配置时，两次调用之间可能发生热加载，读到来自不同版本的配置；
+// fn 中通过 root 读取的所有业务配置都来自同一次加载，即使执行期间发生了重载。
+// 快照在重载后不会被修改，fn 不持有锁，因此可以在 fn 中调用 Manager 的其他方法。
+// fn 不应修改 root。返回 fn 的错误。
+func (m *Manager) Transaction(fn func(root *viper.Viper) error) error {
+	if m == nil {
+		return fn(viper.New())
+	}
+	return fn(m.Root())
+}
+
+// List 返回根配置中所有可用业务配置名称（小写的规范形式）的有序列表，
 // 无论它们是否已被加载。
 func (m *Manager) List() []string {
+	m.mu.RLock()
 	settings := m.root.AllSettings()
+	m.mu.RUnlock()
+	return sortedKeys(settings)
+}
+
+// sortedKeys 返回 settings 的顶级键的有序列表。
+func sortedKeys(settings map[string]any) []string {
 	names := make([]string, 0, len(settings))
 	for name := range settings {
 		names = append(names, name)
@@ -126,132 +271,396 @@ func (m *Manager) List() []string {
 }
 
 // Reset 重新加载配置并清空所有缓存的业务配置。
-// 此方法是线程安全的。
+// 新的根配置包含所有文件（以及环境层和远程层），完整构建之后才一次性替换旧的根配置：
+// 读取方要么看到重载前的全部配置，要么看到重载后的全部配置，不会看到只更新了部分文件的中间状态。
+// 加载失败时保留之前的配置，成功时之前的配置进入历史记录（见 History）。此方法是线程安全的。
 func (m *Manager) Reset() error {
+	// 在锁外读取远程配置源，慢速的远程配置源不会阻塞 Get 等读取方
+	layers, err := loadRemotes(m.opts)
+	if err != nil {
+		return err
+	}
+	return m.resetWith(layers)
+}
+
+// resetWith 与 Reset 相同，但使用已经读取的远程配置层 layers，不再访问远程配置源。
+func (m *Manager) resetWith(layers []map[string]any) error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
-	root, err := loadConfigs(m.configDir)
+	root, sources, defaulted, err := m.loadWith(layers)
 	if err != nil {
 		return err
 	}
 
+	m.pushHistoryLocked()
 	m.root = root
+	m.sources = sources
+	m.defaulted = defaulted
 	m.configs = make(map[string]*viper.Viper)
+	m.checksums, m.rootChecksum = computeChecksums(root)
+	m.generation++
+	m.loadedAt = m.clock().Now()
+	m.reason = ReloadFiles
+	m.restoredFrom = 0
 	return nil
 }
 
 // OnReload 注册配置重载时的回调函数。
-// 回调函数会在配置文件变化并成功重载后被调用。
+// 回调函数会在配置文件变化并成功重载后被调用，优先级为 DefaultReloadPriority。
 // 此方法是线程安全的。
 func (m *Manager) OnReload(callback ReloadCallback) {
+	m.OnReloadPriority(DefaultReloadPriority, callback)
+}
+
+// OnReloadPriority 以指定优先级注册配置重载时的回调函数。
+// 优先级越高的回调越先执行，相同优先级按注册顺序执行。
+// 适用于需要先于业务回调执行的基础设施回调（例如重新应用日志级别）。
+// 此方法是线程安全的。
+func (m *Manager) OnReloadPriority(priority int, callback ReloadCallback) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
-	m.reloadCallbacks = append(m.reloadCallbacks, callback)
+	m.reloadCallbacks = append(m.reloadCallbacks, reloadCallback{priority: priority, fn: callback})
+	sort.SliceStable(m.reloadCallbacks, func(i, j int) bool {
+		return m.reloadCallbacks[i].priority > m.reloadCallbacks[j].priority
+	})
+}
+
+// OnReloadError 注册配置重载失败时的回调函数。
+// 重载失败时不会调用 OnReload 注册的回调，配置保持上一次成功加载的内容。
+// 此方法是线程安全的。
+func (m *Manager) OnReloadError(callback ReloadErrorCallback) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.errorCallbacks = append(m.errorCallbacks, callback)
+}
+
+// LastReloadError 返回最近一次热加载的错误。
+// 包括配置重载失败以及回调返回的错误或 panic；最近一次热加载完全成功时返回 nil。
+func (m *Manager) LastReloadError() error {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.lastReloadErr
 }
 
 // Watch 启动配置文件的热加载监听。
 // 当配置文件发生变化时，会自动重新加载配置并调用注册的回调函数。
-// 此方法是幂等的，多次调用只会启动一次监听。
+// 环境层目录（见 WithEnvironment）或额外配置目录在 Watch 之后才创建时，只要它直接位于配置目录中，
+// 创建后同样会被监听并触发一次重载；位于更深层级且 Watch 时不存在的目录不会被发现。
+// 监听器意外退出（例如 inotify 实例失效）时会按指数退避自动重建，见 WithWatchRestart 和 WatcherStats。
+// 此方法是幂等的，多次调用只会启动一次监听；StopWatch 之后调用返回 ErrWatchStopped。
 func (m *Manager) Watch() error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
+	if m.watchStopped.Load() {
+		return ErrWatchStopped
+	}
 	// 如果已经在监听，直接返回
 	if m.watcher != nil {
 		return nil
 	}
 
-	watcher, err := fsnotify.NewWatcher()
+	watcher, err := m.newWatcher()
 	if err != nil {
-		return fmt.Errorf("config: failed to create watcher: %w", err)
-	}
-
-	// 添加配置目录到监听列表
-	if err := watcher.Add(m.configDir); err != nil {
-		watcher.Close()
-		return fmt.Errorf("config: failed to watch directory %s: %w", m.configDir, err)
+		return err
 	}
 
 	m.watcher = watcher
 	m.watcherDone = make(chan struct{})
+	m.watcherExited = make(chan struct{})
+	m.watchStats.Watching = true
 
-	// 启动监听协程
-	go m.watchLoop()
+	// 启动监听协程，由 superviseWatch 负责在监听器意外退出时重建
+	go func(done <-chan struct{}, exited chan<- struct{}) {
+		defer close(exited)
+		m.superviseWatch(watcher, done)
+	}(m.watcherDone, m.watcherExited)
 
 	return nil
 }
 
-// StopWatch 停止配置文件的热加载监听。
-// 此方法是幂等的，多次调用是安全的。
+// newWatcher 创建文件监听器并添加配置目录、环境层目录和所有已存在的额外配置目录。
+func (m *Manager) newWatcher() (*fsnotify.Watcher, error) {
+	watcher, err := newFSWatcher()
+	if err != nil {
+		return nil, fmt.Errorf("config: failed to create watcher: %w", err)
+	}
+
+	// 添加配置目录到监听列表
+	if err := watcher.Add(m.configDir); err != nil {
+		watcher.Close()
+		return nil, fmt.Errorf("config: failed to watch directory %s: %w", m.configDir, err)
+	}
+
+	// 环境层目录和额外配置目录存在时一并监听
+	for _, dir := range append([]string{m.envDir()}, m.extraDirs()...) {
+		if dir == "" {
+			continue
+		}
+		if _, err := os.Stat(dir); err == nil {
+			if err := watcher.Add(dir); err != nil {
+				watcher.Close()
+				return nil, fmt.Errorf("config: failed to watch directory %s: %w", dir, err)
+			}
+		}
+	}
+	return watcher, nil
+}
+
+// StopWatch 停止配置文件的热加载监听和远程轮询，同时停止监听器的自动重建。
+// StopWatch 会等待后台协程退出（最多 5 秒），正在执行的重载会先完成，返回后不会再调用任何重载回调，
+// 之后再调用 Watch 或 WatchRemote 返回 ErrWatchStopped。
+// 不要在重载回调中调用 StopWatch：回调所在的协程无法在等待自身退出时退出，StopWatch 会等到超时才返回。
+// 此方法是幂等的，多次调用是安全的，并发的调用都会等待协程退出。
 func (m *Manager) StopWatch() {
 	m.watcherStopOnce.Do(func() {
+		// 先设置标记并关闭 done 通道，之后的重载不再执行，重建中的监听器会被丢弃
 		m.mu.Lock()
-		if m.watcher != nil {
-			m.watcher.Close()
-		}
+		m.watchStopped.Store(true)
+		watcher, exited := m.watcher, []chan struct{}{m.watcherExited, m.remoteWatchExited}
 		if m.watcherDone != nil {
 			close(m.watcherDone)
 		}
+		if m.remoteWatchDone != nil {
+			close(m.remoteWatchDone)
+		}
+		m.watchStats.Watching = false
 		m.mu.Unlock()
+
+		// 在锁外关闭监听器并等待协程退出：正在执行的重载需要获取 mu
+		if watcher != nil {
+			watcher.Close()
+		}
+		timer := m.clock().NewTimer(stopWatchTimeout)
+		defer timer.Stop()
+		for _, ch := range exited {
+			if ch == nil {
+				continue
+			}
+			select {
+			case <-ch:
+			case <-timer.C():
+				m.logger().Printf("config: StopWatch timed out after %s waiting for the watch goroutine to exit", stopWatchTimeout)
+				return
+			}
+		}
 	})
 }
 
 // watchLoop 是监听配置文件变化的主循环。
-func (m *Manager) watchLoop() {
+// 新增、修改、删除和重命名 YAML 文件都会触发重载，
+// 防抖间隔内的连续事件只会触发一次重载。
+// 监听器的事件或错误通道关闭，或 done 关闭时返回。
+func (m *Manager) watchLoop(watcher *fsnotify.Watcher, done <-chan struct{}) {
+	// 第一个事件到达时才创建防抖定时器，在此之前 debounceC 为 nil，不会被选中
+	var debounce clock.Timer
+	var debounceC <-chan time.Time
+	defer func() {
+		if debounce != nil {
+			debounce.Stop()
+		}
+	}()
+
 	for {
 		select {
-		case event, ok := <-m.watcher.Events:
+		case event, ok := <-watcher.Events:
 			if !ok {
 				return
 			}
 
-			// 只处理写入和创建事件
-			if event.Op&(fsnotify.Write|fsnotify.Create) != 0 {
-				// 检查是否是 YAML 文件
-				ext := filepath.Ext(event.Name)
-				if ext == ".yml" || ext == ".yaml" {
-					m.handleReload()
+			if event.Op&(fsnotify.Write|fsnotify.Create|fsnotify.Remove|fsnotify.Rename) == 0 {
+				continue
+			}
+			// 检查是否是 YAML 文件，或者 Watch 之后才创建的环境层目录与额外配置目录：
+			// 目录中的文件可能在添加监听之前就已经写入，因此同样触发一次重载
+			ext := filepath.Ext(event.Name)
+			if ext == ".yml" || ext == ".yaml" || (event.Op&fsnotify.Create != 0 && m.watchLayerDir(watcher, event.Name)) {
+				if debounce == nil {
+					debounce = m.clock().NewTimer(m.watchDebounce())
+					debounceC = debounce.C()
+				} else {
+					debounce.Reset(m.watchDebounce())
 				}
 			}
 
-		case err, ok := <-m.watcher.Errors:
+		case <-debounceC:
+			m.handleReload()
+
+		case err, ok := <-watcher.Errors:
 			if !ok {
 				return
 			}
-			// 这里可以选择记录错误或者调用错误回调
-			fmt.Fprintf(os.Stderr, "config watcher error: %v\n", err)
+			m.logger().Printf("config watcher error: %v", err)
 
-		case <-m.watcherDone:
+		case <-done:
 			return
 		}
 	}
 }
 
-// handleReload 处理配置重载逻辑。
+// watchLayerDir 在 path 是新创建的环境层目录或额外配置目录时将其加入监听并返回 true。
+// 只有直接位于已监听目录（例如配置目录）中的目录才会产生创建事件。
+func (m *Manager) watchLayerDir(watcher *fsnotify.Watcher, path string) bool {
+	path = filepath.Clean(path)
+	for _, dir := range append([]string{m.envDir()}, m.extraDirs()...) {
+		if dir == "" || filepath.Clean(dir) != path {
+			continue
+		}
+		if info, err := os.Stat(path); err != nil || !info.IsDir() {
+			return false
+		}
+		if err := watcher.Add(path); err != nil {
+			m.logger().Printf("config watcher error: failed to watch directory %s: %v", path, err)
+			return false
+		}
+		return true
+	}
+	return false
+}
+
+// watchDebounce 返回文件监听的防抖间隔。
+func (m *Manager) watchDebounce() time.Duration {
+	if m.opts == nil || m.opts.watchDebounce <= 0 {
+		return DefaultWatchDebounce
+	}
+	return m.opts.watchDebounce
+}
+
+// handleReload 处理配置重载逻辑，StopWatch 之后以及 PinCurrent 锁定期间不再执行。
 func (m *Manager) handleReload() {
+	m.reloadWith(m.Reset)
+}
+
+// reloadWith 使用 reset 重新加载配置，成功时调用重载回调，失败时调用错误回调。
+func (m *Manager) reloadWith(reset func() error) {
+	if m.watchStopped.Load() {
+		return
+	}
+	if m.Pinned() {
+		m.logger().Printf("config reload skipped: generation %d is pinned", m.Generation())
+		return
+	}
+	before := m.Root()
+
 	// 重新加载配置
-	if err := m.Reset(); err != nil {
-		fmt.Fprintf(os.Stderr, "config reload failed: %v\n", err)
+	if err := reset(); err != nil {
+		m.logger().Printf("config reload failed: %v", err)
+		m.setLastReloadError(err)
+		m.mu.RLock()
+		errorCallbacks := append([]ReloadErrorCallback(nil), m.errorCallbacks...)
+		m.mu.RUnlock()
+		for _, callback := range errorCallbacks {
+			callback(m, err)
+		}
 		return
 	}
+	m.setLastReloadError(errors.Join(m.notifyReload(before)...))
+}
 
-	// 调用所有注册的回调函数
-	m.mu.RLock()
-	callbacks := make([]ReloadCallback, len(m.reloadCallbacks))
+// notifyReload 在根配置从 before 替换之后记录变化，并按优先级调用所有注册的回调函数与键监听函数，返回它们的错误。
+func (m *Manager) notifyReload(before *viper.Viper) []error {
+	m.mu.Lock()
+	m.lastChanges = diffSettings(before, m.root)
+	callbacks := make([]reloadCallback, len(m.reloadCallbacks))
 	copy(callbacks, m.reloadCallbacks)
-	m.mu.RUnlock()
+	m.mu.Unlock()
 
+	var errs []error
 	for _, callback := range callbacks {
-		if err := callback(m); err != nil {
-			fmt.Fprintf(os.Stderr, "config reload callback error: %v\n", err)
+		if err := m.runReloadCallback(callback.fn); err != nil {
+			m.logger().Printf("config reload callback error: %v", err)
+			errs = append(errs, err)
+		}
+	}
+	return append(errs, m.notifyKeyWatchers(before, m.Root())...)
+}
+
+// runReloadCallback 执行单个回调，并将回调中的 panic 转换为错误，
+// 避免一个回调的 panic 终止监听协程并使热加载失效。
+func (m *Manager) runReloadCallback(callback ReloadCallback) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			m.logger().Printf("config reload callback panic: %v\n%s", r, debug.Stack())
+			err = fmt.Errorf("%w: %v", ErrCallbackPanic, r)
+		}
+	}()
+	return callback(m)
+}
+
+// setLastReloadError 记录最近一次热加载的错误。
+func (m *Manager) setLastReloadError(err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.lastReloadErr = err
+}
+
+// load 读取所有远程配置源后调用 loadWith。
+func (m *Manager) load() (root *viper.Viper, sources map[string]string, defaulted map[string][]string, err error) {
+	layers, err := loadRemotes(m.opts)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return m.loadWith(layers)
+}
+
+// loadWith 依次读取本地基础配置、环境层配置和额外配置目录，按优先级叠加已经读取的远程配置层 layers，最后填入声明的默认值。
+// 返回的 sources 记录每个业务配置的来源文件，defaulted 记录各业务配置中取值来自默认值的配置项。
+func (m *Manager) loadWith(layers []map[string]any) (root *viper.Viper, sources map[string]string, defaulted map[string][]string, err error) {
+	sources = make(map[string]string)
+	root, err = m.loadConfigs(m.configDir, sources)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if m.envFromKey {
+		if err := m.resolveEnvironment(root); err != nil {
+			return nil, nil, nil, err
 		}
 	}
+	if err := m.loadEnvironment(root, sources); err != nil {
+		return nil, nil, nil, err
+	}
+	if err := m.loadExtraDirs(root, sources); err != nil {
+		return nil, nil, nil, err
+	}
+
+	if len(layers) > 0 {
+		applyRemotes(root, layers, m.opts.remotePrecedence)
+	}
+	if err := m.checkRequired(root); err != nil {
+		return nil, nil, nil, err
+	}
+	defaulted = m.applyDefaults(root)
+	if err := m.checkSpecs(root, sources); err != nil {
+		return nil, nil, nil, err
+	}
+	return root, sources, defaulted, nil
+}
+
+// checkRequired 校验 WithRequireNonEmpty 和 WithRequireSections 的要求，
+// 在 load 中执行，因此 Reset 和热加载失败时会保留之前的配置。
+func (m *Manager) checkRequired(root *viper.Viper) error {
+	if m.opts == nil || !m.opts.requireNonEmpty {
+		return nil
+	}
+	if len(root.AllSettings()) == 0 {
+		return fmt.Errorf("%w: %s", ErrEmptyConfig, m.configDir)
+	}
+	var missing []string
+	for _, name := range m.opts.requiredSections {
+		if !root.IsSet(name) {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("%w: %s: required sections %v", ErrNotFound, m.configDir, missing)
+	}
+	return nil
 }
 
 // loadConfigs 从给定目录读取所有 YAML 配置文件，
-// 并将它们合并到单个 viper 实例中。
-func loadConfigs(dir string) (*viper.Viper, error) {
+// 并将它们合并到单个 viper 实例中，sources 记录每个业务配置的来源文件。
+func (m *Manager) loadConfigs(dir string, sources map[string]string) (*viper.Viper, error) {
 	entries, err := os.ReadDir(dir)
 	if err != nil {
 		return nil, fmt.Errorf("%w: %s: %v", ErrDirRead, dir, err)
@@ -270,7 +679,7 @@ func loadConfigs(dir string) (*viper.Viper, error) {
 		}
 
 		filePath := filepath.Join(dir, fileInfo.Name())
-		if err := mergeFile(root, filePath); err != nil {
+		if err := m.mergeFile(root, filePath, sources); err != nil {
 			return nil, err
 		}
 	}
@@ -278,27 +687,33 @@ func loadConfigs(dir string) (*viper.Viper, error) {
 	return root, nil
 }
 
-// mergeFile 读取单个配置文件并将其内容合并到 root 中。
-// 文件中的每个顶级键代表一个业务配置。
-func mergeFile(root *viper.Viper, path string) error {
+// mergeFile 读取单个配置文件并将其内容合并到 root 中，sources 记录每个业务配置的来源文件。
+// 读取或解析失败时返回 *FileError，重复定义时返回 *DuplicateKeyError。
+// 文件中的每个顶级键代表一个业务配置，名称忽略大小写：不同文件中的 "Database" 与 "database"
+// 视为重复定义，错误中包含两处的原始拼写和文件路径。
+func (m *Manager) mergeFile(root *viper.Viper, path string, sources map[string]string) error {
 	v := viper.New()
 	v.SetConfigFile(path)
 
 	if err := v.ReadInConfig(); err != nil {
-		return fmt.Errorf("%w: %s: %v", ErrFileRead, path, err)
+		return newFileError(path, err)
+	}
+	if err := m.checkFileKeys(path); err != nil {
+		return err
 	}
 
 	for name := range v.AllSettings() {
 		if root.IsSet(name) {
-			return fmt.Errorf("%w: %q in %s", ErrDuplicateKey, name, path)
+			return duplicateKeyError(name, sources[name], path)
 		}
 
 		sub := v.Sub(name)
 		if sub == nil {
-			return fmt.Errorf("%w: %s: cannot read sub config %q", ErrFileRead, path, name)
+			return newFileError(path, fmt.Errorf("cannot read sub config %q", name))
 		}
 
 		root.Set(name, sub.AllSettings())
+		sources[name] = path
 	}
 
 	return nil
@@ -1,12 +1,14 @@
 package config
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
 	"sync"
 	"testing"
 	"time"
 
+	"github.com/qq1060656096/drugo/pkg/clock"
 	"github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -260,6 +262,53 @@ func TestManager_Get_DoubleCheckedLocking(t *testing.T) {
 	}
 }
 
+// TestManager_Get_ConcurrentReload 测试 Get、Reset 与监听触发的重载并发执行（配合 -race 运行）。
+func TestManager_Get_ConcurrentReload(t *testing.T) {
+	tempDir := t.TempDir()
+	createTestConfigFile(t, tempDir, "app.yml", map[string]interface{}{
+		"service": map[string]interface{}{"name": "v0"},
+	})
+
+	manager := MustNewManager(tempDir, WithWatchDebounce(time.Millisecond))
+	require.NoError(t, manager.Watch())
+	defer manager.StopWatch()
+
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-done:
+					return
+				default:
+				}
+				if v, err := manager.Get("service"); err == nil {
+					assert.NotEmpty(t, v.GetString("name"))
+				}
+				_ = manager.List()
+			}
+		}()
+	}
+
+	for i := 1; i <= 10; i++ {
+		createTestConfigFile(t, tempDir, "app.yml", map[string]interface{}{
+			"service": map[string]interface{}{"name": fmt.Sprintf("v%d", i)},
+		})
+		require.NoError(t, manager.Reset())
+		time.Sleep(5 * time.Millisecond)
+	}
+	close(done)
+	wg.Wait()
+
+	require.NoError(t, manager.Reset())
+	first := manager.MustGet("service")
+	assert.Equal(t, "v10", first.GetString("name"))
+	assert.Same(t, first, manager.MustGet("service"))
+}
+
 // TestManager_MustGet 测试 MustGet 方法。
 func TestManager_MustGet(t *testing.T) {
 	t.Run("existing config", func(t *testing.T) {
@@ -383,6 +432,132 @@ func TestManager_OnReload(t *testing.T) {
 	assert.Len(t, manager.reloadCallbacks, 2)
 }
 
+// TestManager_OnReloadPriority 测试回调按优先级执行，相同优先级保持注册顺序。
+func TestManager_OnReloadPriority(t *testing.T) {
+	manager := MustNewManager(t.TempDir())
+
+	var order []string
+	record := func(name string) ReloadCallback {
+		return func(m *Manager) error {
+			order = append(order, name)
+			return nil
+		}
+	}
+
+	manager.OnReload(record("app1"))
+	manager.OnReloadPriority(100, record("infra1"))
+	manager.OnReload(record("app2"))
+	manager.OnReloadPriority(100, record("infra2"))
+	manager.OnReloadPriority(-1, record("last"))
+
+	manager.handleReload()
+
+	assert.Equal(t, []string{"infra1", "infra2", "app1", "app2", "last"}, order)
+	assert.NoError(t, manager.LastReloadError())
+}
+
+// TestManager_ReloadCallbackPanic 测试回调 panic 被隔离，后续回调仍然执行。
+func TestManager_ReloadCallbackPanic(t *testing.T) {
+	manager := MustNewManager(t.TempDir())
+
+	called := false
+	manager.OnReload(func(m *Manager) error {
+		panic("boom")
+	})
+	manager.OnReload(func(m *Manager) error {
+		called = true
+		return nil
+	})
+
+	assert.NotPanics(t, manager.handleReload)
+	assert.True(t, called)
+
+	err := manager.LastReloadError()
+	require.Error(t, err)
+	assert.True(t, IsCallbackPanic(err))
+	assert.Contains(t, err.Error(), "boom")
+
+	// 下一次成功的重载会清除错误
+	manager.reloadCallbacks = manager.reloadCallbacks[1:]
+	manager.handleReload()
+	assert.NoError(t, manager.LastReloadError())
+}
+
+// TestManager_OnReloadError 测试重载失败时调用失败回调且不调用成功回调。
+func TestManager_OnReloadError(t *testing.T) {
+	tempDir := t.TempDir()
+	manager := MustNewManager(tempDir)
+
+	reloaded := false
+	manager.OnReload(func(m *Manager) error {
+		reloaded = true
+		return nil
+	})
+	var got error
+	manager.OnReloadError(func(m *Manager, err error) {
+		got = err
+	})
+
+	createTestFile(t, tempDir, "invalid.yml", "invalid: yaml: content: [")
+	manager.handleReload()
+
+	assert.False(t, reloaded)
+	require.Error(t, got)
+	assert.Equal(t, got, manager.LastReloadError())
+}
+
+// TestManager_RequireNonEmpty 测试 WithRequireNonEmpty 和 WithRequireSections。
+func TestManager_RequireNonEmpty(t *testing.T) {
+	t.Run("empty directory", func(t *testing.T) {
+		tempDir := t.TempDir()
+
+		_, err := NewManager(tempDir, WithRequireNonEmpty())
+		require.Error(t, err)
+		assert.True(t, IsEmptyConfig(err))
+		assert.Contains(t, err.Error(), tempDir)
+
+		// 默认仍然允许空目录
+		_, err = NewManager(tempDir)
+		assert.NoError(t, err)
+	})
+
+	t.Run("reload to empty keeps previous config", func(t *testing.T) {
+		tempDir := t.TempDir()
+		createTestFile(t, tempDir, "app.yml", "app:\n  name: test\n")
+		manager, err := NewManager(tempDir, WithRequireNonEmpty())
+		require.NoError(t, err)
+
+		var got error
+		manager.OnReloadError(func(m *Manager, err error) {
+			got = err
+		})
+
+		require.NoError(t, os.Remove(filepath.Join(tempDir, "app.yml")))
+		manager.handleReload()
+
+		assert.True(t, IsEmptyConfig(got))
+		assert.True(t, IsEmptyConfig(manager.LastReloadError()))
+		assert.Equal(t, "test", manager.MustGet("app").GetString("name"))
+	})
+
+	t.Run("required sections", func(t *testing.T) {
+		tempDir := t.TempDir()
+		createTestFile(t, tempDir, "app.yml", "app:\n  name: test\n")
+
+		_, err := NewManager(tempDir, WithRequireSections("app", "db"))
+		require.Error(t, err)
+		assert.True(t, IsNotFound(err))
+		assert.Contains(t, err.Error(), "db")
+
+		createTestFile(t, tempDir, "db.yml", "db:\n  host: localhost\n")
+		_, err = NewManager(tempDir, WithRequireSections("app", "db"))
+		assert.NoError(t, err)
+
+		_, err = NewManager(t.TempDir(), WithRequireSections())
+		assert.True(t, IsEmptyConfig(err))
+	})
+}
+
 // TestManager_Watch 测试 Watch 方法。
 func TestManager_Watch(t *testing.T) {
 	t.Run("start watching", func(t *testing.T) {
@@ -470,6 +645,29 @@ func TestManager_StopWatch(t *testing.T) {
 	assert.Equal(t, done, manager.watcherDone)
 }
 
+// TestManager_Watch_FakeClock 测试防抖使用 WithClock 设置的时钟：时间推进到防抖间隔之前不会重载
+func TestManager_Watch_FakeClock(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("app:\n  name: v1\n"), 0644))
+
+	c := clock.NewFake(time.Time{})
+	m, err := NewManager(dir, WithClock(c), WithWatchDebounce(time.Minute))
+	require.NoError(t, err)
+	require.NoError(t, m.Watch())
+	defer m.StopWatch()
+
+	require.NoError(t, os.WriteFile(path, []byte("app:\n  name: v2\n"), 0644))
+	c.BlockUntilWaiters(1) // 收到文件事件后启动防抖定时器
+	c.Advance(time.Minute - time.Second)
+	assert.Equal(t, "v1", m.MustGet("app").GetString("name"))
+
+	c.Advance(time.Second)
+	assert.Eventually(t, func() bool {
+		return m.MustGet("app").GetString("name") == "v2"
+	}, 2*time.Second, time.Millisecond)
+}
+
 // TestManager_WatchFileChange 测试文件监听功能。
 func TestManager_WatchFileChange(t *testing.T) {
 	tempDir := t.TempDir()
@@ -525,6 +723,74 @@ func TestManager_WatchFileChange(t *testing.T) {
 	assert.Equal(t, "modified", config.GetString("name"))
 }
 
+// TestManager_WatchFileLifecycle 测试监听期间新增、修改和删除配置文件。
+func TestManager_WatchFileLifecycle(t *testing.T) {
+	tempDir := t.TempDir()
+	createTestConfigFile(t, tempDir, "app.yml", map[string]interface{}{
+		"app": map[string]interface{}{"name": "initial"},
+	})
+
+	manager := MustNewManager(tempDir)
+
+	var mu sync.Mutex
+	var changes []Changes
+	manager.OnReload(func(m *Manager) error {
+		mu.Lock()
+		changes = append(changes, m.LastChanges())
+		mu.Unlock()
+		return nil
+	})
+	require.NoError(t, manager.Watch())
+	defer manager.StopWatch()
+
+	time.Sleep(100 * time.Millisecond) // 给监听器时间启动
+
+	// waitReload 等待第 n 次重载完成并返回其变化
+	waitReload := func(n int) Changes {
+		t.Helper()
+		require.Eventually(t, func() bool {
+			mu.Lock()
+			defer mu.Unlock()
+			return len(changes) >= n
+		}, 2*time.Second, 10*time.Millisecond)
+		mu.Lock()
+		defer mu.Unlock()
+		return changes[n-1]
+	}
+
+	// 新增文件
+	createTestConfigFile(t, tempDir, "modules.yml", map[string]interface{}{
+		"modules": map[string]interface{}{"enabled": true},
+	})
+	c := waitReload(1)
+	assert.Equal(t, []string{"modules"}, c.Added)
+	assert.Equal(t, []string{"app", "modules"}, manager.List())
+	assert.True(t, manager.MustGet("modules").GetBool("enabled"))
+
+	// 修改文件
+	createTestConfigFile(t, tempDir, "app.yml", map[string]interface{}{
+		"app": map[string]interface{}{"name": "modified"},
+	})
+	c = waitReload(2)
+	assert.Equal(t, []string{"app"}, c.Modified)
+	assert.Equal(t, "modified", manager.MustGet("app").GetString("name"))
+
+	// 删除文件
+	require.NoError(t, os.Remove(filepath.Join(tempDir, "modules.yml")))
+	c = waitReload(3)
+	assert.Equal(t, []string{"modules"}, c.Removed)
+	assert.Equal(t, []string{"app"}, manager.List())
+	_, err := manager.Get("modules")
+	assert.True(t, IsNotFound(err))
+
+	// 删除最后一个文件，目录为空时视为空配置
+	require.NoError(t, os.Remove(filepath.Join(tempDir, "app.yml")))
+	c = waitReload(4)
+	assert.Equal(t, []string{"app"}, c.Removed)
+	assert.Empty(t, manager.List())
+	assert.NoError(t, manager.LastReloadError())
+}
+
 // TestInit 测试全局 Init 函数。
 func TestInit(t *testing.T) {
 	t.Run("successful initialization", func(t *testing.T) {
@@ -705,6 +971,67 @@ func BenchmarkManager_Get_Concurrent(b *testing.B) {
 	})
 }
 
+// lockedGet 是 Get 改为 singleflight 之前的双重检查锁定实现，仅用于基准对比。
+func lockedGet(m *Manager, name string) (*viper.Viper, error) {
+	m.mu.RLock()
+	v, ok := m.configs[name]
+	m.mu.RUnlock()
+	if ok {
+		return v, nil
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if v, ok = m.configs[name]; ok {
+		return v, nil
+	}
+	sub := m.root.Sub(name)
+	if sub == nil {
+		return nil, fmt.Errorf("%w: %q", ErrNotFound, name)
+	}
+	m.configs[name] = sub
+	return sub, nil
+}
+
+// BenchmarkManager_Get_ColdStart 对比 100 个 goroutine 同时冷启动获取多个大配置段的耗时。
+func BenchmarkManager_Get_ColdStart(b *testing.B) {
+	const goroutines = 100
+	names := []string{"db", "cache", "queue", "search"}
+
+	tempDir := b.TempDir()
+	for _, name := range names {
+		section := make(map[string]interface{}, 2000)
+		for i := 0; i < 2000; i++ {
+			section[fmt.Sprintf("key_%d", i)] = i
+		}
+		createBenchmarkConfigFile(b, tempDir, name+".yml", map[string]interface{}{name: section})
+	}
+	manager := MustNewManager(tempDir)
+
+	run := func(b *testing.B, get func(m *Manager, name string) (*viper.Viper, error)) {
+		for i := 0; i < b.N; i++ {
+			// 清空缓存，模拟冷启动
+			manager.mu.Lock()
+			manager.configs = make(map[string]*viper.Viper)
+			manager.generation++
+			manager.mu.Unlock()
+
+			var wg sync.WaitGroup
+			for g := 0; g < goroutines; g++ {
+				wg.Add(1)
+				go func(g int) {
+					defer wg.Done()
+					_, _ = get(manager, names[g%len(names)])
+				}(g)
+			}
+			wg.Wait()
+		}
+	}
+
+	b.Run("locked", func(b *testing.B) { run(b, lockedGet) })
+	b.Run("singleflight", func(b *testing.B) { run(b, (*Manager).Get) })
+}
+
 // 辅助函数
 func createTestConfigFile(t *testing.T, dir, filename string, data map[string]interface{}) {
 	t.Helper()
@@ -741,3 +1068,13 @@ func createTestFile(t *testing.T, dir, filename, content string) {
 	err := os.WriteFile(filePath, []byte(content), 0644)
 	require.NoError(t, err)
 }
+
+// TestManager_NilGet 测试 nil Manager 的 Get 返回 ErrNotFound 而不是 panic。
+func TestManager_NilGet(t *testing.T) {
+	var m *Manager
+	_, err := m.Get("app")
+	assert.True(t, IsNotFound(err))
+
+	var cfg struct{ Name string }
+	assert.True(t, IsNotFound(m.Unmarshal("app", &cfg)))
+}
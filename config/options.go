@@ -0,0 +1,191 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/qq1060656096/drugo/pkg/clock"
+	"github.com/spf13/viper"
+)
+
+// Option 定义 Manager 的可选配置项。
+type Option func(*options)
+
+// options 保存 NewManager 的可选配置。
+type options struct {
+	remotes          []RemoteSource   // 远程配置源列表，按注册顺序合并
+	remotePrecedence RemotePrecedence // 远程层与本地文件层的合并优先级
+	remoteLoader     RemoteLoader     // 远程配置读取器
+	watchDebounce    time.Duration    // 文件监听的防抖间隔
+	env              string           // 当前环境名称
+	envPattern       string           // 环境子目录模式
+	envKey           string           // 读取环境名称的基础配置项，见 WithEnvironmentKey
+	requireNonEmpty  bool             // 加载结果没有任何顶级键时返回 ErrEmptyConfig
+	requiredSections []string         // 加载结果必须包含的业务配置
+	extraDirs        []string         // 额外配置目录，按顺序在主目录之后加载
+	mergeStrategy    MergeStrategy    // 额外配置目录之间的合并策略
+	logger           Logger           // 运行日志输出
+	restartBackoff   time.Duration    // 文件监听器重启的初始退避间隔
+	restartMaxDelay  time.Duration    // 文件监听器重启的最大退避间隔
+	restartAttempts  int              // 文件监听器连续重启失败的最大次数
+	strictKeyCase    bool             // 顶级键必须是小写
+	clock            clock.Clock      // 防抖、重启退避与远程轮询使用的时钟
+	historySize      int              // 保留的历史配置代数
+	trackUsage       bool             // 记录配置项的读取，见 WithUsageTracking
+	usageIgnore      []string         // 不参与未使用配置项报告的键路径模式
+	flagHook         FlagStatsHook    // 功能开关的统计回调，见 WithFlagStatsHook
+}
+
+// newOptions 创建带默认值的 options 并应用所有自定义选项。
+func newOptions(opts []Option) *options {
+	o := &options{
+		remotePrecedence: RemoteOverLocal,
+		remoteLoader:     viperRemoteLoader{},
+		watchDebounce:    DefaultWatchDebounce,
+		logger:           stderrLogger{},
+		restartBackoff:   DefaultWatchRestartBackoff,
+		restartMaxDelay:  DefaultWatchRestartMaxDelay,
+		restartAttempts:  DefaultWatchRestartAttempts,
+		historySize:      DefaultHistorySize,
+	}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(o)
+		}
+	}
+	return o
+}
+
+// validate 检查所有选项，返回用 errors.Join 合并的全部无效选项错误，
+// 每个错误都包装了 ErrInvalidOption；所有选项都有效时返回 nil。
+func (o *options) validate() error {
+	var errs []error
+	invalid := func(format string, args ...any) {
+		errs = append(errs, fmt.Errorf("%w: "+format, append([]any{ErrInvalidOption}, args...)...))
+	}
+
+	if o.remotePrecedence != RemoteOverLocal && o.remotePrecedence != RemoteUnderLocal {
+		invalid("unknown remote precedence %d", o.remotePrecedence)
+	}
+	for i, src := range o.remotes {
+		if src.Provider == "" || src.Endpoint == "" || src.Path == "" {
+			invalid("remote[%d] %q: provider, endpoint and path are required", i, src.String())
+		}
+		if src.ConfigType != "" && !slices.Contains(viper.SupportedExts, src.ConfigType) {
+			invalid("remote[%d] %q: unsupported config type %q", i, src.String(), src.ConfigType)
+		}
+	}
+	if !validEnvName(o.env) {
+		invalid("environment %q is not a directory name", o.env)
+	}
+	if o.envPattern != "" && !strings.Contains(o.envPattern, EnvPlaceholder) {
+		invalid("environment pattern %q does not contain %s", o.envPattern, EnvPlaceholder)
+	}
+	if o.mergeStrategy < MergeStrict || o.mergeStrategy > MergeDeep {
+		invalid("unknown merge strategy %d", o.mergeStrategy)
+	}
+	if o.restartBackoff <= 0 || o.restartMaxDelay < o.restartBackoff || o.restartAttempts <= 0 {
+		invalid("watch restart backoff %s..%s with %d attempts", o.restartBackoff, o.restartMaxDelay, o.restartAttempts)
+	}
+	if o.historySize < 0 {
+		invalid("history size %d is negative", o.historySize)
+	}
+	for i, dir := range o.extraDirs {
+		if dir == "" {
+			invalid("extra directory [%d] is empty", i)
+		}
+	}
+	for i, pattern := range o.usageIgnore {
+		if pattern == "" || slices.Contains(strings.Split(pattern, "."), "") {
+			invalid("usage ignore pattern [%d] %q has an empty segment", i, pattern)
+		}
+	}
+	for i, name := range o.requiredSections {
+		if name == "" {
+			invalid("required section [%d] is empty", i)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Options 是 Manager 生效的选项快照，用于诊断，修改它不会影响 Manager。
+type Options struct {
+	Remotes          []RemoteSource   // 远程配置源列表
+	RemotePrecedence RemotePrecedence // 远程层与本地文件层的合并优先级
+	WatchDebounce    time.Duration    // 文件监听的防抖间隔，已应用默认值
+	Env              string           // 当前环境名称，未启用环境分层时为空
+	EnvPattern       string           // 环境子目录模式，启用环境分层时已应用默认值
+	RequireNonEmpty  bool             // 是否要求加载结果非空
+	RequiredSections []string         // 加载结果必须包含的业务配置
+	ExtraDirs        []string         // 额外配置目录
+	MergeStrategy    MergeStrategy    // 额外配置目录之间的合并策略
+	UsageTracking    bool             // 是否记录配置项的读取，见 WithUsageTracking
+}
+
+// Options 返回 Manager 生效的选项副本。
+func (m *Manager) Options() Options {
+	if m == nil || m.opts == nil {
+		return Options{WatchDebounce: DefaultWatchDebounce}
+	}
+	o := Options{
+		Remotes:          slices.Clone(m.opts.remotes),
+		RemotePrecedence: m.opts.remotePrecedence,
+		WatchDebounce:    m.watchDebounce(),
+		Env:              m.opts.env,
+		EnvPattern:       m.opts.envPattern,
+		RequireNonEmpty:  m.opts.requireNonEmpty,
+		RequiredSections: slices.Clone(m.opts.requiredSections),
+		ExtraDirs:        slices.Clone(m.opts.extraDirs),
+		MergeStrategy:    m.opts.mergeStrategy,
+		UsageTracking:    m.opts.trackUsage,
+	}
+	if o.Env != "" && o.EnvPattern == "" {
+		o.EnvPattern = DefaultEnvSubdirPattern
+	}
+	return o
+}
+
+// WithWatchDebounce 设置文件监听的防抖间隔，d <= 0 时使用 DefaultWatchDebounce。
+func WithWatchDebounce(d time.Duration) Option {
+	return func(o *options) {
+		o.watchDebounce = d
+	}
+}
+
+// WithClock 设置文件监听的防抖、监听器重启退避与远程配置轮询使用的时钟，c 为 nil 时使用真实时钟。
+// 测试中可以传入 clock.Fake，通过推进时间触发重载，而不需要真实的 sleep。
+func WithClock(c clock.Clock) Option {
+	return func(o *options) {
+		o.clock = c
+	}
+}
+
+// clock 返回 Manager 使用的时钟，未设置时返回 clock.Real。
+func (m *Manager) clock() clock.Clock {
+	if m == nil || m.opts == nil {
+		return clock.Real{}
+	}
+	return clock.OrReal(m.opts.clock)
+}
+
+// WithRequireNonEmpty 要求加载结果至少包含一个业务配置（顶级键）。
+// 默认情况下空目录是合法的；启用后 NewManager 对空配置返回包装了目录路径的 ErrEmptyConfig，
+// 热加载得到空配置时保留之前的配置，并通过 OnReloadError 报告错误。
+// 适用于配置目录通过挂载提供的部署，避免挂载失败时以零配置启动。
+func WithRequireNonEmpty() Option {
+	return func(o *options) {
+		o.requireNonEmpty = true
+	}
+}
+
+// WithRequireSections 要求加载结果包含所有 names 指定的业务配置，隐含 WithRequireNonEmpty。
+// 缺少任意一个时返回包装了 ErrNotFound 的错误，热加载时同样保留之前的配置。
+func WithRequireSections(names ...string) Option {
+	return func(o *options) {
+		o.requireNonEmpty = true
+		o.requiredSections = append(o.requiredSections, names...)
+	}
+}
@@ -0,0 +1,78 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestNewManager_InvalidOptions 测试多个无效选项被合并为一个错误一次性返回
+func TestNewManager_InvalidOptions(t *testing.T) {
+	_, err := NewManager(t.TempDir(),
+		WithRemotePrecedence(RemotePrecedence(9)),
+		WithRemoteSource(RemoteSource{Provider: "etcd3", Endpoint: "http://127.0.0.1:2379"}),
+		WithRemoteSource(RemoteSource{Provider: "etcd3", Endpoint: "http://127.0.0.1:2379", Path: "/app", ConfigType: "xml"}),
+		WithEnvironment("../prod", "envs"),
+		WithRequireSections("db", ""),
+	)
+	require.Error(t, err)
+	assert.True(t, IsInvalidOption(err))
+
+	var joined interface{ Unwrap() []error }
+	require.True(t, errors.As(err, &joined))
+	assert.Len(t, joined.Unwrap(), 6)
+	for _, want := range []string{"remote precedence 9", "remote[0]", `unsupported config type "xml"`, `"../prod"`, `"envs"`, "required section [1]"} {
+		assert.Contains(t, err.Error(), want)
+	}
+}
+
+// TestNewManager_NoOptions 测试不传选项时的行为保持不变
+func TestNewManager_NoOptions(t *testing.T) {
+	m, err := NewManager(t.TempDir())
+	require.NoError(t, err)
+	assert.Equal(t, Options{RemotePrecedence: RemoteOverLocal, WatchDebounce: DefaultWatchDebounce}, m.Options())
+}
+
+// TestManager_Options 测试每个有效选项都反映在 Options 中，且返回的是副本
+func TestManager_Options(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "app.yaml"), []byte("db:\n  host: localhost\n"), 0644))
+	src := RemoteSource{Provider: "etcd3", Endpoint: "http://127.0.0.1:2379", Path: "/app", ConfigType: "yaml"}
+	loader := RemoteLoaderFunc(func(RemoteSource) (map[string]any, error) {
+		return map[string]any{"cache": map[string]any{"ttl": 1}}, nil
+	})
+
+	m, err := NewManager(dir,
+		WithRemoteSource(src),
+		WithRemoteLoader(loader),
+		WithRemotePrecedence(RemoteUnderLocal),
+		WithWatchDebounce(time.Second),
+		WithEnvironment("prod", ""),
+		WithRequireSections("db"),
+	)
+	require.NoError(t, err)
+
+	opts := m.Options()
+	assert.Equal(t, Options{
+		Remotes:          []RemoteSource{src},
+		RemotePrecedence: RemoteUnderLocal,
+		WatchDebounce:    time.Second,
+		Env:              "prod",
+		EnvPattern:       DefaultEnvSubdirPattern,
+		RequireNonEmpty:  true,
+		RequiredSections: []string{"db"},
+	}, opts)
+
+	opts.Remotes[0].Path = "/changed"
+	opts.RequiredSections[0] = "changed"
+	assert.Equal(t, "/app", m.Options().Remotes[0].Path)
+	assert.Equal(t, []string{"db"}, m.Options().RequiredSections)
+
+	var nilManager *Manager
+	assert.Equal(t, DefaultWatchDebounce, nilManager.Options().WatchDebounce)
+}
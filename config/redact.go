@@ -0,0 +1,50 @@
+package config
+
+import "strings"
+
+// RedactedValue 是脱敏后敏感配置项的替换值
+const RedactedValue = "******"
+
+// sensitiveKeys 是需要脱敏的配置项名称关键字（不区分大小写）
+var sensitiveKeys = []string{"password", "passwd", "secret", "token", "dsn", "private_key", "access_key"}
+
+// Redact 返回 settings 的副本，名称包含敏感关键字（password、secret、token、dsn 等，不区分大小写）的配置项
+// 替换为 RedactedValue。嵌套的 map 与列表中的 map 会递归处理。
+func Redact(settings map[string]any) map[string]any {
+	result := make(map[string]any, len(settings))
+	for key, value := range settings {
+		if IsSensitiveKey(key) {
+			result[key] = RedactedValue
+			continue
+		}
+		result[key] = redactValue(value)
+	}
+	return result
+}
+
+// redactValue 递归处理嵌套的 map 与列表
+func redactValue(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		return Redact(v)
+	case []any:
+		items := make([]any, len(v))
+		for i, item := range v {
+			items[i] = redactValue(item)
+		}
+		return items
+	default:
+		return value
+	}
+}
+
+// IsSensitiveKey 判断配置项名称是否包含敏感关键字
+func IsSensitiveKey(key string) bool {
+	key = strings.ToLower(key)
+	for _, s := range sensitiveKeys {
+		if strings.Contains(key, s) {
+			return true
+		}
+	}
+	return false
+}
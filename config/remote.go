@@ -0,0 +1,227 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// DefaultRemoteWatchInterval 是 WatchRemote 默认的轮询间隔。
+const DefaultRemoteWatchInterval = 30 * time.Second
+
+// RemotePrecedence 决定远程配置层与本地文件层出现同名业务配置时的合并优先级。
+type RemotePrecedence int
+
+const (
+	// RemoteOverLocal 远程配置覆盖本地同名业务配置（默认）。
+	RemoteOverLocal RemotePrecedence = iota
+	// RemoteUnderLocal 本地同名业务配置优先，远程配置只补充本地缺失的业务配置。
+	RemoteUnderLocal
+)
+
+// RemoteSource 描述一个远程配置源（etcd、consul 等）。
+type RemoteSource struct {
+	Provider   string // 远程提供者，例如 etcd3、consul
+	Endpoint   string // 远程地址，例如 http://127.0.0.1:2379
+	Path       string // 远程 KV 路径，例如 /config/app.yaml
+	ConfigType string // 远程内容格式，默认 yaml
+	Required   bool   // 为 true 时读取失败会导致加载失败；否则仅输出警告并跳过该源
+}
+
+// String 返回远程配置源的可读描述。
+func (s RemoteSource) String() string {
+	return s.Provider + "://" + s.Endpoint + s.Path
+}
+
+// RemoteLoader 负责从远程配置源读取配置内容。
+// 返回值的每个顶级键代表一个业务配置，与本地 YAML 文件的组织方式一致。
+type RemoteLoader interface {
+	Load(src RemoteSource) (map[string]any, error)
+}
+
+// RemoteLoaderFunc 是 RemoteLoader 的函数适配器。
+type RemoteLoaderFunc func(src RemoteSource) (map[string]any, error)
+
+// Load 实现 RemoteLoader 接口。
+func (f RemoteLoaderFunc) Load(src RemoteSource) (map[string]any, error) {
+	return f(src)
+}
+
+// viperRemoteLoader 基于 viper 远程提供者读取配置。
+// 使用前需要在 main 包中匿名导入 _ "github.com/spf13/viper/remote" 以启用远程功能。
+type viperRemoteLoader struct{}
+
+// Load 实现 RemoteLoader 接口。
+func (viperRemoteLoader) Load(src RemoteSource) (map[string]any, error) {
+	v := viper.New()
+	configType := src.ConfigType
+	if configType == "" {
+		configType = "yaml"
+	}
+	v.SetConfigType(configType)
+
+	if err := v.AddRemoteProvider(src.Provider, src.Endpoint, src.Path); err != nil {
+		return nil, err
+	}
+	if err := v.ReadRemoteConfig(); err != nil {
+		return nil, err
+	}
+	return v.AllSettings(), nil
+}
+
+// WithRemote 添加一个必需的远程配置源，启动阶段读取失败时 NewManager 返回错误。
+// 可以多次调用以添加多个远程配置源，它们按注册顺序合并。
+func WithRemote(provider, endpoint, path string) Option {
+	return WithRemoteSource(RemoteSource{
+		Provider: provider,
+		Endpoint: endpoint,
+		Path:     path,
+		Required: true,
+	})
+}
+
+// WithOptionalRemote 添加一个可选的远程配置源，读取失败时仅输出警告并跳过。
+func WithOptionalRemote(provider, endpoint, path string) Option {
+	return WithRemoteSource(RemoteSource{
+		Provider: provider,
+		Endpoint: endpoint,
+		Path:     path,
+	})
+}
+
+// WithRemoteSource 使用完整的 RemoteSource 描述添加一个远程配置源。
+func WithRemoteSource(src RemoteSource) Option {
+	return func(o *options) {
+		o.remotes = append(o.remotes, src)
+	}
+}
+
+// WithRemotePrecedence 设置远程层与本地文件层的合并优先级，默认 RemoteOverLocal。
+func WithRemotePrecedence(precedence RemotePrecedence) Option {
+	return func(o *options) {
+		o.remotePrecedence = precedence
+	}
+}
+
+// WithRemoteLoader 替换默认的远程配置读取器。
+// 主要用于测试或接入 viper 不支持的配置中心。
+func WithRemoteLoader(loader RemoteLoader) Option {
+	return func(o *options) {
+		if loader != nil {
+			o.remoteLoader = loader
+		}
+	}
+}
+
+// WatchRemote 以 interval 为间隔轮询所有远程配置源。
+// 当远程内容发生变化时，走与文件热加载相同的重载流程并调用 OnReload 注册的回调。
+// interval 小于等于 0 时使用 DefaultRemoteWatchInterval。
+// 此方法是幂等的，多次调用只会启动一次轮询；StopWatch 会同时停止轮询，之后调用返回 ErrWatchStopped。
+func (m *Manager) WatchRemote(interval time.Duration) error {
+	if m.watchStopped.Load() {
+		return ErrWatchStopped
+	}
+	m.mu.RLock()
+	started := m.remoteWatchDone != nil
+	m.mu.RUnlock()
+	if started || m.opts == nil || len(m.opts.remotes) == 0 {
+		return nil
+	}
+	if interval <= 0 {
+		interval = DefaultRemoteWatchInterval
+	}
+
+	// 在锁外读取远程配置源作为比较基准，慢速的远程配置源不会阻塞 Get、Reset 与 Watch
+	last, err := loadRemotes(m.opts)
+	if err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.watchStopped.Load() {
+		return ErrWatchStopped
+	}
+	if m.remoteWatchDone != nil {
+		return nil
+	}
+
+	m.remoteWatchDone = make(chan struct{})
+	m.remoteWatchExited = make(chan struct{})
+	go func(done <-chan struct{}, exited chan<- struct{}) {
+		defer close(exited)
+		m.remoteWatchLoop(interval, last, done)
+	}(m.remoteWatchDone, m.remoteWatchExited)
+
+	return nil
+}
+
+// remoteWatchLoop 是轮询远程配置变化的主循环。
+// 检测到变化时直接用本次读取的远程配置层重载，应用的内容与检测到的内容一致，每次变化只访问一次远程配置源。
+func (m *Manager) remoteWatchLoop(interval time.Duration, last []map[string]any, done <-chan struct{}) {
+	timer := m.clock().NewTimer(interval)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-timer.C():
+			timer.Reset(interval)
+			layers, err := loadRemotes(m.opts)
+			if err != nil {
+				m.logger().Printf("config remote watch error: %v", err)
+				continue
+			}
+			if reflect.DeepEqual(layers, last) {
+				continue
+			}
+			last = layers
+			m.reloadWith(func() error { return m.resetWith(layers) })
+
+		case <-done:
+			return
+		}
+	}
+}
+
+// loadRemotes 依次读取所有远程配置源。
+// 必需的配置源读取失败时返回 ErrRemoteRead；可选的配置源读取失败时输出警告并跳过。
+func loadRemotes(o *options) ([]map[string]any, error) {
+	if o == nil || len(o.remotes) == 0 {
+		return nil, nil
+	}
+
+	layers := make([]map[string]any, 0, len(o.remotes))
+	for _, src := range o.remotes {
+		settings, err := o.remoteLoader.Load(src)
+		if err != nil {
+			if src.Required {
+				return nil, fmt.Errorf("%w: %s: %v", ErrRemoteRead, src, err)
+			}
+			o.logger.Printf("config: optional remote %s unavailable: %v", src, err)
+			continue
+		}
+		layers = append(layers, settings)
+	}
+	return layers, nil
+}
+
+// applyRemotes 按优先级将远程配置层合并到 root 中。
+// 多个远程层之间按注册顺序合并，后注册的覆盖先注册的同名业务配置。
+func applyRemotes(root *viper.Viper, layers []map[string]any, precedence RemotePrecedence) {
+	local := make(map[string]struct{})
+	for name := range root.AllSettings() {
+		local[name] = struct{}{}
+	}
+
+	for _, layer := range layers {
+		for name, value := range layer {
+			if _, ok := local[name]; ok && precedence == RemoteUnderLocal {
+				continue
+			}
+			root.Set(name, value)
+		}
+	}
+}
@@ -0,0 +1,257 @@
+package config
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeRemoteLoader 是用于测试的远程配置读取器，按 Path 返回预设内容。
+type fakeRemoteLoader struct {
+	mu    sync.Mutex
+	data  map[string]map[string]any
+	errs  map[string]error
+	calls int
+}
+
+func newFakeRemoteLoader() *fakeRemoteLoader {
+	return &fakeRemoteLoader{
+		data: make(map[string]map[string]any),
+		errs: make(map[string]error),
+	}
+}
+
+func (f *fakeRemoteLoader) Load(src RemoteSource) (map[string]any, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls++
+	if err, ok := f.errs[src.Path]; ok {
+		return nil, err
+	}
+	return f.data[src.Path], nil
+}
+
+func (f *fakeRemoteLoader) set(path string, settings map[string]any) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.data[path] = settings
+}
+
+// TestNewManager_WithRemote 测试远程配置层与本地文件的合并。
+func TestNewManager_WithRemote(t *testing.T) {
+	newLocalDir := func(t *testing.T) string {
+		dir := t.TempDir()
+		createTestConfigFile(t, dir, "app.yml", map[string]interface{}{
+			"database": map[string]interface{}{"host": "local"},
+			"app":      map[string]interface{}{"name": "local-app"},
+		})
+		return dir
+	}
+
+	t.Run("remote over local", func(t *testing.T) {
+		loader := newFakeRemoteLoader()
+		loader.set("/app", map[string]any{
+			"database": map[string]any{"host": "remote"},
+			"feature":  map[string]any{"enabled": true},
+		})
+
+		m, err := NewManager(newLocalDir(t),
+			WithRemoteLoader(loader),
+			WithRemote("etcd3", "http://127.0.0.1:2379", "/app"),
+		)
+		require.NoError(t, err)
+		assert.Equal(t, "remote", m.MustGet("database").GetString("host"))
+		assert.Equal(t, "local-app", m.MustGet("app").GetString("name"))
+		assert.True(t, m.MustGet("feature").GetBool("enabled"))
+		assert.Equal(t, []string{"app", "database", "feature"}, m.List())
+	})
+
+	t.Run("remote under local", func(t *testing.T) {
+		loader := newFakeRemoteLoader()
+		loader.set("/app", map[string]any{
+			"database": map[string]any{"host": "remote"},
+			"feature":  map[string]any{"enabled": true},
+		})
+
+		m, err := NewManager(newLocalDir(t),
+			WithRemoteLoader(loader),
+			WithRemote("etcd3", "http://127.0.0.1:2379", "/app"),
+			WithRemotePrecedence(RemoteUnderLocal),
+		)
+		require.NoError(t, err)
+		assert.Equal(t, "local", m.MustGet("database").GetString("host"))
+		assert.True(t, m.MustGet("feature").GetBool("enabled"))
+	})
+
+	t.Run("later remote wins", func(t *testing.T) {
+		loader := newFakeRemoteLoader()
+		loader.set("/a", map[string]any{"cache": map[string]any{"ttl": 1}})
+		loader.set("/b", map[string]any{"cache": map[string]any{"ttl": 2}})
+
+		m, err := NewManager(newLocalDir(t),
+			WithRemoteLoader(loader),
+			WithRemote("consul", "127.0.0.1:8500", "/a"),
+			WithRemote("consul", "127.0.0.1:8500", "/b"),
+		)
+		require.NoError(t, err)
+		assert.Equal(t, 2, m.MustGet("cache").GetInt("ttl"))
+	})
+
+	t.Run("required remote failure is fatal", func(t *testing.T) {
+		loader := newFakeRemoteLoader()
+		loader.errs["/app"] = errors.New("connection refused")
+
+		_, err := NewManager(newLocalDir(t),
+			WithRemoteLoader(loader),
+			WithRemote("etcd3", "http://127.0.0.1:2379", "/app"),
+		)
+		require.Error(t, err)
+		assert.True(t, IsRemoteRead(err))
+	})
+
+	t.Run("optional remote failure is soft", func(t *testing.T) {
+		loader := newFakeRemoteLoader()
+		loader.errs["/app"] = errors.New("connection refused")
+
+		m, err := NewManager(newLocalDir(t),
+			WithRemoteLoader(loader),
+			WithOptionalRemote("etcd3", "http://127.0.0.1:2379", "/app"),
+		)
+		require.NoError(t, err)
+		assert.Equal(t, "local", m.MustGet("database").GetString("host"))
+	})
+
+	t.Run("reset reloads remote layer", func(t *testing.T) {
+		loader := newFakeRemoteLoader()
+		loader.set("/app", map[string]any{"database": map[string]any{"host": "remote-v1"}})
+
+		m, err := NewManager(newLocalDir(t),
+			WithRemoteLoader(loader),
+			WithRemote("etcd3", "http://127.0.0.1:2379", "/app"),
+		)
+		require.NoError(t, err)
+		assert.Equal(t, "remote-v1", m.MustGet("database").GetString("host"))
+
+		loader.set("/app", map[string]any{"database": map[string]any{"host": "remote-v2"}})
+		require.NoError(t, m.Reset())
+		assert.Equal(t, "remote-v2", m.MustGet("database").GetString("host"))
+	})
+}
+
+// TestManager_WatchRemote 测试远程配置轮询触发 OnReload 回调。
+func TestManager_WatchRemote(t *testing.T) {
+	t.Run("change triggers reload", func(t *testing.T) {
+		dir := t.TempDir()
+		loader := newFakeRemoteLoader()
+		loader.set("/app", map[string]any{"database": map[string]any{"host": "v1"}})
+
+		m, err := NewManager(dir,
+			WithRemoteLoader(loader),
+			WithRemote("etcd3", "http://127.0.0.1:2379", "/app"),
+		)
+		require.NoError(t, err)
+
+		reloaded := make(chan string, 1)
+		m.OnReload(func(m *Manager) error {
+			reloaded <- m.MustGet("database").GetString("host")
+			return nil
+		})
+
+		require.NoError(t, m.WatchRemote(10*time.Millisecond))
+		// 幂等
+		require.NoError(t, m.WatchRemote(10*time.Millisecond))
+		defer m.StopWatch()
+
+		loader.set("/app", map[string]any{"database": map[string]any{"host": "v2"}})
+
+		select {
+		case host := <-reloaded:
+			assert.Equal(t, "v2", host)
+		case <-time.After(time.Second):
+			t.Fatal("reload callback was not called")
+		}
+	})
+
+	t.Run("applies the detected snapshot", func(t *testing.T) {
+		// 每次读取返回新的内容：重载应用的必须是检测到变化的那次读取结果，而不是再读取一次的结果
+		var mu sync.Mutex
+		version := 0
+		loader := RemoteLoaderFunc(func(RemoteSource) (map[string]any, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			version++
+			return map[string]any{"database": map[string]any{"version": version}}, nil
+		})
+		m, err := NewManager(t.TempDir(), WithRemoteLoader(loader), WithRemote("etcd3", "http://127.0.0.1:2379", "/app"))
+		require.NoError(t, err)
+
+		reloaded := make(chan int, 16)
+		m.OnReload(func(m *Manager) error {
+			reloaded <- m.MustGet("database").GetInt("version")
+			return nil
+		})
+		require.NoError(t, m.WatchRemote(10*time.Millisecond))
+		defer m.StopWatch()
+
+		select {
+		case v := <-reloaded:
+			// NewManager 读取第 1 版，WatchRemote 读取第 2 版作为基准，第一次轮询读取第 3 版
+			assert.Equal(t, 3, v)
+		case <-time.After(time.Second):
+			t.Fatal("reload callback was not called")
+		}
+	})
+
+	t.Run("slow remote does not block readers", func(t *testing.T) {
+		loader := newFakeRemoteLoader()
+		loader.set("/app", map[string]any{"database": map[string]any{"host": "v1"}})
+		// NewManager 之后的第一次读取（WatchRemote 读取比较基准）阻塞到 release 关闭
+		entered, release := make(chan struct{}), make(chan struct{})
+		var calls atomic.Int32
+		blocking := RemoteLoaderFunc(func(src RemoteSource) (map[string]any, error) {
+			if calls.Add(1) == 2 {
+				close(entered)
+				<-release
+			}
+			return loader.Load(src)
+		})
+		m, err := NewManager(t.TempDir(), WithRemoteLoader(blocking), WithRemote("etcd3", "http://127.0.0.1:2379", "/app"))
+		require.NoError(t, err)
+		defer m.StopWatch()
+		unblock := sync.OnceFunc(func() { close(release) })
+		defer unblock()
+
+		started := make(chan error, 1)
+		go func() { started <- m.WatchRemote(time.Hour) }()
+		<-entered
+
+		got := make(chan string, 1)
+		go func() { got <- m.MustGet("database").GetString("host") }()
+		select {
+		case host := <-got:
+			assert.Equal(t, "v1", host)
+		case <-time.After(time.Second):
+			t.Fatal("Get blocked while WatchRemote was reading the remote source")
+		}
+		unblock()
+		require.NoError(t, <-started)
+	})
+
+	t.Run("no remotes is noop", func(t *testing.T) {
+		m, err := NewManager(t.TempDir())
+		require.NoError(t, err)
+		require.NoError(t, m.WatchRemote(time.Millisecond))
+		assert.Nil(t, m.remoteWatchDone)
+	})
+}
+
+// TestRemoteSource_String 测试远程配置源的字符串表示。
+func TestRemoteSource_String(t *testing.T) {
+	src := RemoteSource{Provider: "etcd3", Endpoint: "http://127.0.0.1:2379", Path: "/app"}
+	assert.Equal(t, "etcd3://http://127.0.0.1:2379/app", src.String())
+}
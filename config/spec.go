@@ -0,0 +1,337 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// Kind 是配置项声明的类型，用于 DeclareSpec。
+type Kind string
+
+// 支持的配置项类型
+const (
+	KindString      Kind = "string"
+	KindInt         Kind = "int"
+	KindBool        Kind = "bool"
+	KindDuration    Kind = "duration"
+	KindFloat       Kind = "float"
+	KindStringSlice Kind = "string slice"
+	KindMap         Kind = "map"
+)
+
+// valid 判断 k 是否为支持的类型
+func (k Kind) valid() bool {
+	switch k {
+	case KindString, KindInt, KindBool, KindDuration, KindFloat, KindStringSlice, KindMap:
+		return true
+	}
+	return false
+}
+
+// DeclareSpec 声明业务配置 name 中各配置项的类型，spec 的键是相对于 name 的点分路径，例如 "server.port"。
+// 声明之后，每次加载（Reset 与热加载）都会按规格校验该业务配置，校验失败时加载失败并保留之前的配置；
+// 未声明规格的业务配置不做校验，配置中不存在的配置项也不做校验（必需性见 WithRequireSections）。
+//
+// 可以无损转换的值（例如字符串 "8080" 声明为 KindInt、"yes" 声明为 KindBool）校验通过，
+// 但会通过 Manager 的 Logger 输出警告，提示运维人员修正配置。
+// 无法转换的配置项以 errors.Join 合并返回，每个错误都包装了 ErrSpecMismatch，
+// 并包含配置项路径、来源文件以及 YAML 中的实际类型。
+//
+// DeclareSpec 会立即校验当前配置并返回校验结果；即使当前配置不满足规格，声明也会保留，
+// 再次调用时替换之前的声明。spec 中包含不支持的类型时返回 ErrInvalidOption，声明不生效。
+func (m *Manager) DeclareSpec(name string, spec map[string]Kind) error {
+	specCopy := make(map[string]Kind, len(spec))
+	var errs []error
+	for key, kind := range spec {
+		if !kind.valid() {
+			errs = append(errs, fmt.Errorf("%w: spec %s.%s: unknown kind %q", ErrInvalidOption, name, key, kind))
+		}
+		specCopy[strings.ToLower(key)] = kind
+	}
+	if err := errors.Join(errs...); err != nil {
+		return err
+	}
+
+	name = strings.ToLower(name)
+	m.specMu.Lock()
+	if m.specs == nil {
+		m.specs = make(map[string]map[string]Kind)
+	}
+	m.specs[name] = specCopy
+	m.specMu.Unlock()
+
+	m.mu.RLock()
+	root, file := m.root, m.sources[name]
+	m.mu.RUnlock()
+	return m.checkSpec(root, name, file, specCopy)
+}
+
+// checkSpecs 按所有已声明的规格校验 root，在 load 中执行，因此 Reset 和热加载失败时会保留之前的配置。
+func (m *Manager) checkSpecs(root *viper.Viper, sources map[string]string) error {
+	m.specMu.Lock()
+	names := make([]string, 0, len(m.specs))
+	specs := make(map[string]map[string]Kind, len(m.specs))
+	for name, spec := range m.specs {
+		names = append(names, name)
+		specs[name] = spec
+	}
+	m.specMu.Unlock()
+	sort.Strings(names)
+
+	var errs []error
+	for _, name := range names {
+		if err := m.checkSpec(root, name, sources[name], specs[name]); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// checkSpec 按 spec 校验业务配置 name，可转换的值输出警告，无法转换的值返回错误
+func (m *Manager) checkSpec(root *viper.Viper, name, file string, spec map[string]Kind) error {
+	if root == nil || !root.IsSet(name) {
+		return nil
+	}
+	keys := make([]string, 0, len(spec))
+	for key := range spec {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var errs []error
+	for _, key := range keys {
+		path := name + "." + key
+		value := root.Get(path)
+		if value == nil {
+			continue
+		}
+		location := path
+		if file != "" {
+			location += " (" + file + ")"
+		}
+		coerced, ok := checkKind(spec[key], value)
+		switch {
+		case !ok:
+			errs = append(errs, fmt.Errorf("%w: %s: want %s, got %s", ErrSpecMismatch, location, spec[key], describeValue(value)))
+		case coerced != "":
+			m.logger().Printf("config spec warning: %s: want %s, got %s; %s", location, spec[key], describeValue(value), coerced)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// checkKind 判断 value 能否转换为 kind。
+// ok 为 false 表示无法无损转换；coerced 非空表示需要转换，内容说明转换结果。
+func checkKind(kind Kind, value any) (coerced string, ok bool) {
+	switch kind {
+	case KindString:
+		switch v := value.(type) {
+		case string:
+			return "", true
+		case bool, int, int64, uint64, float64:
+			return fmt.Sprintf("read as %q", fmt.Sprint(v)), true
+		}
+	case KindInt:
+		switch v := value.(type) {
+		case int, int64, uint64:
+			return "", true
+		case float64:
+			if v == math.Trunc(v) && !math.IsInf(v, 0) {
+				return fmt.Sprintf("read as %d", int64(v)), true
+			}
+		case string:
+			if n, err := strconv.ParseInt(strings.TrimSpace(v), 10, 64); err == nil {
+				return fmt.Sprintf("read as %d", n), true
+			}
+			// "100MB" 等字节大小是 DecodeHook 支持的写法，无需警告
+			if _, err := strconv.ParseFloat(strings.TrimSpace(v), 64); err != nil {
+				if _, ok := parseByteSize(v); ok {
+					return "", true
+				}
+			}
+		}
+	case KindFloat:
+		switch v := value.(type) {
+		case float64, int, int64, uint64:
+			return "", true
+		case string:
+			if f, err := strconv.ParseFloat(strings.TrimSpace(v), 64); err == nil {
+				return fmt.Sprintf("read as %v", f), true
+			}
+		}
+	case KindBool:
+		switch v := value.(type) {
+		case bool:
+			return "", true
+		case int:
+			if v == 0 || v == 1 {
+				return fmt.Sprintf("read as %t", v == 1), true
+			}
+		case string:
+			if b, ok := parseLooseBool(v); ok {
+				return fmt.Sprintf("read as %t", b), true
+			}
+		}
+	case KindDuration:
+		switch v := value.(type) {
+		case string:
+			if _, err := time.ParseDuration(strings.TrimSpace(v)); err == nil {
+				return "", true
+			}
+		case int:
+			// 不带单位的整数会被当作纳秒，几乎总是配置错误，只有 0 没有歧义
+			if v == 0 {
+				return "", true
+			}
+			return "", false
+		}
+	case KindStringSlice:
+		switch v := value.(type) {
+		case []any:
+			for _, elem := range v {
+				switch elem.(type) {
+				case string:
+				case bool, int, int64, uint64, float64:
+					coerced = "non-string elements read as strings"
+				default:
+					return "", false
+				}
+			}
+			return coerced, true
+		case []string:
+			return "", true
+		case string:
+			return "comma-separated string split into a list", true
+		}
+	case KindMap:
+		if _, ok := value.(map[string]any); ok {
+			return "", true
+		}
+	}
+	return "", false
+}
+
+// parseLooseBool 解析 YAML 1.1 风格的布尔字符串，例如 "yes"、"off"、"true"、"0"
+func parseLooseBool(s string) (bool, bool) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "true", "yes", "y", "on", "1":
+		return true, true
+	case "false", "no", "n", "off", "0":
+		return false, true
+	}
+	return false, false
+}
+
+// describeValue 返回值在 YAML 中的类型与内容，用于错误和警告信息
+func describeValue(value any) string {
+	switch v := value.(type) {
+	case string:
+		return fmt.Sprintf("string %q", v)
+	case bool:
+		return fmt.Sprintf("bool %t", v)
+	case int, int64, uint64:
+		return fmt.Sprintf("int %d", v)
+	case float64:
+		return fmt.Sprintf("float %v", v)
+	case []any, []string:
+		return "sequence"
+	case map[string]any:
+		return "mapping"
+	}
+	return fmt.Sprintf("%T", value)
+}
+
+var (
+	durationType = reflect.TypeOf(time.Duration(0))
+	timeType     = reflect.TypeOf(time.Time{})
+)
+
+// SpecFor 通过反射从结构体类型 T 推导规格，可直接传给 DeclareSpec，
+// 使用 Config 反序列化业务配置的代码无需手写规格：
+//
+//	m.DeclareSpec("app", config.SpecFor[AppConfig]())
+//
+// 配置项名称取 mapstructure 标签，没有标签时取小写的字段名；
+// 嵌套结构体展开为点分路径，带 ",squash" 标签的嵌入结构体合并到上一级，标签为 "-" 的字段与未导出字段被忽略。
+// time.Duration 推导为 KindDuration，time.Time 推导为 KindString，
+// 无法对应到 Kind 的字段类型（例如结构体切片、接口）不做校验。T 不是结构体时返回空规格。
+func SpecFor[T any]() map[string]Kind {
+	spec := make(map[string]Kind)
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() == reflect.Struct {
+		specFields(t, "", spec)
+	}
+	return spec
+}
+
+// specFields 将结构体 t 的字段以 prefix 为前缀写入 spec
+func specFields(t reflect.Type, prefix string, spec map[string]Kind) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		name, opts, _ := strings.Cut(f.Tag.Get("mapstructure"), ",")
+		if name == "-" {
+			continue
+		}
+		ft := f.Type
+		for ft.Kind() == reflect.Pointer {
+			ft = ft.Elem()
+		}
+		if ft.Kind() == reflect.Struct && ft != timeType && strings.Contains(opts, "squash") {
+			specFields(ft, prefix, spec)
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		key := prefix + strings.ToLower(name)
+		if ft.Kind() == reflect.Struct && ft != timeType {
+			specFields(ft, key+".", spec)
+			continue
+		}
+		if kind, ok := kindOf(ft); ok {
+			spec[key] = kind
+		}
+	}
+}
+
+// kindOf 返回字段类型对应的 Kind
+func kindOf(t reflect.Type) (Kind, bool) {
+	switch {
+	case t == durationType:
+		return KindDuration, true
+	case t == timeType:
+		return KindString, true
+	}
+	switch t.Kind() {
+	case reflect.String:
+		return KindString, true
+	case reflect.Bool:
+		return KindBool, true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return KindInt, true
+	case reflect.Float32, reflect.Float64:
+		return KindFloat, true
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.String {
+			return KindStringSlice, true
+		}
+	case reflect.Map:
+		return KindMap, true
+	}
+	return "", false
+}
@@ -0,0 +1,167 @@
+package config
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestManager_DeclareSpec_Mismatch 测试无法转换的配置项合并返回，错误中包含路径、来源文件和实际类型
+func TestManager_DeclareSpec_Mismatch(t *testing.T) {
+	dir := t.TempDir()
+	createTestFile(t, dir, "app.yaml", `app:
+  port: abc
+  debug: maybe
+  timeout: 30
+  hosts: [a, [b]]
+  name: demo
+`)
+	m, err := NewManager(dir, WithLogger(&recordLogger{}))
+	require.NoError(t, err)
+
+	err = m.DeclareSpec("app", map[string]Kind{
+		"port":    KindInt,
+		"debug":   KindBool,
+		"timeout": KindDuration,
+		"hosts":   KindStringSlice,
+		"name":    KindString,
+		"missing": KindInt,
+	})
+	require.Error(t, err)
+	assert.True(t, IsSpecMismatch(err))
+	file := filepath.Join(dir, "app.yaml")
+	assert.ErrorContains(t, err, `app.port (`+file+`): want int, got string "abc"`)
+	assert.ErrorContains(t, err, `app.debug (`+file+`): want bool, got string "maybe"`)
+	assert.ErrorContains(t, err, `app.timeout (`+file+`): want duration, got int 30`)
+	assert.ErrorContains(t, err, `app.hosts (`+file+`): want string slice, got sequence`)
+	assert.NotContains(t, err.Error(), "app.name")
+	assert.NotContains(t, err.Error(), "app.missing", "missing keys are not checked")
+
+	// 声明保留，Reset 同样失败
+	assert.True(t, IsSpecMismatch(m.Reset()))
+}
+
+// TestManager_DeclareSpec_Coercion 测试可以无损转换的值校验通过并输出警告
+func TestManager_DeclareSpec_Coercion(t *testing.T) {
+	dir := t.TempDir()
+	createTestFile(t, dir, "app.yaml", `app:
+  port: "8080"
+  enabled: "yes"
+  ratio: 1
+  max_size: 100MB
+  timeout: 30s
+  tags: [a, 1]
+  labels: {k: v}
+`)
+	logger := &recordLogger{}
+	m, err := NewManager(dir, WithLogger(logger))
+	require.NoError(t, err)
+
+	require.NoError(t, m.DeclareSpec("app", map[string]Kind{
+		"port":     KindInt,
+		"enabled":  KindBool,
+		"ratio":    KindFloat,
+		"max_size": KindInt,
+		"timeout":  KindDuration,
+		"tags":     KindStringSlice,
+		"labels":   KindMap,
+	}))
+	assert.True(t, logger.contains(`config spec warning: app.port (`+filepath.Join(dir, "app.yaml")+`): want int, got string "8080"; read as 8080`))
+	assert.True(t, logger.contains(`app.enabled`))
+	assert.True(t, logger.contains(`app.tags`))
+	assert.Len(t, logger.msgs, 3, "values in their declared form do not warn")
+}
+
+// TestManager_DeclareSpec_Nested 测试点分路径声明嵌套配置项，未声明规格的业务配置不做校验
+func TestManager_DeclareSpec_Nested(t *testing.T) {
+	dir := t.TempDir()
+	createTestFile(t, dir, "db.yaml", `db:
+  primary:
+    port: nope
+    pool:
+      size: 10
+`)
+	createTestFile(t, dir, "other.yaml", "other:\n  port: nope\n")
+	m, err := NewManager(dir, WithLogger(&recordLogger{}))
+	require.NoError(t, err)
+
+	err = m.DeclareSpec("db", map[string]Kind{"Primary.Port": KindInt, "primary.pool.size": KindInt})
+	require.Error(t, err)
+	assert.ErrorContains(t, err, `db.primary.port (`)
+	assert.NotContains(t, err.Error(), "pool.size")
+	assert.NotContains(t, err.Error(), "other")
+
+	assert.True(t, IsInvalidOption(m.DeclareSpec("other", map[string]Kind{"port": "uuid"})))
+	assert.NotContains(t, m.Reset().Error(), "other", "invalid declarations are not kept")
+}
+
+// TestManager_DeclareSpec_Reload 测试重载时重新校验，校验失败时保留之前的配置
+func TestManager_DeclareSpec_Reload(t *testing.T) {
+	dir := t.TempDir()
+	createTestFile(t, dir, "app.yaml", "app:\n  port: 8080\n")
+	m, err := NewManager(dir, WithLogger(&recordLogger{}))
+	require.NoError(t, err)
+	require.NoError(t, m.DeclareSpec("app", map[string]Kind{"port": KindInt}))
+
+	createTestFile(t, dir, "app.yaml", "app:\n  port: eighty\n")
+	err = m.Reset()
+	assert.True(t, IsSpecMismatch(err))
+	assert.Equal(t, 8080, m.Root().GetInt("app.port"), "old root is kept")
+
+	var reloadErr error
+	m.OnReloadError(func(_ *Manager, err error) { reloadErr = err })
+	m.handleReload()
+	assert.True(t, IsSpecMismatch(reloadErr))
+	assert.True(t, IsSpecMismatch(m.LastReloadError()))
+
+	createTestFile(t, dir, "app.yaml", "app:\n  port: 9090\n")
+	require.NoError(t, m.Reset())
+	assert.Equal(t, 9090, m.Root().GetInt("app.port"))
+}
+
+type specServer struct {
+	Host    string        `mapstructure:"host"`
+	Port    int           `mapstructure:"port"`
+	Timeout time.Duration `mapstructure:"timeout"`
+}
+
+// SpecBase 是以 squash 嵌入的结构体，需要导出才能被 mapstructure 解码
+type SpecBase struct {
+	Debug bool `mapstructure:"debug"`
+}
+
+type specConfig struct {
+	SpecBase `mapstructure:",squash"`
+	Name     string
+	Server   specServer     `mapstructure:"server"`
+	Backup   *specServer    `mapstructure:"backup"`
+	Ratio    float64        `mapstructure:"ratio"`
+	Tags     []string       `mapstructure:"tags"`
+	Labels   map[string]any `mapstructure:"labels"`
+	Started  time.Time      `mapstructure:"started"`
+	Ignored  string         `mapstructure:"-"`
+	Handlers []specServer   `mapstructure:"handlers"`
+	secret   string
+}
+
+// TestSpecFor 测试从结构体推导规格
+func TestSpecFor(t *testing.T) {
+	assert.Equal(t, map[string]Kind{
+		"debug":          KindBool,
+		"name":           KindString,
+		"server.host":    KindString,
+		"server.port":    KindInt,
+		"server.timeout": KindDuration,
+		"backup.host":    KindString,
+		"backup.port":    KindInt,
+		"backup.timeout": KindDuration,
+		"ratio":          KindFloat,
+		"tags":           KindStringSlice,
+		"labels":         KindMap,
+		"started":        KindString,
+	}, SpecFor[*specConfig]())
+	assert.Empty(t, SpecFor[int]())
+}
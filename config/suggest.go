@@ -0,0 +1,135 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+const (
+	// maxSuggestions 是 NotFoundError 中最多包含的建议数。
+	maxSuggestions = 3
+
+	// maxSuggestCandidates 是计算建议时最多比较的业务配置数，避免包含数百个业务配置时错误路径过慢。
+	maxSuggestCandidates = 512
+)
+
+// NotFoundError 是 Get 找不到业务配置的错误，记录请求的名称以及拼写相近的可用业务配置名称。
+// 它包装了 ErrNotFound，可以通过 IsNotFound 判断，通过 SuggestionsFromError 获取建议。
+type NotFoundError struct {
+	name        string
+	suggestions []string
+}
+
+// Name 返回请求的业务配置名称（原始拼写）。
+func (e *NotFoundError) Name() string {
+	return e.name
+}
+
+// Suggestions 返回拼写相近的业务配置名称，按相近程度排序，没有时返回 nil。
+func (e *NotFoundError) Suggestions() []string {
+	return slices.Clone(e.suggestions)
+}
+
+// Error 实现 error 接口，例如 `config: not found: "datbase" (did you mean "database"?)`。
+func (e *NotFoundError) Error() string {
+	if len(e.suggestions) == 0 {
+		return fmt.Sprintf("%v: %q", ErrNotFound, e.name)
+	}
+	quoted := make([]string, len(e.suggestions))
+	for i, s := range e.suggestions {
+		quoted[i] = fmt.Sprintf("%q", s)
+	}
+	return fmt.Sprintf("%v: %q (did you mean %s?)", ErrNotFound, e.name, strings.Join(quoted, " or "))
+}
+
+// Unwrap 返回 ErrNotFound。
+func (e *NotFoundError) Unwrap() error {
+	return ErrNotFound
+}
+
+// SuggestionsFromError 返回 err 链中 NotFoundError 携带的建议名称，
+// 便于命令行或 HTTP 处理器单独展示；err 不包含 NotFoundError 或没有建议时返回 nil。
+func SuggestionsFromError(err error) []string {
+	var nf *NotFoundError
+	if errors.As(err, &nf) {
+		return nf.Suggestions()
+	}
+	return nil
+}
+
+// newNotFoundError 创建 name 的 NotFoundError，建议从 candidates 中计算。
+// 只在错误路径上调用，命中缓存的 Get 不会产生任何额外开销。
+func newNotFoundError(name string, candidates []string) *NotFoundError {
+	return &NotFoundError{name: name, suggestions: suggest(canonicalName(name), candidates)}
+}
+
+// suggest 返回 candidates 中与 name 编辑距离足够小的名称，按距离与名称排序，最多 maxSuggestions 个。
+// 允许的距离随名称长度增长（1 到 2），只比较前 maxSuggestCandidates 个候选。
+func suggest(name string, candidates []string) []string {
+	if name == "" {
+		return nil
+	}
+	limit := min(2, max(1, len(name)/4))
+	type match struct {
+		name string
+		dist int
+	}
+	var matches []match
+	for _, c := range candidates[:min(len(candidates), maxSuggestCandidates)] {
+		if c == name {
+			continue
+		}
+		// 长度之差是编辑距离的下界，先用它排除大部分候选
+		if d := len(c) - len(name); d > limit || -d > limit {
+			continue
+		}
+		if d := editDistance(name, c, limit); d <= limit {
+			matches = append(matches, match{name: c, dist: d})
+		}
+	}
+	slices.SortFunc(matches, func(a, b match) int {
+		if a.dist != b.dist {
+			return a.dist - b.dist
+		}
+		return strings.Compare(a.name, b.name)
+	})
+	var out []string
+	for _, m := range matches[:min(len(matches), maxSuggestions)] {
+		out = append(out, m.name)
+	}
+	return out
+}
+
+// editDistance 返回 a 与 b 之间的编辑距离（按字节计算的 optimal string alignment 距离），
+// 插入、删除、替换以及相邻字符交换（"datbase" 与 "databsae" 这类常见笔误）各计 1，
+// 某一行的最小值已经超过 limit 时提前返回 limit+1。
+func editDistance(a, b string, limit int) int {
+	prev2 := make([]int, len(b)+1)
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		rowMin := cur[0]
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+			if i > 1 && j > 1 && a[i-1] == b[j-2] && a[i-2] == b[j-1] {
+				cur[j] = min(cur[j], prev2[j-2]+1)
+			}
+			rowMin = min(rowMin, cur[j])
+		}
+		if rowMin > limit {
+			return limit + 1
+		}
+		prev2, prev, cur = prev, cur, prev2
+	}
+	return prev[len(b)]
+}
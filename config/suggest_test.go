@@ -0,0 +1,98 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newSuggestManager 创建包含 database、cache 与 server 三个业务配置的 Manager。
+func newSuggestManager(t *testing.T) *Manager {
+	t.Helper()
+	dir := t.TempDir()
+	createTestConfigFile(t, dir, "app.yml", map[string]interface{}{
+		"database": map[string]interface{}{"host": "localhost"},
+		"cache":    map[string]interface{}{"type": "redis"},
+		"server":   map[string]interface{}{"port": 8080},
+	})
+	return MustNewManager(dir)
+}
+
+// TestManager_Get_Suggestions 测试拼写相近时错误中包含建议，无关名称不包含建议。
+func TestManager_Get_Suggestions(t *testing.T) {
+	m := newSuggestManager(t)
+
+	t.Run("near miss", func(t *testing.T) {
+		_, err := m.Get("datbase")
+		require.Error(t, err)
+		assert.EqualError(t, err, `config: not found: "datbase" (did you mean "database"?)`)
+		assert.True(t, IsNotFound(err))
+		assert.ErrorIs(t, err, ErrNotFound)
+		assert.Equal(t, []string{"database"}, SuggestionsFromError(err))
+	})
+
+	t.Run("case insensitive", func(t *testing.T) {
+		_, err := m.Get("Cahce")
+		assert.Equal(t, []string{"cache"}, SuggestionsFromError(err))
+		assert.Contains(t, err.Error(), `"Cahce"`)
+	})
+
+	t.Run("unrelated name", func(t *testing.T) {
+		_, err := m.Get("payments")
+		assert.EqualError(t, err, `config: not found: "payments"`)
+		assert.True(t, IsNotFound(err))
+		assert.Nil(t, SuggestionsFromError(err))
+	})
+
+	t.Run("must get", func(t *testing.T) {
+		defer func() {
+			err, ok := recover().(error)
+			require.True(t, ok)
+			assert.True(t, IsNotFound(err))
+			assert.Equal(t, []string{"server"}, SuggestionsFromError(err))
+		}()
+		m.MustGet("sever")
+	})
+}
+
+// TestSuggestionsFromError 测试从包装的错误链中提取建议。
+func TestSuggestionsFromError(t *testing.T) {
+	err := fmt.Errorf("load user module: %w", newNotFoundError("datbase", []string{"cache", "database"}))
+	assert.Equal(t, []string{"database"}, SuggestionsFromError(err))
+
+	var nf *NotFoundError
+	require.True(t, errors.As(err, &nf))
+	assert.Equal(t, "datbase", nf.Name())
+
+	assert.Nil(t, SuggestionsFromError(ErrNotFound))
+	assert.Nil(t, SuggestionsFromError(errors.New("other")))
+	assert.Nil(t, SuggestionsFromError(nil))
+}
+
+// TestSuggest 测试建议按编辑距离排序、数量有上限，且只比较有限数量的候选。
+func TestSuggest(t *testing.T) {
+	assert.Equal(t, []string{"redis", "redis1", "redis2"}, suggest("rediss", []string{"redis2", "mysql", "redis", "redis1", "redis3"}))
+	assert.Equal(t, []string{"db"}, suggest("d", []string{"db", "mq"}))
+	assert.Nil(t, suggest("database", []string{"database"}))
+	assert.Nil(t, suggest("", []string{"a"}))
+
+	candidates := make([]string, 0, maxSuggestCandidates+1)
+	for i := range maxSuggestCandidates {
+		candidates = append(candidates, fmt.Sprintf("section%04d", i))
+	}
+	candidates = append(candidates, "database")
+	assert.Nil(t, suggest("datbase", candidates))
+}
+
+// TestEditDistance 测试编辑距离、相邻字符交换与提前返回。
+func TestEditDistance(t *testing.T) {
+	assert.Equal(t, 1, editDistance("datbase", "database", 2))
+	assert.Equal(t, 1, editDistance("databsae", "database", 2))
+	assert.Equal(t, 1, editDistance("cahce", "cache", 2))
+	assert.Equal(t, 3, editDistance("kitten", "sitting", 5))
+	assert.Equal(t, 2, editDistance("abcdef", "uvwxyz", 1))
+	assert.Equal(t, 0, editDistance("", "", 1))
+}
@@ -0,0 +1,93 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeGeneration 将同一个版本号写入 gin 与 tls 两个配置文件
+func writeGeneration(t *testing.T, dir string, gen int) {
+	t.Helper()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "gin.yaml"), fmt.Appendf(nil, "gin:\n  gen: %d\n", gen), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "tls.yaml"), fmt.Appendf(nil, "tls:\n  gen: %d\n", gen), 0644))
+}
+
+// TestManager_Transaction 测试并发重载时 Transaction 始终读到同一版本的多个业务配置
+func TestManager_Transaction(t *testing.T) {
+	dir := t.TempDir()
+	writeGeneration(t, dir, 0)
+	m, err := NewManager(dir)
+	require.NoError(t, err)
+
+	const reloads = 50
+	var done atomic.Bool
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		defer done.Store(true)
+		for gen := 1; gen <= reloads; gen++ {
+			writeGeneration(t, dir, gen)
+			if err := m.Reset(); err != nil {
+				t.Error(err)
+				return
+			}
+		}
+	}()
+
+	var checks atomic.Int64
+	for range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for !done.Load() {
+				err := m.Transaction(func(root *viper.Viper) error {
+					gin := root.GetInt("gin.gen")
+					// 在读取两个业务配置之间等待一次重载
+					for m.Root() == root && !done.Load() {
+						runtime.Gosched()
+					}
+					if tls := root.GetInt("tls.gen"); tls != gin {
+						return fmt.Errorf("mixed generation: gin=%d tls=%d", gin, tls)
+					}
+					return nil
+				})
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				checks.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Positive(t, checks.Load())
+	require.NoError(t, m.Transaction(func(root *viper.Viper) error {
+		assert.Equal(t, reloads, root.GetInt("gin.gen"))
+		assert.Equal(t, reloads, root.GetInt("tls.gen"))
+		return nil
+	}))
+}
+
+// TestManager_Transaction_Error 测试返回 fn 的错误以及 nil Manager
+func TestManager_Transaction_Error(t *testing.T) {
+	m, err := NewManager(t.TempDir())
+	require.NoError(t, err)
+	assert.ErrorIs(t, m.Transaction(func(*viper.Viper) error { return ErrNotFound }), ErrNotFound)
+
+	var nilManager *Manager
+	assert.NoError(t, nilManager.Transaction(func(root *viper.Viper) error {
+		assert.Empty(t, root.AllSettings())
+		return nil
+	}))
+}
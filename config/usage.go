@@ -0,0 +1,219 @@
+package config
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-viper/mapstructure/v2"
+	"github.com/spf13/viper"
+)
+
+// WithUsageTracking 启用配置项的读取跟踪，用于发现从未被读取的配置项（通常是拼写错误或遗留的配置），
+// 见 Manager.UnusedKeys。
+//
+// 只有通过 Manager 的类型化读取方法读取的配置项会被记录：Config、Manager.Unmarshal、Manager.UnmarshalStrict、
+// ConfigWithFallback，以及 WatchKey 监听中的键路径。通过 Get 返回的 *viper.Viper 直接读取无法被拦截，
+// 可以调用 Manager.MarkRead 手动记录。
+//
+// ignore 是不参与报告的点分隔键路径模式，"*" 匹配任意一段；模式匹配键路径的前缀时整个子树都被忽略，
+// 例如 "log" 忽略日志配置的所有配置项，"*.debug" 忽略所有业务配置中的 debug 配置项。
+// 未启用时读取方法不做任何额外的工作。
+func WithUsageTracking(ignore ...string) Option {
+	return func(o *options) {
+		o.trackUsage = true
+		o.usageIgnore = append(o.usageIgnore, ignore...)
+	}
+}
+
+// usageTracker 记录配置项最近一次被读取的时间，由 WithUsageTracking 启用。
+type usageTracker struct {
+	ignore [][]string // 小写的忽略模式，按段拆分
+
+	mu    sync.Mutex
+	reads map[string]time.Time // 点分隔键路径到最近读取时间的映射，路径可以是子树
+}
+
+// newUsageTracker 根据选项创建读取跟踪器，未启用 WithUsageTracking 时返回 nil。
+func newUsageTracker(o *options) *usageTracker {
+	if !o.trackUsage {
+		return nil
+	}
+	t := &usageTracker{reads: make(map[string]time.Time)}
+	for _, pattern := range o.usageIgnore {
+		t.ignore = append(t.ignore, strings.Split(strings.ToLower(pattern), "."))
+	}
+	return t
+}
+
+// mark 将 paths 记录为在 at 时被读取，路径代表以它为根的整个子树。
+func (t *usageTracker) mark(at time.Time, paths ...string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for _, p := range paths {
+		t.reads[strings.ToLower(p)] = at
+	}
+}
+
+// readSince 报告 key 或其任一上级路径是否在 cutoff 之后（含）被读取过，cutoff 为零值时只要求读取过。
+func (t *usageTracker) readSince(key string, cutoff time.Time) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for p := key; ; {
+		if at, ok := t.reads[p]; ok && !at.Before(cutoff) {
+			return true
+		}
+		i := strings.LastIndexByte(p, '.')
+		if i < 0 {
+			return false
+		}
+		p = p[:i]
+	}
+}
+
+// ignored 报告 key 是否匹配任一忽略模式。
+func (t *usageTracker) ignored(key string) bool {
+	segments := strings.Split(key, ".")
+	for _, pattern := range t.ignore {
+		if matchPrefix(pattern, segments) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchPrefix 报告按段拆分的 pattern 是否匹配 segments 的前缀，"*" 匹配任意一段。
+func matchPrefix(pattern, segments []string) bool {
+	if len(pattern) > len(segments) {
+		return false
+	}
+	for i, p := range pattern {
+		if p != "*" && p != segments[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// unmarshal 使用 DecodeHook 将 v 反序列化到 out，strict 为 true 时配置中存在 out 未定义的字段返回错误。
+// 启用 WithUsageTracking 时，names 中每个业务配置下被解码到 out 的配置项都记录为已读取；
+// 没有对应字段的配置项不记录。
+func (m *Manager) unmarshal(v *viper.Viper, out any, strict bool, names ...string) error {
+	if m == nil || m.usage == nil {
+		if strict {
+			return UnmarshalStrict(v, out)
+		}
+		return Unmarshal(v, out)
+	}
+
+	var md mapstructure.Metadata
+	opts := []viper.DecoderConfigOption{
+		viper.DecodeHook(DecodeHook()),
+		func(c *mapstructure.DecoderConfig) { c.Metadata = &md },
+	}
+	var err error
+	if strict {
+		err = v.UnmarshalExact(out, opts...)
+	} else {
+		err = v.Unmarshal(out, opts...)
+	}
+	if err != nil {
+		return err
+	}
+
+	var unused []string
+	for _, name := range md.Unused {
+		if p, ok := metadataPath(name); ok {
+			unused = append(unused, p)
+		}
+	}
+	var paths []string
+	for _, key := range v.AllKeys() {
+		if underAny(key, unused) {
+			continue
+		}
+		for _, name := range names {
+			paths = append(paths, canonicalName(name)+"."+key)
+		}
+	}
+	m.usage.mark(m.clock().Now(), paths...)
+	return nil
+}
+
+// metadataPath 将 mapstructure 元数据中的字段名转换为点分隔的键路径，map 的元素 "a[k]" 转换为 "a.k"。
+// 字段名包含切片下标时返回 false：viper 将切片视为单个配置项，只要切片被解码就视为已读取。
+func metadataPath(name string) (string, bool) {
+	var b strings.Builder
+	for {
+		i := strings.IndexByte(name, '[')
+		if i < 0 {
+			b.WriteString(name)
+			return strings.ToLower(b.String()), true
+		}
+		j := strings.IndexByte(name[i:], ']')
+		if j < 0 {
+			return "", false
+		}
+		key := name[i+1 : i+j]
+		if key != "" && strings.Trim(key, "0123456789") == "" {
+			return "", false
+		}
+		b.WriteString(name[:i])
+		b.WriteByte('.')
+		b.WriteString(key)
+		name = name[i+j+1:]
+	}
+}
+
+// underAny 报告 key 是否等于 prefixes 中的某个路径或位于其子树中。
+func underAny(key string, prefixes []string) bool {
+	for _, p := range prefixes {
+		if key == p || strings.HasPrefix(key, p+".") {
+			return true
+		}
+	}
+	return false
+}
+
+// MarkRead 将点分隔键路径 paths 记录为已读取，路径代表以它为根的整个子树，例如 "redis" 记录整个业务配置。
+// 用于通过 Get 返回的 *viper.Viper 直接读取配置的代码；未启用 WithUsageTracking 时不做任何事。
+func (m *Manager) MarkRead(paths ...string) {
+	if m == nil || m.usage == nil {
+		return
+	}
+	m.usage.mark(m.clock().Now(), paths...)
+}
+
+// UnusedKeys 返回当前配置中没有被读取过的配置项的有序列表（小写的点分隔键路径），
+// 匹配 WithUsageTracking 忽略模式的配置项除外；WatchKey 监听中的键路径在取消订阅之前始终视为已读取。
+// since > 0 时，最近 since 时间内没有被读取的配置项也视为未使用。
+// 未启用 WithUsageTracking 时返回 nil，启用但所有配置项都被读取过时返回空切片。
+func (m *Manager) UnusedKeys(since time.Duration) []string {
+	if m == nil || m.usage == nil {
+		return nil
+	}
+	m.mu.RLock()
+	root := m.root
+	var watched []string
+	for _, w := range m.keyWatchers {
+		if !w.removed.Load() {
+			watched = append(watched, strings.Join(w.keys, "."))
+		}
+	}
+	m.mu.RUnlock()
+
+	var cutoff time.Time
+	if since > 0 {
+		cutoff = m.clock().Now().Add(-since)
+	}
+	unused := []string{}
+	for _, key := range root.AllKeys() {
+		if m.usage.ignored(key) || underAny(key, watched) || m.usage.readSince(key, cutoff) {
+			continue
+		}
+		unused = append(unused, key)
+	}
+	sort.Strings(unused)
+	return unused
+}
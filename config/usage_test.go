@@ -0,0 +1,147 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/qq1060656096/drugo/pkg/clock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const usageConfig = `database:
+  host: localhost
+  port: 3306
+  pool:
+    max_open: 10
+    max_idle: 2
+  typo_timeout: 3s
+features:
+  flags:
+    checkout: true
+    search: false
+  debug: true
+cache:
+  ttl: 1m
+  servers: [a, b]
+`
+
+// usageDatabase 只定义了 database 中的部分配置项
+type usageDatabase struct {
+	Host string
+	Port int
+	Pool struct {
+		MaxOpen int `mapstructure:"max_open"`
+	}
+}
+
+// newUsageManager 创建使用 usageConfig 的 Manager
+func newUsageManager(t *testing.T, opts ...Option) *Manager {
+	t.Helper()
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "app.yaml"), []byte(usageConfig), 0644))
+	return MustNewManager(dir, opts...)
+}
+
+// TestManager_UnusedKeys 测试通过类型化读取方法解码的配置项被记录，没有对应字段的嵌套配置项被报告
+func TestManager_UnusedKeys(t *testing.T) {
+	m := newUsageManager(t, WithUsageTracking())
+	assert.True(t, m.Options().UsageTracking)
+	assert.Equal(t, []string{
+		"cache.servers", "cache.ttl",
+		"database.host", "database.pool.max_idle", "database.pool.max_open", "database.port", "database.typo_timeout",
+		"features.debug", "features.flags.checkout", "features.flags.search",
+	}, m.UnusedKeys(0))
+
+	_, err := Config[usageDatabase](m, "database")
+	require.NoError(t, err)
+	// 解码到 map 的业务配置整体记录为已读取，切片视为单个配置项
+	_, err = Config[map[string]any](m, "cache")
+	require.NoError(t, err)
+	var features struct {
+		Flags map[string]bool
+	}
+	require.NoError(t, m.Unmarshal("features", &features))
+
+	assert.Equal(t, []string{"database.pool.max_idle", "database.typo_timeout", "features.debug"}, m.UnusedKeys(0))
+
+	// UnmarshalStrict 失败时不记录
+	var strict usageDatabase
+	require.Error(t, m.UnmarshalStrict("database", &strict))
+	m.MarkRead("database.typo_timeout", "FEATURES.Debug")
+	assert.Equal(t, []string{"database.pool.max_idle"}, m.UnusedKeys(0))
+}
+
+// TestManager_UnusedKeys_Ignore 测试忽略模式按段匹配键路径的前缀
+func TestManager_UnusedKeys_Ignore(t *testing.T) {
+	m := newUsageManager(t, WithUsageTracking("Cache", "*.debug", "database.*.max_idle"))
+	assert.Equal(t, []string{
+		"database.host", "database.pool.max_open", "database.port", "database.typo_timeout",
+		"features.flags.checkout", "features.flags.search",
+	}, m.UnusedKeys(0))
+
+	_, err := NewManager(t.TempDir(), WithUsageTracking("database..host", ""))
+	require.Error(t, err)
+	assert.True(t, IsInvalidOption(err))
+}
+
+// TestManager_UnusedKeys_Since 测试读取时间窗口、回退链与 WatchKey
+func TestManager_UnusedKeys_Since(t *testing.T) {
+	c := clock.NewFake(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+	m := newUsageManager(t, WithUsageTracking("cache", "features"), WithClock(c))
+
+	var pool struct {
+		MaxOpen int `mapstructure:"max_open"`
+	}
+	require.NoError(t, m.Unmarshal("database.pool", &pool))
+	c.Advance(time.Minute)
+	_, err := ConfigWithFallback[struct{ Host string }](m, "database", "cache")
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"database.pool.max_idle", "database.port", "database.typo_timeout"}, m.UnusedKeys(0))
+	assert.Equal(t, []string{"database.pool.max_idle", "database.pool.max_open", "database.port", "database.typo_timeout"},
+		m.UnusedKeys(30*time.Second))
+
+	unsubscribe, err := m.WatchKey("Database.Pool", func(old, new any) {})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"database.port", "database.typo_timeout"}, m.UnusedKeys(30*time.Second))
+	unsubscribe()
+	assert.Contains(t, m.UnusedKeys(30*time.Second), "database.pool.max_idle")
+}
+
+// TestManager_UnusedKeys_Disabled 测试未启用时不记录读取，UnusedKeys 返回 nil
+func TestManager_UnusedKeys_Disabled(t *testing.T) {
+	m := newUsageManager(t)
+	assert.Nil(t, m.usage)
+	assert.False(t, m.Options().UsageTracking)
+	_, err := Config[usageDatabase](m, "database")
+	require.NoError(t, err)
+	m.MarkRead("database")
+	assert.Nil(t, m.UnusedKeys(0))
+
+	var nilManager *Manager
+	nilManager.MarkRead("database")
+	assert.Nil(t, nilManager.UnusedKeys(0))
+}
+
+// TestMetadataPath 测试 mapstructure 字段名到键路径的转换
+func TestMetadataPath(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+		ok   bool
+	}{
+		{"pool.max_idle", "pool.max_idle", true},
+		{"labels[env]", "labels.env", true},
+		{"Shards[eu].Replicas[b].Host", "shards.eu.replicas.b.host", true},
+		{"servers[0].host", "", false},
+		{"broken[key", "", false},
+	}
+	for _, tt := range tests {
+		got, ok := metadataPath(tt.name)
+		assert.Equal(t, tt.ok, ok, tt.name)
+		assert.Equal(t, tt.want, got, tt.name)
+	}
+}
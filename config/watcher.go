@@ -0,0 +1,123 @@
+package config
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// 文件监听器自动重建的默认配置。
+const (
+	// DefaultWatchRestartBackoff 是监听器重建的初始退避间隔，每次失败后翻倍。
+	DefaultWatchRestartBackoff = 100 * time.Millisecond
+	// DefaultWatchRestartMaxDelay 是监听器重建的最大退避间隔。
+	DefaultWatchRestartMaxDelay = 30 * time.Second
+	// DefaultWatchRestartAttempts 是监听器退出后连续重建失败的最大次数，超过后停止重建。
+	DefaultWatchRestartAttempts = 10
+)
+
+// newFSWatcher 创建底层的文件监听器，测试中可以替换以模拟监听器失效。
+var newFSWatcher = fsnotify.NewWatcher
+
+// WatcherStats 是文件监听器的运行状态，见 Manager.WatcherStats。
+type WatcherStats struct {
+	Watching  bool  // 监听器是否正在运行
+	Restarts  int   // 监听器意外退出后成功重建的次数
+	LastError error // 最近一次监听器退出或重建失败的原因
+	Failed    bool  // 重建次数耗尽，热加载已永久失效
+}
+
+// WithWatchRestart 设置监听器意外退出后的重建策略：
+// 首次重建前等待 backoff，每次失败后等待时间翻倍，最多 maxDelay；连续失败 attempts 次后停止重建，
+// WatcherStats 的 Failed 变为 true。默认值见 DefaultWatchRestartBackoff、DefaultWatchRestartMaxDelay、DefaultWatchRestartAttempts。
+func WithWatchRestart(backoff, maxDelay time.Duration, attempts int) Option {
+	return func(o *options) {
+		o.restartBackoff = backoff
+		o.restartMaxDelay = maxDelay
+		o.restartAttempts = attempts
+	}
+}
+
+// WatcherStats 返回文件监听器的运行状态。
+func (m *Manager) WatcherStats() WatcherStats {
+	if m == nil {
+		return WatcherStats{}
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.watchStats
+}
+
+// superviseWatch 运行 watchLoop，并在监听器意外退出（而不是 StopWatch）时重建监听器。
+func (m *Manager) superviseWatch(watcher *fsnotify.Watcher, done <-chan struct{}) {
+	for {
+		m.watchLoop(watcher, done)
+		select {
+		case <-done:
+			return
+		default:
+		}
+
+		watcher.Close()
+		err := fmt.Errorf("%w: event channel closed", ErrWatcherFailed)
+		m.logger().Printf("config watcher stopped unexpectedly: %v", err)
+		m.mu.Lock()
+		m.watchStats.Watching = false
+		m.watchStats.LastError = err
+		m.mu.Unlock()
+
+		if watcher = m.restartWatcher(done); watcher == nil {
+			return
+		}
+	}
+}
+
+// restartWatcher 按指数退避重建监听器，成功时返回新的监听器；
+// done 关闭或重建次数耗尽时返回 nil。
+func (m *Manager) restartWatcher(done <-chan struct{}) *fsnotify.Watcher {
+	delay := m.opts.restartBackoff
+	for attempt := 1; attempt <= m.opts.restartAttempts; attempt++ {
+		timer := m.clock().NewTimer(delay)
+		select {
+		case <-timer.C():
+		case <-done:
+			timer.Stop()
+			return nil
+		}
+
+		m.logger().Printf("config watcher restarting (attempt %d/%d)", attempt, m.opts.restartAttempts)
+		watcher, err := m.newWatcher()
+		if err != nil {
+			m.logger().Printf("config watcher restart failed: %v", err)
+			m.mu.Lock()
+			m.watchStats.LastError = err
+			m.mu.Unlock()
+			delay = min(delay*2, m.opts.restartMaxDelay)
+			continue
+		}
+
+		m.mu.Lock()
+		// 重建期间调用了 StopWatch 时丢弃新的监听器
+		select {
+		case <-done:
+			m.mu.Unlock()
+			watcher.Close()
+			return nil
+		default:
+		}
+		m.watcher = watcher
+		m.watchStats.Watching = true
+		m.watchStats.Restarts++
+		m.mu.Unlock()
+		m.logger().Printf("config watcher restarted")
+		return watcher
+	}
+
+	m.mu.Lock()
+	m.watchStats.Failed = true
+	lastErr := m.watchStats.LastError
+	m.mu.Unlock()
+	m.logger().Printf("config watcher restart attempts exhausted, hot reload disabled: %v", lastErr)
+	return nil
+}
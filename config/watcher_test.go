@@ -0,0 +1,278 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+)
+
+// recordLogger 记录 Manager 输出的运行日志
+type recordLogger struct {
+	mu   sync.Mutex
+	msgs []string
+}
+
+func (l *recordLogger) Printf(format string, args ...any) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.msgs = append(l.msgs, fmt.Sprintf(format, args...))
+}
+
+func (l *recordLogger) contains(s string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, msg := range l.msgs {
+		if strings.Contains(msg, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// hookWatchers 替换 newFSWatcher，记录创建的监听器；fail 返回 true 时创建失败
+func hookWatchers(t *testing.T, fail func() bool) func() []*fsnotify.Watcher {
+	var mu sync.Mutex
+	var watchers []*fsnotify.Watcher
+	orig := newFSWatcher
+	newFSWatcher = func() (*fsnotify.Watcher, error) {
+		if fail != nil && fail() {
+			return nil, errors.New("too many open files")
+		}
+		w, err := orig()
+		if err == nil {
+			mu.Lock()
+			watchers = append(watchers, w)
+			mu.Unlock()
+		}
+		return w, err
+	}
+	t.Cleanup(func() { newFSWatcher = orig })
+	return func() []*fsnotify.Watcher {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]*fsnotify.Watcher(nil), watchers...)
+	}
+}
+
+// TestManager_Watch_Restart 测试监听器意外退出后自动重建，重建后热加载继续生效
+func TestManager_Watch_Restart(t *testing.T) {
+	watchers := hookWatchers(t, nil)
+	dir := t.TempDir()
+	createTestConfigFile(t, dir, "app.yml", map[string]interface{}{"app": map[string]interface{}{"name": "v1"}})
+
+	logger := &recordLogger{}
+	m, err := NewManager(dir, WithWatchDebounce(10*time.Millisecond),
+		WithWatchRestart(10*time.Millisecond, 50*time.Millisecond, 3), WithLogger(logger))
+	require.NoError(t, err)
+	require.NoError(t, m.Watch())
+	defer m.StopWatch()
+	assert.Equal(t, WatcherStats{Watching: true}, m.WatcherStats())
+
+	// 在监听协程之外关闭监听器，模拟 inotify 实例失效
+	require.Len(t, watchers(), 1)
+	require.NoError(t, watchers()[0].Close())
+
+	require.Eventually(t, func() bool {
+		stats := m.WatcherStats()
+		return stats.Watching && stats.Restarts == 1
+	}, 2*time.Second, 5*time.Millisecond)
+	assert.Len(t, watchers(), 2)
+	assert.True(t, IsWatcherFailed(m.WatcherStats().LastError))
+	assert.False(t, m.WatcherStats().Failed)
+	assert.True(t, logger.contains("restarting (attempt 1/3)"))
+
+	time.Sleep(50 * time.Millisecond) // 给新的监听器时间启动
+	createTestConfigFile(t, dir, "app.yml", map[string]interface{}{"app": map[string]interface{}{"name": "v2"}})
+	assert.Eventually(t, func() bool {
+		return m.MustGet("app").GetString("name") == "v2"
+	}, 2*time.Second, 10*time.Millisecond)
+}
+
+// TestManager_Watch_RestartExhausted 测试重建次数耗尽后进入永久失效状态
+func TestManager_Watch_RestartExhausted(t *testing.T) {
+	var mu sync.Mutex
+	broken := false
+	watchers := hookWatchers(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return broken
+	})
+	dir := t.TempDir()
+	logger := &recordLogger{}
+	m, err := NewManager(dir, WithWatchRestart(time.Millisecond, 4*time.Millisecond, 3), WithLogger(logger))
+	require.NoError(t, err)
+	require.NoError(t, m.Watch())
+	defer m.StopWatch()
+
+	mu.Lock()
+	broken = true
+	mu.Unlock()
+	require.NoError(t, watchers()[0].Close())
+
+	require.Eventually(t, func() bool { return m.WatcherStats().Failed }, 2*time.Second, 5*time.Millisecond)
+	stats := m.WatcherStats()
+	assert.False(t, stats.Watching)
+	assert.Zero(t, stats.Restarts)
+	assert.ErrorContains(t, stats.LastError, "too many open files")
+	assert.True(t, logger.contains("attempt 3/3"))
+	assert.True(t, logger.contains("exhausted"))
+}
+
+// TestManager_StopWatch_StopsSupervisor 测试 StopWatch 同时终止等待重建的监听协程
+func TestManager_StopWatch_StopsSupervisor(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+	watchers := hookWatchers(t, nil)
+	m, err := NewManager(t.TempDir(), WithWatchRestart(time.Hour, time.Hour, 1))
+	require.NoError(t, err)
+	require.NoError(t, m.Watch())
+
+	require.NoError(t, watchers()[0].Close())
+	require.Eventually(t, func() bool { return !m.WatcherStats().Watching }, 2*time.Second, 5*time.Millisecond)
+
+	m.StopWatch()
+	assert.Len(t, watchers(), 1)
+	assert.False(t, m.WatcherStats().Failed)
+}
+
+// TestNewManager_InvalidWatchRestart 测试无效的重建策略
+func TestNewManager_InvalidWatchRestart(t *testing.T) {
+	_, err := NewManager(t.TempDir(), WithWatchRestart(0, time.Second, 1))
+	assert.True(t, IsInvalidOption(err))
+	_, err = NewManager(t.TempDir(), WithWatchRestart(time.Second, time.Millisecond, 1))
+	assert.True(t, IsInvalidOption(err))
+}
+
+// TestManager_StopWatch_WaitsForReload 测试 StopWatch 等待正在执行的重载完成，返回后不再调用回调
+func TestManager_StopWatch_WaitsForReload(t *testing.T) {
+	dir := t.TempDir()
+	writeFeatures(t, dir, "features:\n  v: 1\n")
+	m, err := NewManager(dir, WithWatchDebounce(time.Millisecond))
+	require.NoError(t, err)
+
+	entered := make(chan struct{}, 1)
+	release := make(chan struct{})
+	var stopped, late atomic.Bool
+	m.OnReload(func(*Manager) error {
+		if stopped.Load() {
+			late.Store(true)
+		}
+		select {
+		case entered <- struct{}{}:
+			<-release
+		default:
+		}
+		return nil
+	})
+	require.NoError(t, m.Watch())
+
+	writeFeatures(t, dir, "features:\n  v: 2\n")
+	select {
+	case <-entered:
+	case <-time.After(2 * time.Second):
+		t.Fatal("reload callback not called")
+	}
+
+	returned := make(chan struct{})
+	go func() {
+		m.StopWatch()
+		stopped.Store(true)
+		close(returned)
+	}()
+	select {
+	case <-returned:
+		t.Fatal("StopWatch returned while a reload callback was running")
+	case <-time.After(50 * time.Millisecond):
+	}
+	close(release)
+	select {
+	case <-returned:
+	case <-time.After(2 * time.Second):
+		t.Fatal("StopWatch did not return after the reload finished")
+	}
+
+	writeFeatures(t, dir, "features:\n  v: 3\n")
+	time.Sleep(50 * time.Millisecond)
+	assert.False(t, late.Load())
+	assert.True(t, IsWatchStopped(m.Watch()))
+	assert.True(t, IsWatchStopped(m.WatchRemote(time.Second)))
+}
+
+// TestManager_StopWatch_Stress 在文件持续变化时并发调用 StopWatch、Watch 与 Reset，
+// 检查不会死锁、StopWatch 返回后不再调用回调且后台协程全部退出。使用 -race 运行。
+func TestManager_StopWatch_Stress(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+	for i := range 20 {
+		dir := t.TempDir()
+		writeFeatures(t, dir, "features:\n  v: 0\n")
+		m, err := NewManager(dir, WithWatchDebounce(time.Millisecond))
+		require.NoError(t, err)
+		var stopped, late atomic.Bool
+		m.OnReload(func(*Manager) error {
+			if stopped.Load() {
+				late.Store(true)
+			}
+			return nil
+		})
+		require.NoError(t, m.Watch())
+
+		quit := make(chan struct{})
+		var wg sync.WaitGroup
+		wg.Go(func() {
+			for n := 0; ; n++ {
+				select {
+				case <-quit:
+					return
+				default:
+				}
+				writeFeatures(t, dir, fmt.Sprintf("features:\n  v: %d\n", n))
+				time.Sleep(100 * time.Microsecond)
+			}
+		})
+		wg.Go(func() {
+			for {
+				select {
+				case <-quit:
+					return
+				default:
+				}
+				assert.NoError(t, m.Reset())
+			}
+		})
+		time.Sleep(time.Duration(i%5) * time.Millisecond)
+
+		var stops sync.WaitGroup
+		for range 3 {
+			stops.Go(func() {
+				if err := m.Watch(); err != nil {
+					assert.True(t, IsWatchStopped(err))
+				}
+				m.StopWatch()
+			})
+		}
+		done := make(chan struct{})
+		go func() {
+			stops.Wait()
+			close(done)
+		}()
+		select {
+		case <-done:
+		case <-time.After(stopWatchTimeout):
+			t.Fatalf("iteration %d: StopWatch deadlocked", i)
+		}
+		stopped.Store(true)
+
+		time.Sleep(5 * time.Millisecond)
+		close(quit)
+		wg.Wait()
+		require.False(t, late.Load(), "iteration %d: reload callback called after StopWatch returned", i)
+	}
+}
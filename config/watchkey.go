@@ -0,0 +1,165 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"runtime/debug"
+	"strings"
+	"sync/atomic"
+
+	"github.com/spf13/viper"
+)
+
+// KeyCallback 是 WatchKey 注册的回调函数类型，old 与 new 为键路径在重载前后的值（深拷贝），
+// 键路径不存在时对应的值为 nil。
+type KeyCallback func(old, new any)
+
+// keyWatcher 记录一个键路径监听
+type keyWatcher struct {
+	path    string
+	keys    []string
+	fn      KeyCallback
+	removed atomic.Bool // 取消订阅后置位，正在进行的重载不再调用该回调
+}
+
+// WatchKey 注册监听点分隔键路径（例如 "features.checkout.new_flow"）变化的回调。
+// 每次成功重载后比较该路径在重载前后的值（map 与 slice 深度比较），只在值变化时调用 cb；
+// 路径可以尚不存在，键出现时 old 为 nil，键被删除时 new 为 nil。
+// 键路径不区分大小写，与 viper 一致。
+//
+// cb 在不持有 Manager 锁的情况下于 OnReload 回调之后调用，可以调用 Manager 的其他方法；
+// 传入的值是深拷贝，修改它们不会影响配置。cb 中的 panic 会被恢复并记录，
+// 与 OnReload 回调一样计入 LastReloadError（ErrCallbackPanic）。
+//
+// 返回的 unsubscribe 函数是幂等的，可以与正在进行的重载并发调用，也可以在 cb 中调用：
+// 取消之后不会再发起新的 cb 调用，已经开始的调用不受影响。
+// 路径为空或包含空的段时返回 ErrInvalidKeyPath。
+func (m *Manager) WatchKey(path string, cb KeyCallback) (unsubscribe func(), err error) {
+	keys := strings.Split(strings.ToLower(path), ".")
+	for _, key := range keys {
+		if key == "" {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidKeyPath, path)
+		}
+	}
+	w := &keyWatcher{path: path, keys: keys, fn: cb}
+
+	m.mu.Lock()
+	m.keyWatchers = append(m.keyWatchers, w)
+	m.mu.Unlock()
+
+	return func() {
+		if w.removed.Swap(true) {
+			return
+		}
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		for i, cur := range m.keyWatchers {
+			if cur == w {
+				m.keyWatchers = append(m.keyWatchers[:i:i], m.keyWatchers[i+1:]...)
+				break
+			}
+		}
+	}, nil
+}
+
+// notifyKeyWatchers 比较 before 与 after 中所有被监听的键路径，并调用值发生变化的回调，
+// 返回回调 panic 转换成的错误。调用方不能持有 mu。
+func (m *Manager) notifyKeyWatchers(before, after *viper.Viper) []error {
+	m.mu.RLock()
+	watchers := append([]*keyWatcher(nil), m.keyWatchers...)
+	m.mu.RUnlock()
+	if len(watchers) == 0 {
+		return nil
+	}
+
+	old, cur := allSettings(before), allSettings(after)
+	var errs []error
+	for _, w := range watchers {
+		if w.removed.Load() {
+			continue
+		}
+		prev, next := lookupKeys(old, w.keys), lookupKeys(cur, w.keys)
+		if reflect.DeepEqual(prev, next) {
+			continue
+		}
+		if err := m.runKeyCallback(w, deepCopy(prev), deepCopy(next)); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errs
+}
+
+// runKeyCallback 执行单个键路径回调，并将回调中的 panic 转换为错误。
+func (m *Manager) runKeyCallback(w *keyWatcher, old, new any) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			m.logger().Printf("config key watcher %q panic: %v\n%s", w.path, r, debug.Stack())
+			err = fmt.Errorf("%w: key %q: %v", ErrCallbackPanic, w.path, r)
+		}
+	}()
+	w.fn(old, new)
+	return nil
+}
+
+// allSettings 返回 v 的全部配置，v 为 nil 时返回 nil。
+func allSettings(v *viper.Viper) map[string]any {
+	if v == nil {
+		return nil
+	}
+	return v.AllSettings()
+}
+
+// lookupKeys 沿键路径逐级查找嵌套的配置值，路径不存在时返回 nil。
+func lookupKeys(settings map[string]any, keys []string) any {
+	var value any = settings
+	for _, key := range keys {
+		m, ok := value.(map[string]any)
+		if !ok {
+			return nil
+		}
+		if value, ok = m[key]; !ok {
+			return nil
+		}
+	}
+	return value
+}
+
+// deepCopy 递归复制 v 中的 map 与 slice，其他值原样返回。
+func deepCopy(v any) any {
+	if v == nil {
+		return nil
+	}
+	return deepCopyValue(reflect.ValueOf(v)).Interface()
+}
+
+func deepCopyValue(v reflect.Value) reflect.Value {
+	switch v.Kind() {
+	case reflect.Map:
+		if v.IsNil() {
+			return v
+		}
+		c := reflect.MakeMapWithSize(v.Type(), v.Len())
+		for iter := v.MapRange(); iter.Next(); {
+			c.SetMapIndex(iter.Key(), deepCopyValue(iter.Value()))
+		}
+		return c
+	case reflect.Slice:
+		if v.IsNil() {
+			return v
+		}
+		c := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		for i := 0; i < v.Len(); i++ {
+			c.Index(i).Set(deepCopyValue(v.Index(i)))
+		}
+		return c
+	case reflect.Interface:
+		if v.IsNil() {
+			return v
+		}
+		c := reflect.New(v.Type()).Elem()
+		c.Set(deepCopyValue(v.Elem()))
+		return c
+	default:
+		return v
+	}
+}
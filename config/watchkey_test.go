@@ -0,0 +1,214 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// keyChange 是键路径回调收到的一次变化
+type keyChange struct {
+	old, new any
+}
+
+// watchKeyRecorder 注册键路径回调并记录收到的变化
+func watchKeyRecorder(t *testing.T, m *Manager, path string) (func() []keyChange, func()) {
+	t.Helper()
+	var mu sync.Mutex
+	var changes []keyChange
+	unsubscribe, err := m.WatchKey(path, func(old, new any) {
+		mu.Lock()
+		defer mu.Unlock()
+		changes = append(changes, keyChange{old: old, new: new})
+	})
+	require.NoError(t, err)
+	return func() []keyChange {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]keyChange(nil), changes...)
+	}, unsubscribe
+}
+
+func writeFeatures(t testing.TB, dir, content string) {
+	t.Helper()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "features.yaml"), []byte(content), 0644))
+}
+
+// TestManager_WatchKey_Leaf 测试只在被监听的叶子值变化时调用回调，其他键的变化被忽略。
+func TestManager_WatchKey_Leaf(t *testing.T) {
+	dir := t.TempDir()
+	writeFeatures(t, dir, "features:\n  checkout:\n    new_flow: false\n  search: v1\n")
+	m := MustNewManager(dir)
+	changes, _ := watchKeyRecorder(t, m, "features.checkout.New_Flow")
+
+	writeFeatures(t, dir, "features:\n  checkout:\n    new_flow: false\n  search: v2\n")
+	m.handleReload()
+	assert.Empty(t, changes(), "unrelated keys do not notify")
+
+	writeFeatures(t, dir, "features:\n  checkout:\n    new_flow: true\n  search: v2\n")
+	m.handleReload()
+	assert.Equal(t, []keyChange{{old: false, new: true}}, changes())
+
+	m.handleReload()
+	assert.Len(t, changes(), 1, "unchanged value does not notify")
+	assert.NoError(t, m.LastReloadError())
+}
+
+// TestManager_WatchKey_Section 测试监听非叶子路径时深度比较，并传入深拷贝的值。
+func TestManager_WatchKey_Section(t *testing.T) {
+	dir := t.TempDir()
+	writeFeatures(t, dir, "features:\n  checkout:\n    regions: [eu, us]\n")
+	m := MustNewManager(dir)
+
+	var got keyChange
+	_, err := m.WatchKey("features.checkout", func(old, new any) {
+		got = keyChange{old: old, new: new}
+		// 修改回调收到的值不能影响配置
+		new.(map[string]any)["regions"].([]any)[0] = "mutated"
+	})
+	require.NoError(t, err)
+
+	m.handleReload()
+	assert.Nil(t, got.new, "deep-equal sections do not notify")
+
+	writeFeatures(t, dir, "features:\n  checkout:\n    regions: [eu, us, ap]\n")
+	m.handleReload()
+	assert.Equal(t, map[string]any{"regions": []any{"eu", "us"}}, got.old)
+	assert.Equal(t, []any{"eu", "us", "ap"}, m.Root().Get("features.checkout.regions"))
+}
+
+// TestManager_WatchKey_AppearDisappear 测试键出现时 old 为 nil，键被删除时 new 为 nil。
+func TestManager_WatchKey_AppearDisappear(t *testing.T) {
+	dir := t.TempDir()
+	writeFeatures(t, dir, "features:\n  search: v1\n")
+	m := MustNewManager(dir)
+	changes, _ := watchKeyRecorder(t, m, "features.checkout.new_flow")
+
+	writeFeatures(t, dir, "features:\n  search: v1\n  checkout:\n    new_flow: true\n")
+	m.handleReload()
+	writeFeatures(t, dir, "features:\n  search: v1\n")
+	m.handleReload()
+	require.NoError(t, os.Remove(filepath.Join(dir, "features.yaml")))
+	m.handleReload()
+
+	assert.Equal(t, []keyChange{{old: nil, new: true}, {old: true, new: nil}}, changes())
+}
+
+// TestManager_WatchKey_Panic 测试回调 panic 被隔离，其他回调仍然执行，并计入 LastReloadError。
+func TestManager_WatchKey_Panic(t *testing.T) {
+	dir := t.TempDir()
+	writeFeatures(t, dir, "features:\n  a: 1\n")
+	m := MustNewManager(dir)
+	_, err := m.WatchKey("features.a", func(old, new any) { panic("boom") })
+	require.NoError(t, err)
+	changes, _ := watchKeyRecorder(t, m, "features.a")
+
+	writeFeatures(t, dir, "features:\n  a: 2\n")
+	m.handleReload()
+	assert.Len(t, changes(), 1)
+	err = m.LastReloadError()
+	assert.True(t, IsCallbackPanic(err))
+	assert.Contains(t, err.Error(), `"features.a"`)
+}
+
+// TestManager_WatchKey_Unsubscribe 测试取消订阅后不再调用回调，可以在回调中或与重载并发调用。
+func TestManager_WatchKey_Unsubscribe(t *testing.T) {
+	t.Run("取消后不再通知", func(t *testing.T) {
+		dir := t.TempDir()
+		writeFeatures(t, dir, "features:\n  a: 1\n")
+		m := MustNewManager(dir)
+		changes, unsubscribe := watchKeyRecorder(t, m, "features.a")
+
+		unsubscribe()
+		unsubscribe()
+		writeFeatures(t, dir, "features:\n  a: 2\n")
+		m.handleReload()
+		assert.Empty(t, changes())
+	})
+
+	t.Run("在回调中取消", func(t *testing.T) {
+		dir := t.TempDir()
+		writeFeatures(t, dir, "features:\n  a: 1\n")
+		m := MustNewManager(dir)
+		calls := 0
+		var unsubscribe func()
+		unsubscribe, err := m.WatchKey("features.a", func(old, new any) {
+			calls++
+			unsubscribe()
+		})
+		require.NoError(t, err)
+
+		for i := 2; i <= 3; i++ {
+			writeFeatures(t, dir, fmt.Sprintf("features:\n  a: %d\n", i))
+			m.handleReload()
+		}
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("与重载并发", func(t *testing.T) {
+		dir := t.TempDir()
+		writeFeatures(t, dir, "features:\n  a: 0\n")
+		m := MustNewManager(dir)
+
+		var wg sync.WaitGroup
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 1; i <= 20; i++ {
+				writeFeatures(t, dir, fmt.Sprintf("features:\n  a: %d\n", i))
+				m.handleReload()
+			}
+		}()
+		for range 50 {
+			_, unsubscribe := watchKeyRecorder(t, m, "features.a")
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				unsubscribe()
+			}()
+		}
+		wg.Wait()
+
+		m.mu.RLock()
+		defer m.mu.RUnlock()
+		assert.Empty(t, m.keyWatchers)
+	})
+}
+
+// TestManager_WatchKey_InvalidPath 测试无效的键路径。
+func TestManager_WatchKey_InvalidPath(t *testing.T) {
+	m := MustNewManager(t.TempDir())
+	for _, path := range []string{"", ".", "a..b", "a."} {
+		_, err := m.WatchKey(path, func(old, new any) {})
+		assert.True(t, IsInvalidKeyPath(err), path)
+	}
+}
+
+// BenchmarkManager_WatchKey_Reload 测试注册了大量键路径回调时单次重载的开销。
+func BenchmarkManager_WatchKey_Reload(b *testing.B) {
+	const flags = 500
+	dir := b.TempDir()
+	var content strings.Builder
+	content.WriteString("features:\n")
+	for i := range flags {
+		fmt.Fprintf(&content, "  flag_%d: true\n", i)
+	}
+	writeFeatures(b, dir, content.String())
+	m := MustNewManager(dir)
+	for i := range flags {
+		if _, err := m.WatchKey(fmt.Sprintf("features.flag_%d", i), func(old, new any) {}); err != nil {
+			b.Fatal(err)
+		}
+	}
+
+	b.ResetTimer()
+	for range b.N {
+		m.handleReload()
+	}
+}
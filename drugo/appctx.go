@@ -0,0 +1,19 @@
+package drugo
+
+import "context"
+
+// AppContext 返回应用级上下文，它派生自 WithContext 设置的上下文，在 Shutdown 开始时取消。
+//
+// 传给服务 Boot 的上下文会随它一同取消，服务在 Boot 中启动的后台 goroutine
+// 可以直接监听 Boot 收到的 ctx；其他位置（例如 Run 之外的回调）也可以显式获取它。
+func (d *Drugo) AppContext() context.Context {
+	return d.appCtx
+}
+
+// bootContext 返回传给服务 Boot 的上下文：保留 ctx 携带的值，在 ctx 或应用级上下文取消时取消。
+// Boot 返回后不会取消，以便服务在 Boot 中启动的后台 goroutine 一直运行到停机。
+func (d *Drugo) bootContext(ctx context.Context) context.Context {
+	ctx, cancel := context.WithCancel(ctx)
+	context.AfterFunc(d.appCtx, cancel)
+	return ctx
+}
@@ -0,0 +1,80 @@
+package drugo
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+)
+
+// helperService 在 Boot 中启动监听 ctx.Done() 的后台 goroutine
+type helperService struct {
+	name    string
+	stopped chan struct{}
+	closing chan bool // Close 时后台 goroutine 是否已经退出
+}
+
+func (s *helperService) Name() string { return s.name }
+
+func (s *helperService) Boot(ctx context.Context) error {
+	s.stopped = make(chan struct{})
+	s.closing = make(chan bool, 1)
+	go func() {
+		<-ctx.Done()
+		close(s.stopped)
+	}()
+	return nil
+}
+
+func (s *helperService) Close(ctx context.Context) error {
+	select {
+	case <-s.stopped:
+		s.closing <- true
+	case <-ctx.Done():
+		s.closing <- false
+	}
+	return nil
+}
+
+// TestDrugo_Shutdown_CancelsBootContext 测试 Shutdown 开始时取消 Boot 的上下文，
+// 服务在 Boot 中启动的 goroutine 在 Close 之前退出，不会泄漏
+func TestDrugo_Shutdown_CancelsBootContext(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	s := &helperService{name: "helper"}
+	app := New(WithService(s))
+	app.logger = newTestLogManager(t)
+
+	require.NoError(t, app.Boot(context.Background()))
+	select {
+	case <-s.stopped:
+		t.Fatal("boot context cancelled after Boot returned")
+	case <-time.After(20 * time.Millisecond):
+	}
+	assert.NoError(t, app.AppContext().Err())
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	require.NoError(t, app.Shutdown(ctx))
+	assert.True(t, <-s.closing, "helper goroutine still running during Close")
+	assert.ErrorIs(t, app.AppContext().Err(), context.Canceled)
+}
+
+// TestDrugo_AppContext 测试应用级上下文派生自 WithContext，Boot 的上下文保留调用方的值
+func TestDrugo_AppContext(t *testing.T) {
+	type key struct{}
+	parent, cancel := context.WithCancel(context.WithValue(context.Background(), key{}, "app"))
+	app := New(WithContext(parent))
+	assert.Equal(t, "app", app.AppContext().Value(key{}))
+
+	bootCtx := app.bootContext(context.WithValue(context.Background(), key{}, "boot"))
+	assert.Equal(t, "boot", bootCtx.Value(key{}))
+
+	cancel()
+	<-app.AppContext().Done()
+	<-bootCtx.Done()
+	assert.ErrorIs(t, bootCtx.Err(), context.Canceled)
+}
@@ -0,0 +1,161 @@
+package drugo
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"runtime"
+	"time"
+
+	"github.com/qq1060656096/drugo/config"
+	"github.com/qq1060656096/drugo/kernel"
+	"go.uber.org/zap"
+)
+
+// BootFailureFileName 是启动失败报告的文件名，写在启动报告文件（见 WithBootReportFile）所在的目录
+const BootFailureFileName = "boot-failure.json"
+
+// BootFailureReport 是 Boot 失败时采集的报告，便于在 CI 中作为构建产物附加。
+// 配置中的敏感项已脱敏。
+type BootFailureReport struct {
+	Time    time.Time // 采集时间
+	App     string    // 框架名称
+	Version string    // 框架版本
+	Env     string    // 运行环境
+
+	Service      string          // 启动失败的服务，Boot 之前的检查（例如能力依赖）失败时为空
+	Error        string          // 完整的错误信息
+	ErrorChain   []ErrorLink     // 从最外层开始逐层 Unwrap 得到的错误链
+	Booted       []ServiceTiming // 启动成功的服务及其耗时
+	Degraded     []string        // 启动失败但作为可选服务继续的服务
+	NotAttempted []string        // 没有尝试启动的服务
+
+	ConfigSection string         // 失败服务的配置段，服务没有实现 kernel.Configurable 时为空
+	Config        map[string]any // 失败服务配置段的脱敏配置，配置段不存在时为 nil
+
+	Runtime RuntimeInfo // 运行环境信息
+	Build   BuildInfo   // 构建信息
+}
+
+// ErrorLink 是错误链中的一个错误。
+type ErrorLink struct {
+	Type    string // 错误的 Go 类型
+	Message string // 错误信息
+}
+
+// RuntimeInfo 是进程的运行环境信息。
+type RuntimeInfo struct {
+	GoVersion string // 运行时的 Go 版本
+	GOOS      string
+	GOARCH    string
+	NumCPU    int
+}
+
+// LastBootFailure 返回最近一次 Boot 失败时采集的报告副本，没有失败过时返回 false。
+func (d *Drugo) LastBootFailure() (*BootFailureReport, bool) {
+	d.reportMu.RLock()
+	defer d.reportMu.RUnlock()
+	if d.bootFailure == nil {
+		return nil, false
+	}
+	r := *d.bootFailure
+	r.ErrorChain = append([]ErrorLink(nil), r.ErrorChain...)
+	r.Booted = append([]ServiceTiming(nil), r.Booted...)
+	r.Degraded = append([]string(nil), r.Degraded...)
+	r.NotAttempted = append([]string(nil), r.NotAttempted...)
+	r.Config = config.Redact(r.Config)
+	return &r, true
+}
+
+// captureBootFailure 采集启动失败报告，service 为启动失败的服务（Boot 之前的检查失败时为 nil），
+// 并在启用 WithBootReportFile 时写入启动报告所在目录的 BootFailureFileName
+func (d *Drugo) captureBootFailure(l *zap.Logger, service kernel.Service, err error) {
+	report := &BootFailureReport{
+		Time:       d.Clock().Now(),
+		App:        Name,
+		Version:    Version(),
+		Error:      err.Error(),
+		ErrorChain: errorChain(err),
+		Runtime: RuntimeInfo{
+			GoVersion: runtime.Version(),
+			GOOS:      runtime.GOOS,
+			GOARCH:    runtime.GOARCH,
+			NumCPU:    runtime.NumCPU(),
+		},
+		Build: readBuildInfo(),
+	}
+	if d.config != nil {
+		report.Env = d.config.Environment()
+	}
+
+	timings := make(map[string]ServiceTiming)
+	for _, t := range d.Timings().Services {
+		timings[t.Name] = t
+	}
+	status := d.Status()
+	for _, s := range d.Container().Services() {
+		name := s.Name()
+		if service != nil && name == service.Name() {
+			continue
+		}
+		switch status[name].State {
+		case ServiceStateBooted:
+			report.Booted = append(report.Booted, timings[name])
+		case ServiceStateDegraded:
+			report.Degraded = append(report.Degraded, name)
+		default:
+			report.NotAttempted = append(report.NotAttempted, name)
+		}
+	}
+
+	if service != nil {
+		report.Service = service.Name()
+		if _, ok := service.(kernel.Configurable); ok {
+			report.ConfigSection = d.configSection(service)
+			if v, err := d.Config().Get(report.ConfigSection); err == nil {
+				report.Config = config.Redact(v.AllSettings())
+			}
+		}
+	}
+
+	d.reportMu.Lock()
+	d.bootFailure = report
+	d.reportMu.Unlock()
+
+	if d.bootReportFile == "" {
+		return
+	}
+	path := filepath.Join(filepath.Dir(ResolveDir(d.Root(), d.bootReportFile, DefaultBootReportFile)), BootFailureFileName)
+	failure, _ := d.LastBootFailure()
+	if err := writeBootReport(path, failure); err != nil {
+		l.Error("boot failure report write failed", zap.String("path", path), zap.Error(err))
+		return
+	}
+	d.reportMu.Lock()
+	d.bootFailureFile = path
+	d.reportMu.Unlock()
+	l.Info("boot failure report written", zap.String("path", path))
+}
+
+// bootFailurePath 返回最近一次写入的启动失败报告文件路径，没有写入时返回空字符串
+func (d *Drugo) bootFailurePath() string {
+	d.reportMu.RLock()
+	defer d.reportMu.RUnlock()
+	return d.bootFailureFile
+}
+
+// errorChain 从 err 开始逐层 Unwrap，返回错误链；errors.Join 等多错误按顺序展开每个分支
+func errorChain(err error) []ErrorLink {
+	var chain []ErrorLink
+	for err != nil {
+		chain = append(chain, ErrorLink{Type: fmt.Sprintf("%T", err), Message: err.Error()})
+		if multi, ok := err.(interface{ Unwrap() []error }); ok {
+			for _, e := range multi.Unwrap() {
+				chain = append(chain, errorChain(e)...)
+			}
+			return chain
+		}
+		err = errors.Unwrap(err)
+	}
+	return chain
+}
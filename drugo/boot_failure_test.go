@@ -0,0 +1,93 @@
+package drugo
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/qq1060656096/drugo/config"
+	"github.com/qq1060656096/drugo/kernel"
+	"github.com/qq1060656096/drugo/kernel/kerneltest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestDrugo_Boot_FailureReport 测试五个服务中第三个启动失败时的报告内容与报告文件
+func TestDrugo_Boot_FailureReport(t *testing.T) {
+	errDial := errors.New("dial tcp: connection refused")
+	failing := &configurableMockService{ServiceMock: kerneltest.NewServiceMock("db")}
+	failing.BootFunc = func(context.Context) error { return fmt.Errorf("connect primary: %w", errDial) }
+
+	root := t.TempDir()
+	app := New(
+		WithRoot(root),
+		WithServices(kerneltest.NewServiceMock("cache"), kerneltest.NewServiceMock("queue"), failing,
+			kerneltest.NewServiceMock("http"), kerneltest.NewServiceMock("worker")),
+		WithBootReportFile(""),
+	)
+	app.logger = newTestLogManager(t)
+	app.config = newTestConfigManager(t, "db:\n  host: localhost\n  password: p@ss\n")
+
+	_, ok := app.LastBootFailure()
+	assert.False(t, ok)
+	err := app.Boot(context.Background())
+	require.ErrorIs(t, err, errDial)
+
+	report, ok := app.LastBootFailure()
+	require.True(t, ok)
+	assert.Equal(t, "db", report.Service)
+	assert.Equal(t, err.Error(), report.Error)
+	require.Len(t, report.Booted, 2)
+	assert.Equal(t, "cache", report.Booted[0].Name)
+	assert.Equal(t, "queue", report.Booted[1].Name)
+	assert.Empty(t, report.Degraded)
+	assert.Equal(t, []string{"http", "worker"}, report.NotAttempted)
+
+	// 错误链从最外层的内核错误一直展开到原始错误
+	require.GreaterOrEqual(t, len(report.ErrorChain), 3)
+	assert.Equal(t, err.Error(), report.ErrorChain[0].Message)
+	assert.Contains(t, report.ErrorChain, ErrorLink{Type: "*fmt.wrapError", Message: "connect primary: dial tcp: connection refused"})
+	assert.Equal(t, ErrorLink{Type: "*errors.errorString", Message: errDial.Error()}, report.ErrorChain[len(report.ErrorChain)-1])
+	assert.True(t, errors.Is(err, kernel.ErrServiceInitFailed))
+
+	assert.Equal(t, "db", report.ConfigSection)
+	assert.Equal(t, "localhost", report.Config["host"])
+	assert.Equal(t, config.RedactedValue, report.Config["password"])
+	assert.Equal(t, runtime.GOOS, report.Runtime.GOOS)
+	assert.Equal(t, runtime.Version(), report.Runtime.GoVersion)
+
+	// 报告文件写在启动报告所在的目录，启动报告本身不会生成
+	path := filepath.Join(root, filepath.Dir(DefaultBootReportFile), BootFailureFileName)
+	assert.Equal(t, path, app.bootFailurePath())
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	var written BootFailureReport
+	require.NoError(t, json.Unmarshal(data, &written))
+	assert.Equal(t, "db", written.Service)
+	assert.Equal(t, []string{"http", "worker"}, written.NotAttempted)
+	assert.Equal(t, config.RedactedValue, written.Config["password"])
+	assert.NoFileExists(t, filepath.Join(root, DefaultBootReportFile))
+}
+
+// TestDrugo_Boot_FailureReport_Precheck 测试 Boot 之前的检查失败时没有失败服务，所有服务都未尝试启动
+func TestDrugo_Boot_FailureReport_Precheck(t *testing.T) {
+	report := &capabilityService{
+		ServiceMock: kerneltest.NewServiceMock("report"),
+		requires:    map[string][]string{"db": {"tx"}},
+	}
+	app := New(WithService(kerneltest.NewServiceMock("cache")), WithService(report))
+	app.logger = newTestLogManager(t)
+
+	require.ErrorIs(t, app.Boot(context.Background()), ErrCapabilityMissing)
+	failure, ok := app.LastBootFailure()
+	require.True(t, ok)
+	assert.Empty(t, failure.Service)
+	assert.Empty(t, failure.Booted)
+	assert.Equal(t, []string{"cache", "report"}, failure.NotAttempted)
+	assert.Empty(t, app.bootFailurePath(), "no report file without WithBootReportFile")
+}
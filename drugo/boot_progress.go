@@ -0,0 +1,156 @@
+package drugo
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// BootProgressThreshold 是自动输出启动进度日志的服务数量，注册的服务超过该数量时 Boot 输出进度日志
+	BootProgressThreshold = 32
+	// DefaultBootProgressEvery 是启动进度日志的默认服务间隔，每完成该数量的服务输出一行
+	DefaultBootProgressEvery = 10
+	// DefaultBootProgressInterval 是启动进度日志的默认时间间隔，同一个服务 Boot 超过该时间时同样输出一行
+	DefaultBootProgressInterval = 2 * time.Second
+)
+
+// WithBootProgress 使 Boot 总是输出启动进度日志（默认只在服务数量超过 BootProgressThreshold 时输出）：
+// 每完成 every 个服务或每经过 interval 输出一行 "framework boot progress"，
+// 包含已完成与总的服务数、已用时间、正在 Boot 的服务以及已完成的服务中最慢的一个。every 或 interval <= 0 时使用默认值
+func WithBootProgress(every int, interval time.Duration) Option {
+	return func(o *options) {
+		o.bootProgress = true
+		o.bootProgressEvery = every
+		o.bootProgressInterval = interval
+	}
+}
+
+// bootProgress 是 Boot 的实时进度，由 progressMu 保护
+type bootProgress struct {
+	done    int       // 已完成（包括降级）的服务数
+	total   int       // 当前已知的服务总数，动态注册的服务在下一轮加入
+	current string    // 正在 Boot 的服务，没有时为空
+	start   time.Time // Boot 开始时间，按 Clock 计时
+}
+
+// BootProgress 返回 Boot 的实时进度：已完成（包括降级）的服务数、当前已知的服务总数，以及正在 Boot 的服务名称。
+// 没有服务正在 Boot 时 current 为空，Boot 成功后 done == total，因服务失败而失败时 current 为失败的服务。
+// 就绪探针可以在启动期间报告它，区分"仍在启动"（done 持续增加）与"卡住"（current 长时间不变）
+func (d *Drugo) BootProgress() (done, total int, current string) {
+	d.progressMu.Lock()
+	defer d.progressMu.Unlock()
+	return d.progress.done, d.progress.total, d.progress.current
+}
+
+// updateBootProgress 在 progressMu 内修改 Boot 进度
+func (d *Drugo) updateBootProgress(fn func(p *bootProgress)) {
+	d.progressMu.Lock()
+	defer d.progressMu.Unlock()
+	fn(&d.progress)
+}
+
+// progressReporter 输出启动进度日志，由 startBootProgress 创建
+type progressReporter struct {
+	d        *Drugo
+	l        *zap.Logger
+	every    int
+	interval time.Duration
+	lastDone int // 最近一次按服务间隔输出时的 done，只在 Boot 所在协程中访问
+
+	mu      sync.Mutex // 串行化日志输出，stop 之后不再输出
+	stopped bool
+	quit    chan struct{}
+	exited  chan struct{}
+}
+
+// startBootProgress 重置 Boot 进度，并在需要输出进度日志时启动按时间间隔输出的协程。
+// 返回的 reporter 为 nil 时不输出进度日志，它的方法都可以在 nil 上调用
+func (d *Drugo) startBootProgress(l *zap.Logger, total int) *progressReporter {
+	d.updateBootProgress(func(p *bootProgress) {
+		*p = bootProgress{total: total, start: d.Clock().Now()}
+	})
+	if !d.bootProgress && total <= BootProgressThreshold {
+		return nil
+	}
+
+	r := &progressReporter{
+		d:        d,
+		l:        l,
+		every:    d.bootProgressEvery,
+		interval: d.bootProgressInterval,
+		quit:     make(chan struct{}),
+		exited:   make(chan struct{}),
+	}
+	if r.every <= 0 {
+		r.every = DefaultBootProgressEvery
+	}
+	if r.interval <= 0 {
+		r.interval = DefaultBootProgressInterval
+	}
+	timer := d.Clock().NewTimer(r.interval)
+	go func() {
+		defer close(r.exited)
+		defer timer.Stop()
+		for {
+			select {
+			case <-timer.C():
+				r.log()
+				timer.Reset(r.interval)
+			case <-r.quit:
+				return
+			}
+		}
+	}()
+	return r
+}
+
+// serviceDone 在服务 Boot 完成后调用，每完成 every 个服务输出一行进度日志
+func (r *progressReporter) serviceDone() {
+	if r == nil {
+		return
+	}
+	done, total, _ := r.d.BootProgress()
+	if done-r.lastDone >= r.every && done < total {
+		r.lastDone = done
+		r.log()
+	}
+}
+
+// log 输出一行进度日志，stop 之后不再输出
+func (r *progressReporter) log() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.stopped {
+		return
+	}
+	r.d.progressMu.Lock()
+	p := r.d.progress
+	r.d.progressMu.Unlock()
+	r.l.Info("framework boot progress",
+		zap.Int("done", p.done),
+		zap.Int("total", p.total),
+		zap.Duration("elapsed", r.d.Clock().Now().Sub(p.start)),
+		zap.String("current", p.current),
+		zap.String("slowest", strings.Join(r.d.Timings().slowestServices(1), "")),
+	)
+}
+
+// stop 停止输出进度日志并等待协程退出。Boot 在输出完成或失败信息之前调用，
+// 返回后不会再有进度日志，避免与失败信息交错。可以重复调用
+func (r *progressReporter) stop() {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	if r.stopped {
+		r.mu.Unlock()
+		return
+	}
+	r.stopped = true
+	close(r.quit)
+	r.mu.Unlock()
+	<-r.exited
+}
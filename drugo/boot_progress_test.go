@@ -0,0 +1,161 @@
+package drugo
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/qq1060656096/drugo/kernel"
+	"github.com/qq1060656096/drugo/kernel/kerneltest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// newProgressApp 创建注册了 n 个快速服务、并在第 slowAt 个位置插入 slow 的应用，框架日志写入返回的 observer
+func newProgressApp(n, slowAt int, slow kernel.Service, opts ...Option) (*Drugo, *observer.ObservedLogs) {
+	for i := range n {
+		if i == slowAt {
+			opts = append(opts, WithService(slow))
+		}
+		opts = append(opts, WithService(kerneltest.NewServiceMock(fmt.Sprintf("tenant-%02d", i))))
+	}
+	app := New(opts...)
+	core, logs := observer.New(zap.InfoLevel)
+	app.fwFallback = zap.New(core)
+	return app, logs
+}
+
+// newGatedService 创建 Boot 阻塞到 release 关闭的服务，started 在 Boot 开始时关闭，Boot 返回 err
+func newGatedService(name string, err error) (s *kerneltest.ServiceMock, started, release chan struct{}) {
+	started, release = make(chan struct{}), make(chan struct{})
+	s = kerneltest.NewServiceMock(name)
+	s.BootFunc = func(context.Context) error {
+		close(started)
+		<-release
+		return err
+	}
+	return s, started, release
+}
+
+// progressLines 返回进度日志的字段
+func progressLines(logs *observer.ObservedLogs) []map[string]any {
+	var lines []map[string]any
+	for _, e := range logs.FilterMessage("framework boot progress").All() {
+		lines = append(lines, e.ContextMap())
+	}
+	return lines
+}
+
+// assertNoProgressAfter 断言日志 msg 存在，且它之后没有进度日志
+func assertNoProgressAfter(t *testing.T, logs *observer.ObservedLogs, msg string) {
+	t.Helper()
+	entries := logs.All()
+	at := -1
+	for i, e := range entries {
+		if e.Message == msg {
+			at = i
+		}
+	}
+	require.NotEqual(t, -1, at, msg)
+	for _, e := range entries[at:] {
+		assert.NotEqual(t, "framework boot progress", e.Message)
+	}
+}
+
+// bootAsync 在后台执行 Boot，返回接收其错误的通道
+func bootAsync(app *Drugo) <-chan error {
+	done := make(chan error, 1)
+	go func() { done <- app.Boot(context.Background()) }()
+	return done
+}
+
+// TestDrugo_BootProgress 测试进度快照随服务推进、慢服务期间按时间间隔输出进度，以及 Boot 完成后停止输出
+func TestDrugo_BootProgress(t *testing.T) {
+	c := kernel.NewFakeClock(time.Time{})
+	slow, started, release := newGatedService("tenant-acme-db", nil)
+	app, logs := newProgressApp(40, 25, slow, WithBootProgress(10, time.Second), WithClock(c))
+	// 服务耗时按时钟计算，tenant-03 的 Boot 推进 10ms 使其成为已完成服务中最慢的一个
+	app.Container().MustGet("tenant-03").(*kerneltest.ServiceMock).BootFunc = func(context.Context) error {
+		c.Advance(10 * time.Millisecond)
+		return nil
+	}
+
+	done, total, current := app.BootProgress()
+	assert.Equal(t, []any{0, 0, ""}, []any{done, total, current})
+
+	result := bootAsync(app)
+	<-started
+	done, total, current = app.BootProgress()
+	assert.Equal(t, []any{25, 41, "tenant-acme-db"}, []any{done, total, current})
+	// 每完成 10 个服务输出一行
+	lines := progressLines(logs)
+	require.Len(t, lines, 2)
+	assert.Equal(t, int64(10), lines[0]["done"])
+	assert.Equal(t, int64(20), lines[1]["done"])
+	assert.Equal(t, int64(41), lines[1]["total"])
+
+	// 慢服务 Boot 期间按时间间隔输出，current 为慢服务
+	c.BlockUntilWaiters(1)
+	c.Advance(time.Second)
+	require.Eventually(t, func() bool { return len(progressLines(logs)) == 3 }, time.Second, time.Millisecond)
+	line := progressLines(logs)[2]
+	assert.Equal(t, int64(25), line["done"])
+	assert.Equal(t, "tenant-acme-db", line["current"])
+	assert.Equal(t, time.Second+10*time.Millisecond, line["elapsed"])
+	assert.Equal(t, "tenant-03=10ms", line["slowest"])
+
+	close(release)
+	require.NoError(t, <-result)
+	done, total, current = app.BootProgress()
+	assert.Equal(t, []any{41, 41, ""}, []any{done, total, current})
+	lines = progressLines(logs)
+	require.Len(t, lines, 5)
+	assert.Equal(t, int64(30), lines[3]["done"])
+	assert.Equal(t, int64(40), lines[4]["done"])
+
+	// Boot 返回后定时器已停止，不再输出进度
+	assert.Zero(t, c.Waiters())
+	c.Advance(time.Minute)
+	assert.Len(t, progressLines(logs), 5)
+	assertNoProgressAfter(t, logs, "framework boot complete")
+}
+
+// TestDrugo_BootProgress_Failure 测试 Boot 失败时先停止进度输出，失败信息之后没有进度日志
+func TestDrugo_BootProgress_Failure(t *testing.T) {
+	c := kernel.NewFakeClock(time.Time{})
+	slow, started, release := newGatedService("tenant-acme-db", assert.AnError)
+	app, logs := newProgressApp(40, 5, slow, WithBootProgress(0, 0), WithClock(c))
+
+	result := bootAsync(app)
+	<-started
+	c.BlockUntilWaiters(1)
+	c.Advance(DefaultBootProgressInterval)
+	require.Eventually(t, func() bool { return len(progressLines(logs)) == 1 }, time.Second, time.Millisecond)
+	assert.Equal(t, "tenant-acme-db", progressLines(logs)[0]["current"])
+
+	close(release)
+	require.Error(t, <-result)
+	done, total, current := app.BootProgress()
+	assert.Equal(t, []any{5, 41, "tenant-acme-db"}, []any{done, total, current})
+
+	assert.Zero(t, c.Waiters())
+	c.Advance(time.Minute)
+	assertNoProgressAfter(t, logs, "service boot failed")
+}
+
+// TestDrugo_BootProgress_Threshold 测试服务数量不超过阈值时不输出进度，但 BootProgress 仍然可用
+func TestDrugo_BootProgress_Threshold(t *testing.T) {
+	app, logs := newProgressApp(BootProgressThreshold, -1, nil)
+	require.NoError(t, app.Boot(context.Background()))
+	assert.Empty(t, progressLines(logs))
+	done, total, _ := app.BootProgress()
+	assert.Equal(t, BootProgressThreshold, done)
+	assert.Equal(t, BootProgressThreshold, total)
+
+	app, logs = newProgressApp(BootProgressThreshold+DefaultBootProgressEvery, -1, nil)
+	require.NoError(t, app.Boot(context.Background()))
+	assert.Len(t, progressLines(logs), (BootProgressThreshold+DefaultBootProgressEvery-1)/DefaultBootProgressEvery)
+}
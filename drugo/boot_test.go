@@ -0,0 +1,126 @@
+package drugo
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/qq1060656096/drugo/kernel"
+	"github.com/qq1060656096/drugo/kernel/kerneltest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// orderRecorder 记录服务 Boot/Close 的顺序
+type orderRecorder struct {
+	mu     sync.Mutex
+	events []string
+}
+
+func (r *orderRecorder) add(event string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, event)
+}
+
+func (r *orderRecorder) list() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string(nil), r.events...)
+}
+
+// pluginService 在 Boot 中通过容器动态注册子服务
+type pluginService struct {
+	name     string
+	recorder *orderRecorder
+	children []kernel.Service
+}
+
+func (p *pluginService) Name() string { return p.name }
+
+func (p *pluginService) Boot(ctx context.Context) error {
+	p.recorder.add("boot:" + p.name)
+	k := kernel.MustFromContext(ctx)
+	for _, child := range p.children {
+		k.Container().Bind(child.Name(), child)
+	}
+	return nil
+}
+
+func (p *pluginService) Close(ctx context.Context) error {
+	p.recorder.add("close:" + p.name)
+	return nil
+}
+
+// TestDrugo_Boot_DynamicRegistration 测试 Boot 期间动态注册的服务会被初始化并逆序关闭
+func TestDrugo_Boot_DynamicRegistration(t *testing.T) {
+	rec := &orderRecorder{}
+	grandchild := &pluginService{name: "grandchild", recorder: rec}
+	child := &pluginService{name: "child", recorder: rec, children: []kernel.Service{grandchild}}
+	host := &pluginService{name: "host", recorder: rec, children: []kernel.Service{child}}
+	tail := &pluginService{name: "tail", recorder: rec}
+
+	app := New(WithService(host), WithService(tail))
+	app.logger = newTestLogManager(t)
+
+	require.NoError(t, app.Boot(context.Background()))
+	require.NoError(t, app.Shutdown(context.Background()))
+
+	assert.Equal(t, []string{
+		"boot:host", "boot:tail", "boot:child", "boot:grandchild",
+		"close:grandchild", "close:child", "close:tail", "close:host",
+	}, rec.list())
+	assert.Equal(t, ServiceStateClosed, app.Status()["grandchild"].State)
+}
+
+// loopService 每次 Boot 都注册一个新的服务，模拟循环注册
+type loopService struct {
+	id int
+}
+
+func (s *loopService) Name() string { return fmt.Sprintf("loop-%d", s.id) }
+
+func (s *loopService) Boot(ctx context.Context) error {
+	next := &loopService{id: s.id + 1}
+	kernel.MustFromContext(ctx).Container().Bind(next.Name(), next)
+	return nil
+}
+
+func (s *loopService) Close(ctx context.Context) error { return nil }
+
+// TestDrugo_Boot_PassLimit 测试循环注册超过最大轮数时返回错误
+func TestDrugo_Boot_PassLimit(t *testing.T) {
+	app := New(WithService(&loopService{}))
+	app.logger = newTestLogManager(t)
+
+	err := app.Boot(context.Background())
+	assert.ErrorIs(t, err, ErrBootPassLimit)
+}
+
+// TestDrugo_Boot_ConcurrentBind 测试 Boot 期间其他 goroutine 并发注册服务（配合 -race 运行）
+func TestDrugo_Boot_ConcurrentBind(t *testing.T) {
+	app := New(WithService(kerneltest.NewServiceMock("base")))
+	app.logger = newTestLogManager(t)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 10; j++ {
+				name := fmt.Sprintf("svc-%d-%d", i, j)
+				app.Container().Bind(name, kerneltest.NewServiceMock(name))
+				_ = app.Status()
+			}
+		}(i)
+	}
+
+	require.NoError(t, app.Boot(context.Background()))
+	wg.Wait()
+
+	// 并发注册的服务要么在本次 Boot 中被初始化，要么在 Boot 结束后加入并保持 pending
+	for name, status := range app.Status() {
+		assert.Contains(t, []ServiceState{ServiceStateBooted, ServiceStatePending}, status.State, name)
+	}
+}
@@ -0,0 +1,51 @@
+package drugo
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/qq1060656096/drugo/kernel"
+)
+
+// CheckCapabilities 检查所有服务通过 kernel.CapabilityRequirer 声明的能力依赖，
+// 依赖的服务未注册、没有实现 kernel.Capabilities 或缺少所需能力时，返回包装了 ErrCapabilityMissing 的错误，
+// 错误信息汇总所有缺失项，并列出声明依赖的服务与被依赖的服务。
+// Boot 在初始化任何服务之前调用它；也可以在部署前的检查中直接调用，不需要 Boot。
+func (d *Drugo) CheckCapabilities() error {
+	var missing []string
+	for _, service := range d.Container().Services() {
+		requirer, ok := service.(kernel.CapabilityRequirer)
+		if !ok {
+			continue
+		}
+		required := requirer.RequiredCapabilities()
+		for _, target := range slices.Sorted(maps.Keys(required)) {
+			dep, err := d.Container().Get(target)
+			if err != nil {
+				missing = append(missing, fmt.Sprintf("%q requires service %q which is not registered", service.Name(), target))
+				continue
+			}
+			caps := capabilitiesOf(dep)
+			for _, capability := range required[target] {
+				if _, ok := caps[capability]; !ok {
+					missing = append(missing, fmt.Sprintf("%q requires capability %q of %q", service.Name(), capability, target))
+				}
+			}
+		}
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%w: %s", ErrCapabilityMissing, strings.Join(missing, "; "))
+}
+
+// capabilitiesOf 返回服务声明的能力副本，没有实现 kernel.Capabilities 时返回 nil
+func capabilitiesOf(service kernel.Service) map[string]string {
+	c, ok := service.(kernel.Capabilities)
+	if !ok {
+		return nil
+	}
+	return maps.Clone(c.Capabilities())
+}
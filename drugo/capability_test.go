@@ -0,0 +1,76 @@
+package drugo
+
+import (
+	"context"
+	"testing"
+
+	"github.com/qq1060656096/drugo/kernel"
+	"github.com/qq1060656096/drugo/kernel/kerneltest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// capabilityService 是声明能力与能力依赖的测试服务
+type capabilityService struct {
+	*kerneltest.ServiceMock
+	caps     map[string]string
+	requires map[string][]string
+}
+
+func (s *capabilityService) Capabilities() map[string]string { return s.caps }
+
+func (s *capabilityService) RequiredCapabilities() map[string][]string { return s.requires }
+
+// TestDrugo_Boot_CapabilityMissing 测试 Boot 之前的能力依赖检查：汇总所有缺失项，错误信息包含双方的服务名称，且不初始化任何服务
+func TestDrugo_Boot_CapabilityMissing(t *testing.T) {
+	db := &capabilityService{ServiceMock: kerneltest.NewServiceMock("db"), caps: map[string]string{"tx": ""}}
+	cache := kerneltest.NewServiceMock("cache")
+	report := &capabilityService{
+		ServiceMock: kerneltest.NewServiceMock("report"),
+		requires: map[string][]string{
+			"db":    {"tx", "read-replicas"},
+			"cache": {"ttl"},
+			"queue": {"fifo"},
+		},
+	}
+	app := New(WithService(db), WithService(cache), WithService(report))
+	app.logger = newTestLogManager(t)
+
+	err := app.Boot(context.Background())
+	require.ErrorIs(t, err, ErrCapabilityMissing)
+	assert.Contains(t, err.Error(), `"report" requires capability "ttl" of "cache"`)
+	assert.Contains(t, err.Error(), `"report" requires capability "read-replicas" of "db"`)
+	assert.Contains(t, err.Error(), `"report" requires service "queue" which is not registered`)
+	assert.NotContains(t, err.Error(), `"tx"`)
+	assert.False(t, db.Booted())
+	assert.Equal(t, ServiceStatePending, app.Status()["db"].State)
+}
+
+// TestDrugo_CheckCapabilities 测试能力依赖满足时检查通过，并且 Boot 之后的状态与启动报告包含服务声明的能力
+func TestDrugo_CheckCapabilities(t *testing.T) {
+	db := &capabilityService{ServiceMock: kerneltest.NewServiceMock("db"), caps: map[string]string{"read-replicas": "2"}}
+	report := &capabilityService{
+		ServiceMock: kerneltest.NewServiceMock("report"),
+		requires:    map[string][]string{"db": {"read-replicas"}},
+	}
+	app := New(WithService(db), WithService(report))
+	app.logger = newTestLogManager(t)
+
+	require.NoError(t, app.CheckCapabilities())
+	require.NoError(t, app.Boot(context.Background()))
+	assert.True(t, kernel.HasCapability(app, "db", "read-replicas"))
+
+	status := app.Status()
+	assert.Equal(t, map[string]string{"read-replicas": "2"}, status["db"].Capabilities)
+	assert.Nil(t, status["report"].Capabilities)
+
+	services := app.BootReport().Services
+	require.Len(t, services, 2)
+	assert.Equal(t, map[string]string{"read-replicas": "2"}, services[0].Capabilities)
+	assert.Nil(t, services[1].Capabilities)
+
+	// 报告中的能力是副本
+	services[0].Capabilities["read-replicas"] = "0"
+	assert.Equal(t, "2", app.BootReport().Services[0].Capabilities["read-replicas"])
+	assert.Equal(t, "2", db.caps["read-replicas"])
+}
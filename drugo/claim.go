@@ -0,0 +1,59 @@
+package drugo
+
+import (
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/qq1060656096/drugo/kernel"
+)
+
+// checkClaims 收集所有未降级服务的资源声明（见 kernel.ResourceClaimer），
+// 两个服务声明同一项资源时返回包装了 ErrClaimConflict 的错误。
+// 启用 WithProbeClaims 时，还会尝试短暂监听 tcp-port 声明的端口，以发现与外部进程的冲突。
+func (d *Drugo) checkClaims() error {
+	owners := make(map[kernel.Claim]string)
+	var claims []kernel.Claim
+	for _, service := range d.Container().Services() {
+		claimer, ok := service.(kernel.ResourceClaimer)
+		if !ok || d.isDegraded(service.Name()) {
+			continue
+		}
+		for _, claim := range claimer.Claims() {
+			owner, ok := owners[claim]
+			if ok && owner != service.Name() {
+				return fmt.Errorf("%w: %s claimed by both %q and %q", ErrClaimConflict, claim, owner, service.Name())
+			}
+			if !ok {
+				owners[claim] = service.Name()
+				claims = append(claims, claim)
+			}
+		}
+	}
+
+	if !d.probeClaims {
+		return nil
+	}
+	for _, claim := range claims {
+		if claim.Kind != kernel.ClaimTCPPort {
+			continue
+		}
+		if err := probeTCPPort(claim.Value); err != nil {
+			return fmt.Errorf("%w: %s claimed by %q is unavailable: %w", ErrClaimConflict, claim, owners[claim], err)
+		}
+	}
+	return nil
+}
+
+// probeTCPPort 尝试监听 tcp-port 声明的地址并立即关闭；value 只有端口号时监听所有地址
+func probeTCPPort(value string) error {
+	addr := value
+	if !strings.Contains(addr, ":") {
+		addr = ":" + addr
+	}
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	return ln.Close()
+}
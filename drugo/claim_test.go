@@ -0,0 +1,99 @@
+package drugo
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+
+	"github.com/qq1060656096/drugo/kernel"
+	"github.com/qq1060656096/drugo/kernel/kerneltest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// claimService 是一个声明资源的 Runner 服务
+type claimService struct {
+	*kerneltest.RunnerMock
+	claims []kernel.Claim
+}
+
+func (s *claimService) Claims() []kernel.Claim {
+	return s.claims
+}
+
+func newClaimService(name string, claims ...kernel.Claim) *claimService {
+	return &claimService{
+		RunnerMock: kerneltest.NewRunnerMock(name),
+		claims:     claims,
+	}
+}
+
+// TestDrugo_Run_ClaimConflict 测试两个服务声明同一端口时 Run 直接失败且不启动任何 Runner
+func TestDrugo_Run_ClaimConflict(t *testing.T) {
+	port := kernel.Claim{Kind: kernel.ClaimTCPPort, Value: "8080"}
+	gin := newClaimService("gin", port)
+	pprof := newClaimService("pprof", kernel.Claim{Kind: kernel.ClaimFileLock, Value: "/tmp/pprof.lock"}, port)
+	app := New(WithService(gin), WithService(pprof))
+	app.logger = newTestLogManager(t)
+	require.NoError(t, app.Boot(context.Background()))
+
+	err := app.Run(context.Background())
+	require.ErrorIs(t, err, ErrClaimConflict)
+	assert.Contains(t, err.Error(), `tcp-port:8080 claimed by both "gin" and "pprof"`)
+	assert.False(t, gin.Ran())
+	assert.False(t, pprof.Ran())
+}
+
+// TestDrugo_Run_NoClaimConflict 测试声明互不冲突时正常运行
+func TestDrugo_Run_NoClaimConflict(t *testing.T) {
+	gin := newClaimService("gin",
+		kernel.Claim{Kind: kernel.ClaimTCPPort, Value: "8080"},
+		kernel.Claim{Kind: kernel.ClaimTCPPort, Value: "8080"}, // 同一服务重复声明不算冲突
+	)
+	pprof := newClaimService("pprof", kernel.Claim{Kind: kernel.ClaimTCPPort, Value: "6060"})
+	rpc := newClaimService("rpc", kernel.Claim{Kind: kernel.ClaimUnixSocket, Value: "8080"})
+	app := New(WithService(gin), WithService(pprof), WithService(rpc))
+	app.logger = newTestLogManager(t)
+	require.NoError(t, app.Boot(context.Background()))
+
+	require.NoError(t, app.Run(context.Background()))
+	assert.True(t, gin.Ran())
+	assert.True(t, pprof.Ran())
+	assert.True(t, rpc.Ran())
+}
+
+// TestDrugo_Run_ClaimDegradedService 测试降级的可选服务不参与冲突检查
+func TestDrugo_Run_ClaimDegradedService(t *testing.T) {
+	port := kernel.Claim{Kind: kernel.ClaimTCPPort, Value: "8080"}
+	gin := newClaimService("gin", port)
+	debug := newClaimService("debug", port)
+	debug.BootFunc = kerneltest.ReturnAfter(0, errors.New("boot failed"))
+	app := New(WithService(gin), WithOptionalService(debug))
+	app.logger = newTestLogManager(t)
+	require.NoError(t, app.Boot(context.Background()))
+
+	require.NoError(t, app.Run(context.Background()))
+	assert.True(t, gin.Ran())
+}
+
+// TestDrugo_checkClaims_Probe 测试 WithProbeClaims 能发现被外部监听占用的端口
+func TestDrugo_checkClaims_Probe(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer ln.Close()
+	claim := kernel.Claim{Kind: kernel.ClaimTCPPort, Value: ln.Addr().String()}
+
+	// 默认不探测端口
+	app := New(WithService(newClaimService("gin", claim)))
+	assert.NoError(t, app.checkClaims())
+
+	app = New(WithService(newClaimService("gin", claim)), WithProbeClaims())
+	err = app.checkClaims()
+	require.ErrorIs(t, err, ErrClaimConflict)
+	assert.Contains(t, err.Error(), `claimed by "gin" is unavailable`)
+
+	// 端口释放后探测通过
+	require.NoError(t, ln.Close())
+	assert.NoError(t, app.checkClaims())
+}
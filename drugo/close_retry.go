@@ -0,0 +1,149 @@
+package drugo
+
+import (
+	"context"
+	"time"
+
+	"github.com/qq1060656096/drugo/kernel"
+	"go.uber.org/zap"
+)
+
+const (
+	// closeRetryBackoff 是第一次重试 Close 前的等待时间，之后每轮翻倍，最多 closeRetryMaxBackoff
+	closeRetryBackoff    = 50 * time.Millisecond
+	closeRetryMaxBackoff = time.Second
+	// closeRetryMaxAttempts 是 ctx 没有截止时间时 Close 的最大调用次数，避免无限重试
+	closeRetryMaxAttempts = 5
+)
+
+// CloseOutcome 是服务在 Shutdown 中 Close 的最终结果。
+type CloseOutcome string
+
+const (
+	// CloseOutcomeClosed 第一次 Close 即成功。
+	CloseOutcomeClosed CloseOutcome = "closed"
+	// CloseOutcomeRetried Close 返回 kernel.ErrCloseRetryable 后在重试中成功。
+	CloseOutcomeRetried CloseOutcome = "closed_on_retry"
+	// CloseOutcomeExhausted Close 持续返回 kernel.ErrCloseRetryable，直到停机超时或重试次数耗尽。
+	CloseOutcomeExhausted CloseOutcome = "retry_exhausted"
+	// CloseOutcomeFailed Close 返回不可重试的错误。
+	CloseOutcomeFailed CloseOutcome = "failed"
+)
+
+// ShutdownSummary 是最近一次 Shutdown 中各服务 Close 的结果，见 Drugo.LastShutdown。
+type ShutdownSummary struct {
+	Services []ServiceShutdown // 按关闭顺序排列，降级的可选服务不参与关闭
+}
+
+// ServiceShutdown 是单个服务 Close 的结果。
+type ServiceShutdown struct {
+	Name     string       // 服务名称
+	Outcome  CloseOutcome // 最终结果
+	Attempts int          // Close 的调用次数
+	Err      error        // 最后一次 Close 的错误，成功时为 nil
+}
+
+// serviceClose 记录 Shutdown 中单个服务的关闭过程
+type serviceClose struct {
+	ServiceShutdown
+	service kernel.Service
+}
+
+// LastShutdown 返回最近一次 Shutdown 的关闭结果副本，尚未执行 Shutdown 时返回 false。
+func (d *Drugo) LastShutdown() (*ShutdownSummary, bool) {
+	d.reportMu.RLock()
+	defer d.reportMu.RUnlock()
+	if d.shutdown == nil {
+		return nil, false
+	}
+	return &ShutdownSummary{Services: append([]ServiceShutdown(nil), d.shutdown.Services...)}, true
+}
+
+// closeService 调用一次服务的 Close，记录调用次数与错误
+func (d *Drugo) closeService(ctx context.Context, c *serviceClose) error {
+	c.Attempts++
+	c.Err = c.service.Close(d.withServiceLogger(ctx, c.service))
+	return c.Err
+}
+
+// retryClose 按指数退避重试 Close 返回 kernel.ErrCloseRetryable 的服务，每轮按逆序依次重试仍未关闭的服务。
+// 等待与重试都受 ctx 约束：ctx 结束后不再调用 Close，剩余服务保留最后一次的错误；
+// ctx 没有截止时间时最多调用 closeRetryMaxAttempts 次
+func (d *Drugo) retryClose(ctx context.Context, l *zap.Logger, pending []*serviceClose) {
+	_, hasDeadline := ctx.Deadline()
+	delay := closeRetryBackoff
+	for attempt := 2; len(pending) > 0; attempt++ {
+		if !hasDeadline && attempt > closeRetryMaxAttempts {
+			return
+		}
+		timer := d.Clock().NewTimer(delay)
+		select {
+		case <-timer.C():
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		}
+		delay = min(delay*2, closeRetryMaxBackoff)
+
+		next := pending[:0]
+		for _, c := range pending {
+			if ctx.Err() != nil {
+				return
+			}
+			l.Info("service shutdown retry", zap.String("service", c.Name), zap.Int("attempt", attempt))
+			if err := d.closeService(ctx, c); err != nil && kernel.IsCloseRetryable(err) {
+				l.Warn("service shutdown retry failed",
+					zap.String("service", c.Name),
+					zap.Int("attempt", attempt),
+					zap.Error(err),
+				)
+				next = append(next, c)
+			}
+		}
+		pending = next
+	}
+}
+
+// finishClose 确定服务 Close 的最终结果并记录到状态中，失败时记录日志并交给错误处理函数，
+// 返回需要合并到 Shutdown 返回值中的错误
+func (d *Drugo) finishClose(l *zap.Logger, c *serviceClose) error {
+	switch {
+	case c.Err == nil && c.Attempts > 1:
+		c.Outcome = CloseOutcomeRetried
+	case c.Err == nil:
+		c.Outcome = CloseOutcomeClosed
+	case kernel.IsCloseRetryable(c.Err):
+		c.Outcome = CloseOutcomeExhausted
+	default:
+		c.Outcome = CloseOutcomeFailed
+	}
+	d.setCloseStatus(c.ServiceShutdown)
+
+	fields := []zap.Field{zap.String("service", c.Name)}
+	if c.Attempts > 1 {
+		fields = append(fields, zap.Int("attempts", c.Attempts), zap.String("outcome", string(c.Outcome)))
+	}
+	if c.Err == nil {
+		if c.Attempts > 1 {
+			l.Info("service closed on retry", fields...)
+		}
+		return nil
+	}
+	l.Error("service shutdown failed", append(fields, zap.Error(c.Err))...)
+	wrapped := kernel.WrapServiceCloseFailed(c.Name, c.Err)
+	if d.handleError(PhaseShutdown, c.Name, wrapped) != DecisionContinue {
+		return wrapped
+	}
+	return nil
+}
+
+// recordShutdown 保存 Shutdown 的关闭结果，见 LastShutdown
+func (d *Drugo) recordShutdown(closes []*serviceClose) {
+	summary := &ShutdownSummary{Services: make([]ServiceShutdown, 0, len(closes))}
+	for _, c := range closes {
+		summary.Services = append(summary.Services, c.ServiceShutdown)
+	}
+	d.reportMu.Lock()
+	d.shutdown = summary
+	d.reportMu.Unlock()
+}
@@ -0,0 +1,140 @@
+package drugo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/qq1060656096/drugo/kernel"
+	"github.com/qq1060656096/drugo/kernel/kerneltest"
+	"github.com/qq1060656096/drugo/pkg/clock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newFlappingService 创建前 failures 次 Close 返回可重试错误的服务，failures 为负数时一直失败
+func newFlappingService(name string, failures int) *kerneltest.ServiceMock {
+	s := kerneltest.NewServiceMock(name)
+	s.CloseFunc = func(context.Context) error {
+		if failures < 0 || s.CloseCount() <= failures {
+			return fmt.Errorf("%w: connections still open", kernel.ErrCloseRetryable)
+		}
+		return nil
+	}
+	return s
+}
+
+// shutdownAsync 在后台执行 Shutdown，返回接收其错误的通道
+func shutdownAsync(app *Drugo, ctx context.Context) <-chan error {
+	done := make(chan error, 1)
+	go func() { done <- app.Shutdown(ctx) }()
+	return done
+}
+
+// TestDrugo_Shutdown_CloseRetry 测试可重试的 Close 在其余服务关闭后退避重试直到成功
+func TestDrugo_Shutdown_CloseRetry(t *testing.T) {
+	c := kernel.NewFakeClock(time.Time{})
+	flapping := newFlappingService("db", 2)
+	cache := kerneltest.NewServiceMock("cache")
+	failing := newCloseFailingService("queue", assert.AnError)
+	app := New(WithServices(flapping, cache, failing), WithClock(c))
+	app.logger = newTestLogManager(t)
+
+	done := shutdownAsync(app, context.Background())
+	c.BlockUntilWaiters(1)
+	// 第一轮结束时其余服务都已关闭，不可重试的错误不会重试
+	assert.Equal(t, 1, flapping.CloseCount())
+	assert.Equal(t, 1, cache.CloseCount())
+	assert.Equal(t, 1, failing.CloseCount())
+	c.Advance(closeRetryBackoff)
+	c.BlockUntilWaiters(1)
+	c.Advance(2 * closeRetryBackoff)
+
+	err := <-done
+	require.Error(t, err)
+	assert.True(t, kernel.IsServiceCloseFailed(err))
+	assert.ErrorIs(t, err, assert.AnError)
+	assert.False(t, kernel.IsCloseRetryable(err))
+	assert.Equal(t, 3, flapping.CloseCount())
+	assert.Equal(t, 1, failing.CloseCount())
+
+	summary, ok := app.LastShutdown()
+	require.True(t, ok)
+	require.Len(t, summary.Services, 3)
+	assert.Equal(t, ServiceShutdown{Name: "queue", Outcome: CloseOutcomeFailed, Attempts: 1, Err: assert.AnError}, summary.Services[0])
+	assert.Equal(t, ServiceShutdown{Name: "cache", Outcome: CloseOutcomeClosed, Attempts: 1}, summary.Services[1])
+	assert.Equal(t, ServiceShutdown{Name: "db", Outcome: CloseOutcomeRetried, Attempts: 3}, summary.Services[2])
+
+	status := app.Status()
+	assert.Equal(t, ServiceStateClosed, status["db"].State)
+	assert.Equal(t, CloseOutcomeRetried, status["db"].CloseOutcome)
+	assert.Equal(t, 3, status["db"].CloseAttempts)
+	assert.NoError(t, status["db"].Err)
+	assert.Equal(t, CloseOutcomeFailed, status["queue"].CloseOutcome)
+	assert.NotEqual(t, ServiceStateClosed, status["queue"].State)
+}
+
+// TestDrugo_Shutdown_CloseRetryExhausted 测试一直可重试失败的 Close 在停机超时后不再重试
+func TestDrugo_Shutdown_CloseRetryExhausted(t *testing.T) {
+	c := kernel.NewFakeClock(time.Time{})
+	flapping := newFlappingService("db", -1)
+	app := New(WithService(flapping), WithClock(c))
+	app.logger = newTestLogManager(t)
+
+	ctx, cancel := clock.WithTimeout(context.Background(), c, time.Second)
+	defer cancel()
+	done := shutdownAsync(app, ctx)
+	// 依次在 50ms、150ms、350ms、750ms 重试，下一次重试（1550ms）超过停机超时
+	for _, d := range []time.Duration{50, 100, 200, 400} {
+		c.BlockUntilWaiters(2) // 停机超时与退避的定时器
+		c.Advance(d * time.Millisecond)
+	}
+	c.BlockUntilWaiters(2)
+	c.Advance(250 * time.Millisecond)
+
+	err := <-done
+	require.Error(t, err)
+	assert.True(t, kernel.IsServiceCloseFailed(err))
+	assert.True(t, kernel.IsCloseRetryable(err))
+	assert.Equal(t, 5, flapping.CloseCount())
+
+	summary, ok := app.LastShutdown()
+	require.True(t, ok)
+	require.Len(t, summary.Services, 1)
+	assert.Equal(t, CloseOutcomeExhausted, summary.Services[0].Outcome)
+	assert.Equal(t, 5, summary.Services[0].Attempts)
+
+	st := app.Status()["db"]
+	assert.Equal(t, CloseOutcomeExhausted, st.CloseOutcome)
+	assert.Equal(t, 5, st.CloseAttempts)
+	assert.True(t, kernel.IsCloseRetryable(st.Err))
+}
+
+// TestDrugo_Shutdown_CloseRetryNoDeadline 测试 ctx 没有截止时间时重试次数有上限，重试中返回不可重试的错误时停止重试
+func TestDrugo_Shutdown_CloseRetryNoDeadline(t *testing.T) {
+	app := New(WithService(newFlappingService("db", -1)))
+	app.logger = newTestLogManager(t)
+	_, ok := app.LastShutdown()
+	assert.False(t, ok)
+
+	require.Error(t, app.Shutdown(context.Background()))
+	summary, _ := app.LastShutdown()
+	assert.Equal(t, CloseOutcomeExhausted, summary.Services[0].Outcome)
+	assert.Equal(t, closeRetryMaxAttempts, summary.Services[0].Attempts)
+
+	errGone := errors.New("connection reset")
+	s := kerneltest.NewServiceMock("db")
+	s.CloseFunc = func(context.Context) error {
+		if s.CloseCount() == 1 {
+			return kernel.ErrCloseRetryable
+		}
+		return errGone
+	}
+	app = New(WithService(s))
+	app.logger = newTestLogManager(t)
+	require.ErrorIs(t, app.Shutdown(context.Background()), errGone)
+	summary, _ = app.LastShutdown()
+	assert.Equal(t, ServiceShutdown{Name: "db", Outcome: CloseOutcomeFailed, Attempts: 2, Err: errGone}, summary.Services[0])
+}
@@ -0,0 +1,220 @@
+package drugo
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"text/tabwriter"
+
+	"github.com/gin-gonic/gin"
+	"github.com/qq1060656096/drugo/config"
+	"github.com/qq1060656096/drugo/kernel"
+	"github.com/qq1060656096/drugo/pkg/clock"
+	"github.com/qq1060656096/drugo/pkg/router"
+	"go.uber.org/zap"
+)
+
+// DefaultCommand 是未指定子命令时执行的命令
+const DefaultCommand = "serve"
+
+// CommandFunc 是子命令的执行函数。
+// args 是解析完命令自身 flag 后剩余的参数。
+type CommandFunc func(ctx context.Context, k kernel.Kernel, args []string) error
+
+// command 描述一个已注册的子命令
+type command struct {
+	name  string
+	short string
+	flags *flag.FlagSet
+	run   CommandFunc
+	// boot 为 false 时不执行服务的 Boot/Shutdown，用于只读取元数据的内置命令
+	boot bool
+}
+
+// Command 注册一个子命令，并返回该命令的 FlagSet 以便定义命令参数。
+// 子命令执行前会 Boot 所有服务，执行完成后 Shutdown；
+// 执行期间收到 SIGINT/SIGTERM 会取消 ctx，使长时间运行的命令有机会退出。
+// 同名命令会覆盖之前的注册（包括内置命令）。
+func (d *Drugo) Command(name, short string, run CommandFunc) *flag.FlagSet {
+	return d.addCommand(name, short, run, true)
+}
+
+// Execute 根据命令行参数分发子命令，args 通常为 os.Args。
+//
+// 分发规则：
+//   - 没有参数或参数为 "serve"：调用 Serve
+//   - "help"、"-h"、"--help"：打印命令列表
+//   - 其他已注册命令：Boot → 执行命令 → Shutdown
+//   - 未注册命令：打印命令列表并返回 ErrUnknownCommand
+//
+// 不经过 Serve 的命令返回前按返回的错误记录生命周期结果（OutcomeClean 或 OutcomeError），见 ExitCode。
+func (d *Drugo) Execute(ctx context.Context, args []string) (err error) {
+	defer func() { d.setOutcomeIfPending(err) }()
+	d.registerBuiltinCommands()
+
+	var rest []string
+	if len(args) > 1 {
+		rest = args[1:]
+	}
+	name := DefaultCommand
+	if len(rest) > 0 {
+		name, rest = rest[0], rest[1:]
+	}
+
+	switch name {
+	case "help", "-h", "--help":
+		d.printUsage(args)
+		return nil
+	}
+
+	cmd, err := d.commands.Get(name)
+	if err != nil {
+		d.printUsage(args)
+		return fmt.Errorf("%w: %q", ErrUnknownCommand, name)
+	}
+
+	if err := cmd.flags.Parse(rest); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+	return d.runCommand(ctx, cmd, cmd.flags.Args())
+}
+
+// runCommand 执行子命令，需要 Boot 的命令会在执行前后负责服务的启动与关闭。
+// 命令执行期间与 Serve 一样处理停机信号（见 WithShutdownSignals、WithDisableSignals），收到信号时取消命令的上下文。
+func (d *Drugo) runCommand(ctx context.Context, cmd *command, args []string) error {
+	if !cmd.boot {
+		return cmd.run(kernel.WithContext(ctx, d), d, args)
+	}
+
+	l := d.frameworkLogger()
+	l.Info("command starting", zap.String("command", cmd.name))
+
+	if err := d.Boot(ctx); err != nil {
+		return err
+	}
+
+	runCtx, stop := d.shutdownContext(ctx)
+	runErr := cmd.run(kernel.WithContext(runCtx, d), d, args)
+	stop()
+	if runErr != nil {
+		l.Error("command failed", zap.String("command", cmd.name), zap.Error(runErr))
+	}
+
+	timeoutCtx, cancel := clock.WithTimeout(ctx, d.Clock(), d.shutdownTimeoutOrDefault())
+	defer cancel()
+	if err := d.Shutdown(timeoutCtx); err != nil && runErr == nil {
+		return err
+	}
+
+	l.Info("command complete", zap.String("command", cmd.name))
+	return runErr
+}
+
+// addCommand 注册子命令并返回其 FlagSet。
+func (d *Drugo) addCommand(name, short string, run CommandFunc, boot bool) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(d.output())
+	d.commands.Bind(name, &command{name: name, short: short, flags: fs, run: run, boot: boot})
+	return fs
+}
+
+// registerBuiltinCommands 注册内置命令，已被用户覆盖的命令保持不变。
+func (d *Drugo) registerBuiltinCommands() {
+	builtins := []struct {
+		name  string
+		short string
+		run   CommandFunc
+		boot  bool
+	}{
+		{DefaultCommand, "启动所有服务并等待退出信号（默认命令）", func(ctx context.Context, k kernel.Kernel, args []string) error {
+			return d.Serve(ctx)
+		}, false},
+		{"routes", "打印路由表", d.routesCommand, false},
+		{"config", "打印脱敏后的配置", d.configCommand, false},
+		{"providers", "列出已注册的服务 provider", d.providersCommand, false},
+		{"status", "以 JSON 格式打印应用状态快照", d.statusCommand, false},
+	}
+	for _, b := range builtins {
+		if _, err := d.commands.Get(b.name); err != nil {
+			d.addCommand(b.name, b.short, b.run, b.boot)
+		}
+	}
+}
+
+// printUsage 打印所有可用命令。
+func (d *Drugo) printUsage(args []string) {
+	prog := Name
+	if len(args) > 0 {
+		prog = filepath.Base(args[0])
+	}
+
+	commands := d.commands.Values()
+	sort.Slice(commands, func(i, j int) bool { return commands[i].name < commands[j].name })
+
+	out := d.output()
+	fmt.Fprintf(out, "Usage: %s [command] [flags] [args]\n\nCommands:\n", prog)
+	w := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	for _, cmd := range commands {
+		fmt.Fprintf(w, "  %s\t%s\n", cmd.name, cmd.short)
+	}
+	w.Flush()
+}
+
+// routesCommand 打印通过 router.Default() 注册的所有路由。
+func (d *Drugo) routesCommand(ctx context.Context, k kernel.Kernel, args []string) error {
+	engine := gin.New()
+	if err := router.Default().SetupE(engine); err != nil {
+		return err
+	}
+
+	routes := engine.Routes()
+	sort.Slice(routes, func(i, j int) bool {
+		if routes[i].Path != routes[j].Path {
+			return routes[i].Path < routes[j].Path
+		}
+		return routes[i].Method < routes[j].Method
+	})
+
+	w := tabwriter.NewWriter(d.output(), 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "METHOD\tPATH\tHANDLER")
+	for _, r := range routes {
+		fmt.Fprintf(w, "%s\t%s\t%s\n", r.Method, r.Path, r.Handler)
+	}
+	return w.Flush()
+}
+
+// configCommand 以 JSON 格式打印脱敏后的全部配置。
+func (d *Drugo) configCommand(ctx context.Context, k kernel.Kernel, args []string) error {
+	if d.Config() == nil {
+		return fmt.Errorf("drugo: config manager is not initialized")
+	}
+	settings := config.Redact(d.Config().Root().AllSettings())
+
+	enc := json.NewEncoder(d.output())
+	enc.SetIndent("", "  ")
+	return enc.Encode(settings)
+}
+
+// statusCommand 以 JSON 格式打印应用状态快照，见 Snapshot。
+func (d *Drugo) statusCommand(ctx context.Context, k kernel.Kernel, args []string) error {
+	enc := json.NewEncoder(d.output())
+	enc.SetIndent("", "  ")
+	return enc.Encode(d.Snapshot())
+}
+
+// output 返回命令的输出目标，默认为标准输出。
+func (d *Drugo) output() io.Writer {
+	if d.stdout == nil {
+		return os.Stdout
+	}
+	return d.stdout
+}
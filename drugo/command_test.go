@@ -0,0 +1,150 @@
+package drugo
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/qq1060656096/drugo/config"
+	"github.com/qq1060656096/drugo/kernel"
+	"github.com/qq1060656096/drugo/kernel/kerneltest"
+	"github.com/qq1060656096/drugo/pkg/router"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestCommandApp 创建一个带测试日志和输出缓冲区的应用
+func newTestCommandApp(t *testing.T, opts ...Option) (*Drugo, *bytes.Buffer) {
+	t.Helper()
+	out := &bytes.Buffer{}
+	app := New(append(opts, WithOutput(out))...)
+	app.logger = newTestLogManager(t)
+	return app, out
+}
+
+// TestDrugo_Execute_Command 测试自定义命令的 Boot → Run → Shutdown 流程
+func TestDrugo_Execute_Command(t *testing.T) {
+	svc := kerneltest.NewServiceMock("db")
+	app, _ := newTestCommandApp(t, WithService(svc))
+
+	var gotArgs []string
+	var bootedBeforeRun bool
+	fs := app.Command("migrate", "执行数据库迁移", func(ctx context.Context, k kernel.Kernel, args []string) error {
+		bootedBeforeRun = svc.Booted() && !svc.Closed()
+		assert.Same(t, app, kernel.MustFromContext(ctx))
+		gotArgs = args
+		return nil
+	})
+	steps := fs.Int("steps", 0, "迁移步数")
+
+	err := app.Execute(context.Background(), []string{"app", "migrate", "-steps", "3", "up"})
+	require.NoError(t, err)
+	assert.True(t, bootedBeforeRun)
+	assert.True(t, svc.Closed())
+	assert.Equal(t, 3, *steps)
+	assert.Equal(t, []string{"up"}, gotArgs)
+}
+
+// TestDrugo_Execute_CommandError 测试命令失败时仍然关闭服务并返回命令错误
+func TestDrugo_Execute_CommandError(t *testing.T) {
+	svc := kerneltest.NewServiceMock("db")
+	app, _ := newTestCommandApp(t, WithService(svc))
+	app.Command("migrate", "执行数据库迁移", func(ctx context.Context, k kernel.Kernel, args []string) error {
+		return assert.AnError
+	})
+
+	err := app.Execute(context.Background(), []string{"app", "migrate"})
+	assert.ErrorIs(t, err, assert.AnError)
+	assert.True(t, svc.Closed())
+}
+
+// TestDrugo_Execute_BootError 测试 Boot 失败时不执行命令
+func TestDrugo_Execute_BootError(t *testing.T) {
+	app, _ := newTestCommandApp(t, WithService(newBootFailingService("db", assert.AnError)))
+	called := false
+	app.Command("migrate", "执行数据库迁移", func(ctx context.Context, k kernel.Kernel, args []string) error {
+		called = true
+		return nil
+	})
+
+	err := app.Execute(context.Background(), []string{"app", "migrate"})
+	assert.ErrorIs(t, err, assert.AnError)
+	assert.False(t, called)
+}
+
+// TestDrugo_Execute_DefaultServe 测试没有参数时执行 Serve
+func TestDrugo_Execute_DefaultServe(t *testing.T) {
+	svc := kerneltest.NewRunnerMock("worker")
+	app, _ := newTestCommandApp(t, WithService(svc))
+
+	require.NoError(t, app.Execute(context.Background(), []string{"app"}))
+	assert.Positive(t, svc.BootCount())
+	assert.True(t, svc.Ran())
+	assert.True(t, svc.Closed())
+}
+
+// TestDrugo_Execute_UnknownCommand 测试未注册命令返回 ErrUnknownCommand 并打印帮助
+func TestDrugo_Execute_UnknownCommand(t *testing.T) {
+	app, out := newTestCommandApp(t)
+	app.Command("migrate", "执行数据库迁移", func(ctx context.Context, k kernel.Kernel, args []string) error {
+		return nil
+	})
+
+	err := app.Execute(context.Background(), []string{"/bin/myapp", "nope"})
+	assert.ErrorIs(t, err, ErrUnknownCommand)
+	assert.Contains(t, out.String(), "Usage: myapp")
+	assert.Contains(t, out.String(), "migrate")
+	assert.Contains(t, out.String(), "routes")
+}
+
+// TestDrugo_Execute_Help 测试 help 命令
+func TestDrugo_Execute_Help(t *testing.T) {
+	app, out := newTestCommandApp(t)
+	require.NoError(t, app.Execute(context.Background(), []string{"app", "help"}))
+	assert.Contains(t, out.String(), "serve")
+	assert.Contains(t, out.String(), "config")
+}
+
+// TestDrugo_Execute_Routes 测试 routes 内置命令
+func TestDrugo_Execute_Routes(t *testing.T) {
+	router.Default().Register(func(r *gin.Engine) {
+		r.GET("/command-test/health", func(c *gin.Context) {})
+	})
+	svc := kerneltest.NewServiceMock("db")
+	app, out := newTestCommandApp(t, WithService(svc))
+
+	require.NoError(t, app.Execute(context.Background(), []string{"app", "routes"}))
+	assert.Contains(t, out.String(), "METHOD")
+	assert.Regexp(t, `GET\s+/command-test/health`, out.String())
+	assert.Zero(t, svc.BootCount(), "routes 命令不应启动服务")
+}
+
+// TestDrugo_Execute_Config 测试 config 内置命令对敏感配置脱敏
+func TestDrugo_Execute_Config(t *testing.T) {
+	app, out := newTestCommandApp(t)
+	app.config = newTestConfigManager(t, "db:\n  host: localhost\n  password: p@ss\n  auth:\n    api_token: abc\n")
+
+	require.NoError(t, app.Execute(context.Background(), []string{"app", "config"}))
+
+	var dump map[string]map[string]any
+	require.NoError(t, json.Unmarshal(out.Bytes(), &dump))
+	assert.Equal(t, "localhost", dump["db"]["host"])
+	assert.Equal(t, config.RedactedValue, dump["db"]["password"])
+	assert.Equal(t, map[string]any{"api_token": config.RedactedValue}, dump["db"]["auth"])
+	assert.NotContains(t, out.String(), "p@ss")
+}
+
+// TestDrugo_Execute_OverrideBuiltin 测试用户命令可以覆盖内置命令
+func TestDrugo_Execute_OverrideBuiltin(t *testing.T) {
+	app, _ := newTestCommandApp(t)
+	called := false
+	app.Command("routes", "自定义路由输出", func(ctx context.Context, k kernel.Kernel, args []string) error {
+		called = true
+		return nil
+	})
+
+	require.NoError(t, app.Execute(context.Background(), []string{"app", "routes"}))
+	assert.True(t, called)
+}
@@ -0,0 +1,49 @@
+package drugo
+
+import (
+	"fmt"
+
+	"github.com/qq1060656096/drugo/config"
+	"github.com/qq1060656096/drugo/kernel"
+	"github.com/spf13/viper"
+)
+
+// configSection 返回注入给服务的配置段名称。
+// 优先使用 WithServiceConfig 指定的名称，否则使用服务名称。
+func (d *Drugo) configSection(service kernel.Service) string {
+	if section, ok := d.configSections[service.Name()]; ok && section != "" {
+		return section
+	}
+	return service.Name()
+}
+
+// configureService 在 Boot 之前为实现了 kernel.Configurable 的服务注入配置。
+// 未实现 kernel.Configurable 的服务不受影响。返回的错误由 bootService 包装为 kernel.ErrServiceInitFailed。
+func (d *Drugo) configureService(service kernel.Service) error {
+	c, ok := service.(kernel.Configurable)
+	if !ok {
+		return nil
+	}
+
+	section := d.configSection(service)
+	var v *viper.Viper
+	if d.Config() != nil {
+		sub, err := d.Config().Get(section)
+		if err != nil && !config.IsNotFound(err) {
+			return fmt.Errorf("config: %w", err)
+		}
+		v = sub
+		d.Config().MarkRead(section)
+	}
+
+	if v == nil {
+		if r, ok := service.(kernel.ConfigRequirer); ok && r.ConfigRequired() {
+			return fmt.Errorf("%w: %q", config.ErrNotFound, section)
+		}
+	}
+
+	if err := c.Configure(v); err != nil {
+		return fmt.Errorf("configure: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,128 @@
+package drugo
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/qq1060656096/drugo/config"
+	"github.com/qq1060656096/drugo/kernel"
+	"github.com/qq1060656096/drugo/kernel/kerneltest"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// configurableMockService 是一个实现了 kernel.Configurable 的模拟服务
+type configurableMockService struct {
+	*kerneltest.ServiceMock
+	configured     bool
+	configuredWith *viper.Viper
+	configureError error
+	required       bool
+}
+
+func (m *configurableMockService) Configure(v *viper.Viper) error {
+	m.configured = true
+	m.configuredWith = v
+	return m.configureError
+}
+
+func (m *configurableMockService) ConfigRequired() bool {
+	return m.required
+}
+
+// newTestConfigManager 在临时目录中写入配置文件并创建配置管理器
+func newTestConfigManager(t *testing.T, content string, opts ...config.Option) *config.Manager {
+	t.Helper()
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "app.yaml"), []byte(content), 0644))
+	m, err := config.NewManager(dir, opts...)
+	require.NoError(t, err)
+	return m
+}
+
+// TestDrugo_Boot_Configurable 测试 Boot 前的配置注入
+func TestDrugo_Boot_Configurable(t *testing.T) {
+	const content = "metrics:\n  addr: \":9100\"\ncustom:\n  addr: \":9200\"\n"
+
+	t.Run("配置段存在", func(t *testing.T) {
+		svc := &configurableMockService{ServiceMock: kerneltest.NewServiceMock("metrics")}
+		app := New(WithService(svc))
+		app.logger = newTestLogManager(t)
+		app.config = newTestConfigManager(t, content)
+
+		require.NoError(t, app.Boot(context.Background()))
+		assert.True(t, svc.configured)
+		require.NotNil(t, svc.configuredWith)
+		assert.Equal(t, ":9100", svc.configuredWith.GetString("addr"))
+		assert.Positive(t, svc.BootCount())
+	})
+
+	t.Run("指定配置段名称", func(t *testing.T) {
+		svc := &configurableMockService{ServiceMock: kerneltest.NewServiceMock("metrics")}
+		app := New(WithServiceConfig(svc, "custom"))
+		app.logger = newTestLogManager(t)
+		app.config = newTestConfigManager(t, content)
+
+		require.NoError(t, app.Boot(context.Background()))
+		require.NotNil(t, svc.configuredWith)
+		assert.Equal(t, ":9200", svc.configuredWith.GetString("addr"))
+	})
+
+	t.Run("配置段不存在", func(t *testing.T) {
+		svc := &configurableMockService{ServiceMock: kerneltest.NewServiceMock("tracing")}
+		app := New(WithService(svc))
+		app.logger = newTestLogManager(t)
+		app.config = newTestConfigManager(t, content)
+
+		require.NoError(t, app.Boot(context.Background()))
+		assert.True(t, svc.configured)
+		assert.Nil(t, svc.configuredWith)
+		assert.Positive(t, svc.BootCount())
+	})
+
+	t.Run("必需的配置段不存在", func(t *testing.T) {
+		svc := &configurableMockService{
+			ServiceMock: kerneltest.NewServiceMock("tracing"),
+			required:    true,
+		}
+		app := New(WithService(svc))
+		app.logger = newTestLogManager(t)
+		app.config = newTestConfigManager(t, content)
+
+		err := app.Boot(context.Background())
+		require.Error(t, err)
+		assert.True(t, kernel.IsServiceInitFailed(err))
+		assert.True(t, config.IsNotFound(err))
+		assert.False(t, svc.configured)
+		assert.Zero(t, svc.BootCount())
+	})
+
+	t.Run("Configure 返回错误", func(t *testing.T) {
+		svc := &configurableMockService{
+			ServiceMock:    kerneltest.NewServiceMock("metrics"),
+			configureError: assert.AnError,
+		}
+		app := New(WithService(svc))
+		app.logger = newTestLogManager(t)
+		app.config = newTestConfigManager(t, content)
+
+		err := app.Boot(context.Background())
+		require.Error(t, err)
+		assert.True(t, kernel.IsServiceInitFailed(err))
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.Zero(t, svc.BootCount())
+	})
+
+	t.Run("非 Configurable 服务不受影响", func(t *testing.T) {
+		svc := kerneltest.NewServiceMock("metrics")
+		app := New(WithService(svc))
+		app.logger = newTestLogManager(t)
+		app.config = newTestConfigManager(t, content)
+
+		require.NoError(t, app.Boot(context.Background()))
+		assert.Positive(t, svc.BootCount())
+	})
+}
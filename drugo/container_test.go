@@ -1,82 +1,64 @@
 package drugo
 
 import (
-	"context"
 	"errors"
 	"sync"
 	"testing"
 
 	"github.com/qq1060656096/drugo/kernel"
+	"github.com/qq1060656096/drugo/kernel/kerneltest"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
-// mockContainerService 是一个用于测试容器的模拟服务实现
-type mockContainerService struct {
-	name string
-}
-
-func (m *mockContainerService) Name() string {
-	return m.name
-}
-
-func (m *mockContainerService) Boot(ctx context.Context) error {
-	return nil
-}
-
-func (m *mockContainerService) Close(ctx context.Context) error {
-	return nil
-}
-
 // TestNewContainer 测试容器创建
 func TestNewContainer(t *testing.T) {
 	container := NewContainer[kernel.Service]()
 
 	require.NotNil(t, container)
-	assert.NotNil(t, container.services)
-	assert.Empty(t, container.services)
-	assert.Empty(t, container.servicesIds)
+	assert.Empty(t, container.Values())
+	assert.Empty(t, container.Names())
 }
 
 // TestContainer_Bind 测试服务绑定功能
 func TestContainer_Bind(t *testing.T) {
 	container := NewContainer[kernel.Service]()
-	service1 := &mockContainerService{name: "service1"}
-	service2 := &mockContainerService{name: "service2"}
+	service1 := kerneltest.NewServiceMock("service1")
+	service2 := kerneltest.NewServiceMock("service2")
 
 	// 测试绑定新服务
 	container.Bind("service1", service1)
-	assert.Equal(t, service1, container.services["service1"])
-	assert.Contains(t, container.servicesIds, "service1")
-	assert.Len(t, container.servicesIds, 1)
+	assert.Equal(t, service1, container.MustGet("service1"))
+	assert.Contains(t, container.Names(), "service1")
+	assert.Len(t, container.Names(), 1)
 
 	// 测试覆盖已存在的服务
-	service1New := &mockContainerService{name: "service1-new"}
+	service1New := kerneltest.NewServiceMock("service1-new")
 	container.Bind("service1", service1New)
-	assert.Equal(t, service1New, container.services["service1"])
-	assert.Len(t, container.servicesIds, 1) // 服务ID列表长度不应增加
+	assert.Equal(t, service1New, container.MustGet("service1"))
+	assert.Len(t, container.Names(), 1) // 服务ID列表长度不应增加
 
 	// 测试绑定第二个服务
 	container.Bind("service2", service2)
-	assert.Equal(t, service2, container.services["service2"])
-	assert.Contains(t, container.servicesIds, "service2")
-	assert.Len(t, container.servicesIds, 2)
+	assert.Equal(t, service2, container.MustGet("service2"))
+	assert.Contains(t, container.Names(), "service2")
+	assert.Len(t, container.Names(), 2)
 }
 
 // TestContainer_Bind_EmptyName 测试绑定空名称服务
 func TestContainer_Bind_EmptyName(t *testing.T) {
 	container := NewContainer[kernel.Service]()
-	service := &mockContainerService{name: "empty-service"}
+	service := kerneltest.NewServiceMock("empty-service")
 
 	container.Bind("", service)
-	assert.Equal(t, service, container.services[""])
-	assert.Contains(t, container.servicesIds, "")
+	assert.Equal(t, service, container.MustGet(""))
+	assert.Contains(t, container.Names(), "")
 }
 
 // TestContainer_Get 测试服务获取功能
 func TestContainer_Get(t *testing.T) {
 	container := NewContainer[kernel.Service]()
-	service := &mockContainerService{name: "test-service"}
+	service := kerneltest.NewServiceMock("test-service")
 
 	// 测试获取不存在的服务
 	result, err := container.Get("nonexistent")
@@ -95,7 +77,7 @@ func TestContainer_Get(t *testing.T) {
 // TestContainer_Get_EmptyName 测试获取空名称服务
 func TestContainer_Get_EmptyName(t *testing.T) {
 	container := NewContainer[kernel.Service]()
-	service := &mockContainerService{name: "empty-service"}
+	service := kerneltest.NewServiceMock("empty-service")
 
 	// 绑定空名称服务
 	container.Bind("", service)
@@ -109,7 +91,7 @@ func TestContainer_Get_EmptyName(t *testing.T) {
 // TestContainer_MustGet 测试必须获取服务功能
 func TestContainer_MustGet(t *testing.T) {
 	container := NewContainer[kernel.Service]()
-	service := &mockContainerService{name: "must-service"}
+	service := kerneltest.NewServiceMock("must-service")
 
 	// 绑定服务
 	container.Bind("must-service", service)
@@ -127,7 +109,7 @@ func TestContainer_MustGet(t *testing.T) {
 // TestContainer_MustGet_EmptyName 测试必须获取空名称服务
 func TestContainer_MustGet_EmptyName(t *testing.T) {
 	container := NewContainer[kernel.Service]()
-	service := &mockContainerService{name: "empty-service"}
+	service := kerneltest.NewServiceMock("empty-service")
 
 	// 绑定空名称服务
 	container.Bind("", service)
@@ -140,9 +122,9 @@ func TestContainer_MustGet_EmptyName(t *testing.T) {
 // TestContainer_Services 测试获取所有服务功能
 func TestContainer_Services(t *testing.T) {
 	container := NewContainer[kernel.Service]()
-	service1 := &mockContainerService{name: "service1"}
-	service2 := &mockContainerService{name: "service2"}
-	service3 := &mockContainerService{name: "service3"}
+	service1 := kerneltest.NewServiceMock("service1")
+	service2 := kerneltest.NewServiceMock("service2")
+	service3 := kerneltest.NewServiceMock("service3")
 
 	// 测试空容器
 	services := container.Services()
@@ -162,15 +144,15 @@ func TestContainer_Services(t *testing.T) {
 
 	// 验证返回的是副本，修改不影响原容器
 	services[0] = nil
-	assert.Equal(t, service1, container.services["service1"])
+	assert.Equal(t, service1, container.MustGet("service1"))
 }
 
 // TestContainer_Services_WithOverride 测试服务覆盖后的服务列表
 func TestContainer_Services_WithOverride(t *testing.T) {
 	container := NewContainer[kernel.Service]()
-	service1 := &mockContainerService{name: "service1"}
-	service1New := &mockContainerService{name: "service1-new"}
-	service2 := &mockContainerService{name: "service2"}
+	service1 := kerneltest.NewServiceMock("service1")
+	service1New := kerneltest.NewServiceMock("service1-new")
+	service2 := kerneltest.NewServiceMock("service2")
 
 	// 绑定服务
 	container.Bind("service1", service1)
@@ -189,7 +171,7 @@ func TestContainer_Services_WithOverride(t *testing.T) {
 // TestContainer_Names 测试获取所有服务名称功能
 func TestContainer_Names(t *testing.T) {
 	container := NewContainer[kernel.Service]()
-	service := &mockContainerService{name: "test-service"}
+	service := kerneltest.NewServiceMock("test-service")
 
 	// 测试空容器
 	names := container.Names()
@@ -209,13 +191,13 @@ func TestContainer_Names(t *testing.T) {
 
 	// 验证返回的是副本，修改不影响原容器
 	names[0] = "modified"
-	assert.Equal(t, "service1", container.servicesIds[0])
+	assert.Equal(t, "service1", container.Names()[0])
 }
 
 // TestContainer_Names_EmptyName 测试包含空名称的服务名称列表
 func TestContainer_Names_EmptyName(t *testing.T) {
 	container := NewContainer[kernel.Service]()
-	service := &mockContainerService{name: "empty-service"}
+	service := kerneltest.NewServiceMock("empty-service")
 
 	// 绑定空名称服务
 	container.Bind("", service)
@@ -231,7 +213,7 @@ func TestContainer_Names_EmptyName(t *testing.T) {
 // TestContainer_ConcurrentAccess 测试并发访问安全性
 func TestContainer_ConcurrentAccess(t *testing.T) {
 	container := NewContainer[kernel.Service]()
-	service := &mockContainerService{name: "concurrent-service"}
+	service := kerneltest.NewServiceMock("concurrent-service")
 
 	// 使用 WaitGroup 来协调并发操作
 	var wg sync.WaitGroup
@@ -260,14 +242,14 @@ func TestContainer_ConcurrentAccess(t *testing.T) {
 	wg.Wait()
 
 	// 验证所有服务都被正确绑定
-	assert.Len(t, container.services, numGoroutines)
-	assert.Len(t, container.servicesIds, numGoroutines)
+	assert.Len(t, container.Values(), numGoroutines)
+	assert.Len(t, container.Names(), numGoroutines)
 }
 
 // TestContainer_ConcurrentBindAndGet 测试并发绑定和获取
 func TestContainer_ConcurrentBindAndGet(t *testing.T) {
 	container := NewContainer[kernel.Service]()
-	service := &mockContainerService{name: "concurrent-service"}
+	service := kerneltest.NewServiceMock("concurrent-service")
 
 	var wg sync.WaitGroup
 	numGoroutines := 50
@@ -301,7 +283,7 @@ func TestContainer_ConcurrentBindAndGet(t *testing.T) {
 // TestContainer_OrderPreservation 测试服务注册顺序保持
 func TestContainer_OrderPreservation(t *testing.T) {
 	container := NewContainer[kernel.Service]()
-	service := &mockContainerService{name: "order-service"}
+	service := kerneltest.NewServiceMock("order-service")
 
 	// 按特定顺序绑定服务
 	bindOrder := []string{"zebra", "apple", "banana", "cherry"}
@@ -329,7 +311,7 @@ func TestContainer_InterfaceCompliance(t *testing.T) {
 	var _ kernel.Container[kernel.Service] = (*Container[kernel.Service])(nil)
 
 	container := NewContainer[kernel.Service]()
-	service := &mockContainerService{name: "interface-service"}
+	service := kerneltest.NewServiceMock("interface-service")
 
 	// 测试所有接口方法
 	container.Bind("test", service)
@@ -352,7 +334,7 @@ func TestContainer_InterfaceCompliance(t *testing.T) {
 // BenchmarkContainer_Bind 测试绑定服务的性能
 func BenchmarkContainer_Bind(b *testing.B) {
 	container := NewContainer[kernel.Service]()
-	service := &mockContainerService{name: "benchmark-service"}
+	service := kerneltest.NewServiceMock("benchmark-service")
 
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
@@ -363,7 +345,7 @@ func BenchmarkContainer_Bind(b *testing.B) {
 // BenchmarkContainer_Get 测试获取服务的性能
 func BenchmarkContainer_Get(b *testing.B) {
 	container := NewContainer[kernel.Service]()
-	service := &mockContainerService{name: "benchmark-service"}
+	service := kerneltest.NewServiceMock("benchmark-service")
 	container.Bind("service", service)
 
 	b.ResetTimer()
@@ -375,7 +357,7 @@ func BenchmarkContainer_Get(b *testing.B) {
 // BenchmarkContainer_Services 测试获取所有服务的性能
 func BenchmarkContainer_Services(b *testing.B) {
 	container := NewContainer[kernel.Service]()
-	service := &mockContainerService{name: "benchmark-service"}
+	service := kerneltest.NewServiceMock("benchmark-service")
 
 	// 预先绑定一些服务
 	for i := 0; i < 100; i++ {
@@ -391,7 +373,7 @@ func BenchmarkContainer_Services(b *testing.B) {
 // BenchmarkContainer_Names 测试获取所有服务名称的性能
 func BenchmarkContainer_Names(b *testing.B) {
 	container := NewContainer[kernel.Service]()
-	service := &mockContainerService{name: "benchmark-service"}
+	service := kerneltest.NewServiceMock("benchmark-service")
 
 	// 预先绑定一些服务
 	for i := 0; i < 100; i++ {
@@ -407,7 +389,7 @@ func BenchmarkContainer_Names(b *testing.B) {
 // BenchmarkContainer_ConcurrentAccess 测试并发访问性能
 func BenchmarkContainer_ConcurrentAccess(b *testing.B) {
 	container := NewContainer[kernel.Service]()
-	service := &mockContainerService{name: "benchmark-service"}
+	service := kerneltest.NewServiceMock("benchmark-service")
 
 	// 预先绑定一些服务
 	for i := 0; i < 10; i++ {
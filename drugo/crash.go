@@ -0,0 +1,41 @@
+package drugo
+
+import (
+	"fmt"
+	"runtime/debug"
+
+	"go.uber.org/zap"
+)
+
+// callRecovered 调用服务的生命周期方法 fn。fn panic 时恢复 panic，在 crashDir 中写入崩溃报告
+// （包含 panic 的值、堆栈以及日志配置 crash_ring 保留的最近日志，见 log.Manager.DumpCrash），
+// 并返回包装了 ErrServicePanic 的错误，随后按普通的 Boot 或 Run 失败处理，优雅停机照常进行
+func (d *Drugo) callRecovered(phase Phase, serviceName string, fn func() error) (err error) {
+	defer func() {
+		r := recover()
+		if r == nil {
+			return
+		}
+		stack := debug.Stack()
+		err = fmt.Errorf("%w: %s %s: %v", ErrServicePanic, serviceName, phase, r)
+
+		l := d.frameworkLogger()
+		path, werr := d.Logger().WriteCrashFile(d.crashDir(), r, stack)
+		if werr != nil {
+			l.Error("write crash report failed", zap.String("service", serviceName), zap.Error(werr))
+		}
+		l.Error("service panic recovered",
+			zap.String("service", serviceName),
+			zap.String("phase", string(phase)),
+			zap.Any("panic", r),
+			zap.String("crash_report", path),
+			zap.ByteString("stack", stack),
+		)
+	}()
+	return fn()
+}
+
+// crashDir 返回崩溃报告的目录：日志配置的 crash_dir（相对路径基于 Root），默认为 Root()/runtime
+func (d *Drugo) crashDir() string {
+	return ResolveDir(d.Root(), d.logConfig.CrashDir, "runtime")
+}
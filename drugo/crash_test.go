@@ -0,0 +1,97 @@
+package drugo
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/qq1060656096/drugo/kernel"
+	"github.com/qq1060656096/drugo/kernel/kerneltest"
+	"github.com/qq1060656096/drugo/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newCrashLogManager 创建保留最近 ring 条日志的控制台日志管理器
+func newCrashLogManager(t *testing.T, ring int) *log.Manager {
+	t.Helper()
+	m, err := log.NewManager(log.Config{
+		Level:     "info",
+		Outputs:   []log.OutputConfig{{Type: "console", Format: "text"}},
+		CrashRing: ring,
+	})
+	require.NoError(t, err)
+	return m
+}
+
+// readCrashReport 返回 dir 中唯一的崩溃报告内容
+func readCrashReport(t *testing.T, dir string) string {
+	t.Helper()
+	files, err := filepath.Glob(filepath.Join(dir, "crash-*.log"))
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	data, err := os.ReadFile(files[0])
+	require.NoError(t, err)
+	return string(data)
+}
+
+// TestDrugo_Boot_Panic 测试 Boot 中的 panic 被恢复为 Boot 失败，并在 runtime 目录中写入包含最近日志的崩溃报告
+func TestDrugo_Boot_Panic(t *testing.T) {
+	db := kerneltest.NewServiceMock("db")
+	cache := kerneltest.NewServiceMock("cache")
+	cache.BootFunc = func(ctx context.Context) error {
+		kernel.ServiceLoggerFromContext(ctx).Info("connecting cache")
+		panic("cache: nil pool")
+	}
+	root := t.TempDir()
+	app := New(WithRoot(root), WithServices(db, cache))
+	app.logger = newCrashLogManager(t, 100)
+
+	err := app.Boot(context.Background())
+	require.ErrorIs(t, err, ErrServicePanic)
+	require.ErrorIs(t, err, kernel.ErrServiceInitFailed)
+	assert.ErrorContains(t, err, "cache boot: cache: nil pool")
+
+	report := readCrashReport(t, filepath.Join(root, "runtime"))
+	assert.Contains(t, report, "panic: cache: nil pool [string]")
+	assert.Contains(t, report, "crash_test.go")
+	assert.Contains(t, report, `[cache] {"level":"info"`)
+	assert.Contains(t, report, `"msg":"connecting cache"`)
+	assert.Contains(t, report, `"msg":"service booted","biz":"drugo","service":"db"`)
+
+	// Boot 失败后 Shutdown 照常关闭已经启动的服务
+	require.NoError(t, app.Shutdown(context.Background()))
+	assert.True(t, db.Closed())
+}
+
+// TestDrugo_Run_Panic 测试 Run 中的 panic 使 Run 返回错误并取消其他 Runner，没有日志管理器时崩溃报告只包含 panic 信息
+func TestDrugo_Run_Panic(t *testing.T) {
+	worker := kerneltest.NewRunnerMock("worker")
+	worker.RunFunc = func(ctx context.Context) error {
+		var m map[string]int
+		m["x"] = 1
+		return nil
+	}
+	api := newBlockingRunner("api")
+	root := t.TempDir()
+	app := New(WithRoot(root), WithServices(api, worker))
+	app.logConfig.CrashDir = "crash"
+
+	h, err := app.Start(context.Background())
+	require.NoError(t, err)
+	select {
+	case <-h.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatal("Run did not return after the runner panicked")
+	}
+	require.ErrorIs(t, h.Err(), ErrServicePanic)
+	require.ErrorIs(t, h.Err(), kernel.ErrServiceRunFailed)
+	assert.ErrorContains(t, h.Err(), "assignment to entry in nil map")
+	require.NoError(t, h.Stop(context.Background()))
+
+	report := readCrashReport(t, filepath.Join(root, "crash"))
+	assert.Contains(t, report, "assignment to entry in nil map")
+	assert.NotContains(t, report, "recent logs")
+}
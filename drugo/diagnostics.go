@@ -0,0 +1,215 @@
+package drugo
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/pprof"
+	"strings"
+	"time"
+
+	"github.com/qq1060656096/drugo/kernel"
+	"go.uber.org/zap"
+)
+
+// 诊断采集的默认配置
+const (
+	// DefaultDiagnosticsDir 是诊断文件相对项目根目录的默认目录
+	DefaultDiagnosticsDir = "runtime/diagnostics"
+	// DefaultDiagnosticsCPUDuration 是 CPU profile 的默认采集时长
+	DefaultDiagnosticsCPUDuration = 30 * time.Second
+	// DefaultDiagnosticsRetention 是诊断文件的默认保留时长
+	DefaultDiagnosticsRetention = 7 * 24 * time.Hour
+)
+
+// diagnosticsPrefix 是诊断文件名的前缀，清理过期文件时只处理带该前缀的文件
+const diagnosticsPrefix = "diag-"
+
+// WithDiagnosticsDir 启用按需诊断采集（见 Drugo.CaptureDiagnostics），诊断文件写入 dir。
+// dir 为相对路径时基于项目根目录，为空时使用 DefaultDiagnosticsDir。
+// 非 Windows 平台上 Serve 运行期间收到 SIGUSR2 时在后台执行一次采集。
+func WithDiagnosticsDir(dir string) Option {
+	return func(o *options) {
+		o.diagnosticsDir = dir
+		if dir == "" {
+			o.diagnosticsDir = DefaultDiagnosticsDir
+		}
+		for _, sig := range diagnosticsSignals {
+			WithSignalHandler(sig, func(ctx context.Context, d *Drugo) {
+				// 采集包含较长的 CPU profile，不能阻塞信号处理主流程
+				go func() { _, _ = d.CaptureDiagnostics(ctx) }()
+			})(o)
+		}
+	}
+}
+
+// WithDiagnosticsCPUDuration 设置诊断采集中 CPU profile 的时长，默认 DefaultDiagnosticsCPUDuration
+func WithDiagnosticsCPUDuration(d time.Duration) Option {
+	return func(o *options) {
+		o.diagnosticsCPU = d
+	}
+}
+
+// WithDiagnosticsRetention 设置诊断文件的保留时长，默认 DefaultDiagnosticsRetention。
+// 每次采集前删除诊断目录中修改时间早于保留时长的诊断文件
+func WithDiagnosticsRetention(d time.Duration) Option {
+	return func(o *options) {
+		o.diagnosticsRetention = d
+	}
+}
+
+// CaptureDiagnostics 将当前进程的诊断信息写入诊断目录，返回写入的文件路径：
+//   - goroutine 堆栈（debug=2）
+//   - 堆内存 profile
+//   - Status() 与 BootReport() 快照（JSON）
+//   - CPU profile，时长见 WithDiagnosticsCPUDuration，ctx 取消时提前结束
+//
+// 文件名包含采集时间，例如 diag-20240102T150405-goroutine.txt。
+// 未启用 WithDiagnosticsDir 时返回 ErrDiagnosticsDisabled；已有采集在进行时记录日志并返回 ErrDiagnosticsInProgress。
+// 单项采集失败不会中断其他项，所有失败会合并返回。
+func (d *Drugo) CaptureDiagnostics(ctx context.Context) ([]string, error) {
+	l := d.frameworkLogger()
+	if d.diagnosticsDir == "" {
+		return nil, ErrDiagnosticsDisabled
+	}
+	if !d.diagnosing.CompareAndSwap(false, true) {
+		l.Warn("diagnostics capture rejected, another capture is in progress")
+		return nil, ErrDiagnosticsInProgress
+	}
+	defer d.diagnosing.Store(false)
+
+	dir := ResolveDir(d.Root(), d.diagnosticsDir, DefaultDiagnosticsDir)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		l.Error("diagnostics capture failed", zap.String("dir", dir), zap.Error(err))
+		return nil, err
+	}
+	d.cleanDiagnostics(l, dir)
+
+	l.Info("diagnostics capture start", zap.String("dir", dir))
+	prefix := filepath.Join(dir, diagnosticsPrefix+d.Clock().Now().Format("20060102T150405.000")+"-")
+	artifacts := []struct {
+		name  string
+		write func(f *os.File) error
+	}{
+		{"goroutine.txt", func(f *os.File) error { return pprof.Lookup("goroutine").WriteTo(f, 2) }},
+		{"heap.pprof", func(f *os.File) error {
+			runtime.GC()
+			return pprof.Lookup("heap").WriteTo(f, 0)
+		}},
+		{"status.json", func(f *os.File) error { return writeJSON(f, d.statusSnapshot()) }},
+		{"boot-report.json", func(f *os.File) error { return writeJSON(f, d.BootReport()) }},
+		{"cpu.pprof", func(f *os.File) error { return captureCPUProfile(ctx, d.Clock(), f, d.diagnosticsCPUOrDefault()) }},
+	}
+
+	var paths []string
+	var errs []error
+	for _, a := range artifacts {
+		path := prefix + a.name
+		if err := writeArtifact(path, a.write); err != nil {
+			l.Error("diagnostics artifact failed", zap.String("path", path), zap.Error(err))
+			errs = append(errs, fmt.Errorf("%s: %w", a.name, err))
+			continue
+		}
+		l.Info("diagnostics artifact written", zap.String("path", path))
+		paths = append(paths, path)
+	}
+	l.Info("diagnostics capture complete", zap.Int("artifacts", len(paths)))
+	return paths, errors.Join(errs...)
+}
+
+// diagnosticsCPUOrDefault 返回 CPU profile 的采集时长
+func (d *Drugo) diagnosticsCPUOrDefault() time.Duration {
+	if d.diagnosticsCPU <= 0 {
+		return DefaultDiagnosticsCPUDuration
+	}
+	return d.diagnosticsCPU
+}
+
+// cleanDiagnostics 删除 dir 中超过保留时长的诊断文件
+func (d *Drugo) cleanDiagnostics(l *zap.Logger, dir string) {
+	retention := d.diagnosticsRetention
+	if retention <= 0 {
+		retention = DefaultDiagnosticsRetention
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		l.Warn("diagnostics cleanup failed", zap.String("dir", dir), zap.Error(err))
+		return
+	}
+	cutoff := d.Clock().Now().Add(-retention)
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasPrefix(e.Name(), diagnosticsPrefix) {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil || !info.ModTime().Before(cutoff) {
+			continue
+		}
+		path := filepath.Join(dir, e.Name())
+		if err := os.Remove(path); err != nil {
+			l.Warn("diagnostics cleanup failed", zap.String("path", path), zap.Error(err))
+			continue
+		}
+		l.Info("diagnostics file expired", zap.String("path", path))
+	}
+}
+
+// diagnosticsStatus 是 status.json 中单个服务的状态
+type diagnosticsStatus struct {
+	State        ServiceState      `json:"state"`
+	Error        string            `json:"error,omitempty"`
+	Capabilities map[string]string `json:"capabilities,omitempty"`
+}
+
+// statusSnapshot 返回可以序列化为 JSON 的服务状态
+func (d *Drugo) statusSnapshot() map[string]diagnosticsStatus {
+	status := d.Status()
+	result := make(map[string]diagnosticsStatus, len(status))
+	for name, st := range status {
+		s := diagnosticsStatus{State: st.State, Capabilities: st.Capabilities}
+		if st.Err != nil {
+			s.Error = st.Err.Error()
+		}
+		result[name] = s
+	}
+	return result
+}
+
+// writeArtifact 创建 path 并使用 write 写入内容
+func writeArtifact(path string, write func(f *os.File) error) error {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	if err := write(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// writeJSON 将 v 以缩进的 JSON 格式写入 f
+func writeJSON(f *os.File, v any) error {
+	enc := json.NewEncoder(f)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+// captureCPUProfile 采集 duration 时长的 CPU profile，ctx 取消时提前结束
+func captureCPUProfile(ctx context.Context, c kernel.Clock, f *os.File, duration time.Duration) error {
+	if err := pprof.StartCPUProfile(f); err != nil {
+		return err
+	}
+	timer := c.NewTimer(duration)
+	defer timer.Stop()
+	select {
+	case <-timer.C():
+	case <-ctx.Done():
+	}
+	pprof.StopCPUProfile()
+	return nil
+}
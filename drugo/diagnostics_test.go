@@ -0,0 +1,124 @@
+package drugo
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/qq1060656096/drugo/kernel"
+	"github.com/qq1060656096/drugo/kernel/kerneltest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestDrugo_CaptureDiagnostics 测试诊断采集写入所有诊断文件
+func TestDrugo_CaptureDiagnostics(t *testing.T) {
+	root := t.TempDir()
+	app := New(WithRoot(root), WithService(kerneltest.NewServiceMock("db")),
+		WithDiagnosticsDir(""), WithDiagnosticsCPUDuration(10*time.Millisecond))
+
+	paths, err := app.CaptureDiagnostics(context.Background())
+	require.NoError(t, err)
+	require.Len(t, paths, 5)
+
+	suffixes := []string{"goroutine.txt", "heap.pprof", "status.json", "boot-report.json", "cpu.pprof"}
+	for i, path := range paths {
+		assert.Equal(t, filepath.Join(root, DefaultDiagnosticsDir), filepath.Dir(path))
+		assert.True(t, strings.HasPrefix(filepath.Base(path), diagnosticsPrefix))
+		assert.True(t, strings.HasSuffix(path, suffixes[i]), path)
+		info, err := os.Stat(path)
+		require.NoError(t, err)
+		assert.NotZero(t, info.Size(), path)
+	}
+
+	goroutines, err := os.ReadFile(paths[0])
+	require.NoError(t, err)
+	assert.Contains(t, string(goroutines), "TestDrugo_CaptureDiagnostics")
+
+	data, err := os.ReadFile(paths[2])
+	require.NoError(t, err)
+	var status map[string]diagnosticsStatus
+	require.NoError(t, json.Unmarshal(data, &status))
+	assert.Equal(t, ServiceStatePending, status["db"].State)
+}
+
+// TestDrugo_CaptureDiagnostics_Disabled 测试未启用时返回 ErrDiagnosticsDisabled
+func TestDrugo_CaptureDiagnostics_Disabled(t *testing.T) {
+	app := New(WithRoot(t.TempDir()))
+	paths, err := app.CaptureDiagnostics(context.Background())
+	assert.ErrorIs(t, err, ErrDiagnosticsDisabled)
+	assert.Empty(t, paths)
+}
+
+// TestDrugo_CaptureDiagnostics_InProgress 测试采集进行中时拒绝新的采集
+func TestDrugo_CaptureDiagnostics_InProgress(t *testing.T) {
+	root := t.TempDir()
+	app := New(WithRoot(root), WithDiagnosticsDir("diag"), WithDiagnosticsCPUDuration(time.Minute))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		_, err := app.CaptureDiagnostics(ctx)
+		done <- err
+	}()
+	require.Eventually(t, app.diagnosing.Load, time.Second, time.Millisecond)
+
+	_, err := app.CaptureDiagnostics(context.Background())
+	assert.ErrorIs(t, err, ErrDiagnosticsInProgress)
+
+	// 取消 ctx 提前结束 CPU profile
+	cancel()
+	select {
+	case err := <-done:
+		require.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("capture did not stop after ctx cancel")
+	}
+	assert.False(t, app.diagnosing.Load())
+
+	entries, err := os.ReadDir(filepath.Join(root, "diag"))
+	require.NoError(t, err)
+	assert.Len(t, entries, 5)
+}
+
+// TestDrugo_CaptureDiagnostics_Retention 测试采集前清理过期的诊断文件
+func TestDrugo_CaptureDiagnostics_Retention(t *testing.T) {
+	root := t.TempDir()
+	dir := filepath.Join(root, DefaultDiagnosticsDir)
+	require.NoError(t, os.MkdirAll(dir, 0755))
+
+	// 文件名与过期时间按 WithClock 设置的时钟计算
+	c := kernel.NewFakeClock(time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC))
+	old := c.Now().Add(-2 * time.Hour)
+	recent := c.Now().Add(-30 * time.Minute)
+	expired := filepath.Join(dir, diagnosticsPrefix+"old-heap.pprof")
+	unrelated := filepath.Join(dir, "notes.txt")
+	for _, path := range []string{expired, unrelated} {
+		require.NoError(t, os.WriteFile(path, []byte("x"), 0644))
+		require.NoError(t, os.Chtimes(path, old, old))
+	}
+
+	kept := filepath.Join(dir, diagnosticsPrefix+"recent-heap.pprof")
+	require.NoError(t, os.WriteFile(kept, []byte("x"), 0644))
+	require.NoError(t, os.Chtimes(kept, recent, recent))
+
+	app := New(WithRoot(root), WithDiagnosticsDir(""), WithClock(c),
+		WithDiagnosticsCPUDuration(time.Millisecond), WithDiagnosticsRetention(time.Hour))
+	// CPU profile 按同一个时钟等待
+	go func() {
+		c.BlockUntilWaiters(1)
+		c.Advance(time.Millisecond)
+	}()
+	paths, err := app.CaptureDiagnostics(context.Background())
+	require.NoError(t, err)
+	require.NotEmpty(t, paths)
+	assert.True(t, strings.HasPrefix(filepath.Base(paths[0]), diagnosticsPrefix+"20260101T120000.000-"), paths[0])
+
+	assert.NoFileExists(t, expired)
+	assert.FileExists(t, kept)
+	assert.FileExists(t, unrelated)
+}
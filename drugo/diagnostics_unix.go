@@ -0,0 +1,11 @@
+//go:build !windows
+
+package drugo
+
+import (
+	"os"
+	"syscall"
+)
+
+// diagnosticsSignals 是触发诊断采集的信号
+var diagnosticsSignals = []os.Signal{syscall.SIGUSR2}
@@ -0,0 +1,8 @@
+//go:build windows
+
+package drugo
+
+import "os"
+
+// diagnosticsSignals 是触发诊断采集的信号，Windows 不支持 SIGUSR2，只能调用 Drugo.CaptureDiagnostics
+var diagnosticsSignals []os.Signal
@@ -0,0 +1,73 @@
+package drugo
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/qq1060656096/drugo/kernel"
+	"github.com/qq1060656096/drugo/pkg/clock"
+	"go.uber.org/zap"
+)
+
+// drainTimeoutOrDefault 返回排空阶段的超时时间，未设置时为停机超时时间的一半
+func (d *Drugo) drainTimeoutOrDefault() time.Duration {
+	if d.drainTimeout > 0 {
+		return d.drainTimeout
+	}
+	return d.shutdownTimeoutOrDefault() / 2
+}
+
+// drain 并发调用所有实现了 kernel.Drainer 的服务的 Drain 方法。
+// 排空超时后不再等待未完成的服务，直接进入关闭阶段；降级的可选服务不参与排空。
+func (d *Drugo) drain(ctx context.Context, l *zap.Logger, services []kernel.Service) {
+	var drainers []kernel.Service
+	for _, service := range services {
+		if _, ok := service.(kernel.Drainer); ok && !d.isDegraded(service.Name()) {
+			drainers = append(drainers, service)
+		}
+	}
+	if len(drainers) == 0 {
+		return
+	}
+
+	timeout := d.drainTimeoutOrDefault()
+	l.Info("framework drain start", zap.Int("services", len(drainers)), zap.Duration("timeout", timeout))
+	drainCtx, cancel := clock.WithTimeout(ctx, d.Clock(), timeout)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	for _, service := range drainers {
+		wg.Add(1)
+		go func(s kernel.Service) {
+			defer wg.Done()
+			start := d.Clock().Now()
+			err := s.(kernel.Drainer).Drain(d.withServiceLogger(drainCtx, s))
+			fields := []zap.Field{
+				zap.String("service", s.Name()),
+				zap.Duration("duration", d.Clock().Now().Sub(start)),
+			}
+			if err != nil {
+				l.Error("service drain failed", append(fields, zap.Error(err))...)
+				return
+			}
+			l.Info("service drained", fields...)
+		}(service)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		l.Info("framework drain complete")
+	case <-drainCtx.Done():
+		l.Warn("framework drain timeout, continue to close services",
+			zap.Duration("timeout", timeout),
+			zap.Error(drainCtx.Err()),
+		)
+	}
+}
@@ -0,0 +1,111 @@
+package drugo
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/qq1060656096/drugo/kernel"
+	"github.com/qq1060656096/drugo/kernel/kerneltest"
+	"github.com/qq1060656096/drugo/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// drainMockService 是一个实现了 kernel.Drainer 的模拟服务，记录 Drain 与 Close 的顺序
+type drainMockService struct {
+	name       string
+	recorder   *orderRecorder
+	drainDelay time.Duration
+}
+
+func (s *drainMockService) Name() string                   { return s.name }
+func (s *drainMockService) Boot(ctx context.Context) error { return nil }
+
+func (s *drainMockService) Drain(ctx context.Context) error {
+	s.recorder.add("drain:" + s.name)
+	select {
+	case <-time.After(s.drainDelay):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (s *drainMockService) Close(ctx context.Context) error {
+	s.recorder.add("close:" + s.name)
+	return nil
+}
+
+// newFileTestLogManager 创建一个写入临时目录的日志管理器，返回日志目录
+func newFileTestLogManager(t *testing.T) (*log.Manager, string) {
+	t.Helper()
+	dir := t.TempDir()
+	m, err := log.NewManager(log.Config{
+		Level: "info",
+		Outputs: []log.OutputConfig{
+			{Type: log.OutputTypeFile, Format: log.FormatJSON, File: &log.FileOutputConfig{Dir: dir}},
+		},
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = m.Close() })
+	return m, dir
+}
+
+// TestDrugo_Shutdown_DrainBeforeClose 测试先排空所有 Drainer 再逆序关闭
+func TestDrugo_Shutdown_DrainBeforeClose(t *testing.T) {
+	rec := &orderRecorder{}
+	http := &drainMockService{name: "http", recorder: rec, drainDelay: 20 * time.Millisecond}
+	grpc := &drainMockService{name: "grpc", recorder: rec}
+	plain := kerneltest.NewServiceMock("db")
+
+	app := New(WithService(plain), WithService(http), WithService(grpc))
+	app.logger = newTestLogManager(t)
+
+	require.NoError(t, app.Shutdown(context.Background()))
+
+	events := rec.list()
+	require.Len(t, events, 4)
+	assert.ElementsMatch(t, []string{"drain:http", "drain:grpc"}, events[:2])
+	assert.Equal(t, []string{"close:grpc", "close:http"}, events[2:])
+	assert.True(t, plain.Closed())
+}
+
+// TestDrugo_Shutdown_DrainTimeout 测试排空超时后仍然关闭服务并记录超时日志
+func TestDrugo_Shutdown_DrainTimeout(t *testing.T) {
+	rec := &orderRecorder{}
+	slow := &drainMockService{name: "http", recorder: rec, drainDelay: time.Hour}
+
+	c := kernel.NewFakeClock(time.Time{})
+	app := New(WithService(slow), WithDrainTimeout(time.Minute), WithClock(c))
+	logger, dir := newFileTestLogManager(t)
+	app.logger = logger
+
+	done := make(chan error, 1)
+	go func() { done <- app.Shutdown(context.Background()) }()
+	c.BlockUntilWaiters(1) // 排空超时的定时器
+	require.Eventually(t, func() bool { return len(rec.list()) == 1 }, time.Second, time.Millisecond, "Drain started")
+	c.Advance(time.Minute)
+	require.NoError(t, <-done)
+	assert.Equal(t, []string{"drain:http", "close:http"}, rec.list())
+
+	// 超时的 Drain 协程在 Shutdown 返回后才记录失败日志，等它写完再清理临时目录
+	require.Eventually(t, func() bool {
+		_ = logger.Sync()
+		content, _ := os.ReadFile(filepath.Join(dir, logName+".log"))
+		return strings.Contains(string(content), "service drain failed")
+	}, time.Second, 5*time.Millisecond)
+	content, err := os.ReadFile(filepath.Join(dir, logName+".log"))
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "framework drain timeout")
+}
+
+// TestDrugo_DrainTimeoutDefault 测试排空超时默认为停机超时的一半
+func TestDrugo_DrainTimeoutDefault(t *testing.T) {
+	assert.Equal(t, DefaultShutdownTimeout/2, New().drainTimeoutOrDefault())
+	assert.Equal(t, 15*time.Second, New(WithShutdownTimeout(30*time.Second)).drainTimeoutOrDefault())
+	assert.Equal(t, time.Second, New(WithDrainTimeout(time.Second)).drainTimeoutOrDefault())
+}
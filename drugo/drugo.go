@@ -2,22 +2,25 @@ package drugo
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"os"
-	"os/signal"
 	"path/filepath"
+	"reflect"
+	"strconv"
 	"strings"
-	"syscall"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/qq1060656096/drugo/config"
 	"github.com/qq1060656096/drugo/kernel"
 	"github.com/qq1060656096/drugo/log"
+	"github.com/qq1060656096/drugo/pkg/clock"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
-	"golang.org/x/sync/errgroup"
 )
 
 // 框架元数据
@@ -26,18 +29,130 @@ const (
 	logName = "drugo"
 )
 
-var _ kernel.Kernel = (*Drugo)(nil)
+// EnvVar 是选择应用运行环境的环境变量名称
+const EnvVar = "DRUGO_ENV"
+
+// envConfigKey 是没有通过 WithAppEnv 或 EnvVar 选择运行环境时，基础配置中选择运行环境的配置项
+const envConfigKey = "app.env"
+
+// QuietEnvVar 是开启安静模式的环境变量，取值按 strconv.ParseBool 解析，见 WithQuiet
+const QuietEnvVar = "DRUGO_QUIET"
+
+// LogEnvPrefix 是 MustNewApp 覆盖日志配置的环境变量前缀，例如 DRUGO_LOG_LEVEL=debug，见 log.Config.ApplyEnvOverrides
+const LogEnvPrefix = "DRUGO_LOG"
+
+// MaxBootPasses 是 Boot 初始化动态注册服务的最大轮数
+const MaxBootPasses = 16
+
+var (
+	_ kernel.Kernel     = (*Drugo)(nil)
+	_ kernel.BootWaiter = (*Drugo)(nil)
+)
 
 // Drugo 是框架的核心引擎结构体
 // 它负责管理服务容器、上下文、配置以及日志系统
 type Drugo struct {
-	container       kernel.Container[kernel.Service]
-	root            string
-	ctx             context.Context
-	config          *config.Manager
-	logger          *log.Manager
-	shutdownTimeout time.Duration
-	configDir       string
+	container        kernel.Container[kernel.Service]
+	root             string
+	ctx              context.Context
+	appCtx           context.Context
+	appCancel        context.CancelFunc
+	config           *config.Manager
+	logger           *log.Manager
+	shutdownTimeout  time.Duration
+	clock            kernel.Clock // 为 nil 时使用 kernel.RealClock，见 WithClock
+	configDir        string
+	optional         map[string]struct{}
+	configSections   map[string]string
+	signalHandlers   map[os.Signal][]SignalHandler
+	shutdownSignals  []os.Signal
+	disableSignals   bool
+	stopCh           chan struct{}
+	stopOnce         sync.Once
+	errorHandler     ErrorHandler
+	runRetryBackoff  time.Duration // 见 WithRunRetry
+	runRetryMaxDelay time.Duration
+	runRetryAttempts int
+	providers        map[string]struct{} // 通过 WithRegisteredProviders 选用的 provider
+	strictNames      bool
+	injector         *injector                      // 尚未调用的 WithProvider 构造函数，见 MustNewApp
+	provided         map[reflect.Type]reflect.Value // WithProvider 构造函数返回的值，见 Provided
+	providerDeps     map[string][]string            // WithProvider 创建的服务通过构造函数参数依赖的服务名称，见 shutdownOrder
+	failMu           sync.Mutex
+	failErr          error // 由错误处理函数升级、需要由 Serve 返回的错误
+	commands         TypedContainer[*command]
+	stdout           io.Writer
+	appEnv           string
+	drainTimeout     time.Duration
+	bootReportFile   string
+	logConfig        log.Config
+	logSources       map[string]string // 日志配置项的来源，见 log.EnvOverrides.Sources
+	allowEmptyConf   bool
+	probeClaims      bool
+	pidFile          string   // 见 WithPIDFile
+	pidLock          *os.File // Serve 持有的 PID 文件，Shutdown 的最后阶段释放
+
+	// 启动进度相关字段，见 WithBootProgress 与 BootProgress
+	bootProgress         bool
+	bootProgressEvery    int
+	bootProgressInterval time.Duration
+	progressMu           sync.Mutex
+	progress             bootProgress
+
+	// 配置项读取跟踪，见 WithConfigUsageTracking
+	configUsage       bool
+	configUsageIgnore []string
+
+	// 没有注册任何服务时的处理策略，见 WithEmptyContainerPolicy
+	emptyContainerPolicy EmptyContainerPolicy
+
+	// 退出码相关字段，见 ExitCode
+	exitCodes map[Outcome]int
+	outcomeMu sync.Mutex
+	outcome   Outcome
+
+	// 安静模式相关字段
+	quiet            bool
+	quietSet         bool
+	quietConsoleOnly bool
+
+	// 诊断采集相关字段
+	diagnosticsDir       string
+	diagnosticsCPU       time.Duration
+	diagnosticsRetention time.Duration
+	diagnosing           atomic.Bool
+
+	// 框架 logger 相关字段
+	frameworkLogName  string
+	frameworkLogLevel string
+	fwLogMu           sync.Mutex
+	fwFallback        *zap.Logger
+	fwLevelApplied    *log.Manager
+
+	// started 保证 Start/Serve 只执行一次
+	started atomic.Bool
+
+	statusMu      sync.RWMutex
+	status        map[string]ServiceStatus
+	statusChanged chan struct{} // 状态变化时关闭，见 WaitBooted
+
+	// Runner 管理相关字段，见 StopRunner 与 StartRunner
+	runMu     sync.Mutex
+	runCtx    context.Context
+	runEvents chan runnerExit
+	runners   map[string]*runnerHandle
+	runActive int
+
+	reportMu        sync.RWMutex
+	report          *BootReport
+	bootFailure     *BootFailureReport // 见 LastBootFailure
+	bootFailureFile string             // 写入的启动失败报告文件路径
+	shutdown        *ShutdownSummary   // 见 LastShutdown
+
+	// 启动耗时相关字段，见 Timings
+	timingsMu sync.Mutex
+	timings   StartupTimings
+	bootDone  time.Time
 }
 
 // ResolveDir 根据 root、dir 和默认子目录 defaultSubdir 解析最终目录路径。
@@ -72,81 +187,206 @@ func (d *Drugo) Root() string {
 
 // Boot 初始化所有已注册的服务
 // 按照服务注册的顺序调用它们的 Boot 方法
+//
+// 服务在 Boot 中通过 Container().Bind 动态注册的新服务会在后续轮次中继续被初始化，
+// 直到没有新服务加入为止；超过 MaxBootPasses 轮仍有新服务加入时返回 ErrBootPassLimit，
+// 用于防止服务之间循环注册。注意：Boot 期间覆盖已初始化服务的同名实例不会再次初始化。
+//
+// 服务 Boot 失败时返回由 kernel.WrapServiceInitFailed 包装的错误，
+// 可以通过 kernel.IsServiceInitFailed 判断，并通过 errors.Is 匹配服务返回的原始错误。
+// 初始化任何服务之前会检查服务之间的能力依赖（见 CheckCapabilities），不满足时返回包装了 ErrCapabilityMissing 的错误
 func (d *Drugo) Boot(ctx context.Context) error {
-	services := d.Container().Services()
-	l := d.Logger().MustGet(logName)
+	l := d.frameworkLogger()
 
 	l.Info("framework boot start", zap.String("app", Name))
 	l.Info("framework boot start services names " + strings.Join(d.serviceNames(), ","))
 
-	if len(services) == 0 {
-		l.Warn("no services registered to boot")
+	if err := d.CheckCapabilities(); err != nil {
+		l.Error("service capability check failed", zap.Error(err))
+		d.captureBootFailure(l, nil, err)
+		return err
+	}
+
+	start := d.Clock().Now()
+	d.recordTiming(func(t *StartupTimings) { t.Services = nil })
+	if len(d.Container().Services()) == 0 {
+		if d.warnEmptyContainer() {
+			l.Warn("no services registered to boot")
+		}
+		d.finishBootTimings(l, start)
+		d.captureBootReport(l)
 		return nil
 	}
 
-	ctx = kernel.WithContext(ctx, d)
-	for i := range services {
-		service := services[i]
-		// 动态变量作为 Field 传入，而非拼接字符串
-		l.Info("service booting", zap.String("service", service.Name()))
+	ctx = d.bootContext(kernel.WithContext(ctx, d))
+	progress := d.startBootProgress(l, len(d.Container().Services()))
+	defer progress.stop()
+	booted := 0
+	for pass := 1; ; pass++ {
+		// 每一轮重新获取服务快照，包含上一轮中动态注册的服务
+		services := d.Container().Services()
+		if booted >= len(services) {
+			break
+		}
+		if pass > MaxBootPasses {
+			progress.stop()
+			err := fmt.Errorf("%w: %d passes, pending services: %s",
+				ErrBootPassLimit, MaxBootPasses, strings.Join(d.serviceNames()[booted:], ","))
+			l.Error("service boot failed", zap.Error(err))
+			d.captureBootFailure(l, nil, err)
+			return err
+		}
+		if pass > 1 {
+			l.Info("booting dynamically registered services", zap.Int("pass", pass))
+			// Boot 中通过 Container().Bind 注册的服务没有经过 NewE 的名称检查
+			if err := d.checkBoundNames(l, d.Container().Names()[booted:len(services)]); err != nil {
+				progress.stop()
+				l.Error("service boot failed", zap.Error(err))
+				d.captureBootFailure(l, nil, err)
+				return err
+			}
+		}
+		d.updateBootProgress(func(p *bootProgress) { p.total = len(services) })
+
+		// 本轮待初始化的服务按依赖关系排序，被依赖的服务先初始化
+		for _, service := range d.bootOrder(l, services[booted:]) {
+			name := service.Name()
+			d.updateBootProgress(func(p *bootProgress) { p.current = name })
+			if err := d.bootService(ctx, l, progress, service); err != nil {
+				d.captureBootFailure(l, service, err)
+				return err
+			}
+			d.updateBootProgress(func(p *bootProgress) { p.done, p.current = p.done+1, "" })
+			progress.serviceDone()
+		}
+		booted = len(services)
+	}
+	progress.stop()
+	l.Info("framework boot complete", zap.Strings("degraded", d.Degraded()))
+	d.finishBootTimings(l, start)
+	d.captureBootReport(l)
+	return nil
+}
+
+// checkBoundNames 检查 Boot 期间动态注册的服务名称，严格模式下返回合并的错误，否则只记录警告
+func (d *Drugo) checkBoundNames(l *zap.Logger, names []string) error {
+	var errs []error
+	for _, name := range names {
+		if err := checkServiceName(name, d.frameworkLogName); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if d.strictNames {
+		return errors.Join(errs...)
+	}
+	for _, err := range errs {
+		l.Warn("invalid service name", zap.Error(err))
+	}
+	return nil
+}
+
+// finishBootTimings 记录 Boot 的总耗时与完成时间，并输出耗时摘要
+func (d *Drugo) finishBootTimings(l *zap.Logger, start time.Time) {
+	now := d.Clock().Now()
+	d.timingsMu.Lock()
+	d.timings.Boot = now.Sub(start)
+	d.bootDone = now
+	d.timingsMu.Unlock()
+	d.logTimings(l)
+}
 
-		if err := service.Boot(ctx); err != nil {
-			l.Error("service boot failed",
+// bootService 注入 logger 与配置并初始化单个服务，可选服务失败时标记为降级并返回 nil。
+// 服务失败导致 Boot 失败时，先停止进度日志再输出失败信息
+func (d *Drugo) bootService(ctx context.Context, l *zap.Logger, progress *progressReporter, service kernel.Service) error {
+	// 动态变量作为 Field 传入，而非拼接字符串
+	l.Info("service booting", zap.String("service", service.Name()))
+
+	start := d.Clock().Now()
+	ctx, err := d.injectServiceLogger(ctx, service)
+	if err == nil {
+		err = d.configureService(service)
+	}
+	if err == nil {
+		err = d.callRecovered(PhaseBoot, service.Name(), func() error { return service.Boot(ctx) })
+	}
+	elapsed := d.Clock().Now().Sub(start)
+	d.recordTiming(func(t *StartupTimings) {
+		t.Services = append(t.Services, ServiceTiming{Name: service.Name(), Boot: elapsed})
+	})
+	if err != nil {
+		continueBoot := d.isOptional(service)
+		wrapped := kernel.WrapServiceInitFailed(service.Name(), err)
+		switch d.handleError(PhaseBoot, service.Name(), wrapped) {
+		case DecisionContinue:
+			continueBoot = true
+		case DecisionFail, DecisionRetry:
+			continueBoot = false
+		}
+		if continueBoot {
+			l.Warn("optional service boot failed, continue without it",
 				zap.String("service", service.Name()),
 				zap.Error(err),
 			)
-			return err
+			d.setStatus(service.Name(), ServiceStateDegraded, err)
+			return nil
 		}
+		progress.stop()
+		l.Error("service boot failed",
+			zap.String("service", service.Name()),
+			zap.Error(err),
+		)
+		return wrapped
 	}
-	l.Info("framework boot complete")
+	d.setStatus(service.Name(), ServiceStateBooted, nil)
+	l.Info("service booted", zap.String("service", service.Name()), zap.Duration("elapsed", elapsed))
 	return nil
 }
 
 // Run 启动所有实现了 kernel.Runner 接口的服务
 // 这些服务通常是常驻进程，如 HTTP Server 或消息消费者
+//
+// 每个 Runner 运行在独立的子上下文中，可以通过 StopRunner/StartRunner 单独停止和重新启动；
+// 任一 Runner 主动返回错误时取消所有 Runner 并返回该错误（由 kernel.WrapServiceRunFailed 包装）
+//
+// 启动任何 Runner 之前会检查服务的资源声明（见 kernel.ResourceClaimer），
+// 存在冲突时直接返回 ErrClaimConflict，不会启动任何 Runner
 func (d *Drugo) Run(ctx context.Context) error {
 	services := d.Container().Services()
-	l := d.Logger().MustGet(logName)
+	l := d.frameworkLogger()
 
 	l.Info("framework run start")
 
+	if err := d.checkClaims(); err != nil {
+		l.Error("resource claim check failed", zap.Error(err))
+		return err
+	}
+
 	if len(services) == 0 {
-		l.Warn("no services to run")
+		if d.warnEmptyContainer() {
+			l.Warn("no services to run")
+		}
 		return nil
 	}
 
-	runnerCount := 0
-	ctx = kernel.WithContext(ctx, d)
-	g, ctx := errgroup.WithContext(ctx)
-
-	for i := range services {
-		service := services[i]
+	var runners []*runnerHandle
+	for _, service := range services {
 		runner, ok := service.(kernel.Runner)
 		if !ok {
 			continue
 		}
-		runnerCount++
-
-		// 闭包捕获
-		r := runner
-		s := service
-		g.Go(func() error {
-			if err := r.Run(ctx); err != nil {
-				l.Error("service run failed",
-					zap.String("service", s.Name()),
-					zap.Error(err),
-				)
-				return err
-			}
-			return nil
-		})
+		// 降级的可选服务不参与运行
+		if d.isDegraded(service.Name()) {
+			l.Warn("skip degraded service run", zap.String("service", service.Name()))
+			continue
+		}
+		runners = append(runners, &runnerHandle{service: service, runner: runner})
 	}
 
-	if runnerCount < 1 {
+	if len(runners) < 1 {
 		l.Warn("no runner services identified")
 	}
 
-	if err := g.Wait(); err != nil {
+	if err := d.superviseRunners(kernel.WithContext(ctx, d), l, runners); err != nil {
 		l.Error("framework run interrupted by error", zap.Error(err))
 		return err
 	}
@@ -156,105 +396,219 @@ func (d *Drugo) Run(ctx context.Context) error {
 }
 
 // Shutdown 优雅地关闭所有服务
-// 会在指定的上下文超时时间内尝试调用所有服务的 Close 方法
+// 首先取消应用级上下文（见 AppContext），使服务在 Boot 中启动的后台 goroutine 在关闭期间退出；
+// 然后并发调用所有 kernel.Drainer 服务的 Drain（受排空超时控制），
+// 之后在指定的上下文超时时间内逆序调用所有服务的 Close 方法：依赖方（见 kernel.Dependent）先于被依赖的服务关闭，
+// 不受依赖关系约束的服务按注册顺序的逆序关闭，依赖关系存在循环时输出警告并退回注册顺序的逆序；
+// 最后使用剩余的超时时间刷新所有日志输出（见 log.Manager.Flush）
+//
+// Close 失败时继续关闭其余服务，最后返回所有失败合并（errors.Join）后的错误，
+// 每个错误都由 kernel.WrapServiceCloseFailed 包装，可以通过 kernel.IsServiceCloseFailed 判断；
+// 错误处理函数（见 WithErrorHandler）返回 DecisionContinue 的错误只记录日志，不会返回
 func (d *Drugo) Shutdown(ctx context.Context) error {
 	services := d.Container().Services()
-	l := d.Logger().MustGet(logName)
+	l := d.frameworkLogger()
 
 	l.Info("framework shutdown start")
+	d.appCancel()
 
 	if len(services) == 0 {
+		d.releasePIDFile(l)
+		d.flushLogs(ctx, l)
 		return nil
 	}
 
 	ctx = kernel.WithContext(ctx, d)
-	// 逆序关闭服务
-	for i := len(services) - 1; i >= 0; i-- {
-		service := services[i]
+	// 第一阶段：排空实现了 kernel.Drainer 的服务
+	d.drain(ctx, l, services)
+
+	// 第二阶段：按依赖关系的逆序关闭服务，返回 kernel.ErrCloseRetryable 的服务在本轮结束后退避重试
+	services = d.shutdownOrder(l, services)
+	l.Debug("framework shutdown order", zap.Strings("services", serviceNamesOf(services)))
+	var (
+		failed  []error
+		closes  []*serviceClose
+		pending []*serviceClose
+	)
+	for _, service := range services {
+		// 降级的可选服务未完成初始化，无需关闭
+		if d.isDegraded(service.Name()) {
+			continue
+		}
 		l.Info("service shutting down", zap.String("service", service.Name()))
 
-		if err := service.Close(ctx); err != nil {
-			l.Error("service shutdown failed",
-				zap.String("service", service.Name()),
-				zap.Error(err),
-			)
-			// 继续尝试关闭其他服务，不应立即退出
+		c := &serviceClose{ServiceShutdown: ServiceShutdown{Name: service.Name()}, service: service}
+		closes = append(closes, c)
+		if err := d.closeService(ctx, c); err != nil && kernel.IsCloseRetryable(err) {
+			l.Warn("service shutdown retryable", zap.String("service", c.Name), zap.Error(err))
+			pending = append(pending, c)
+			continue
+		}
+		// 继续尝试关闭其他服务，不应立即退出
+		if err := d.finishClose(l, c); err != nil {
+			failed = append(failed, err)
+		}
+	}
+	d.retryClose(ctx, l, pending)
+	for _, c := range pending {
+		if err := d.finishClose(l, c); err != nil {
+			failed = append(failed, err)
 		}
 	}
+	d.recordShutdown(closes)
 	l.Info("framework shutdown complete")
-	return nil
+
+	// 第三阶段：删除 Serve 写入的 PID 文件（见 WithPIDFile），
+	// 并在剩余的停机时间内刷新所有日志输出，保证退出前的日志已经落盘
+	d.releasePIDFile(l)
+	d.flushLogs(ctx, l)
+	return errors.Join(failed...)
+}
+
+// flushLogs 刷新所有日志输出，失败时只记录日志
+func (d *Drugo) flushLogs(ctx context.Context, l *zap.Logger) {
+	if err := d.Logger().Flush(ctx); err != nil {
+		l.Warn("log flush failed", zap.Error(err))
+	}
 }
 
 // Serve 是框架的启动入口
-// 它封装了 Boot、Run 以及信号监听逻辑，实现了优雅停机
+// 它在 Start 的基础上增加了信号监听逻辑，实现了优雅停机
 //
 // 执行流程：
-//  1. Boot
-//  2. Run（异步）
-//  3. 监听系统信号
-//  4. Shutdown（带超时）
+//  1. 获取 PID 文件（仅在使用 WithPIDFile 时，被其他实例持有时返回 ErrPIDFileLocked）
+//  2. Boot
+//  3. Run（异步）
+//  4. 监听系统信号（默认 SIGINT/SIGTERM，见 WithShutdownSignals）
+//  5. Shutdown（带超时，最后阶段删除 PID 文件）
+//
+// 以下任一情况都会触发停机：收到停机信号、ctx 被取消、调用 Stop、Run 结束（例如没有 Runner 服务）。
+// 使用 WithDisableSignals 时不注册任何信号，由宿主进程负责信号处理
+//
+// 返回值：Boot 失败时返回 Boot 的错误（kernel.ErrServiceInitFailed）；
+// 否则返回 Run 的错误（kernel.ErrServiceRunFailed）与 Shutdown 的错误（kernel.ErrServiceCloseFailed），
+// 两者都存在时以 errors.Join 合并，Shutdown 仍会关闭所有服务。
+// 返回前记录生命周期结果，main 函数可以通过 ExitCode 或 Exit 以对应的退出码退出
 func (d *Drugo) Serve(ctx context.Context) error {
-	l := d.Logger().MustGet(logName)
+	l := d.frameworkLogger()
 
-	l.Info("app starting",
-		zap.String("name", Name),
-		zap.String("version", Version()),
-	)
+	// 重复调用时由 Start 返回 ErrAlreadyStarted，不再尝试获取已经由本实例持有的 PID 文件
+	if !d.started.Load() {
+		if err := d.acquirePIDFile(l); err != nil {
+			d.setOutcome(OutcomeBootFailed)
+			l.Error("app start failed", zap.String("pid_file", d.PIDFile()), zap.Error(err))
+			return err
+		}
+	}
 
-	if err := d.Boot(ctx); err != nil {
+	h, err := d.Start(ctx)
+	if err != nil {
+		if !errors.Is(err, ErrAlreadyStarted) {
+			d.releasePIDFile(l)
+			d.setOutcome(OutcomeBootFailed)
+			if path := d.bootFailurePath(); path != "" {
+				l.Error("app boot failed", zap.String("boot_failure_report", path), zap.Error(err))
+			} else {
+				l.Error("app boot failed", zap.Error(err))
+			}
+		}
 		return err
 	}
 
-	quit := make(chan os.Signal, 1)
-	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
-	defer signal.Stop(quit)
+	// 作为 Windows 服务运行时由服务控制管理器的停止请求触发停机，其他平台不做任何事
+	defer d.watchPlatformStop(h)()
 
-	errChan := make(chan error, 1)
-	runCtx, cancelRun := context.WithCancel(ctx)
-	defer cancelRun()
-	go func() {
-		// 无论 Run 成功/失败都要通知主流程（特别是没有 Runner 服务时）
-		errChan <- d.Run(runCtx)
-	}()
+	// 禁用信号处理时不注册任何信号，停机只由 ctx 取消、Run 结束或 Drugo.Stop 触发
+	quit := make(chan os.Signal, 1)
+	custom := make(chan os.Signal, 1)
+	if d.disableSignals {
+		if len(d.signalHandlers) > 0 {
+			l.Warn("signals disabled, custom signal handlers are ignored")
+		}
+	} else {
+		signalNotify(quit, d.shutdownSignalsOrDefault()...)
+		defer signalStop(quit)
+
+		// 自定义信号处理（例如 SIGUSR1 轮转日志），不会触发停机
+		if sigs := d.signals(); len(sigs) > 0 {
+			signalNotify(custom, sigs...)
+			defer signalStop(custom)
+		}
+	}
 
 	var runErr error
-	select {
-	case err := <-errChan:
-		// Run 可能立即返回（例如没有 Runner 服务），此时应当进入 Shutdown 并正常退出
-		runErr = err
-		if runErr != nil {
-			l.Error("app exit with error", zap.Error(runErr))
-		} else {
-			l.Info("app run complete, initiating shutdown")
+wait:
+	for {
+		select {
+		case <-h.Done():
+			// Run 可能立即返回（例如没有 Runner 服务），此时应当进入 Shutdown 并正常退出
+			runErr = h.Err()
+			if runErr != nil {
+				l.Error("app exit with error", zap.Error(runErr))
+			} else {
+				l.Info("app run complete, initiating shutdown")
+			}
+			break wait
+		case sig := <-quit:
+			l.Info("receive signal, initiating graceful shutdown",
+				zap.String("signal", sig.String()),
+			)
+			break wait
+		case <-ctx.Done():
+			l.Info("context canceled, initiating graceful shutdown")
+			break wait
+		case <-d.stopCh:
+			l.Info("stop requested, initiating graceful shutdown")
+			break wait
+		case sig := <-custom:
+			d.handleSignal(h.ctx, sig)
 		}
-	case sig := <-quit:
-		l.Info("receive signal, initiating graceful shutdown",
-			zap.String("signal", sig.String()),
-		)
-		// 通知所有 Runner 尽快退出
-		cancelRun()
 	}
 
-	// 优雅停机超时控制
-	timeout := d.shutdownTimeout
-	if timeout <= 0 {
-		timeout = DefaultShutdownTimeout
+	// ctx 可能已经被取消，停机仍然需要在停机超时时间内完成
+	stopErr := h.Stop(context.WithoutCancel(ctx))
+	if runErr == nil {
+		runErr = d.failure()
+	}
+	// Run 与 Shutdown 都失败时合并两者，Run 的错误在前
+	switch {
+	case runErr == nil:
+		runErr = stopErr
+	case stopErr != nil:
+		runErr = errors.Join(runErr, stopErr)
+	}
+	switch {
+	case h.timedOut.Load():
+		d.setOutcome(OutcomeShutdownTimeout)
+	case runErr != nil:
+		d.setOutcome(OutcomeError)
+	default:
+		d.setOutcome(OutcomeClean)
+		l.Info("app exit successfully")
 	}
-	l.Info("initiating shutdown with timeout", zap.Duration("timeout", timeout))
-	timeoutCtx, cancel := context.WithTimeout(ctx, timeout)
-	defer cancel()
+	return runErr
+}
 
-	if err := d.Shutdown(timeoutCtx); err != nil {
-		l.Error("app shutdown failed", zap.Error(err))
-		// 如果 Run 已经报错，优先返回 Run 的错误；否则返回 Shutdown 错误
-		if runErr != nil {
-			return runErr
-		}
-		return err
+// resolveEnv 返回通过 WithAppEnv 或环境变量 DRUGO_ENV 选择的运行环境，WithAppEnv 优先；
+// 两者都没有设置时返回空字符串，由配置管理器读取基础配置中的 app.env（见 MustNewApp）
+func (d *Drugo) resolveEnv() string {
+	if d.appEnv != "" {
+		return d.appEnv
 	}
+	return os.Getenv(EnvVar)
+}
 
-	l.Info("app exit successfully")
-	return runErr
+// Clock 返回框架使用的时钟，未通过 WithClock 设置时返回 kernel.RealClock
+func (d *Drugo) Clock() kernel.Clock {
+	return clock.OrReal(d.clock)
+}
+
+// shutdownTimeoutOrDefault 返回优雅停机的超时时间，未设置时使用 DefaultShutdownTimeout
+func (d *Drugo) shutdownTimeoutOrDefault() time.Duration {
+	if d.shutdownTimeout <= 0 {
+		return DefaultShutdownTimeout
+	}
+	return d.shutdownTimeout
 }
 
 // Config 获取配置管理器
@@ -299,34 +653,67 @@ func (d *Drugo) serviceNames() []string {
 }
 
 // MustNewApp 快速创建一个预集成了默认服务（HTTP, Demo）的 Drugo 应用
-// 如果初始化失败会 panic
+// 如果初始化失败会 panic，panic 的值是 *StartupError，列出出错的配置文件、原因和修复提示
+// 默认严格检查服务名称（见 WithStrictNames），可以使用 WithStrictNames(false) 关闭；
+// 没有注册任何服务时 Start/Serve 默认返回 ErrEmptyContainer（见 WithEmptyContainerPolicy）
 //
 // 会自动注册：
 //   - Config
 //   - Logger
 func MustNewApp(opts ...Option) *Drugo {
-	app := New(opts...)
+	app := New(append([]Option{WithStrictNames(true), WithEmptyContainerPolicy(EmptyContainerFail), withDeferredProviders()}, opts...)...)
+	if !app.quietSet {
+		app.quiet, _ = strconv.ParseBool(os.Getenv(QuietEnvVar))
+	}
 
 	// 设置配置文件目录
+	// 没有任何配置的应用几乎一定是部署错误（例如 conf/ 挂载失败），默认直接失败
 	configDir := app.ConfigDir()
-	app.config = config.MustNewManager(configDir)
+	configStart := app.Clock().Now()
+	var configOpts []config.Option
+	if !app.allowEmptyConf {
+		configOpts = append(configOpts, config.WithRequireNonEmpty())
+	}
+	if app.clock != nil {
+		configOpts = append(configOpts, config.WithClock(app.clock))
+	}
+	if app.configUsage {
+		configOpts = append(configOpts, config.WithUsageTracking(app.configUsageIgnore...))
+	}
+	// 在基础配置之上叠加 conf/<env> 环境层：没有通过 WithAppEnv 或 DRUGO_ENV 选择运行环境时，
+	// 由配置管理器在同一次加载中读取基础配置的 app.env
+	if env := app.resolveEnv(); env != "" {
+		configOpts = append(configOpts, config.WithEnvironment(env, ""))
+	} else {
+		configOpts = append(configOpts, config.WithEnvironmentKey(envConfigKey, ""))
+	}
+	var err error
+	app.config, err = config.NewManager(configDir, configOpts...)
+	if err != nil {
+		panic(&StartupError{stage: "config", dir: configDir, err: err})
+	}
+	app.timings.Config = app.Clock().Now().Sub(configStart)
 
 	// 初始化日志系统 (默认路径: project_root/runtime/logs)
+	logStart := app.Clock().Now()
 	logConfigDir := filepath.Join(app.Root(), "runtime/logs")
 	logCfg := log.Config{}
 
-	// 尝试从配置文件加载日志配置
-	if logConfig, err := app.Config().Get("log"); err == nil {
-		if err := logConfig.Unmarshal(&logCfg); err != nil {
+	// 尝试从配置文件加载日志配置，通过 Manager 解码以便 WithConfigUsageTracking 记录读取
+	if _, err := app.Config().Get("log"); err == nil {
+		if err := app.Config().Unmarshal("log", &logCfg); err != nil {
 			fmt.Fprintf(os.Stderr, "drugo: failed to unmarshal log config: %v\n", err)
 		}
 	}
-	if len(logCfg.Outputs) == 0 {
+	if len(logCfg.Outputs) == 0 && !app.quiet {
 		fmt.Fprintf(os.Stderr, "drugo: log.outputs is empty, fallback to default file logger\n")
 	}
 	if logCfg.Level == "" {
 		logCfg.Level = "info"
 	}
+	if app.quiet && app.quietConsoleOnly {
+		logCfg.Outputs = consoleOutputs(logCfg.Outputs)
+	}
 	if len(logCfg.Outputs) == 0 {
 		logCfg.Outputs = []log.OutputConfig{
 			{
@@ -338,6 +725,9 @@ func MustNewApp(opts ...Option) *Drugo {
 			},
 		}
 	}
+	// 环境变量覆盖配置文件中的日志配置，用于不修改镜像中的配置文件临时调整日志，无效的值只记录警告
+	logOverrides := logCfg.ApplyEnvOverrides(LogEnvPrefix)
+	app.logSources = logOverrides.Sources()
 	for i := range logCfg.Outputs {
 		out := &logCfg.Outputs[i]
 		if out.Type == "file" {
@@ -351,31 +741,91 @@ func MustNewApp(opts ...Option) *Drugo {
 			}
 		}
 	}
+	for i := range logCfg.Routes {
+		if r := &logCfg.Routes[i]; r.Dir != "" {
+			r.Dir = ResolveDir(app.Root(), r.Dir, "runtime/logs")
+		}
+	}
 
-	var err error
+	logCfg.CrashDir = ResolveDir(app.Root(), logCfg.CrashDir, "runtime")
+	if app.clock != nil {
+		logCfg.Clock = app.clock
+	}
+	app.logConfig = logCfg
 	app.logger, err = log.NewManager(logCfg)
 	if err != nil {
-		panic(err) // NewApp 不返回 error，配置错误时 panic
+		panic(&StartupError{stage: "log", dir: configDir, err: err}) // MustNewApp 不返回 error，配置错误时 panic
 	}
+	app.timings.Log = app.Clock().Now().Sub(logStart)
 	// 将 gin 的默认输出重定向到 zap，避免 Gin 的 [GIN-debug] 日志只打印到控制台。
 	// 注意：这里使用独立的 bizName=gin，日志会写入 gin.log（取决于 log.outputs 的 file 配置）。
 	ginLogger := app.Logger().MustGet("gin")
 	gin.DefaultWriter = io.MultiWriter(gin.DefaultWriter, log.NewWriter(ginLogger, zapcore.InfoLevel))
 	gin.DefaultErrorWriter = io.MultiWriter(gin.DefaultErrorWriter, log.NewWriter(ginLogger, zapcore.ErrorLevel))
 
-	drugoLog := app.Logger().MustGet(logName)
+	// WithProvider 的构造函数可以依赖配置与日志管理器，因此在它们创建之后调用
+	if err := app.injector.invoke(app, app.strictNames); err != nil {
+		panic(err)
+	}
+	app.injector = nil
+
+	drugoLog := app.frameworkLogger()
+	for _, o := range logOverrides.Invalid() {
+		drugoLog.Warn("log env override ignored", zap.String("var", o.Var), zap.String("value", o.Value), zap.Error(o.Err))
+	}
+	if app.quiet {
+		return app
+	}
 	drugoLog.Info("framework init")
 	drugoLog.Info("framework init has service names: " + strings.Join(app.serviceNames(), ", "))
 	drugoLog.Info("framework init has config dir: " + configDir)
+	drugoLog.Info("framework init has config env: " + app.Config().Environment())
 	drugoLog.Info("framework init has log dir: " + logConfigDir)
 	drugoLog.Info("framework init has log config: ", zap.Any("logConfig", logCfg))
+	for _, o := range logOverrides {
+		if o.Err == nil {
+			drugoLog.Info("framework init has log env override", zap.String("var", o.Var), zap.String("value", o.Value))
+		}
+	}
 	drugoLog.Info("framework init has config biz names: " + strings.Join(app.Config().List(), ", "))
 
 	return app
 }
 
+// Quiet 返回是否处于安静模式，见 WithQuiet
+func (d *Drugo) Quiet() bool {
+	return d.quiet
+}
+
+// consoleOutputs 去掉 outputs 中的 file 输出，没有剩余输出时使用文本格式的控制台输出
+func consoleOutputs(outputs []log.OutputConfig) []log.OutputConfig {
+	var result []log.OutputConfig
+	for _, out := range outputs {
+		if out.Type != log.OutputTypeFile {
+			result = append(result, out)
+		}
+	}
+	if len(result) == 0 {
+		result = []log.OutputConfig{{Type: log.OutputTypeConsole, Format: log.FormatText}}
+	}
+	return result
+}
+
 // New 创建一个新的 Drugo 实例
+// 注册服务失败（nil 服务或构造函数返回错误）时 panic，需要处理错误时使用 NewE
 func New(opts ...Option) *Drugo {
+	app, err := NewE(opts...)
+	if err != nil {
+		panic(err)
+	}
+	return app
+}
+
+// NewE 创建一个新的 Drugo 实例。
+// 注册服务时收集的所有错误（nil 服务、WithServiceErr、WithServiceProvider 与 WithRegisteredProviders 的构造错误）
+// 会通过 errors.Join 合并返回，每个错误都标明了对应的服务或 provider；
+// 同时使用 WithDisableSignals 与 WithShutdownSignals 时返回 ErrSignalOptionConflict
+func NewE(opts ...Option) (*Drugo, error) {
 	// 1. 初始化默认选项
 	o := &options{
 		services: make([]map[string]kernel.Service, 0),
@@ -387,22 +837,93 @@ func New(opts ...Option) *Drugo {
 	for _, opt := range opts {
 		opt(o)
 	}
+	// 注册耗时使用 WithClock 设置的时钟，因此在应用选项之后开始计时
+	start := clock.OrReal(o.clock).Now()
+	var nameErrs []error
+	for _, serviceMap := range o.services {
+		for name := range serviceMap {
+			if err := checkServiceName(name, o.frameworkLogName); err != nil {
+				nameErrs = append(nameErrs, err)
+			}
+		}
+	}
+	if o.strictNames {
+		o.serviceErrs = append(o.serviceErrs, nameErrs...)
+	}
+	if o.disableSignals && len(o.shutdownSignals) > 0 {
+		o.serviceErrs = append(o.serviceErrs,
+			fmt.Errorf("%w: WithDisableSignals and WithShutdownSignals cannot be used together", ErrSignalOptionConflict))
+	}
+	inj, injectErrs := newInjector(o.constructors)
+	o.serviceErrs = append(o.serviceErrs, injectErrs...)
+	if len(o.serviceErrs) > 0 {
+		return nil, errors.Join(o.serviceErrs...)
+	}
 
 	// 3. 实例化 Drugo
+	appCtx, appCancel := context.WithCancel(o.ctx)
 	app := &Drugo{
-		root:            o.root,
-		ctx:             o.ctx,
-		container:       NewContainer[kernel.Service](),
-		shutdownTimeout: o.shutdownTimeout,
-		configDir:       o.configDir,
+		root:                 o.root,
+		ctx:                  o.ctx,
+		appCtx:               appCtx,
+		appCancel:            appCancel,
+		container:            NewContainer[kernel.Service](),
+		shutdownTimeout:      o.shutdownTimeout,
+		clock:                o.clock,
+		configDir:            o.configDir,
+		optional:             o.optional,
+		configSections:       o.configSections,
+		signalHandlers:       o.signalHandlers,
+		shutdownSignals:      o.shutdownSignals,
+		disableSignals:       o.disableSignals,
+		stopCh:               make(chan struct{}),
+		errorHandler:         o.errorHandler,
+		runRetryBackoff:      o.runRetryBackoff,
+		runRetryMaxDelay:     o.runRetryMaxDelay,
+		runRetryAttempts:     o.runRetryAttempts,
+		stdout:               o.stdout,
+		appEnv:               o.appEnv,
+		drainTimeout:         o.drainTimeout,
+		bootReportFile:       o.bootReportFile,
+		frameworkLogName:     o.frameworkLogName,
+		frameworkLogLevel:    o.frameworkLogLevel,
+		allowEmptyConf:       o.allowEmptyConfig,
+		bootProgress:         o.bootProgress,
+		bootProgressEvery:    o.bootProgressEvery,
+		bootProgressInterval: o.bootProgressInterval,
+		configUsage:          o.configUsage,
+		configUsageIgnore:    o.configUsageIgnore,
+		probeClaims:          o.probeClaims,
+		pidFile:              o.pidFile,
+		quiet:                o.quiet,
+		quietSet:             o.quietSet,
+		quietConsoleOnly:     o.quietConsoleOnly,
+		diagnosticsDir:       o.diagnosticsDir,
+		diagnosticsCPU:       o.diagnosticsCPU,
+		diagnosticsRetention: o.diagnosticsRetention,
+		providers:            o.providers,
+		strictNames:          o.strictNames,
+		emptyContainerPolicy: o.emptyContainerPolicy,
+		exitCodes:            o.exitCodes,
+		status:               make(map[string]ServiceStatus),
 	}
 
-	// 4. 将选项中的服务注册到容器中
+	// 4. 将选项中的服务注册到容器中，非严格模式下名称问题只记录警告
+	for _, err := range nameErrs {
+		app.frameworkLogger().Warn("invalid service name", zap.Error(err))
+	}
 	for _, serviceMap := range o.services {
 		for name, service := range serviceMap {
 			app.Container().Bind(name, service)
 		}
 	}
+	// 5. 调用 WithProvider 注册的构造函数；MustNewApp 在创建配置与日志管理器之后再调用
+	if o.deferProviders {
+		app.injector = inj
+	} else if err := inj.invoke(app, app.strictNames); err != nil {
+		return nil, err
+	}
+	app.timings.Bind = app.Clock().Now().Sub(start)
 
-	return app
+	return app, nil
 }
@@ -2,65 +2,60 @@ package drugo
 
 import (
 	"context"
+	"errors"
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
 	"github.com/qq1060656096/drugo/config"
 	"github.com/qq1060656096/drugo/kernel"
+	"github.com/qq1060656096/drugo/kernel/kerneltest"
 	"github.com/qq1060656096/drugo/log"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
-// mockDrugoService 是一个用于测试框架的模拟服务实现
-type mockDrugoService struct {
-	name        string
-	bootCalled  bool
-	closeCalled bool
-	bootError   error
-	closeError  error
-	bootDelay   time.Duration
-	closeDelay  time.Duration
+// newBootFailingService 返回 Boot 时返回 err 的模拟服务
+func newBootFailingService(name string, err error) *kerneltest.ServiceMock {
+	svc := kerneltest.NewServiceMock(name)
+	svc.BootFunc = kerneltest.ReturnAfter(0, err)
+	return svc
 }
 
-func (m *mockDrugoService) Name() string {
-	return m.name
+// newCloseFailingService 返回 Close 时返回 err 的模拟服务
+func newCloseFailingService(name string, err error) *kerneltest.ServiceMock {
+	svc := kerneltest.NewServiceMock(name)
+	svc.CloseFunc = kerneltest.ReturnAfter(0, err)
+	return svc
 }
 
-func (m *mockDrugoService) Boot(ctx context.Context) error {
-	m.bootCalled = true
-	if m.bootDelay > 0 {
-		time.Sleep(m.bootDelay)
-	}
-	return m.bootError
+// newSlowCloseService 返回 Close 需要 d 才能完成的模拟服务
+func newSlowCloseService(name string, d time.Duration) *kerneltest.ServiceMock {
+	svc := kerneltest.NewServiceMock(name)
+	svc.CloseFunc = kerneltest.ReturnAfter(d, nil)
+	return svc
 }
 
-func (m *mockDrugoService) Close(ctx context.Context) error {
-	m.closeCalled = true
-	if m.closeDelay > 0 {
-		time.Sleep(m.closeDelay)
-	}
-	return m.closeError
+// newBootFailingRunner 返回 Boot 时返回 err 的模拟运行器
+func newBootFailingRunner(name string, err error) *kerneltest.RunnerMock {
+	r := kerneltest.NewRunnerMock(name)
+	r.BootFunc = kerneltest.ReturnAfter(0, err)
+	return r
 }
 
-// mockRunnerService 是一个实现了 Runner 接口的模拟服务
-type mockRunnerService struct {
-	*mockDrugoService
-	runCalled bool
-	runError  error
-	runDelay  time.Duration
-	runBlock  bool // 是否阻塞运行
+// newBlockingRunner 返回阻塞直到上下文取消的模拟运行器
+func newBlockingRunner(name string) *kerneltest.RunnerMock {
+	r := kerneltest.NewRunnerMock(name)
+	r.RunFunc = kerneltest.BlockUntilCancel
+	return r
 }
 
-func (m *mockRunnerService) Run(ctx context.Context) error {
-	m.runCalled = true
-	if m.runDelay > 0 {
-		time.Sleep(m.runDelay)
-	}
-	if m.runBlock {
-		<-ctx.Done() // 阻塞直到上下文取消
-	}
-	return m.runError
+// newFailingRunner 返回 Run 时返回 err 的模拟运行器
+func newFailingRunner(name string, err error) *kerneltest.RunnerMock {
+	r := kerneltest.NewRunnerMock(name)
+	r.RunFunc = kerneltest.ReturnAfter(0, err)
+	return r
 }
 
 // TestNew 测试框架实例创建
@@ -74,7 +69,7 @@ func TestNew(t *testing.T) {
 	assert.Zero(t, app.shutdownTimeout)
 
 	// 测试自定义选项
-	service := &mockDrugoService{name: "test-service"}
+	service := kerneltest.NewServiceMock("test-service")
 	customCtx := context.WithValue(context.Background(), "key", "value")
 	app = New(
 		WithRoot("/custom/root"),
@@ -130,7 +125,7 @@ func TestDrugo_Boot(t *testing.T) {
 		{
 			name: "单个服务启动成功",
 			services: []kernel.Service{
-				&mockDrugoService{name: "service1"},
+				kerneltest.NewServiceMock("service1"),
 			},
 			expectError: false,
 			setupLogger: true,
@@ -138,8 +133,8 @@ func TestDrugo_Boot(t *testing.T) {
 		{
 			name: "多个服务启动成功",
 			services: []kernel.Service{
-				&mockDrugoService{name: "service1"},
-				&mockDrugoService{name: "service2"},
+				kerneltest.NewServiceMock("service1"),
+				kerneltest.NewServiceMock("service2"),
 			},
 			expectError: false,
 			setupLogger: true,
@@ -147,8 +142,8 @@ func TestDrugo_Boot(t *testing.T) {
 		{
 			name: "服务启动失败",
 			services: []kernel.Service{
-				&mockDrugoService{name: "service1"},
-				&mockDrugoService{name: "service2", bootError: assert.AnError},
+				kerneltest.NewServiceMock("service1"),
+				newBootFailingService("service2", assert.AnError),
 			},
 			expectError: true,
 			setupLogger: true,
@@ -192,8 +187,8 @@ func TestDrugo_Boot(t *testing.T) {
 
 			// 验证所有服务的 Boot 方法都被调用
 			for _, service := range tt.services {
-				if mockSvc, ok := service.(*mockDrugoService); ok {
-					assert.True(t, mockSvc.bootCalled, "服务 %s 的 Boot 方法应该被调用", mockSvc.name)
+				if mockSvc, ok := service.(*kerneltest.ServiceMock); ok {
+					assert.Positive(t, mockSvc.BootCount(), "服务 %s 的 Boot 方法应该被调用", mockSvc.Name())
 				}
 			}
 		})
@@ -220,7 +215,7 @@ func TestDrugo_Run(t *testing.T) {
 		{
 			name: "无Runner服务",
 			services: []kernel.Service{
-				&mockDrugoService{name: "service1"},
+				kerneltest.NewServiceMock("service1"),
 			},
 			expectError: false,
 			setupLogger: true,
@@ -229,9 +224,7 @@ func TestDrugo_Run(t *testing.T) {
 		{
 			name: "单个Runner服务",
 			services: []kernel.Service{
-				&mockRunnerService{
-					mockDrugoService: &mockDrugoService{name: "runner1"},
-				},
+				kerneltest.NewRunnerMock("runner1"),
 			},
 			expectError: false,
 			setupLogger: true,
@@ -240,10 +233,7 @@ func TestDrugo_Run(t *testing.T) {
 		{
 			name: "Runner服务运行失败",
 			services: []kernel.Service{
-				&mockRunnerService{
-					mockDrugoService: &mockDrugoService{name: "runner1"},
-					runError:         assert.AnError,
-				},
+				newFailingRunner("runner1", assert.AnError),
 			},
 			expectError: true,
 			setupLogger: true,
@@ -287,8 +277,8 @@ func TestDrugo_Run(t *testing.T) {
 
 			// 验证Runner服务的Run方法都被调用
 			for _, service := range tt.services {
-				if runner, ok := service.(*mockRunnerService); ok {
-					assert.True(t, runner.runCalled, "Runner服务 %s 的 Run 方法应该被调用", runner.name)
+				if runner, ok := service.(*kerneltest.RunnerMock); ok {
+					assert.True(t, runner.Ran(), "Runner服务 %s 的 Run 方法应该被调用", runner.Name())
 				}
 			}
 		})
@@ -312,7 +302,7 @@ func TestDrugo_Shutdown(t *testing.T) {
 		{
 			name: "单个服务关闭成功",
 			services: []kernel.Service{
-				&mockDrugoService{name: "service1"},
+				kerneltest.NewServiceMock("service1"),
 			},
 			expectError: false,
 			setupLogger: true,
@@ -320,8 +310,8 @@ func TestDrugo_Shutdown(t *testing.T) {
 		{
 			name: "多个服务关闭成功",
 			services: []kernel.Service{
-				&mockDrugoService{name: "service1"},
-				&mockDrugoService{name: "service2"},
+				kerneltest.NewServiceMock("service1"),
+				kerneltest.NewServiceMock("service2"),
 			},
 			expectError: false,
 			setupLogger: true,
@@ -329,11 +319,11 @@ func TestDrugo_Shutdown(t *testing.T) {
 		{
 			name: "服务关闭失败但继续关闭其他服务",
 			services: []kernel.Service{
-				&mockDrugoService{name: "service1"},
-				&mockDrugoService{name: "service2", closeError: assert.AnError},
-				&mockDrugoService{name: "service3"},
+				kerneltest.NewServiceMock("service1"),
+				newCloseFailingService("service2", assert.AnError),
+				kerneltest.NewServiceMock("service3"),
 			},
-			expectError: false, // 关闭失败不会返回错误
+			expectError: true, // 关闭失败时继续关闭其他服务，最后返回错误
 			setupLogger: true,
 		},
 	}
@@ -365,12 +355,16 @@ func TestDrugo_Shutdown(t *testing.T) {
 
 			// 执行关闭
 			err := app.Shutdown(context.Background())
-			assert.NoError(t, err) // Shutdown 总是返回 nil
+			if tt.expectError {
+				assert.True(t, kernel.IsServiceCloseFailed(err))
+			} else {
+				assert.NoError(t, err)
+			}
 
 			// 验证所有服务的 Close 方法都被调用
 			for _, service := range tt.services {
-				if mockSvc, ok := service.(*mockDrugoService); ok {
-					assert.True(t, mockSvc.closeCalled, "服务 %s 的 Close 方法应该被调用", mockSvc.name)
+				if mockSvc, ok := service.(*kerneltest.ServiceMock); ok {
+					assert.True(t, mockSvc.Closed(), "服务 %s 的 Close 方法应该被调用", mockSvc.Name())
 				}
 			}
 		})
@@ -378,20 +372,10 @@ func TestDrugo_Shutdown(t *testing.T) {
 }
 
 func TestDrugo_Shutdown_Order(t *testing.T) {
-	// 创建多个服务，通过不同的关闭延迟来验证顺序
-	services := []kernel.Service{
-		&mockDrugoService{
-			name:       "service1",
-			closeDelay: 30 * time.Millisecond, // 最长的延迟
-		},
-		&mockDrugoService{
-			name:       "service2",
-			closeDelay: 20 * time.Millisecond,
-		},
-		&mockDrugoService{
-			name:       "service3",
-			closeDelay: 10 * time.Millisecond, // 最短的延迟
-		},
+	services := []*kerneltest.ServiceMock{
+		kerneltest.NewServiceMock("service1"),
+		kerneltest.NewServiceMock("service2"),
+		kerneltest.NewServiceMock("service3"),
 	}
 
 	// 创建应用
@@ -415,24 +399,102 @@ func TestDrugo_Shutdown_Order(t *testing.T) {
 	require.NoError(t, err)
 	app.logger = logger
 
-	// 记录开始时间
-	start := time.Now()
-
 	// 执行关闭
-	app.Shutdown(context.Background())
+	require.NoError(t, app.Shutdown(context.Background()))
 
-	// 记录结束时间
-	elapsed := time.Since(start)
+	// 验证所有服务都被关闭，且按注册的逆序关闭
+	kerneltest.AssertClosedInReverseOrder(t, services...)
+}
 
-	// 验证总时间至少等于最长延迟（说明是逆序关闭）
-	assert.True(t, elapsed >= 30*time.Millisecond)
+// TestDrugo_Shutdown_FlushLogs 测试 Shutdown 结束前刷新日志，服务在 Close 中写入的日志可以立即读取
+func TestDrugo_Shutdown_FlushLogs(t *testing.T) {
+	dir := t.TempDir()
+	logger, err := log.NewManager(log.Config{
+		Outputs: []log.OutputConfig{{Type: "file", File: &log.FileOutputConfig{Dir: dir}}},
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = logger.Close() })
 
-	// 验证所有服务都被关闭
-	for _, service := range services {
-		if mockSvc, ok := service.(*mockDrugoService); ok {
-			assert.True(t, mockSvc.closeCalled)
-		}
+	svc := kerneltest.NewServiceMock("db")
+	svc.CloseFunc = func(ctx context.Context) error {
+		kernel.ServiceLoggerFromContext(ctx).Info("db closed")
+		return nil
 	}
+	app := New(WithService(svc))
+	app.logger = logger
+
+	require.NoError(t, app.Shutdown(context.Background()))
+	data, err := os.ReadFile(filepath.Join(dir, "db.log"))
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "db closed")
+}
+
+// assertServiceError 断言 err 同时匹配内核错误 kind 与原始错误 cause，且可以取得服务名称
+func assertServiceError(t *testing.T, err error, kind, cause error, service string) {
+	t.Helper()
+	assert.ErrorIs(t, err, kind)
+	assert.ErrorIs(t, err, cause)
+	var kerr *kernel.Error
+	require.ErrorAs(t, err, &kerr)
+	assert.Equal(t, service, kerr.Op())
+}
+
+// TestDrugo_LifecycleErrors 测试 Boot、Run、Close 的错误都被包装为对应的内核错误，并保留原始错误与服务名称
+func TestDrugo_LifecycleErrors(t *testing.T) {
+	t.Run("boot", func(t *testing.T) {
+		app := New(WithService(kerneltest.NewServiceMock("db")), WithService(newBootFailingService("cache", assert.AnError)))
+		app.logger = newTestLogManager(t)
+
+		err := app.Boot(context.Background())
+		assertServiceError(t, err, kernel.ErrServiceInitFailed, assert.AnError, "cache")
+		assert.True(t, kernel.IsServiceInitFailed(err))
+	})
+
+	t.Run("run", func(t *testing.T) {
+		app := New(WithService(newFailingRunner("consumer", assert.AnError)), WithService(newBlockingRunner("server")))
+		app.logger = newTestLogManager(t)
+		require.NoError(t, app.Boot(context.Background()))
+
+		err := app.Run(context.Background())
+		assertServiceError(t, err, kernel.ErrServiceRunFailed, assert.AnError, "consumer")
+		assert.True(t, kernel.IsServiceRunFailed(err))
+	})
+
+	t.Run("close", func(t *testing.T) {
+		closeErr := errors.New("close db")
+		db := newCloseFailingService("db", closeErr)
+		api := kerneltest.NewServiceMock("api")
+		app := New(WithService(db), WithService(newCloseFailingService("cache", assert.AnError)), WithService(api))
+		app.logger = newTestLogManager(t)
+
+		err := app.Shutdown(context.Background())
+		assert.True(t, kernel.IsServiceCloseFailed(err))
+		assert.True(t, api.Closed(), "remaining services are closed")
+
+		// Shutdown 合并所有失败，每个错误都保留各自的服务名称
+		joined, ok := err.(interface{ Unwrap() []error })
+		require.True(t, ok)
+		errs := joined.Unwrap()
+		require.Len(t, errs, 2)
+		assertServiceError(t, errs[0], kernel.ErrServiceCloseFailed, assert.AnError, "cache")
+		assertServiceError(t, errs[1], kernel.ErrServiceCloseFailed, closeErr, "db")
+	})
+
+	t.Run("serve", func(t *testing.T) {
+		runErr := errors.New("consume")
+		app := New(
+			WithService(newCloseFailingService("db", assert.AnError)),
+			WithService(newFailingRunner("consumer", runErr)),
+			WithDisableSignals(),
+		)
+		app.logger = newTestLogManager(t)
+
+		err := app.Serve(context.Background())
+		assert.True(t, kernel.IsServiceRunFailed(err), "Run 的错误")
+		assert.True(t, kernel.IsServiceCloseFailed(err), "Shutdown 的错误")
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.ErrorIs(t, err, runErr)
+	})
 }
 
 // TestDrugo_Config 测试配置管理器访问
@@ -470,8 +532,8 @@ func TestDrugo_serviceNames(t *testing.T) {
 	assert.Empty(t, names)
 
 	// 添加服务
-	service1 := &mockDrugoService{name: "service1"}
-	service2 := &mockDrugoService{name: "service2"}
+	service1 := kerneltest.NewServiceMock("service1")
+	service2 := kerneltest.NewServiceMock("service2")
 	app.Container().Bind("svc1", service1)
 	app.Container().Bind("svc2", service2)
 
@@ -481,7 +543,7 @@ func TestDrugo_serviceNames(t *testing.T) {
 
 func TestDrugo_Serve_Signal(t *testing.T) {
 	// 创建一个简单的应用
-	service := &mockDrugoService{name: "test-service"}
+	service := kerneltest.NewServiceMock("test-service")
 	app := New(WithService(service))
 
 	// 设置日志管理器
@@ -510,44 +572,41 @@ func TestDrugo_Serve_Signal(t *testing.T) {
 	// Serve 应该正常退出
 	err = app.Serve(ctx)
 	assert.NoError(t, err)
-	assert.True(t, service.bootCalled)
-	assert.True(t, service.closeCalled)
+	assert.Positive(t, service.BootCount())
+	assert.True(t, service.Closed())
 }
 
-// TestDrugo_Serve_Timeout 测试关闭超时
+// TestDrugo_Serve_Timeout 测试关闭超时：停机超时由 WithClock 设置的时钟计时，到期后 Close 的上下文被取消
 func TestDrugo_Serve_Timeout(t *testing.T) {
-	// 创建一个关闭缓慢的服务
-	service := &mockDrugoService{
-		name:       "slow-service",
-		closeDelay: 200 * time.Millisecond, // 超过默认超时时间
-	}
+	// 创建一个直到上下文取消才关闭完成的服务
+	service := kerneltest.NewServiceMock("slow-service")
+	service.CloseFunc = kerneltest.BlockUntilCancel
 
+	c := kernel.NewFakeClock(time.Time{})
 	app := New(
 		WithService(service),
-		WithShutdownTimeout(50*time.Millisecond), // 设置较短的超时时间
+		WithShutdownTimeout(time.Hour),
+		WithClock(c),
+		WithDisableSignals(),
 	)
+	app.logger = newTestLogManager(t)
 
-	// 设置日志管理器
-	logCfg := log.Config{
-		Level: "info",
-		Outputs: []log.OutputConfig{
-			{
-				Type:   "console",
-				Format: "text",
-			},
-		},
-	}
-	logger, err := log.NewManager(logCfg)
-	require.NoError(t, err)
-	app.logger = logger
+	done := make(chan error, 1)
+	go func() { done <- app.Serve(context.Background()) }()
 
-	ctx := context.Background()
-	err = app.Serve(ctx)
+	c.BlockUntilWaiters(1) // 停机超时的定时器
+	c.Advance(time.Hour - time.Second)
+	select {
+	case <-done:
+		t.Fatal("Serve returned before the shutdown timeout")
+	default:
+	}
 
 	// 应该正常退出，即使关闭超时
-	assert.NoError(t, err)
-	assert.True(t, service.bootCalled)
-	assert.True(t, service.closeCalled)
+	c.Advance(time.Second)
+	assert.NoError(t, <-done)
+	assert.Positive(t, service.BootCount())
+	assert.True(t, service.Closed())
 }
 
 // TestMustNewApp 测试强制创建应用
@@ -561,6 +620,79 @@ func TestMustNewApp(t *testing.T) {
 	})
 }
 
+// TestMustNewApp_EmptyConfig 测试配置目录为空时默认失败，WithAllowEmptyConfig 可以放开
+func TestMustNewApp_EmptyConfig(t *testing.T) {
+	root := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "conf"), 0755))
+
+	func() {
+		defer func() {
+			err, ok := recover().(error)
+			require.True(t, ok)
+			assert.True(t, config.IsEmptyConfig(err))
+		}()
+		MustNewApp(WithRoot(root))
+	}()
+
+	var app *Drugo
+	require.NotPanics(t, func() {
+		app = MustNewApp(WithRoot(root), WithAllowEmptyConfig())
+	})
+	assert.Empty(t, app.Config().List())
+}
+
+// TestMustNewApp_StrictNames 测试 MustNewApp 默认严格检查服务名称
+func TestMustNewApp_StrictNames(t *testing.T) {
+	root := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "conf"), 0755))
+	svc := WithNameService("config", kerneltest.NewServiceMock("config"))
+
+	func() {
+		defer func() {
+			err, ok := recover().(error)
+			require.True(t, ok)
+			assert.True(t, kernel.IsReservedServiceName(err))
+		}()
+		MustNewApp(WithRoot(root), WithAllowEmptyConfig(), svc)
+	}()
+
+	assert.NotPanics(t, func() {
+		MustNewApp(WithRoot(root), WithAllowEmptyConfig(), svc, WithStrictNames(false))
+	})
+}
+
+// TestMustNewApp_Env 测试运行环境的选择优先级
+func TestMustNewApp_Env(t *testing.T) {
+	root := t.TempDir()
+	conf := filepath.Join(root, "conf")
+	base := "app:\n  env: dev\n  name: base\nlog:\n  outputs:\n    - type: console\n"
+	require.NoError(t, os.MkdirAll(filepath.Join(conf, "dev"), 0755))
+	require.NoError(t, os.MkdirAll(filepath.Join(conf, "prod"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(conf, "app.yaml"), []byte(base), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(conf, "dev", "app.yaml"), []byte("app:\n  name: dev\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(conf, "prod", "app.yaml"), []byte("app:\n  name: prod\n"), 0644))
+
+	t.Run("配置项 app.env", func(t *testing.T) {
+		t.Setenv(EnvVar, "")
+		app := MustNewApp(WithRoot(root))
+		assert.Equal(t, "dev", app.Config().Environment())
+		assert.Equal(t, "dev", app.Config().MustGet("app").GetString("name"))
+	})
+
+	t.Run("环境变量优先于配置项", func(t *testing.T) {
+		t.Setenv(EnvVar, "prod")
+		app := MustNewApp(WithRoot(root))
+		assert.Equal(t, "prod", app.Config().Environment())
+		assert.Equal(t, "prod", app.Config().MustGet("app").GetString("name"))
+	})
+
+	t.Run("WithAppEnv 优先于环境变量", func(t *testing.T) {
+		t.Setenv(EnvVar, "prod")
+		app := MustNewApp(WithRoot(root), WithAppEnv("dev"))
+		assert.Equal(t, "dev", app.Config().MustGet("app").GetString("name"))
+	})
+}
+
 // TestConstants 测试常量定义
 func TestConstants(t *testing.T) {
 	assert.Equal(t, "(devel)", Version())
@@ -580,7 +712,7 @@ func TestInterfaceCompliance(t *testing.T) {
 
 // BenchmarkNew 测试创建应用性能
 func BenchmarkNew(b *testing.B) {
-	service := &mockDrugoService{name: "benchmark-service"}
+	service := kerneltest.NewServiceMock("benchmark-service")
 
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
@@ -593,7 +725,7 @@ func BenchmarkNew(b *testing.B) {
 func BenchmarkDrugo_Boot(b *testing.B) {
 	services := make([]kernel.Service, 10)
 	for i := 0; i < 10; i++ {
-		services[i] = &mockDrugoService{name: "service-" + string(rune(i))}
+		services[i] = kerneltest.NewServiceMock("service-" + string(rune(i)))
 	}
 
 	opts := []Option{}
@@ -628,7 +760,7 @@ func BenchmarkDrugo_Boot(b *testing.B) {
 func BenchmarkDrugo_serviceNames(b *testing.B) {
 	services := make([]kernel.Service, 100)
 	for i := 0; i < 100; i++ {
-		services[i] = &mockDrugoService{name: "service-" + string(rune(i))}
+		services[i] = kerneltest.NewServiceMock("service-" + string(rune(i)))
 	}
 
 	opts := []Option{}
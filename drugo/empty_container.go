@@ -0,0 +1,50 @@
+package drugo
+
+import "fmt"
+
+// EmptyContainerPolicy 决定没有注册任何服务时 Start/Serve 的行为，见 WithEmptyContainerPolicy
+type EmptyContainerPolicy string
+
+const (
+	// EmptyContainerWarn 在 Boot 与 Run 时记录警告日志后照常启动，是 New/NewE 的默认策略
+	EmptyContainerWarn EmptyContainerPolicy = "warn"
+	// EmptyContainerFail 使 Start/Serve 在 Boot 之前返回 ErrEmptyContainer，是 MustNewApp 的默认策略
+	EmptyContainerFail EmptyContainerPolicy = "fail"
+	// EmptyContainerAllow 允许没有服务的应用正常启动，并且不记录警告日志，适用于特殊的嵌入场景
+	EmptyContainerAllow EmptyContainerPolicy = "allow"
+)
+
+// WithEmptyContainerPolicy 设置没有注册任何服务时的处理策略。
+// 空容器几乎一定是编程错误，例如漏掉了 WithService，或者构建标签排除了所有 provider（见 RegisterProvider）。
+// New/NewE 默认使用 EmptyContainerWarn，MustNewApp 默认使用 EmptyContainerFail；
+// 未知的策略按 EmptyContainerWarn 处理
+func WithEmptyContainerPolicy(policy EmptyContainerPolicy) Option {
+	return func(o *options) {
+		o.emptyContainerPolicy = policy
+	}
+}
+
+// EmptyContainerPolicy 返回生效的空容器策略
+func (d *Drugo) EmptyContainerPolicy() EmptyContainerPolicy {
+	switch d.emptyContainerPolicy {
+	case EmptyContainerFail, EmptyContainerAllow:
+		return d.emptyContainerPolicy
+	default:
+		return EmptyContainerWarn
+	}
+}
+
+// checkEmptyContainer 在 EmptyContainerFail 策略下检查是否注册了服务
+func (d *Drugo) checkEmptyContainer() error {
+	if d.EmptyContainerPolicy() != EmptyContainerFail || len(d.Container().Services()) > 0 {
+		return nil
+	}
+	return fmt.Errorf("%w: register services with WithService or WithServiceProvider, "+
+		"check that build tags did not exclude every provider selected by WithRegisteredProviders, "+
+		"or use WithEmptyContainerPolicy(EmptyContainerAllow) if this is intended", ErrEmptyContainer)
+}
+
+// warnEmptyContainer 报告是否需要为空容器记录警告日志
+func (d *Drugo) warnEmptyContainer() bool {
+	return d.EmptyContainerPolicy() != EmptyContainerAllow
+}
@@ -0,0 +1,101 @@
+package drugo
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/qq1060656096/drugo/kernel/kerneltest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestWithEmptyContainerPolicy 测试没有注册任何服务时各策略下 Start 的行为、日志与启动报告
+func TestWithEmptyContainerPolicy(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     []Option
+		policy   EmptyContainerPolicy
+		wantErr  bool
+		wantWarn bool
+	}{
+		{name: "New 默认 warn", policy: EmptyContainerWarn, wantWarn: true},
+		{name: "warn", opts: []Option{WithEmptyContainerPolicy(EmptyContainerWarn)}, policy: EmptyContainerWarn, wantWarn: true},
+		{name: "fail", opts: []Option{WithEmptyContainerPolicy(EmptyContainerFail)}, policy: EmptyContainerFail, wantErr: true},
+		{name: "allow", opts: []Option{WithEmptyContainerPolicy(EmptyContainerAllow)}, policy: EmptyContainerAllow},
+		{name: "未知策略按 warn 处理", opts: []Option{WithEmptyContainerPolicy("ignore")}, policy: EmptyContainerWarn, wantWarn: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, dir := newFileTestLogManager(t)
+			app := New(tt.opts...)
+			app.logger = m
+			assert.Equal(t, tt.policy, app.EmptyContainerPolicy())
+
+			h, err := app.Start(context.Background())
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrEmptyContainer)
+				assert.Zero(t, app.BootReport(), "Boot must not run")
+			} else {
+				require.NoError(t, err)
+				<-h.Done()
+				require.NoError(t, h.Stop(context.Background()))
+				report := app.BootReport()
+				assert.Equal(t, tt.policy, report.EmptyContainerPolicy)
+				assert.Zero(t, report.ServiceCount)
+			}
+
+			require.NoError(t, m.Sync())
+			data, err := os.ReadFile(filepath.Join(dir, "drugo.log"))
+			require.NoError(t, err)
+			log := string(data)
+			assert.Contains(t, log, `"services":0`)
+			assert.Contains(t, log, `"empty_container_policy":"`+string(tt.policy)+`"`)
+			if tt.wantWarn {
+				assert.Contains(t, log, "no services registered to boot")
+				assert.Contains(t, log, "no services to run")
+			} else {
+				assert.NotContains(t, log, "no services registered to boot")
+				assert.NotContains(t, log, "no services to run")
+			}
+		})
+	}
+}
+
+// TestWithEmptyContainerPolicy_Serve 测试 fail 策略下 Serve 在 Boot 之前返回错误
+func TestWithEmptyContainerPolicy_Serve(t *testing.T) {
+	app := New(WithEmptyContainerPolicy(EmptyContainerFail), WithDisableSignals())
+	app.logger = newTestLogManager(t)
+	err := app.Serve(context.Background())
+	assert.ErrorIs(t, err, ErrEmptyContainer)
+	assert.Contains(t, err.Error(), "WithEmptyContainerPolicy(EmptyContainerAllow)")
+}
+
+// TestWithEmptyContainerPolicy_NonEmpty 测试注册了服务时 fail 策略不影响启动
+func TestWithEmptyContainerPolicy_NonEmpty(t *testing.T) {
+	db := kerneltest.NewServiceMock("db")
+	app := New(WithService(db), WithEmptyContainerPolicy(EmptyContainerFail))
+	app.logger = newTestLogManager(t)
+
+	h, err := app.Start(context.Background())
+	require.NoError(t, err)
+	require.NoError(t, h.Stop(context.Background()))
+	assert.Positive(t, db.BootCount())
+	assert.Equal(t, 1, app.BootReport().ServiceCount)
+	assert.Equal(t, EmptyContainerFail, app.BootReport().EmptyContainerPolicy)
+}
+
+// TestMustNewApp_EmptyContainerPolicy 测试 MustNewApp 默认使用 fail 策略，并且可以被覆盖
+func TestMustNewApp_EmptyContainerPolicy(t *testing.T) {
+	root := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "conf"), 0755))
+
+	app := MustNewApp(WithRoot(root), WithAllowEmptyConfig(), WithQuiet(true))
+	assert.Equal(t, EmptyContainerFail, app.EmptyContainerPolicy())
+	_, err := app.Start(context.Background())
+	assert.ErrorIs(t, err, ErrEmptyContainer)
+
+	app = MustNewApp(WithRoot(root), WithAllowEmptyConfig(), WithQuiet(true), WithEmptyContainerPolicy(EmptyContainerAllow))
+	assert.Equal(t, EmptyContainerAllow, app.EmptyContainerPolicy())
+}
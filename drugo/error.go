@@ -0,0 +1,58 @@
+package drugo
+
+import (
+	"errors"
+
+	"github.com/qq1060656096/drugo/kernel"
+)
+
+var (
+	// ErrUnknownCommand 表示 Execute 收到了未注册的子命令
+	ErrUnknownCommand = errors.New("drugo: unknown command")
+	// ErrBootPassLimit 表示 Boot 期间动态注册服务的轮数超过 MaxBootPasses，通常意味着服务之间循环注册
+	ErrBootPassLimit = errors.New("drugo: boot pass limit exceeded")
+	// ErrAlreadyStarted 表示 Start 或 Serve 被重复调用，一个 Drugo 实例只能启动一次
+	ErrAlreadyStarted = errors.New("drugo: already started")
+	// ErrClaimConflict 表示两个服务声明了同一项资源，或启用 WithProbeClaims 时声明的端口已被占用
+	ErrClaimConflict = errors.New("drugo: resource claim conflict")
+	// ErrEntryNotFound 表示 TypedContainer 中不存在指定名称的实例，与 kernel.ErrEntryNotFound 相同
+	ErrEntryNotFound = kernel.ErrEntryNotFound
+	// ErrNilService 表示注册了 nil 服务
+	ErrNilService = errors.New("drugo: nil service")
+	// ErrServiceProvider 表示服务的构造函数（见 WithServiceErr、WithServiceProvider）返回了错误
+	ErrServiceProvider = errors.New("drugo: service provider failed")
+	// ErrUnknownProvider 表示 WithRegisteredProviders 选用了未通过 RegisterProvider 注册的 provider
+	ErrUnknownProvider = errors.New("drugo: unknown provider")
+	// ErrEmptyContainer 表示在 EmptyContainerFail 策略下启动了没有注册任何服务的应用，见 WithEmptyContainerPolicy
+	ErrEmptyContainer = errors.New("drugo: no services registered")
+	// ErrDiagnosticsDisabled 表示未通过 WithDiagnosticsDir 启用诊断采集
+	ErrDiagnosticsDisabled = errors.New("drugo: diagnostics disabled")
+	// ErrDiagnosticsInProgress 表示已有诊断采集正在进行
+	ErrDiagnosticsInProgress = errors.New("drugo: diagnostics capture in progress")
+	// ErrNotRunner 表示服务没有实现 kernel.Runner
+	ErrNotRunner = errors.New("drugo: service is not a runner")
+	// ErrAppNotRunning 表示 Run 未在运行，无法停止或启动单个 Runner
+	ErrAppNotRunning = errors.New("drugo: app not running")
+	// ErrRunnerNotRunning 表示 Runner 未在运行，例如已经通过 StopRunner 停止
+	ErrRunnerNotRunning = errors.New("drugo: runner not running")
+	// ErrSignalOptionConflict 表示同时使用了 WithDisableSignals 与 WithShutdownSignals
+	ErrSignalOptionConflict = errors.New("drugo: conflicting signal options")
+	// ErrRunnerNotStopped 表示 Runner 不是通过 StopRunner 停止的状态，无法通过 StartRunner 重新启动
+	ErrRunnerNotStopped = errors.New("drugo: runner not stopped")
+	// ErrCapabilityMissing 表示服务通过 kernel.CapabilityRequirer 声明的能力依赖无法满足
+	ErrCapabilityMissing = errors.New("drugo: required capability missing")
+	// ErrInvalidProvider 表示 WithProvider 传入的不是合法的构造函数，见 WithProvider
+	ErrInvalidProvider = errors.New("drugo: invalid provider")
+	// ErrProviderMissing 表示构造函数的参数类型没有任何构造函数返回
+	ErrProviderMissing = errors.New("drugo: provider missing")
+	// ErrProviderDuplicate 表示多个构造函数返回同一类型
+	ErrProviderDuplicate = errors.New("drugo: duplicate provider")
+	// ErrProviderCycle 表示构造函数之间存在循环依赖
+	ErrProviderCycle = errors.New("drugo: provider cycle")
+	// ErrNotProvided 表示没有构造函数返回 Provided 查询的类型
+	ErrNotProvided = errors.New("drugo: type not provided")
+	// ErrServicePanic 表示服务的 Boot 或 Run 发生了 panic，panic 已被恢复并写入崩溃报告
+	ErrServicePanic = errors.New("drugo: service panicked")
+	// ErrPIDFileLocked 表示 WithPIDFile 指定的 PID 文件被另一个存活的实例持有
+	ErrPIDFileLocked = errors.New("drugo: pid file locked by another instance")
+)
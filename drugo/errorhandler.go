@@ -0,0 +1,144 @@
+package drugo
+
+import (
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Phase 是发生错误的生命周期阶段，见 WithErrorHandler
+type Phase string
+
+const (
+	// PhaseBoot 表示服务的 Boot（包括注入 logger 与配置）失败
+	PhaseBoot Phase = "boot"
+	// PhaseRun 表示 Runner 的 Run 主动返回了错误
+	PhaseRun Phase = "run"
+	// PhaseShutdown 表示服务的 Close 失败
+	PhaseShutdown Phase = "shutdown"
+	// PhaseReload 表示配置热加载失败，服务名称为 "config"
+	PhaseReload Phase = "reload"
+)
+
+// ErrorDecision 是错误处理函数对生命周期错误的处理决定
+type ErrorDecision int
+
+const (
+	// DecisionDefault 保持框架的默认行为，适用于只做告警的处理函数
+	DecisionDefault ErrorDecision = iota
+	// DecisionContinue 忽略错误继续运行：
+	// Boot 阶段将服务标记为降级（与可选服务相同），Run 阶段只让该 Runner 退出、其他 Runner 继续运行，
+	// Shutdown 阶段只记录日志，Shutdown 不返回该错误，Reload 阶段与默认行为相同
+	DecisionContinue
+	// DecisionFail 使应用失败：
+	// Boot 阶段即使是可选服务也返回错误，Run 与 Shutdown 阶段与默认行为相同
+	// （Shutdown 在关闭其余服务后返回所有 Close 错误），
+	// Reload 阶段触发 Serve 优雅停机并由 Serve 返回该错误
+	DecisionFail
+	// DecisionRetry 只对 Run 阶段有效：按指数退避在同一个服务实例上再次调用 Run（见 WithRunRetry），
+	// 连续重试次数耗尽后视为 DecisionFail；其他阶段视为 DecisionFail
+	DecisionRetry
+)
+
+// Run 阶段 DecisionRetry 的默认重试策略，见 WithRunRetry
+const (
+	// DefaultRunRetryBackoff 是第一次重新运行 Runner 之前的等待时间，每次连续重试后翻倍
+	DefaultRunRetryBackoff = 100 * time.Millisecond
+	// DefaultRunRetryMaxDelay 是重新运行 Runner 之前的最长等待时间
+	DefaultRunRetryMaxDelay = 30 * time.Second
+	// DefaultRunRetryAttempts 是 Runner 连续重试的最大次数，超过后视为 DecisionFail
+	DefaultRunRetryAttempts = 5
+)
+
+// String 返回决定的名称
+func (dec ErrorDecision) String() string {
+	switch dec {
+	case DecisionDefault:
+		return "default"
+	case DecisionContinue:
+		return "continue"
+	case DecisionFail:
+		return "fail"
+	case DecisionRetry:
+		return "retry"
+	default:
+		return fmt.Sprintf("ErrorDecision(%d)", int(dec))
+	}
+}
+
+// ErrorHandler 是生命周期错误的集中处理函数，见 WithErrorHandler。
+// err 包装了对应阶段的 kernel 错误（kernel.ErrServiceInitFailed、ErrServiceRunFailed、ErrServiceCloseFailed），
+// 同时保留服务返回的原始错误，处理函数中可以使用 errors.Is 判断；Reload 阶段为 config 包返回的错误。
+type ErrorHandler func(phase Phase, serviceName string, err error) ErrorDecision
+
+// WithErrorHandler 设置生命周期错误的集中处理函数，用于统一告警或调整错误的处理方式。
+// 框架在应用默认行为之前调用 handler，生命周期允许时以其返回的决定（见 ErrorDecision）代替默认行为。
+// handler 在框架的主流程中同步执行，应当尽快返回；handler panic 时记录日志并保持默认行为
+func WithErrorHandler(handler ErrorHandler) Option {
+	return func(o *options) {
+		o.errorHandler = handler
+	}
+}
+
+// WithRunRetry 设置错误处理函数对 Run 阶段的错误返回 DecisionRetry 时的重试策略：
+// 第一次重新运行前等待 backoff，每次连续重试后等待时间翻倍，最多 maxDelay；连续重试 attempts 次后
+// 再次失败视为 DecisionFail。一次 Run 持续 maxDelay 以上之后，或通过 StartRunner 重新启动之后重新计数。
+// 等待使用 WithClock 设置的时钟；小于等于 0 的参数使用 DefaultRunRetryBackoff、DefaultRunRetryMaxDelay 与 DefaultRunRetryAttempts
+func WithRunRetry(backoff, maxDelay time.Duration, attempts int) Option {
+	return func(o *options) {
+		o.runRetryBackoff = backoff
+		o.runRetryMaxDelay = maxDelay
+		o.runRetryAttempts = attempts
+	}
+}
+
+// runRetryPolicy 返回 DecisionRetry 的重试策略，未设置的部分使用默认值
+func (d *Drugo) runRetryPolicy() (backoff, maxDelay time.Duration, attempts int) {
+	backoff, maxDelay, attempts = d.runRetryBackoff, d.runRetryMaxDelay, d.runRetryAttempts
+	if backoff <= 0 {
+		backoff = DefaultRunRetryBackoff
+	}
+	if maxDelay <= 0 {
+		maxDelay = DefaultRunRetryMaxDelay
+	}
+	if attempts <= 0 {
+		attempts = DefaultRunRetryAttempts
+	}
+	return backoff, max(backoff, maxDelay), attempts
+}
+
+// handleError 调用错误处理函数并返回其决定，没有设置处理函数或处理函数 panic 时返回 DecisionDefault
+func (d *Drugo) handleError(phase Phase, serviceName string, err error) (dec ErrorDecision) {
+	if d.errorHandler == nil {
+		return DecisionDefault
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			d.frameworkLogger().Error("error handler panic",
+				zap.String("phase", string(phase)),
+				zap.String("service", serviceName),
+				zap.Any("panic", r),
+			)
+			dec = DecisionDefault
+		}
+	}()
+	return d.errorHandler(phase, serviceName, err)
+}
+
+// fail 记录第一个由错误处理函数升级的错误，并请求 Serve 开始优雅停机
+func (d *Drugo) fail(err error) {
+	d.failMu.Lock()
+	if d.failErr == nil {
+		d.failErr = err
+	}
+	d.failMu.Unlock()
+	d.Stop()
+}
+
+// failure 返回由错误处理函数升级的错误
+func (d *Drugo) failure() error {
+	d.failMu.Lock()
+	defer d.failMu.Unlock()
+	return d.failErr
+}
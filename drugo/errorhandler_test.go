@@ -0,0 +1,285 @@
+package drugo
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/qq1060656096/drugo/config"
+	"github.com/qq1060656096/drugo/kernel"
+	"github.com/qq1060656096/drugo/kernel/kerneltest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// handlerCall 是错误处理函数收到的一次调用
+type handlerCall struct {
+	phase   Phase
+	service string
+	err     error
+}
+
+// handlerRecorder 记录错误处理函数的调用并返回固定的决定
+type handlerRecorder struct {
+	mu       sync.Mutex
+	calls    []handlerCall
+	decision ErrorDecision
+}
+
+func (r *handlerRecorder) handle(phase Phase, service string, err error) ErrorDecision {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls = append(r.calls, handlerCall{phase: phase, service: service, err: err})
+	return r.decision
+}
+
+func (r *handlerRecorder) Calls() []handlerCall {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]handlerCall(nil), r.calls...)
+}
+
+// TestWithErrorHandler_Boot 测试 Boot 失败时处理函数的决定覆盖可选服务的默认行为
+func TestWithErrorHandler_Boot(t *testing.T) {
+	tests := []struct {
+		name     string
+		optional bool
+		decision ErrorDecision
+		wantErr  bool
+	}{
+		{"required default", false, DecisionDefault, true},
+		{"required continue", false, DecisionContinue, false},
+		{"optional default", true, DecisionDefault, false},
+		{"optional fail", true, DecisionFail, true},
+		{"optional retry treated as fail", true, DecisionRetry, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := &handlerRecorder{decision: tt.decision}
+			failing := newBootFailingRunner("cache", assert.AnError)
+			opts := []Option{WithErrorHandler(rec.handle)}
+			if tt.optional {
+				opts = append(opts, WithOptionalService(failing))
+			} else {
+				opts = append(opts, WithService(failing))
+			}
+			app := New(opts...)
+			app.logger = newTestLogManager(t)
+
+			err := app.Boot(context.Background())
+			if tt.wantErr {
+				assert.ErrorIs(t, err, assert.AnError)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, []string{"cache"}, app.Degraded())
+			}
+
+			calls := rec.Calls()
+			require.Len(t, calls, 1)
+			assert.Equal(t, PhaseBoot, calls[0].phase)
+			assert.Equal(t, "cache", calls[0].service)
+			assert.ErrorIs(t, calls[0].err, kernel.ErrServiceInitFailed)
+			assert.ErrorIs(t, calls[0].err, assert.AnError)
+		})
+	}
+}
+
+// TestWithErrorHandler_Run 测试 Runner 失败时 Continue 只让该 Runner 退出，Retry 重新运行，Fail 取消所有 Runner
+func TestWithErrorHandler_Run(t *testing.T) {
+	t.Run("continue", func(t *testing.T) {
+		rec := &handlerRecorder{decision: DecisionContinue}
+		failing := newFailingRunner("consumer", assert.AnError)
+		server := newBlockingRunner("server")
+		app := New(WithService(failing), WithService(server), WithErrorHandler(rec.handle))
+		h := startRunners(t, app, server)
+
+		require.Eventually(t, func() bool { return len(rec.Calls()) == 1 }, time.Second, 5*time.Millisecond)
+		select {
+		case <-h.Done():
+			t.Fatal("other runners keep running when the failure is ignored")
+		case <-time.After(50 * time.Millisecond):
+		}
+		assert.Equal(t, ServiceStateRunning, app.Status()["server"].State)
+
+		require.NoError(t, h.Stop(context.Background()))
+		assert.NoError(t, h.Err())
+		call := rec.Calls()[0]
+		assert.Equal(t, PhaseRun, call.phase)
+		assert.Equal(t, "consumer", call.service)
+		assert.ErrorIs(t, call.err, kernel.ErrServiceRunFailed)
+		assert.ErrorIs(t, call.err, assert.AnError)
+	})
+
+	t.Run("retry", func(t *testing.T) {
+		rec := &handlerRecorder{decision: DecisionRetry}
+		var attempts atomic.Int32
+		flaky := kerneltest.NewRunnerMock("consumer")
+		flaky.RunFunc = func(ctx context.Context) error {
+			if attempts.Add(1) == 1 {
+				return assert.AnError
+			}
+			return kerneltest.BlockUntilCancel(ctx)
+		}
+		app := New(WithService(flaky), WithErrorHandler(rec.handle))
+		h := startRunners(t, app, flaky)
+
+		require.Eventually(t, func() bool { return flaky.RunCount() == 2 }, time.Second, 5*time.Millisecond)
+		assert.Equal(t, ServiceStateRunning, app.Status()["consumer"].State)
+		require.NoError(t, h.Stop(context.Background()))
+		assert.NoError(t, h.Err())
+		assert.Len(t, rec.Calls(), 1)
+	})
+
+	t.Run("fail", func(t *testing.T) {
+		rec := &handlerRecorder{decision: DecisionFail}
+		failing := newFailingRunner("consumer", assert.AnError)
+		server := newBlockingRunner("server")
+		app := New(WithService(failing), WithService(server), WithErrorHandler(rec.handle))
+		app.logger = newTestLogManager(t)
+		require.NoError(t, app.Boot(context.Background()))
+
+		assert.ErrorIs(t, app.Run(context.Background()), assert.AnError)
+		assert.Len(t, rec.Calls(), 1)
+	})
+}
+
+// TestWithRunRetry 测试 DecisionRetry 按指数退避重新运行 Runner，连续重试次数耗尽后视为 DecisionFail
+func TestWithRunRetry(t *testing.T) {
+	clk := kernel.NewFakeClock(time.Now())
+	rec := &handlerRecorder{decision: DecisionRetry}
+	failing := newFailingRunner("consumer", assert.AnError)
+	app := New(WithService(failing), WithErrorHandler(rec.handle), WithClock(clk), WithRunRetry(time.Second, 3*time.Second, 3))
+	app.logger = newTestLogManager(t)
+	require.NoError(t, app.Boot(context.Background()))
+
+	done := make(chan error, 1)
+	go func() { done <- app.Run(context.Background()) }()
+
+	// 每次重试前的等待依次为 1s、2s，之后不超过 3s
+	for i, delay := range []time.Duration{time.Second, 2 * time.Second, 3 * time.Second} {
+		clk.BlockUntilWaiters(1)
+		clk.Advance(delay - time.Millisecond)
+		assert.Equal(t, i+1, failing.RunCount(), "runner relaunched before the backoff elapsed")
+		clk.Advance(time.Millisecond)
+		require.Eventually(t, func() bool { return failing.RunCount() == i+2 }, time.Second, time.Millisecond)
+	}
+
+	select {
+	case err := <-done:
+		assert.ErrorIs(t, err, assert.AnError)
+	case <-time.After(time.Second):
+		t.Fatal("Run did not fail after retries were exhausted")
+	}
+	assert.Equal(t, 4, failing.RunCount())
+	assert.Len(t, rec.Calls(), 4)
+}
+
+// TestWithRunRetry_Shutdown 测试等待重试期间停机时不再重新运行 Runner
+func TestWithRunRetry_Shutdown(t *testing.T) {
+	clk := kernel.NewFakeClock(time.Now())
+	failing := newFailingRunner("consumer", assert.AnError)
+	app := New(WithService(failing), WithClock(clk),
+		WithErrorHandler(func(Phase, string, error) ErrorDecision { return DecisionRetry }))
+	app.logger = newTestLogManager(t)
+	require.NoError(t, app.Boot(context.Background()))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- app.Run(ctx) }()
+	clk.BlockUntilWaiters(1)
+	cancel()
+
+	select {
+	case err := <-done:
+		assert.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("Run did not return while a retry was pending")
+	}
+	assert.Equal(t, 1, failing.RunCount())
+}
+
+// TestWithErrorHandler_Shutdown 测试 Close 失败时 Continue 使 Shutdown 与 Serve 不返回该错误，其他服务仍然关闭
+func TestWithErrorHandler_Shutdown(t *testing.T) {
+	tests := []struct {
+		decision ErrorDecision
+		wantErr  bool
+	}{
+		{DecisionDefault, true},
+		{DecisionContinue, false},
+		{DecisionFail, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.decision.String(), func(t *testing.T) {
+			rec := &handlerRecorder{decision: tt.decision}
+			db := kerneltest.NewServiceMock("db")
+			cache := newCloseFailingService("cache", assert.AnError)
+			app := New(WithService(db), WithService(cache), WithDisableSignals(), WithErrorHandler(rec.handle))
+			app.logger = newTestLogManager(t)
+
+			err := app.Serve(context.Background())
+			if tt.wantErr {
+				assert.ErrorIs(t, err, kernel.ErrServiceCloseFailed)
+				assert.ErrorIs(t, err, assert.AnError)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.True(t, db.Closed(), "remaining services are closed")
+
+			calls := rec.Calls()
+			require.Len(t, calls, 1)
+			assert.Equal(t, PhaseShutdown, calls[0].phase)
+			assert.Equal(t, "cache", calls[0].service)
+			assert.ErrorIs(t, calls[0].err, kernel.ErrServiceCloseFailed)
+		})
+	}
+}
+
+// TestWithErrorHandler_Reload 测试热加载失败时 Fail 触发 Serve 停机并返回该错误
+func TestWithErrorHandler_Reload(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "app.yaml")
+	require.NoError(t, os.WriteFile(file, []byte(reportConfig), 0644))
+	m, err := config.NewManager(dir, config.WithWatchDebounce(10*time.Millisecond))
+	require.NoError(t, err)
+
+	rec := &handlerRecorder{decision: DecisionFail}
+	server := newBlockingRunner("server")
+	app := New(WithService(server), WithDisableSignals(), WithErrorHandler(rec.handle))
+	app.logger = newTestLogManager(t)
+	app.config = m
+
+	done := serveAsync(t, context.Background(), app, server)
+	require.NoError(t, m.Watch())
+	defer m.StopWatch()
+	time.Sleep(100 * time.Millisecond) // 给监听器时间启动
+	require.NoError(t, os.WriteFile(file, []byte("invalid: yaml: ["), 0644))
+
+	err = waitServe(t, done)
+	require.Error(t, err)
+	assert.True(t, server.Closed())
+	calls := rec.Calls()
+	require.Len(t, calls, 1)
+	assert.Equal(t, PhaseReload, calls[0].phase)
+	assert.Equal(t, "config", calls[0].service)
+	assert.Equal(t, calls[0].err, err)
+}
+
+// TestWithErrorHandler_Panic 测试处理函数 panic 时保持默认行为
+func TestWithErrorHandler_Panic(t *testing.T) {
+	app := New(
+		WithOptionalService(newBootFailingRunner("cache", assert.AnError)),
+		WithErrorHandler(func(phase Phase, serviceName string, err error) ErrorDecision {
+			panic("boom")
+		}),
+	)
+	app.logger = newTestLogManager(t)
+
+	require.NoError(t, app.Boot(context.Background()))
+	assert.Equal(t, []string{"cache"}, app.Degraded())
+	assert.Equal(t, DecisionDefault, app.handleError(PhaseRun, "x", errors.New("x")))
+}
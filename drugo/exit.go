@@ -0,0 +1,99 @@
+package drugo
+
+import (
+	"maps"
+	"os"
+)
+
+// Outcome 是 Serve 或 Execute 结束时的生命周期结果，ExitCode 据此返回进程退出码。
+type Outcome string
+
+const (
+	// OutcomePending 表示 Serve 与 Execute 都尚未返回。
+	OutcomePending Outcome = ""
+	// OutcomeClean 表示正常结束，包括收到停机信号、ctx 取消、调用 Stop 或 Run 结束后的优雅停机。
+	OutcomeClean Outcome = "clean"
+	// OutcomeBootFailed 表示 Start 失败（例如服务 Boot 失败），应用没有运行。
+	OutcomeBootFailed Outcome = "boot_failed"
+	// OutcomeError 表示 Runner 出错、服务关闭失败或子命令返回了错误。
+	OutcomeError Outcome = "error"
+	// OutcomeShutdownTimeout 表示优雅停机超过了停机超时时间（见 WithShutdownTimeout），优先于 OutcomeError。
+	OutcomeShutdownTimeout Outcome = "shutdown_timeout"
+)
+
+// defaultExitCodes 是未通过 WithExitCodes 覆盖时各结果的退出码
+var defaultExitCodes = map[Outcome]int{
+	OutcomePending:         0,
+	OutcomeClean:           0,
+	OutcomeBootFailed:      1,
+	OutcomeError:           1,
+	OutcomeShutdownTimeout: 2,
+}
+
+// DefaultExitCodes 返回默认的退出码映射：正常结束为 0，启动失败与运行出错为 1，停机超时为 2，
+// 容器编排系统可以据此区分崩溃循环与停机缓慢。
+func DefaultExitCodes() map[Outcome]int {
+	return maps.Clone(defaultExitCodes)
+}
+
+// WithExitCodes 覆盖部分结果的退出码，未出现在 codes 中的结果仍使用 DefaultExitCodes 的值。
+// 退出码应在 0 到 125 之间，避免与 shell 及容器运行时保留的退出码混淆。
+func WithExitCodes(codes map[Outcome]int) Option {
+	return func(o *options) {
+		if o.exitCodes == nil {
+			o.exitCodes = make(map[Outcome]int, len(codes))
+		}
+		maps.Copy(o.exitCodes, codes)
+	}
+}
+
+// Outcome 返回最近一次 Serve 或 Execute 结束时的结果，两者都尚未返回时为 OutcomePending
+func (d *Drugo) Outcome() Outcome {
+	d.outcomeMu.Lock()
+	defer d.outcomeMu.Unlock()
+	return d.outcome
+}
+
+// ExitCode 返回 Outcome 对应的进程退出码，映射见 DefaultExitCodes 与 WithExitCodes
+func (d *Drugo) ExitCode() int {
+	outcome := d.Outcome()
+	if code, ok := d.exitCodes[outcome]; ok {
+		return code
+	}
+	return defaultExitCodes[outcome]
+}
+
+// setOutcome 记录生命周期结果
+func (d *Drugo) setOutcome(outcome Outcome) {
+	d.outcomeMu.Lock()
+	defer d.outcomeMu.Unlock()
+	d.outcome = outcome
+}
+
+// setOutcomeIfPending 在 Serve 没有记录结果时按 err 记录结果，用于不经过 Serve 的子命令
+func (d *Drugo) setOutcomeIfPending(err error) {
+	d.outcomeMu.Lock()
+	defer d.outcomeMu.Unlock()
+	if d.outcome != OutcomePending {
+		return
+	}
+	d.outcome = OutcomeClean
+	if err != nil {
+		d.outcome = OutcomeError
+	}
+}
+
+// osExit 是 Exit 使用的退出函数，测试中可以替换
+var osExit = os.Exit
+
+// Exit 以 app.ExitCode() 退出进程，用于 main 函数在 Serve 或 Execute 返回之后调用：
+//
+//	if err := app.Execute(ctx, os.Args); err != nil {
+//		fmt.Fprintln(os.Stderr, err)
+//	}
+//	drugo.Exit(app)
+//
+// os.Exit 不会执行 defer，需要清理的资源应当在 Exit 之前释放。
+func Exit(app *Drugo) {
+	osExit(app.ExitCode())
+}
@@ -0,0 +1,145 @@
+package drugo
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/qq1060656096/drugo/kernel"
+	"github.com/qq1060656096/drugo/kernel/kerneltest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// serveOutcome 以 opts 创建应用并执行 Serve，stop 为 true 时在 Boot 完成后调用 Stop
+func serveOutcome(t *testing.T, stop bool, opts ...Option) (*Drugo, error) {
+	t.Helper()
+	app := New(append([]Option{WithDisableSignals()}, opts...)...)
+	app.logger = newTestLogManager(t)
+	if stop {
+		app.Stop()
+	}
+	err := app.Serve(context.Background())
+	return app, err
+}
+
+// TestDrugo_ExitCode 测试各生命周期结果对应的默认退出码
+func TestDrugo_ExitCode(t *testing.T) {
+	t.Run("pending", func(t *testing.T) {
+		app := New()
+		assert.Equal(t, OutcomePending, app.Outcome())
+		assert.Equal(t, 0, app.ExitCode())
+	})
+
+	t.Run("clean after stop", func(t *testing.T) {
+		app, err := serveOutcome(t, true, WithService(newBlockingRunner("runner")))
+		require.NoError(t, err)
+		assert.Equal(t, OutcomeClean, app.Outcome())
+		assert.Equal(t, 0, app.ExitCode())
+	})
+
+	t.Run("boot failed", func(t *testing.T) {
+		app, err := serveOutcome(t, false, WithService(newBootFailingRunner("runner", errors.New("boom"))))
+		require.Error(t, err)
+		assert.Equal(t, OutcomeBootFailed, app.Outcome())
+		assert.Equal(t, 1, app.ExitCode())
+
+		// 重复启动不会覆盖已经记录的结果
+		assert.ErrorIs(t, app.Serve(context.Background()), ErrAlreadyStarted)
+		assert.Equal(t, OutcomeBootFailed, app.Outcome())
+	})
+
+	t.Run("runner error", func(t *testing.T) {
+		app, err := serveOutcome(t, false, WithService(newFailingRunner("runner", errors.New("boom"))))
+		require.Error(t, err)
+		assert.Equal(t, OutcomeError, app.Outcome())
+		assert.Equal(t, 1, app.ExitCode())
+	})
+
+	t.Run("close error", func(t *testing.T) {
+		service := kerneltest.NewServiceMock("svc")
+		service.CloseFunc = kerneltest.ReturnAfter(0, errors.New("close failed"))
+		app, err := serveOutcome(t, true, WithService(service))
+		require.True(t, kernel.IsServiceCloseFailed(err))
+		assert.Equal(t, OutcomeError, app.Outcome())
+		assert.Equal(t, 1, app.ExitCode())
+	})
+
+	t.Run("shutdown timeout", func(t *testing.T) {
+		service := kerneltest.NewServiceMock("slow-service")
+		service.CloseFunc = kerneltest.BlockUntilCancel
+		c := kernel.NewFakeClock(time.Time{})
+		app := New(WithService(service), WithShutdownTimeout(time.Minute), WithClock(c), WithDisableSignals())
+		app.logger = newTestLogManager(t)
+		app.Stop()
+
+		done := make(chan error, 1)
+		go func() { done <- app.Serve(context.Background()) }()
+		c.BlockUntilWaiters(1)
+		c.Advance(time.Minute)
+		<-done
+		assert.Equal(t, OutcomeShutdownTimeout, app.Outcome())
+		assert.Equal(t, 2, app.ExitCode())
+	})
+}
+
+// TestWithExitCodes 测试 WithExitCodes 只覆盖指定结果的退出码
+func TestWithExitCodes(t *testing.T) {
+	codes := map[Outcome]int{OutcomeError: 70}
+	app, err := serveOutcome(t, false,
+		WithService(newFailingRunner("runner", errors.New("boom"))),
+		WithExitCodes(codes),
+		WithExitCodes(map[Outcome]int{OutcomeShutdownTimeout: 75}),
+	)
+	require.Error(t, err)
+	assert.Equal(t, 70, app.ExitCode())
+	assert.Equal(t, map[Outcome]int{OutcomeError: 70, OutcomeShutdownTimeout: 75}, app.exitCodes)
+
+	// 修改传入的 map 不影响应用
+	codes[OutcomeError] = 3
+	assert.Equal(t, 70, app.ExitCode())
+
+	app.setOutcome(OutcomeClean)
+	assert.Equal(t, 0, app.ExitCode())
+	app.setOutcome(OutcomeBootFailed)
+	assert.Equal(t, 1, app.ExitCode())
+
+	// DefaultExitCodes 返回副本
+	DefaultExitCodes()[OutcomeClean] = 9
+	assert.Equal(t, 0, DefaultExitCodes()[OutcomeClean])
+}
+
+// TestDrugo_Execute_Outcome 测试不经过 Serve 的子命令按返回的错误记录结果
+func TestDrugo_Execute_Outcome(t *testing.T) {
+	app := New(WithOutput(io.Discard))
+	app.logger = newTestLogManager(t)
+	app.Command("fail", "always fails", func(ctx context.Context, k kernel.Kernel, args []string) error {
+		return errors.New("boom")
+	})
+	require.Error(t, app.Execute(context.Background(), []string{"app", "fail"}))
+	assert.Equal(t, OutcomeError, app.Outcome())
+	assert.Equal(t, 1, app.ExitCode())
+
+	app = New(WithOutput(io.Discard))
+	app.logger = newTestLogManager(t)
+	require.NoError(t, app.Execute(context.Background(), []string{"app", "help"}))
+	assert.Equal(t, OutcomeClean, app.Outcome())
+
+	app = New(WithOutput(io.Discard))
+	assert.ErrorIs(t, app.Execute(context.Background(), []string{"app", "nope"}), ErrUnknownCommand)
+	assert.Equal(t, 1, app.ExitCode())
+}
+
+// TestExit 测试 Exit 以 ExitCode 退出进程
+func TestExit(t *testing.T) {
+	var code = -1
+	orig := osExit
+	osExit = func(c int) { code = c }
+	t.Cleanup(func() { osExit = orig })
+
+	app, _ := serveOutcome(t, false, WithService(newFailingRunner("runner", errors.New("boom"))))
+	Exit(app)
+	assert.Equal(t, 1, code)
+}
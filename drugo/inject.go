@@ -0,0 +1,283 @@
+package drugo
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"runtime"
+	"strings"
+
+	"github.com/qq1060656096/drugo/config"
+	"github.com/qq1060656096/drugo/kernel"
+	"github.com/qq1060656096/drugo/log"
+	"go.uber.org/zap"
+)
+
+// 构造函数可以直接声明的内置参数类型
+var (
+	kernelType = reflect.TypeFor[kernel.Kernel]()
+	configType = reflect.TypeFor[*config.Manager]()
+	logType    = reflect.TypeFor[*log.Manager]()
+	errorType  = reflect.TypeFor[error]()
+)
+
+// WithProvider 注册一个构造函数，构造函数的参数由其他构造函数的返回值注入，类似 wire 的依赖注入：
+//
+//	func NewDB(cfg *config.Manager) (*sql.DB, error)
+//	func NewUserRepo(db *sql.DB) *UserRepo
+//	func NewUserService(repo *UserRepo) *UserService // UserService 实现了 kernel.Service
+//
+//	app := drugo.MustNewApp(
+//		drugo.WithProvider(NewDB),
+//		drugo.WithProvider(NewUserRepo),
+//		drugo.WithProvider(NewUserService),
+//	)
+//
+// fn 必须是非变参函数，返回 (T) 或 (T, error)，每个类型 T 只能由一个构造函数返回。
+// 参数除了其他构造函数返回的类型，还可以是内置的 kernel.Kernel（应用本身）、*config.Manager 与 *log.Manager，
+// 后两者只有通过 MustNewApp 创建应用时才可用。
+//
+// 创建应用时先检查所有构造函数：签名无效、参数类型没有构造函数返回、多个构造函数返回同一类型
+// 以及循环依赖的错误（分别包装 ErrInvalidProvider、ErrProviderMissing、ErrProviderDuplicate、ErrProviderCycle）
+// 会与其他注册错误一起合并返回，此时不会调用任何构造函数。
+// 检查通过后按依赖顺序调用每个构造函数一次，依赖相同时保持注册顺序；构造函数返回错误时
+// 停止创建应用并返回包装了 ErrServiceProvider 的错误。
+// 返回值实现了 kernel.Service 时按 Name() 注册到容器中，排在 WithService 注册的服务之后；
+// 其他返回值可以通过 Provided 获取
+func WithProvider(fn any) Option {
+	return func(o *options) {
+		o.constructors = append(o.constructors, fn)
+	}
+}
+
+// Provided 返回 WithProvider 注册的构造函数创建的 T 类型的值，没有构造函数返回 T 时返回 ErrNotProvided
+func Provided[T any](app *Drugo) (T, error) {
+	var zero T
+	typ := reflect.TypeFor[T]()
+	v, ok := app.provided[typ]
+	if !ok {
+		return zero, fmt.Errorf("%w: %s", ErrNotProvided, typ)
+	}
+	value, _ := v.Interface().(T) // 接口类型的 nil 值断言失败，返回零值
+	return value, nil
+}
+
+// constructor 是解析后的构造函数
+type constructor struct {
+	fn       reflect.Value
+	name     string
+	params   []reflect.Type
+	out      reflect.Type
+	hasError bool
+}
+
+// injector 保存按依赖顺序排列的构造函数
+type injector struct {
+	ordered []*constructor
+}
+
+// newInjector 解析构造函数并计算调用顺序，返回所有签名与依赖关系错误
+func newInjector(fns []any) (*injector, []error) {
+	var (
+		errs  []error
+		ctors []*constructor
+		byOut = make(map[reflect.Type]*constructor)
+	)
+	for i, fn := range fns {
+		c, err := parseConstructor(fn)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%w: provider #%d: %w", ErrInvalidProvider, i+1, err))
+			continue
+		}
+		if prev, ok := byOut[c.out]; ok {
+			errs = append(errs, fmt.Errorf("%w: %s is returned by both %s and %s", ErrProviderDuplicate, c.out, prev.name, c.name))
+			continue
+		}
+		byOut[c.out] = c
+		ctors = append(ctors, c)
+	}
+	for _, c := range ctors {
+		for _, param := range c.params {
+			if _, ok := byOut[param]; !ok && !isBuiltinParam(param) {
+				errs = append(errs, fmt.Errorf("%w: %s requires %s, which no provider returns", ErrProviderMissing, c.name, param))
+			}
+		}
+	}
+
+	// 深度优先遍历，后序即依赖顺序；遇到正在访问的构造函数说明存在循环依赖
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := make(map[*constructor]int, len(ctors))
+	var (
+		ordered []*constructor
+		path    []*constructor
+		visit   func(c *constructor)
+	)
+	visit = func(c *constructor) {
+		switch state[c] {
+		case visited:
+			return
+		case visiting:
+			errs = append(errs, fmt.Errorf("%w: %s", ErrProviderCycle, cyclePath(path, c)))
+			return
+		}
+		state[c] = visiting
+		path = append(path, c)
+		for _, param := range c.params {
+			if dep, ok := byOut[param]; ok {
+				visit(dep)
+			}
+		}
+		path = path[:len(path)-1]
+		state[c] = visited
+		ordered = append(ordered, c)
+	}
+	for _, c := range ctors {
+		visit(c)
+	}
+	return &injector{ordered: ordered}, errs
+}
+
+// cyclePath 返回从 c 开始并回到 c 的循环依赖路径，例如 main.newA -> main.newB -> main.newA
+func cyclePath(path []*constructor, c *constructor) string {
+	var names []string
+	for i := len(path) - 1; i >= 0; i-- {
+		if path[i] == c {
+			for _, p := range path[i:] {
+				names = append(names, p.name)
+			}
+			break
+		}
+	}
+	return strings.Join(append(names, c.name), " -> ")
+}
+
+// parseConstructor 检查 fn 的签名并解析参数与返回值类型
+func parseConstructor(fn any) (*constructor, error) {
+	v := reflect.ValueOf(fn)
+	if v.Kind() != reflect.Func || v.IsNil() {
+		return nil, fmt.Errorf("%T is not a function", fn)
+	}
+	name := funcName(v)
+	typ := v.Type()
+	if typ.IsVariadic() {
+		return nil, fmt.Errorf("%s must not be variadic", name)
+	}
+	c := &constructor{fn: v, name: name}
+	switch {
+	case typ.NumOut() == 1:
+	case typ.NumOut() == 2 && typ.Out(1) == errorType:
+		c.hasError = true
+	default:
+		return nil, fmt.Errorf("%s must return (T) or (T, error)", name)
+	}
+	c.out = typ.Out(0)
+	if c.out == errorType || isBuiltinParam(c.out) {
+		return nil, fmt.Errorf("%s must not return %s", name, c.out)
+	}
+	for i := range typ.NumIn() {
+		c.params = append(c.params, typ.In(i))
+	}
+	return c, nil
+}
+
+// isBuiltinParam 判断参数类型是否由应用本身提供
+func isBuiltinParam(typ reflect.Type) bool {
+	return typ == kernelType || typ == configType || typ == logType
+}
+
+// funcName 返回函数的完整名称，例如 main.newRedis
+func funcName(v reflect.Value) string {
+	if fn := runtime.FuncForPC(v.Pointer()); fn != nil {
+		return fn.Name()
+	}
+	return "provider"
+}
+
+// withDeferredProviders 推迟到 MustNewApp 创建配置与日志管理器之后再调用构造函数
+func withDeferredProviders() Option {
+	return func(o *options) {
+		o.deferProviders = true
+	}
+}
+
+// invoke 按依赖顺序调用构造函数，将实现了 kernel.Service 的返回值注册到容器中
+func (in *injector) invoke(d *Drugo, strictNames bool) error {
+	builtins := map[reflect.Type]reflect.Value{kernelType: reflect.ValueOf(d)}
+	if d.config != nil {
+		builtins[configType] = reflect.ValueOf(d.config)
+	}
+	if d.logger != nil {
+		builtins[logType] = reflect.ValueOf(d.logger)
+	}
+	var errs []error
+	for _, c := range in.ordered {
+		for _, param := range c.params {
+			if _, ok := builtins[param]; isBuiltinParam(param) && !ok {
+				errs = append(errs, fmt.Errorf("%w: %s requires %s, which is only available with MustNewApp",
+					ErrProviderMissing, c.name, param))
+			}
+		}
+	}
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+
+	d.provided = make(map[reflect.Type]reflect.Value, len(in.ordered))
+	d.providerDeps = make(map[string][]string)
+	// serviceDeps 是每个返回值通过参数（包括经由非服务类型的间接参数）依赖的服务名称
+	serviceDeps := make(map[reflect.Type][]string, len(in.ordered))
+	for _, c := range in.ordered {
+		args := make([]reflect.Value, len(c.params))
+		for i, param := range c.params {
+			if v, ok := builtins[param]; ok {
+				args[i] = v
+			} else {
+				args[i] = d.provided[param]
+			}
+		}
+		results := c.fn.Call(args)
+		if c.hasError {
+			if err, _ := results[1].Interface().(error); err != nil {
+				return fmt.Errorf("%w: %s: %w", ErrServiceProvider, c.name, err)
+			}
+		}
+		d.provided[c.out] = results[0]
+
+		var deps []string
+		for _, param := range c.params {
+			v, ok := d.provided[param]
+			if !ok {
+				continue // 内置参数
+			}
+			if dep, ok := v.Interface().(kernel.Service); ok && !isNilService(dep) {
+				deps = append(deps, dep.Name())
+			} else {
+				deps = append(deps, serviceDeps[param]...)
+			}
+		}
+		serviceDeps[c.out] = deps
+
+		service, ok := results[0].Interface().(kernel.Service)
+		if !ok {
+			continue
+		}
+		if isNilService(service) {
+			return fmt.Errorf("%w: %s returned nil", ErrNilService, c.name)
+		}
+		if err := checkServiceName(service.Name(), d.frameworkLogName); err != nil {
+			if strictNames {
+				return err
+			}
+			d.frameworkLogger().Warn("invalid service name", zap.Error(err))
+		}
+		d.Container().Bind(service.Name(), service)
+		if len(deps) > 0 {
+			d.providerDeps[service.Name()] = deps
+		}
+	}
+	return nil
+}
@@ -0,0 +1,195 @@
+package drugo
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/qq1060656096/drugo/config"
+	"github.com/qq1060656096/drugo/kernel"
+	"github.com/qq1060656096/drugo/kernel/kerneltest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// 依赖注入测试使用的类型
+type (
+	injectConfig struct{ dsn string }
+	injectDB     struct{ cfg *injectConfig }
+	injectCache  struct{ cfg *injectConfig }
+	injectRepo   struct {
+		db    *injectDB
+		cache *injectCache
+	}
+	injectA struct{}
+	injectB struct{}
+)
+
+// injectService 是由构造函数创建的测试服务
+type injectService struct {
+	*kerneltest.ServiceMock
+	repo *injectRepo
+}
+
+func newInjectConfig() *injectConfig                { return &injectConfig{dsn: "memory"} }
+func newInjectDB(cfg *injectConfig) *injectDB       { return &injectDB{cfg: cfg} }
+func newInjectCache(cfg *injectConfig) *injectCache { return &injectCache{cfg: cfg} }
+func newInjectA(*injectB) *injectA                  { return &injectA{} }
+func newInjectB(*injectA) *injectB                  { return &injectB{} }
+func newInjectRepo(db *injectDB, cache *injectCache) *injectRepo {
+	return &injectRepo{db: db, cache: cache}
+}
+
+// TestWithProvider_Chain 测试线性依赖链：注册顺序与依赖顺序相反时按依赖顺序调用
+func TestWithProvider_Chain(t *testing.T) {
+	var order []string
+	app, err := NewE(
+		WithProvider(func(db *injectDB) *injectRepo { order = append(order, "repo"); return &injectRepo{db: db} }),
+		WithProvider(func(cfg *injectConfig) (*injectDB, error) { order = append(order, "db"); return newInjectDB(cfg), nil }),
+		WithProvider(func() *injectConfig { order = append(order, "config"); return newInjectConfig() }),
+	)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"config", "db", "repo"}, order)
+
+	repo, err := Provided[*injectRepo](app)
+	require.NoError(t, err)
+	cfg, err := Provided[*injectConfig](app)
+	require.NoError(t, err)
+	assert.Same(t, cfg, repo.db.cfg)
+	assert.Equal(t, "memory", repo.db.cfg.dsn)
+}
+
+// TestWithProvider_Diamond 测试菱形依赖：共享的依赖只创建一次
+func TestWithProvider_Diamond(t *testing.T) {
+	calls := 0
+	app, err := NewE(
+		WithProvider(newInjectRepo),
+		WithProvider(newInjectDB),
+		WithProvider(newInjectCache),
+		WithProvider(func() *injectConfig { calls++; return newInjectConfig() }),
+	)
+	require.NoError(t, err)
+	assert.Equal(t, 1, calls)
+
+	repo, err := Provided[*injectRepo](app)
+	require.NoError(t, err)
+	assert.Same(t, repo.db.cfg, repo.cache.cfg)
+}
+
+// TestWithProvider_GraphErrors 测试依赖缺失、重复与循环依赖的错误会合并返回，并且不调用任何构造函数
+func TestWithProvider_GraphErrors(t *testing.T) {
+	called := false
+	_, err := NewE(
+		WithProvider(func() *injectConfig { called = true; return newInjectConfig() }),
+		WithProvider(newInjectRepo), // 缺少 *injectCache
+		WithProvider(newInjectDB),
+		WithProvider(func(cfg *injectConfig) *injectDB { return newInjectDB(cfg) }),
+		WithProvider(newInjectA),
+		WithProvider(newInjectB),
+	)
+	require.Error(t, err)
+	assert.False(t, called)
+
+	assert.ErrorIs(t, err, ErrProviderMissing)
+	assert.Contains(t, err.Error(), "drugo.newInjectRepo requires *drugo.injectCache, which no provider returns")
+	assert.ErrorIs(t, err, ErrProviderDuplicate)
+	assert.Contains(t, err.Error(), "*drugo.injectDB is returned by both github.com/qq1060656096/drugo/drugo.newInjectDB and")
+	assert.ErrorIs(t, err, ErrProviderCycle)
+	assert.Contains(t, err.Error(), "drugo.newInjectA -> github.com/qq1060656096/drugo/drugo.newInjectB -> github.com/qq1060656096/drugo/drugo.newInjectA")
+}
+
+// TestWithProvider_Invalid 测试签名无效的构造函数
+func TestWithProvider_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		fn   any
+		msg  string
+	}{
+		{"nil", nil, "<nil> is not a function"},
+		{"不是函数", "db", "string is not a function"},
+		{"变参", func(...int) *injectA { return nil }, "must not be variadic"},
+		{"没有返回值", func() {}, "must return (T) or (T, error)"},
+		{"第二个返回值不是 error", func() (*injectA, int) { return nil, 0 }, "must return (T) or (T, error)"},
+		{"只返回 error", func() error { return nil }, "must not return error"},
+		{"返回内置类型", func() kernel.Kernel { return nil }, "must not return kernel.Kernel"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewE(WithProvider(tt.fn))
+			require.ErrorIs(t, err, ErrInvalidProvider)
+			assert.Contains(t, err.Error(), "provider #1")
+			assert.Contains(t, err.Error(), tt.msg)
+		})
+	}
+}
+
+// TestWithProvider_Error 测试构造函数返回错误时停止创建应用，之后的构造函数不会被调用
+func TestWithProvider_Error(t *testing.T) {
+	errDial := errors.New("dial failed")
+	repoCalled := false
+	_, err := NewE(
+		WithProvider(newInjectConfig),
+		WithProvider(func(*injectConfig) (*injectDB, error) { return nil, errDial }),
+		WithProvider(func(db *injectDB) *injectRepo { repoCalled = true; return &injectRepo{db: db} }),
+	)
+	require.ErrorIs(t, err, ErrServiceProvider)
+	assert.ErrorIs(t, err, errDial)
+	assert.Contains(t, err.Error(), "drugo.TestWithProvider_Error.func1")
+	assert.False(t, repoCalled)
+}
+
+// TestWithProvider_Service 测试实现了 kernel.Service 的返回值注册到容器中，并参与 Boot 与 Shutdown
+func TestWithProvider_Service(t *testing.T) {
+	var got kernel.Kernel
+	plain := kerneltest.NewServiceMock("plain")
+	app, err := NewE(
+		WithService(plain),
+		WithProvider(func(k kernel.Kernel, repo *injectRepo) *injectService {
+			got = k
+			return &injectService{ServiceMock: kerneltest.NewServiceMock("users"), repo: repo}
+		}),
+		WithProvider(newInjectRepo),
+		WithProvider(newInjectDB),
+		WithProvider(newInjectCache),
+		WithProvider(newInjectConfig),
+	)
+	require.NoError(t, err)
+	app.logger = newTestLogManager(t)
+	assert.Same(t, app, got)
+	assert.Equal(t, []string{"plain", "users"}, app.serviceNames())
+
+	svc, err := Provided[*injectService](app)
+	require.NoError(t, err)
+	assert.NotNil(t, svc.repo.db)
+
+	require.NoError(t, app.Boot(context.Background()))
+	assert.True(t, svc.Booted())
+	require.NoError(t, app.Shutdown(context.Background()))
+	assert.True(t, svc.Closed())
+}
+
+// TestWithProvider_Builtins 测试 *config.Manager 只有通过 MustNewApp 创建应用时才能注入
+func TestWithProvider_Builtins(t *testing.T) {
+	var got *config.Manager
+	newConfig := func(cfg *config.Manager) *injectConfig { got = cfg; return newInjectConfig() }
+	_, err := NewE(WithProvider(newConfig))
+	require.ErrorIs(t, err, ErrProviderMissing)
+	assert.Contains(t, err.Error(), "only available with MustNewApp")
+
+	root := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "conf"), 0755))
+	app := MustNewApp(WithRoot(root), WithAllowEmptyConfig(), WithQuiet(true), WithProvider(newConfig))
+	_, err = Provided[*injectConfig](app)
+	require.NoError(t, err)
+	assert.Same(t, app.Config(), got)
+}
+
+// TestProvided_NotProvided 测试查询没有构造函数返回的类型
+func TestProvided_NotProvided(t *testing.T) {
+	app := New(WithProvider(newInjectConfig))
+	_, err := Provided[*injectDB](app)
+	require.ErrorIs(t, err, ErrNotProvided)
+	assert.Contains(t, err.Error(), "*drugo.injectDB")
+}
@@ -0,0 +1,108 @@
+package drugo
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/qq1060656096/drugo/kernel"
+	"github.com/qq1060656096/drugo/log"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// frameworkLogger 返回框架生命周期日志（Boot/Run/Shutdown/Serve 等）使用的 logger。
+//
+// 默认从日志管理器获取业务名称为 "drugo" 的 logger（可通过 WithFrameworkLogName 修改），
+// 与应用自己的业务日志分开；日志管理器为 nil 或获取失败时回退到只输出到控制台的 logger，
+// 保证生命周期不会因为缺少日志配置而 panic。
+// 设置了 WithFrameworkLogLevel 或处于安静模式时，首次获取后通过 Manager.SetLevel 应用该级别（见 frameworkLevel）。
+func (d *Drugo) frameworkLogger() *zap.Logger {
+	m := d.Logger()
+	if m == nil {
+		return d.fallbackLogger()
+	}
+
+	name := d.frameworkLogNameOrDefault()
+	l, err := m.Get(name)
+	if err != nil {
+		fallback := d.fallbackLogger()
+		fallback.Warn("framework logger unavailable, fallback to console", zap.String("name", name), zap.Error(err))
+		return fallback
+	}
+
+	d.fwLogMu.Lock()
+	defer d.fwLogMu.Unlock()
+	if level := d.frameworkLevel(); level != "" && d.fwLevelApplied != m {
+		d.fwLevelApplied = m
+		if err := m.SetLevel(name, level); err != nil {
+			l.Warn("framework log level not applied", zap.String("level", level), zap.Error(err))
+		}
+	}
+	return l
+}
+
+// fallbackLogger 返回只输出到控制台的框架 logger，首次调用时创建。
+func (d *Drugo) fallbackLogger() *zap.Logger {
+	d.fwLogMu.Lock()
+	defer d.fwLogMu.Unlock()
+	if d.fwFallback != nil {
+		return d.fwFallback
+	}
+
+	cfg := log.Config{
+		Level:   d.frameworkLevel(),
+		Outputs: []log.OutputConfig{{Type: log.OutputTypeConsole, Format: log.FormatText}},
+	}
+	l, _, err := log.NewZapLogger(cfg, d.frameworkLogNameOrDefault())
+	if err != nil {
+		// 级别无效时使用默认级别
+		cfg.Level = ""
+		l, _, _ = log.NewZapLogger(cfg, d.frameworkLogNameOrDefault())
+	}
+	d.fwFallback = l
+	return l
+}
+
+// frameworkLevel 返回框架 logger 需要应用的级别，为空表示使用日志配置中的级别。
+// 安静模式下级别至少为 warn，WithFrameworkLogLevel 设置了更高的级别时使用更高的级别。
+func (d *Drugo) frameworkLevel() string {
+	if !d.quiet {
+		return d.frameworkLogLevel
+	}
+	if lvl, err := zapcore.ParseLevel(d.frameworkLogLevel); err == nil && lvl > zapcore.WarnLevel {
+		return d.frameworkLogLevel
+	}
+	return zapcore.WarnLevel.String()
+}
+
+// frameworkLogNameOrDefault 返回框架 logger 的业务名称，未设置时使用 "drugo"
+func (d *Drugo) frameworkLogNameOrDefault() string {
+	if d.frameworkLogName == "" {
+		return logName
+	}
+	return d.frameworkLogName
+}
+
+// injectServiceLogger 在 Boot 之前以服务名称为业务名称获取服务自己的 logger，
+// 对实现了 kernel.LoggerAware 的服务调用 SetLogger，并返回携带该 logger 的上下文。
+// 获取失败时返回的错误由 bootService 包装为 kernel.ErrServiceInitFailed。
+func (d *Drugo) injectServiceLogger(ctx context.Context, service kernel.Service) (context.Context, error) {
+	l, err := d.Logger().Get(service.Name())
+	if err != nil {
+		return ctx, fmt.Errorf("logger: %w", err)
+	}
+	if la, ok := service.(kernel.LoggerAware); ok {
+		la.SetLogger(l)
+	}
+	return kernel.WithServiceLogger(ctx, l), nil
+}
+
+// withServiceLogger 返回携带服务 logger 的上下文，用于 Run/Drain/Close；
+// 获取失败时原样返回 ctx（kernel.ServiceLoggerFromContext 会回退到 Nop logger）。
+func (d *Drugo) withServiceLogger(ctx context.Context, service kernel.Service) context.Context {
+	l, err := d.Logger().Get(service.Name())
+	if err != nil {
+		return ctx
+	}
+	return kernel.WithServiceLogger(ctx, l)
+}
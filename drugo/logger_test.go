@@ -0,0 +1,294 @@
+package drugo
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/qq1060656096/drugo/kernel"
+	"github.com/qq1060656096/drugo/kernel/kerneltest"
+	"github.com/qq1060656096/drugo/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// TestDrugo_FrameworkLogger_NilManager 测试没有日志管理器时生命周期不会 panic
+func TestDrugo_FrameworkLogger_NilManager(t *testing.T) {
+	app := New(WithService(kerneltest.NewRunnerMock("runner")))
+	require.Nil(t, app.Logger())
+
+	assert.NotPanics(t, func() {
+		require.NoError(t, app.Boot(context.Background()))
+		require.NoError(t, app.Run(context.Background()))
+		require.NoError(t, app.Shutdown(context.Background()))
+	})
+	assert.Same(t, app.frameworkLogger(), app.frameworkLogger())
+}
+
+// TestDrugo_FrameworkLogger_File 测试框架日志写入独立的业务日志文件
+func TestDrugo_FrameworkLogger_File(t *testing.T) {
+	t.Run("default name", func(t *testing.T) {
+		m, dir := newFileTestLogManager(t)
+		app := New(WithService(kerneltest.NewServiceMock("db")))
+		app.logger = m
+
+		require.NoError(t, app.Boot(context.Background()))
+		require.NoError(t, m.Sync())
+
+		data, err := os.ReadFile(filepath.Join(dir, "drugo.log"))
+		require.NoError(t, err)
+		assert.Contains(t, string(data), "framework boot complete")
+		assert.NoFileExists(t, filepath.Join(dir, "app.log"))
+	})
+
+	t.Run("custom name", func(t *testing.T) {
+		m, dir := newFileTestLogManager(t)
+		app := New(WithService(kerneltest.NewServiceMock("db")), WithFrameworkLogName("framework"))
+		app.logger = m
+
+		require.NoError(t, app.Boot(context.Background()))
+		require.NoError(t, m.Sync())
+
+		assert.FileExists(t, filepath.Join(dir, "framework.log"))
+		assert.NoFileExists(t, filepath.Join(dir, "drugo.log"))
+	})
+}
+
+// TestDrugo_FrameworkLogger_Level 测试框架日志级别独立于应用日志级别
+func TestDrugo_FrameworkLogger_Level(t *testing.T) {
+	m, dir := newFileTestLogManager(t)
+	app := New(WithService(kerneltest.NewServiceMock("db")), WithFrameworkLogLevel("warn"))
+	app.logger = m
+
+	require.NoError(t, app.Boot(context.Background()))
+	m.MustGet("app").Info("app info")
+	require.NoError(t, m.Sync())
+
+	level, err := m.GetLevel(logName)
+	require.NoError(t, err)
+	assert.Equal(t, "warn", level)
+	level, err = m.GetLevel("app")
+	require.NoError(t, err)
+	assert.Equal(t, "info", level)
+
+	data, _ := os.ReadFile(filepath.Join(dir, "drugo.log"))
+	assert.NotContains(t, string(data), "framework boot complete")
+	assert.FileExists(t, filepath.Join(dir, "app.log"))
+}
+
+// loggerAwareService 通过 kernel.LoggerAware 接收框架注入的 logger
+type loggerAwareService struct {
+	*kerneltest.ServiceMock
+	logger *zap.Logger
+}
+
+func (s *loggerAwareService) SetLogger(l *zap.Logger) { s.logger = l }
+
+func (s *loggerAwareService) Boot(ctx context.Context) error {
+	s.logger.Info("aware booted")
+	return nil
+}
+
+// ctxLoggerService 通过 kernel.ServiceLoggerFromContext 使用框架注入的 logger
+type ctxLoggerService struct {
+	*kerneltest.ServiceMock
+}
+
+func (s *ctxLoggerService) Boot(ctx context.Context) error {
+	kernel.ServiceLoggerFromContext(ctx).Info("ctx booted")
+	return nil
+}
+
+func (s *ctxLoggerService) Run(ctx context.Context) error {
+	kernel.ServiceLoggerFromContext(ctx).Info("ctx running")
+	return nil
+}
+
+func (s *ctxLoggerService) Close(ctx context.Context) error {
+	kernel.ServiceLoggerFromContext(ctx).Info("ctx closed")
+	return nil
+}
+
+// readLogEntries 读取 JSON 格式的日志文件
+func readLogEntries(t *testing.T, path string) []map[string]any {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	var entries []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+		var entry map[string]any
+		require.NoError(t, json.Unmarshal([]byte(line), &entry))
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+// TestDrugo_ServiceLogger_Injection 测试两种注入方式，且每个服务的日志带有自己的 biz 字段
+func TestDrugo_ServiceLogger_Injection(t *testing.T) {
+	m, dir := newFileTestLogManager(t)
+	aware := &loggerAwareService{ServiceMock: kerneltest.NewServiceMock("aware")}
+	fromCtx := &ctxLoggerService{ServiceMock: kerneltest.NewServiceMock("ctxsvc")}
+	app := New(WithService(aware), WithService(fromCtx))
+	app.logger = m
+
+	require.NoError(t, app.Boot(context.Background()))
+	require.NoError(t, app.Run(context.Background()))
+	require.NoError(t, app.Shutdown(context.Background()))
+	require.NoError(t, m.Sync())
+
+	assert.Same(t, m.MustGet("aware"), aware.logger)
+
+	tests := []struct {
+		biz  string
+		msgs []string
+	}{
+		{biz: "aware", msgs: []string{"aware booted"}},
+		{biz: "ctxsvc", msgs: []string{"ctx booted", "ctx running", "ctx closed"}},
+	}
+	for _, tt := range tests {
+		entries := readLogEntries(t, filepath.Join(dir, tt.biz+".log"))
+		var msgs []string
+		for _, entry := range entries {
+			assert.Equal(t, tt.biz, entry["biz"])
+			msgs = append(msgs, entry["msg"].(string))
+		}
+		assert.Equal(t, tt.msgs, msgs)
+	}
+}
+
+// TestDrugo_ServiceLogger_Error 测试获取服务 logger 失败时 Boot 返回 ErrServiceInitFailed
+func TestDrugo_ServiceLogger_Error(t *testing.T) {
+	svc := kerneltest.NewServiceMock("")
+	app := New()
+	app.logger = newTestLogManager(t)
+
+	_, err := app.injectServiceLogger(context.Background(), svc)
+	require.Error(t, err)
+	assert.ErrorIs(t, err, log.ErrEmptyBizName)
+
+	err = app.bootService(context.Background(), app.frameworkLogger(), nil, svc)
+	assert.True(t, kernel.IsServiceInitFailed(err))
+	assert.ErrorIs(t, err, log.ErrEmptyBizName)
+	assert.Zero(t, svc.BootCount())
+}
+
+// TestDrugo_Quiet 测试安静模式下不输出 info 级别的框架日志，服务自己的 info 日志不受影响
+func TestDrugo_Quiet(t *testing.T) {
+	m, dir := newFileTestLogManager(t)
+	svc := kerneltest.NewServiceMock("db")
+	svc.BootFunc = func(ctx context.Context) error {
+		kernel.ServiceLoggerFromContext(ctx).Info("db booted")
+		return nil
+	}
+	app := New(WithService(svc), WithQuiet(true))
+	app.logger = m
+
+	require.NoError(t, app.Boot(context.Background()))
+	app.frameworkLogger().Warn("framework warn")
+	require.NoError(t, m.Sync())
+
+	for _, entry := range readLogEntries(t, filepath.Join(dir, "drugo.log")) {
+		assert.NotEqual(t, "info", entry["level"], entry["msg"])
+	}
+	entries := readLogEntries(t, filepath.Join(dir, "db.log"))
+	require.Len(t, entries, 1)
+	assert.Equal(t, "db booted", entries[0]["msg"])
+
+	assert.True(t, app.Quiet())
+	assert.True(t, app.BootReport().Quiet)
+}
+
+// TestDrugo_frameworkLevel 测试安静模式下框架日志的级别至少为 warn
+func TestDrugo_frameworkLevel(t *testing.T) {
+	tests := []struct {
+		quiet bool
+		level string
+		want  string
+	}{
+		{quiet: false, level: "", want: ""},
+		{quiet: false, level: "debug", want: "debug"},
+		{quiet: true, level: "", want: "warn"},
+		{quiet: true, level: "debug", want: "warn"},
+		{quiet: true, level: "error", want: "error"},
+	}
+	for _, tt := range tests {
+		app := New(WithQuiet(tt.quiet), WithFrameworkLogLevel(tt.level))
+		assert.Equal(t, tt.want, app.frameworkLevel(), "quiet=%v level=%q", tt.quiet, tt.level)
+	}
+}
+
+// TestMustNewApp_Quiet 测试 DRUGO_QUIET 环境变量、WithQuiet 的优先级以及只输出到控制台
+func TestMustNewApp_Quiet(t *testing.T) {
+	root := t.TempDir()
+	conf := filepath.Join(root, "conf")
+	require.NoError(t, os.MkdirAll(conf, 0755))
+	content := "log:\n  outputs:\n    - type: file\n      format: json\n"
+	require.NoError(t, os.WriteFile(filepath.Join(conf, "log.yaml"), []byte(content), 0644))
+
+	t.Run("环境变量开启安静模式", func(t *testing.T) {
+		t.Setenv(QuietEnvVar, "true")
+		app := MustNewApp(WithRoot(root), WithLogConsoleOnlyInQuiet(true))
+		assert.True(t, app.Quiet())
+		require.Len(t, app.logConfig.Outputs, 1)
+		assert.Equal(t, log.OutputTypeConsole, app.logConfig.Outputs[0].Type)
+
+		app.Logger().MustGet("app").Info("cli output")
+		assert.NoDirExists(t, filepath.Join(root, "runtime", "logs"))
+	})
+
+	t.Run("WithQuiet 优先于环境变量", func(t *testing.T) {
+		t.Setenv(QuietEnvVar, "true")
+		app := MustNewApp(WithRoot(root), WithQuiet(false), WithLogConsoleOnlyInQuiet(true))
+		assert.False(t, app.Quiet())
+		assert.Equal(t, log.OutputTypeFile, app.logConfig.Outputs[0].Type)
+		require.NoError(t, app.Logger().Close())
+	})
+}
+
+// TestMustNewApp_LogEnvOverrides 测试 DRUGO_LOG_* 环境变量覆盖配置文件中的日志配置，无效的值记录警告后被忽略
+func TestMustNewApp_LogEnvOverrides(t *testing.T) {
+	root := t.TempDir()
+	conf := "log:\n  level: warn\n  outputs:\n    - type: file\n      format: json\n      file:\n        dir: logs\n"
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "conf"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "conf", "log.yaml"), []byte(conf), 0644))
+	t.Setenv(LogEnvPrefix+"_LEVEL", "info")
+	t.Setenv(LogEnvPrefix+"_LEVEL_GIN", "error")
+	t.Setenv(LogEnvPrefix+"_FORMAT", "yaml")
+	t.Setenv(LogEnvPrefix+"_DIR", "debug-logs")
+
+	app := MustNewApp(WithRoot(root), WithService(kerneltest.NewServiceMock("db")))
+	t.Cleanup(func() { _ = app.Logger().Close() })
+	assert.Equal(t, "info", app.logConfig.Level)
+	assert.Equal(t, log.FormatJSON, app.logConfig.Outputs[0].Format)
+	assert.Equal(t, filepath.Join(root, "debug-logs"), app.logConfig.Outputs[0].File.Dir)
+	level, pinned, err := app.Logger().LevelInfo("gin")
+	require.NoError(t, err)
+	assert.Equal(t, "error", level)
+	assert.True(t, pinned)
+
+	require.NoError(t, app.Boot(context.Background()))
+	assert.Equal(t, map[string]string{
+		"level":      "env:DRUGO_LOG_LEVEL",
+		"format":     log.SourceFile,
+		"console":    log.SourceFile,
+		"dir":        "env:DRUGO_LOG_DIR",
+		"levels.gin": "env:DRUGO_LOG_LEVEL_GIN",
+	}, app.BootReport().LogSources)
+
+	require.NoError(t, app.Logger().Sync())
+	data, err := os.ReadFile(filepath.Join(root, "debug-logs", "drugo.log"))
+	require.NoError(t, err)
+	var warning map[string]any
+	for _, line := range strings.Split(string(data), "\n") {
+		if strings.Contains(line, "log env override ignored") {
+			require.NoError(t, json.Unmarshal([]byte(line), &warning))
+		}
+	}
+	require.NotNil(t, warning, "invalid override is logged")
+	assert.Equal(t, "DRUGO_LOG_FORMAT", warning["var"])
+	assert.Equal(t, "yaml", warning["value"])
+}
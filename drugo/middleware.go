@@ -0,0 +1,25 @@
+package drugo
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/qq1060656096/drugo/kernel"
+)
+
+// GinMiddleware 返回将应用注入到每个请求的 gin 中间件：
+//   - c.Set(Name, d)，处理函数中可以通过 c.MustGet(drugo.Name) 获取应用
+//   - 将 c.Request 替换为上下文携带 Kernel 的请求（见 kernel.EnsureKernel）
+//
+// 处理函数把 c.Request.Context() 传给 service、biz、data 层之后，
+// 各层都可以直接使用 kernel.ServiceFromContext、kernel.LoggerFromContext 等基于上下文的辅助函数，
+// 无需向下传递 gin.Context。请求上下文中已有的元数据（见 router.MetaMiddleware）会被保留，
+// 与 MetaMiddleware 的注册顺序无关。
+func (d *Drugo) GinMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set(Name, d)
+		// 已经携带 Kernel 时不复制请求，避免多层中间件重复包装
+		if k, ok := kernel.FromContext(c.Request.Context()); !ok || k == nil {
+			c.Request = c.Request.WithContext(kernel.EnsureKernel(c.Request.Context(), d))
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,56 @@
+package drugo
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/qq1060656096/drugo/kernel"
+	"github.com/qq1060656096/drugo/kernel/kerneltest"
+	"github.com/qq1060656096/drugo/pkg/router"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// userService 模拟 handler 之下的 service 层，只通过 ctx 获取依赖
+type userService struct{}
+
+func (userService) Find(ctx context.Context) (string, error) {
+	db, err := kernel.ServiceFromContext[*kerneltest.ServiceMock](ctx, "db")
+	if err != nil {
+		return "", err
+	}
+	requestID, _ := kernel.Meta(ctx, kernel.MetaRequestID)
+	kernel.LoggerFromContext(ctx).Info("find user")
+	return db.Name() + ":" + requestID, nil
+}
+
+// TestDrugo_GinMiddleware 测试中间件将应用写入 gin 上下文与请求上下文，service 层可以直接使用 ctx
+func TestDrugo_GinMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	app := New(WithService(kerneltest.NewServiceMock("db")))
+	app.logger = newTestLogManager(t)
+
+	engine := gin.New()
+	// 嵌套注册两次，第二次不会重复包装请求
+	engine.Use(app.GinMiddleware(), router.MetaMiddleware(), app.GinMiddleware())
+	engine.GET("/user", func(c *gin.Context) {
+		assert.Same(t, app, c.MustGet(Name))
+		got, err := userService{}.Find(c.Request.Context())
+		if err != nil {
+			c.String(http.StatusInternalServerError, err.Error())
+			return
+		}
+		c.String(http.StatusOK, got)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/user", nil)
+	req.Header.Set(router.RequestIDHeader, "req-1")
+	w := httptest.NewRecorder()
+	engine.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, "db:req-1", w.Body.String())
+}
@@ -2,6 +2,9 @@ package drugo
 
 import (
 	"context"
+	"fmt"
+	"io"
+	"os"
 	"time"
 
 	"github.com/qq1060656096/drugo/kernel"
@@ -13,10 +16,48 @@ const DefaultShutdownTimeout = 10 * time.Second
 type options struct {
 	root string
 	// Changed to a simple map for easier registration
-	services        []map[string]kernel.Service
-	ctx             context.Context
-	shutdownTimeout time.Duration
-	configDir       string
+	services             []map[string]kernel.Service
+	ctx                  context.Context
+	shutdownTimeout      time.Duration
+	clock                kernel.Clock
+	configDir            string
+	optional             map[string]struct{}
+	configSections       map[string]string
+	signalHandlers       map[os.Signal][]SignalHandler
+	shutdownSignals      []os.Signal
+	disableSignals       bool
+	errorHandler         ErrorHandler
+	runRetryBackoff      time.Duration // 见 WithRunRetry
+	runRetryMaxDelay     time.Duration
+	runRetryAttempts     int
+	stdout               io.Writer
+	appEnv               string
+	drainTimeout         time.Duration
+	bootReportFile       string
+	frameworkLogName     string
+	frameworkLogLevel    string
+	allowEmptyConfig     bool
+	bootProgress         bool          // 见 WithBootProgress
+	bootProgressEvery    int           // 启动进度日志的服务间隔
+	bootProgressInterval time.Duration // 启动进度日志的时间间隔
+	configUsage          bool          // 见 WithConfigUsageTracking
+	configUsageIgnore    []string      // 不参与未使用配置项报告的键路径模式
+	probeClaims          bool
+	pidFile              string // 见 WithPIDFile
+	diagnosticsDir       string
+	diagnosticsCPU       time.Duration
+	diagnosticsRetention time.Duration
+	strictNames          bool
+	emptyContainerPolicy EmptyContainerPolicy
+	exitCodes            map[Outcome]int
+	quiet                bool
+	quietSet             bool // 是否显式设置了 WithQuiet，设置后 MustNewApp 不再读取 DRUGO_QUIET
+	quietConsoleOnly     bool
+	serviceCount         int                 // 已注册（包括注册失败）的服务数量，用于在错误中标识服务
+	serviceErrs          []error             // 注册服务时收集的错误，由 NewE 合并返回
+	providers            map[string]struct{} // 已实例化的注册 provider，见 WithRegisteredProviders
+	constructors         []any               // 通过 WithProvider 注册的构造函数
+	deferProviders       bool                // 由 MustNewApp 设置，在创建配置与日志管理器之后才调用构造函数
 }
 
 type Option func(*options)
@@ -33,8 +74,15 @@ func WithContext(ctx context.Context) Option {
 	}
 }
 
+// WithNameService 以 name 为名称注册一个服务。
+// service 为 nil 时不会注册，而是记录一个包装了 ErrNilService 的错误，由 NewE 返回。
 func WithNameService(name string, service kernel.Service) Option {
 	return func(o *options) {
+		o.serviceCount++
+		if isNilService(service) {
+			o.serviceErrs = append(o.serviceErrs, fmt.Errorf("%w: %q (service #%d)", ErrNilService, name, o.serviceCount))
+			return
+		}
 		if o.services == nil {
 			o.services = make([]map[string]kernel.Service, 0)
 		}
@@ -42,8 +90,27 @@ func WithNameService(name string, service kernel.Service) Option {
 	}
 }
 
+// WithService 以服务自身的名称注册一个服务。
+// service 为 nil 时不会注册，而是记录一个包装了 ErrNilService 的错误，由 NewE 返回。
 func WithService(service kernel.Service) Option {
-	return WithNameService(service.Name(), service)
+	return func(o *options) {
+		if isNilService(service) {
+			o.serviceCount++
+			o.serviceErrs = append(o.serviceErrs, fmt.Errorf("%w: service #%d", ErrNilService, o.serviceCount))
+			return
+		}
+		WithNameService(service.Name(), service)(o)
+	}
+}
+
+// WithClock 设置框架中基于时间的行为使用的时钟，包括停机超时、排空超时、StopRunner 的等待、诊断采集的 CPU profile 时长、
+// 诊断文件名中的时间与过期清理、启动耗时（见 Drugo.Timings）、启动报告与重载记录的时间，以及 MustNewApp 创建的配置管理器（防抖、远程轮询）与日志管理器（目录配额、归档扫描）。
+// 不设置或 c 为 nil 时使用 kernel.RealClock；测试中可以传入 kernel.NewFakeClock 创建的时钟，推进时间代替真实的 sleep。
+// 用户服务自身的定时逻辑不受影响，可以通过 Drugo.Clock 获取同一个时钟
+func WithClock(c kernel.Clock) Option {
+	return func(o *options) {
+		o.clock = c
+	}
 }
 
 // WithShutdownTimeout 设置优雅停机的超时时间
@@ -61,3 +128,138 @@ func WithConfigDir(configDir string) Option {
 		o.configDir = configDir
 	}
 }
+
+// WithOptionalService 注册一个可选服务。
+// 可选服务 Boot 失败时只记录警告并标记为降级，应用会在没有它的情况下继续启动。
+func WithOptionalService(service kernel.Service) Option {
+	return func(o *options) {
+		WithService(service)(o)
+		if isNilService(service) {
+			return
+		}
+		if o.optional == nil {
+			o.optional = make(map[string]struct{})
+		}
+		o.optional[service.Name()] = struct{}{}
+	}
+}
+
+// WithServiceConfig 注册一个服务，并指定注入给它的配置段名称。
+// 仅对实现了 kernel.Configurable 的服务生效；未指定时使用服务名称作为配置段名称。
+func WithServiceConfig(service kernel.Service, sectionName string) Option {
+	return func(o *options) {
+		WithService(service)(o)
+		if isNilService(service) {
+			return
+		}
+		if o.configSections == nil {
+			o.configSections = make(map[string]string)
+		}
+		o.configSections[service.Name()] = sectionName
+	}
+}
+
+// WithOutput 设置子命令（例如 routes、config、help）的输出目标
+// 如果不设置，默认输出到 os.Stdout
+func WithOutput(w io.Writer) Option {
+	return func(o *options) {
+		o.stdout = w
+	}
+}
+
+// WithAppEnv 设置应用运行环境，用于加载 conf/<env> 环境层配置
+// 优先级高于环境变量 DRUGO_ENV 和配置项 app.env
+func WithAppEnv(env string) Option {
+	return func(o *options) {
+		o.appEnv = env
+	}
+}
+
+// WithDrainTimeout 设置停机时排空阶段（kernel.Drainer）的超时时间
+// 如果不设置，默认使用停机超时时间的一半
+func WithDrainTimeout(timeout time.Duration) Option {
+	return func(o *options) {
+		o.drainTimeout = timeout
+	}
+}
+
+// WithBootReportFile 在 Boot 成功后将启动报告（见 Drugo.BootReport）以 JSON 格式写入文件
+// path 为相对路径时基于项目根目录，为空时使用 DefaultBootReportFile
+func WithBootReportFile(path string) Option {
+	return func(o *options) {
+		o.bootReportFile = path
+		if path == "" {
+			o.bootReportFile = DefaultBootReportFile
+		}
+	}
+}
+
+// WithFrameworkLogName 设置框架生命周期日志使用的业务名称
+// 如果不设置，默认使用 "drugo"，即写入 drugo.log，与应用自己的业务日志分开
+func WithFrameworkLogName(name string) Option {
+	return func(o *options) {
+		o.frameworkLogName = name
+	}
+}
+
+// WithFrameworkLogLevel 单独设置框架生命周期日志的级别，例如 "warn"
+// 不影响应用其他业务日志的级别；如果不设置，使用日志配置中的全局级别
+func WithFrameworkLogLevel(level string) Option {
+	return func(o *options) {
+		o.frameworkLogLevel = level
+	}
+}
+
+// WithQuiet 设置安静模式，适用于迁移命令、一次性任务等 CLI 形式的二进制文件。
+// 安静模式下框架日志的最低级别提升到 warn，MustNewApp 不输出初始化信息；应用自己的业务日志不受影响。
+// 显式设置后 MustNewApp 不再读取环境变量 DRUGO_QUIET
+func WithQuiet(quiet bool) Option {
+	return func(o *options) {
+		o.quiet = quiet
+		o.quietSet = true
+	}
+}
+
+// WithLogConsoleOnlyInQuiet 设置安静模式下 MustNewApp 只输出到控制台，
+// 忽略日志配置中的 file 输出，不创建 runtime/logs 下的日志文件；非安静模式下不生效
+func WithLogConsoleOnlyInQuiet(consoleOnly bool) Option {
+	return func(o *options) {
+		o.quietConsoleOnly = consoleOnly
+	}
+}
+
+// WithAllowEmptyConfig 允许 MustNewApp 在配置目录为空时正常启动
+// 默认情况下 MustNewApp 使用 config.WithRequireNonEmpty，配置目录为空（例如 conf/ 挂载失败）时直接 panic
+func WithAllowEmptyConfig() Option {
+	return func(o *options) {
+		o.allowEmptyConfig = true
+	}
+}
+
+// WithConfigUsageTracking 使 MustNewApp 创建的配置管理器记录配置项的读取（见 config.WithUsageTracking），
+// 启动报告的 UnusedConfigKeys 列出 Boot 结束时没有被读取过的配置项，ignore 是不参与报告的键路径模式。
+// 注入给 kernel.Configurable 服务的配置段整体视为已读取，框架无法观察服务读取了其中的哪些配置项
+func WithConfigUsageTracking(ignore ...string) Option {
+	return func(o *options) {
+		o.configUsage = true
+		o.configUsageIgnore = append(o.configUsageIgnore, ignore...)
+	}
+}
+
+// WithStrictNames 设置是否严格检查服务名称。
+// 严格模式下，格式无效（见 kernel.ValidateServiceName）或与框架保留名称（见 kernel.ReservedNames）、
+// 框架日志的业务名称（见 WithFrameworkLogName）冲突的服务会使 NewE 返回错误，Boot 期间动态注册的此类服务会使 Boot 失败；
+// 否则只记录警告日志。New/NewE 默认不严格，MustNewApp 默认严格
+func WithStrictNames(strict bool) Option {
+	return func(o *options) {
+		o.strictNames = strict
+	}
+}
+
+// WithProbeClaims 在启动 Runner 之前，除了检查服务之间的资源声明冲突（见 kernel.ResourceClaimer），
+// 还会短暂监听每个 tcp-port 声明的端口，以便在端口被外部进程占用时尽早失败
+func WithProbeClaims() Option {
+	return func(o *options) {
+		o.probeClaims = true
+	}
+}
@@ -2,30 +2,20 @@ package drugo
 
 import (
 	"context"
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
 	"github.com/qq1060656096/drugo/kernel"
+	"github.com/qq1060656096/drugo/kernel/kerneltest"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
 )
 
-// mockService 是一个用于测试的模拟服务实现
-type mockService struct {
-	name string
-}
-
-func (m *mockService) Name() string {
-	return m.name
-}
-
-func (m *mockService) Boot(ctx context.Context) error {
-	return nil
-}
-
-func (m *mockService) Close(ctx context.Context) error {
-	return nil
-}
-
 // TestWithRoot 测试 WithRoot 选项函数
 func TestWithRoot(t *testing.T) {
 	tests := []struct {
@@ -96,7 +86,7 @@ func TestWithContext(t *testing.T) {
 
 // TestWithNameService 测试 WithNameService 选项函数
 func TestWithNameService(t *testing.T) {
-	service := &mockService{name: "test-service"}
+	service := kerneltest.NewServiceMock("test-service")
 
 	tests := []struct {
 		name            string
@@ -136,7 +126,7 @@ func TestWithNameService(t *testing.T) {
 
 // TestWithService 测试 WithService 选项函数
 func TestWithService(t *testing.T) {
-	service := &mockService{name: "auto-service"}
+	service := kerneltest.NewServiceMock("auto-service")
 
 	tests := []struct {
 		name        string
@@ -200,10 +190,25 @@ func TestWithShutdownTimeout(t *testing.T) {
 	}
 }
 
+// TestWithClock 测试 WithClock 设置的时钟，以及 MustNewApp 将其传递给日志配置
+func TestWithClock(t *testing.T) {
+	assert.Equal(t, kernel.RealClock{}, New().Clock())
+	assert.Equal(t, kernel.RealClock{}, New(WithClock(nil)).Clock())
+
+	c := kernel.NewFakeClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	assert.Same(t, c, New(WithClock(c)).Clock())
+
+	root := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "conf"), 0755))
+	app := MustNewApp(WithRoot(root), WithAllowEmptyConfig(), WithQuiet(true), WithClock(c))
+	assert.Same(t, c, app.logConfig.Clock)
+	assert.Nil(t, MustNewApp(WithRoot(root), WithAllowEmptyConfig(), WithQuiet(true)).logConfig.Clock)
+}
+
 // TestOptions_Combo 测试多个选项的组合使用
 func TestOptions_Combo(t *testing.T) {
-	service1 := &mockService{name: "service1"}
-	service2 := &mockService{name: "service2"}
+	service1 := kerneltest.NewServiceMock("service1")
+	service2 := kerneltest.NewServiceMock("service2")
 	ctx := context.WithValue(context.Background(), "test", "value")
 
 	opts := &options{}
@@ -243,7 +248,7 @@ func TestOptions_DefaultValues(t *testing.T) {
 
 // TestOptions_ServicesNilHandling 测试服务切片为nil时的处理
 func TestOptions_ServicesNilHandling(t *testing.T) {
-	service := &mockService{name: "test-service"}
+	service := kerneltest.NewServiceMock("test-service")
 
 	opts := &options{}
 	assert.Nil(t, opts.services)
@@ -258,8 +263,8 @@ func TestOptions_ServicesNilHandling(t *testing.T) {
 
 // TestOptions_MultipleSameNameServices 测试添加多个同名服务
 func TestOptions_MultipleSameNameServices(t *testing.T) {
-	service1 := &mockService{name: "service"}
-	service2 := &mockService{name: "service"}
+	service1 := kerneltest.NewServiceMock("service")
+	service2 := kerneltest.NewServiceMock("service")
 
 	opts := &options{}
 
@@ -301,7 +306,7 @@ func BenchmarkWithContext(b *testing.B) {
 
 // BenchmarkWithNameService 测试 WithNameService 函数的性能
 func BenchmarkWithNameService(b *testing.B) {
-	service := &mockService{name: "benchmark-service"}
+	service := kerneltest.NewServiceMock("benchmark-service")
 	opt := WithNameService("benchmark", service)
 
 	b.ResetTimer()
@@ -313,7 +318,7 @@ func BenchmarkWithNameService(b *testing.B) {
 
 // BenchmarkWithService 测试 WithService 函数的性能
 func BenchmarkWithService(b *testing.B) {
-	service := &mockService{name: "benchmark-service"}
+	service := kerneltest.NewServiceMock("benchmark-service")
 	opt := WithService(service)
 
 	b.ResetTimer()
@@ -336,7 +341,7 @@ func BenchmarkWithShutdownTimeout(b *testing.B) {
 
 // BenchmarkOptions_Combo 测试组合选项的性能
 func BenchmarkOptions_Combo(b *testing.B) {
-	service := &mockService{name: "benchmark-service"}
+	service := kerneltest.NewServiceMock("benchmark-service")
 	ctx := context.Background()
 
 	optionList := []Option{
@@ -354,3 +359,77 @@ func BenchmarkOptions_Combo(b *testing.B) {
 		}
 	}
 }
+
+// TestWithStrictNames 测试严格模式拒绝无效或保留的服务名称，非严格模式只记录警告
+func TestWithStrictNames(t *testing.T) {
+	opts := []Option{
+		WithNameService("app", kerneltest.NewServiceMock("app")),
+		WithNameService("a/b", kerneltest.NewServiceMock("a/b")),
+		WithNameService("db", kerneltest.NewServiceMock("db")),
+	}
+
+	_, err := NewE(append(opts, WithStrictNames(true))...)
+	require.Error(t, err)
+	assert.True(t, kernel.IsReservedServiceName(err))
+	assert.True(t, kernel.IsInvalidServiceName(err))
+	assert.Contains(t, err.Error(), "app, config, drugo, log")
+
+	// 非严格模式照常注册
+	app, err := NewE(opts...)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"app", "a/b", "db"}, app.Container().Names())
+
+	// 原始容器不检查名称
+	c := NewContainer[kernel.Service]()
+	c.Bind("", kerneltest.NewServiceMock(""))
+	assert.Equal(t, []string{""}, c.Names())
+}
+
+// TestWithStrictNames_FrameworkLogName 测试服务名称与 WithFrameworkLogName 设置的框架日志名称冲突（不区分大小写）
+func TestWithStrictNames_FrameworkLogName(t *testing.T) {
+	opts := []Option{
+		WithFrameworkLogName("framework"),
+		WithNameService("Framework", kerneltest.NewServiceMock("Framework")),
+	}
+
+	_, err := NewE(append(opts, WithStrictNames(true))...)
+	require.Error(t, err)
+	assert.True(t, kernel.IsReservedServiceName(err))
+	assert.Contains(t, err.Error(), "framework log name")
+
+	app, err := NewE(opts...)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Framework"}, app.Container().Names())
+}
+
+// TestWithStrictNames_BoundDuringBoot 测试 Boot 期间通过 Container().Bind 注册的服务同样检查名称：
+// 严格模式下 Boot 失败且不初始化这些服务，非严格模式下记录警告后照常初始化
+func TestWithStrictNames_BoundDuringBoot(t *testing.T) {
+	newApp := func(strict bool) (*Drugo, *kerneltest.ServiceMock, *observer.ObservedLogs) {
+		shadow := kerneltest.NewServiceMock("config")
+		loader := kerneltest.NewServiceMock("loader")
+		loader.BootFunc = func(ctx context.Context) error {
+			c := kernel.MustFromContext(ctx).Container()
+			c.Bind("config", shadow)
+			c.Bind("a/b", kerneltest.NewServiceMock("a/b"))
+			c.Bind("plugin", kerneltest.NewServiceMock("plugin"))
+			return nil
+		}
+		app := New(WithService(loader), WithStrictNames(strict))
+		core, logs := observer.New(zapcore.InfoLevel)
+		app.fwFallback = zap.New(core)
+		return app, shadow, logs
+	}
+
+	app, shadow, _ := newApp(true)
+	err := app.Boot(context.Background())
+	require.Error(t, err)
+	assert.True(t, kernel.IsReservedServiceName(err))
+	assert.True(t, kernel.IsInvalidServiceName(err))
+	assert.Zero(t, shadow.BootCount())
+
+	app, shadow, logs := newApp(false)
+	require.NoError(t, app.Boot(context.Background()))
+	assert.Equal(t, 1, shadow.BootCount())
+	assert.Len(t, logs.FilterMessage("invalid service name").All(), 2)
+}
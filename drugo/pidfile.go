@@ -0,0 +1,114 @@
+package drugo
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+// DefaultPIDFile 返回 PID 文件相对项目根目录的默认路径 runtime/<应用名>.pid。
+// 应用名取自可执行文件名（去掉 .exe 扩展名），共用同一个项目根目录的不同应用不会互相冲突
+func DefaultPIDFile() string {
+	name := logName
+	if len(os.Args) > 0 {
+		if base := strings.TrimSuffix(filepath.Base(os.Args[0]), ".exe"); base != "" && base != "." && base != string(filepath.Separator) {
+			name = base
+		}
+	}
+	return "runtime/" + name + ".pid"
+}
+
+// WithPIDFile 使 Serve 在 Boot 之前创建 PID 文件并写入当前进程的 PID，Shutdown 的最后阶段删除它。
+// path 为相对路径时基于项目根目录，为空时使用 DefaultPIDFile。
+//
+// 非 Windows 平台上 PID 文件由独占的 flock 保护：另一个实例持有锁时 Serve 返回包装了 ErrPIDFileLocked 的错误，
+// 错误中包含对方的 PID；上一次运行崩溃遗留的 PID 文件没有被锁定，会被接管并记录警告日志。
+// Windows 上退化为文件存在性检查加上所记录 PID 的存活检查
+func WithPIDFile(path string) Option {
+	return func(o *options) {
+		o.pidFile = path
+		if path == "" {
+			o.pidFile = DefaultPIDFile()
+		}
+	}
+}
+
+// PIDFile 返回 PID 文件的路径，未启用 WithPIDFile 时返回空字符串
+func (d *Drugo) PIDFile() string {
+	if d.pidFile == "" {
+		return ""
+	}
+	return ResolveDir(d.Root(), d.pidFile, DefaultPIDFile())
+}
+
+// acquirePIDFile 锁定 PID 文件并写入当前进程的 PID，未启用 WithPIDFile 时不做任何事
+func (d *Drugo) acquirePIDFile(l *zap.Logger) error {
+	path := d.PIDFile()
+	if path == "" {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("drugo: create pid file dir: %w", err)
+	}
+	f, stale, err := lockPIDFile(path)
+	if err != nil {
+		return err
+	}
+	if stale != 0 && stale != os.Getpid() {
+		l.Warn("stale pid file taken over", zap.String("path", path), zap.Int("stale_pid", stale))
+	}
+	if err := writePID(f); err != nil {
+		_ = unlockPIDFile(f, path)
+		return fmt.Errorf("drugo: write pid file %s: %w", path, err)
+	}
+	d.pidLock = f
+	l.Info("pid file written", zap.String("path", path), zap.Int("pid", os.Getpid()))
+	return nil
+}
+
+// releasePIDFile 删除 PID 文件并释放锁，没有持有 PID 文件时不做任何事，失败时只记录日志
+func (d *Drugo) releasePIDFile(l *zap.Logger) {
+	if d.pidLock == nil {
+		return
+	}
+	path := d.PIDFile()
+	if err := unlockPIDFile(d.pidLock, path); err != nil {
+		l.Warn("pid file remove failed", zap.String("path", path), zap.Error(err))
+	} else {
+		l.Info("pid file removed", zap.String("path", path))
+	}
+	d.pidLock = nil
+}
+
+// writePID 用当前进程的 PID 覆盖 f 的内容
+func writePID(f *os.File) error {
+	if err := f.Truncate(0); err != nil {
+		return err
+	}
+	if _, err := f.WriteAt([]byte(strconv.Itoa(os.Getpid())+"\n"), 0); err != nil {
+		return err
+	}
+	return f.Sync()
+}
+
+// parsePID 解析 PID 文件的内容，内容无效时返回 0
+func parsePID(data []byte) int {
+	pid, err := strconv.Atoi(string(bytes.TrimSpace(data)))
+	if err != nil || pid <= 0 {
+		return 0
+	}
+	return pid
+}
+
+// pidFileLockedError 返回 path 被进程 pid 持有的错误，pid 尚未写入时为 0
+func pidFileLockedError(path string, pid int) error {
+	if pid == 0 {
+		return fmt.Errorf("%w: %s is held by another process", ErrPIDFileLocked, path)
+	}
+	return fmt.Errorf("%w: %s is held by pid %d", ErrPIDFileLocked, path, pid)
+}
@@ -0,0 +1,116 @@
+package drugo
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// servePIDApp 创建使用 PID 文件 path 的应用并在后台执行 Serve，Boot 完成后返回
+func servePIDApp(t *testing.T, root, path string) (*Drugo, <-chan error) {
+	t.Helper()
+	app := New(WithRoot(root), WithPIDFile(path), WithService(newBlockingRunner("runner")), WithDisableSignals())
+	app.logger = newTestLogManager(t)
+	done := make(chan error, 1)
+	go func() { done <- app.Serve(context.Background()) }()
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	require.NoError(t, app.WaitBooted(ctx, "runner"))
+	return app, done
+}
+
+// readPID 读取 PID 文件中的 PID
+func readPID(t *testing.T, path string) int {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	return parsePID(data)
+}
+
+// TestWithPIDFile_Path 测试 PID 文件路径的解析
+func TestWithPIDFile_Path(t *testing.T) {
+	root := t.TempDir()
+	assert.Empty(t, New(WithRoot(root)).PIDFile())
+	assert.Equal(t, filepath.Join(root, DefaultPIDFile()), New(WithRoot(root), WithPIDFile("")).PIDFile())
+	assert.Equal(t, filepath.Join(root, "run", "api.pid"), New(WithRoot(root), WithPIDFile("run/api.pid")).PIDFile())
+	abs := filepath.Join(t.TempDir(), "api.pid")
+	assert.Equal(t, abs, New(WithRoot(root), WithPIDFile(abs)).PIDFile())
+}
+
+// TestDefaultPIDFile 测试默认 PID 文件以可执行文件名命名
+func TestDefaultPIDFile(t *testing.T) {
+	args := os.Args
+	t.Cleanup(func() { os.Args = args })
+
+	os.Args = []string{filepath.Join("usr", "local", "bin", "orders-api")}
+	assert.Equal(t, "runtime/orders-api.pid", DefaultPIDFile())
+	os.Args = []string{`orders-api.exe`}
+	assert.Equal(t, "runtime/orders-api.pid", DefaultPIDFile())
+	os.Args = nil
+	assert.Equal(t, "runtime/drugo.pid", DefaultPIDFile())
+}
+
+// TestDrugo_Serve_PIDFile 测试 Serve 在 Boot 之前写入 PID 文件，Shutdown 时删除
+func TestDrugo_Serve_PIDFile(t *testing.T) {
+	app, done := servePIDApp(t, t.TempDir(), "")
+	path := app.PIDFile()
+	assert.Equal(t, os.Getpid(), readPID(t, path))
+
+	app.Stop()
+	require.NoError(t, waitServe(t, done))
+	assert.NoFileExists(t, path)
+}
+
+// TestDrugo_Serve_PIDFileLocked 测试 PID 文件被另一个实例持有时拒绝启动，且不影响持有者
+func TestDrugo_Serve_PIDFileLocked(t *testing.T) {
+	root := t.TempDir()
+	first, done := servePIDApp(t, root, "")
+
+	runner := newBlockingRunner("runner")
+	second := New(WithRoot(root), WithPIDFile(""), WithService(runner), WithDisableSignals())
+	second.logger = newTestLogManager(t)
+	err := second.Serve(context.Background())
+	require.ErrorIs(t, err, ErrPIDFileLocked)
+	assert.ErrorContains(t, err, "held by pid "+strconv.Itoa(os.Getpid()))
+	assert.Equal(t, OutcomeBootFailed, second.Outcome())
+	assert.Equal(t, 0, runner.BootCount(), "Boot 之前就应当拒绝启动")
+	assert.Equal(t, os.Getpid(), readPID(t, first.PIDFile()))
+
+	first.Stop()
+	require.NoError(t, waitServe(t, done))
+	assert.NoFileExists(t, first.PIDFile())
+}
+
+// TestDrugo_Serve_StalePIDFile 测试接管上一次运行崩溃遗留的 PID 文件
+func TestDrugo_Serve_StalePIDFile(t *testing.T) {
+	root := t.TempDir()
+	path := filepath.Join(root, "app.pid")
+	require.NoError(t, os.WriteFile(path, []byte("999999999\n"), 0o644))
+
+	app, done := servePIDApp(t, root, "app.pid")
+	assert.Equal(t, os.Getpid(), readPID(t, path))
+
+	app.Stop()
+	require.NoError(t, waitServe(t, done))
+	assert.NoFileExists(t, path)
+}
+
+// TestDrugo_Serve_PIDFileBootFailed 测试 Boot 失败时同样删除 PID 文件
+func TestDrugo_Serve_PIDFileBootFailed(t *testing.T) {
+	root := t.TempDir()
+	app, err := serveOutcome(t, false, WithRoot(root), WithPIDFile(""),
+		WithService(newBootFailingRunner("runner", errors.New("boom"))))
+	require.Error(t, err)
+	assert.NotErrorIs(t, err, ErrPIDFileLocked)
+	assert.NoFileExists(t, app.PIDFile())
+
+	// 同一个实例重复启动时返回 ErrAlreadyStarted，而不是 ErrPIDFileLocked
+	assert.ErrorIs(t, app.Serve(context.Background()), ErrAlreadyStarted)
+}
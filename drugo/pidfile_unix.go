@@ -0,0 +1,69 @@
+//go:build !windows
+
+package drugo
+
+import (
+	"errors"
+	"io"
+	"os"
+	"syscall"
+)
+
+// lockPIDFile 打开（必要时创建）path 并对其加非阻塞的独占 flock，返回文件与其中原有的 PID。
+// 锁由进程持有，进程退出（包括崩溃）时由内核释放，因此没有被锁定的 PID 文件一定是遗留文件。
+//
+// 持有者退出时先删除 path 再释放锁，在此之前打开了旧文件的进程随后可能锁定已经删除的文件，
+// 而第三个进程同时创建并锁定了新的文件；因此加锁成功后还要确认锁定的文件仍然是 path，否则重新打开
+func lockPIDFile(path string) (*os.File, int, error) {
+	for {
+		f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o644)
+		if err != nil {
+			return nil, 0, err
+		}
+		if err := syscall.Flock(int(f.Fd()), syscall.LOCK_EX|syscall.LOCK_NB); err != nil {
+			data, _ := io.ReadAll(f)
+			_ = f.Close()
+			if errors.Is(err, syscall.EWOULDBLOCK) {
+				return nil, 0, pidFileLockedError(path, parsePID(data))
+			}
+			return nil, 0, err
+		}
+		same, err := isPathFile(f, path)
+		if err != nil {
+			_ = f.Close()
+			return nil, 0, err
+		}
+		if !same {
+			_ = f.Close()
+			continue
+		}
+		data, err := io.ReadAll(f)
+		if err != nil {
+			_ = f.Close()
+			return nil, 0, err
+		}
+		return f, parsePID(data), nil
+	}
+}
+
+// isPathFile 报告打开的文件 f 是否仍然是 path 指向的文件，path 已被删除时返回 false
+func isPathFile(f *os.File, path string) (bool, error) {
+	fi, err := f.Stat()
+	if err != nil {
+		return false, err
+	}
+	pi, err := os.Stat(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return os.SameFile(fi, pi), nil
+}
+
+// unlockPIDFile 在持有锁时删除 path，再关闭文件释放锁，避免删除其他实例随后创建的 PID 文件
+func unlockPIDFile(f *os.File, path string) error {
+	err := os.Remove(path)
+	return errors.Join(err, f.Close())
+}
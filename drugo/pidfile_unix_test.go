@@ -0,0 +1,45 @@
+//go:build !windows
+
+package drugo
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestLockPIDFile_Unlinked 测试持有者删除 PID 文件后，锁定旧文件的进程能识别出旧文件已不是 path，
+// 而新创建并被锁定的 PID 文件仍然拒绝其他实例
+func TestLockPIDFile_Unlinked(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.pid")
+	holder, _, err := lockPIDFile(path)
+	require.NoError(t, err)
+	require.NoError(t, writePID(holder))
+
+	// 在持有者退出之前打开了旧文件
+	old, err := os.Open(path)
+	require.NoError(t, err)
+	defer old.Close()
+
+	require.NoError(t, unlockPIDFile(holder, path))
+	same, err := isPathFile(old, path)
+	require.NoError(t, err)
+	assert.False(t, same)
+
+	// 第三个实例创建并锁定了新的 PID 文件
+	current, _, err := lockPIDFile(path)
+	require.NoError(t, err)
+	defer func() { _ = unlockPIDFile(current, path) }()
+	same, err = isPathFile(old, path)
+	require.NoError(t, err)
+	assert.False(t, same)
+	same, err = isPathFile(current, path)
+	require.NoError(t, err)
+	assert.True(t, same)
+
+	_, _, err = lockPIDFile(path)
+	assert.ErrorIs(t, err, ErrPIDFileLocked)
+}
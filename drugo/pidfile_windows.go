@@ -0,0 +1,53 @@
+//go:build windows
+
+package drugo
+
+import (
+	"errors"
+	"os"
+)
+
+// lockPIDFile 以 O_EXCL 创建 path，返回文件与遗留文件中的 PID。
+// Windows 上没有 flock，path 已存在时检查其中记录的进程是否存活：存活时返回 ErrPIDFileLocked，
+// 否则视为上一次运行遗留的文件，删除后重新创建
+func lockPIDFile(path string) (*os.File, int, error) {
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_EXCL, 0o644)
+	if err == nil {
+		return f, 0, nil
+	}
+	if !errors.Is(err, os.ErrExist) {
+		return nil, 0, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, 0, err
+	}
+	pid := parsePID(data)
+	if pid != 0 && processAlive(pid) {
+		return nil, 0, pidFileLockedError(path, pid)
+	}
+	if err := os.Remove(path); err != nil {
+		return nil, 0, err
+	}
+	f, err = os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_EXCL, 0o644)
+	if err != nil {
+		return nil, 0, err
+	}
+	return f, pid, nil
+}
+
+// processAlive 报告进程 pid 是否存在，Windows 上 FindProcess 在进程不存在时返回错误
+func processAlive(pid int) bool {
+	p, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	_ = p.Release()
+	return true
+}
+
+// unlockPIDFile 关闭文件后删除 path，Windows 上不能删除仍然打开的文件
+func unlockPIDFile(f *os.File, path string) error {
+	err := f.Close()
+	return errors.Join(err, os.Remove(path))
+}
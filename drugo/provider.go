@@ -0,0 +1,93 @@
+package drugo
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/qq1060656096/drugo/kernel"
+)
+
+// ServiceProvider 创建一个服务，创建失败时返回错误
+type ServiceProvider func() (kernel.Service, error)
+
+// WithServices 按顺序注册多个服务，等价于对每个服务调用 WithService
+func WithServices(services ...kernel.Service) Option {
+	return func(o *options) {
+		for _, service := range services {
+			WithService(service)(o)
+		}
+	}
+}
+
+// WithServiceErr 注册构造函数返回的服务及其错误，便于直接传入 NewXxx() (Service, error) 的结果：
+//
+//	drugo.NewE(drugo.WithServiceErr(redis.New(cfg)))
+//
+// err 不为 nil 时不会注册服务，而是记录一个包装了 ErrServiceProvider 的错误，由 NewE 合并返回
+func WithServiceErr(service kernel.Service, err error) Option {
+	return func(o *options) {
+		if err == nil {
+			WithService(service)(o)
+			return
+		}
+		o.serviceCount++
+		name := fmt.Sprintf("service #%d", o.serviceCount)
+		if !isNilService(service) {
+			name = fmt.Sprintf("%q (%s)", service.Name(), name)
+		}
+		o.serviceErrs = append(o.serviceErrs, fmt.Errorf("%w: %s: %w", ErrServiceProvider, name, err))
+	}
+}
+
+// WithServiceProvider 在应用选项时调用 provider 创建服务并注册。
+// provider 返回错误时记录一个包装了 ErrServiceProvider 并带有 provider 函数名的错误，由 NewE 合并返回
+func WithServiceProvider(provider ServiceProvider) Option {
+	return func(o *options) {
+		if provider == nil {
+			WithService(nil)(o)
+			return
+		}
+		service, err := provider()
+		if err == nil {
+			WithService(service)(o)
+			return
+		}
+		o.serviceCount++
+		o.serviceErrs = append(o.serviceErrs, fmt.Errorf("%w: %s (service #%d): %w",
+			ErrServiceProvider, providerName(provider), o.serviceCount, err))
+	}
+}
+
+// checkServiceName 检查服务名称的格式，以及是否与框架保留名称或框架日志的业务名称（见 WithFrameworkLogName）冲突，
+// 与框架日志名称的比较同样不区分大小写
+func checkServiceName(name, frameworkLogName string) error {
+	if err := kernel.ValidateServiceName(name); err != nil {
+		return err
+	}
+	if kernel.IsReservedName(name) {
+		return kernel.NewReservedServiceName(name)
+	}
+	if frameworkLogName != "" && strings.EqualFold(name, frameworkLogName) {
+		return fmt.Errorf("%w: %q is the framework log name", kernel.ErrReservedServiceName, name)
+	}
+	return nil
+}
+
+// isNilService 判断服务是否为 nil，包括包装了 nil 指针的接口值
+func isNilService(service kernel.Service) bool {
+	if service == nil {
+		return true
+	}
+	v := reflect.ValueOf(service)
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Map, reflect.Slice, reflect.Func, reflect.Chan, reflect.Interface:
+		return v.IsNil()
+	}
+	return false
+}
+
+// providerName 返回 provider 函数的完整名称，例如 main.newRedis
+func providerName(provider ServiceProvider) string {
+	return funcName(reflect.ValueOf(provider))
+}
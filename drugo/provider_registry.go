@@ -0,0 +1,116 @@
+package drugo
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"text/tabwriter"
+
+	"github.com/qq1060656096/drugo/kernel"
+)
+
+// providerRegistry 是包级别的 provider 注册表，见 RegisterProvider
+var providerRegistry = struct {
+	mu        sync.RWMutex
+	factories map[string]ServiceProvider
+}{factories: make(map[string]ServiceProvider)}
+
+// RegisterProvider 以 name 注册一个服务 provider，供 WithRegisteredProviders 与 WithAllRegisteredProviders 按名称选用。
+// 通常在 provider 包的 init 中调用，并用构建标签控制是否编译进二进制，从而无需修改 main.go 即可加入部署相关的服务：
+//
+//	//go:build exporter
+//
+//	package exporter
+//
+//	func init() {
+//	    drugo.RegisterProvider("exporter", func() (kernel.Service, error) { return New() })
+//	}
+//
+// name 为空、factory 为 nil 或 name 已被注册时 panic，使错误在程序启动时立即暴露。
+func RegisterProvider(name string, factory ServiceProvider) {
+	if name == "" {
+		panic("drugo: RegisterProvider called with an empty name")
+	}
+	if factory == nil {
+		panic(fmt.Sprintf("drugo: RegisterProvider called with a nil factory for provider %q", name))
+	}
+	providerRegistry.mu.Lock()
+	defer providerRegistry.mu.Unlock()
+	if _, ok := providerRegistry.factories[name]; ok {
+		panic(fmt.Sprintf("drugo: provider %q is already registered", name))
+	}
+	providerRegistry.factories[name] = factory
+}
+
+// RegisteredProviders 返回所有已注册 provider 的名称（按名称排序），用于诊断输出
+func RegisteredProviders() []string {
+	providerRegistry.mu.RLock()
+	defer providerRegistry.mu.RUnlock()
+	names := make([]string, 0, len(providerRegistry.factories))
+	for name := range providerRegistry.factories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// registeredProvider 返回名为 name 的 provider
+func registeredProvider(name string) (ServiceProvider, bool) {
+	providerRegistry.mu.RLock()
+	defer providerRegistry.mu.RUnlock()
+	factory, ok := providerRegistry.factories[name]
+	return factory, ok
+}
+
+// WithRegisteredProviders 在应用选项时按给定顺序调用选中的已注册 provider 创建服务并注册。
+// 同一个 provider 只会实例化一次；名称未注册时记录一个包装了 ErrUnknownProvider 的错误，
+// provider 返回错误时记录一个包装了 ErrServiceProvider 并带有 provider 名称的错误，均由 NewE 合并返回
+func WithRegisteredProviders(names ...string) Option {
+	return func(o *options) {
+		for _, name := range names {
+			if _, ok := o.providers[name]; ok {
+				continue
+			}
+			factory, ok := registeredProvider(name)
+			if !ok {
+				o.serviceErrs = append(o.serviceErrs, fmt.Errorf("%w: %q", ErrUnknownProvider, name))
+				continue
+			}
+			if o.providers == nil {
+				o.providers = make(map[string]struct{})
+			}
+			o.providers[name] = struct{}{}
+
+			service, err := factory()
+			if err == nil {
+				WithService(service)(o)
+				continue
+			}
+			o.serviceCount++
+			o.serviceErrs = append(o.serviceErrs, fmt.Errorf("%w: provider %q (service #%d): %w",
+				ErrServiceProvider, name, o.serviceCount, err))
+		}
+	}
+}
+
+// WithAllRegisteredProviders 按名称顺序实例化所有已注册的 provider，见 WithRegisteredProviders
+func WithAllRegisteredProviders() Option {
+	return func(o *options) {
+		WithRegisteredProviders(RegisteredProviders()...)(o)
+	}
+}
+
+// providersCommand 打印所有已注册的 provider，当前应用选用的 provider 标记为 enabled
+func (d *Drugo) providersCommand(ctx context.Context, k kernel.Kernel, args []string) error {
+	w := tabwriter.NewWriter(d.output(), 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "PROVIDER\tSTATUS")
+	for _, name := range RegisteredProviders() {
+		status := "available"
+		if _, ok := d.providers[name]; ok {
+			status = "enabled"
+		}
+		fmt.Fprintf(w, "%s\t%s\n", name, status)
+	}
+	return w.Flush()
+}
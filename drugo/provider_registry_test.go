@@ -0,0 +1,104 @@
+package drugo
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+
+	"github.com/qq1060656096/drugo/kernel"
+	"github.com/qq1060656096/drugo/kernel/kerneltest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// registerTestProvider 注册一个创建名为 name 的模拟服务的 provider，测试结束后注销，
+// 返回 provider 被调用的次数
+func registerTestProvider(t *testing.T, name string, err error) *atomic.Int32 {
+	t.Helper()
+	var calls atomic.Int32
+	RegisterProvider(name, func() (kernel.Service, error) {
+		calls.Add(1)
+		if err != nil {
+			return nil, err
+		}
+		return kerneltest.NewServiceMock(name), nil
+	})
+	t.Cleanup(func() {
+		providerRegistry.mu.Lock()
+		defer providerRegistry.mu.Unlock()
+		delete(providerRegistry.factories, name)
+	})
+	return &calls
+}
+
+// TestRegisterProvider 测试注册与发现，以及重复注册、空名称与 nil factory 时 panic
+func TestRegisterProvider(t *testing.T) {
+	registerTestProvider(t, "test-exporter", nil)
+	registerTestProvider(t, "test-audit", nil)
+	assert.Subset(t, RegisteredProviders(), []string{"test-audit", "test-exporter"})
+
+	assert.PanicsWithValue(t, `drugo: provider "test-exporter" is already registered`, func() {
+		RegisterProvider("test-exporter", func() (kernel.Service, error) { return nil, nil })
+	})
+	assert.Panics(t, func() { RegisterProvider("", func() (kernel.Service, error) { return nil, nil }) })
+	assert.Panics(t, func() { RegisterProvider("test-nil", nil) })
+}
+
+// TestWithRegisteredProviders 测试只实例化选中的 provider，且同一 provider 只实例化一次
+func TestWithRegisteredProviders(t *testing.T) {
+	exporter := registerTestProvider(t, "test-exporter", nil)
+	audit := registerTestProvider(t, "test-audit", nil)
+	unused := registerTestProvider(t, "test-unused", nil)
+
+	app, err := NewE(
+		WithRegisteredProviders("test-exporter", "test-audit"),
+		WithRegisteredProviders("test-exporter"),
+	)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"test-exporter", "test-audit"}, app.Container().Names())
+	assert.EqualValues(t, 1, exporter.Load())
+	assert.EqualValues(t, 1, audit.Load())
+	assert.Zero(t, unused.Load())
+}
+
+// TestWithAllRegisteredProviders 测试按名称顺序实例化所有已注册的 provider
+func TestWithAllRegisteredProviders(t *testing.T) {
+	calls := []*atomic.Int32{
+		registerTestProvider(t, "test-b", nil),
+		registerTestProvider(t, "test-a", nil),
+	}
+
+	app, err := NewE(WithAllRegisteredProviders())
+	require.NoError(t, err)
+	names := app.Container().Names()
+	assert.Subset(t, names, []string{"test-a", "test-b"})
+	for _, c := range calls {
+		assert.EqualValues(t, 1, c.Load())
+	}
+	assert.Equal(t, RegisteredProviders(), names)
+}
+
+// TestWithRegisteredProviders_Errors 测试未注册的名称与 provider 的错误由 NewE 合并返回
+func TestWithRegisteredProviders_Errors(t *testing.T) {
+	registerTestProvider(t, "test-exporter", nil)
+	registerTestProvider(t, "test-broken", assert.AnError)
+
+	_, err := NewE(WithRegisteredProviders("test-exporter", "test-broken", "test-missing"))
+	require.Error(t, err)
+	assert.ErrorIs(t, err, ErrServiceProvider)
+	assert.ErrorIs(t, err, assert.AnError)
+	assert.ErrorIs(t, err, ErrUnknownProvider)
+	assert.Contains(t, err.Error(), `provider "test-broken"`)
+	assert.Contains(t, err.Error(), `"test-missing"`)
+}
+
+// TestDrugo_Execute_Providers 测试 providers 内置命令列出已注册的 provider 及其是否被选用
+func TestDrugo_Execute_Providers(t *testing.T) {
+	registerTestProvider(t, "test-exporter", nil)
+	registerTestProvider(t, "test-audit", nil)
+	app, out := newTestCommandApp(t, WithRegisteredProviders("test-exporter"))
+
+	require.NoError(t, app.Execute(context.Background(), []string{"app", "providers"}))
+	assert.Regexp(t, `test-audit\s+available`, out.String())
+	assert.Regexp(t, `test-exporter\s+enabled`, out.String())
+}
@@ -0,0 +1,71 @@
+package drugo
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/qq1060656096/drugo/kernel"
+	"github.com/qq1060656096/drugo/kernel/kerneltest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var errDial = errors.New("dial failed")
+
+func newFailingCache() (kernel.Service, error) { return nil, errDial }
+
+func newFailingQueue() (kernel.Service, error) {
+	return kerneltest.NewServiceMock("queue"), errors.New("bad config")
+}
+
+// TestNewE_AggregatesServiceErrors 测试多个失败的 provider 与 nil 服务被合并为一个错误
+func TestNewE_AggregatesServiceErrors(t *testing.T) {
+	var typedNil *kerneltest.ServiceMock
+	app, err := NewE(
+		WithService(kerneltest.NewServiceMock("db")),
+		WithServiceProvider(newFailingCache),
+		WithServiceProvider(newFailingQueue),
+		WithService(typedNil),
+	)
+	require.Error(t, err)
+	assert.Nil(t, app)
+
+	assert.ErrorIs(t, err, ErrServiceProvider)
+	assert.ErrorIs(t, err, ErrNilService)
+	assert.ErrorIs(t, err, errDial)
+
+	var joined interface{ Unwrap() []error }
+	require.True(t, errors.As(err, &joined))
+	errs := joined.Unwrap()
+	require.Len(t, errs, 3)
+	assert.Contains(t, errs[0].Error(), "newFailingCache (service #2)")
+	assert.Contains(t, errs[1].Error(), "newFailingQueue (service #3): bad config")
+	assert.Contains(t, errs[2].Error(), "service #4")
+	assert.ErrorIs(t, errs[2], ErrNilService)
+}
+
+// TestNew_PanicsOnServiceErrors 测试 New 在注册服务失败时 panic，而不是在 Boot 时调用 nil 服务
+func TestNew_PanicsOnServiceErrors(t *testing.T) {
+	assert.PanicsWithError(t, ErrNilService.Error()+": service #1", func() {
+		New(WithService(nil))
+	})
+	assert.Panics(t, func() { New(WithOptionalService(nil)) })
+	assert.Panics(t, func() { New(WithServiceConfig(nil, "db")) })
+	assert.Panics(t, func() { New(WithNameService("db", nil)) })
+	assert.Panics(t, func() { New(WithServiceProvider(nil)) })
+}
+
+// TestWithServices 测试一次注册多个服务，以及构造函数成功时的 WithServiceErr/WithServiceProvider
+func TestWithServices(t *testing.T) {
+	app, err := NewE(
+		WithServices(kerneltest.NewServiceMock("a"), kerneltest.NewServiceMock("b")),
+		WithServiceErr(kerneltest.NewServiceMock("c"), nil),
+		WithServiceProvider(func() (kernel.Service, error) { return kerneltest.NewServiceMock("d"), nil }),
+	)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a", "b", "c", "d"}, app.serviceNames())
+
+	_, err = NewE(WithServiceErr(kerneltest.NewServiceMock("e"), errDial))
+	assert.ErrorIs(t, err, errDial)
+	assert.Contains(t, err.Error(), `"e" (service #1)`)
+}
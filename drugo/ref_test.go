@@ -0,0 +1,113 @@
+package drugo
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/qq1060656096/drugo/kernel"
+	"github.com/qq1060656096/drugo/kernel/kerneltest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestRef_MutualReference 测试两个相互依赖的服务在 Boot 中持有对方的引用，在 Run 中解析
+func TestRef_MutualReference(t *testing.T) {
+	httpSvc := kerneltest.NewRunnerMock("http")
+	authSvc := kerneltest.NewRunnerMock("auth")
+	var authRef, httpRef *kernel.Ref[*kerneltest.RunnerMock]
+	httpSvc.BootFunc = func(ctx context.Context) error {
+		authRef = RefFromContext[*kerneltest.RunnerMock](ctx, "auth")
+		return nil
+	}
+	authSvc.BootFunc = func(ctx context.Context) error {
+		httpRef = RefFromContext[*kerneltest.RunnerMock](ctx, "http")
+		return nil
+	}
+
+	resolved := make(chan *kerneltest.RunnerMock, 2)
+	resolveIn := func(ref **kernel.Ref[*kerneltest.RunnerMock]) func(ctx context.Context) error {
+		return func(ctx context.Context) error {
+			svc, err := (*ref).GetContext(ctx)
+			if err != nil {
+				return err
+			}
+			resolved <- svc
+			return kerneltest.BlockUntilCancel(ctx)
+		}
+	}
+	httpSvc.RunFunc = resolveIn(&authRef)
+	authSvc.RunFunc = resolveIn(&httpRef)
+
+	app := New(WithService(httpSvc), WithService(authSvc))
+	app.logger = newTestLogManager(t)
+	h := startRunners(t, app, httpSvc, authSvc)
+
+	got := map[*kerneltest.RunnerMock]bool{}
+	for range 2 {
+		select {
+		case svc := <-resolved:
+			got[svc] = true
+		case <-time.After(time.Second):
+			t.Fatal("refs not resolved during Run")
+		}
+	}
+	assert.True(t, got[httpSvc])
+	assert.True(t, got[authSvc])
+	require.NoError(t, h.Stop(context.Background()))
+	assert.NoError(t, h.Err())
+}
+
+// TestDrugo_WaitBooted 测试引用在服务完成 Boot 之前解析时等待，而不是失败
+func TestDrugo_WaitBooted(t *testing.T) {
+	t.Run("等待 Boot 完成", func(t *testing.T) {
+		db := kerneltest.NewServiceMock("db")
+		app := New(WithService(db))
+		app.logger = newTestLogManager(t)
+		ref := NewRef[*kerneltest.ServiceMock](app, "db", WithRefWaitBooted(time.Second))
+
+		resolved := make(chan error, 1)
+		go func() {
+			_, err := ref.Get()
+			resolved <- err
+		}()
+		select {
+		case <-resolved:
+			t.Fatal("ref resolved before Boot")
+		case <-time.After(30 * time.Millisecond):
+		}
+
+		require.NoError(t, app.Boot(context.Background()))
+		select {
+		case err := <-resolved:
+			assert.NoError(t, err)
+		case <-time.After(time.Second):
+			t.Fatal("ref not resolved after Boot")
+		}
+		assert.Same(t, db, ref.MustGet())
+	})
+
+	t.Run("已经 Boot 时立即返回", func(t *testing.T) {
+		app := New(WithService(kerneltest.NewServiceMock("db")))
+		app.logger = newTestLogManager(t)
+		require.NoError(t, app.Boot(context.Background()))
+		assert.NoError(t, app.WaitBooted(context.Background(), "db"))
+	})
+
+	t.Run("降级服务返回 Boot 错误", func(t *testing.T) {
+		app := New(WithOptionalService(newBootFailingRunner("cache", assert.AnError)))
+		app.logger = newTestLogManager(t)
+		require.NoError(t, app.Boot(context.Background()))
+
+		_, err := NewRef[*kerneltest.RunnerMock](app, "cache", WithRefWaitBooted(time.Second)).Get()
+		assert.True(t, kernel.IsServiceInitFailed(err))
+		assert.ErrorIs(t, err, assert.AnError)
+	})
+
+	t.Run("未注册的服务等待到超时", func(t *testing.T) {
+		app := New()
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+		assert.ErrorIs(t, app.WaitBooted(ctx, "missing"), context.DeadlineExceeded)
+	})
+}
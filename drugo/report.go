@@ -0,0 +1,206 @@
+package drugo
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"runtime/debug"
+	"slices"
+	"time"
+
+	"github.com/qq1060656096/drugo/config"
+	"github.com/qq1060656096/drugo/log"
+	"go.uber.org/zap"
+)
+
+// DefaultBootReportFile 是启动报告文件相对项目根目录的默认路径
+const DefaultBootReportFile = "runtime/boot-report.json"
+
+// MaxReloadEntries 是启动报告中保留的配置重载记录的最大条数，超出时丢弃最早的记录
+const MaxReloadEntries = 32
+
+// BootReport 是 Boot 成功时采集的运行时配置快照，用于排查"进程实际使用的是什么配置"。
+// 配置中的敏感项已脱敏。
+type BootReport struct {
+	Time     time.Time      // 采集时间
+	App      string         // 框架名称
+	Version  string         // 框架版本
+	Env      string         // 运行环境
+	Quiet    bool           // 是否处于安静模式，见 WithQuiet
+	Config   map[string]any // 脱敏后的生效配置
+	Log      log.Config     // 生效的日志配置
+	Services []ServiceInfo  // 已注册的服务
+	Build    BuildInfo      // 构建信息
+	Reloads  []ReloadEntry  // 启动后的配置重载记录，最多保留 MaxReloadEntries 条
+	Timings  StartupTimings // 采集时的启动耗时，不包含 Run 阶段
+
+	// 采集时没有被读取过的配置项，只在启用 WithConfigUsageTracking 时采集，未启用时为 nil
+	UnusedConfigKeys []string
+	// 可被环境变量覆盖的日志配置项的来源（"file" 或 "env:<变量名>"，见 LogEnvPrefix），只在 MustNewApp 创建的应用中采集
+	LogSources map[string]string
+
+	ServiceCount         int                  // 已注册的服务数量
+	EmptyContainerPolicy EmptyContainerPolicy // 生效的空容器策略，见 WithEmptyContainerPolicy
+}
+
+// ServiceInfo 描述启动报告中的单个服务。
+type ServiceInfo struct {
+	Name         string            // 服务名称
+	Type         string            // 服务的 Go 类型
+	State        ServiceState      // 采集时的状态
+	Capabilities map[string]string // 服务声明的能力，见 kernel.Capabilities，没有声明时为 nil
+}
+
+// BuildInfo 是二进制文件的构建信息。
+type BuildInfo struct {
+	GoVersion string            // 编译使用的 Go 版本
+	Path      string            // 主包路径
+	Version   string            // 主模块版本
+	Settings  map[string]string // 构建参数，例如 vcs.revision
+}
+
+// ReloadEntry 记录一次配置热加载的结果。
+type ReloadEntry struct {
+	Time    time.Time      // 重载时间
+	Changes config.Changes // 发生变化的业务配置
+	Error   string         // 重载失败的原因，成功时为空
+}
+
+// BootReport 返回最近一次成功 Boot 时采集的启动报告副本。
+// 尚未 Boot 时返回零值。
+func (d *Drugo) BootReport() BootReport {
+	d.reportMu.RLock()
+	defer d.reportMu.RUnlock()
+	if d.report == nil {
+		return BootReport{}
+	}
+	r := *d.report
+	r.Config = config.Redact(r.Config)
+	r.Services = append([]ServiceInfo(nil), r.Services...)
+	for i := range r.Services {
+		r.Services[i].Capabilities = maps.Clone(r.Services[i].Capabilities)
+	}
+	r.Reloads = append([]ReloadEntry(nil), r.Reloads...)
+	r.UnusedConfigKeys = slices.Clone(r.UnusedConfigKeys)
+	r.LogSources = maps.Clone(r.LogSources)
+	r.Timings.Services = append([]ServiceTiming(nil), r.Timings.Services...)
+	return r
+}
+
+// captureBootReport 采集启动报告，首次采集时注册配置重载记录，
+// 并在启用 WithBootReportFile 时写入 JSON 文件。
+func (d *Drugo) captureBootReport(l *zap.Logger) {
+	report := &BootReport{
+		Time:    d.Clock().Now(),
+		App:     Name,
+		Version: Version(),
+		Quiet:   d.quiet,
+		Log:     d.logConfig,
+		Build:   readBuildInfo(),
+		Timings: d.Timings(),
+
+		LogSources: maps.Clone(d.logSources),
+
+		EmptyContainerPolicy: d.EmptyContainerPolicy(),
+	}
+	if d.config != nil {
+		report.Env = d.config.Environment()
+		report.Config = config.Redact(d.config.Root().AllSettings())
+		report.UnusedConfigKeys = d.config.UnusedKeys(0)
+	}
+	status := d.Status()
+	services := d.Container().Services()
+	report.ServiceCount = len(services)
+	for _, service := range services {
+		report.Services = append(report.Services, ServiceInfo{
+			Name:         service.Name(),
+			Type:         fmt.Sprintf("%T", service),
+			State:        status[service.Name()].State,
+			Capabilities: capabilitiesOf(service),
+		})
+	}
+
+	d.reportMu.Lock()
+	first := d.report == nil
+	if !first {
+		report.Reloads = d.report.Reloads
+	}
+	d.report = report
+	d.reportMu.Unlock()
+
+	if first && d.config != nil {
+		d.config.OnReload(func(m *config.Manager) error {
+			d.recordReload(m.LastChanges(), nil)
+			return nil
+		})
+		d.config.OnReloadError(func(m *config.Manager, err error) {
+			d.recordReload(config.Changes{}, err)
+			switch d.handleError(PhaseReload, "config", err) {
+			case DecisionFail, DecisionRetry:
+				d.frameworkLogger().Error("config reload failure escalated by error handler", zap.Error(err))
+				d.fail(err)
+			}
+		})
+	}
+
+	if d.bootReportFile == "" {
+		return
+	}
+	path := ResolveDir(d.Root(), d.bootReportFile, DefaultBootReportFile)
+	if err := writeBootReport(path, d.BootReport()); err != nil {
+		l.Error("boot report write failed", zap.String("path", path), zap.Error(err))
+		return
+	}
+	l.Info("boot report written", zap.String("path", path))
+}
+
+// recordReload 向启动报告追加一条配置重载记录。
+func (d *Drugo) recordReload(changes config.Changes, err error) {
+	entry := ReloadEntry{Time: d.Clock().Now(), Changes: changes}
+	if err != nil {
+		entry.Error = err.Error()
+	}
+
+	d.reportMu.Lock()
+	defer d.reportMu.Unlock()
+	if d.report == nil {
+		return
+	}
+	reloads := append(d.report.Reloads, entry)
+	if len(reloads) > MaxReloadEntries {
+		reloads = append([]ReloadEntry(nil), reloads[len(reloads)-MaxReloadEntries:]...)
+	}
+	d.report.Reloads = reloads
+}
+
+// writeBootReport 将启动报告（或启动失败报告）以 JSON 格式写入 path。
+func writeBootReport(path string, report any) error {
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0600)
+}
+
+// readBuildInfo 读取二进制文件的构建信息，无法读取时返回零值。
+func readBuildInfo() BuildInfo {
+	bi, ok := debug.ReadBuildInfo()
+	if !ok {
+		return BuildInfo{}
+	}
+	info := BuildInfo{
+		GoVersion: bi.GoVersion,
+		Path:      bi.Path,
+		Version:   bi.Main.Version,
+		Settings:  make(map[string]string, len(bi.Settings)),
+	}
+	for _, s := range bi.Settings {
+		info.Settings[s.Key] = s.Value
+	}
+	return info
+}
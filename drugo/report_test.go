@@ -0,0 +1,125 @@
+package drugo
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/qq1060656096/drugo/config"
+	"github.com/qq1060656096/drugo/kernel/kerneltest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const reportConfig = "db:\n  host: localhost\n  password: p@ss\n"
+
+// TestDrugo_BootReport 测试 Boot 后启动报告的内容与脱敏
+func TestDrugo_BootReport(t *testing.T) {
+	root := t.TempDir()
+	app := New(WithRoot(root), WithService(kerneltest.NewServiceMock("db")), WithBootReportFile(""))
+	app.logger = newTestLogManager(t)
+	app.config = newTestConfigManager(t, reportConfig)
+	app.logConfig.Level = "debug"
+
+	assert.Empty(t, app.BootReport().App)
+	require.NoError(t, app.Boot(context.Background()))
+
+	report := app.BootReport()
+	assert.Equal(t, Name, report.App)
+	assert.False(t, report.Time.IsZero())
+	assert.Equal(t, "debug", report.Log.Level)
+	assert.NotEmpty(t, report.Build.GoVersion)
+	assert.Equal(t, []ServiceInfo{{Name: "db", Type: "*kerneltest.ServiceMock", State: ServiceStateBooted}}, report.Services)
+
+	db := report.Config["db"].(map[string]any)
+	assert.Equal(t, "localhost", db["host"])
+	assert.Equal(t, config.RedactedValue, db["password"])
+
+	// 修改返回的副本不影响内部快照
+	db["host"] = "changed"
+	assert.Equal(t, "localhost", app.BootReport().Config["db"].(map[string]any)["host"])
+
+	data, err := os.ReadFile(filepath.Join(root, DefaultBootReportFile))
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "p@ss")
+
+	var fromFile BootReport
+	require.NoError(t, json.Unmarshal(data, &fromFile))
+	assert.Equal(t, config.RedactedValue, fromFile.Config["db"].(map[string]any)["password"])
+	assert.Equal(t, "db", fromFile.Services[0].Name)
+}
+
+// TestDrugo_BootReport_Reloads 测试热加载成功与失败都会追加到启动报告
+func TestDrugo_BootReport_Reloads(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "app.yaml")
+	require.NoError(t, os.WriteFile(file, []byte(reportConfig), 0644))
+	m, err := config.NewManager(dir, config.WithWatchDebounce(10*time.Millisecond))
+	require.NoError(t, err)
+
+	app := New()
+	app.logger = newTestLogManager(t)
+	app.config = m
+	require.NoError(t, app.Boot(context.Background()))
+	assert.Empty(t, app.BootReport().Reloads)
+
+	require.NoError(t, m.Watch())
+	defer m.StopWatch()
+	time.Sleep(100 * time.Millisecond) // 给监听器时间启动
+
+	require.NoError(t, os.WriteFile(file, []byte(reportConfig+"cache:\n  token: t0k\n"), 0644))
+	require.Eventually(t, func() bool { return len(app.BootReport().Reloads) == 1 }, 2*time.Second, 10*time.Millisecond)
+
+	reload := app.BootReport().Reloads[0]
+	assert.Empty(t, reload.Error)
+	assert.Equal(t, []string{"cache"}, reload.Changes.Added)
+	assert.Equal(t, []config.Change{
+		{Path: "cache", Kind: config.ChangeAdded, New: map[string]any{"token": config.RedactedValue}},
+	}, reload.Changes.Details)
+
+	require.NoError(t, os.WriteFile(file, []byte("invalid: yaml: ["), 0644))
+	require.Eventually(t, func() bool { return len(app.BootReport().Reloads) == 2 }, 2*time.Second, 10*time.Millisecond)
+	assert.NotEmpty(t, app.BootReport().Reloads[1].Error)
+
+	// 启动快照保持 Boot 时的配置
+	assert.NotContains(t, app.BootReport().Config, "cache")
+}
+
+// TestDrugo_BootReport_UnusedConfigKeys 测试启用配置读取跟踪时启动报告列出没有被读取的配置项
+func TestDrugo_BootReport_UnusedConfigKeys(t *testing.T) {
+	root := t.TempDir()
+	conf := "log:\n  level: debug\n  outputs:\n    - type: console\n  typo_level: warn\n" +
+		"db:\n  host: localhost\n  legacy: true\napp:\n  name: shop\n  debug: true\n"
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "conf"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "conf", "app.yaml"), []byte(conf), 0644))
+
+	db := &configurableMockService{ServiceMock: kerneltest.NewServiceMock("db")}
+	app := MustNewApp(WithRoot(root), WithService(db), WithConfigUsageTracking("app.debug"), WithQuiet(true))
+	require.NoError(t, app.Boot(context.Background()))
+	t.Cleanup(func() { _ = app.Shutdown(context.Background()) })
+	// 注入给 Configurable 服务的 db 整体视为已读取
+	assert.Equal(t, []string{"app.name", "log.typo_level"}, app.BootReport().UnusedConfigKeys)
+
+	app = New(WithService(kerneltest.NewServiceMock("db")))
+	app.logger = newTestLogManager(t)
+	app.config = newTestConfigManager(t, reportConfig)
+	require.NoError(t, app.Boot(context.Background()))
+	assert.Nil(t, app.BootReport().UnusedConfigKeys)
+}
+
+// TestDrugo_recordReload_Cap 测试重载记录超过上限时丢弃最早的记录
+func TestDrugo_recordReload_Cap(t *testing.T) {
+	app := New()
+	app.logger = newTestLogManager(t)
+	require.NoError(t, app.Boot(context.Background()))
+
+	for i := 0; i < MaxReloadEntries+5; i++ {
+		app.recordReload(config.Changes{Modified: []string{string(rune('a' + i%26))}}, nil)
+	}
+	reloads := app.BootReport().Reloads
+	assert.Len(t, reloads, MaxReloadEntries)
+	assert.Equal(t, []string{string(rune('a' + 5))}, reloads[0].Changes.Modified)
+}
@@ -0,0 +1,292 @@
+package drugo
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/qq1060656096/drugo/kernel"
+	"github.com/qq1060656096/drugo/pkg/clock"
+	"go.uber.org/zap"
+)
+
+// runnerHandle 记录单个 Runner 的运行状态，字段受 Drugo.runMu 保护
+type runnerHandle struct {
+	service kernel.Service
+	runner  kernel.Runner
+
+	cancel   context.CancelFunc // 取消该 Runner 独立的上下文
+	done     chan struct{}      // 该次 Run 返回后关闭
+	running  bool               // Run 正在执行
+	stopping bool               // 已通过 StopRunner 请求停止
+	stopped  bool               // 因 StopRunner 退出，可以通过 StartRunner 重新启动
+
+	startedAt time.Time // 最近一次 Run 开始的时间
+	retries   int       // 因 DecisionRetry 连续重新运行的次数，见 retryRunner
+}
+
+// runnerExit 是 Runner 的 Run 返回时发送给 Run 的事件
+type runnerExit struct {
+	name    string
+	err     error
+	stopped bool // 由 StopRunner 触发的退出，不视为运行失败
+}
+
+// superviseRunners 为每个 Runner 创建独立的子上下文并运行，直到所有 Runner 退出。
+// 任一 Runner 主动返回错误时取消所有 Runner 并返回第一个错误（由 kernel.WrapServiceRunFailed 包装），
+// 除非错误处理函数（见 WithErrorHandler）决定忽略该错误或重新运行该 Runner（见 WithRunRetry）；
+// 通过 StopRunner 停止的 Runner 不影响其他 Runner，在 ctx 取消之前可以通过 StartRunner 重新启动。
+func (d *Drugo) superviseRunners(ctx context.Context, l *zap.Logger, runners []*runnerHandle) error {
+	groupCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	events := make(chan runnerExit)
+	d.runMu.Lock()
+	d.runCtx = groupCtx
+	d.runEvents = events
+	d.runners = make(map[string]*runnerHandle, len(runners))
+	for _, h := range runners {
+		d.runners[h.service.Name()] = h
+		d.launchRunner(h)
+	}
+	d.runMu.Unlock()
+	d.timingsMu.Lock()
+	if !d.bootDone.IsZero() {
+		d.timings.RunLaunch = d.Clock().Now().Sub(d.bootDone)
+	}
+	d.timingsMu.Unlock()
+
+	var firstErr error
+	done := groupCtx.Done()
+	for {
+		d.runMu.Lock()
+		if d.runActive == 0 && (d.parkedRunners() == 0 || groupCtx.Err() != nil) {
+			// 在同一临界区内结束运行，之后的 StartRunner 返回 ErrAppNotRunning
+			d.runCtx, d.runEvents, d.runners = nil, nil, nil
+			d.runMu.Unlock()
+			return firstErr
+		}
+		d.runMu.Unlock()
+
+		select {
+		case ev := <-events:
+			d.runMu.Lock()
+			d.runActive--
+			d.runMu.Unlock()
+			if ev.stopped {
+				l.Info("service run stopped", zap.String("service", ev.name))
+				continue
+			}
+			if ev.err != nil {
+				l.Error("service run failed",
+					zap.String("service", ev.name),
+					zap.Error(ev.err),
+				)
+				wrapped := kernel.WrapServiceRunFailed(ev.name, ev.err)
+				switch d.handleError(PhaseRun, ev.name, wrapped) {
+				case DecisionContinue:
+					l.Warn("service run failure ignored by error handler", zap.String("service", ev.name))
+					continue
+				case DecisionRetry:
+					if d.retryRunner(l, ev.name) {
+						continue
+					}
+				}
+				if firstErr == nil {
+					firstErr = wrapped
+					cancel()
+				}
+			}
+		case <-done:
+			// 只需要唤醒一次，之后等待剩余 Runner 退出
+			done = nil
+		}
+	}
+}
+
+// launchRunner 在独立的子上下文中运行 h，调用方需持有 runMu
+func (d *Drugo) launchRunner(h *runnerHandle) {
+	ctx, cancel := context.WithCancel(d.runCtx)
+	done := make(chan struct{})
+	h.cancel, h.done = cancel, done
+	h.running, h.stopping, h.stopped = true, false, false
+	h.startedAt = d.Clock().Now()
+	d.runActive++
+	d.setStatus(h.service.Name(), ServiceStateRunning, nil)
+
+	events := d.runEvents
+	go func() {
+		err := d.callRecovered(PhaseRun, h.service.Name(), func() error {
+			return h.runner.Run(d.withServiceLogger(ctx, h.service))
+		})
+		cancel()
+
+		// 在 runMu 内更新状态，避免与随后的 StartRunner 交错
+		d.runMu.Lock()
+		h.running = false
+		h.stopped = h.stopping
+		stopped := h.stopped
+		if stopped {
+			d.setStatus(h.service.Name(), ServiceStateStopped, nil)
+		} else {
+			d.setStatus(h.service.Name(), ServiceStateBooted, err)
+		}
+		d.runMu.Unlock()
+		close(done)
+		events <- runnerExit{name: h.service.Name(), err: err, stopped: stopped}
+	}()
+}
+
+// retryRunner 在错误处理函数返回 DecisionRetry 时按 WithRunRetry 的策略等待后重新运行名为 name 的 Runner。
+// 连续重试次数耗尽或 Run 正在退出时返回 false，由调用方视为 DecisionFail
+func (d *Drugo) retryRunner(l *zap.Logger, name string) bool {
+	backoff, maxDelay, attempts := d.runRetryPolicy()
+	d.runMu.Lock()
+	defer d.runMu.Unlock()
+	h, ok := d.runners[name]
+	if !ok || h.running || d.runCtx == nil || d.runCtx.Err() != nil {
+		return false
+	}
+	// 持续运行了足够长时间的 Runner 不再视为连续失败
+	if d.Clock().Now().Sub(h.startedAt) >= maxDelay {
+		h.retries = 0
+	}
+	if h.retries >= attempts {
+		l.Error("service run retries exhausted", zap.String("service", name), zap.Int("attempts", attempts))
+		return false
+	}
+	delay := backoff
+	for i := 0; i < h.retries && delay < maxDelay; i++ {
+		delay *= 2
+	}
+	delay = min(delay, maxDelay)
+	h.retries++
+
+	// 等待期间计入运行中的 Runner，Run 不会在重新运行之前返回
+	d.runActive++
+	l.Info("service run restarting",
+		zap.String("service", name),
+		zap.Int("attempt", h.retries),
+		zap.Duration("delay", delay),
+	)
+	go d.relaunchAfter(d.runCtx, d.runEvents, h, delay)
+	return true
+}
+
+// relaunchAfter 等待 delay 后重新运行 h；等待期间 ctx 取消时不再运行，向 events 报告 h 已停止
+func (d *Drugo) relaunchAfter(ctx context.Context, events chan<- runnerExit, h *runnerHandle, delay time.Duration) {
+	timer := d.Clock().NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C():
+	case <-ctx.Done():
+	}
+
+	d.runMu.Lock()
+	if ctx.Err() == nil {
+		// launchRunner 重新计入运行中的 Runner
+		d.runActive--
+		d.launchRunner(h)
+		d.runMu.Unlock()
+		return
+	}
+	d.runMu.Unlock()
+	events <- runnerExit{name: h.service.Name(), stopped: true}
+}
+
+// parkedRunners 返回通过 StopRunner 停止、尚未重新启动的 Runner 数量，调用方需持有 runMu
+func (d *Drugo) parkedRunners() int {
+	n := 0
+	for _, h := range d.runners {
+		if h.stopped {
+			n++
+		}
+	}
+	return n
+}
+
+// StopRunner 停止名为 name 的 Runner，其他 Runner 继续运行。
+// 它取消该 Runner 独立的上下文，并在停机超时时间（见 WithShutdownTimeout）内等待其 Run 返回，
+// 之后服务状态为 ServiceStateStopped，可以通过 StartRunner 重新启动。
+// 停止期间 Run 返回的错误不会导致应用退出。
+//
+// name 不存在时返回 kernel.ErrServiceNotFound，服务不是 Runner 时返回 ErrNotRunner，
+// Run 未在运行时返回 ErrAppNotRunning，Runner 已停止或正在停止时返回 ErrRunnerNotRunning；
+// 等待超时返回 ctx 或超时的错误，此时 Runner 仍会在 Run 返回后记为已停止。
+func (d *Drugo) StopRunner(ctx context.Context, name string) error {
+	d.runMu.Lock()
+	h, err := d.runnerHandle(name)
+	if err != nil {
+		d.runMu.Unlock()
+		return err
+	}
+	if !h.running || h.stopping {
+		d.runMu.Unlock()
+		return fmt.Errorf("%w: %s", ErrRunnerNotRunning, name)
+	}
+	h.stopping = true
+	cancel, done := h.cancel, h.done
+	d.runMu.Unlock()
+
+	d.frameworkLogger().Info("service run stopping", zap.String("service", name))
+	cancel()
+
+	timeoutCtx, cancelTimeout := clock.WithTimeout(ctx, d.Clock(), d.shutdownTimeoutOrDefault())
+	defer cancelTimeout()
+	select {
+	case <-done:
+		return nil
+	case <-timeoutCtx.Done():
+		return fmt.Errorf("drugo: stop runner %s: %w", name, timeoutCtx.Err())
+	}
+}
+
+// StartRunner 重新启动通过 StopRunner 停止的 Runner，在同一个服务实例上再次调用 Run。
+// 新的上下文同样派生自 Run 的上下文，重新启动后主动返回的错误与其他 Runner 一样会导致应用退出。
+// ctx 只用于调用方取消，已取消时直接返回其错误。
+//
+// name 不存在时返回 kernel.ErrServiceNotFound，服务不是 Runner 时返回 ErrNotRunner，
+// Run 未在运行（或正在退出）时返回 ErrAppNotRunning，Runner 不是已停止状态时返回 ErrRunnerNotStopped。
+func (d *Drugo) StartRunner(ctx context.Context, name string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	d.runMu.Lock()
+	defer d.runMu.Unlock()
+	h, err := d.runnerHandle(name)
+	if err != nil {
+		return err
+	}
+	if d.runCtx.Err() != nil {
+		return fmt.Errorf("%w: %s", ErrAppNotRunning, name)
+	}
+	if !h.stopped {
+		return fmt.Errorf("%w: %s", ErrRunnerNotStopped, name)
+	}
+
+	d.frameworkLogger().Info("service run restarting", zap.String("service", name))
+	h.retries = 0
+	d.launchRunner(h)
+	return nil
+}
+
+// runnerHandle 查找名为 name 的 Runner，调用方需持有 runMu
+func (d *Drugo) runnerHandle(name string) (*runnerHandle, error) {
+	service, err := d.Container().Get(name)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := service.(kernel.Runner); !ok {
+		return nil, fmt.Errorf("%w: %s", ErrNotRunner, name)
+	}
+	if d.runners == nil {
+		return nil, fmt.Errorf("%w: %s", ErrAppNotRunning, name)
+	}
+	h, ok := d.runners[name]
+	if !ok {
+		// 降级或 Run 开始之后注册的 Runner 不受 Run 管理
+		return nil, fmt.Errorf("%w: %s", ErrRunnerNotRunning, name)
+	}
+	return h, nil
+}
@@ -0,0 +1,189 @@
+package drugo
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/qq1060656096/drugo/kernel"
+	"github.com/qq1060656096/drugo/kernel/kerneltest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// startRunners 启动 app 的所有 Runner 并等待它们开始运行
+func startRunners(t *testing.T, app *Drugo, runners ...*kerneltest.RunnerMock) *RunHandle {
+	t.Helper()
+	h, err := app.Start(context.Background())
+	require.NoError(t, err)
+	t.Cleanup(func() { h.Stop(context.Background()) })
+	for _, r := range runners {
+		select {
+		case <-r.Ready():
+		case <-time.After(time.Second):
+			t.Fatalf("runner %s did not start", r.Name())
+		}
+	}
+	return h
+}
+
+// TestDrugo_StopRunner 测试停止一个 Runner 时其他 Runner 继续运行，且应用不会退出
+func TestDrugo_StopRunner(t *testing.T) {
+	consumer := kerneltest.NewRunnerMock("consumer")
+	consumer.RunFunc = func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err() // 停止期间返回的错误不应导致应用退出
+	}
+	server := newBlockingRunner("server")
+	app := New(WithService(consumer), WithService(server))
+	app.logger = newTestLogManager(t)
+	h := startRunners(t, app, consumer, server)
+
+	assert.Equal(t, ServiceStateRunning, app.Status()["consumer"].State)
+	require.NoError(t, app.StopRunner(context.Background(), "consumer"))
+	assert.Equal(t, ServiceStateStopped, app.Status()["consumer"].State)
+	assert.Equal(t, ServiceStateRunning, app.Status()["server"].State)
+
+	select {
+	case <-h.Done():
+		t.Fatal("stopping one runner must not stop the app")
+	case <-server.RunContexts()[0].Done():
+		t.Fatal("stopping one runner must not cancel the others")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	// 重复停止
+	assert.ErrorIs(t, app.StopRunner(context.Background(), "consumer"), ErrRunnerNotRunning)
+
+	require.NoError(t, h.Stop(context.Background()))
+	assert.NoError(t, h.Err())
+	assert.True(t, consumer.Closed())
+}
+
+// TestDrugo_StartRunner 测试在同一个服务实例上重新启动已停止的 Runner
+func TestDrugo_StartRunner(t *testing.T) {
+	consumer := newBlockingRunner("consumer")
+	server := newBlockingRunner("server")
+	app := New(WithService(consumer), WithService(server))
+	app.logger = newTestLogManager(t)
+	h := startRunners(t, app, consumer, server)
+
+	assert.ErrorIs(t, app.StartRunner(context.Background(), "consumer"), ErrRunnerNotStopped)
+	require.NoError(t, app.StopRunner(context.Background(), "consumer"))
+	require.NoError(t, app.StartRunner(context.Background(), "consumer"))
+
+	assert.Eventually(t, func() bool { return consumer.RunCount() == 2 }, time.Second, 5*time.Millisecond)
+	assert.Equal(t, ServiceStateRunning, app.Status()["consumer"].State)
+	first := consumer.RunContexts()[0]
+	assert.Error(t, first.Err(), "the stopped run context is cancelled")
+
+	// 重新启动后仍受 Stop 控制
+	require.NoError(t, h.Stop(context.Background()))
+	assert.NoError(t, h.Err())
+	assert.Error(t, consumer.RunContexts()[1].Err())
+}
+
+// TestDrugo_StopRunner_AllStopped 测试所有 Runner 都被停止时 Run 不会返回，重新启动后恢复运行
+func TestDrugo_StopRunner_AllStopped(t *testing.T) {
+	consumer := newBlockingRunner("consumer")
+	app := New(WithService(consumer))
+	app.logger = newTestLogManager(t)
+	h := startRunners(t, app, consumer)
+
+	require.NoError(t, app.StopRunner(context.Background(), "consumer"))
+	select {
+	case <-h.Done():
+		t.Fatal("run must wait for a stopped runner to be restarted")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	require.NoError(t, app.StartRunner(context.Background(), "consumer"))
+	assert.Eventually(t, func() bool { return consumer.RunCount() == 2 }, time.Second, 5*time.Millisecond)
+
+	require.NoError(t, h.Stop(context.Background()))
+	<-h.Done()
+	assert.NoError(t, h.Err())
+	assert.ErrorIs(t, app.StartRunner(context.Background(), "consumer"), ErrAppNotRunning)
+}
+
+// TestDrugo_StartRunner_Failure 测试重新启动后主动返回的错误仍会导致应用退出
+func TestDrugo_StartRunner_Failure(t *testing.T) {
+	fail := make(chan struct{})
+	consumer := kerneltest.NewRunnerMock("consumer")
+	consumer.RunFunc = func(ctx context.Context) error {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-fail:
+			return assert.AnError
+		}
+	}
+	server := newBlockingRunner("server")
+	app := New(WithService(consumer), WithService(server))
+	app.logger = newTestLogManager(t)
+	h := startRunners(t, app, consumer, server)
+
+	require.NoError(t, app.StopRunner(context.Background(), "consumer"))
+	require.NoError(t, app.StartRunner(context.Background(), "consumer"))
+	assert.Eventually(t, func() bool { return consumer.RunCount() == 2 }, time.Second, 5*time.Millisecond)
+	close(fail)
+
+	select {
+	case <-h.Done():
+	case <-time.After(time.Second):
+		t.Fatal("runner failure did not stop the run")
+	}
+	assert.ErrorIs(t, h.Err(), assert.AnError)
+	assert.Error(t, server.RunContexts()[0].Err())
+	assert.Equal(t, ServiceStateBooted, app.Status()["consumer"].State)
+	assert.ErrorIs(t, app.Status()["consumer"].Err, assert.AnError)
+}
+
+// TestDrugo_StopRunner_Timeout 测试 Runner 未在超时时间内退出时返回超时错误
+func TestDrugo_StopRunner_Timeout(t *testing.T) {
+	release := make(chan struct{})
+	slow := kerneltest.NewRunnerMock("slow")
+	slow.RunFunc = func(ctx context.Context) error {
+		<-ctx.Done()
+		<-release
+		return nil
+	}
+	c := kernel.NewFakeClock(time.Time{})
+	app := New(WithService(slow), WithShutdownTimeout(time.Hour), WithClock(c))
+	app.logger = newTestLogManager(t)
+	startRunners(t, app, slow)
+
+	errCh := make(chan error, 1)
+	go func() { errCh <- app.StopRunner(context.Background(), "slow") }()
+	c.BlockUntilWaiters(1)
+	c.Advance(time.Hour)
+	err := <-errCh
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.ErrorIs(t, app.StopRunner(context.Background(), "slow"), ErrRunnerNotRunning, "stop already in progress")
+
+	close(release)
+	assert.Eventually(t, func() bool { return app.Status()["slow"].State == ServiceStateStopped },
+		time.Second, 5*time.Millisecond)
+}
+
+// TestDrugo_StopRunner_Errors 测试未知名称、非 Runner 服务以及 Run 未运行时的错误
+func TestDrugo_StopRunner_Errors(t *testing.T) {
+	runner := newBlockingRunner("runner")
+	app := New(WithService(kerneltest.NewServiceMock("db")), WithService(runner))
+	app.logger = newTestLogManager(t)
+	ctx := context.Background()
+
+	assert.ErrorIs(t, app.StopRunner(ctx, "runner"), ErrAppNotRunning)
+	assert.ErrorIs(t, app.StartRunner(ctx, "runner"), ErrAppNotRunning)
+
+	startRunners(t, app, runner)
+	for _, op := range []func(context.Context, string) error{app.StopRunner, app.StartRunner} {
+		assert.True(t, kernel.IsServiceNotFound(op(ctx, "missing")))
+		assert.ErrorIs(t, op(ctx, "db"), ErrNotRunner)
+	}
+
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	assert.True(t, errors.Is(app.StartRunner(cancelled, "runner"), context.Canceled))
+}
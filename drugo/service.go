@@ -1,6 +1,11 @@
 package drugo
 
-import "github.com/qq1060656096/drugo/kernel"
+import (
+	"context"
+	"time"
+
+	"github.com/qq1060656096/drugo/kernel"
+)
 
 // GetService 从 Kernel 中获取指定名称和类型的服务。
 // 它是 kernel.GetService 的门面封装，保证用户只依赖 drugo 包。
@@ -14,3 +19,28 @@ func GetService[T any](k kernel.Kernel, name string) (T, error) {
 func MustGetService[T any](k kernel.Kernel, name string) T {
 	return kernel.MustGetService[T](k, name)
 }
+
+// TryGetService 尝试从 Kernel 中获取指定名称和类型的可选服务。
+// 服务未注册时返回 ok=false，类型不匹配时 panic。
+// 它是 kernel.TryGetService 的门面封装。
+func TryGetService[T any](k kernel.Kernel, name string) (T, bool) {
+	return kernel.TryGetService[T](k, name)
+}
+
+// NewRef 创建 k 中名称为 name、类型为 T 的服务的延迟引用，首次 Get 时才解析。
+// 它是 kernel.NewRef 的门面封装，用于打破服务之间在 Boot 阶段的循环依赖。
+func NewRef[T any](k kernel.Kernel, name string, opts ...kernel.RefOption) *kernel.Ref[T] {
+	return kernel.NewRef[T](k, name, opts...)
+}
+
+// RefFromContext 使用 ctx 中携带的 Kernel 创建服务的延迟引用。
+// 它是 kernel.RefFromContext 的门面封装，通常在服务的 Boot 中调用。
+func RefFromContext[T any](ctx context.Context, name string, opts ...kernel.RefOption) *kernel.Ref[T] {
+	return kernel.RefFromContext[T](ctx, name, opts...)
+}
+
+// WithRefWaitBooted 使延迟引用在解析前等待目标服务完成 Boot。
+// 它是 kernel.WithWaitBooted 的门面封装。
+func WithRefWaitBooted(timeout time.Duration) kernel.RefOption {
+	return kernel.WithWaitBooted(timeout)
+}
@@ -0,0 +1,136 @@
+package drugo
+
+import (
+	"maps"
+	"slices"
+
+	"github.com/qq1060656096/drugo/kernel"
+	"go.uber.org/zap"
+)
+
+// shutdownOrder 返回 Shutdown 关闭服务的顺序：依赖方先于被依赖的服务关闭，
+// 不受依赖关系约束的服务按注册顺序的逆序关闭，没有声明任何依赖时与逆序完全相同。
+//
+// 依赖关系来自 kernel.Dependent、kernel.CapabilityRequirer 以及 WithProvider 构造函数的参数，
+// services 包含 Boot 期间动态注册的服务。依赖关系存在循环（例如动态注册的服务引入了循环）时
+// 不拒绝停机，而是输出警告并退回注册顺序的逆序
+func (d *Drugo) shutdownOrder(l *zap.Logger, services []kernel.Service) []kernel.Service {
+	deps := d.dependencyGraph(services)
+
+	// dependents[j] 是尚未关闭的依赖 j 的服务数量，为 0 时 j 可以关闭
+	dependents := make([]int, len(services))
+	for i := range services {
+		for _, j := range deps[i] {
+			dependents[j]++
+		}
+	}
+
+	ordered := make([]kernel.Service, 0, len(services))
+	closed := make([]bool, len(services))
+	for len(ordered) < len(services) {
+		// 可以关闭的服务中选择注册最晚的一个，保持未声明依赖的服务按逆序关闭
+		next := -1
+		for i := len(services) - 1; i >= 0; i-- {
+			if !closed[i] && dependents[i] == 0 {
+				next = i
+				break
+			}
+		}
+		if next < 0 {
+			l.Warn("service dependency cycle at shutdown, fallback to reverse registration order",
+				zap.Strings("services", pendingNames(services, closed)))
+			ordered = slices.Clone(services)
+			slices.Reverse(ordered)
+			return ordered
+		}
+		closed[next] = true
+		ordered = append(ordered, services[next])
+		for _, j := range deps[next] {
+			dependents[j]--
+		}
+	}
+	return ordered
+}
+
+// bootOrder 返回 Boot 初始化 services 的顺序：被依赖的服务先于依赖方初始化，
+// 不受依赖关系约束的服务按注册顺序初始化，没有声明任何依赖时与注册顺序完全相同。
+//
+// 依赖关系与 shutdownOrder 相同，只考虑 services 内部的依赖：Boot 的每一轮只传入该轮待初始化的服务，
+// 之前轮次的服务已经初始化完成。依赖关系存在循环时输出警告并退回注册顺序
+func (d *Drugo) bootOrder(l *zap.Logger, services []kernel.Service) []kernel.Service {
+	deps := d.dependencyGraph(services)
+
+	ordered := make([]kernel.Service, 0, len(services))
+	booted := make([]bool, len(services))
+	for len(ordered) < len(services) {
+		// 依赖都已初始化的服务中选择注册最早的一个
+		next := -1
+		for i := range services {
+			if !booted[i] && !slices.ContainsFunc(deps[i], func(j int) bool { return !booted[j] }) {
+				next = i
+				break
+			}
+		}
+		if next < 0 {
+			l.Warn("service dependency cycle at boot, fallback to registration order",
+				zap.Strings("services", pendingNames(services, booted)))
+			return slices.Clone(services)
+		}
+		booted[next] = true
+		ordered = append(ordered, services[next])
+	}
+	return ordered
+}
+
+// dependencyGraph 返回 services 之间的依赖关系，deps[i] 是 services[i] 依赖的服务下标，
+// 忽略不在 services 中的服务与自身依赖
+func (d *Drugo) dependencyGraph(services []kernel.Service) [][]int {
+	index := make(map[string]int, len(services))
+	for i, service := range services {
+		index[service.Name()] = i
+	}
+
+	deps := make([][]int, len(services))
+	for i, service := range services {
+		for _, name := range d.serviceDependencies(service) {
+			j, ok := index[name]
+			if !ok || j == i || slices.Contains(deps[i], j) {
+				continue
+			}
+			deps[i] = append(deps[i], j)
+		}
+	}
+	return deps
+}
+
+// pendingNames 返回 done 中尚未完成的服务名称
+func pendingNames(services []kernel.Service, done []bool) []string {
+	var names []string
+	for i, service := range services {
+		if !done[i] {
+			names = append(names, service.Name())
+		}
+	}
+	return names
+}
+
+// serviceDependencies 返回服务依赖的服务名称，可能包含未注册的服务
+func (d *Drugo) serviceDependencies(service kernel.Service) []string {
+	var names []string
+	if dep, ok := service.(kernel.Dependent); ok {
+		names = append(names, dep.DependsOn()...)
+	}
+	if requirer, ok := service.(kernel.CapabilityRequirer); ok {
+		names = append(names, slices.Sorted(maps.Keys(requirer.RequiredCapabilities()))...)
+	}
+	return append(names, d.providerDeps[service.Name()]...)
+}
+
+// serviceNamesOf 返回服务名称列表
+func serviceNamesOf(services []kernel.Service) []string {
+	names := make([]string, len(services))
+	for i, service := range services {
+		names[i] = service.Name()
+	}
+	return names
+}
@@ -0,0 +1,188 @@
+package drugo
+
+import (
+	"context"
+	"testing"
+
+	"github.com/qq1060656096/drugo/kernel"
+	"github.com/qq1060656096/drugo/kernel/kerneltest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// dependentService 是声明依赖的测试服务，Close 时将名称追加到 closed
+type dependentService struct {
+	*kerneltest.ServiceMock
+	deps []string
+}
+
+func (s *dependentService) DependsOn() []string { return s.deps }
+
+// newDependentService 创建依赖 deps 的服务，Close 时将名称追加到 closed
+func newDependentService(name string, closed *[]string, deps ...string) *dependentService {
+	s := &dependentService{ServiceMock: kerneltest.NewServiceMock(name), deps: deps}
+	s.CloseFunc = func(context.Context) error {
+		*closed = append(*closed, name)
+		return nil
+	}
+	return s
+}
+
+// newOrderApp 创建注册了 services 的应用，框架日志（包括 debug）写入返回的 observer
+func newOrderApp(services ...kernel.Service) (*Drugo, *observer.ObservedLogs) {
+	app := New(WithServices(services...))
+	core, logs := observer.New(zapcore.DebugLevel)
+	app.fwFallback = zap.New(core)
+	return app, logs
+}
+
+// TestDrugo_Shutdown_DependencyOrder 测试依赖方先于被依赖的服务关闭，包括 Boot 中动态注册的服务，
+// 没有依赖关系的服务仍按注册顺序的逆序关闭
+func TestDrugo_Shutdown_DependencyOrder(t *testing.T) {
+	var closed []string
+	// api 先于 db 注册，逆序关闭会先关闭 db
+	api := newDependentService("api", &closed, "db", "unknown")
+	db := newDependentService("db", &closed)
+	metrics := newDependentService("metrics", &closed)
+	// loader 在 Boot 中先注册依赖 store 的 plugin，再注册 store
+	loader := newDependentService("loader", &closed)
+	loader.BootFunc = func(ctx context.Context) error {
+		k := kernel.MustFromContext(ctx)
+		k.Container().Bind("plugin", newDependentService("plugin", &closed, "store"))
+		k.Container().Bind("store", newDependentService("store", &closed))
+		return nil
+	}
+	app, logs := newOrderApp(api, db, metrics, loader)
+
+	require.NoError(t, app.Boot(context.Background()))
+	require.NoError(t, app.Shutdown(context.Background()))
+	assert.Equal(t, []string{"plugin", "store", "loader", "metrics", "api", "db"}, closed)
+
+	entries := logs.FilterMessage("framework shutdown order").All()
+	require.Len(t, entries, 1)
+	assert.Equal(t, zapcore.DebugLevel, entries[0].Level)
+	assert.Equal(t, []any{"plugin", "store", "loader", "metrics", "api", "db"}, entries[0].ContextMap()["services"])
+	assert.Empty(t, logs.FilterMessage("service dependency cycle at shutdown, fallback to reverse registration order").All())
+}
+
+// TestDrugo_Shutdown_DependencyCycle 测试依赖关系存在循环时输出警告并按注册顺序的逆序关闭所有服务
+func TestDrugo_Shutdown_DependencyCycle(t *testing.T) {
+	var closed []string
+	app, logs := newOrderApp(
+		newDependentService("a", &closed, "b"),
+		newDependentService("b", &closed, "a"),
+		newDependentService("c", &closed),
+	)
+
+	require.NoError(t, app.Boot(context.Background()))
+	require.NoError(t, app.Shutdown(context.Background()))
+	assert.Equal(t, []string{"c", "b", "a"}, closed)
+
+	entries := logs.FilterMessage("service dependency cycle at shutdown, fallback to reverse registration order").All()
+	require.Len(t, entries, 1)
+	assert.Equal(t, []any{"a", "b"}, entries[0].ContextMap()["services"])
+}
+
+// recordBoot 使 services 在 Boot 时将名称追加到 booted
+func recordBoot(booted *[]string, services ...*dependentService) {
+	for _, s := range services {
+		name, boot := s.Name(), s.BootFunc
+		s.BootFunc = func(ctx context.Context) error {
+			*booted = append(*booted, name)
+			if boot != nil {
+				return boot(ctx)
+			}
+			return nil
+		}
+	}
+}
+
+// TestDrugo_Boot_DependencyOrder 测试被依赖的服务先于依赖方初始化，包括 Boot 中动态注册的服务，
+// 没有依赖关系的服务仍按注册顺序初始化，Boot 与 Shutdown 的顺序互为逆序
+func TestDrugo_Boot_DependencyOrder(t *testing.T) {
+	var booted, closed []string
+	// api 先于 db 注册，按注册顺序初始化会先初始化 api
+	api := newDependentService("api", &closed, "db", "unknown")
+	db := newDependentService("db", &closed)
+	metrics := newDependentService("metrics", &closed)
+	// loader 在 Boot 中先注册依赖 store 的 plugin，再注册 store
+	loader := newDependentService("loader", &closed)
+	loader.BootFunc = func(ctx context.Context) error {
+		k := kernel.MustFromContext(ctx)
+		plugin := newDependentService("plugin", &closed, "store", "db")
+		store := newDependentService("store", &closed)
+		recordBoot(&booted, plugin, store)
+		k.Container().Bind("plugin", plugin)
+		k.Container().Bind("store", store)
+		return nil
+	}
+	recordBoot(&booted, api, db, metrics, loader)
+	app, logs := newOrderApp(api, db, metrics, loader)
+
+	require.NoError(t, app.Boot(context.Background()))
+	assert.Equal(t, []string{"db", "api", "metrics", "loader", "store", "plugin"}, booted)
+	assert.Empty(t, logs.FilterMessage("service dependency cycle at boot, fallback to registration order").All())
+
+	require.NoError(t, app.Shutdown(context.Background()))
+	assert.Equal(t, []string{"plugin", "store", "loader", "metrics", "api", "db"}, closed)
+}
+
+// TestDrugo_Boot_DependencyCycle 测试依赖关系存在循环时输出警告并按注册顺序初始化所有服务
+func TestDrugo_Boot_DependencyCycle(t *testing.T) {
+	var booted, closed []string
+	a := newDependentService("a", &closed, "b")
+	b := newDependentService("b", &closed, "a")
+	c := newDependentService("c", &closed, "a")
+	recordBoot(&booted, a, b, c)
+	app, logs := newOrderApp(c, a, b)
+
+	require.NoError(t, app.Boot(context.Background()))
+	assert.Equal(t, []string{"c", "a", "b"}, booted)
+
+	entries := logs.FilterMessage("service dependency cycle at boot, fallback to registration order").All()
+	require.Len(t, entries, 1)
+	assert.Equal(t, []any{"c", "a", "b"}, entries[0].ContextMap()["services"])
+}
+
+// TestDrugo_Shutdown_CapabilityDependency 测试 kernel.CapabilityRequirer 声明的服务同样视为依赖
+func TestDrugo_Shutdown_CapabilityDependency(t *testing.T) {
+	var closed []string
+	report := &capabilityService{
+		ServiceMock: kerneltest.NewServiceMock("report"),
+		requires:    map[string][]string{"db": {"tx"}},
+	}
+	report.CloseFunc = func(context.Context) error {
+		closed = append(closed, "report")
+		return nil
+	}
+	db := &capabilityService{ServiceMock: kerneltest.NewServiceMock("db"), caps: map[string]string{"tx": ""}}
+	db.CloseFunc = func(context.Context) error {
+		closed = append(closed, "db")
+		return nil
+	}
+	app, _ := newOrderApp(report, db)
+
+	require.NoError(t, app.Boot(context.Background()))
+	require.NoError(t, app.Shutdown(context.Background()))
+	assert.Equal(t, []string{"report", "db"}, closed)
+}
+
+// orderRepo 是构造函数之间传递的非服务类型
+type orderRepo struct{ db *kerneltest.ServiceMock }
+
+// TestDrugo_ProviderDependencies 测试 WithProvider 创建的服务经由非服务类型的参数间接依赖的服务
+func TestDrugo_ProviderDependencies(t *testing.T) {
+	app, err := NewE(
+		WithProvider(func() *kerneltest.ServiceMock { return kerneltest.NewServiceMock("db") }),
+		WithProvider(func(db *kerneltest.ServiceMock) *orderRepo { return &orderRepo{db: db} }),
+		WithProvider(func(repo *orderRepo, k kernel.Kernel) *dependentService {
+			return &dependentService{ServiceMock: kerneltest.NewServiceMock("users")}
+		}),
+	)
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]string{"users": {"db"}}, app.providerDeps)
+	assert.Equal(t, []string{"db"}, app.serviceDependencies(app.Container().MustGet("users")))
+}
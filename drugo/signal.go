@@ -0,0 +1,106 @@
+package drugo
+
+import (
+	"context"
+	"os"
+	"os/signal"
+
+	"go.uber.org/zap"
+)
+
+// SignalHandler 是收到自定义信号时执行的处理函数。
+// 处理函数在 Serve 的主流程中同步执行，不会触发停机。
+type SignalHandler func(ctx context.Context, d *Drugo)
+
+// WithSignalHandler 注册一个自定义信号的处理函数。
+// Serve 运行期间收到 sig 时调用 handler，同一信号可以注册多个处理函数，按注册顺序执行。
+// 注意：停机信号（默认 SIGINT/SIGTERM，见 WithShutdownSignals）始终会触发优雅停机，不建议为它们注册处理函数；
+// 使用 WithDisableSignals 时处理函数不会被调用。
+func WithSignalHandler(sig os.Signal, handler SignalHandler) Option {
+	return func(o *options) {
+		if o.signalHandlers == nil {
+			o.signalHandlers = make(map[os.Signal][]SignalHandler)
+		}
+		o.signalHandlers[sig] = append(o.signalHandlers[sig], handler)
+	}
+}
+
+// signals 返回所有注册了处理函数的信号。
+func (d *Drugo) signals() []os.Signal {
+	sigs := make([]os.Signal, 0, len(d.signalHandlers))
+	for sig := range d.signalHandlers {
+		sigs = append(sigs, sig)
+	}
+	return sigs
+}
+
+// handleSignal 依次执行 sig 对应的所有处理函数。
+func (d *Drugo) handleSignal(ctx context.Context, sig os.Signal) {
+	l := d.frameworkLogger()
+	l.Info("receive signal, running handlers", zap.String("signal", sig.String()))
+
+	for _, handler := range d.signalHandlers[sig] {
+		handler(ctx, d)
+	}
+}
+
+// signalNotify 与 signalStop 注册和注销信号通知，测试中可以替换以断言 Serve 注册了哪些信号
+var (
+	signalNotify = signal.Notify
+	signalStop   = signal.Stop
+)
+
+// WithShutdownSignals 设置 Serve 触发优雅停机（以及需要 Boot 的子命令取消上下文）的信号，默认为 SIGINT 与 SIGTERM；
+// Windows 上默认为 os.Interrupt（Ctrl+C、Ctrl+Break）与 SIGTERM（控制台关闭、注销与系统关机）。
+// 不能与 WithDisableSignals 同时使用，否则 NewE 返回 ErrSignalOptionConflict
+func WithShutdownSignals(sigs ...os.Signal) Option {
+	return func(o *options) {
+		o.shutdownSignals = append(o.shutdownSignals, sigs...)
+	}
+}
+
+// WithDisableSignals 禁用 Serve 与子命令（见 Execute）的信号处理，它们都不会调用 signal.Notify，
+// 适用于宿主进程（systemd 集成、父进程监管、测试等）已经负责信号处理的场景。
+// 此时停机由 ctx 取消、Run 结束或 Drugo.Stop 触发，WithSignalHandler 注册的处理函数不会被调用。
+// 不能与 WithShutdownSignals 同时使用，否则 NewE 返回 ErrSignalOptionConflict
+func WithDisableSignals() Option {
+	return func(o *options) {
+		o.disableSignals = true
+	}
+}
+
+// shutdownSignalsOrDefault 返回触发优雅停机的信号
+func (d *Drugo) shutdownSignalsOrDefault() []os.Signal {
+	if len(d.shutdownSignals) > 0 {
+		return d.shutdownSignals
+	}
+	return defaultShutdownSignals
+}
+
+// shutdownContext 返回收到停机信号时取消的 ctx 子上下文，与 Serve 一样遵循 WithShutdownSignals 与 WithDisableSignals：
+// 禁用信号处理时不注册任何信号。调用返回的 stop 注销信号通知并取消上下文
+func (d *Drugo) shutdownContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(ctx)
+	if d.disableSignals {
+		return ctx, cancel
+	}
+	quit := make(chan os.Signal, 1)
+	signalNotify(quit, d.shutdownSignalsOrDefault()...)
+	go func() {
+		select {
+		case <-quit:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+	return ctx, func() {
+		signalStop(quit)
+		cancel()
+	}
+}
+
+// Stop 请求正在运行的 Serve 开始优雅停机，效果等同于收到停机信号，可以重复调用。
+// 在 Serve 之前调用时，Serve 完成 Boot 后立即停机。通过 Start 启动时请使用 RunHandle.Stop
+func (d *Drugo) Stop() {
+	d.stopOnce.Do(func() { close(d.stopCh) })
+}
@@ -0,0 +1,171 @@
+package drugo
+
+import (
+	"context"
+	"os"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/qq1060656096/drugo/kernel"
+	"github.com/qq1060656096/drugo/kernel/kerneltest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// notifyRecorder 替换 signalNotify/signalStop，记录 Serve 注册的信号
+type notifyRecorder struct {
+	mu      sync.Mutex
+	signals []os.Signal
+}
+
+func recordSignalNotify(t *testing.T) *notifyRecorder {
+	t.Helper()
+	rec := &notifyRecorder{}
+	origNotify, origStop := signalNotify, signalStop
+	signalNotify = func(c chan<- os.Signal, sig ...os.Signal) {
+		rec.mu.Lock()
+		defer rec.mu.Unlock()
+		rec.signals = append(rec.signals, sig...)
+	}
+	signalStop = func(c chan<- os.Signal) {}
+	t.Cleanup(func() { signalNotify, signalStop = origNotify, origStop })
+	return rec
+}
+
+func (r *notifyRecorder) Signals() []os.Signal {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]os.Signal(nil), r.signals...)
+}
+
+// serveAsync 在后台运行 Serve，等待 runner 启动后返回结果 channel
+func serveAsync(t *testing.T, ctx context.Context, app *Drugo, runner *kerneltest.RunnerMock) <-chan error {
+	t.Helper()
+	done := make(chan error, 1)
+	go func() { done <- app.Serve(ctx) }()
+	require.Eventually(t, func() bool { return runner.RunCount() == 1 }, time.Second, 5*time.Millisecond)
+	return done
+}
+
+// waitServe 等待 Serve 返回
+func waitServe(t *testing.T, done <-chan error) error {
+	t.Helper()
+	select {
+	case err := <-done:
+		return err
+	case <-time.After(2 * time.Second):
+		t.Fatal("serve did not return")
+		return nil
+	}
+}
+
+// TestDrugo_Serve_DisableSignals 测试禁用信号后 Serve 不注册任何信号，ctx 取消或 Stop 触发停机
+func TestDrugo_Serve_DisableSignals(t *testing.T) {
+	tests := []struct {
+		name     string
+		shutdown func(cancel context.CancelFunc, app *Drugo)
+	}{
+		{"context canceled", func(cancel context.CancelFunc, app *Drugo) { cancel() }},
+		{"stop", func(cancel context.CancelFunc, app *Drugo) { app.Stop(); app.Stop() }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := recordSignalNotify(t)
+			runner := newBlockingRunner("server")
+			app := New(
+				WithService(runner),
+				WithDisableSignals(),
+				WithSignalHandler(syscall.SIGINT, func(ctx context.Context, d *Drugo) {}),
+			)
+			app.logger = newTestLogManager(t)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			done := serveAsync(t, ctx, app, runner)
+			tt.shutdown(cancel, app)
+
+			require.NoError(t, waitServe(t, done))
+			assert.True(t, runner.Closed())
+			assert.Empty(t, rec.Signals(), "no signal is registered when signals are disabled")
+		})
+	}
+}
+
+// TestDrugo_Serve_DisableSignals_NoRunners 测试禁用信号且没有 Runner 时，Serve 在 Boot 与 Run 完成后直接退出
+func TestDrugo_Serve_DisableSignals_NoRunners(t *testing.T) {
+	rec := recordSignalNotify(t)
+	service := kerneltest.NewServiceMock("db")
+	app := New(WithService(service), WithDisableSignals())
+	app.logger = newTestLogManager(t)
+
+	done := make(chan error, 1)
+	go func() { done <- app.Serve(context.Background()) }()
+
+	require.NoError(t, waitServe(t, done))
+	assert.Equal(t, 1, service.BootCount())
+	assert.True(t, service.Closed())
+	assert.Empty(t, rec.Signals())
+}
+
+// TestDrugo_Serve_ShutdownSignals 测试默认注册 SIGINT/SIGTERM，WithShutdownSignals 替换停机信号
+func TestDrugo_Serve_ShutdownSignals(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []Option
+		want []os.Signal
+	}{
+		{"default", nil, []os.Signal{syscall.SIGINT, syscall.SIGTERM}},
+		{"custom", []Option{WithShutdownSignals(syscall.SIGTERM)}, []os.Signal{syscall.SIGTERM}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := recordSignalNotify(t)
+			runner := newBlockingRunner("server")
+			app := New(append(tt.opts, WithService(runner))...)
+			app.logger = newTestLogManager(t)
+
+			done := serveAsync(t, context.Background(), app, runner)
+			assert.Equal(t, tt.want, rec.Signals())
+			app.Stop()
+			require.NoError(t, waitServe(t, done))
+		})
+	}
+}
+
+// TestNewE_SignalOptionConflict 测试 WithDisableSignals 与 WithShutdownSignals 不能同时使用
+func TestNewE_SignalOptionConflict(t *testing.T) {
+	app, err := NewE(WithDisableSignals(), WithShutdownSignals(syscall.SIGTERM))
+	assert.Nil(t, app)
+	require.ErrorIs(t, err, ErrSignalOptionConflict)
+	assert.ErrorContains(t, err, "WithDisableSignals and WithShutdownSignals")
+}
+
+// TestDrugo_Execute_Signals 测试需要 Boot 的子命令遵循 WithDisableSignals 与 WithShutdownSignals
+func TestDrugo_Execute_Signals(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []Option
+		want []os.Signal
+	}{
+		{"default", nil, defaultShutdownSignals},
+		{"disabled", []Option{WithDisableSignals()}, nil},
+		{"custom", []Option{WithShutdownSignals(syscall.SIGTERM)}, []os.Signal{syscall.SIGTERM}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := recordSignalNotify(t)
+			app, _ := newTestCommandApp(t, tt.opts...)
+			called := false
+			app.Command("migrate", "执行数据库迁移", func(ctx context.Context, k kernel.Kernel, args []string) error {
+				called = true
+				return nil
+			})
+
+			require.NoError(t, app.Execute(context.Background(), []string{"app", "migrate"}))
+			assert.True(t, called)
+			assert.Equal(t, tt.want, rec.Signals())
+		})
+	}
+}
@@ -0,0 +1,36 @@
+//go:build !windows
+
+package drugo
+
+import (
+	"context"
+	"os"
+	"syscall"
+
+	"go.uber.org/zap"
+)
+
+// defaultShutdownSignals 是未设置 WithShutdownSignals 时触发优雅停机的信号
+var defaultShutdownSignals = []os.Signal{syscall.SIGINT, syscall.SIGTERM}
+
+// watchPlatformStop 监听平台特有的停机请求，非 Windows 平台只使用信号，返回的函数不做任何事
+func (d *Drugo) watchPlatformStop(h *RunHandle) (stop func()) {
+	return func() {}
+}
+
+// RotateLogsOnUSR1 在收到 SIGUSR1 时轮转所有日志文件，
+// 便于与基于 logrotate 的运维工具配合使用。
+func RotateLogsOnUSR1() Option {
+	return WithSignalHandler(syscall.SIGUSR1, func(ctx context.Context, d *Drugo) {
+		l := d.frameworkLogger()
+		if d.Logger() == nil {
+			l.Warn("rotate logs skipped, log manager is nil")
+			return
+		}
+		if err := d.Logger().RotateAll(); err != nil {
+			l.Error("rotate logs failed", zap.Error(err))
+			return
+		}
+		l.Info("rotate logs complete")
+	})
+}
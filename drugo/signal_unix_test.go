@@ -0,0 +1,86 @@
+//go:build !windows
+
+package drugo
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/qq1060656096/drugo/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestDrugo_Serve_SignalHandler 测试自定义信号处理函数被调用且不会触发停机
+func TestDrugo_Serve_SignalHandler(t *testing.T) {
+	runner := newBlockingRunner("runner")
+	handled := make(chan struct{}, 1)
+	app := New(
+		WithService(runner),
+		WithSignalHandler(syscall.SIGUSR2, func(ctx context.Context, d *Drugo) {
+			handled <- struct{}{}
+		}),
+	)
+	app.logger = newTestLogManager(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- app.Serve(ctx) }()
+
+	time.Sleep(50 * time.Millisecond)
+	require.NoError(t, syscall.Kill(os.Getpid(), syscall.SIGUSR2))
+
+	select {
+	case <-handled:
+	case <-time.After(time.Second):
+		t.Fatal("signal handler was not called")
+	}
+
+	// 自定义信号不应触发停机
+	select {
+	case <-done:
+		t.Fatal("serve should keep running after custom signal")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	cancel()
+	require.NoError(t, <-done)
+}
+
+// TestRotateLogsOnUSR1 测试 SIGUSR1 触发日志轮转
+func TestRotateLogsOnUSR1(t *testing.T) {
+	dir := t.TempDir()
+	logger, err := log.NewManager(log.Config{
+		Level: "info",
+		Outputs: []log.OutputConfig{
+			{Type: "file", Format: "json", File: &log.FileOutputConfig{Dir: dir}},
+		},
+	})
+	require.NoError(t, err)
+	defer logger.Close()
+
+	app := New(
+		WithService(newBlockingRunner("runner")),
+		RotateLogsOnUSR1(),
+	)
+	app.logger = logger
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- app.Serve(ctx) }()
+
+	time.Sleep(50 * time.Millisecond)
+	require.NoError(t, syscall.Kill(os.Getpid(), syscall.SIGUSR1))
+
+	assert.Eventually(t, func() bool {
+		matches, _ := filepath.Glob(filepath.Join(dir, "drugo-*.log"))
+		return len(matches) == 1
+	}, time.Second, 10*time.Millisecond)
+
+	cancel()
+	require.NoError(t, <-done)
+}
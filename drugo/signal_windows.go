@@ -0,0 +1,87 @@
+//go:build windows
+
+package drugo
+
+import (
+	"os"
+	"syscall"
+	"time"
+
+	"go.uber.org/zap"
+	"golang.org/x/sys/windows/svc"
+)
+
+// defaultShutdownSignals 是未设置 WithShutdownSignals 时触发优雅停机的信号。
+// Go 运行时将 CTRL_C_EVENT 与 CTRL_BREAK_EVENT 转换为 os.Interrupt，
+// 将 CTRL_CLOSE_EVENT、CTRL_LOGOFF_EVENT 与 CTRL_SHUTDOWN_EVENT 转换为 SIGTERM，
+// 并在处理函数返回之前阻止系统结束进程（系统仍会在 CTRL_CLOSE_EVENT 约 5 秒后强制结束进程）。
+var defaultShutdownSignals = []os.Signal{os.Interrupt, syscall.SIGTERM}
+
+// serviceStopWait 是 Serve 返回后等待服务控制处理器退出的最长时间
+const serviceStopWait = 5 * time.Second
+
+// isWindowsService 与 runService 在测试中可以替换
+var (
+	isWindowsService = svc.IsWindowsService
+	runService       = svc.Run
+)
+
+// watchPlatformStop 在进程作为 Windows 服务运行时注册服务控制处理器：
+// 服务控制管理器的停止或关机请求会调用 Drugo.Stop 触发优雅停机，服务在 Serve 返回后才报告为已停止，
+// 退出码为 ExitCode。返回的函数在 Serve 返回前调用，等待处理器退出。
+// 禁用信号处理（WithDisableSignals）时由宿主进程负责服务控制，不注册处理器。
+func (d *Drugo) watchPlatformStop(h *RunHandle) (stop func()) {
+	if d.disableSignals {
+		return func() {}
+	}
+	ok, err := isWindowsService()
+	if err != nil || !ok {
+		return func() {}
+	}
+
+	l := d.frameworkLogger()
+	served := make(chan struct{})
+	exited := make(chan struct{})
+	go func() {
+		defer close(exited)
+		if err := runService("", &serviceHandler{d: d, served: served}); err != nil {
+			l.Error("windows service control failed", zap.Error(err))
+		}
+	}()
+	return func() {
+		close(served)
+		select {
+		case <-exited:
+		case <-time.After(serviceStopWait):
+			l.Warn("windows service control handler did not exit", zap.Duration("wait", serviceStopWait))
+		}
+	}
+}
+
+// serviceHandler 实现 svc.Handler，将服务控制请求转换为 Drugo.Stop
+type serviceHandler struct {
+	d      *Drugo
+	served <-chan struct{} // Serve 返回时关闭
+}
+
+// Execute 报告服务正在运行，收到停止或关机请求时触发优雅停机，并在 Serve 返回后以 ExitCode 报告服务已停止
+func (s *serviceHandler) Execute(args []string, requests <-chan svc.ChangeRequest, status chan<- svc.Status) (bool, uint32) {
+	const accepts = svc.AcceptStop | svc.AcceptShutdown
+	status <- svc.Status{State: svc.Running, Accepts: accepts}
+	for {
+		select {
+		case req := <-requests:
+			switch req.Cmd {
+			case svc.Interrogate:
+				status <- req.CurrentStatus
+			case svc.Stop, svc.Shutdown:
+				s.d.frameworkLogger().Info("windows service stop requested, initiating graceful shutdown")
+				status <- svc.Status{State: svc.StopPending}
+				s.d.Stop()
+			}
+		case <-s.served:
+			code := uint32(s.d.ExitCode())
+			return code != 0, code
+		}
+	}
+}
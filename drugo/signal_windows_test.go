@@ -0,0 +1,53 @@
+//go:build windows
+
+package drugo
+
+import (
+	"context"
+	"os"
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/sys/windows/svc"
+)
+
+// TestDefaultShutdownSignals_Windows 测试 Windows 上默认的停机信号
+func TestDefaultShutdownSignals_Windows(t *testing.T) {
+	assert.Equal(t, []os.Signal{os.Interrupt, syscall.SIGTERM}, New().shutdownSignalsOrDefault())
+}
+
+// TestDrugo_Serve_WindowsServiceStop 测试服务控制管理器的停止请求触发优雅停机，并以 ExitCode 报告服务已停止
+func TestDrugo_Serve_WindowsServiceStop(t *testing.T) {
+	type result struct {
+		specific bool
+		code     uint32
+	}
+	results := make(chan result, 1)
+	states := make(chan svc.State, 8)
+	origIs, origRun := isWindowsService, runService
+	isWindowsService = func() (bool, error) { return true, nil }
+	runService = func(name string, h svc.Handler) error {
+		requests := make(chan svc.ChangeRequest, 1)
+		status := make(chan svc.Status, 8)
+		go func() {
+			for st := range status {
+				states <- st.State
+			}
+		}()
+		requests <- svc.ChangeRequest{Cmd: svc.Stop}
+		specific, code := h.Execute(nil, requests, status)
+		results <- result{specific, code}
+		return nil
+	}
+	t.Cleanup(func() { isWindowsService, runService = origIs, origRun })
+
+	app := New(WithService(newBlockingRunner("runner")), WithExitCodes(map[Outcome]int{OutcomeClean: 3}))
+	app.logger = newTestLogManager(t)
+	require.NoError(t, app.Serve(context.Background()))
+
+	assert.Equal(t, result{true, 3}, <-results)
+	assert.Equal(t, svc.Running, <-states)
+	assert.Equal(t, svc.StopPending, <-states)
+}
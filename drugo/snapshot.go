@@ -0,0 +1,299 @@
+package drugo
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/qq1060656096/drugo/kernel"
+)
+
+// SnapshotSchemaVersion 是 Snapshot 的结构版本，输出在 schema_version 字段中。
+//
+// 兼容性约定：Snapshot 及其嵌套结构只会增加字段，已有字段的 JSON 键、类型与含义保持不变，
+// 解析它的看板与脚本不会因为升级框架而失效。删除或修改已有字段属于破坏性变更，必须同时增加该版本；
+// testdata/snapshot_schema.golden 记录了每个版本的全部 JSON 键，字段被删除而版本未变时测试失败
+const SnapshotSchemaVersion = 1
+
+// Snapshot 是应用状态的机器可读快照，汇总身份与构建信息、生命周期、服务与 Runner 状态、
+// 健康检查结果、配置代数与校验和以及日志统计，管理接口与命令直接将它序列化为 JSON，
+// 而不必各自拼装，见 Drugo.Snapshot 与 router.RegisterStatus。
+// 对应的子系统不存在时（例如通过 New 创建、没有配置与日志管理器的应用）相应字段为空
+type Snapshot struct {
+	SchemaVersion int               `json:"schema_version"`   // 结构版本，见 SnapshotSchemaVersion
+	Time          time.Time         `json:"time"`             // 采集时间，按 Clock 计时
+	App           SnapshotApp       `json:"app"`              // 应用身份与构建信息
+	Lifecycle     SnapshotLifecycle `json:"lifecycle"`        // 生命周期状态
+	Services      []SnapshotService `json:"services"`         // 已注册的服务，按注册顺序排列
+	Runners       []SnapshotRunner  `json:"runners"`          // 正在运行或已停止的 Runner，Run 未在运行时为空
+	Health        []SnapshotHealth  `json:"health,omitempty"` // 健康检查结果，只由 SnapshotWithHealth 采集
+	Config        *SnapshotConfig   `json:"config,omitempty"` // 配置状态，没有配置管理器时为 nil
+	Log           *SnapshotLog      `json:"log,omitempty"`    // 日志统计，没有日志管理器时为 nil
+}
+
+// SnapshotApp 是应用的身份与构建信息。
+type SnapshotApp struct {
+	Name      string            `json:"name"`                 // 框架名称
+	Version   string            `json:"version"`              // 框架版本
+	Env       string            `json:"env,omitempty"`        // 运行环境
+	Root      string            `json:"root"`                 // 项目根目录
+	GoVersion string            `json:"go_version,omitempty"` // 编译使用的 Go 版本
+	Path      string            `json:"path,omitempty"`       // 主包路径
+	Build     map[string]string `json:"build,omitempty"`      // 构建参数，例如 vcs.revision
+}
+
+// SnapshotLifecycle 是应用的生命周期状态。
+type SnapshotLifecycle struct {
+	Outcome     Outcome  `json:"outcome"`                // Serve 或 Execute 的结果，尚未结束时为空
+	ExitCode    int      `json:"exit_code"`              // Outcome 对应的退出码
+	Booted      bool     `json:"booted"`                 // Boot 是否已经成功完成
+	Running     bool     `json:"running"`                // Run 是否正在运行
+	Quiet       bool     `json:"quiet"`                  // 是否处于安静模式
+	BootDone    int      `json:"boot_done"`              // 已完成 Boot 的服务数，见 BootProgress
+	BootTotal   int      `json:"boot_total"`             // Boot 已知的服务总数
+	BootCurrent string   `json:"boot_current,omitempty"` // 正在 Boot 的服务
+	BootMS      float64  `json:"boot_ms"`                // Boot 的总耗时（毫秒）
+	Degraded    []string `json:"degraded"`               // 降级的服务，见 Degraded
+}
+
+// SnapshotService 是单个服务的状态。
+type SnapshotService struct {
+	Name          string            `json:"name"`                     // 服务名称
+	Type          string            `json:"type"`                     // 服务的 Go 类型
+	State         ServiceState      `json:"state"`                    // 当前状态
+	Error         string            `json:"error,omitempty"`          // 最近一次导致异常状态的错误
+	Optional      bool              `json:"optional"`                 // 是否为可选服务
+	BootMS        float64           `json:"boot_ms"`                  // Boot 耗时（毫秒），尚未 Boot 时为 0
+	DependsOn     []string          `json:"depends_on,omitempty"`     // 依赖的服务，见 kernel.Dependent
+	Capabilities  map[string]string `json:"capabilities,omitempty"`   // 声明的能力，见 kernel.Capabilities
+	Metrics       map[string]int64  `json:"metrics,omitempty"`        // 运行指标，见 kernel.MetricsReporter
+	CloseOutcome  CloseOutcome      `json:"close_outcome,omitempty"`  // Shutdown 中 Close 的结果
+	CloseAttempts int               `json:"close_attempts,omitempty"` // Shutdown 中 Close 的调用次数
+}
+
+// Runner 在快照中的状态
+const (
+	RunnerStateRunning  = "running"  // Run 正在执行
+	RunnerStateStopping = "stopping" // 已通过 StopRunner 请求停止，Run 尚未返回
+	RunnerStateStopped  = "stopped"  // 已通过 StopRunner 停止，可以通过 StartRunner 重新启动
+	RunnerStateExited   = "exited"   // Run 已经返回
+)
+
+// SnapshotRunner 是单个 Runner 的运行状态。
+type SnapshotRunner struct {
+	Name  string `json:"name"`  // 服务名称
+	State string `json:"state"` // RunnerStateRunning 等
+}
+
+// SnapshotHealth 是单个服务的健康检查结果，见 kernel.HealthChecker。
+type SnapshotHealth struct {
+	Name       string  `json:"name"`            // 服务名称
+	Healthy    bool    `json:"healthy"`         // CheckHealth 是否返回 nil
+	Error      string  `json:"error,omitempty"` // 检查失败的原因
+	DurationMS float64 `json:"duration_ms"`     // 检查耗时（毫秒）
+}
+
+// SnapshotConfig 是配置管理器的状态。
+type SnapshotConfig struct {
+	Generation      uint64            `json:"generation"`                  // 当前配置的代数，见 config.Manager.Generation
+	ReloadReason    string            `json:"reload_reason"`               // 当前这一代配置产生的原因
+	Pinned          bool              `json:"pinned"`                      // 是否已固定当前配置
+	RootChecksum    string            `json:"root_checksum"`               // 全部配置内容的校验和
+	Checksums       map[string]string `json:"checksums"`                   // 各业务配置的校验和
+	LastReloadError string            `json:"last_reload_error,omitempty"` // 最近一次重载失败的原因
+	WatcherFailed   bool              `json:"watcher_failed"`              // 配置文件监听器是否已永久失效
+}
+
+// SnapshotLog 是日志管理器的统计。
+type SnapshotLog struct {
+	DefaultLevel string            `json:"default_level"`          // 默认日志级别
+	Loggers      []string          `json:"loggers"`                // 已创建的业务 logger，按名称排序
+	Evictions    uint64            `json:"evictions"`              // 目录配额累计淘汰的文件数量
+	EvictedBytes int64             `json:"evicted_bytes"`          // 目录配额累计释放的字节数
+	WriteErrors  map[string]uint64 `json:"write_errors,omitempty"` // 各业务日志累计写入失败的次数
+	Sources      map[string]string `json:"sources,omitempty"`      // 日志配置项的来源，见 BootReport.LogSources
+}
+
+// Snapshot 返回应用当前状态的快照，不执行健康检查，可以随时并发调用。
+func (d *Drugo) Snapshot() Snapshot {
+	build := readBuildInfo()
+	s := Snapshot{
+		SchemaVersion: SnapshotSchemaVersion,
+		Time:          d.Clock().Now(),
+		App: SnapshotApp{
+			Name:      Name,
+			Version:   Version(),
+			Root:      d.Root(),
+			GoVersion: build.GoVersion,
+			Path:      build.Path,
+			Build:     build.Settings,
+		},
+		Services: make([]SnapshotService, 0),
+		Runners:  d.runnerSnapshot(),
+	}
+
+	timings := d.Timings()
+	bootTimes := make(map[string]time.Duration, len(timings.Services))
+	for _, t := range timings.Services {
+		bootTimes[t.Name] = t.Boot
+	}
+	done, total, current := d.BootProgress()
+	d.timingsMu.Lock()
+	booted := !d.bootDone.IsZero()
+	d.timingsMu.Unlock()
+	s.Lifecycle = SnapshotLifecycle{
+		Outcome:     d.Outcome(),
+		ExitCode:    d.ExitCode(),
+		Booted:      booted,
+		Running:     d.isRunning(),
+		Quiet:       d.quiet,
+		BootDone:    done,
+		BootTotal:   total,
+		BootCurrent: current,
+		BootMS:      millis(timings.Boot),
+		Degraded:    d.Degraded(),
+	}
+
+	status := d.Status()
+	for _, service := range d.Container().Services() {
+		name := service.Name()
+		st := status[name]
+		ss := SnapshotService{
+			Name:          name,
+			Type:          fmt.Sprintf("%T", service),
+			State:         st.State,
+			Optional:      d.isOptional(service),
+			BootMS:        millis(bootTimes[name]),
+			Capabilities:  st.Capabilities,
+			Metrics:       st.Metrics,
+			CloseOutcome:  st.CloseOutcome,
+			CloseAttempts: st.CloseAttempts,
+		}
+		if st.Err != nil {
+			ss.Error = st.Err.Error()
+		}
+		if dep, ok := service.(kernel.Dependent); ok {
+			ss.DependsOn = slices.Clone(dep.DependsOn())
+		}
+		s.Services = append(s.Services, ss)
+	}
+
+	if d.config != nil {
+		s.App.Env = d.config.Environment()
+		s.Config = d.configSnapshot()
+	}
+	if d.logger != nil {
+		s.Log = d.logSnapshot()
+	}
+	return s
+}
+
+// SnapshotWithHealth 与 Snapshot 相同，并对所有实现了 kernel.HealthChecker 的服务执行一次健康检查
+// （降级的服务除外），ctx 限制全部检查的时间。检查按注册顺序依次执行，结果记录在 Health 中
+func (d *Drugo) SnapshotWithHealth(ctx context.Context) Snapshot {
+	s := d.Snapshot()
+	s.Health = make([]SnapshotHealth, 0)
+	for _, service := range d.Container().Services() {
+		checker, ok := service.(kernel.HealthChecker)
+		if !ok || d.isDegraded(service.Name()) {
+			continue
+		}
+		start := d.Clock().Now()
+		err := checker.CheckHealth(ctx)
+		h := SnapshotHealth{Name: service.Name(), Healthy: err == nil, DurationMS: millis(d.Clock().Now().Sub(start))}
+		if err != nil {
+			h.Error = err.Error()
+		}
+		s.Health = append(s.Health, h)
+	}
+	return s
+}
+
+// runnerSnapshot 返回 Runner 的运行状态，按名称排序
+func (d *Drugo) runnerSnapshot() []SnapshotRunner {
+	d.runMu.Lock()
+	defer d.runMu.Unlock()
+	runners := make([]SnapshotRunner, 0, len(d.runners))
+	for _, name := range slices.Sorted(maps.Keys(d.runners)) {
+		h := d.runners[name]
+		state := RunnerStateExited
+		switch {
+		case h.running && h.stopping:
+			state = RunnerStateStopping
+		case h.running:
+			state = RunnerStateRunning
+		case h.stopped:
+			state = RunnerStateStopped
+		}
+		runners = append(runners, SnapshotRunner{Name: name, State: state})
+	}
+	return runners
+}
+
+// isRunning 判断 Run 是否正在运行
+func (d *Drugo) isRunning() bool {
+	d.runMu.Lock()
+	defer d.runMu.Unlock()
+	return d.runners != nil
+}
+
+// configSnapshot 返回配置管理器的状态，调用方保证 d.config 不为 nil
+func (d *Drugo) configSnapshot() *SnapshotConfig {
+	m := d.config
+	c := &SnapshotConfig{
+		Generation:    m.Generation(),
+		ReloadReason:  string(m.ReloadReason()),
+		Pinned:        m.Pinned(),
+		RootChecksum:  m.RootChecksum(),
+		Checksums:     make(map[string]string),
+		WatcherFailed: m.WatcherStats().Failed,
+	}
+	for _, name := range m.List() {
+		if sum, err := m.Checksum(name); err == nil {
+			c.Checksums[name] = sum
+		}
+	}
+	if err := m.LastReloadError(); err != nil {
+		c.LastReloadError = err.Error()
+	}
+	return c
+}
+
+// logSnapshot 返回日志管理器的统计，调用方保证 d.logger 不为 nil
+func (d *Drugo) logSnapshot() *SnapshotLog {
+	m := d.logger
+	evictions := m.Evictions()
+	l := &SnapshotLog{
+		DefaultLevel: m.DefaultLevel(),
+		Loggers:      m.List(),
+		Evictions:    evictions.Count,
+		EvictedBytes: evictions.FreedBytes,
+		Sources:      maps.Clone(d.logSources),
+	}
+	slices.Sort(l.Loggers)
+	for name, stats := range m.WriteErrors() {
+		if l.WriteErrors == nil {
+			l.WriteErrors = make(map[string]uint64)
+		}
+		l.WriteErrors[name] = stats.Count
+	}
+	return l
+}
+
+// millis 将耗时转换为毫秒
+func millis(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
+
+// StatusSnapshot 在 health 为 true 时返回 SnapshotWithHealth，否则返回 Snapshot，
+// 签名与 router.StatusOptions.Snapshot 一致，用于注册状态快照路由：
+//
+//	router.RegisterStatus(router.Default(), router.StatusOptions{Snapshot: app.StatusSnapshot})
+func (d *Drugo) StatusSnapshot(ctx context.Context, health bool) any {
+	if health {
+		return d.SnapshotWithHealth(ctx)
+	}
+	return d.Snapshot()
+}
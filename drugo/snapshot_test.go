@@ -0,0 +1,206 @@
+package drugo
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/qq1060656096/drugo/kernel"
+	"github.com/qq1060656096/drugo/kernel/kerneltest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var updateSnapshotSchema = flag.Bool("update", false, "update testdata/snapshot_schema.golden")
+
+// snapshotSchemaGolden 记录 SnapshotSchemaVersion 与该版本的全部 JSON 键
+const snapshotSchemaGolden = "testdata/snapshot_schema.golden"
+
+// healthService 是实现了 kernel.HealthChecker 与 kernel.MetricsReporter 的测试服务
+type healthService struct {
+	*kerneltest.ServiceMock
+	err error
+}
+
+func (s *healthService) CheckHealth(ctx context.Context) error { return s.err }
+
+func (s *healthService) Metrics() map[string]int64 { return map[string]int64{"in_flight": 3} }
+
+// TestDrugo_Snapshot_NilSubsystems 测试通过 New 创建、没有配置与日志管理器的应用也可以采集并序列化快照
+func TestDrugo_Snapshot_NilSubsystems(t *testing.T) {
+	app := New(WithService(kerneltest.NewServiceMock("db")))
+
+	s := app.Snapshot()
+	assert.Equal(t, SnapshotSchemaVersion, s.SchemaVersion)
+	assert.Nil(t, s.Config)
+	assert.Nil(t, s.Log)
+	assert.Nil(t, s.Health)
+	assert.False(t, s.Lifecycle.Booted)
+	require.Len(t, s.Services, 1)
+	assert.Equal(t, ServiceStatePending, s.Services[0].State)
+
+	data, err := json.Marshal(s)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"runners":[]`)
+	assert.Contains(t, string(data), `"degraded":[]`)
+	assert.NotContains(t, string(data), `"config"`)
+
+	empty := New().SnapshotWithHealth(context.Background())
+	assert.Empty(t, empty.Services)
+	assert.NotNil(t, empty.Health)
+}
+
+// TestDrugo_Snapshot 测试快照汇总服务状态、健康检查、Runner、配置与日志统计，并且 JSON 往返后保持不变
+func TestDrugo_Snapshot(t *testing.T) {
+	c := kernel.NewFakeClock(time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC))
+	db := &healthService{ServiceMock: kerneltest.NewServiceMock("db")}
+	cache := &healthService{ServiceMock: kerneltest.NewServiceMock("cache"), err: assert.AnError}
+	api := newDependentService("api", new([]string), "db")
+	metrics := newBootFailingRunner("metrics", assert.AnError)
+	worker := newBlockingRunner("worker")
+	app := New(WithClock(c), WithServices(db, cache, api, worker), WithOptionalService(metrics))
+	app.logger = newTestLogManager(t)
+	app.config = newTestConfigManager(t, "db:\n  host: localhost\n")
+	app.logger.MustGet("orders")
+
+	h := startRunners(t, app, worker)
+	s := app.SnapshotWithHealth(context.Background())
+
+	assert.Equal(t, c.Now(), s.Time)
+	assert.True(t, s.Lifecycle.Booted)
+	assert.True(t, s.Lifecycle.Running)
+	assert.Equal(t, []string{"metrics"}, s.Lifecycle.Degraded)
+	assert.Equal(t, []any{5, 5}, []any{s.Lifecycle.BootDone, s.Lifecycle.BootTotal})
+
+	names := make([]string, len(s.Services))
+	for i, ss := range s.Services {
+		names[i] = ss.Name
+	}
+	assert.Equal(t, []string{"db", "cache", "api", "worker", "metrics"}, names)
+	assert.Equal(t, map[string]int64{"in_flight": 3}, s.Services[0].Metrics)
+	assert.Equal(t, []string{"db"}, s.Services[2].DependsOn)
+	assert.Equal(t, ServiceStateRunning, s.Services[3].State)
+	assert.Equal(t, ServiceStateDegraded, s.Services[4].State)
+	assert.True(t, s.Services[4].Optional)
+	assert.Equal(t, assert.AnError.Error(), s.Services[4].Error)
+
+	assert.Equal(t, []SnapshotRunner{{Name: "worker", State: RunnerStateRunning}}, s.Runners)
+	require.Len(t, s.Health, 2)
+	assert.Equal(t, SnapshotHealth{Name: "db", Healthy: true}, s.Health[0])
+	assert.Equal(t, SnapshotHealth{Name: "cache", Error: assert.AnError.Error()}, s.Health[1])
+
+	require.NotNil(t, s.Config)
+	assert.Equal(t, app.config.Generation(), s.Config.Generation)
+	assert.Equal(t, app.config.RootChecksum(), s.Config.RootChecksum)
+	assert.Contains(t, s.Config.Checksums, "db")
+	require.NotNil(t, s.Log)
+	assert.Equal(t, "info", s.Log.DefaultLevel)
+	assert.Contains(t, s.Log.Loggers, "orders")
+
+	// JSON 往返后保持不变
+	data, err := json.Marshal(s)
+	require.NoError(t, err)
+	var decoded Snapshot
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	again, err := json.Marshal(decoded)
+	require.NoError(t, err)
+	assert.JSONEq(t, string(data), string(again))
+	assert.Equal(t, s.Time, decoded.Time.UTC())
+
+	require.NoError(t, app.StopRunner(context.Background(), "worker"))
+	assert.Equal(t, []SnapshotRunner{{Name: "worker", State: RunnerStateStopped}}, app.Snapshot().Runners)
+	require.NoError(t, h.Stop(context.Background()))
+	assert.Empty(t, app.Snapshot().Runners)
+	assert.Equal(t, OutcomePending, app.Snapshot().Lifecycle.Outcome)
+}
+
+// TestDrugo_StatusCommand 测试内置的 status 命令以 JSON 格式打印快照，不会 Boot 服务
+func TestDrugo_StatusCommand(t *testing.T) {
+	svc := kerneltest.NewServiceMock("db")
+	app := New(WithService(svc))
+	var out bytes.Buffer
+	app.stdout = &out
+
+	require.NoError(t, app.Execute(context.Background(), []string{"app", "status"}))
+	var s Snapshot
+	require.NoError(t, json.Unmarshal(out.Bytes(), &s))
+	assert.Equal(t, SnapshotSchemaVersion, s.SchemaVersion)
+	require.Len(t, s.Services, 1)
+	assert.Equal(t, "db", s.Services[0].Name)
+	assert.False(t, svc.Booted())
+
+	got := app.StatusSnapshot(context.Background(), true)
+	require.IsType(t, Snapshot{}, got)
+	assert.NotNil(t, got.(Snapshot).Health)
+}
+
+// TestSnapshotSchema 测试 Snapshot 的 JSON 键与 golden 文件一致：删除或修改已有字段而没有增加
+// SnapshotSchemaVersion 时失败。增加字段或版本后使用 go test -run TestSnapshotSchema -update 更新 golden 文件
+func TestSnapshotSchema(t *testing.T) {
+	keys := snapshotKeys(reflect.TypeFor[Snapshot](), "")
+	current := fmt.Sprintf("schema_version %d\n%s\n", SnapshotSchemaVersion, strings.Join(keys, "\n"))
+
+	// 先与现有的 golden 文件比较，-update 不能绕过版本检查
+	data, err := os.ReadFile(snapshotSchemaGolden)
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	var version int
+	_, err = fmt.Sscanf(lines[0], "schema_version %d", &version)
+	require.NoError(t, err)
+
+	var removed []string
+	for _, key := range lines[1:] {
+		if !slices.Contains(keys, key) {
+			removed = append(removed, key)
+		}
+	}
+	if version == SnapshotSchemaVersion {
+		require.Empty(t, removed, "Snapshot fields were removed or changed without bumping SnapshotSchemaVersion")
+	}
+
+	if *updateSnapshotSchema {
+		require.NoError(t, os.WriteFile(snapshotSchemaGolden, []byte(current), 0644))
+		return
+	}
+	assert.Equal(t, string(data), current, "Snapshot schema changed, run go test -run TestSnapshotSchema -update")
+}
+
+// snapshotKeys 返回类型 t 的全部 JSON 键路径（按名称排序），切片元素以 "[]" 表示，
+// 结构体之外的类型（包括 time.Time 与 map）视为叶子，键后附带类型名称以发现类型变化
+func snapshotKeys(t reflect.Type, prefix string) []string {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	var keys []string
+	for i := range t.NumField() {
+		f := t.Field(i)
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "" || name == "-" {
+			continue
+		}
+		path := prefix + name
+		ft := f.Type
+		for ft.Kind() == reflect.Pointer {
+			ft = ft.Elem()
+		}
+		if ft.Kind() == reflect.Slice && ft.Elem().Kind() == reflect.Struct {
+			keys = append(keys, snapshotKeys(ft.Elem(), path+"[].")...)
+			continue
+		}
+		if ft.Kind() == reflect.Struct && ft != reflect.TypeFor[time.Time]() {
+			keys = append(keys, snapshotKeys(ft, path+".")...)
+			continue
+		}
+		keys = append(keys, path+" "+ft.String())
+	}
+	slices.Sort(keys)
+	return keys
+}
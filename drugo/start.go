@@ -0,0 +1,122 @@
+package drugo
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+
+	"github.com/qq1060656096/drugo/pkg/clock"
+	"go.uber.org/zap"
+)
+
+// RunHandle 是 Start 返回的运行句柄，用于在宿主程序中控制后台运行的应用。
+type RunHandle struct {
+	d      *Drugo
+	ctx    context.Context // 传给 Runner 的上下文，Stop 时取消
+	cancel context.CancelFunc
+	done   chan struct{}
+	err    error // Run 的返回值，在 done 关闭之前写入
+
+	stopOnce sync.Once
+	stopErr  error
+	timedOut atomic.Bool // 停机超过了停机超时时间
+}
+
+// Start 初始化所有服务并在后台运行所有 Runner，立即返回运行句柄。
+// 与 Serve 不同，Start 不监听任何系统信号，停机时机完全由宿主程序通过 RunHandle.Stop 控制，
+// 适用于将 Drugo 嵌入到桌面程序或其他框架的生命周期中。
+//
+// 一个 Drugo 实例只能启动一次（包括 Serve），重复调用返回 ErrAlreadyStarted；
+// Boot 失败时同样视为已启动，返回 Boot 的错误。
+// EmptyContainerFail 策略下没有注册任何服务时，在 Boot 之前返回 ErrEmptyContainer（见 WithEmptyContainerPolicy）。
+func (d *Drugo) Start(ctx context.Context) (*RunHandle, error) {
+	if !d.started.CompareAndSwap(false, true) {
+		return nil, ErrAlreadyStarted
+	}
+
+	l := d.frameworkLogger()
+	l.Info("app starting",
+		zap.String("name", Name),
+		zap.String("version", Version()),
+		zap.Int("services", len(d.Container().Services())),
+		zap.String("empty_container_policy", string(d.EmptyContainerPolicy())),
+	)
+
+	if err := d.checkEmptyContainer(); err != nil {
+		l.Error("app start failed", zap.Error(err))
+		return nil, err
+	}
+
+	if err := d.Boot(ctx); err != nil {
+		return nil, err
+	}
+
+	runCtx, cancel := context.WithCancel(ctx)
+	h := &RunHandle{
+		d:      d,
+		ctx:    runCtx,
+		cancel: cancel,
+		done:   make(chan struct{}),
+	}
+	go func() {
+		// 无论 Run 成功/失败都要关闭 done（特别是没有 Runner 服务时 Run 会立即返回）
+		h.err = d.Run(runCtx)
+		close(h.done)
+	}()
+	return h, nil
+}
+
+// Done 返回在所有 Runner 退出后关闭的 channel。
+func (h *RunHandle) Done() <-chan struct{} {
+	return h.done
+}
+
+// Err 返回 Run 的最终结果，Runner 尚未全部退出时返回 nil。
+func (h *RunHandle) Err() error {
+	select {
+	case <-h.done:
+		return h.err
+	default:
+		return nil
+	}
+}
+
+// Stop 取消所有 Runner，并在停机超时时间（见 WithShutdownTimeout）内执行 Shutdown，返回 Shutdown 的错误。
+// Stop 是幂等的，重复调用直接返回第一次调用的结果。
+func (h *RunHandle) Stop(ctx context.Context) error {
+	h.stopOnce.Do(func() {
+		h.stopErr = h.stop(ctx)
+	})
+	return h.stopErr
+}
+
+func (h *RunHandle) stop(ctx context.Context) error {
+	l := h.d.frameworkLogger()
+
+	// 通知所有 Runner 尽快退出
+	h.cancel()
+
+	// 优雅停机超时控制
+	timeout := h.d.shutdownTimeoutOrDefault()
+	l.Info("initiating shutdown with timeout", zap.Duration("timeout", timeout))
+	timeoutCtx, cancel := clock.WithTimeout(ctx, h.d.Clock(), timeout)
+	defer cancel()
+
+	// Close 失败时 Shutdown 仍会关闭其余服务，因此同样需要等待 Runner 退出
+	err := h.d.Shutdown(timeoutCtx)
+	if err != nil {
+		l.Error("app shutdown failed", zap.Error(err))
+	}
+
+	// 等待 Runner 退出，超时后不再等待
+	select {
+	case <-h.done:
+	case <-timeoutCtx.Done():
+		l.Warn("runners did not exit before shutdown timeout", zap.Error(timeoutCtx.Err()))
+	}
+	if errors.Is(timeoutCtx.Err(), context.DeadlineExceeded) {
+		h.timedOut.Store(true)
+	}
+	return err
+}
@@ -0,0 +1,89 @@
+package drugo
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/qq1060656096/drugo/kernel/kerneltest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestDrugo_Start_Stop 测试嵌入流程：Start 返回后 Runner 在后台运行，Stop 取消 Runner 并关闭所有服务
+func TestDrugo_Start_Stop(t *testing.T) {
+	runner := newBlockingRunner("runner")
+	db := kerneltest.NewServiceMock("db")
+	app := New(WithService(db), WithService(runner), WithShutdownTimeout(time.Second))
+	app.logger = newTestLogManager(t)
+
+	h, err := app.Start(context.Background())
+	require.NoError(t, err)
+	assert.Positive(t, db.BootCount())
+
+	select {
+	case <-h.Done():
+		t.Fatal("runner exited before Stop")
+	case <-time.After(50 * time.Millisecond):
+	}
+	assert.NoError(t, h.Err())
+
+	require.NoError(t, h.Stop(context.Background()))
+	<-h.Done()
+	assert.NoError(t, h.Err())
+	assert.True(t, runner.Ran())
+	assert.True(t, runner.Closed())
+	assert.True(t, db.Closed())
+	assert.Equal(t, ServiceStateClosed, app.Status()["db"].State)
+
+	// Stop 是幂等的
+	assert.NoError(t, h.Stop(context.Background()))
+	assert.Equal(t, 1, db.CloseCount())
+}
+
+// TestDrugo_Start_RunnerFailure 测试 Runner 失败通过 Done/Err 暴露给宿主程序
+func TestDrugo_Start_RunnerFailure(t *testing.T) {
+	failing := newFailingRunner("failing", assert.AnError)
+	blocking := newBlockingRunner("blocking")
+	app := New(WithService(failing), WithService(blocking))
+	app.logger = newTestLogManager(t)
+
+	h, err := app.Start(context.Background())
+	require.NoError(t, err)
+
+	select {
+	case <-h.Done():
+	case <-time.After(time.Second):
+		t.Fatal("runner failure did not stop the run")
+	}
+	assert.ErrorIs(t, h.Err(), assert.AnError)
+	assert.NoError(t, h.Stop(context.Background()))
+	assert.True(t, blocking.Closed())
+}
+
+// TestDrugo_Start_Twice 测试重复启动返回 ErrAlreadyStarted
+func TestDrugo_Start_Twice(t *testing.T) {
+	app := New(WithService(kerneltest.NewServiceMock("db")))
+	app.logger = newTestLogManager(t)
+
+	h, err := app.Start(context.Background())
+	require.NoError(t, err)
+	defer h.Stop(context.Background())
+
+	_, err = app.Start(context.Background())
+	assert.ErrorIs(t, err, ErrAlreadyStarted)
+	assert.ErrorIs(t, app.Serve(context.Background()), ErrAlreadyStarted)
+}
+
+// TestDrugo_Start_BootFailure 测试 Boot 失败时 Start 返回错误且不能再次启动
+func TestDrugo_Start_BootFailure(t *testing.T) {
+	app := New(WithService(newBootFailingService("db", assert.AnError)))
+	app.logger = newTestLogManager(t)
+
+	h, err := app.Start(context.Background())
+	assert.Nil(t, h)
+	assert.ErrorIs(t, err, assert.AnError)
+
+	_, err = app.Start(context.Background())
+	assert.ErrorIs(t, err, ErrAlreadyStarted)
+}
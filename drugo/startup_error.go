@@ -0,0 +1,84 @@
+package drugo
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/qq1060656096/drugo/config"
+)
+
+// StartupError 是 MustNewApp 初始化配置或日志系统失败时 panic 的值。
+// Error 返回多行信息，列出出错的文件、原因和修复提示，原始错误可以通过 errors.Is/errors.As 获取：
+//
+//	defer func() {
+//		if err, ok := recover().(*drugo.StartupError); ok {
+//			var fe *config.FileError
+//			if errors.As(err, &fe) {
+//				fmt.Println(fe.Path(), fe.Line())
+//			}
+//		}
+//	}()
+type StartupError struct {
+	stage string // 失败的阶段: "config", "log"
+	dir   string // 配置目录
+	err   error  // 原始错误
+}
+
+// Stage 返回失败的阶段，"config" 或 "log"
+func (e *StartupError) Stage() string {
+	return e.stage
+}
+
+// Error 实现 error 接口
+func (e *StartupError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "drugo: %s initialization failed\n", e.stage)
+	for _, file := range e.files() {
+		fmt.Fprintf(&b, "  file:   %s\n", file)
+	}
+	fmt.Fprintf(&b, "  reason: %v\n", e.err)
+	fmt.Fprintf(&b, "  hint:   %s", e.hint())
+	return b.String()
+}
+
+// Unwrap 实现 Go 1.13+ 的错误链解包接口
+func (e *StartupError) Unwrap() error {
+	return e.err
+}
+
+// files 返回与错误相关的文件，行号可用时附加在路径之后
+func (e *StartupError) files() []string {
+	var fe *config.FileError
+	if errors.As(e.err, &fe) {
+		if fe.Line() > 0 {
+			return []string{fmt.Sprintf("%s:%d", fe.Path(), fe.Line())}
+		}
+		return []string{fe.Path()}
+	}
+	var de *config.DuplicateKeyError
+	if errors.As(e.err, &de) {
+		first, second := de.Files()
+		if first == second {
+			return []string{first}
+		}
+		return []string{first, second}
+	}
+	return nil
+}
+
+// hint 返回针对错误类型的修复提示
+func (e *StartupError) hint() string {
+	switch {
+	case config.IsFileRead(e.err):
+		return "fix the YAML syntax at the reported location"
+	case config.IsDuplicateKey(e.err):
+		return "each top-level key (case-insensitive) may be defined in only one file, merge or rename one of them"
+	case config.IsEmptyConfig(e.err):
+		return "check that " + e.dir + " is mounted and contains *.yaml files, or use WithAllowEmptyConfig"
+	case e.stage == "log":
+		return "check the log section in " + e.dir
+	default:
+		return "check the files under " + e.dir
+	}
+}
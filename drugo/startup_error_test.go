@@ -0,0 +1,74 @@
+package drugo
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/qq1060656096/drugo/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recoverStartupError 调用 MustNewApp 并返回其 panic 的 *StartupError
+func recoverStartupError(t *testing.T, opts ...Option) (se *StartupError) {
+	t.Helper()
+	defer func() {
+		r := recover()
+		require.NotNil(t, r, "MustNewApp should panic")
+		var ok bool
+		se, ok = r.(*StartupError)
+		require.True(t, ok, "panic value should be *StartupError, got %T", r)
+	}()
+	MustNewApp(opts...)
+	return nil
+}
+
+// TestMustNewApp_StartupError 测试配置错误时 MustNewApp 的 panic 值列出出错的文件、原因和提示
+func TestMustNewApp_StartupError(t *testing.T) {
+	t.Run("语法错误", func(t *testing.T) {
+		root := t.TempDir()
+		conf := filepath.Join(root, "conf")
+		require.NoError(t, os.MkdirAll(conf, 0755))
+		path := filepath.Join(conf, "app.yaml")
+		require.NoError(t, os.WriteFile(path, []byte("app:\n  name: demo\n  port: 8080\n bad\n"), 0644))
+
+		se := recoverStartupError(t, WithRoot(root), WithQuiet(true))
+		assert.Equal(t, "config", se.Stage())
+		var fe *config.FileError
+		require.True(t, errors.As(se, &fe))
+		assert.Equal(t, path, fe.Path())
+
+		msg := se.Error()
+		assert.Contains(t, msg, "drugo: config initialization failed\n")
+		assert.Contains(t, msg, "  file:   "+path+":3\n")
+		assert.Contains(t, msg, "  reason: config: file read failed: ")
+		assert.Contains(t, msg, "  hint:   fix the YAML syntax")
+	})
+
+	t.Run("重复定义", func(t *testing.T) {
+		root := t.TempDir()
+		conf := filepath.Join(root, "conf")
+		require.NoError(t, os.MkdirAll(conf, 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(conf, "a.yaml"), []byte("db:\n  host: a\n"), 0644))
+		require.NoError(t, os.WriteFile(filepath.Join(conf, "b.yaml"), []byte("db:\n  host: b\n"), 0644))
+
+		se := recoverStartupError(t, WithRoot(root), WithQuiet(true))
+		assert.True(t, config.IsDuplicateKey(se))
+		msg := se.Error()
+		assert.Contains(t, msg, "  file:   "+filepath.Join(conf, "a.yaml")+"\n")
+		assert.Contains(t, msg, "  file:   "+filepath.Join(conf, "b.yaml")+"\n")
+		assert.Contains(t, msg, "  hint:   each top-level key")
+	})
+
+	t.Run("空配置", func(t *testing.T) {
+		root := t.TempDir()
+		require.NoError(t, os.MkdirAll(filepath.Join(root, "conf"), 0755))
+
+		se := recoverStartupError(t, WithRoot(root), WithQuiet(true))
+		assert.True(t, config.IsEmptyConfig(se))
+		assert.NotContains(t, se.Error(), "file:")
+		assert.Contains(t, se.Error(), "WithAllowEmptyConfig")
+	})
+}
@@ -0,0 +1,186 @@
+package drugo
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/qq1060656096/drugo/config"
+	"github.com/qq1060656096/drugo/kernel"
+)
+
+// ServiceState 表示服务在生命周期中的状态。
+type ServiceState string
+
+const (
+	// ServiceStatePending 服务已注册但尚未完成 Boot。
+	ServiceStatePending ServiceState = "pending"
+	// ServiceStateBooted 服务 Boot 成功。
+	ServiceStateBooted ServiceState = "booted"
+	// ServiceStateDegraded 可选服务 Boot 失败，应用在没有它的情况下继续运行。
+	ServiceStateDegraded ServiceState = "degraded"
+	// ServiceStateRunning Runner 的 Run 正在执行。
+	ServiceStateRunning ServiceState = "running"
+	// ServiceStateStopped Runner 已通过 StopRunner 停止，可以通过 StartRunner 重新启动。
+	ServiceStateStopped ServiceState = "stopped"
+	// ServiceStateClosed 服务已关闭。
+	ServiceStateClosed ServiceState = "closed"
+)
+
+// ServiceStatus 记录单个服务的状态及导致该状态的错误。
+type ServiceStatus struct {
+	Name         string            // 服务名称
+	State        ServiceState      // 当前状态
+	Err          error             // 导致降级等异常状态的错误，正常时为 nil
+	Capabilities map[string]string // 服务声明的能力，见 kernel.Capabilities，没有声明时为 nil
+	Metrics      map[string]int64  // 服务报告的运行指标，见 kernel.MetricsReporter，没有实现时为 nil
+
+	CloseOutcome  CloseOutcome // Shutdown 中 Close 的最终结果，尚未关闭时为空
+	CloseAttempts int          // Shutdown 中 Close 的调用次数
+}
+
+// configStatusName 是配置热加载在状态快照中使用的名称，属于 kernel 保留的服务名称，不会与服务冲突。
+const configStatusName = "config"
+
+// Status 返回所有已注册服务的状态快照。
+// 尚未 Boot 的服务状态为 ServiceStatePending，Capabilities 为服务当前声明的能力，
+// Metrics 为服务当前报告的运行指标。
+// 配置文件监听器重建次数耗尽（见 config.Manager.WatcherStats）时，
+// 快照中额外包含名为 "config" 的降级状态。
+func (d *Drugo) Status() map[string]ServiceStatus {
+	d.statusMu.RLock()
+	defer d.statusMu.RUnlock()
+
+	result := make(map[string]ServiceStatus)
+	for _, service := range d.Container().Services() {
+		name := service.Name()
+		st, ok := d.status[name]
+		if !ok {
+			st = ServiceStatus{Name: name, State: ServiceStatePending}
+		}
+		st.Capabilities = capabilitiesOf(service)
+		if m, ok := service.(kernel.MetricsReporter); ok {
+			st.Metrics = m.Metrics()
+		}
+		result[name] = st
+	}
+	if st, ok := configWatcherStatus(d.config.WatcherStats()); ok {
+		result[configStatusName] = st
+	}
+	return result
+}
+
+// Degraded 返回所有处于降级状态的服务名称（按名称排序），配置热加载永久失效时包含 "config"。
+func (d *Drugo) Degraded() []string {
+	d.statusMu.RLock()
+	defer d.statusMu.RUnlock()
+
+	names := make([]string, 0)
+	for name, st := range d.status {
+		if st.State == ServiceStateDegraded {
+			names = append(names, name)
+		}
+	}
+	if _, ok := configWatcherStatus(d.config.WatcherStats()); ok {
+		names = append(names, configStatusName)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// configWatcherStatus 在配置文件监听器永久失效时返回降级状态。
+func configWatcherStatus(stats config.WatcherStats) (ServiceStatus, bool) {
+	if !stats.Failed {
+		return ServiceStatus{}, false
+	}
+	return ServiceStatus{Name: configStatusName, State: ServiceStateDegraded, Err: stats.LastError}, true
+}
+
+// setStatus 更新指定服务的状态。
+func (d *Drugo) setStatus(name string, state ServiceState, err error) {
+	d.statusMu.Lock()
+	defer d.statusMu.Unlock()
+
+	if d.status == nil {
+		d.status = make(map[string]ServiceStatus)
+	}
+	d.status[name] = ServiceStatus{Name: name, State: state, Err: err}
+	if d.statusChanged != nil {
+		close(d.statusChanged)
+		d.statusChanged = nil
+	}
+}
+
+// setCloseStatus 记录服务 Close 的结果：成功时状态变为 ServiceStateClosed，
+// 失败时保留之前的状态并记录最后一次 Close 的错误。
+func (d *Drugo) setCloseStatus(c ServiceShutdown) {
+	d.statusMu.Lock()
+	defer d.statusMu.Unlock()
+
+	if d.status == nil {
+		d.status = make(map[string]ServiceStatus)
+	}
+	st, ok := d.status[c.Name]
+	if !ok {
+		st = ServiceStatus{Name: c.Name, State: ServiceStatePending}
+	}
+	if c.Err == nil {
+		st.State = ServiceStateClosed
+	}
+	st.Err = c.Err
+	st.CloseOutcome = c.Outcome
+	st.CloseAttempts = c.Attempts
+	d.status[c.Name] = st
+	if d.statusChanged != nil {
+		close(d.statusChanged)
+		d.statusChanged = nil
+	}
+}
+
+// WaitBooted 阻塞直到名为 name 的服务完成 Boot，实现 kernel.BootWaiter。
+// 服务已经 Boot 成功（包括运行中、已停止与已关闭）时立即返回 nil；
+// 服务降级时返回包装了 kernel.ErrServiceInitFailed 与 Boot 错误的错误；
+// 服务尚未注册或尚未 Boot 时继续等待，直到 ctx 取消并返回 ctx.Err()。
+func (d *Drugo) WaitBooted(ctx context.Context, name string) error {
+	for {
+		d.statusMu.Lock()
+		st := d.status[name]
+		if d.statusChanged == nil {
+			d.statusChanged = make(chan struct{})
+		}
+		changed := d.statusChanged
+		d.statusMu.Unlock()
+
+		switch st.State {
+		case ServiceStateBooted, ServiceStateRunning, ServiceStateStopped, ServiceStateClosed:
+			return nil
+		case ServiceStateDegraded:
+			return fmt.Errorf("%w: %s: %w", kernel.ErrServiceInitFailed, name, st.Err)
+		}
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// isDegraded 判断服务是否处于降级状态。
+func (d *Drugo) isDegraded(name string) bool {
+	d.statusMu.RLock()
+	defer d.statusMu.RUnlock()
+
+	return d.status[name].State == ServiceStateDegraded
+}
+
+// isOptional 判断服务是否为可选服务：
+// 通过 WithOptionalService 注册，或实现了 kernel.Optional 且返回 true。
+func (d *Drugo) isOptional(service kernel.Service) bool {
+	if _, ok := d.optional[service.Name()]; ok {
+		return true
+	}
+	if o, ok := service.(kernel.Optional); ok {
+		return o.Optional()
+	}
+	return false
+}
@@ -0,0 +1,149 @@
+package drugo
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/qq1060656096/drugo/config"
+	"github.com/qq1060656096/drugo/kernel/kerneltest"
+	"github.com/qq1060656096/drugo/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// optionalMockService 是一个通过 kernel.Optional 接口声明为可选的模拟服务
+type optionalMockService struct {
+	*kerneltest.ServiceMock
+}
+
+func (m *optionalMockService) Optional() bool {
+	return true
+}
+
+// newTestLogManager 创建一个只输出到控制台的日志管理器
+func newTestLogManager(t *testing.T) *log.Manager {
+	t.Helper()
+	logger, err := log.NewManager(log.Config{
+		Level: "info",
+		Outputs: []log.OutputConfig{
+			{Type: "console", Format: "text"},
+		},
+	})
+	require.NoError(t, err)
+	return logger
+}
+
+// TestDrugo_Boot_OptionalServiceFails 测试可选服务启动失败时继续启动
+func TestDrugo_Boot_OptionalServiceFails(t *testing.T) {
+	optional := newBootFailingRunner("metrics", assert.AnError)
+	required := kerneltest.NewServiceMock("db")
+
+	app := New(
+		WithOptionalService(optional),
+		WithService(required),
+	)
+	app.logger = newTestLogManager(t)
+
+	require.NoError(t, app.Boot(context.Background()))
+	assert.Positive(t, required.BootCount())
+
+	status := app.Status()
+	assert.Equal(t, ServiceStateDegraded, status["metrics"].State)
+	assert.ErrorIs(t, status["metrics"].Err, assert.AnError)
+	assert.Equal(t, ServiceStateBooted, status["db"].State)
+	assert.Equal(t, []string{"metrics"}, app.Degraded())
+
+	// 降级服务不参与运行
+	require.NoError(t, app.Run(context.Background()))
+	assert.False(t, optional.Ran())
+
+	// 降级服务不参与关闭
+	require.NoError(t, app.Shutdown(context.Background()))
+	assert.False(t, optional.Closed())
+	assert.True(t, required.Closed())
+	assert.Equal(t, ServiceStateClosed, app.Status()["db"].State)
+}
+
+// TestDrugo_Boot_RequiredServiceFails 测试必需服务启动失败时仍然快速失败
+func TestDrugo_Boot_RequiredServiceFails(t *testing.T) {
+	optional := kerneltest.NewServiceMock("tracing")
+	required := newBootFailingService("db", assert.AnError)
+	after := kerneltest.NewServiceMock("cache")
+
+	app := New(
+		WithOptionalService(optional),
+		WithService(required),
+		WithService(after),
+	)
+	app.logger = newTestLogManager(t)
+
+	err := app.Boot(context.Background())
+	assert.ErrorIs(t, err, assert.AnError)
+	assert.Positive(t, optional.BootCount())
+	assert.Zero(t, after.BootCount())
+
+	status := app.Status()
+	assert.Equal(t, ServiceStateBooted, status["tracing"].State)
+	assert.Equal(t, ServiceStatePending, status["cache"].State)
+	assert.Empty(t, app.Degraded())
+}
+
+// TestDrugo_Boot_OptionalInterface 测试通过 kernel.Optional 接口声明可选服务
+func TestDrugo_Boot_OptionalInterface(t *testing.T) {
+	optional := &optionalMockService{
+		ServiceMock: newBootFailingService("pusher", assert.AnError),
+	}
+
+	app := New(WithService(optional))
+	app.logger = newTestLogManager(t)
+
+	require.NoError(t, app.Boot(context.Background()))
+	assert.Equal(t, []string{"pusher"}, app.Degraded())
+}
+
+// TestDrugo_Status_Pending 测试未启动时所有服务处于 pending 状态
+func TestDrugo_Status_Pending(t *testing.T) {
+	app := New(WithService(kerneltest.NewServiceMock("svc")))
+	status := app.Status()
+	require.Len(t, status, 1)
+	assert.Equal(t, ServiceStatus{Name: "svc", State: ServiceStatePending}, status["svc"])
+}
+
+// TestConfigWatcherStatus 测试配置监听器永久失效时报告为降级
+func TestConfigWatcherStatus(t *testing.T) {
+	_, ok := configWatcherStatus(config.WatcherStats{Watching: true, Restarts: 2})
+	assert.False(t, ok)
+
+	err := errors.New("too many open files")
+	st, ok := configWatcherStatus(config.WatcherStats{Failed: true, LastError: err})
+	require.True(t, ok)
+	assert.Equal(t, ServiceStatus{Name: "config", State: ServiceStateDegraded, Err: err}, st)
+
+	// 未设置配置管理器或监听器正常时不出现在状态快照中
+	app := New(WithService(kerneltest.NewServiceMock("svc")))
+	assert.NotContains(t, app.Status(), "config")
+	assert.Empty(t, app.Degraded())
+}
+
+// metricsService 是一个报告运行指标的模拟服务
+type metricsService struct {
+	*kerneltest.ServiceMock
+	inFlight int64
+}
+
+func (m *metricsService) Metrics() map[string]int64 {
+	return map[string]int64{"in_flight": m.inFlight}
+}
+
+// TestDrugo_Status_Metrics 测试状态快照包含服务当前报告的运行指标
+func TestDrugo_Status_Metrics(t *testing.T) {
+	pool := &metricsService{ServiceMock: kerneltest.NewServiceMock("pool")}
+	app := New(WithService(pool), WithService(kerneltest.NewServiceMock("db")))
+
+	pool.inFlight = 3
+	assert.Equal(t, map[string]int64{"in_flight": 3}, app.Status()["pool"].Metrics)
+	pool.inFlight = 1
+	assert.Equal(t, map[string]int64{"in_flight": 1}, app.Status()["pool"].Metrics)
+	assert.Nil(t, app.Status()["db"].Metrics)
+}
@@ -0,0 +1,82 @@
+package drugo
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// slowestTimings 是 Boot 结束时摘要日志中列出的最慢项目数量
+const slowestTimings = 3
+
+// StartupTimings 是应用启动各阶段的耗时，用于定位启动变慢的原因，见 Drugo.Timings。
+// 未执行的阶段耗时为 0。
+type StartupTimings struct {
+	Config    time.Duration   // MustNewApp 构建配置管理器的耗时
+	Log       time.Duration   // MustNewApp 构建日志管理器的耗时
+	Bind      time.Duration   // 应用选项并将服务注册到容器的耗时
+	Boot      time.Duration   // Boot 的总耗时
+	Services  []ServiceTiming // 各服务 Boot 的耗时，按初始化顺序排列
+	RunLaunch time.Duration   // 从 Boot 完成到所有 Runner 启动的耗时
+}
+
+// ServiceTiming 是单个服务 Boot 的耗时。
+type ServiceTiming struct {
+	Name string        // 服务名称
+	Boot time.Duration // Boot 耗时，包含注入 logger 与配置
+}
+
+// Timings 返回应用启动各阶段耗时的副本。
+func (d *Drugo) Timings() StartupTimings {
+	d.timingsMu.Lock()
+	defer d.timingsMu.Unlock()
+	t := d.timings
+	t.Services = append([]ServiceTiming(nil), t.Services...)
+	return t
+}
+
+// recordTiming 在 timingsMu 内修改启动耗时
+func (d *Drugo) recordTiming(fn func(t *StartupTimings)) {
+	d.timingsMu.Lock()
+	defer d.timingsMu.Unlock()
+	fn(&d.timings)
+}
+
+// slowest 返回耗时最长的 n 个项目，格式为 "名称=耗时"，初始化阶段的名称带 "init:" 前缀
+func (t StartupTimings) slowest(n int) []string {
+	type item struct {
+		name string
+		d    time.Duration
+	}
+	items := []item{{"init:config", t.Config}, {"init:log", t.Log}, {"init:bind", t.Bind}}
+	for _, s := range t.Services {
+		items = append(items, item{s.Name, s.Boot})
+	}
+	sort.SliceStable(items, func(i, j int) bool { return items[i].d > items[j].d })
+
+	var result []string
+	for _, it := range items {
+		if len(result) == n || it.d <= 0 {
+			break
+		}
+		result = append(result, fmt.Sprintf("%s=%s", it.name, it.d.Round(time.Microsecond)))
+	}
+	return result
+}
+
+// slowestServices 与 slowest 相同，但只包含各服务的 Boot 耗时
+func (t StartupTimings) slowestServices(n int) []string {
+	return StartupTimings{Services: t.Services}.slowest(n)
+}
+
+// logTimings 在 Boot 结束时输出一行启动耗时摘要
+func (d *Drugo) logTimings(l *zap.Logger) {
+	t := d.Timings()
+	l.Info("framework boot timings",
+		zap.Duration("boot", t.Boot),
+		zap.String("slowest", strings.Join(t.slowest(slowestTimings), ", ")),
+	)
+}
@@ -0,0 +1,146 @@
+package drugo
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/qq1060656096/drugo/kernel"
+	"github.com/qq1060656096/drugo/kernel/kerneltest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newSlowBootService 创建 Boot 耗时 bootDelay 的模拟服务
+func newSlowBootService(name string, bootDelay time.Duration) *kerneltest.ServiceMock {
+	s := kerneltest.NewServiceMock(name)
+	s.BootFunc = func(ctx context.Context) error {
+		time.Sleep(bootDelay)
+		return nil
+	}
+	return s
+}
+
+// TestDrugo_Timings 测试记录各服务的 Boot 耗时、Runner 启动耗时，以及 Boot 结束时的耗时摘要
+func TestDrugo_Timings(t *testing.T) {
+	const tolerance = 200 * time.Millisecond
+	delays := map[string]time.Duration{"db": 60 * time.Millisecond, "cache": 20 * time.Millisecond}
+	server := newBlockingRunner("server")
+	app := New(
+		WithService(newSlowBootService("db", delays["db"])),
+		WithService(newSlowBootService("cache", delays["cache"])),
+		WithService(server),
+	)
+	logger, dir := newFileTestLogManager(t)
+	app.logger = logger
+
+	assert.Positive(t, app.Timings().Bind)
+	assert.Zero(t, app.Timings().Boot)
+	startRunners(t, app, server)
+
+	timings := app.Timings()
+	require.Len(t, timings.Services, 3)
+	for _, s := range timings.Services[:2] {
+		assert.GreaterOrEqual(t, s.Boot, delays[s.Name], s.Name)
+		assert.Less(t, s.Boot, delays[s.Name]+tolerance, s.Name)
+	}
+	assert.Equal(t, "server", timings.Services[2].Name)
+	assert.GreaterOrEqual(t, timings.Boot, delays["db"]+delays["cache"])
+	assert.Eventually(t, func() bool { return app.Timings().RunLaunch > 0 }, time.Second, 5*time.Millisecond)
+
+	// 启动报告包含 Boot 阶段的耗时，可以序列化为 JSON
+	report := app.BootReport()
+	assert.Equal(t, timings.Services, report.Timings.Services)
+	data, err := json.Marshal(report.Timings)
+	require.NoError(t, err)
+	var decoded StartupTimings
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, timings.Services, decoded.Services)
+
+	require.NoError(t, logger.Flush(context.Background()))
+	data, err = os.ReadFile(filepath.Join(dir, "drugo.log"))
+	require.NoError(t, err)
+	var summary map[string]any
+	for _, line := range strings.Split(string(data), "\n") {
+		if strings.Contains(line, "framework boot timings") {
+			require.NoError(t, json.Unmarshal([]byte(line), &summary))
+		}
+	}
+	require.NotNil(t, summary, "boot timings summary is logged")
+	slowest := strings.Split(summary["slowest"].(string), ", ")
+	assert.LessOrEqual(t, len(slowest), slowestTimings)
+	assert.True(t, strings.HasPrefix(slowest[0], "db="), "slowest item: %v", slowest)
+}
+
+// TestDrugo_Timings_Clock 测试启动耗时按 WithClock 设置的时钟计算，使用 FakeClock 时结果是确定的
+func TestDrugo_Timings_Clock(t *testing.T) {
+	c := kernel.NewFakeClock(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+	advanceOnBoot := func(name string, d time.Duration) *kerneltest.ServiceMock {
+		s := kerneltest.NewServiceMock(name)
+		s.BootFunc = func(context.Context) error {
+			c.Advance(d)
+			return nil
+		}
+		return s
+	}
+	server := newBlockingRunner("server")
+	app := New(
+		WithClock(c),
+		WithService(advanceOnBoot("db", 3*time.Second)),
+		WithService(advanceOnBoot("cache", time.Second)),
+		WithService(server),
+	)
+	assert.Zero(t, app.Timings().Bind)
+
+	require.NoError(t, app.Boot(context.Background()))
+	timings := app.Timings()
+	assert.Equal(t, []ServiceTiming{
+		{Name: "db", Boot: 3 * time.Second},
+		{Name: "cache", Boot: time.Second},
+		{Name: "server"},
+	}, timings.Services)
+	assert.Equal(t, 4*time.Second, timings.Boot)
+
+	// Runner 的启动耗时从 Boot 完成时开始计算
+	c.Advance(2 * time.Second)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	result := make(chan error, 1)
+	go func() { result <- app.Run(ctx) }()
+	<-server.Ready()
+	assert.Equal(t, 2*time.Second, app.Timings().RunLaunch)
+	cancel()
+	require.NoError(t, <-result)
+}
+
+// TestMustNewApp_Timings 测试 MustNewApp 记录配置与日志管理器的构建耗时
+func TestMustNewApp_Timings(t *testing.T) {
+	root := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "conf"), 0755))
+
+	app := MustNewApp(WithRoot(root), WithAllowEmptyConfig(), WithQuiet(true))
+	timings := app.Timings()
+	assert.Positive(t, timings.Config)
+	assert.Positive(t, timings.Log)
+	assert.Positive(t, timings.Bind)
+	assert.Empty(t, timings.Services)
+}
+
+// TestStartupTimings_Slowest 测试按耗时从大到小选取项目，跳过未执行的阶段
+func TestStartupTimings_Slowest(t *testing.T) {
+	timings := StartupTimings{
+		Config: 5 * time.Millisecond,
+		Services: []ServiceTiming{
+			{Name: "db", Boot: 120 * time.Millisecond},
+			{Name: "cache", Boot: 30 * time.Millisecond},
+			{Name: "api", Boot: time.Millisecond},
+		},
+	}
+	assert.Equal(t, []string{"db=120ms", "cache=30ms", "init:config=5ms"}, timings.slowest(3))
+	assert.Equal(t, []string{"db=120ms", "cache=30ms", "init:config=5ms", "api=1ms"}, timings.slowest(10))
+	assert.Empty(t, StartupTimings{}.slowest(3))
+}
@@ -0,0 +1,15 @@
+package drugo
+
+import (
+	"github.com/qq1060656096/drugo/kernel"
+)
+
+// TypedContainer 是一个并发安全、保持注册顺序的通用容器，对存放的类型没有约束，
+// 可用于路由元数据、按租户划分的句柄、编解码器注册表等非生命周期对象。
+// 它是 kernel.TypedContainer 的别名，实例不存在时返回 ErrEntryNotFound。零值可以直接使用。
+type TypedContainer[T any] = kernel.TypedContainer[T]
+
+// NewTypedContainer 创建一个空的 TypedContainer
+func NewTypedContainer[T any]() *TypedContainer[T] {
+	return kernel.NewTypedContainer[T]()
+}
@@ -0,0 +1,368 @@
+package drugo
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/qq1060656096/drugo/kernel"
+	"github.com/qq1060656096/drugo/kernel/kerneltest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestNewTypedContainer 测试容器创建
+func TestNewTypedContainer(t *testing.T) {
+	container := NewTypedContainer[*http.Client]()
+
+	require.NotNil(t, container)
+	assert.Empty(t, container.Values())
+	assert.Empty(t, container.Names())
+}
+
+// TestTypedContainer_ZeroValue 测试零值容器可以直接使用
+func TestTypedContainer_ZeroValue(t *testing.T) {
+	var container TypedContainer[*http.Client]
+	_, err := container.Get("api")
+	assert.ErrorIs(t, err, ErrEntryNotFound)
+
+	client := &http.Client{}
+	container.Bind("api", client)
+	assert.Same(t, client, container.MustGet("api"))
+}
+
+// TestTypedContainer_Bind 测试绑定功能
+func TestTypedContainer_Bind(t *testing.T) {
+	container := NewTypedContainer[*http.Client]()
+	client1 := &http.Client{Timeout: time.Second}
+	client2 := &http.Client{Timeout: 2 * time.Second}
+
+	// 测试绑定新实例
+	container.Bind("client1", client1)
+	assert.Equal(t, client1, container.MustGet("client1"))
+	assert.Contains(t, container.Names(), "client1")
+	assert.Len(t, container.Names(), 1)
+
+	// 测试覆盖已存在的实例
+	client1New := &http.Client{Timeout: 3 * time.Second}
+	container.Bind("client1", client1New)
+	assert.Equal(t, client1New, container.MustGet("client1"))
+	assert.Len(t, container.Names(), 1) // 名称列表长度不应增加
+
+	// 测试绑定第二个实例
+	container.Bind("client2", client2)
+	assert.Equal(t, client2, container.MustGet("client2"))
+	assert.Contains(t, container.Names(), "client2")
+	assert.Len(t, container.Names(), 2)
+}
+
+// TestTypedContainer_Bind_EmptyName 测试绑定空名称
+func TestTypedContainer_Bind_EmptyName(t *testing.T) {
+	container := NewTypedContainer[*http.Client]()
+	client := &http.Client{}
+
+	container.Bind("", client)
+	assert.Equal(t, client, container.MustGet(""))
+	assert.Contains(t, container.Names(), "")
+}
+
+// TestTypedContainer_Get 测试获取功能
+func TestTypedContainer_Get(t *testing.T) {
+	container := NewTypedContainer[*http.Client]()
+	client := &http.Client{}
+
+	// 测试获取不存在的实例
+	result, err := container.Get("nonexistent")
+	assert.Error(t, err)
+	assert.True(t, errors.Is(err, ErrEntryNotFound))
+	assert.Contains(t, err.Error(), `"nonexistent"`)
+	assert.Nil(t, result)
+
+	// 绑定后测试获取
+	container.Bind("api", client)
+	result, err = container.Get("api")
+	assert.NoError(t, err)
+	assert.Equal(t, client, result)
+}
+
+// TestTypedContainer_Get_EmptyName 测试获取空名称
+func TestTypedContainer_Get_EmptyName(t *testing.T) {
+	container := NewTypedContainer[*http.Client]()
+	client := &http.Client{}
+
+	container.Bind("", client)
+
+	result, err := container.Get("")
+	assert.NoError(t, err)
+	assert.Equal(t, client, result)
+}
+
+// TestTypedContainer_MustGet 测试必须获取功能
+func TestTypedContainer_MustGet(t *testing.T) {
+	container := NewTypedContainer[*http.Client]()
+	client := &http.Client{}
+
+	container.Bind("api", client)
+
+	// 测试获取存在的实例
+	assert.Equal(t, client, container.MustGet("api"))
+
+	// 测试获取不存在的实例会panic
+	assert.Panics(t, func() {
+		container.MustGet("nonexistent")
+	})
+}
+
+// TestTypedContainer_MustGet_EmptyName 测试必须获取空名称
+func TestTypedContainer_MustGet_EmptyName(t *testing.T) {
+	container := NewTypedContainer[*http.Client]()
+	client := &http.Client{}
+
+	container.Bind("", client)
+	assert.Equal(t, client, container.MustGet(""))
+}
+
+// TestTypedContainer_Values 测试获取所有实例功能
+func TestTypedContainer_Values(t *testing.T) {
+	container := NewTypedContainer[*http.Client]()
+
+	// 测试空容器
+	assert.Empty(t, container.Values())
+
+	client1 := &http.Client{Timeout: time.Second}
+	client2 := &http.Client{Timeout: 2 * time.Second}
+	client3 := &http.Client{Timeout: 3 * time.Second}
+	container.Bind("client1", client1)
+	container.Bind("client2", client2)
+	container.Bind("client3", client3)
+
+	// 验证返回的实例顺序与注册顺序一致
+	assert.Equal(t, []*http.Client{client1, client2, client3}, container.Values())
+}
+
+// TestTypedContainer_Values_WithOverride 测试覆盖后实例列表
+func TestTypedContainer_Values_WithOverride(t *testing.T) {
+	container := NewTypedContainer[*http.Client]()
+	client1 := &http.Client{Timeout: time.Second}
+	client2 := &http.Client{Timeout: 2 * time.Second}
+
+	container.Bind("client1", client1)
+	container.Bind("client2", client2)
+
+	// 覆盖第一个实例，保持原来的位置
+	client1New := &http.Client{Timeout: 3 * time.Second}
+	container.Bind("client1", client1New)
+
+	assert.Equal(t, []*http.Client{client1New, client2}, container.Values())
+}
+
+// TestTypedContainer_Names 测试获取所有名称功能
+func TestTypedContainer_Names(t *testing.T) {
+	container := NewTypedContainer[*http.Client]()
+
+	// 测试空容器
+	assert.Empty(t, container.Names())
+
+	container.Bind("client1", &http.Client{})
+	container.Bind("client2", &http.Client{})
+	container.Bind("client3", &http.Client{})
+
+	names := container.Names()
+	assert.Equal(t, []string{"client1", "client2", "client3"}, names)
+
+	// 测试返回的是副本，修改不影响内部状态
+	names[0] = "modified"
+	assert.Equal(t, "client1", container.Names()[0])
+}
+
+// TestTypedContainer_Names_EmptyName 测试包含空名称的名称列表
+func TestTypedContainer_Names_EmptyName(t *testing.T) {
+	container := NewTypedContainer[*http.Client]()
+
+	container.Bind("", &http.Client{})
+	container.Bind("normal", &http.Client{})
+
+	assert.Equal(t, []string{"", "normal"}, container.Names())
+}
+
+// TestTypedContainer_Remove 测试移除后其余实例保持顺序
+func TestTypedContainer_Remove(t *testing.T) {
+	container := NewTypedContainer[*http.Client]()
+	client1 := &http.Client{Timeout: time.Second}
+	client3 := &http.Client{Timeout: 3 * time.Second}
+	container.Bind("client1", client1)
+	container.Bind("client2", &http.Client{})
+	container.Bind("client3", client3)
+
+	assert.True(t, container.Remove("client2"))
+	assert.False(t, container.Remove("client2"))
+	assert.False(t, container.Remove("nonexistent"))
+
+	assert.Equal(t, []string{"client1", "client3"}, container.Names())
+	assert.Equal(t, []*http.Client{client1, client3}, container.Values())
+	_, err := container.Get("client2")
+	assert.ErrorIs(t, err, ErrEntryNotFound)
+
+	// 重新绑定时追加到末尾
+	container.Bind("client2", &http.Client{})
+	assert.Equal(t, []string{"client1", "client3", "client2"}, container.Names())
+}
+
+// TestTypedContainer_ConcurrentAccess 测试并发访问安全性
+func TestTypedContainer_ConcurrentAccess(t *testing.T) {
+	container := NewTypedContainer[*http.Client]()
+	client := &http.Client{}
+
+	var wg sync.WaitGroup
+	numGoroutines := 100
+
+	// 并发绑定
+	for i := 0; i < numGoroutines; i++ {
+		wg.Add(1)
+		go func(id int) {
+			defer wg.Done()
+			container.Bind("client-"+strconv.Itoa(id), client)
+		}(i)
+	}
+
+	// 并发读取
+	for i := 0; i < numGoroutines; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			container.Values()
+			container.Names()
+		}()
+	}
+
+	wg.Wait()
+
+	assert.Len(t, container.Values(), numGoroutines)
+	assert.Len(t, container.Names(), numGoroutines)
+}
+
+// TestTypedContainer_ConcurrentBindGetRemove 测试并发绑定、获取和移除
+func TestTypedContainer_ConcurrentBindGetRemove(t *testing.T) {
+	container := NewTypedContainer[*http.Client]()
+	client := &http.Client{}
+
+	var wg sync.WaitGroup
+	numGoroutines := 50
+
+	for i := 0; i < numGoroutines; i++ {
+		container.Bind("client-"+strconv.Itoa(i), client)
+	}
+
+	for i := 0; i < numGoroutines; i++ {
+		wg.Add(2)
+		go func(id int) {
+			defer wg.Done()
+			_, err := container.Get("client-" + strconv.Itoa(id))
+			assert.NoError(t, err)
+		}(i)
+		go func(id int) {
+			defer wg.Done()
+			container.Remove("removed-" + strconv.Itoa(id))
+			container.Bind("removed-"+strconv.Itoa(id), client)
+			container.Remove("removed-" + strconv.Itoa(id))
+		}(i)
+	}
+
+	wg.Wait()
+	assert.Len(t, container.Names(), numGoroutines)
+}
+
+// TestTypedContainer_OrderPreservation 测试注册顺序保持
+func TestTypedContainer_OrderPreservation(t *testing.T) {
+	container := NewTypedContainer[*http.Client]()
+
+	bindOrder := []string{"zebra", "apple", "banana", "cherry"}
+	for _, name := range bindOrder {
+		container.Bind(name, &http.Client{})
+	}
+
+	values := container.Values()
+	assert.Len(t, values, 4)
+	for i, name := range bindOrder {
+		result, _ := container.Get(name)
+		assert.Same(t, result, values[i])
+	}
+	assert.Equal(t, bindOrder, container.Names())
+}
+
+// TestContainer_TypedContainerErrors 测试服务容器保留 kernel 的错误类型
+func TestContainer_TypedContainerErrors(t *testing.T) {
+	container := NewContainer[*kerneltest.ServiceMock]()
+	_, err := container.Get("missing")
+	assert.True(t, kernel.IsServiceNotFound(err))
+	assert.NotErrorIs(t, err, ErrEntryNotFound)
+	assert.Panics(t, func() { container.MustGet("missing") })
+}
+
+// BenchmarkTypedContainer_Bind 测试绑定的性能
+func BenchmarkTypedContainer_Bind(b *testing.B) {
+	container := NewTypedContainer[*http.Client]()
+	client := &http.Client{}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		container.Bind("client", client)
+	}
+}
+
+// BenchmarkTypedContainer_Get 测试获取的性能
+func BenchmarkTypedContainer_Get(b *testing.B) {
+	container := NewTypedContainer[*http.Client]()
+	container.Bind("client", &http.Client{})
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = container.Get("client")
+	}
+}
+
+// BenchmarkTypedContainer_Values 测试获取所有实例的性能
+func BenchmarkTypedContainer_Values(b *testing.B) {
+	container := NewTypedContainer[*http.Client]()
+	for i := 0; i < 100; i++ {
+		container.Bind("client-"+strconv.Itoa(i), &http.Client{})
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = container.Values()
+	}
+}
+
+// BenchmarkTypedContainer_Names 测试获取所有名称的性能
+func BenchmarkTypedContainer_Names(b *testing.B) {
+	container := NewTypedContainer[*http.Client]()
+	for i := 0; i < 100; i++ {
+		container.Bind("client-"+strconv.Itoa(i), &http.Client{})
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = container.Names()
+	}
+}
+
+// BenchmarkTypedContainer_ConcurrentAccess 测试并发访问性能
+func BenchmarkTypedContainer_ConcurrentAccess(b *testing.B) {
+	container := NewTypedContainer[*http.Client]()
+	for i := 0; i < 10; i++ {
+		container.Bind("client-"+strconv.Itoa(i), &http.Client{})
+	}
+
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			_, _ = container.Get("client-0")
+			_ = container.Values()
+			_ = container.Names()
+		}
+	})
+}
@@ -0,0 +1,4 @@
+// Package e2e 包含端到端测试：在进程内构建 examples 中的示例应用，按真实的配置目录与日志配置
+// 驱动完整的生命周期（Boot、Run、Runner 工作、Shutdown），覆盖单元测试之间的集成行为。
+// 测试随 go test ./... 默认运行，日志写入临时目录，不修改仓库中的文件。
+package e2e
@@ -0,0 +1,122 @@
+package e2e
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/qq1060656096/drugo/drugo"
+	"github.com/qq1060656096/drugo/examples/minimal/app"
+	"github.com/qq1060656096/drugo/kernel"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// minimalConfDir 是示例应用的配置目录
+const minimalConfDir = "../examples/minimal/conf"
+
+// newMinimalApp 使用示例应用的配置目录与临时根目录构建应用，日志写入 root/runtime/logs
+func newMinimalApp(t *testing.T, clk kernel.Clock, opts ...drugo.Option) (*app.App, string) {
+	t.Helper()
+	confDir, err := filepath.Abs(minimalConfDir)
+	require.NoError(t, err)
+	root := t.TempDir()
+	opts = append([]drugo.Option{drugo.WithRoot(root), drugo.WithConfigDir(confDir), drugo.WithQuiet(false)}, opts...)
+	a := app.New(clk, opts...)
+	return a, root
+}
+
+// readLogMessages 返回 root/runtime/logs 中业务日志 biz 的所有 msg 字段
+func readLogMessages(t *testing.T, root, biz string) []string {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join(root, "runtime", "logs", biz+".log"))
+	require.NoError(t, err)
+	var msgs []string
+	for _, line := range bytes.Split(bytes.TrimSpace(data), []byte("\n")) {
+		var entry struct {
+			Msg string `json:"msg"`
+		}
+		require.NoError(t, json.Unmarshal(line, &entry), string(line))
+		msgs = append(msgs, entry.Msg)
+	}
+	return msgs
+}
+
+// TestMinimal_Lifecycle 测试示例应用完整的生命周期：按配置 Boot，ticker 在假时钟推进后向 worker 提交任务并被执行，
+// Stop 之后按依赖顺序关闭，生命周期日志写入配置的文件输出
+func TestMinimal_Lifecycle(t *testing.T) {
+	clk := kernel.NewFakeClock(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+	a, root := newMinimalApp(t, clk)
+	assert.Equal(t, []string{"log", "ticker", "worker"}, a.Config().List())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	h, err := a.Start(ctx)
+	require.NoError(t, err)
+
+	// ticker 与 worker 的配置已注入
+	assert.Equal(t, 2, a.Worker.Config().Workers)
+	assert.Equal(t, 16, a.Worker.Config().Size)
+
+	for i := range 3 {
+		clk.BlockUntilWaiters(1)
+		clk.Advance(time.Second)
+		require.Eventually(t, func() bool { return a.Ticker.Processed() == int64(i+1) }, 2*time.Second, time.Millisecond)
+	}
+	assert.Equal(t, int64(3), a.Ticker.Ticks())
+	assert.Empty(t, a.Degraded())
+	status := a.Status()
+	assert.Equal(t, drugo.ServiceStateRunning, status["ticker"].State)
+	assert.Equal(t, drugo.ServiceStateRunning, status["worker"].State)
+
+	require.NoError(t, h.Stop(ctx))
+	<-h.Done()
+	require.NoError(t, h.Err())
+	assert.Equal(t, int64(3), a.Worker.Stats().Completed)
+	assert.Equal(t, drugo.OutcomePending, a.Outcome())
+
+	framework := readLogMessages(t, root, "drugo")
+	assert.Subset(t, framework, []string{
+		"app starting",
+		"framework boot complete",
+		"framework run start",
+		"framework shutdown start",
+		"framework shutdown complete",
+		"framework run complete",
+	})
+	assert.Contains(t, framework, "framework init has config dir: "+a.ConfigDir())
+
+	ticker := readLogMessages(t, root, "ticker")
+	assert.Equal(t, "ticker booted", ticker[0])
+	assert.Equal(t, "ticker closed", ticker[len(ticker)-1])
+	assert.Subset(t, ticker, []string{"tick", "job processed"})
+
+	// ticker 依赖 worker，停机时先于 worker 关闭
+	summary, ok := a.LastShutdown()
+	require.True(t, ok)
+	var order []string
+	for _, s := range summary.Services {
+		order = append(order, s.Name)
+	}
+	assert.Equal(t, []string{"ticker", "worker"}, order)
+}
+
+// TestMinimal_Status 测试示例应用的内置 status 命令输出状态快照，且不会 Boot 服务
+func TestMinimal_Status(t *testing.T) {
+	var out bytes.Buffer
+	a, _ := newMinimalApp(t, nil, drugo.WithOutput(&out))
+
+	require.NoError(t, a.Execute(context.Background(), []string{"minimal", "status"}))
+	var s drugo.Snapshot
+	require.NoError(t, json.Unmarshal(out.Bytes(), &s))
+	require.Len(t, s.Services, 2)
+	assert.Equal(t, "worker", s.Services[0].Name)
+	assert.Equal(t, "ticker", s.Services[1].Name)
+	assert.Equal(t, []string{"worker"}, s.Services[1].DependsOn)
+	assert.False(t, s.Lifecycle.Booted)
+	assert.Equal(t, int64(0), a.Ticker.Ticks())
+}
@@ -0,0 +1,138 @@
+// Package app 组装 examples/minimal 示例应用：ticker 按配置的间隔向名为 worker 的后台任务池提交任务，
+// 两者都是 Runner，由框架负责配置注入、logger 注入、Boot、Run 与按依赖顺序 Shutdown。
+//
+// 应用在 main.go 中运行，同时被 e2e 包在进程内构建并驱动，作为生命周期相关改动的回归测试。
+package app
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/qq1060656096/drugo/drugo"
+	"github.com/qq1060656096/drugo/kernel"
+	"github.com/qq1060656096/drugo/pkg/clock"
+	"github.com/qq1060656096/drugo/provider/workerpool"
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+)
+
+// DefaultTickInterval 是 ticker 配置段没有设置 interval 时的提交间隔
+const DefaultTickInterval = time.Second
+
+// App 是示例应用及其服务
+type App struct {
+	*drugo.Drugo
+	Ticker *Ticker
+	Worker *workerpool.Pool
+}
+
+// New 创建示例应用，opts 追加在默认选项之后（例如 drugo.WithRoot、drugo.WithConfigDir）。
+// clk 为 ticker 与框架使用的时钟，为 nil 时使用系统时间
+func New(clk kernel.Clock, opts ...drugo.Option) *App {
+	worker := workerpool.New("worker")
+	ticker := NewTicker(worker, clk)
+	if clk != nil {
+		opts = append([]drugo.Option{drugo.WithClock(clk)}, opts...)
+	}
+	opts = append(opts, drugo.WithService(worker), drugo.WithService(ticker))
+	return &App{Drugo: drugo.MustNewApp(opts...), Ticker: ticker, Worker: worker}
+}
+
+var (
+	_ kernel.Runner       = (*Ticker)(nil)
+	_ kernel.Configurable = (*Ticker)(nil)
+	_ kernel.LoggerAware  = (*Ticker)(nil)
+	_ kernel.Dependent    = (*Ticker)(nil)
+)
+
+// Ticker 是按固定间隔向任务池提交任务的 Runner，对应 ticker 配置段：
+//
+//	ticker:
+//	  interval: 1s
+type Ticker struct {
+	interval time.Duration
+	clock    kernel.Clock
+	pool     *workerpool.Pool
+	logger   *zap.Logger
+
+	ticks     atomic.Int64
+	processed atomic.Int64
+}
+
+// NewTicker 创建向 pool 提交任务的 Ticker，clk 为 nil 时使用系统时间
+func NewTicker(pool *workerpool.Pool, clk kernel.Clock) *Ticker {
+	return &Ticker{interval: DefaultTickInterval, clock: clock.OrReal(clk), pool: pool, logger: zap.NewNop()}
+}
+
+// Name 返回服务名称
+func (t *Ticker) Name() string {
+	return "ticker"
+}
+
+// DependsOn 声明 ticker 依赖任务池，停机时 ticker 先于 worker 关闭
+func (t *Ticker) DependsOn() []string {
+	return []string{t.pool.Name()}
+}
+
+// SetLogger 接收框架注入的 logger
+func (t *Ticker) SetLogger(l *zap.Logger) {
+	t.logger = l
+}
+
+// Configure 读取 ticker 配置段，配置段不存在时使用默认间隔
+func (t *Ticker) Configure(v *viper.Viper) error {
+	if v == nil || !v.IsSet("interval") {
+		return nil
+	}
+	interval := v.GetDuration("interval")
+	if interval <= 0 {
+		return fmt.Errorf("ticker: interval must be positive, got %q", v.GetString("interval"))
+	}
+	t.interval = interval
+	return nil
+}
+
+// Boot 初始化服务
+func (t *Ticker) Boot(ctx context.Context) error {
+	t.logger.Info("ticker booted", zap.Duration("interval", t.interval))
+	return nil
+}
+
+// Run 每隔 interval 提交一个任务，直到 ctx 取消。任务池已满时跳过本次提交
+func (t *Ticker) Run(ctx context.Context) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-t.clock.After(t.interval):
+		}
+		n := t.ticks.Add(1)
+		t.logger.Info("tick", zap.Int64("tick", n))
+		err := t.pool.Submit(ctx, func(ctx context.Context) error {
+			t.processed.Add(1)
+			t.logger.Info("job processed", zap.Int64("tick", n))
+			return nil
+		})
+		if err != nil {
+			t.logger.Warn("job submit failed", zap.Int64("tick", n), zap.Error(err))
+		}
+	}
+}
+
+// Close 释放资源
+func (t *Ticker) Close(ctx context.Context) error {
+	t.logger.Info("ticker closed", zap.Int64("ticks", t.ticks.Load()))
+	return nil
+}
+
+// Ticks 返回已经触发的次数
+func (t *Ticker) Ticks() int64 {
+	return t.ticks.Load()
+}
+
+// Processed 返回 worker 已经执行完的任务数
+func (t *Ticker) Processed() int64 {
+	return t.processed.Load()
+}
@@ -0,0 +1,29 @@
+// Command minimal 是使用配置、日志、服务容器与 Runner 的最小示例应用，
+// 在 examples/minimal 目录中运行：
+//
+//	go run .
+//
+// 日志输出到控制台与 runtime/logs，按 Ctrl+C 优雅停机。
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/qq1060656096/drugo/drugo"
+	"github.com/qq1060656096/drugo/examples/minimal/app"
+)
+
+func main() {
+	wd, err := os.Getwd()
+	if err != nil {
+		panic(err)
+	}
+
+	a := app.New(nil, drugo.WithRoot(wd))
+	if err := a.Execute(context.Background(), os.Args); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+	}
+	drugo.Exit(a.Drugo)
+}
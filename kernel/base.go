@@ -0,0 +1,66 @@
+package kernel
+
+import (
+	"context"
+	"sync"
+
+	"github.com/qq1060656096/drugo/config"
+	"github.com/qq1060656096/drugo/log"
+)
+
+var _ Kernel = (*BaseKernel)(nil)
+
+// BaseKernel 是 Kernel 的可嵌入默认实现，零值即可使用。
+// 将其嵌入自定义结构体后只需覆盖关心的方法，例如：
+//
+//	type AppKernel struct {
+//	    kernel.BaseKernel
+//	    cfg *config.Manager
+//	}
+//
+//	func (k *AppKernel) Config() *config.Manager { return k.cfg }
+//
+//	var _ kernel.Kernel = (*AppKernel)(nil)
+//
+// 默认行为：
+//   - Container 返回首次调用时创建的 NewContainer 容器
+//   - Boot、Run、Shutdown、Serve 不做任何事并返回 nil
+//   - Root 返回 "."
+//   - Config、Logger 返回 nil；nil 的 *config.Manager 与 *log.Manager 可安全调用 Get/MustGet，
+//     分别返回 config.ErrNotFound 与不输出任何内容的日志实例
+//
+// 注意：Go 的嵌入没有虚方法分派，BaseKernel 的方法不会调用嵌入者覆盖的方法，
+// 例如覆盖 Boot 后 BaseKernel.Serve 仍然不会调用它，需要时请一并覆盖 Serve。
+type BaseKernel struct {
+	once      sync.Once
+	container Container[Service]
+}
+
+// Container 返回服务容器，首次调用时创建
+func (k *BaseKernel) Container() Container[Service] {
+	k.once.Do(func() {
+		k.container = NewContainer[Service]()
+	})
+	return k.container
+}
+
+// Boot 默认不做任何事
+func (k *BaseKernel) Boot(ctx context.Context) error { return nil }
+
+// Run 默认不做任何事
+func (k *BaseKernel) Run(ctx context.Context) error { return nil }
+
+// Shutdown 默认不做任何事
+func (k *BaseKernel) Shutdown(ctx context.Context) error { return nil }
+
+// Root 默认返回当前目录 "."
+func (k *BaseKernel) Root() string { return "." }
+
+// Config 默认返回 nil
+func (k *BaseKernel) Config() *config.Manager { return nil }
+
+// Logger 默认返回 nil
+func (k *BaseKernel) Logger() *log.Manager { return nil }
+
+// Serve 默认不做任何事
+func (k *BaseKernel) Serve(ctx context.Context) error { return nil }
@@ -0,0 +1,119 @@
+package kernel_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/qq1060656096/drugo/kernel"
+	"github.com/qq1060656096/drugo/kernel/kerneltest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// bootOnlyKernel 只覆盖 Boot 的嵌入者
+type bootOnlyKernel struct {
+	kernel.BaseKernel
+	booted bool
+}
+
+var _ kernel.Kernel = (*bootOnlyKernel)(nil)
+
+func (k *bootOnlyKernel) Boot(ctx context.Context) error {
+	k.booted = true
+	return nil
+}
+
+// TestBaseKernel_Defaults 测试零值 BaseKernel 的默认行为
+func TestBaseKernel_Defaults(t *testing.T) {
+	var k kernel.BaseKernel
+	ctx := context.Background()
+
+	assert.NoError(t, k.Boot(ctx))
+	assert.NoError(t, k.Run(ctx))
+	assert.NoError(t, k.Shutdown(ctx))
+	assert.NoError(t, k.Serve(ctx))
+	assert.Equal(t, ".", k.Root())
+	assert.Nil(t, k.Config())
+	assert.Nil(t, k.Logger())
+	assert.Same(t, k.Container(), k.Container())
+
+	// nil 的配置与日志管理器可以被安全使用
+	assert.NotPanics(t, func() {
+		k.Logger().MustGet("app").Info("discarded")
+	})
+	_, err := k.Config().Get("app")
+	assert.Error(t, err)
+}
+
+// TestBaseKernel_Embed 测试只覆盖 Boot 的嵌入者可以通过 GetService 与 WithContext 正常使用
+func TestBaseKernel_Embed(t *testing.T) {
+	k := &bootOnlyKernel{}
+	svc := kerneltest.NewServiceMock("db")
+	k.Container().Bind("db", svc)
+
+	var kern kernel.Kernel = k
+	require.NoError(t, kern.Boot(context.Background()))
+	assert.True(t, k.booted)
+
+	got, err := kernel.GetService[*kerneltest.ServiceMock](kern, "db")
+	require.NoError(t, err)
+	assert.Same(t, svc, got)
+
+	ctx := kernel.WithContext(context.Background(), kern)
+	assert.Same(t, svc, kernel.MustServiceFromContext[*kerneltest.ServiceMock](ctx, "db"))
+	assert.Same(t, kern, kernel.MustFromContext(ctx))
+
+	_, err = kernel.ServiceFromContext[*kerneltest.ServiceMock](ctx, "missing")
+	assert.True(t, kernel.IsServiceNotFound(err))
+}
+
+// TestNewContainer 测试默认容器的注册顺序与并发安全（配合 -race 运行）
+func TestNewContainer(t *testing.T) {
+	c := kernel.NewContainer[kernel.Service]()
+	c.Bind("a", kerneltest.NewServiceMock("a"))
+	c.Bind("b", kerneltest.NewServiceMock("b"))
+	c.Bind("a", kerneltest.NewServiceMock("a2"))
+
+	assert.Equal(t, []string{"a", "b"}, c.Names())
+	assert.Equal(t, "a2", c.MustGet("a").Name())
+	assert.Len(t, c.Services(), 2)
+	assert.Panics(t, func() { c.MustGet("missing") })
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			c.Bind("c", kerneltest.NewServiceMock("c"))
+			_ = c.Services()
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, []string{"a", "b", "c"}, c.Names())
+}
+
+// TestTypedContainer 测试无约束容器的注册顺序、删除与零值，以及 KernelMock 使用同一个容器实现
+func TestTypedContainer(t *testing.T) {
+	var c kernel.TypedContainer[int]
+	_, err := c.Get("missing")
+	assert.ErrorIs(t, err, kernel.ErrEntryNotFound)
+	assert.False(t, kernel.IsServiceNotFound(err))
+
+	c.Bind("a", 1)
+	c.Bind("b", 2)
+	c.Bind("a", 3)
+	assert.Equal(t, []string{"a", "b"}, c.Names())
+	assert.Equal(t, []int{3, 2}, c.Values())
+	assert.True(t, c.Remove("a"))
+	assert.False(t, c.Remove("a"))
+	assert.Equal(t, []string{"b"}, c.Names())
+
+	var services kernel.ServiceContainer[kernel.Service]
+	_, err = services.Get("missing")
+	assert.True(t, kernel.IsServiceNotFound(err))
+
+	k := kerneltest.NewKernelMock()
+	_, ok := k.BaseKernel.Container().(*kernel.ServiceContainer[kernel.Service])
+	assert.True(t, ok)
+}
@@ -0,0 +1,39 @@
+package kernel
+
+// Capabilities 描述一个声明自身能力的服务，返回能力名称到版本或取值的映射，
+// 例如 {"read-replicas": "1", "dialect": "mysql"}；不需要取值的能力使用空字符串。
+// 应用通过 HasCapability 与 CapabilityValue 查询能力，而不必对不断增加的具体类型做类型断言。
+// 框架在 Boot 之前检查 CapabilityRequirer 声明的依赖时就会调用 Capabilities，
+// 因此返回值不能依赖 Boot 中才完成的初始化。
+type Capabilities interface {
+	Capabilities() map[string]string
+}
+
+// CapabilityRequirer 描述一个依赖其他服务能力的服务，返回服务名称到所需能力名称的映射，
+// 例如 {"db": {"read-replicas"}}。框架在 Boot 之前检查所有声明，
+// 依赖的服务未注册、没有实现 Capabilities 或缺少所需能力时，汇总所有缺失项后使启动失败。
+type CapabilityRequirer interface {
+	RequiredCapabilities() map[string][]string
+}
+
+// HasCapability 报告名为 serviceName 的服务是否声明了能力 capability。
+// 服务未注册或没有实现 Capabilities 时返回 false。
+func HasCapability(k Kernel, serviceName, capability string) bool {
+	_, ok := CapabilityValue(k, serviceName, capability)
+	return ok
+}
+
+// CapabilityValue 返回名为 serviceName 的服务声明的能力 capability 的取值。
+// 服务未注册、没有实现 Capabilities 或没有声明该能力时 ok 为 false。
+func CapabilityValue(k Kernel, serviceName, capability string) (string, bool) {
+	svc, err := k.Container().Get(serviceName)
+	if err != nil {
+		return "", false
+	}
+	c, ok := svc.(Capabilities)
+	if !ok {
+		return "", false
+	}
+	value, ok := c.Capabilities()[capability]
+	return value, ok
+}
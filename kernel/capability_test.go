@@ -0,0 +1,39 @@
+package kernel_test
+
+import (
+	"testing"
+
+	"github.com/qq1060656096/drugo/kernel"
+	"github.com/qq1060656096/drugo/kernel/kerneltest"
+	"github.com/stretchr/testify/assert"
+)
+
+// capableService 是声明了能力的测试服务
+type capableService struct {
+	*kerneltest.ServiceMock
+	caps map[string]string
+}
+
+func (s *capableService) Capabilities() map[string]string { return s.caps }
+
+// TestCapabilityValue 测试查询已声明、未声明的能力，以及未实现接口或未注册的服务
+func TestCapabilityValue(t *testing.T) {
+	k := kerneltest.NewKernelMock()
+	k.Container().Bind("db", &capableService{
+		ServiceMock: kerneltest.NewServiceMock("db"),
+		caps:        map[string]string{"read-replicas": "2", "tx": ""},
+	})
+	k.Container().Bind("cache", kerneltest.NewServiceMock("cache"))
+
+	value, ok := kernel.CapabilityValue(k, "db", "read-replicas")
+	assert.True(t, ok)
+	assert.Equal(t, "2", value)
+	assert.True(t, kernel.HasCapability(k, "db", "read-replicas"))
+	assert.True(t, kernel.HasCapability(k, "db", "tx"), "取值为空的能力同样视为已声明")
+
+	assert.False(t, kernel.HasCapability(k, "db", "sharding"))
+	assert.False(t, kernel.HasCapability(k, "cache", "read-replicas"), "没有实现 Capabilities 的服务")
+	value, ok = kernel.CapabilityValue(k, "missing", "read-replicas")
+	assert.False(t, ok, "未注册的服务")
+	assert.Empty(t, value)
+}
@@ -0,0 +1,28 @@
+package kernel
+
+// 常用的资源声明类型
+const (
+	ClaimTCPPort    = "tcp-port"    // TCP 端口，Value 为端口号（如 "8080"）或监听地址（如 "127.0.0.1:8080"）
+	ClaimUnixSocket = "unix-socket" // Unix 套接字文件路径
+	ClaimFileLock   = "file-lock"   // 文件锁路径
+)
+
+// Claim 描述服务独占使用的一项资源，例如 {Kind: ClaimTCPPort, Value: "8080"}。
+// Kind 与 Value 都相同的两项声明视为冲突。
+type Claim struct {
+	Kind  string
+	Value string
+}
+
+// String 返回 "kind:value" 形式的声明描述
+func (c Claim) String() string {
+	return c.Kind + ":" + c.Value
+}
+
+// ResourceClaimer 描述一个声明独占资源的服务。
+// 框架在 Boot 之后、启动任何 Runner 之前收集所有服务的声明，
+// 两个服务声明了同一项资源时直接失败，而不是等到第二个服务 Run 时才绑定失败。
+// Claims 在 Boot 之后调用，因此可以返回由配置决定的端口等资源。
+type ResourceClaimer interface {
+	Claims() []Claim
+}
@@ -0,0 +1,26 @@
+package kernel
+
+import (
+	"time"
+
+	"github.com/qq1060656096/drugo/pkg/clock"
+)
+
+// Clock 是框架中基于时间的行为（停机超时、防抖、定期扫描等）使用的时间来源，见 pkg/clock。
+// 它是类型别名，config 与 log 包直接使用 pkg/clock 以避免与 kernel 循环依赖。
+type Clock = clock.Clock
+
+// Timer 是 Clock 创建的定时器
+type Timer = clock.Timer
+
+// RealClock 是使用系统时间的 Clock，是框架的默认时钟
+type RealClock = clock.Real
+
+// FakeClock 是由测试手动推进的 Clock，见 NewFakeClock
+type FakeClock = clock.Fake
+
+// NewFakeClock 创建当前时间为 now 的 FakeClock，时间只在调用 Advance 时前进，
+// 可以使用 BlockUntilWaiters 等待被测代码创建定时器后再推进时间
+func NewFakeClock(now time.Time) *FakeClock {
+	return clock.NewFake(now)
+}
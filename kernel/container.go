@@ -1,5 +1,11 @@
 package kernel
 
+import (
+	"fmt"
+	"slices"
+	"sync"
+)
+
 // Container 表示一个通用的服务容器，用于管理满足 Service 或 RunnerService 约束的实例。
 // T 约束确保了存入容器的对象具备预定义的行为。
 type Container[T Service] interface {
@@ -21,3 +27,132 @@ type Container[T Service] interface {
 	// Names 返回所有已注册的服务名称
 	Names() []string
 }
+
+// 确保 ServiceContainer 完整实现了 Container 接口
+var _ Container[Service] = (*ServiceContainer[Service])(nil)
+
+// NewContainer 创建一个并发安全的服务容器，Services 与 Names 按注册顺序返回。
+// 它是 BaseKernel 与 drugo 应用使用的服务容器实现。
+func NewContainer[T Service]() *ServiceContainer[T] {
+	return &ServiceContainer[T]{TypedContainer: TypedContainer[T]{values: make(map[string]T)}}
+}
+
+// ServiceContainer 是 Container 的默认实现。
+// 它是 TypedContainer 针对服务的特化：存储与顺序语义完全相同，
+// 服务不存在时返回 ErrServiceNotFound。零值可以直接使用。
+type ServiceContainer[T Service] struct {
+	TypedContainer[T]
+}
+
+// Get 根据名称获取对应的服务实例。
+// 如果服务不存在，则返回包装了 ErrServiceNotFound 的错误。
+func (c *ServiceContainer[T]) Get(name string) (T, error) {
+	svc, ok := c.lookup(name)
+	if !ok {
+		return svc, NewServiceNotFound(name)
+	}
+	return svc, nil
+}
+
+// MustGet 尝试获取服务实例，如果服务不存在则直接触发 panic。
+func (c *ServiceContainer[T]) MustGet(name string) T {
+	svc, err := c.Get(name)
+	if err != nil {
+		panic(err)
+	}
+	return svc
+}
+
+// Services 按注册顺序返回所有已注册的服务实例。
+func (c *ServiceContainer[T]) Services() []T {
+	return c.Values()
+}
+
+// TypedContainer 是一个并发安全、保持注册顺序的通用容器，对存放的类型没有约束，
+// 可用于路由元数据、按租户划分的句柄、编解码器注册表等非生命周期对象。
+// 服务容器 ServiceContainer 基于它实现。零值可以直接使用。
+type TypedContainer[T any] struct {
+	values map[string]T // 存储名称到实例的映射
+	names  []string     // 记录注册的先后顺序
+	mu     sync.RWMutex // 保护并发读写的读写锁
+}
+
+// NewTypedContainer 创建一个空的 TypedContainer
+func NewTypedContainer[T any]() *TypedContainer[T] {
+	return &TypedContainer[T]{values: make(map[string]T)}
+}
+
+// Bind 将 value 绑定到指定的名称。
+// 如果名称已存在，则覆盖旧实例并保持原来的顺序；否则追加到末尾。
+func (c *TypedContainer[T]) Bind(name string, value T) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.values == nil {
+		c.values = make(map[string]T)
+	}
+	if _, ok := c.values[name]; !ok {
+		c.names = append(c.names, name)
+	}
+	c.values[name] = value
+}
+
+// Get 根据名称获取对应的实例，不存在时返回零值和包装了 ErrEntryNotFound 的错误。
+func (c *TypedContainer[T]) Get(name string) (T, error) {
+	value, ok := c.lookup(name)
+	if !ok {
+		return value, fmt.Errorf("%w: %q", ErrEntryNotFound, name)
+	}
+	return value, nil
+}
+
+// MustGet 类似于 Get，但实例不存在时直接 panic。
+func (c *TypedContainer[T]) MustGet(name string) T {
+	value, err := c.Get(name)
+	if err != nil {
+		panic(err)
+	}
+	return value
+}
+
+// Values 按注册顺序返回所有实例。
+func (c *TypedContainer[T]) Values() []T {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	values := make([]T, 0, len(c.names))
+	for _, name := range c.names {
+		values = append(values, c.values[name])
+	}
+	return values
+}
+
+// Names 按注册顺序返回所有名称的副本。
+func (c *TypedContainer[T]) Names() []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return slices.Clone(c.names)
+}
+
+// Remove 移除指定名称的实例，其余实例保持原来的顺序；返回该名称是否存在。
+func (c *TypedContainer[T]) Remove(name string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, ok := c.values[name]; !ok {
+		return false
+	}
+	delete(c.values, name)
+	c.names = slices.DeleteFunc(c.names, func(n string) bool { return n == name })
+	return true
+}
+
+// lookup 根据名称查找实例
+func (c *TypedContainer[T]) lookup(name string) (T, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	value, ok := c.values[name]
+	return value, ok
+}
@@ -10,6 +10,15 @@ func WithContext(ctx context.Context, kernel Kernel) context.Context {
 	return context.WithValue(ctx, kernelCtxKey{}, kernel)
 }
 
+// EnsureKernel 在 ctx 尚未携带 Kernel 时返回携带 kernel 的上下文，已经携带时原样返回 ctx。
+// 多层中间件都调用它时只会包装一次，上下文中的 Kernel 以最外层写入的为准。
+func EnsureKernel(ctx context.Context, kernel Kernel) context.Context {
+	if k, ok := FromContext(ctx); ok && k != nil {
+		return ctx
+	}
+	return WithContext(ctx, kernel)
+}
+
 func FromContext(ctx context.Context) (Kernel, bool) {
 	k, ok := ctx.Value(kernelCtxKey{}).(Kernel)
 	return k, ok
@@ -44,3 +53,14 @@ func MustServiceFromContext[T any](ctx context.Context, name string) T {
 	}
 	return svc
 }
+
+// TryServiceFromContext 是 TryGetService 的上下文版本。
+// 上下文中没有 Kernel 或服务未注册时返回 ok=false；类型不匹配时 panic。
+func TryServiceFromContext[T any](ctx context.Context, name string) (T, bool) {
+	k, ok := FromContext(ctx)
+	if !ok || k == nil {
+		var zero T
+		return zero, false
+	}
+	return TryGetService[T](k, name)
+}
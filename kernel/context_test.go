@@ -1,31 +1,55 @@
-package kernel
+package kernel_test
 
 import (
 	"context"
 	"testing"
 	"time"
 
+	"github.com/qq1060656096/drugo/kernel"
+	"github.com/qq1060656096/drugo/kernel/kerneltest"
 	"github.com/stretchr/testify/assert"
 )
 
 // TestWithContext 测试 WithContext 函数
 func TestWithContext(t *testing.T) {
 	// 创建模拟内核
-	kernel := NewMockKernel()
+	k := kerneltest.NewKernelMock()
 
 	// 创建上下文
 	ctx := context.Background()
 
 	// 使用 WithContext 将内核添加到上下文
-	ctxWithKernel := WithContext(ctx, kernel)
+	ctxWithKernel := kernel.WithContext(ctx, k)
 
 	// 验证上下文不为空
 	assert.NotNil(t, ctxWithKernel, "WithContext 应该返回非空的上下文")
 
 	// 验证可以从上下文中获取内核
-	retrievedKernel, ok := FromContext(ctxWithKernel)
+	retrievedKernel, ok := kernel.FromContext(ctxWithKernel)
 	assert.True(t, ok, "应该能够从上下文中获取内核")
-	assert.Equal(t, kernel, retrievedKernel, "获取的内核应该与设置的内核相同")
+	assert.Equal(t, k, retrievedKernel, "获取的内核应该与设置的内核相同")
+}
+
+// TestEnsureKernel 测试上下文中没有内核时写入，已有内核时原样返回
+func TestEnsureKernel(t *testing.T) {
+	outer := kerneltest.NewKernelMock()
+	inner := kerneltest.NewKernelMock()
+
+	ctx := kernel.EnsureKernel(context.Background(), outer)
+	got, ok := kernel.FromContext(ctx)
+	assert.True(t, ok)
+	assert.Same(t, outer, got)
+
+	// 已有内核时不再包装，外层写入的内核优先
+	again := kernel.EnsureKernel(ctx, inner)
+	assert.Equal(t, ctx, again)
+	got, _ = kernel.FromContext(again)
+	assert.Same(t, outer, got)
+
+	// nil 内核视为不存在
+	ctx = kernel.EnsureKernel(kernel.WithContext(context.Background(), nil), inner)
+	got, _ = kernel.FromContext(ctx)
+	assert.Same(t, inner, got)
 }
 
 // TestWithContext_NilKernel 测试传入 nil 内核的情况
@@ -33,10 +57,10 @@ func TestWithContext_NilKernel(t *testing.T) {
 	ctx := context.Background()
 
 	// 传入 nil 内核
-	ctxWithKernel := WithContext(ctx, nil)
+	ctxWithKernel := kernel.WithContext(ctx, nil)
 
 	// 验证从上下文中获取内核时，由于 nil 不满足 Kernel 接口类型断言，返回 false
-	retrievedKernel, ok := FromContext(ctxWithKernel)
+	retrievedKernel, ok := kernel.FromContext(ctxWithKernel)
 	assert.False(t, ok, "当存储 nil 时，类型断言应该失败")
 	assert.Nil(t, retrievedKernel, "获取的内核应该为 nil")
 }
@@ -44,16 +68,16 @@ func TestWithContext_NilKernel(t *testing.T) {
 // TestFromContext 测试 FromContext 函数
 func TestFromContext(t *testing.T) {
 	// 测试从包含内核的上下文中获取
-	kernel := NewMockKernel()
-	ctx := WithContext(context.Background(), kernel)
+	k := kerneltest.NewKernelMock()
+	ctx := kernel.WithContext(context.Background(), k)
 
-	retrievedKernel, ok := FromContext(ctx)
+	retrievedKernel, ok := kernel.FromContext(ctx)
 	assert.True(t, ok, "应该能够从上下文中获取内核")
-	assert.Equal(t, kernel, retrievedKernel, "获取的内核应该与设置的内核相同")
+	assert.Equal(t, k, retrievedKernel, "获取的内核应该与设置的内核相同")
 
 	// 测试从不包含内核的上下文中获取
 	ctxWithoutKernel := context.Background()
-	retrievedKernel, ok = FromContext(ctxWithoutKernel)
+	retrievedKernel, ok = kernel.FromContext(ctxWithoutKernel)
 	assert.False(t, ok, "不应该能够从普通上下文中获取内核")
 	assert.Nil(t, retrievedKernel, "获取的内核应该为 nil")
 }
@@ -61,42 +85,42 @@ func TestFromContext(t *testing.T) {
 // TestMustFromContext 测试 MustFromContext 函数
 func TestMustFromContext(t *testing.T) {
 	// 测试成功获取
-	kernel := NewMockKernel()
-	ctx := WithContext(context.Background(), kernel)
+	k := kerneltest.NewKernelMock()
+	ctx := kernel.WithContext(context.Background(), k)
 
-	retrievedKernel := MustFromContext(ctx)
-	assert.Equal(t, kernel, retrievedKernel, "MustFromContext 应该返回正确的内核")
+	retrievedKernel := kernel.MustFromContext(ctx)
+	assert.Equal(t, k, retrievedKernel, "MustFromContext 应该返回正确的内核")
 
 	// 测试上下文中没有内核时的 panic 行为
 	ctxWithoutKernel := context.Background()
 	assert.Panics(t, func() {
-		MustFromContext(ctxWithoutKernel)
+		kernel.MustFromContext(ctxWithoutKernel)
 	}, "当上下文中没有内核时，MustFromContext 应该 panic")
 }
 
 // TestServiceFromContext 测试 ServiceFromContext 函数
 func TestServiceFromContext(t *testing.T) {
 	// 设置测试环境
-	kernel := NewMockKernel()
-	service := NewMockService("test-service")
-	kernel.Container().Bind("test-service", service)
+	k := kerneltest.NewKernelMock()
+	service := kerneltest.NewServiceMock("test-service")
+	k.Container().Bind("test-service", service)
 
-	ctx := WithContext(context.Background(), kernel)
+	ctx := kernel.WithContext(context.Background(), k)
 
 	// 测试成功获取服务
-	retrievedService, err := ServiceFromContext[*MockService](ctx, "test-service")
+	retrievedService, err := kernel.ServiceFromContext[*kerneltest.ServiceMock](ctx, "test-service")
 	assert.NoError(t, err, "获取服务不应该出错")
 	assert.Equal(t, service, retrievedService, "获取的服务应该与绑定的服务相同")
 
 	// 测试获取不存在的服务
-	_, err = ServiceFromContext[*MockService](ctx, "non-existent-service")
+	_, err = kernel.ServiceFromContext[*kerneltest.ServiceMock](ctx, "non-existent-service")
 	assert.Error(t, err, "获取不存在的服务应该出错")
-	assert.True(t, IsServiceNotFound(err), "应该是服务未找到错误")
+	assert.True(t, kernel.IsServiceNotFound(err), "应该是服务未找到错误")
 
 	// 测试类型不匹配
-	_, err = ServiceFromContext[string](ctx, "test-service")
+	_, err = kernel.ServiceFromContext[string](ctx, "test-service")
 	assert.Error(t, err, "类型不匹配应该出错")
-	assert.True(t, IsServiceType(err), "应该是服务类型错误")
+	assert.True(t, kernel.IsServiceType(err), "应该是服务类型错误")
 }
 
 // TestServiceFromContext_NoKernel 测试上下文中没有内核的情况
@@ -104,85 +128,110 @@ func TestServiceFromContext_NoKernel(t *testing.T) {
 	ctx := context.Background()
 
 	// 测试从没有内核的上下文获取服务
-	_, err := ServiceFromContext[*MockService](ctx, "test-service")
+	_, err := kernel.ServiceFromContext[*kerneltest.ServiceMock](ctx, "test-service")
 	assert.Error(t, err, "从没有内核的上下文获取服务应该出错")
-	assert.True(t, IsKernelError(err), "应该是内核错误")
+	assert.True(t, kernel.IsKernelError(err), "应该是内核错误")
 }
 
 // TestServiceFromContext_NilKernel 测试内核为 nil 的情况
 func TestServiceFromContext_NilKernel(t *testing.T) {
-	ctx := WithContext(context.Background(), nil)
+	ctx := kernel.WithContext(context.Background(), nil)
 
 	// 测试从内核为 nil 的上下文获取服务
-	_, err := ServiceFromContext[*MockService](ctx, "test-service")
+	_, err := kernel.ServiceFromContext[*kerneltest.ServiceMock](ctx, "test-service")
 	assert.Error(t, err, "从内核为 nil 的上下文获取服务应该出错")
-	assert.True(t, IsKernelError(err), "应该是内核错误")
+	assert.True(t, kernel.IsKernelError(err), "应该是内核错误")
+}
+
+// TestTryServiceFromContext 测试 TryServiceFromContext 函数
+func TestTryServiceFromContext(t *testing.T) {
+	k := kerneltest.NewKernelMock()
+	service := kerneltest.NewServiceMock("test-service")
+	k.Container().Bind("test-service", service)
+	ctx := kernel.WithContext(context.Background(), k)
+
+	svc, ok := kernel.TryServiceFromContext[*kerneltest.ServiceMock](ctx, "test-service")
+	assert.True(t, ok)
+	assert.Equal(t, service, svc)
+
+	_, ok = kernel.TryServiceFromContext[*kerneltest.ServiceMock](ctx, "non-existent-service")
+	assert.False(t, ok)
+
+	assert.Panics(t, func() {
+		kernel.TryServiceFromContext[string](ctx, "test-service")
+	})
+
+	_, ok = kernel.TryServiceFromContext[*kerneltest.ServiceMock](context.Background(), "test-service")
+	assert.False(t, ok, "没有内核的上下文应该返回 false")
+
+	_, ok = kernel.TryServiceFromContext[*kerneltest.ServiceMock](kernel.WithContext(context.Background(), nil), "test-service")
+	assert.False(t, ok, "内核为 nil 的上下文应该返回 false")
 }
 
 // TestMustServiceFromContext 测试 MustServiceFromContext 函数
 func TestMustServiceFromContext(t *testing.T) {
 	// 设置测试环境
-	kernel := NewMockKernel()
-	service := NewMockService("test-service")
-	kernel.Container().Bind("test-service", service)
+	k := kerneltest.NewKernelMock()
+	service := kerneltest.NewServiceMock("test-service")
+	k.Container().Bind("test-service", service)
 
-	ctx := WithContext(context.Background(), kernel)
+	ctx := kernel.WithContext(context.Background(), k)
 
 	// 测试成功获取服务
-	retrievedService := MustServiceFromContext[*MockService](ctx, "test-service")
+	retrievedService := kernel.MustServiceFromContext[*kerneltest.ServiceMock](ctx, "test-service")
 	assert.Equal(t, service, retrievedService, "MustServiceFromContext 应该返回正确的服务")
 
 	// 测试获取不存在的服务时的 panic 行为
 	assert.Panics(t, func() {
-		MustServiceFromContext[*MockService](ctx, "non-existent-service")
+		kernel.MustServiceFromContext[*kerneltest.ServiceMock](ctx, "non-existent-service")
 	}, "获取不存在的服务时应该 panic")
 
 	// 测试类型不匹配时的 panic 行为
 	assert.Panics(t, func() {
-		MustServiceFromContext[string](ctx, "test-service")
+		kernel.MustServiceFromContext[string](ctx, "test-service")
 	}, "类型不匹配时应该 panic")
 
 	// 测试上下文中没有内核时的 panic 行为
 	ctxWithoutKernel := context.Background()
 	assert.Panics(t, func() {
-		MustServiceFromContext[*MockService](ctxWithoutKernel, "test-service")
+		kernel.MustServiceFromContext[*kerneltest.ServiceMock](ctxWithoutKernel, "test-service")
 	}, "上下文中没有内核时应该 panic")
 }
 
 // TestContextKeyUniqueness 测试上下文键的唯一性
 func TestContextKeyUniqueness(t *testing.T) {
 	// 创建两个不同的内核
-	kernel1 := NewMockKernel()
-	kernel2 := NewMockKernel()
+	kernel1 := kerneltest.NewKernelMock()
+	kernel2 := kerneltest.NewKernelMock()
 
 	// 在第一个内核中绑定一个特定的服务
-	service1 := NewMockService("service1")
+	service1 := kerneltest.NewServiceMock("service1")
 	kernel1.Container().Bind("service1", service1)
 
 	// 在第二个内核中绑定一个不同的服务
-	service2 := NewMockService("service2")
+	service2 := kerneltest.NewServiceMock("service2")
 	kernel2.Container().Bind("service2", service2)
 
 	// 分别创建上下文
-	ctx1 := WithContext(context.Background(), kernel1)
-	ctx2 := WithContext(context.Background(), kernel2)
+	ctx1 := kernel.WithContext(context.Background(), kernel1)
+	ctx2 := kernel.WithContext(context.Background(), kernel2)
 
 	// 验证每个上下文返回正确的内核
-	_, ok1 := FromContext(ctx1)
-	_, ok2 := FromContext(ctx2)
+	_, ok1 := kernel.FromContext(ctx1)
+	_, ok2 := kernel.FromContext(ctx2)
 
 	assert.True(t, ok1, "应该能够从第一个上下文获取内核")
 	assert.True(t, ok2, "应该能够从第二个上下文获取内核")
 
 	// 验证上下文隔离：第一个上下文只能访问第一个内核的服务
-	retrievedService1, err1 := ServiceFromContext[*MockService](ctx1, "service1")
+	retrievedService1, err1 := kernel.ServiceFromContext[*kerneltest.ServiceMock](ctx1, "service1")
 	assert.NoError(t, err1, "应该能够从第一个上下文获取 service1")
 	assert.Equal(t, service1, retrievedService1, "获取的服务应该正确")
 
 	// 第一个上下文不应该能访问第二个内核的服务
-	_, err2 := ServiceFromContext[*MockService](ctx1, "service2")
+	_, err2 := kernel.ServiceFromContext[*kerneltest.ServiceMock](ctx1, "service2")
 	assert.Error(t, err2, "第一个上下文不应该能访问 service2")
-	assert.True(t, IsServiceNotFound(err2), "应该是服务未找到错误")
+	assert.True(t, kernel.IsServiceNotFound(err2), "应该是服务未找到错误")
 }
 
 // TestContextChain 测试上下文链式传递
@@ -194,16 +243,16 @@ func TestContextChain(t *testing.T) {
 	valueCtx := context.WithValue(originalCtx, "test-key", "test-value")
 
 	// 添加内核到上下文
-	kernel := NewMockKernel()
-	kernelCtx := WithContext(valueCtx, kernel)
+	k := kerneltest.NewKernelMock()
+	kernelCtx := kernel.WithContext(valueCtx, k)
 
 	// 验证原始值仍然存在
 	assert.Equal(t, "test-value", kernelCtx.Value("test-key"), "原始上下文的值应该保留")
 
 	// 验证内核可以获取
-	retrievedKernel, ok := FromContext(kernelCtx)
+	retrievedKernel, ok := kernel.FromContext(kernelCtx)
 	assert.True(t, ok, "应该能够获取内核")
-	assert.Equal(t, kernel, retrievedKernel, "获取的内核应该正确")
+	assert.Equal(t, k, retrievedKernel, "获取的内核应该正确")
 }
 
 // TestContextCancellation 测试上下文取消行为
@@ -212,13 +261,13 @@ func TestContextCancellation(t *testing.T) {
 	ctx, cancel := context.WithCancel(context.Background())
 
 	// 添加内核
-	kernel := NewMockKernel()
-	ctxWithKernel := WithContext(ctx, kernel)
+	k := kerneltest.NewKernelMock()
+	ctxWithKernel := kernel.WithContext(ctx, k)
 
 	// 验证内核可以获取
-	retrievedKernel, ok := FromContext(ctxWithKernel)
+	retrievedKernel, ok := kernel.FromContext(ctxWithKernel)
 	assert.True(t, ok, "取消前应该能够获取内核")
-	assert.Equal(t, kernel, retrievedKernel, "获取的内核应该正确")
+	assert.Equal(t, k, retrievedKernel, "获取的内核应该正确")
 
 	// 取消上下文
 	cancel()
@@ -232,15 +281,15 @@ func TestContextCancellation(t *testing.T) {
 	}
 
 	// 验证即使上下文取消，内核仍然可以获取
-	retrievedKernel, ok = FromContext(ctxWithKernel)
+	retrievedKernel, ok = kernel.FromContext(ctxWithKernel)
 	assert.True(t, ok, "取消后仍然应该能够获取内核")
-	assert.Equal(t, kernel, retrievedKernel, "获取的内核应该仍然正确")
+	assert.Equal(t, k, retrievedKernel, "获取的内核应该仍然正确")
 }
 
 // TestConcurrentAccess 测试并发访问安全性
 func TestConcurrentAccess(t *testing.T) {
-	kernel := NewMockKernel()
-	ctx := WithContext(context.Background(), kernel)
+	k := kerneltest.NewKernelMock()
+	ctx := kernel.WithContext(context.Background(), k)
 
 	// 并发读取内核
 	const numGoroutines = 100
@@ -252,13 +301,13 @@ func TestConcurrentAccess(t *testing.T) {
 
 			// 多次读取内核
 			for j := 0; j < 10; j++ {
-				retrievedKernel, ok := FromContext(ctx)
+				retrievedKernel, ok := kernel.FromContext(ctx)
 				assert.True(t, ok, "应该能够获取内核")
-				assert.Equal(t, kernel, retrievedKernel, "获取的内核应该正确")
+				assert.Equal(t, k, retrievedKernel, "获取的内核应该正确")
 
 				// 测试 MustFromContext
-				mustKernel := MustFromContext(ctx)
-				assert.Equal(t, kernel, mustKernel, "MustFromContext 应该返回正确的内核")
+				mustKernel := kernel.MustFromContext(ctx)
+				assert.Equal(t, k, mustKernel, "MustFromContext 应该返回正确的内核")
 			}
 		}()
 	}
@@ -276,61 +325,61 @@ func TestConcurrentAccess(t *testing.T) {
 
 // BenchmarkWithContext 性能测试：WithContext 函数
 func BenchmarkWithContext(b *testing.B) {
-	kernel := NewMockKernel()
+	k := kerneltest.NewKernelMock()
 	ctx := context.Background()
 
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
-		_ = WithContext(ctx, kernel)
+		_ = kernel.WithContext(ctx, k)
 	}
 }
 
 // BenchmarkFromContext 性能测试：FromContext 函数
 func BenchmarkFromContext(b *testing.B) {
-	kernel := NewMockKernel()
-	ctx := WithContext(context.Background(), kernel)
+	k := kerneltest.NewKernelMock()
+	ctx := kernel.WithContext(context.Background(), k)
 
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
-		_, _ = FromContext(ctx)
+		_, _ = kernel.FromContext(ctx)
 	}
 }
 
 // BenchmarkMustFromContext 性能测试：MustFromContext 函数
 func BenchmarkMustFromContext(b *testing.B) {
-	kernel := NewMockKernel()
-	ctx := WithContext(context.Background(), kernel)
+	k := kerneltest.NewKernelMock()
+	ctx := kernel.WithContext(context.Background(), k)
 
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
-		_ = MustFromContext(ctx)
+		_ = kernel.MustFromContext(ctx)
 	}
 }
 
 // BenchmarkServiceFromContext 性能测试：ServiceFromContext 函数
 func BenchmarkServiceFromContext(b *testing.B) {
-	kernel := NewMockKernel()
-	service := NewMockService("benchmark-service")
-	kernel.Container().Bind("benchmark-service", service)
+	k := kerneltest.NewKernelMock()
+	service := kerneltest.NewServiceMock("benchmark-service")
+	k.Container().Bind("benchmark-service", service)
 
-	ctx := WithContext(context.Background(), kernel)
+	ctx := kernel.WithContext(context.Background(), k)
 
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
-		_, _ = ServiceFromContext[*MockService](ctx, "benchmark-service")
+		_, _ = kernel.ServiceFromContext[*kerneltest.ServiceMock](ctx, "benchmark-service")
 	}
 }
 
 // BenchmarkMustServiceFromContext 性能测试：MustServiceFromContext 函数
 func BenchmarkMustServiceFromContext(b *testing.B) {
-	kernel := NewMockKernel()
-	service := NewMockService("benchmark-service")
-	kernel.Container().Bind("benchmark-service", service)
+	k := kerneltest.NewKernelMock()
+	service := kerneltest.NewServiceMock("benchmark-service")
+	k.Container().Bind("benchmark-service", service)
 
-	ctx := WithContext(context.Background(), kernel)
+	ctx := kernel.WithContext(context.Background(), k)
 
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
-		_ = MustServiceFromContext[*MockService](ctx, "benchmark-service")
+		_ = kernel.MustServiceFromContext[*kerneltest.ServiceMock](ctx, "benchmark-service")
 	}
 }
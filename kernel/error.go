@@ -2,6 +2,7 @@ package kernel
 
 import (
 	"errors"
+	"fmt"
 )
 
 var (
@@ -11,6 +12,16 @@ var (
 	ErrServiceRunFailed   = errors.New("kernel: service run failed")
 	ErrServiceCloseFailed = errors.New("kernel: service close failed")
 	ErrServiceType        = errors.New("kernel: service type mismatch")
+	// ErrInvalidServiceName 表示服务名称格式无效，见 ValidateServiceName
+	ErrInvalidServiceName = errors.New("kernel: invalid service name")
+	// ErrReservedServiceName 表示服务名称与框架保留名称冲突，见 ReservedNames
+	ErrReservedServiceName = errors.New("kernel: reserved service name")
+	// ErrCloseRetryable 表示服务暂时无法关闭，可以稍后重试。
+	// 服务在 Close 中返回包装了它的错误（例如 fmt.Errorf("%w: %w", kernel.ErrCloseRetryable, err)）时，
+	// 框架在停机超时时间内退避重试该服务的 Close
+	ErrCloseRetryable = errors.New("kernel: close retryable")
+	// ErrEntryNotFound 表示 TypedContainer 中不存在指定名称的实例
+	ErrEntryNotFound = errors.New("kernel: entry not found")
 )
 
 // IsKernelError 判断是否为内核级别的错误（任意一个）
@@ -22,7 +33,8 @@ func IsKernelError(err error) bool {
 	kernelErrors := []error{
 		ErrServiceNotFound, ErrKernelNotInContext,
 		ErrServiceInitFailed, ErrServiceRunFailed, ErrServiceCloseFailed,
-		ErrServiceType,
+		ErrServiceType, ErrInvalidServiceName, ErrReservedServiceName,
+		ErrCloseRetryable, ErrEntryNotFound,
 	}
 	for _, target := range kernelErrors {
 		if errors.Is(err, target) {
@@ -49,10 +61,23 @@ func IsServiceCloseFailed(err error) bool {
 	return errors.Is(err, ErrServiceCloseFailed)
 }
 
+// IsCloseRetryable 判断 Close 的错误是否可以重试，见 ErrCloseRetryable
+func IsCloseRetryable(err error) bool {
+	return errors.Is(err, ErrCloseRetryable)
+}
+
 func IsServiceType(err error) bool {
 	return errors.Is(err, ErrServiceType)
 }
 
+func IsInvalidServiceName(err error) bool {
+	return errors.Is(err, ErrInvalidServiceName)
+}
+
+func IsReservedServiceName(err error) bool {
+	return errors.Is(err, ErrReservedServiceName)
+}
+
 // Error 是 Drugo 内核的标准错误结构
 // 模仿标准库 net.OpError，记录操作名称和原始错误
 type Error struct {
@@ -69,6 +94,11 @@ func (e *Error) Error() string {
 	return "kernel " + e.op + ": " + e.err.Error()
 }
 
+// Op 返回发生错误的操作，服务相关的错误中为服务名称
+func (e *Error) Op() string {
+	return e.op
+}
+
 // Unwrap 实现 Go 1.13+ 的错误链解包接口
 func (e *Error) Unwrap() error {
 	return e.err
@@ -105,6 +135,30 @@ func NewServiceType(serviceName string) error {
 	return NewError(serviceName, ErrServiceType)
 }
 
+// WrapServiceInitFailed 将服务 Boot 返回的 err 包装为内核错误：
+// errors.Is 同时匹配 ErrServiceInitFailed 与 err，服务名称可以通过 errors.As 得到的 *Error 的 Op 获取。
+// err 为 nil 时返回 nil。
+func WrapServiceInitFailed(serviceName string, err error) error {
+	return wrapServiceError(serviceName, ErrServiceInitFailed, err)
+}
+
+// WrapServiceRunFailed 将 Runner 的 Run 返回的 err 包装为内核错误，见 WrapServiceInitFailed
+func WrapServiceRunFailed(serviceName string, err error) error {
+	return wrapServiceError(serviceName, ErrServiceRunFailed, err)
+}
+
+// WrapServiceCloseFailed 将服务 Close 返回的 err 包装为内核错误，见 WrapServiceInitFailed
+func WrapServiceCloseFailed(serviceName string, err error) error {
+	return wrapServiceError(serviceName, ErrServiceCloseFailed, err)
+}
+
+func wrapServiceError(serviceName string, kind, err error) error {
+	if err == nil {
+		return nil
+	}
+	return NewError(serviceName, fmt.Errorf("%w: %w", kind, err))
+}
+
 func NewKernelNotInContext() error {
 	return NewError("kernel", ErrKernelNotInContext)
 }
@@ -2,6 +2,7 @@ package kernel
 
 import (
 	"errors"
+	"fmt"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -475,6 +476,45 @@ func TestNewKernelNotInContext(t *testing.T) {
 	assert.Equal(t, ErrKernelNotInContext, errors.Unwrap(err), "错误链应该包含 ErrKernelNotInContext")
 }
 
+// TestWrapServiceErrors 测试 WrapServiceInitFailed 等函数同时保留内核错误、原始错误与服务名称
+func TestWrapServiceErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		wrap func(string, error) error
+		kind error
+	}{
+		{"init", WrapServiceInitFailed, ErrServiceInitFailed},
+		{"run", WrapServiceRunFailed, ErrServiceRunFailed},
+		{"close", WrapServiceCloseFailed, ErrServiceCloseFailed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			original := errors.New("boom")
+			err := tt.wrap("db", original)
+
+			assert.ErrorIs(t, err, tt.kind)
+			assert.ErrorIs(t, err, original)
+			assert.True(t, IsKernelError(err))
+			assert.Equal(t, "kernel db: "+tt.kind.Error()+": boom", err.Error())
+
+			var kerr *Error
+			require.ErrorAs(t, err, &kerr)
+			assert.Equal(t, "db", kerr.Op())
+
+			assert.NoError(t, tt.wrap("db", nil), "nil 错误不包装")
+		})
+	}
+}
+
+// TestIsCloseRetryable 测试包装后的可重试关闭错误仍然可以识别
+func TestIsCloseRetryable(t *testing.T) {
+	err := WrapServiceCloseFailed("db", fmt.Errorf("%w: %w", ErrCloseRetryable, errors.New("busy")))
+	assert.True(t, IsCloseRetryable(err))
+	assert.True(t, IsServiceCloseFailed(err))
+	assert.False(t, IsCloseRetryable(WrapServiceCloseFailed("db", errors.New("boom"))))
+	assert.False(t, IsCloseRetryable(nil))
+}
+
 // TestError_Chain 测试错误链的行为
 func TestError_Chain(t *testing.T) {
 	// 创建多层错误包装
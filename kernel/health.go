@@ -0,0 +1,11 @@
+package kernel
+
+import "context"
+
+// HealthChecker 描述一个可以主动检查自身健康状况的服务，例如 ping 数据库连接。
+// 返回 nil 表示健康，ctx 限制单次检查的时间，实现应当在 ctx 到期时尽快返回。
+// 应用的状态快照（例如 drugo.Drugo.SnapshotWithHealth）会执行这些检查并汇总结果。
+// CheckHealth 可能在任意 goroutine 中随时调用，实现需要并发安全。
+type HealthChecker interface {
+	CheckHealth(ctx context.Context) error
+}
@@ -0,0 +1,52 @@
+package kerneltest
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/qq1060656096/drugo/kernel"
+)
+
+// BootAll 按注册顺序启动 k 中的所有服务，任一服务启动失败时终止测试
+func BootAll(t testing.TB, k kernel.Kernel) {
+	t.Helper()
+	for _, svc := range k.Container().Services() {
+		if err := svc.Boot(t.Context()); err != nil {
+			t.Fatalf("kerneltest: boot service %q: %v", svc.Name(), err)
+		}
+	}
+}
+
+// CloseAll 按注册的逆序关闭 k 中的所有服务，任一服务关闭失败时标记测试失败并继续关闭其余服务
+func CloseAll(t testing.TB, k kernel.Kernel) {
+	t.Helper()
+	services := k.Container().Services()
+	for _, svc := range slices.Backward(services) {
+		if err := svc.Close(t.Context()); err != nil {
+			t.Errorf("kerneltest: close service %q: %v", svc.Name(), err)
+		}
+	}
+}
+
+// AssertClosedInReverseOrder 断言 mocks 都已关闭，且关闭顺序与传入顺序相反，
+// 即按注册顺序传入时，最后注册的服务最先关闭。
+// RunnerMock 通过其嵌入的 ServiceMock 传入。
+func AssertClosedInReverseOrder(t testing.TB, mocks ...*ServiceMock) bool {
+	t.Helper()
+	ok := true
+	for i, m := range mocks {
+		if !m.Closed() {
+			t.Errorf("kerneltest: service %q was not closed", m.Name())
+			ok = false
+			continue
+		}
+		if i == 0 || !mocks[i-1].Closed() {
+			continue
+		}
+		if prev := mocks[i-1]; prev.closeOrder() < m.closeOrder() {
+			t.Errorf("kerneltest: service %q closed before %q, want reverse registration order", prev.Name(), m.Name())
+			ok = false
+		}
+	}
+	return ok
+}
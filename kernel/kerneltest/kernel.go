@@ -0,0 +1,105 @@
+package kerneltest
+
+import (
+	"sync"
+
+	"github.com/qq1060656096/drugo/config"
+	"github.com/qq1060656096/drugo/kernel"
+	"github.com/qq1060656096/drugo/log"
+)
+
+var _ kernel.Kernel = (*KernelMock)(nil)
+
+// KernelMock 是用于测试的 kernel.Kernel 实现，零值即可使用。
+// 它嵌入 kernel.BaseKernel，服务保存在 BaseKernel 使用的真实容器中，
+// 因此注册顺序、覆盖绑定与 ErrServiceNotFound 等行为与框架一致。
+type KernelMock struct {
+	kernel.BaseKernel
+
+	RootDir       string          // Root 的返回值，为空时使用 BaseKernel 的默认值 "."
+	ConfigManager *config.Manager // Config 的返回值
+	LogManager    *log.Manager    // Logger 的返回值
+
+	once      sync.Once
+	container *container
+}
+
+// NewKernelMock 创建模拟内核，并按名称绑定给定的服务
+func NewKernelMock(services ...kernel.Service) *KernelMock {
+	k := &KernelMock{}
+	for _, svc := range services {
+		k.Container().Bind(svc.Name(), svc)
+	}
+	return k
+}
+
+// Container 返回服务容器，Get 会优先返回 SetGetError 注入的错误
+func (k *KernelMock) Container() kernel.Container[kernel.Service] {
+	return k.mockContainer()
+}
+
+// SetGetError 使容器获取 name 时返回 err，err 为 nil 时清除注入的错误
+func (k *KernelMock) SetGetError(name string, err error) {
+	c := k.mockContainer()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err == nil {
+		delete(c.getErrs, name)
+		return
+	}
+	c.getErrs[name] = err
+}
+
+// Root 返回 RootDir，为空时返回 "."
+func (k *KernelMock) Root() string {
+	if k.RootDir != "" {
+		return k.RootDir
+	}
+	return k.BaseKernel.Root()
+}
+
+// Config 返回 ConfigManager
+func (k *KernelMock) Config() *config.Manager {
+	return k.ConfigManager
+}
+
+// Logger 返回 LogManager
+func (k *KernelMock) Logger() *log.Manager {
+	return k.LogManager
+}
+
+func (k *KernelMock) mockContainer() *container {
+	k.once.Do(func() {
+		k.container = &container{
+			Container: k.BaseKernel.Container(),
+			getErrs:   make(map[string]error),
+		}
+	})
+	return k.container
+}
+
+// container 在真实容器之上支持注入获取错误
+type container struct {
+	kernel.Container[kernel.Service]
+
+	mu      sync.RWMutex
+	getErrs map[string]error
+}
+
+func (c *container) Get(name string) (kernel.Service, error) {
+	c.mu.RLock()
+	err, ok := c.getErrs[name]
+	c.mu.RUnlock()
+	if ok {
+		return nil, err
+	}
+	return c.Container.Get(name)
+}
+
+func (c *container) MustGet(name string) kernel.Service {
+	svc, err := c.Get(name)
+	if err != nil {
+		panic(err)
+	}
+	return svc
+}
@@ -0,0 +1,178 @@
+package kerneltest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/qq1060656096/drugo/kernel"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestServiceMock 测试默认行为、自定义行为与调用记录
+func TestServiceMock(t *testing.T) {
+	svc := NewServiceMock("db")
+	assert.Equal(t, "db", svc.Name())
+	assert.False(t, svc.Booted())
+	assert.False(t, svc.Closed())
+
+	ctx := context.WithValue(context.Background(), "k", "v")
+	require.NoError(t, svc.Boot(ctx))
+	require.NoError(t, svc.Close(ctx))
+	assert.True(t, svc.Booted())
+	assert.True(t, svc.Closed())
+	assert.Equal(t, 1, svc.BootCount())
+	assert.Equal(t, 1, svc.CloseCount())
+	assert.Equal(t, []context.Context{ctx}, svc.BootContexts())
+	assert.Equal(t, []context.Context{ctx}, svc.CloseContexts())
+
+	bootErr := errors.New("boot failed")
+	closeErr := errors.New("close failed")
+	failing := NewServiceMock("cache")
+	failing.NameFunc = func() string { return "renamed" }
+	failing.BootFunc = ReturnAfter(0, bootErr)
+	failing.CloseFunc = ReturnAfter(0, closeErr)
+
+	assert.Equal(t, "renamed", failing.Name())
+	assert.ErrorIs(t, failing.Boot(ctx), bootErr)
+	assert.False(t, failing.Booted(), "启动失败不应标记为已启动")
+	assert.Equal(t, 1, failing.BootCount())
+	assert.ErrorIs(t, failing.Close(ctx), closeErr)
+	assert.True(t, failing.Closed(), "关闭失败也记录为已调用")
+}
+
+// TestRunnerMock 测试运行器的内置行为与 Ready 通知
+func TestRunnerMock(t *testing.T) {
+	t.Run("默认立即返回", func(t *testing.T) {
+		r := NewRunnerMock("worker")
+		assert.False(t, r.Ran())
+		require.NoError(t, r.Run(context.Background()))
+		assert.True(t, r.Ran())
+		assert.Equal(t, 1, r.RunCount())
+		assert.Len(t, r.RunContexts(), 1)
+	})
+
+	t.Run("阻塞直到取消", func(t *testing.T) {
+		r := NewRunnerMock("server")
+		r.RunFunc = BlockUntilCancel
+
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan error, 1)
+		go func() { done <- r.Run(ctx) }()
+
+		select {
+		case <-r.Ready():
+		case <-time.After(time.Second):
+			t.Fatal("Run 应该通知 Ready")
+		}
+		select {
+		case <-done:
+			t.Fatal("取消之前 Run 不应返回")
+		case <-time.After(20 * time.Millisecond):
+		}
+
+		cancel()
+		select {
+		case err := <-done:
+			assert.NoError(t, err)
+		case <-time.After(time.Second):
+			t.Fatal("取消后 Run 应该返回")
+		}
+	})
+
+	t.Run("延迟后返回错误", func(t *testing.T) {
+		r := NewRunnerMock("failing")
+		r.RunFunc = ReturnAfter(10*time.Millisecond, assert.AnError)
+
+		start := time.Now()
+		assert.ErrorIs(t, r.Run(context.Background()), assert.AnError)
+		assert.GreaterOrEqual(t, time.Since(start), 10*time.Millisecond)
+	})
+
+	t.Run("零值可用", func(t *testing.T) {
+		r := &RunnerMock{ServiceMock: &ServiceMock{}}
+		require.NoError(t, r.Run(context.Background()))
+		<-r.Ready()
+	})
+}
+
+// TestKernelMock 测试模拟内核使用真实容器并支持注入获取错误
+func TestKernelMock(t *testing.T) {
+	a := NewServiceMock("a")
+	b := NewRunnerMock("b")
+	k := NewKernelMock(a, b)
+
+	assert.Equal(t, ".", k.Root())
+	k.RootDir = "/mock/root"
+	assert.Equal(t, "/mock/root", k.Root())
+	assert.Nil(t, k.Config())
+	assert.Nil(t, k.Logger())
+
+	assert.Equal(t, []string{"a", "b"}, k.Container().Names())
+	got, err := kernel.GetService[*RunnerMock](k, "b")
+	require.NoError(t, err)
+	assert.Same(t, b, got)
+
+	_, err = k.Container().Get("missing")
+	assert.True(t, kernel.IsServiceNotFound(err))
+
+	injected := errors.New("container broken")
+	k.SetGetError("a", injected)
+	_, err = kernel.GetService[*ServiceMock](k, "a")
+	assert.Equal(t, injected, err)
+	assert.Panics(t, func() { k.Container().MustGet("a") })
+
+	k.SetGetError("a", nil)
+	assert.Same(t, a, k.Container().MustGet("a"))
+
+	// 零值同样可用
+	var zero KernelMock
+	zero.Container().Bind("c", NewServiceMock("c"))
+	assert.Equal(t, []string{"c"}, zero.Container().Names())
+}
+
+// TestBootAllCloseAll 测试按注册顺序启动、按逆序关闭
+func TestBootAllCloseAll(t *testing.T) {
+	a, b, c := NewServiceMock("a"), NewServiceMock("b"), NewServiceMock("c")
+	k := NewKernelMock(a, b, c)
+
+	BootAll(t, k)
+	for _, m := range []*ServiceMock{a, b, c} {
+		assert.True(t, m.Booted(), m.Name())
+	}
+
+	CloseAll(t, k)
+	assert.True(t, AssertClosedInReverseOrder(t, a, b, c))
+}
+
+// TestAssertClosedInReverseOrder_Failures 测试顺序错误与未关闭时断言失败
+func TestAssertClosedInReverseOrder_Failures(t *testing.T) {
+	a, b := NewServiceMock("a"), NewServiceMock("b")
+	require.NoError(t, a.Close(context.Background()))
+	require.NoError(t, b.Close(context.Background()))
+
+	rec := &recordingTB{TB: t}
+	assert.False(t, AssertClosedInReverseOrder(rec, a, b))
+	require.Len(t, rec.errors, 1)
+	assert.Contains(t, rec.errors[0], `service "a" closed before "b"`)
+
+	rec = &recordingTB{TB: t}
+	assert.False(t, AssertClosedInReverseOrder(rec, NewServiceMock("c")))
+	require.Len(t, rec.errors, 1)
+	assert.Contains(t, rec.errors[0], `service "c" was not closed`)
+}
+
+// recordingTB 记录断言失败而不使外层测试失败
+type recordingTB struct {
+	testing.TB
+	errors []string
+}
+
+func (r *recordingTB) Helper() {}
+
+func (r *recordingTB) Errorf(format string, args ...any) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
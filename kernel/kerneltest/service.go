@@ -0,0 +1,217 @@
+// Package kerneltest 提供 kernel 的测试替身，供 drugo 自身以及下游项目的测试共同使用：
+//   - ServiceMock：可配置 Name/Boot/Close 行为的服务，记录调用次数、收到的上下文与调用顺序
+//   - RunnerMock：在 ServiceMock 之上实现 kernel.Runner，内置阻塞至取消、延迟后返回等行为
+//   - KernelMock：嵌入 kernel.BaseKernel，使用真实的服务容器，可注入容器的获取错误
+//   - BootAll、CloseAll、AssertClosedInReverseOrder 等断言辅助函数
+//
+// 稳定性约定：本包是公开 API，遵循与 kernel 相同的兼容性承诺。
+// 已导出的类型、函数与方法不会删除或改变语义，只会新增；
+// 未配置行为时的默认值（Boot/Close/Run 立即返回 nil）保持不变。
+package kerneltest
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/qq1060656096/drugo/kernel"
+)
+
+var (
+	_ kernel.Service = (*ServiceMock)(nil)
+	_ kernel.Runner  = (*RunnerMock)(nil)
+)
+
+// seq 是所有模拟服务共享的调用序号，用于断言跨服务的调用顺序
+var seq atomic.Uint64
+
+// ServiceMock 是可配置行为的 kernel.Service 实现，并发安全。
+// 行为字段需要在服务交给被测代码之前设置；为 nil 时对应方法立即返回 nil。
+type ServiceMock struct {
+	NameFunc  func() string                   // 覆盖 Name 的返回值
+	BootFunc  func(ctx context.Context) error // Boot 的行为
+	CloseFunc func(ctx context.Context) error // Close 的行为
+
+	name string
+
+	mu        sync.Mutex
+	booted    bool
+	bootCtxs  []context.Context
+	closeCtxs []context.Context
+	bootSeq   uint64
+	closeSeq  uint64
+}
+
+// NewServiceMock 创建名称为 name 的模拟服务
+func NewServiceMock(name string) *ServiceMock {
+	return &ServiceMock{name: name}
+}
+
+// Name 实现 kernel.Service
+func (m *ServiceMock) Name() string {
+	if m.NameFunc != nil {
+		return m.NameFunc()
+	}
+	return m.name
+}
+
+// Boot 实现 kernel.Service，记录调用后执行 BootFunc
+func (m *ServiceMock) Boot(ctx context.Context) error {
+	m.mu.Lock()
+	m.bootCtxs = append(m.bootCtxs, ctx)
+	m.bootSeq = seq.Add(1)
+	m.mu.Unlock()
+
+	var err error
+	if m.BootFunc != nil {
+		err = m.BootFunc(ctx)
+	}
+	if err == nil {
+		m.mu.Lock()
+		m.booted = true
+		m.mu.Unlock()
+	}
+	return err
+}
+
+// Close 实现 kernel.Service，记录调用后执行 CloseFunc
+func (m *ServiceMock) Close(ctx context.Context) error {
+	m.mu.Lock()
+	m.closeCtxs = append(m.closeCtxs, ctx)
+	m.closeSeq = seq.Add(1)
+	m.mu.Unlock()
+
+	if m.CloseFunc != nil {
+		return m.CloseFunc(ctx)
+	}
+	return nil
+}
+
+// Booted 返回是否至少有一次 Boot 成功
+func (m *ServiceMock) Booted() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.booted
+}
+
+// Closed 返回 Close 是否被调用过，无论是否返回错误
+func (m *ServiceMock) Closed() bool {
+	return m.CloseCount() > 0
+}
+
+// BootCount 返回 Boot 被调用的次数
+func (m *ServiceMock) BootCount() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.bootCtxs)
+}
+
+// CloseCount 返回 Close 被调用的次数
+func (m *ServiceMock) CloseCount() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.closeCtxs)
+}
+
+// BootContexts 返回每次 Boot 收到的上下文
+func (m *ServiceMock) BootContexts() []context.Context {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]context.Context(nil), m.bootCtxs...)
+}
+
+// CloseContexts 返回每次 Close 收到的上下文
+func (m *ServiceMock) CloseContexts() []context.Context {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]context.Context(nil), m.closeCtxs...)
+}
+
+// closeOrder 返回最近一次 Close 的全局序号，未调用时为 0
+func (m *ServiceMock) closeOrder() uint64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.closeSeq
+}
+
+// RunnerMock 是可配置行为的 kernel.Runner 实现。
+// RunFunc 为 nil 时 Run 立即返回 nil；常用行为见 BlockUntilCancel 与 ReturnAfter。
+type RunnerMock struct {
+	*ServiceMock
+	RunFunc func(ctx context.Context) error // Run 的行为
+
+	mu        sync.Mutex
+	runCtxs   []context.Context
+	ready     chan struct{}
+	readyOnce sync.Once
+}
+
+// NewRunnerMock 创建名称为 name 的模拟运行器
+func NewRunnerMock(name string) *RunnerMock {
+	return &RunnerMock{ServiceMock: NewServiceMock(name)}
+}
+
+// Run 实现 kernel.Runner，记录调用并通知 Ready 后执行 RunFunc
+func (m *RunnerMock) Run(ctx context.Context) error {
+	m.mu.Lock()
+	m.runCtxs = append(m.runCtxs, ctx)
+	m.mu.Unlock()
+	m.readyOnce.Do(func() { close(m.readyChan()) })
+
+	if m.RunFunc != nil {
+		return m.RunFunc(ctx)
+	}
+	return nil
+}
+
+// Ready 返回在 Run 首次被调用时关闭的通道，用于等待运行器启动
+func (m *RunnerMock) Ready() <-chan struct{} {
+	return m.readyChan()
+}
+
+// readyChan 延迟创建 ready 通道，使零值的 RunnerMock 也可以使用
+func (m *RunnerMock) readyChan() chan struct{} {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.ready == nil {
+		m.ready = make(chan struct{})
+	}
+	return m.ready
+}
+
+// Ran 返回 Run 是否被调用过
+func (m *RunnerMock) Ran() bool {
+	return m.RunCount() > 0
+}
+
+// RunCount 返回 Run 被调用的次数
+func (m *RunnerMock) RunCount() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.runCtxs)
+}
+
+// RunContexts 返回每次 Run 收到的上下文
+func (m *RunnerMock) RunContexts() []context.Context {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]context.Context(nil), m.runCtxs...)
+}
+
+// BlockUntilCancel 阻塞直到 ctx 取消后返回 nil，模拟正常退出的长期运行服务
+func BlockUntilCancel(ctx context.Context) error {
+	<-ctx.Done()
+	return nil
+}
+
+// ReturnAfter 返回等待 d 之后返回 err 的行为，可用于 BootFunc、CloseFunc 与 RunFunc。
+// 等待期间不响应 ctx 取消，用于模拟忽略取消的慢服务。
+func ReturnAfter(d time.Duration, err error) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		if d > 0 {
+			time.Sleep(d)
+		}
+		return err
+	}
+}
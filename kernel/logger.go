@@ -0,0 +1,31 @@
+package kernel
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+)
+
+// LoggerAware 描述一个接收框架注入 logger 的服务。
+// 框架在 Boot 之前以服务名称为业务名称从日志管理器获取 logger 并调用 SetLogger，
+// 服务无需再通过 k.Logger().MustGet("<名称>") 硬编码自己的业务名称。
+type LoggerAware interface {
+	SetLogger(l *zap.Logger)
+}
+
+type serviceLoggerCtxKey struct{}
+
+// WithServiceLogger 返回携带服务 logger 的上下文。
+// 框架在调用每个服务的 Boot/Run/Drain/Close 时注入该服务自己的 logger。
+func WithServiceLogger(ctx context.Context, l *zap.Logger) context.Context {
+	return context.WithValue(ctx, serviceLoggerCtxKey{}, l)
+}
+
+// ServiceLoggerFromContext 返回框架注入到上下文中的服务 logger。
+// 上下文中没有 logger 时返回不输出任何内容的 logger，调用方无需判空。
+func ServiceLoggerFromContext(ctx context.Context) *zap.Logger {
+	if l, ok := ctx.Value(serviceLoggerCtxKey{}).(*zap.Logger); ok && l != nil {
+		return l
+	}
+	return zap.NewNop()
+}
@@ -0,0 +1,28 @@
+package kernel
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+// TestServiceLoggerFromContext 测试服务 logger 的注入与缺省回退
+func TestServiceLoggerFromContext(t *testing.T) {
+	// 未注入时返回 Nop logger，调用方无需判空
+	l := ServiceLoggerFromContext(context.Background())
+	assert.NotNil(t, l)
+	assert.NotPanics(t, func() { l.Info("discarded") })
+
+	// nil logger 同样回退
+	assert.NotNil(t, ServiceLoggerFromContext(WithServiceLogger(context.Background(), nil)))
+
+	want := zap.NewExample()
+	ctx := WithServiceLogger(context.Background(), want)
+	assert.Same(t, want, ServiceLoggerFromContext(ctx))
+
+	// 内层注入覆盖外层
+	inner := zap.NewExample()
+	assert.Same(t, inner, ServiceLoggerFromContext(WithServiceLogger(ctx, inner)))
+}
@@ -0,0 +1,90 @@
+package kernel
+
+import (
+	"context"
+	"sort"
+
+	"github.com/qq1060656096/drugo/log"
+	"go.uber.org/zap"
+)
+
+// 常用的请求元数据键，各服务与跨服务的辅助函数应使用这些键读写元数据
+const (
+	MetaTenant    = "tenant"     // 租户 ID
+	MetaUser      = "user"       // 用户 ID
+	MetaRequestID = "request_id" // 请求 ID
+	MetaLocale    = "locale"     // 语言区域，例如 zh-CN
+	MetaClientIP  = "client_ip"  // 客户端 IP
+)
+
+type metaCtxKey struct{}
+
+// metaNode 是不可变的元数据链表节点，新增键只创建一个指向父节点的新节点，
+// 不复制已有的元数据，也不会影响父上下文看到的内容。
+type metaNode struct {
+	key    string
+	value  string
+	parent *metaNode
+}
+
+// WithMeta 返回携带元数据 key=value 的上下文，同名键会覆盖父上下文中的值。
+// 父上下文看到的元数据保持不变，可以安全地在多个 goroutine 之间共享。
+func WithMeta(ctx context.Context, key, value string) context.Context {
+	parent, _ := ctx.Value(metaCtxKey{}).(*metaNode)
+	return context.WithValue(ctx, metaCtxKey{}, &metaNode{key: key, value: value, parent: parent})
+}
+
+// Meta 返回上下文中 key 对应的元数据，不存在时 ok 为 false。
+func Meta(ctx context.Context, key string) (string, bool) {
+	for n, _ := ctx.Value(metaCtxKey{}).(*metaNode); n != nil; n = n.parent {
+		if n.key == key {
+			return n.value, true
+		}
+	}
+	return "", false
+}
+
+// AllMeta 返回上下文中所有元数据的副本，没有元数据时返回 nil。
+func AllMeta(ctx context.Context) map[string]string {
+	n, _ := ctx.Value(metaCtxKey{}).(*metaNode)
+	if n == nil {
+		return nil
+	}
+	all := make(map[string]string)
+	for ; n != nil; n = n.parent {
+		// 离当前上下文最近的值优先
+		if _, ok := all[n.key]; !ok {
+			all[n.key] = n.value
+		}
+	}
+	return all
+}
+
+// MetaFields 将上下文中的所有元数据按键排序转换为 zap 字段，没有元数据时返回 nil。
+func MetaFields(ctx context.Context) []zap.Field {
+	all := AllMeta(ctx)
+	if len(all) == 0 {
+		return nil
+	}
+	keys := make([]string, 0, len(all))
+	for k := range all {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	fields := make([]zap.Field, 0, len(keys))
+	for _, k := range keys {
+		fields = append(fields, zap.String(k, all[k]))
+	}
+	return fields
+}
+
+// LoggerFromContext 返回框架注入的服务 logger（见 ServiceLoggerFromContext），
+// 上下文中存在元数据时附带所有元数据字段，例如 request_id、tenant；
+// 上下文通过 log.WithMinLevel 设置了请求级别时按该级别放宽输出。
+func LoggerFromContext(ctx context.Context) *zap.Logger {
+	l := log.Escalate(ctx, ServiceLoggerFromContext(ctx))
+	if fields := MetaFields(ctx); len(fields) > 0 {
+		return l.With(fields...)
+	}
+	return l
+}
@@ -0,0 +1,119 @@
+package kernel_test
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/qq1060656096/drugo/kernel"
+	"github.com/qq1060656096/drugo/kernel/kerneltest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// TestMeta 测试元数据的读取、覆盖与缺省
+func TestMeta(t *testing.T) {
+	ctx := context.Background()
+	_, ok := kernel.Meta(ctx, kernel.MetaTenant)
+	assert.False(t, ok)
+	assert.Nil(t, kernel.AllMeta(ctx))
+	assert.Nil(t, kernel.MetaFields(ctx))
+
+	ctx = kernel.WithMeta(ctx, kernel.MetaTenant, "t1")
+	ctx = kernel.WithMeta(ctx, kernel.MetaUser, "u1")
+	ctx = kernel.WithMeta(ctx, kernel.MetaTenant, "t2")
+
+	v, ok := kernel.Meta(ctx, kernel.MetaTenant)
+	assert.True(t, ok)
+	assert.Equal(t, "t2", v)
+	assert.Equal(t, map[string]string{kernel.MetaTenant: "t2", kernel.MetaUser: "u1"}, kernel.AllMeta(ctx))
+
+	// 修改 AllMeta 的返回值不影响上下文
+	kernel.AllMeta(ctx)[kernel.MetaUser] = "changed"
+	v, _ = kernel.Meta(ctx, kernel.MetaUser)
+	assert.Equal(t, "u1", v)
+}
+
+// TestMeta_Immutable 测试子上下文新增元数据不影响父上下文，并发派生互不干扰
+func TestMeta_Immutable(t *testing.T) {
+	parent := kernel.WithMeta(context.Background(), kernel.MetaRequestID, "req")
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			id := strconv.Itoa(i)
+			child := kernel.WithMeta(kernel.WithMeta(parent, kernel.MetaUser, id), kernel.MetaRequestID, "req-"+id)
+
+			assert.Equal(t, map[string]string{kernel.MetaRequestID: "req-" + id, kernel.MetaUser: id}, kernel.AllMeta(child))
+			assert.Equal(t, map[string]string{kernel.MetaRequestID: "req"}, kernel.AllMeta(parent))
+		}(i)
+	}
+	wg.Wait()
+
+	_, ok := kernel.Meta(parent, kernel.MetaUser)
+	assert.False(t, ok)
+}
+
+// TestMeta_WithContext 测试元数据与 Kernel 上下文互不覆盖
+func TestMeta_WithContext(t *testing.T) {
+	k := kerneltest.NewKernelMock()
+
+	ctx := kernel.WithContext(kernel.WithMeta(context.Background(), kernel.MetaLocale, "zh-CN"), k)
+	ctx = kernel.WithMeta(ctx, kernel.MetaTenant, "t1")
+
+	got, ok := kernel.FromContext(ctx)
+	require.True(t, ok)
+	assert.Same(t, k, got)
+	assert.Equal(t, map[string]string{kernel.MetaLocale: "zh-CN", kernel.MetaTenant: "t1"}, kernel.AllMeta(ctx))
+}
+
+// TestLoggerFromContext 测试日志附带所有元数据字段
+func TestLoggerFromContext(t *testing.T) {
+	core, logs := observer.New(zap.InfoLevel)
+	ctx := kernel.WithServiceLogger(context.Background(), zap.New(core))
+
+	kernel.LoggerFromContext(ctx).Info("no meta")
+	ctx = kernel.WithMeta(kernel.WithMeta(ctx, kernel.MetaRequestID, "req"), kernel.MetaTenant, "t1")
+	kernel.LoggerFromContext(ctx).Info("with meta")
+
+	entries := logs.All()
+	require.Len(t, entries, 2)
+	assert.Empty(t, entries[0].Context)
+	assert.Equal(t, map[string]any{kernel.MetaRequestID: "req", kernel.MetaTenant: "t1"}, entries[1].ContextMap())
+
+	assert.NotNil(t, kernel.LoggerFromContext(context.Background()))
+}
+
+// BenchmarkWithMeta 测试在已有大量元数据时新增一个键的开销（不随已有键数量增长）
+func BenchmarkWithMeta(b *testing.B) {
+	for _, n := range []int{1, 100} {
+		ctx := context.Background()
+		for i := 0; i < n; i++ {
+			ctx = kernel.WithMeta(ctx, "key"+strconv.Itoa(i), "value")
+		}
+		b.Run(strconv.Itoa(n), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				_ = kernel.WithMeta(ctx, kernel.MetaTenant, "t1")
+			}
+		})
+	}
+}
+
+// BenchmarkMeta 测试读取常用键的开销
+func BenchmarkMeta(b *testing.B) {
+	ctx := kernel.WithMeta(context.Background(), kernel.MetaRequestID, "req")
+	ctx = kernel.WithMeta(ctx, kernel.MetaTenant, "t1")
+	ctx = kernel.WithMeta(ctx, kernel.MetaUser, "u1")
+	ctx = kernel.WithContext(ctx, kerneltest.NewKernelMock())
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_, _ = kernel.Meta(ctx, kernel.MetaRequestID)
+	}
+}
@@ -0,0 +1,9 @@
+package kernel
+
+// MetricsReporter 描述一个报告运行指标的服务，返回指标名称到当前值的映射，
+// 例如 {"in_flight": 3, "rejected": 12}。应用的状态快照（例如 drugo.Drugo.Status）会包含这些指标，
+// 健康检查或诊断接口可以统一展示，而不必对具体的服务类型做类型断言。
+// Metrics 可能在任意 goroutine 中随时调用，实现需要并发安全。
+type MetricsReporter interface {
+	Metrics() map[string]int64
+}
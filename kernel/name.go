@@ -0,0 +1,55 @@
+package kernel
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// MaxServiceNameLength 是服务名称的最大长度（字节）
+const MaxServiceNameLength = 64
+
+// reservedNames 是框架保留的服务名称，分别被框架日志、应用日志、配置与日志服务占用。
+// 比较时不区分大小写，因此 "Drugo"（gin 上下文中的 drugo.Name）同样被保留
+var reservedNames = map[string]struct{}{
+	"app":    {},
+	"drugo":  {},
+	"config": {},
+	"log":    {},
+}
+
+// ReservedNames 按字母顺序返回框架保留的服务名称
+func ReservedNames() []string {
+	names := make([]string, 0, len(reservedNames))
+	for name := range reservedNames {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// IsReservedName 判断 name 是否是框架保留的服务名称（不区分大小写）
+func IsReservedName(name string) bool {
+	_, ok := reservedNames[strings.ToLower(name)]
+	return ok
+}
+
+// ValidateServiceName 检查服务名称的格式：不能为空、不能包含路径分隔符、
+// 长度不能超过 MaxServiceNameLength。失败时返回包装了 ErrInvalidServiceName 的错误。
+// 它不检查保留名称，保留名称见 IsReservedName
+func ValidateServiceName(name string) error {
+	switch {
+	case name == "":
+		return fmt.Errorf("%w: empty name", ErrInvalidServiceName)
+	case strings.ContainsAny(name, `/\`):
+		return fmt.Errorf("%w: %q contains path separator", ErrInvalidServiceName, name)
+	case len(name) > MaxServiceNameLength:
+		return fmt.Errorf("%w: %q exceeds %d bytes", ErrInvalidServiceName, name, MaxServiceNameLength)
+	}
+	return nil
+}
+
+// NewReservedServiceName 创建一个包装了 ErrReservedServiceName 的错误，错误信息列出所有保留名称
+func NewReservedServiceName(name string) error {
+	return fmt.Errorf("%w: %q (reserved names: %s)", ErrReservedServiceName, name, strings.Join(ReservedNames(), ", "))
+}
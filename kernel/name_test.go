@@ -0,0 +1,43 @@
+package kernel
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestValidateServiceName 测试服务名称的格式校验
+func TestValidateServiceName(t *testing.T) {
+	valid := []string{"db", "user-api", "cache_v2", "app", strings.Repeat("a", MaxServiceNameLength)}
+	for _, name := range valid {
+		assert.NoError(t, ValidateServiceName(name), name)
+	}
+
+	invalid := []string{"", "a/b", `a\b`, strings.Repeat("a", MaxServiceNameLength+1)}
+	for _, name := range invalid {
+		err := ValidateServiceName(name)
+		assert.True(t, IsInvalidServiceName(err), name)
+		assert.True(t, IsKernelError(err), name)
+	}
+}
+
+// TestReservedNames 测试框架保留名称的判断与错误信息
+func TestReservedNames(t *testing.T) {
+	assert.Equal(t, []string{"app", "config", "drugo", "log"}, ReservedNames())
+
+	for _, name := range []string{"app", "drugo", "Drugo", "CONFIG", "log"} {
+		assert.True(t, IsReservedName(name), name)
+	}
+	assert.False(t, IsReservedName("db"))
+
+	// 修改返回的切片不影响保留名称
+	names := ReservedNames()
+	names[0] = "db"
+	assert.False(t, IsReservedName("db"))
+
+	err := NewReservedServiceName("app")
+	assert.True(t, IsReservedServiceName(err))
+	assert.True(t, IsKernelError(err))
+	assert.Contains(t, err.Error(), "app, config, drugo, log")
+}
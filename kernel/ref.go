@@ -0,0 +1,137 @@
+package kernel
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// BootWaiter 描述一个可以等待指定服务完成 Boot 的 Kernel（drugo.Drugo 实现了该接口）。
+// WaitBooted 在服务 Boot 成功后返回 nil，服务 Boot 失败（例如可选服务降级）时返回包装了
+// ErrServiceInitFailed 的错误，ctx 取消时返回 ctx 的错误。
+type BootWaiter interface {
+	WaitBooted(ctx context.Context, name string) error
+}
+
+// refOptions 是 Ref 的配置
+type refOptions struct {
+	wait        bool
+	waitTimeout time.Duration
+}
+
+// RefOption 配置 Ref 的解析行为
+type RefOption func(*refOptions)
+
+// WithWaitBooted 使 Ref 在解析前通过 BootWaiter 等待目标服务完成 Boot，而不是立即查找。
+// timeout 限制单次解析的最长等待时间，小于等于 0 表示只受 GetContext 的 ctx 限制；
+// Kernel 没有实现 BootWaiter 时不等待，直接查找。
+//
+// 注意：Boot 按注册顺序串行执行，在 Boot 中解析等待后注册服务的 Ref 会一直等待到超时。
+func WithWaitBooted(timeout time.Duration) RefOption {
+	return func(o *refOptions) {
+		o.wait = true
+		o.waitTimeout = timeout
+	}
+}
+
+// Ref 是对服务的延迟引用，用于打破服务之间在 Boot 阶段的循环依赖。
+// 创建 Ref 时不做任何查找，首次调用 Get/MustGet 时才通过 GetService 解析，
+// 解析成功后缓存结果；解析失败不会缓存，之后的调用会重新解析。Ref 并发安全。
+//
+// 示例：服务在 Boot 中持有对方的引用，在 Run 或处理请求时再解析
+//
+//	func (s *HTTPService) Boot(ctx context.Context) error {
+//	    s.auth = kernel.NewRef[*AuthService](kernel.MustFromContext(ctx), "auth")
+//	    return nil
+//	}
+//
+//	func (s *HTTPService) Run(ctx context.Context) error {
+//	    auth, err := s.auth.GetContext(ctx)
+//	    ...
+//	}
+type Ref[T any] struct {
+	k    Kernel
+	name string
+	opts refOptions
+
+	mu       sync.Mutex
+	resolved bool
+	value    T
+}
+
+// NewRef 创建 k 中名称为 name、类型为 T 的服务的延迟引用
+func NewRef[T any](k Kernel, name string, opts ...RefOption) *Ref[T] {
+	r := &Ref[T]{k: k, name: name}
+	for _, opt := range opts {
+		opt(&r.opts)
+	}
+	return r
+}
+
+// RefFromContext 是 NewRef 的上下文版本，使用 ctx 中携带的 Kernel。
+// ctx 中没有 Kernel 时仍然返回 Ref，解析时返回 ErrKernelNotInContext。
+func RefFromContext[T any](ctx context.Context, name string, opts ...RefOption) *Ref[T] {
+	k, _ := FromContext(ctx)
+	return NewRef[T](k, name, opts...)
+}
+
+// Name 返回引用的服务名称
+func (r *Ref[T]) Name() string {
+	return r.name
+}
+
+// Get 解析并返回引用的服务，等价于 GetContext(context.Background())。
+// 错误与 GetService 相同：服务未注册时为 ErrServiceNotFound，类型不匹配时为 ErrServiceType。
+func (r *Ref[T]) Get() (T, error) {
+	return r.GetContext(context.Background())
+}
+
+// GetContext 解析并返回引用的服务，ctx 用于限制 WithWaitBooted 的等待
+func (r *Ref[T]) GetContext(ctx context.Context) (T, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.resolved {
+		return r.value, nil
+	}
+	var zero T
+	if r.k == nil {
+		return zero, NewKernelNotInContext()
+	}
+	if err := r.waitBooted(ctx); err != nil {
+		return zero, err
+	}
+	svc, err := GetService[T](r.k, r.name)
+	if err != nil {
+		return zero, err
+	}
+	r.value, r.resolved = svc, true
+	return svc, nil
+}
+
+// MustGet 解析并返回引用的服务，失败时 panic
+func (r *Ref[T]) MustGet() T {
+	svc, err := r.Get()
+	if err != nil {
+		panic(err)
+	}
+	return svc
+}
+
+// waitBooted 在设置了 WithWaitBooted 且 Kernel 实现了 BootWaiter 时等待服务完成 Boot
+func (r *Ref[T]) waitBooted(ctx context.Context) error {
+	w, ok := r.k.(BootWaiter)
+	if !r.opts.wait || !ok {
+		return nil
+	}
+	if r.opts.waitTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, r.opts.waitTimeout)
+		defer cancel()
+	}
+	if err := w.WaitBooted(ctx, r.name); err != nil {
+		return fmt.Errorf("kernel: wait for service %s to boot: %w", r.name, err)
+	}
+	return nil
+}
@@ -0,0 +1,159 @@
+package kernel_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/qq1060656096/drugo/kernel"
+	"github.com/qq1060656096/drugo/kernel/kerneltest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// waitingKernel 是实现了 kernel.BootWaiter 的模拟内核，close(booted) 之前 WaitBooted 一直阻塞
+type waitingKernel struct {
+	kerneltest.KernelMock
+	booted chan struct{}
+	err    error
+}
+
+func (k *waitingKernel) WaitBooted(ctx context.Context, name string) error {
+	select {
+	case <-k.booted:
+		return k.err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// TestRef_Lazy 测试创建 Ref 时不做查找，服务在创建之后注册也能解析
+func TestRef_Lazy(t *testing.T) {
+	k := kerneltest.NewKernelMock()
+	ref := kernel.NewRef[*kerneltest.ServiceMock](k, "db")
+	assert.Equal(t, "db", ref.Name())
+
+	db := kerneltest.NewServiceMock("db")
+	k.Container().Bind("db", db)
+
+	svc, err := ref.Get()
+	require.NoError(t, err)
+	assert.Same(t, db, svc)
+	assert.Same(t, db, ref.MustGet())
+}
+
+// TestRef_Memoized 测试解析成功后缓存结果，之后不再查找
+func TestRef_Memoized(t *testing.T) {
+	db := kerneltest.NewServiceMock("db")
+	k := kerneltest.NewKernelMock(db)
+	ref := kernel.NewRef[*kerneltest.ServiceMock](k, "db")
+
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			svc, err := ref.Get()
+			assert.NoError(t, err)
+			assert.Same(t, db, svc)
+		}()
+	}
+	wg.Wait()
+
+	k.SetGetError("db", errors.New("lookup must not happen again"))
+	svc, err := ref.Get()
+	require.NoError(t, err)
+	assert.Same(t, db, svc)
+}
+
+// TestRef_Errors 测试解析失败时返回标准的 kernel 错误，且失败不会被缓存
+func TestRef_Errors(t *testing.T) {
+	t.Run("服务不存在", func(t *testing.T) {
+		k := kerneltest.NewKernelMock()
+		ref := kernel.NewRef[*kerneltest.ServiceMock](k, "db")
+
+		_, err := ref.Get()
+		assert.True(t, kernel.IsServiceNotFound(err))
+		assert.Panics(t, func() { ref.MustGet() })
+
+		k.Container().Bind("db", kerneltest.NewServiceMock("db"))
+		_, err = ref.Get()
+		assert.NoError(t, err, "failures are not memoized")
+	})
+
+	t.Run("类型不匹配", func(t *testing.T) {
+		k := kerneltest.NewKernelMock(kerneltest.NewServiceMock("db"))
+		_, err := kernel.NewRef[*kerneltest.RunnerMock](k, "db").Get()
+		assert.True(t, kernel.IsServiceType(err))
+	})
+
+	t.Run("上下文中没有 Kernel", func(t *testing.T) {
+		_, err := kernel.RefFromContext[*kerneltest.ServiceMock](context.Background(), "db").Get()
+		assert.ErrorIs(t, err, kernel.ErrKernelNotInContext)
+	})
+}
+
+// TestRefFromContext 测试使用上下文中的 Kernel 创建 Ref
+func TestRefFromContext(t *testing.T) {
+	db := kerneltest.NewServiceMock("db")
+	ctx := kernel.WithContext(context.Background(), kerneltest.NewKernelMock(db))
+
+	svc, err := kernel.RefFromContext[*kerneltest.ServiceMock](ctx, "db").Get()
+	require.NoError(t, err)
+	assert.Same(t, db, svc)
+}
+
+// TestRef_WaitBooted 测试 WithWaitBooted 在解析前等待 BootWaiter
+func TestRef_WaitBooted(t *testing.T) {
+	t.Run("等待完成后解析", func(t *testing.T) {
+		db := kerneltest.NewServiceMock("db")
+		k := &waitingKernel{booted: make(chan struct{})}
+		k.Container().Bind("db", db)
+		ref := kernel.NewRef[*kerneltest.ServiceMock](k, "db", kernel.WithWaitBooted(time.Second))
+
+		resolved := make(chan *kerneltest.ServiceMock, 1)
+		go func() { resolved <- ref.MustGet() }()
+		select {
+		case <-resolved:
+			t.Fatal("ref resolved before the service was booted")
+		case <-time.After(30 * time.Millisecond):
+		}
+		close(k.booted)
+		select {
+		case svc := <-resolved:
+			assert.Same(t, db, svc)
+		case <-time.After(time.Second):
+			t.Fatal("ref not resolved after the service was booted")
+		}
+	})
+
+	t.Run("超时", func(t *testing.T) {
+		k := &waitingKernel{booted: make(chan struct{})}
+		k.Container().Bind("db", kerneltest.NewServiceMock("db"))
+		_, err := kernel.NewRef[*kerneltest.ServiceMock](k, "db", kernel.WithWaitBooted(20*time.Millisecond)).Get()
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+
+	t.Run("上下文取消", func(t *testing.T) {
+		k := &waitingKernel{booted: make(chan struct{})}
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		_, err := kernel.NewRef[*kerneltest.ServiceMock](k, "db", kernel.WithWaitBooted(0)).GetContext(ctx)
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+
+	t.Run("Boot 失败", func(t *testing.T) {
+		k := &waitingKernel{booted: make(chan struct{}), err: kernel.NewServiceInitFailed("db")}
+		close(k.booted)
+		_, err := kernel.NewRef[*kerneltest.ServiceMock](k, "db", kernel.WithWaitBooted(time.Second)).Get()
+		assert.True(t, kernel.IsServiceInitFailed(err))
+	})
+
+	t.Run("Kernel 未实现 BootWaiter 时直接查找", func(t *testing.T) {
+		k := kerneltest.NewKernelMock()
+		_, err := kernel.NewRef[*kerneltest.ServiceMock](k, "db", kernel.WithWaitBooted(time.Second)).Get()
+		assert.True(t, kernel.IsServiceNotFound(err))
+	})
+}
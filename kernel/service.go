@@ -3,6 +3,8 @@ package kernel
 import (
 	"context"
 	"fmt"
+
+	"github.com/spf13/viper"
 )
 
 // Booter 定义了具有初始化能力的组件。
@@ -32,6 +34,43 @@ type Runner interface {
 	Run(ctx context.Context) error
 }
 
+// Drainer 描述一个支持优雅排空的服务（例如 HTTP Server）。
+// 停机时框架会先并发调用所有服务的 Drain，再按逆序调用 Close：
+// Drain 应停止接收新请求并等待进行中的请求完成，ctx 到期时应尽快返回。
+type Drainer interface {
+	Drain(ctx context.Context) error
+}
+
+// Optional 描述一个可选服务。
+// 当 Optional 返回 true 时，该服务 Boot 失败只会被记录为降级，而不会中止整个应用的启动。
+type Optional interface {
+	Optional() bool
+}
+
+// Dependent 描述一个依赖其他服务的服务，返回被依赖的服务名称。
+// Boot 时框架保证被依赖的服务先于依赖方初始化，未声明依赖的服务按注册顺序初始化；
+// Boot 期间动态注册的服务在下一轮初始化，只能依赖之前轮次或同一轮注册的服务。
+// 停机时框架保证依赖方在被依赖的服务之前关闭（包括 Boot 期间动态注册的服务），
+// 未声明依赖的服务按注册顺序的逆序关闭。依赖的服务未注册时忽略该项。
+// CapabilityRequirer 声明的服务同样视为依赖。
+type Dependent interface {
+	DependsOn() []string
+}
+
+// Configurable 描述一个在 Boot 之前接收配置注入的服务。
+// 框架会查找与服务名称（或显式指定的配置段名称）匹配的配置段，
+// 并在调用 Boot 之前将其传给 Configure；配置段不存在时 v 为 nil。
+// 测试中可以直接用手工构造的 viper 调用 Configure。
+type Configurable interface {
+	Configure(v *viper.Viper) error
+}
+
+// ConfigRequirer 描述一个必须存在配置段的 Configurable 服务。
+// 当 ConfigRequired 返回 true 且配置段不存在时，框架不会调用 Configure，而是直接使 Boot 失败。
+type ConfigRequirer interface {
+	ConfigRequired() bool
+}
+
 func GetService[T any](k Kernel, name string) (T, error) {
 	var zero T
 	svc, err := k.Container().Get(name)
@@ -52,3 +91,24 @@ func MustGetService[T any](k Kernel, name string) T {
 	}
 	return svc
 }
+
+// TryGetService 尝试获取可选集成的服务，适用于“注册了就使用，否则跳过”的场景。
+// 仅当服务未注册时返回 ok=false；服务已注册但类型不匹配属于编程错误，会直接 panic，
+// 避免类型错误被当作“未注册”静默忽略。
+//
+// 示例：
+//
+//	if tracer, ok := kernel.TryGetService[*TracingService](k, "tracing"); ok {
+//	    tracer.Start(ctx)
+//	}
+func TryGetService[T any](k Kernel, name string) (T, bool) {
+	svc, err := GetService[T](k, name)
+	if err != nil {
+		if IsServiceNotFound(err) {
+			var zero T
+			return zero, false
+		}
+		panic(err)
+	}
+	return svc, true
+}
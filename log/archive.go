@@ -0,0 +1,223 @@
+package log
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/qq1060656096/drugo/pkg/clock"
+)
+
+// DefaultArchiveTimeout 是未设置 ArchiveCommandTimeout 时单次归档钩子的超时时间
+const DefaultArchiveTimeout = time.Minute
+
+// MaxArchiveAttempts 是单个轮转文件归档失败后的最大尝试次数，超过后不再重试
+const MaxArchiveAttempts = 5
+
+// 扫描间隔与重试退避，测试中可以调小
+var (
+	archiveScanInterval = 10 * time.Second
+	archiveRetryBackoff = time.Second
+	archiveMaxBackoff   = 5 * time.Minute
+)
+
+// rotatedFilePattern 匹配 lumberjack 轮转出的备份文件，例如 app-2024-01-02T15-04-05.000.log(.gz)，
+// 不会匹配正在写入的 app.log
+var rotatedFilePattern = regexp.MustCompile(`^.+-\d{4}-\d{2}-\d{2}T\d{2}-\d{2}-\d{2}\.\d{3}\.log(\.gz)?$`)
+
+// ArchiveHook 归档钩子，path 为已完成轮转的日志文件路径，通常用于上传到对象存储。
+// ctx 在超时（ArchiveCommandTimeout）或 Manager 关闭时取消。
+type ArchiveHook func(ctx context.Context, path string) error
+
+// archiveState 记录单个轮转文件的归档进度
+type archiveState struct {
+	attempts int
+	next     time.Time
+	done     bool
+}
+
+// archiver 是后台扫描轮转文件并调用归档钩子的协程
+type archiver struct {
+	clock   clock.Clock
+	hook    ArchiveHook
+	dirs    map[string]bool // 日志目录 -> 是否压缩轮转文件
+	timeout time.Duration
+	remove  bool
+	states  map[string]*archiveState
+	cancel  context.CancelFunc
+	done    chan struct{}
+}
+
+// SetArchiveHook 设置归档钩子并启动后台协程，定期扫描所有文件输出目录中已完成轮转的文件，
+// 对每个文件调用 hook；成功后如果启用了 RemoveAfterArchive 则删除本地文件。
+//
+// 正在写入的 .log 文件不会被处理；启用压缩时只处理压缩完成的 .gz 文件。
+// hook 失败时按指数退避重试，最多尝试 MaxArchiveAttempts 次，失败信息输出到标准错误。
+// 重复调用会替换之前的钩子，hook 为 nil 时停止归档；Close 会停止归档协程。
+func (m *Manager) SetArchiveHook(hook ArchiveHook) {
+	if m == nil {
+		return
+	}
+	m.archMu.Lock()
+	defer m.archMu.Unlock()
+
+	m.stopArchiverLocked()
+	if hook == nil {
+		return
+	}
+
+	timeout := m.cfg.ArchiveCommandTimeout
+	if timeout <= 0 {
+		timeout = DefaultArchiveTimeout
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	a := &archiver{
+		clock:   m.clock(),
+		hook:    hook,
+		dirs:    m.cfg.archiveDirs(),
+		timeout: timeout,
+		remove:  m.cfg.RemoveAfterArchive,
+		states:  make(map[string]*archiveState),
+		cancel:  cancel,
+		done:    make(chan struct{}),
+	}
+	m.arch = a
+	go a.loop(ctx)
+}
+
+// stopArchiver 停止归档协程并等待其退出
+func (m *Manager) stopArchiver() {
+	m.archMu.Lock()
+	defer m.archMu.Unlock()
+	m.stopArchiverLocked()
+}
+
+func (m *Manager) stopArchiverLocked() {
+	if m.arch == nil {
+		return
+	}
+	m.arch.cancel()
+	<-m.arch.done
+	m.arch = nil
+}
+
+// archiveDirs 返回所有文件输出目录（包括路由目录）及其是否压缩轮转文件
+func (c Config) archiveDirs() map[string]bool {
+	dirs := make(map[string]bool)
+	compress := false
+	for _, out := range c.Outputs {
+		if out.Type == OutputTypeFile && out.File != nil {
+			dirs[out.File.Dir] = dirs[out.File.Dir] || out.File.Compress
+			compress = compress || out.File.Compress
+		}
+	}
+	if len(dirs) == 0 {
+		return dirs
+	}
+	for _, r := range c.Routes {
+		routeCompress := compress
+		if r.Compress != nil {
+			routeCompress = *r.Compress
+		}
+		dirs[r.Dir] = dirs[r.Dir] || routeCompress
+	}
+	return dirs
+}
+
+func (a *archiver) loop(ctx context.Context) {
+	defer close(a.done)
+
+	timer := a.clock.NewTimer(archiveScanInterval)
+	defer timer.Stop()
+	for {
+		a.scan(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C():
+			timer.Reset(archiveScanInterval)
+		}
+	}
+}
+
+// scan 扫描一遍所有日志目录，处理已完成轮转的文件
+func (a *archiver) scan(ctx context.Context) {
+	seen := make(map[string]struct{})
+	for dir, compress := range a.dirs {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			// 目录可能尚未创建（还没有写入过日志）
+			continue
+		}
+		names := make(map[string]struct{}, len(entries))
+		for _, e := range entries {
+			names[e.Name()] = struct{}{}
+		}
+		for _, e := range entries {
+			name := e.Name()
+			if e.IsDir() || !rotatedFilePattern.MatchString(name) {
+				continue
+			}
+			src, gz := strings.CutSuffix(name, ".gz")
+			if compress && !gz {
+				continue // lumberjack 稍后会压缩该文件
+			}
+			if _, ok := names[src]; gz && ok {
+				continue // 压缩尚未完成
+			}
+			path := filepath.Join(dir, name)
+			seen[path] = struct{}{}
+			if ctx.Err() != nil {
+				return
+			}
+			a.process(ctx, path)
+		}
+	}
+	// 清理已不存在的文件的状态
+	for path := range a.states {
+		if _, ok := seen[path]; !ok {
+			delete(a.states, path)
+		}
+	}
+}
+
+// process 对单个文件调用归档钩子，失败时记录下次重试时间
+func (a *archiver) process(ctx context.Context, path string) {
+	st := a.states[path]
+	if st == nil {
+		st = &archiveState{}
+		a.states[path] = st
+	}
+	if st.done || st.attempts >= MaxArchiveAttempts || a.clock.Now().Before(st.next) {
+		return
+	}
+
+	hookCtx, cancel := clock.WithTimeout(ctx, a.clock, a.timeout)
+	err := a.hook(hookCtx, path)
+	cancel()
+	if err != nil {
+		if ctx.Err() != nil {
+			return // Manager 关闭导致的失败不计入尝试次数
+		}
+		st.attempts++
+		if st.attempts >= MaxArchiveAttempts {
+			fmt.Fprintf(os.Stderr, "log archive %s failed after %d attempts, giving up: %v\n", path, st.attempts, err)
+			return
+		}
+		backoff := min(archiveRetryBackoff<<(st.attempts-1), archiveMaxBackoff)
+		st.next = a.clock.Now().Add(backoff)
+		fmt.Fprintf(os.Stderr, "log archive %s failed (attempt %d), retry in %s: %v\n", path, st.attempts, backoff, err)
+		return
+	}
+
+	st.done = true
+	if a.remove {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			fmt.Fprintf(os.Stderr, "log archive %s: remove failed: %v\n", path, err)
+		}
+	}
+}
@@ -0,0 +1,206 @@
+package log
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const rotatedName = "app-2024-01-02T15-04-05.000.log"
+
+// shortArchiveIntervals 缩短扫描间隔和重试退避，测试结束后恢复
+func shortArchiveIntervals(t *testing.T) {
+	t.Helper()
+	scan, backoff := archiveScanInterval, archiveRetryBackoff
+	archiveScanInterval, archiveRetryBackoff = 10*time.Millisecond, 10*time.Millisecond
+	t.Cleanup(func() { archiveScanInterval, archiveRetryBackoff = scan, backoff })
+}
+
+// hookRecorder 记录归档钩子的调用，前 failures 次调用返回错误
+type hookRecorder struct {
+	mu       sync.Mutex
+	calls    []string
+	failures int
+}
+
+func (r *hookRecorder) hook(ctx context.Context, path string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls = append(r.calls, filepath.Base(path))
+	if len(r.calls) <= r.failures {
+		return errors.New("upload failed")
+	}
+	return nil
+}
+
+func (r *hookRecorder) Calls() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string(nil), r.calls...)
+}
+
+func newArchiveTestManager(t *testing.T, dir string, compress, remove bool) *Manager {
+	t.Helper()
+	m, err := NewManager(Config{
+		Outputs: []OutputConfig{
+			{Type: OutputTypeFile, Format: FormatJSON, File: &FileOutputConfig{Dir: dir, Compress: compress}},
+		},
+		RemoveAfterArchive: remove,
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = m.Close() })
+	return m
+}
+
+func writeFile(t *testing.T, path string) {
+	t.Helper()
+	require.NoError(t, os.WriteFile(path, []byte("{}\n"), 0644))
+}
+
+// TestManager_ArchiveHook 测试只归档轮转文件，成功后删除本地文件
+func TestManager_ArchiveHook(t *testing.T) {
+	shortArchiveIntervals(t)
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "app.log"))
+	writeFile(t, filepath.Join(dir, rotatedName))
+	writeFile(t, filepath.Join(dir, "notes.txt"))
+
+	m := newArchiveTestManager(t, dir, false, true)
+	rec := &hookRecorder{}
+	m.SetArchiveHook(rec.hook)
+
+	require.Eventually(t, func() bool { return len(rec.Calls()) == 1 }, time.Second, 5*time.Millisecond)
+	require.Eventually(t, func() bool {
+		_, err := os.Stat(filepath.Join(dir, rotatedName))
+		return os.IsNotExist(err)
+	}, time.Second, 5*time.Millisecond)
+
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, []string{rotatedName}, rec.Calls())
+	assert.FileExists(t, filepath.Join(dir, "app.log"))
+	assert.FileExists(t, filepath.Join(dir, "notes.txt"))
+}
+
+// TestManager_ArchiveHook_Rotate 测试真实轮转产生的文件会被归档，未启用删除时保留本地文件
+func TestManager_ArchiveHook_Rotate(t *testing.T) {
+	shortArchiveIntervals(t)
+	dir := t.TempDir()
+	m := newArchiveTestManager(t, dir, false, false)
+	rec := &hookRecorder{}
+	m.SetArchiveHook(rec.hook)
+
+	m.MustGet("app").Info("before rotate")
+	require.NoError(t, m.Rotate("app"))
+
+	require.Eventually(t, func() bool { return len(rec.Calls()) == 1 }, time.Second, 5*time.Millisecond)
+	assert.Regexp(t, rotatedFilePattern, rec.Calls()[0])
+
+	// 归档成功且未删除的文件不会被重复归档
+	time.Sleep(50 * time.Millisecond)
+	assert.Len(t, rec.Calls(), 1)
+	assert.FileExists(t, filepath.Join(dir, rec.Calls()[0]))
+}
+
+// TestManager_ArchiveHook_Retry 测试钩子失败后重试，直到成功或达到最大次数
+func TestManager_ArchiveHook_Retry(t *testing.T) {
+	shortArchiveIntervals(t)
+
+	t.Run("succeeds after failures", func(t *testing.T) {
+		dir := t.TempDir()
+		writeFile(t, filepath.Join(dir, rotatedName))
+		m := newArchiveTestManager(t, dir, false, true)
+		rec := &hookRecorder{failures: 2}
+		m.SetArchiveHook(rec.hook)
+
+		require.Eventually(t, func() bool {
+			_, err := os.Stat(filepath.Join(dir, rotatedName))
+			return os.IsNotExist(err)
+		}, 2*time.Second, 5*time.Millisecond)
+		assert.Len(t, rec.Calls(), 3)
+	})
+
+	t.Run("gives up after max attempts", func(t *testing.T) {
+		dir := t.TempDir()
+		writeFile(t, filepath.Join(dir, rotatedName))
+		m := newArchiveTestManager(t, dir, false, true)
+		rec := &hookRecorder{failures: 100}
+		m.SetArchiveHook(rec.hook)
+
+		require.Eventually(t, func() bool { return len(rec.Calls()) == MaxArchiveAttempts }, 3*time.Second, 5*time.Millisecond)
+		time.Sleep(200 * time.Millisecond)
+		assert.Len(t, rec.Calls(), MaxArchiveAttempts)
+		assert.FileExists(t, filepath.Join(dir, rotatedName))
+	})
+}
+
+// TestManager_ArchiveHook_Compress 测试启用压缩时只处理压缩完成的 .gz 文件
+func TestManager_ArchiveHook_Compress(t *testing.T) {
+	shortArchiveIntervals(t)
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, rotatedName))
+	writeFile(t, filepath.Join(dir, rotatedName+".gz"))
+
+	m := newArchiveTestManager(t, dir, true, false)
+	rec := &hookRecorder{}
+	m.SetArchiveHook(rec.hook)
+
+	// 未压缩的源文件仍然存在，说明压缩尚未完成
+	time.Sleep(50 * time.Millisecond)
+	assert.Empty(t, rec.Calls())
+
+	require.NoError(t, os.Remove(filepath.Join(dir, rotatedName)))
+	require.Eventually(t, func() bool { return len(rec.Calls()) == 1 }, time.Second, 5*time.Millisecond)
+	assert.Equal(t, rotatedName+".gz", rec.Calls()[0])
+}
+
+// TestManager_ArchiveHook_Close 测试 Close 停止归档协程并取消进行中的钩子
+func TestManager_ArchiveHook_Close(t *testing.T) {
+	shortArchiveIntervals(t)
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, rotatedName))
+
+	m := newArchiveTestManager(t, dir, false, true)
+	started := make(chan struct{})
+	var once sync.Once
+	m.SetArchiveHook(func(ctx context.Context, path string) error {
+		once.Do(func() { close(started) })
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	<-started
+
+	done := make(chan struct{})
+	go func() {
+		_ = m.Close()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Close did not stop the archiver")
+	}
+	assert.Nil(t, m.arch)
+	assert.FileExists(t, filepath.Join(dir, rotatedName))
+
+	// nil 钩子和 nil Manager 不会启动协程
+	m.SetArchiveHook(nil)
+	assert.Nil(t, m.arch)
+	var nilManager *Manager
+	assert.NotPanics(t, func() { nilManager.SetArchiveHook(func(context.Context, string) error { return nil }) })
+}
+
+// TestConfig_Validate_ArchiveTimeout 测试归档超时不能为负数
+func TestConfig_Validate_ArchiveTimeout(t *testing.T) {
+	cfg := Config{
+		Outputs:               []OutputConfig{{Type: OutputTypeConsole}},
+		ArchiveCommandTimeout: -time.Second,
+	}
+	assert.True(t, IsInvalidConfigValue(cfg.Validate()))
+}
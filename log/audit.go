@@ -0,0 +1,380 @@
+package log
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/qq1060656096/drugo/pkg/clock"
+)
+
+const (
+	// AuditSubdir 是未设置 AuditConfig.Dir 时审计日志所在的子目录，位于第一个文件输出的目录下
+	AuditSubdir = "audit"
+	// DefaultAuditMaxSize 是未设置 AuditConfig.MaxSize 时单个审计文件的最大大小(MB)
+	DefaultAuditMaxSize = 100
+	// AuditSchemaVersion 是审计记录的格式版本，写入每条记录的 "v" 字段
+	AuditSchemaVersion = 1
+)
+
+// auditBackupTimeFormat 是审计文件轮转后备份文件名中的时间格式，与 lumberjack 的备份文件一致
+const auditBackupTimeFormat = "2006-01-02T15-04-05.000"
+
+// AuditConfig 审计日志配置，见 Manager.Audit
+type AuditConfig struct {
+	// Dir 审计日志目录，为空时使用第一个文件输出目录下的 AuditSubdir 子目录
+	Dir string `yaml:"dir" mapstructure:"dir"`
+	// MaxSize 单个审计文件的最大大小(MB)，超过时轮转，为 0 时使用 DefaultAuditMaxSize
+	MaxSize int `yaml:"max_size" mapstructure:"max_size"`
+	// SyncInterval 批量 fsync 的间隔，为 0 时每次写入后立即 fsync；
+	// 大于 0 时写入只保证进入操作系统缓存，最多间隔 SyncInterval 后 fsync，用于对吞吐敏感的场景
+	SyncInterval time.Duration `yaml:"sync_interval" mapstructure:"sync_interval"`
+}
+
+func (c *AuditConfig) validate() error {
+	if c.MaxSize < 0 {
+		return fmt.Errorf("%w: audit.max_size=%d", ErrInvalidConfigValue, c.MaxSize)
+	}
+	if c.SyncInterval < 0 {
+		return fmt.Errorf("%w: audit.sync_interval=%s", ErrInvalidConfigValue, c.SyncInterval)
+	}
+	return nil
+}
+
+// auditDir 返回审计日志目录，没有文件输出且没有设置 AuditConfig.Dir 时返回空字符串
+func (c Config) auditDir() string {
+	if c.Audit.Dir != "" {
+		return c.Audit.Dir
+	}
+	for _, out := range c.Outputs {
+		if out.Type == OutputTypeFile && out.File != nil {
+			return filepath.Join(out.File.Dir, AuditSubdir)
+		}
+	}
+	return ""
+}
+
+// AuditEvent 是一条审计记录
+type AuditEvent struct {
+	Time     time.Time         // 发生时间，为零值时使用写入时的时间
+	Actor    string            // 操作者，例如用户 ID
+	Action   string            // 操作，例如 "order.refund"
+	Resource string            // 操作对象，例如 "order/1001"
+	Result   string            // 结果，例如 "success"、"denied"
+	Metadata map[string]string // 附加信息
+}
+
+// auditRecord 是审计记录的 JSON 格式，字段顺序即输出顺序，修改需要同时递增 AuditSchemaVersion
+type auditRecord struct {
+	Version  int               `json:"v"`
+	Time     string            `json:"ts"`
+	Biz      string            `json:"biz"`
+	Actor    string            `json:"actor"`
+	Action   string            `json:"action"`
+	Resource string            `json:"resource"`
+	Result   string            `json:"result"`
+	Metadata map[string]string `json:"metadata"`
+}
+
+// encodeAudit 将审计记录编码为一行 JSON（以换行结尾）。
+// 字段顺序固定，Metadata 按键排序且为空时输出 {}，时间为 UTC 的 RFC 3339 格式，不转义 HTML 字符，
+// 相同的记录总是得到相同的字节
+func encodeAudit(bizName string, e AuditEvent) ([]byte, error) {
+	metadata := e.Metadata
+	if metadata == nil {
+		metadata = map[string]string{}
+	}
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	err := enc.Encode(auditRecord{
+		Version:  AuditSchemaVersion,
+		Time:     e.Time.UTC().Format(time.RFC3339Nano),
+		Biz:      bizName,
+		Actor:    e.Actor,
+		Action:   e.Action,
+		Resource: e.Resource,
+		Result:   e.Result,
+		Metadata: metadata,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// AuditLogger 将审计记录同步写入独立的审计文件，与普通业务日志相比有更严格的保证：
+//   - Write 在记录写入文件并 fsync（或按 AuditConfig.SyncInterval 批量 fsync）之后才返回，没有异步缓冲
+//   - 写入或 fsync 失败时 Write 返回错误，而不是像业务日志那样只记录到 WriteErrors
+//   - 审计文件位于独立的目录中，不经过 zap，不受级别、采样、目录配额（MaxTotalSizeMB）与归档的影响
+//
+// 文件以 O_APPEND 打开，超过 AuditConfig.MaxSize 时先 fsync 再轮转为带时间戳的备份文件，轮转不会丢失已写入的记录。
+// AuditLogger 的方法是并发安全的。
+type AuditLogger struct {
+	bizName  string
+	path     string
+	maxSize  int64
+	interval time.Duration
+	clock    clock.Clock
+
+	mu      sync.Mutex
+	file    *os.File // 第一次写入或轮转后打开
+	size    int64    // 当前文件的大小
+	dirty   bool     // 批量模式下有尚未 fsync 的写入
+	syncErr error    // 批量 fsync 的错误，由下一次 Write 返回
+	closed  bool
+
+	stop chan struct{} // 关闭时停止批量 fsync 协程
+	done chan struct{}
+}
+
+// Audit 返回 bizName 的审计日志实例，记录写入审计目录中的 <bizName>.log。
+// 同一业务名称返回同一个实例，Manager.Close 时关闭。
+// 没有文件输出且没有设置 AuditConfig.Dir 时返回 ErrNoFileOutput。
+func (m *Manager) Audit(bizName string) (*AuditLogger, error) {
+	if m == nil {
+		return nil, ErrNilManager
+	}
+	if bizName == "" {
+		return nil, ErrEmptyBizName
+	}
+	dir := m.cfg.auditDir()
+	if dir == "" {
+		return nil, fmt.Errorf("audit '%s': %w", bizName, ErrNoFileOutput)
+	}
+
+	m.auditMu.Lock()
+	defer m.auditMu.Unlock()
+	if a, ok := m.audits[bizName]; ok {
+		return a, nil
+	}
+	maxSize := m.cfg.Audit.MaxSize
+	if maxSize <= 0 {
+		maxSize = DefaultAuditMaxSize
+	}
+	a := &AuditLogger{
+		bizName:  bizName,
+		path:     filepath.Join(dir, bizName+".log"),
+		maxSize:  int64(maxSize) << 20,
+		interval: m.cfg.Audit.SyncInterval,
+		clock:    m.clock(),
+	}
+	if a.interval > 0 {
+		a.stop = make(chan struct{})
+		a.done = make(chan struct{})
+		go a.syncLoop()
+	}
+	if m.audits == nil {
+		m.audits = make(map[string]*AuditLogger)
+	}
+	m.audits[bizName] = a
+	return a, nil
+}
+
+// Path 返回当前审计文件的路径
+func (a *AuditLogger) Path() string {
+	return a.path
+}
+
+// Write 写入一条审计记录，记录持久化之后才返回（批量 fsync 模式见 AuditConfig.SyncInterval），
+// 失败时返回错误，调用方应当据此决定是否继续执行被审计的操作。ctx 已取消时不写入并返回 ctx 的错误。
+func (a *AuditLogger) Write(ctx context.Context, event AuditEvent) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if event.Time.IsZero() {
+		event.Time = a.clock.Now()
+	}
+	line, err := encodeAudit(a.bizName, event)
+	if err != nil {
+		return fmt.Errorf("audit '%s': encode: %w", a.bizName, err)
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.closed {
+		return fmt.Errorf("audit '%s': %w", a.bizName, ErrAuditClosed)
+	}
+	if err := a.syncErr; err != nil {
+		a.syncErr = nil
+		return err
+	}
+	if a.file != nil && a.size > 0 && a.size+int64(len(line)) > a.maxSize {
+		if err := a.rotate(); err != nil {
+			return err
+		}
+	}
+	if a.file == nil {
+		if err := a.open(); err != nil {
+			return err
+		}
+	}
+
+	n, err := a.file.Write(line)
+	a.size += int64(n)
+	if err != nil {
+		return fmt.Errorf("audit '%s': write %s: %w", a.bizName, a.path, err)
+	}
+	if a.interval > 0 {
+		a.dirty = true
+		return nil
+	}
+	return a.sync()
+}
+
+// Sync 立即 fsync 尚未持久化的记录，只在批量 fsync 模式下需要
+func (a *AuditLogger) Sync() error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if err := a.syncErr; err != nil {
+		a.syncErr = nil
+		return err
+	}
+	return a.sync()
+}
+
+// Close 持久化尚未 fsync 的记录并关闭审计文件，之后的 Write 返回 ErrAuditClosed。可以重复调用
+func (a *AuditLogger) Close() error {
+	a.mu.Lock()
+	if a.closed {
+		a.mu.Unlock()
+		return nil
+	}
+	a.closed = true
+	err := errors.Join(a.syncErr, a.closeFile())
+	a.mu.Unlock()
+
+	if a.stop != nil {
+		close(a.stop)
+		<-a.done
+	}
+	return err
+}
+
+// open 创建审计目录并以追加模式打开审计文件，调用方需要持有 a.mu
+func (a *AuditLogger) open() error {
+	if err := os.MkdirAll(filepath.Dir(a.path), 0755); err != nil {
+		return fmt.Errorf("audit '%s': %w", a.bizName, err)
+	}
+	f, err := os.OpenFile(a.path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0644)
+	if err != nil {
+		return fmt.Errorf("audit '%s': %w", a.bizName, err)
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return fmt.Errorf("audit '%s': %w", a.bizName, err)
+	}
+	a.file = f
+	a.size = info.Size()
+	return nil
+}
+
+// sync 在有打开的文件时执行 fsync，调用方需要持有 a.mu
+func (a *AuditLogger) sync() error {
+	if a.file == nil {
+		return nil
+	}
+	if err := a.file.Sync(); err != nil {
+		return fmt.Errorf("audit '%s': sync %s: %w", a.bizName, a.path, err)
+	}
+	a.dirty = false
+	return nil
+}
+
+// closeFile fsync 并关闭当前文件，调用方需要持有 a.mu
+func (a *AuditLogger) closeFile() error {
+	if a.file == nil {
+		return nil
+	}
+	err := a.sync()
+	if cerr := a.file.Close(); cerr != nil && err == nil {
+		err = fmt.Errorf("audit '%s': close %s: %w", a.bizName, a.path, cerr)
+	}
+	a.file = nil
+	a.size = 0
+	return err
+}
+
+// rotate fsync 并关闭当前文件，将其重命名为带时间戳的备份文件，下一次写入时打开新文件。
+// 调用方需要持有 a.mu
+func (a *AuditLogger) rotate() error {
+	if err := a.closeFile(); err != nil {
+		return err
+	}
+	ext := filepath.Ext(a.path)
+	backup := strings.TrimSuffix(a.path, ext) + "-" + a.clock.Now().UTC().Format(auditBackupTimeFormat) + ext
+	if err := os.Rename(a.path, backup); err != nil {
+		return fmt.Errorf("audit '%s': rotate: %w", a.bizName, err)
+	}
+	syncDir(filepath.Dir(a.path))
+	return nil
+}
+
+// syncLoop 是批量 fsync 模式下的后台协程，每隔 interval 持久化一次尚未 fsync 的记录
+func (a *AuditLogger) syncLoop() {
+	defer close(a.done)
+	timer := a.clock.NewTimer(a.interval)
+	defer timer.Stop()
+	for {
+		select {
+		case <-a.stop:
+			return
+		case <-timer.C():
+			timer.Reset(a.interval)
+		}
+		a.mu.Lock()
+		if a.dirty {
+			if err := a.sync(); err != nil {
+				a.syncErr = errors.Join(a.syncErr, err)
+				a.dirty = false
+			}
+		}
+		a.mu.Unlock()
+	}
+}
+
+// syncDir fsync 目录，使轮转时的重命名持久化；部分平台（例如 Windows）不支持，此时忽略错误
+func syncDir(dir string) {
+	d, err := os.Open(dir)
+	if err != nil {
+		return
+	}
+	_ = d.Sync()
+	d.Close()
+}
+
+// syncAudits fsync 所有审计日志实例，返回失败的错误
+func (m *Manager) syncAudits() []error {
+	m.auditMu.Lock()
+	defer m.auditMu.Unlock()
+	var errs []error
+	for _, a := range m.audits {
+		if err := a.Sync(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errs
+}
+
+// closeAudits 关闭并清空所有审计日志实例，返回失败的错误
+func (m *Manager) closeAudits() []error {
+	m.auditMu.Lock()
+	audits := m.audits
+	m.audits = nil
+	m.auditMu.Unlock()
+	var errs []error
+	for _, a := range audits {
+		if err := a.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errs
+}
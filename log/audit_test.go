@@ -0,0 +1,210 @@
+package log
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/qq1060656096/drugo/pkg/clock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newAuditTestManager(t *testing.T, dir string, audit AuditConfig) *Manager {
+	t.Helper()
+	m, err := NewManager(Config{
+		Outputs: []OutputConfig{
+			{Type: OutputTypeFile, Format: FormatJSON, File: &FileOutputConfig{Dir: dir}},
+		},
+		Audit: audit,
+		Clock: clock.NewFake(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)),
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = m.Close() })
+	return m
+}
+
+// readLines 返回文件中的所有行
+func readLines(t *testing.T, path string) []string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	return strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+}
+
+// TestManager_Audit_Durable 测试 Write 返回后记录已经写入审计子目录中的文件，不需要调用 Sync
+func TestManager_Audit_Durable(t *testing.T) {
+	dir := t.TempDir()
+	m := newAuditTestManager(t, dir, AuditConfig{})
+
+	a, err := m.Audit("compliance")
+	require.NoError(t, err)
+	same, err := m.Audit("compliance")
+	require.NoError(t, err)
+	assert.Same(t, a, same)
+	assert.Equal(t, filepath.Join(dir, AuditSubdir, "compliance.log"), a.Path())
+
+	ctx := context.Background()
+	require.NoError(t, a.Write(ctx, AuditEvent{Actor: "u1", Action: "login", Resource: "session", Result: "success"}))
+	require.NoError(t, a.Write(ctx, AuditEvent{Actor: "u2", Action: "order.refund", Resource: "order/1", Result: "denied",
+		Metadata: map[string]string{"reason": "limit"}}))
+
+	lines := readLines(t, a.Path())
+	require.Len(t, lines, 2)
+	assert.Equal(t, `{"v":1,"ts":"2024-01-02T03:04:05Z","biz":"compliance","actor":"u1","action":"login","resource":"session","result":"success","metadata":{}}`, lines[0])
+	assert.Contains(t, lines[1], `"metadata":{"reason":"limit"}`)
+
+	// 审计记录不会出现在普通日志中
+	assert.NoFileExists(t, filepath.Join(dir, "compliance.log"))
+
+	// ctx 已取消时不写入
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	assert.ErrorIs(t, a.Write(canceled, AuditEvent{Actor: "u3"}), context.Canceled)
+	assert.Len(t, readLines(t, a.Path()), 2)
+
+	// Close 之后返回 ErrAuditClosed，再次获取得到新的实例
+	require.NoError(t, m.Close())
+	assert.True(t, IsAuditClosed(a.Write(ctx, AuditEvent{})))
+	b, err := m.Audit("compliance")
+	require.NoError(t, err)
+	assert.NotSame(t, a, b)
+	require.NoError(t, b.Write(ctx, AuditEvent{Actor: "u4"}))
+	assert.Len(t, readLines(t, a.Path()), 3)
+}
+
+// TestEncodeAudit_Schema 测试审计记录的格式固定：字段顺序、UTC 时间、按键排序的 Metadata，相同的记录得到相同的字节
+func TestEncodeAudit_Schema(t *testing.T) {
+	at := time.Date(2024, 5, 6, 15, 4, 5, 123000000, time.FixedZone("CST", 8*3600))
+	event := AuditEvent{
+		Time:     at,
+		Actor:    "admin",
+		Action:   "user.delete",
+		Resource: "user/42",
+		Result:   "success",
+		Metadata: map[string]string{"z": "1", "a": "<2>", "m": "line\nbreak"},
+	}
+	line, err := encodeAudit("audit", event)
+	require.NoError(t, err)
+	assert.Equal(t, `{"v":1,"ts":"2024-05-06T07:04:05.123Z","biz":"audit","actor":"admin","action":"user.delete",`+
+		`"resource":"user/42","result":"success","metadata":{"a":"<2>","m":"line\nbreak","z":"1"}}`+"\n", string(line))
+	assert.Equal(t, 1, strings.Count(string(line), "\n"), "single line")
+
+	for range 10 {
+		again, err := encodeAudit("audit", event)
+		require.NoError(t, err)
+		assert.Equal(t, line, again)
+	}
+}
+
+// TestAuditLogger_Rotate 测试按大小轮转时不丢失记录，备份文件不受目录配额影响
+func TestAuditLogger_Rotate(t *testing.T) {
+	recordQuotaLogs(t)
+	dir := t.TempDir()
+	m := newAuditTestManager(t, dir, AuditConfig{})
+	a, err := m.Audit("compliance")
+	require.NoError(t, err)
+	a.maxSize = 250 // 每个文件 2 条记录
+
+	ctx := context.Background()
+	for i := range 5 {
+		require.NoError(t, a.Write(ctx, AuditEvent{Actor: "u", Action: "act", Result: strings.Repeat("x", i)}))
+		m.cfg.Clock.(*clock.Fake).Advance(time.Second)
+	}
+
+	auditDir := filepath.Join(dir, AuditSubdir)
+	names := listDir(t, auditDir)
+	require.Len(t, names, 3)
+	assert.Equal(t, []string{
+		"compliance-2024-01-02T03-04-07.000.log",
+		"compliance-2024-01-02T03-04-09.000.log",
+		"compliance.log",
+	}, names)
+	var total int
+	for _, name := range names {
+		lines := readLines(t, filepath.Join(auditDir, name))
+		total += len(lines)
+	}
+	assert.Equal(t, 5, total)
+
+	// 目录配额只检查文件输出目录本身，审计子目录中的备份文件不会被淘汰
+	m.cfg.MaxTotalSizeMB = 1
+	writeSizedFile(t, auditDir, "compliance-2023-01-01T00-00-00.000.log", 2<<20, time.Hour)
+	m.enforceQuota()
+	assert.FileExists(t, filepath.Join(auditDir, "compliance-2023-01-01T00-00-00.000.log"))
+	assert.Zero(t, m.Evictions().Count)
+}
+
+// TestAuditLogger_WriteError 测试目录不可写时 Write 返回错误
+func TestAuditLogger_WriteError(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("dir is a file", func(t *testing.T) {
+		dir := t.TempDir()
+		blocker := filepath.Join(dir, "blocker")
+		require.NoError(t, os.WriteFile(blocker, nil, 0644))
+		m := newAuditTestManager(t, dir, AuditConfig{Dir: filepath.Join(blocker, "audit")})
+		a, err := m.Audit("compliance")
+		require.NoError(t, err)
+		assert.Error(t, a.Write(ctx, AuditEvent{Actor: "u1"}))
+	})
+
+	t.Run("read-only dir", func(t *testing.T) {
+		if os.Geteuid() == 0 {
+			t.Skip("root ignores directory permissions")
+		}
+		dir := t.TempDir()
+		auditDir := filepath.Join(dir, AuditSubdir)
+		require.NoError(t, os.MkdirAll(auditDir, 0755))
+		require.NoError(t, os.Chmod(auditDir, 0555))
+		t.Cleanup(func() { _ = os.Chmod(auditDir, 0755) })
+
+		m := newAuditTestManager(t, dir, AuditConfig{})
+		a, err := m.Audit("compliance")
+		require.NoError(t, err)
+		assert.ErrorIs(t, a.Write(ctx, AuditEvent{Actor: "u1"}), os.ErrPermission)
+	})
+}
+
+// TestAuditLogger_BatchSync 测试批量 fsync 模式按间隔持久化
+func TestAuditLogger_BatchSync(t *testing.T) {
+	dir := t.TempDir()
+	m := newAuditTestManager(t, dir, AuditConfig{SyncInterval: time.Second})
+	a, err := m.Audit("compliance")
+	require.NoError(t, err)
+
+	c := m.cfg.Clock.(*clock.Fake)
+	c.BlockUntilWaiters(1)
+	require.NoError(t, a.Write(context.Background(), AuditEvent{Actor: "u1"}))
+	assert.Len(t, readLines(t, a.Path()), 1)
+
+	dirty := func() bool {
+		a.mu.Lock()
+		defer a.mu.Unlock()
+		return a.dirty
+	}
+	assert.True(t, dirty())
+	c.Advance(time.Second)
+	assert.Eventually(t, func() bool { return !dirty() }, time.Second, time.Millisecond)
+
+	require.NoError(t, a.Close())
+	require.NoError(t, a.Close())
+}
+
+// TestAuditConfig_Validate 测试无效的审计配置
+func TestAuditConfig_Validate(t *testing.T) {
+	for _, audit := range []AuditConfig{{MaxSize: -1}, {SyncInterval: -time.Second}} {
+		_, err := NewManager(Config{Outputs: []OutputConfig{{Type: OutputTypeConsole}}, Audit: audit})
+		assert.True(t, IsInvalidConfigValue(err), "%+v", audit)
+	}
+
+	m, err := NewManager(Config{Outputs: []OutputConfig{{Type: OutputTypeConsole}}})
+	require.NoError(t, err)
+	_, err = m.Audit("compliance")
+	assert.True(t, IsNoFileOutput(err))
+	_, err = m.Audit("")
+	assert.True(t, IsEmptyBizName(err))
+}
@@ -0,0 +1,37 @@
+package log
+
+import (
+	"os"
+
+	"golang.org/x/term"
+)
+
+// 控制台输出的颜色模式，见 Config.Color
+const (
+	ColorAuto   = "auto"   // stdout 是终端时为级别着色（默认）
+	ColorAlways = "always" // 始终着色
+	ColorNever  = "never"  // 从不着色
+)
+
+var validColorModes = map[string]struct{}{
+	ColorAuto:   {},
+	ColorAlways: {},
+	ColorNever:  {},
+}
+
+// stdoutIsTerminal 报告 stdout 是否连接到终端，测试中可以替换
+var stdoutIsTerminal = func() bool {
+	return term.IsTerminal(int(os.Stdout.Fd()))
+}
+
+// useColor 根据颜色模式决定控制台输出是否为级别着色，mode 为空时视为 ColorAuto
+func useColor(mode string, isTerminal func() bool) bool {
+	switch mode {
+	case ColorAlways:
+		return true
+	case ColorNever:
+		return false
+	default:
+		return isTerminal()
+	}
+}
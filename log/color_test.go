@@ -0,0 +1,128 @@
+package log
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestUseColor 测试三种颜色模式在终端与非终端下的决定
+func TestUseColor(t *testing.T) {
+	tty := func() bool { return true }
+	pipe := func() bool { return false }
+
+	tests := []struct {
+		mode     string
+		terminal bool
+		pipe     bool
+	}{
+		{ColorAuto, true, false},
+		{"", true, false},
+		{ColorAlways, true, true},
+		{ColorNever, false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.mode, func(t *testing.T) {
+			assert.Equal(t, tt.terminal, useColor(tt.mode, tty))
+			assert.Equal(t, tt.pipe, useColor(tt.mode, pipe))
+		})
+	}
+}
+
+// TestConfig_Validate_Color 测试颜色模式的校验
+func TestConfig_Validate_Color(t *testing.T) {
+	for _, mode := range []string{"", ColorAuto, ColorAlways, ColorNever} {
+		cfg := Config{Color: mode, Outputs: []OutputConfig{{Type: OutputTypeConsole}}}
+		require.NoError(t, cfg.Validate(), mode)
+	}
+
+	cfg := Config{Color: "rainbow", Outputs: []OutputConfig{{Type: OutputTypeConsole}}}
+	err := cfg.Validate()
+	assert.True(t, IsInvalidConfigValue(err))
+	assert.Contains(t, err.Error(), "color=rainbow")
+}
+
+// TestColor_FileOutput 测试即使强制着色，任何格式的文件输出都不包含 ESC 控制字符
+func TestColor_FileOutput(t *testing.T) {
+	for _, format := range []string{FormatText, FormatJSON} {
+		t.Run(format, func(t *testing.T) {
+			dir := t.TempDir()
+			m, err := NewManager(Config{
+				Level: "debug",
+				Color: ColorAlways,
+				Outputs: []OutputConfig{
+					{Type: OutputTypeFile, Format: format, File: &FileOutputConfig{Dir: dir}},
+				},
+			})
+			require.NoError(t, err)
+			defer m.Close()
+
+			l := m.MustGet("app")
+			l.Debug("debug")
+			l.Info("info")
+			l.Warn("warn")
+			l.Error("error")
+			require.NoError(t, m.Sync())
+
+			data, err := os.ReadFile(filepath.Join(dir, "app.log"))
+			require.NoError(t, err)
+			assert.Contains(t, string(data), "warn")
+			assert.NotContains(t, string(data), "\x1b")
+		})
+	}
+}
+
+// TestColor_ConsoleOutput 测试控制台 text 格式按颜色模式着色，json 格式从不着色
+func TestColor_ConsoleOutput(t *testing.T) {
+	tests := []struct {
+		mode   string
+		format string
+		isTTY  bool
+		want   bool
+	}{
+		{ColorAuto, FormatText, true, true},
+		{ColorAuto, FormatText, false, false},
+		{ColorAlways, FormatText, false, true},
+		{ColorNever, FormatText, true, false},
+		{ColorAlways, FormatJSON, true, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.mode+"/"+tt.format, func(t *testing.T) {
+			orig := stdoutIsTerminal
+			stdoutIsTerminal = func() bool { return tt.isTTY }
+			t.Cleanup(func() { stdoutIsTerminal = orig })
+
+			out := captureStdout(t, func() {
+				logger, _, err := newZapLogger(Config{
+					Color:   tt.mode,
+					Outputs: []OutputConfig{{Type: OutputTypeConsole, Format: tt.format}},
+				}, "app", allLevels, nil, nil, &truncStats{})
+				require.NoError(t, err)
+				logger.Info("hello")
+			})
+			assert.Contains(t, out, "hello")
+			assert.Equal(t, tt.want, bytes.Contains([]byte(out), []byte("\x1b[")))
+		})
+	}
+}
+
+// captureStdout 返回 fn 执行期间写入 os.Stdout 的内容
+func captureStdout(t *testing.T, fn func()) string {
+	t.Helper()
+	r, w, err := os.Pipe()
+	require.NoError(t, err)
+	orig := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = orig }()
+
+	fn()
+	require.NoError(t, w.Close())
+	data, err := io.ReadAll(r)
+	require.NoError(t, err)
+	return string(data)
+}
@@ -2,7 +2,9 @@ package log
 
 import (
 	"fmt"
+	"time"
 
+	"github.com/qq1060656096/drugo/pkg/clock"
 	"go.uber.org/zap"
 )
 
@@ -40,6 +42,54 @@ func isValidOutputFormat(f string) bool {
 type Config struct {
 	Level   string         `yaml:"level" mapstructure:"level"`     // 日志级别: debug, info, warn, error
 	Outputs []OutputConfig `yaml:"outputs" mapstructure:"outputs"` // 输出配置列表
+	// CallerSkip 额外跳过的调用栈层数，用于在全局日志封装函数中输出正确的调用位置
+	CallerSkip int `yaml:"caller_skip" mapstructure:"caller_skip"`
+	// Routes 按业务名称将文件输出路由到不同目录，使用第一个匹配的路由，未匹配时使用输出本身的目录
+	Routes []Route `yaml:"routes" mapstructure:"routes"`
+	// ArchiveCommandTimeout 单次归档钩子（见 Manager.SetArchiveHook）的超时时间，为 0 时使用 DefaultArchiveTimeout
+	ArchiveCommandTimeout time.Duration `yaml:"archive_command_timeout" mapstructure:"archive_command_timeout"`
+	// RemoveAfterArchive 归档钩子成功后是否删除本地的轮转文件
+	RemoveAfterArchive bool `yaml:"remove_after_archive" mapstructure:"remove_after_archive"`
+	// WriteFailureThreshold 连续写入失败超过该次数时调用 Manager.OnWriteFailure 的回调，为 0 时使用 DefaultWriteFailureThreshold
+	WriteFailureThreshold int `yaml:"write_failure_threshold" mapstructure:"write_failure_threshold"`
+	// StacktraceLevel 达到该级别的日志附带 stacktrace 字段，为空时不记录堆栈；与日志级别相互独立，不受 SetLevel 影响
+	StacktraceLevel string `yaml:"stacktrace_level" mapstructure:"stacktrace_level"`
+	// Development 启用 zap 的开发模式，例如 DPanic 级别的日志会触发 panic
+	Development bool `yaml:"development" mapstructure:"development"`
+	// MaxTotalSizeMB 每个日志目录中轮转文件的总大小上限(MB)，超过时从最旧的轮转文件开始删除，为 0 时不限制
+	MaxTotalSizeMB int `yaml:"max_total_size_mb" mapstructure:"max_total_size_mb"`
+	// QuotaScanInterval 目录配额的检查间隔，为 0 时使用 DefaultQuotaScanInterval
+	QuotaScanInterval time.Duration `yaml:"quota_scan_interval" mapstructure:"quota_scan_interval"`
+	// Color 控制台 text 格式输出的级别颜色: auto（为空时的默认值，stdout 是终端时着色）、always、never；
+	// 文件输出与 json 格式从不包含颜色控制字符
+	Color string `yaml:"color" mapstructure:"color"`
+	// Trace 为 true 时 Manager.For 从上下文中提取链路追踪信息并添加 trace_id 与 span_id 字段，
+	// 需要先通过 SetTraceExtractor（例如 log/otelzap.Install）设置提取函数
+	Trace bool `yaml:"trace" mapstructure:"trace"`
+	// Clock 目录配额与归档扫描等定期任务使用的时钟，为 nil 时使用真实时钟；
+	// 只能通过代码设置，测试中可以传入 clock.Fake 推进时间
+	Clock clock.Clock `yaml:"-" mapstructure:"-" json:"-"`
+	// Audit 审计日志配置，见 Manager.Audit
+	Audit AuditConfig `yaml:"audit" mapstructure:"audit"`
+	// Labels 按业务名称为日志添加的静态字段，键 LabelsWildcard 的字段添加到所有业务，
+	// 与具体业务的字段同名时以具体业务为准，见 Manager.LabelsFor
+	Labels map[string]map[string]string `yaml:"labels" mapstructure:"labels"`
+	// Levels 按业务名称固定的日志级别，Get 创建该业务的日志实例时应用，效果等同于 SetLevel，
+	// 之后 SetDefaultLevel 不再影响它；通常由 ApplyEnvOverrides 根据 <prefix>_LEVEL_<业务名称> 设置
+	Levels map[string]string `yaml:"levels" mapstructure:"levels"`
+	// CrashRing 大于 0 时在内存中保留所有业务日志最近的 CrashRing 条日志（不受输出配置影响，按日志级别过滤），
+	// 崩溃报告（见 Manager.DumpCrash）中附带这些日志；为 0 时不保留
+	CrashRing int `yaml:"crash_ring" mapstructure:"crash_ring"`
+	// CrashDir 崩溃报告文件的目录，见 Manager.WriteCrashFile 与 HandlePanic
+	CrashDir string `yaml:"crash_dir" mapstructure:"crash_dir"`
+	// NewlineReplacement text 格式中替换消息与堆栈里换行符（\r\n、\n、\r）的字符串，为空时使用 DefaultNewlineReplacement，
+	// 避免一条日志被下游按行解析成多条；json 格式由编码器转义换行符，不受影响
+	NewlineReplacement string `yaml:"newline_replacement" mapstructure:"newline_replacement"`
+	// MaxFieldBytes 单个字符串或字节字段的最大字节数，超过时截断并追加 "...(truncated N bytes)"，为 0 时不限制
+	MaxFieldBytes int `yaml:"max_field_bytes" mapstructure:"max_field_bytes"`
+	// MaxEntryBytes 单条日志编码后的最大字节数，超过时该条日志的字段被替换为一个 EntryTruncatedKey 摘要字段并丢弃堆栈，为 0 时不限制；
+	// 截断与替换的次数见 Manager.Truncations
+	MaxEntryBytes int `yaml:"max_entry_bytes" mapstructure:"max_entry_bytes"`
 }
 
 // OutputConfig 单个日志输出配置
@@ -70,12 +120,58 @@ func (c *Config) Validate() error {
 	if err := validateLogLevel(c.Level); err != nil {
 		return err
 	}
+	if err := validateLogLevel(c.StacktraceLevel); err != nil {
+		return fmt.Errorf("stacktrace_level: %w", err)
+	}
+	if c.CallerSkip < 0 {
+		return fmt.Errorf("%w: caller_skip=%d", ErrInvalidConfigValue, c.CallerSkip)
+	}
+	if c.ArchiveCommandTimeout < 0 {
+		return fmt.Errorf("%w: archive_command_timeout=%s", ErrInvalidConfigValue, c.ArchiveCommandTimeout)
+	}
+	if c.WriteFailureThreshold < 0 {
+		return fmt.Errorf("%w: write_failure_threshold=%d", ErrInvalidConfigValue, c.WriteFailureThreshold)
+	}
+	if c.MaxTotalSizeMB < 0 {
+		return fmt.Errorf("%w: max_total_size_mb=%d", ErrInvalidConfigValue, c.MaxTotalSizeMB)
+	}
+	if c.CrashRing < 0 {
+		return fmt.Errorf("%w: crash_ring=%d", ErrInvalidConfigValue, c.CrashRing)
+	}
+	if c.MaxFieldBytes < 0 {
+		return fmt.Errorf("%w: max_field_bytes=%d", ErrInvalidConfigValue, c.MaxFieldBytes)
+	}
+	if c.MaxEntryBytes < 0 {
+		return fmt.Errorf("%w: max_entry_bytes=%d", ErrInvalidConfigValue, c.MaxEntryBytes)
+	}
+	if c.QuotaScanInterval < 0 {
+		return fmt.Errorf("%w: quota_scan_interval=%s", ErrInvalidConfigValue, c.QuotaScanInterval)
+	}
+	if _, ok := validColorModes[c.Color]; c.Color != "" && !ok {
+		return fmt.Errorf("%w: color=%s", ErrInvalidConfigValue, c.Color)
+	}
+	if err := c.Audit.validate(); err != nil {
+		return err
+	}
+	if err := validateLabels(c.Labels); err != nil {
+		return err
+	}
+	for bizName, level := range c.Levels {
+		if err := validateLogLevel(level); err != nil {
+			return fmt.Errorf("levels.%s: %w", bizName, err)
+		}
+	}
 
 	for i := range c.Outputs {
 		if err := c.Outputs[i].validateAt(i); err != nil {
 			return err
 		}
 	}
+	for i := range c.Routes {
+		if err := c.Routes[i].validateAt(i); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -446,6 +446,17 @@ func TestConfig_Validate_LogLevels(t *testing.T) {
 	}
 }
 
+func TestConfig_Validate_StacktraceLevel(t *testing.T) {
+	config := Config{StacktraceLevel: "error", Outputs: []OutputConfig{{Type: "console"}}}
+	assert.NoError(t, config.Validate())
+
+	config.StacktraceLevel = "bogus"
+	err := config.Validate()
+	require.Error(t, err)
+	assert.True(t, IsInvalidLogLevel(err))
+	assert.Contains(t, err.Error(), "stacktrace_level")
+}
+
 func TestConfig_Validate_EdgeCases(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -0,0 +1,212 @@
+package log
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime/debug"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// crashFileTimeLayout 是崩溃报告文件名中的时间格式，精确到纳秒以避免同一时刻的多个报告互相覆盖
+const crashFileTimeLayout = "20060102T150405.000000000Z"
+
+// crashEntry 是环形缓冲区中的一条日志
+type crashEntry struct {
+	biz  string
+	line string // JSON 编码的日志行，不含结尾换行
+}
+
+// crashRing 保存所有业务日志最近的 size 条日志，由 Config.CrashRing 启用，见 Manager.DumpCrash
+type crashRing struct {
+	mu      sync.Mutex
+	entries []crashEntry
+	next    int    // 下一条日志写入的位置
+	total   uint64 // 累计写入的日志条数
+}
+
+// newCrashRing 创建容量为 size 的环形缓冲区，size <= 0 时返回 nil
+func newCrashRing(size int) *crashRing {
+	if size <= 0 {
+		return nil
+	}
+	return &crashRing{entries: make([]crashEntry, size)}
+}
+
+// add 写入一条日志，缓冲区已满时覆盖最旧的一条
+func (r *crashRing) add(biz, line string) {
+	r.mu.Lock()
+	r.entries[r.next] = crashEntry{biz: biz, line: line}
+	r.next = (r.next + 1) % len(r.entries)
+	r.total++
+	r.mu.Unlock()
+}
+
+// snapshot 按写入顺序返回缓冲区中的日志，以及因缓冲区已满被覆盖的日志条数
+func (r *crashRing) snapshot() ([]crashEntry, uint64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	size := uint64(len(r.entries))
+	if r.total < size {
+		return append([]crashEntry(nil), r.entries[:r.total]...), 0
+	}
+	entries := make([]crashEntry, 0, size)
+	entries = append(entries, r.entries[r.next:]...)
+	entries = append(entries, r.entries[:r.next]...)
+	return entries, r.total - size
+}
+
+// ringCore 将日志编码后写入环形缓冲区，作为业务日志的一个输出，级别由外层的 levelCore 过滤
+type ringCore struct {
+	enc  zapcore.Encoder
+	biz  string
+	ring *crashRing
+}
+
+func (c *ringCore) Enabled(zapcore.Level) bool {
+	return true
+}
+
+func (c *ringCore) With(fields []zapcore.Field) zapcore.Core {
+	enc := c.enc.Clone()
+	for _, f := range fields {
+		f.AddTo(enc)
+	}
+	return &ringCore{enc: enc, biz: c.biz, ring: c.ring}
+}
+
+func (c *ringCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	return ce.AddCore(ent, c)
+}
+
+func (c *ringCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	buf, err := c.enc.EncodeEntry(ent, fields)
+	if err != nil {
+		return err
+	}
+	c.ring.add(c.biz, strings.TrimSuffix(buf.String(), "\n"))
+	buf.Free()
+	return nil
+}
+
+func (c *ringCore) Sync() error {
+	return nil
+}
+
+// DumpCrash 向 w 写入一份自包含的崩溃报告：时间、构建信息（二进制包含时）、panic 的值与堆栈，
+// 以及启用 Config.CrashRing 时所有业务日志最近的日志（按写入顺序，每行以业务名称开头）。
+// recovered 通常是 recover() 的返回值，stack 通常是 debug.Stack() 的返回值，为空时报告中注明没有堆栈。
+// nil Manager 只写入 panic 信息。
+func (m *Manager) DumpCrash(w io.Writer, recovered any, stack []byte) error {
+	var b strings.Builder
+	now := time.Now()
+	if m != nil {
+		now = m.clock().Now()
+	}
+	fmt.Fprintf(&b, "=== crash report ===\n")
+	fmt.Fprintf(&b, "time: %s\n", now.Format(time.RFC3339Nano))
+	if info, ok := debug.ReadBuildInfo(); ok {
+		fmt.Fprintf(&b, "go: %s\n", info.GoVersion)
+		fmt.Fprintf(&b, "path: %s\n", info.Path)
+		fmt.Fprintf(&b, "module: %s %s\n", info.Main.Path, info.Main.Version)
+		for _, s := range info.Settings {
+			if strings.HasPrefix(s.Key, "vcs.") {
+				fmt.Fprintf(&b, "%s: %s\n", s.Key, s.Value)
+			}
+		}
+	}
+	fmt.Fprintf(&b, "\npanic: %v [%T]\n", recovered, recovered)
+	if err, ok := recovered.(error); ok {
+		for unwrapped := errors.Unwrap(err); unwrapped != nil; unwrapped = errors.Unwrap(unwrapped) {
+			fmt.Fprintf(&b, "  caused by: %v\n", unwrapped)
+		}
+	}
+	fmt.Fprintf(&b, "\nstack:\n")
+	if len(stack) == 0 {
+		fmt.Fprintf(&b, "(no stack)\n")
+	} else {
+		b.Write(stack)
+		if stack[len(stack)-1] != '\n' {
+			b.WriteByte('\n')
+		}
+	}
+
+	switch {
+	case m == nil:
+	case m.ring == nil:
+		fmt.Fprintf(&b, "\nrecent logs: disabled (crash_ring is 0)\n")
+	default:
+		entries, dropped := m.ring.snapshot()
+		fmt.Fprintf(&b, "\nrecent logs: %d entries, %d earlier entries dropped\n", len(entries), dropped)
+		for _, e := range entries {
+			fmt.Fprintf(&b, "[%s] %s\n", e.biz, e.line)
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// WriteCrashFile 将 DumpCrash 的报告写入 dir 中名为 crash-<UTC 时间>.log 的新文件，目录不存在时创建，
+// 返回文件路径。dir 为空时使用 Config.CrashDir，两者都为空时返回 ErrEmptyLogDir
+func (m *Manager) WriteCrashFile(dir string, recovered any, stack []byte) (string, error) {
+	if dir == "" && m != nil {
+		dir = m.cfg.CrashDir
+	}
+	if dir == "" {
+		return "", ErrEmptyLogDir
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", err
+	}
+	now := time.Now()
+	if m != nil {
+		now = m.clock().Now()
+	}
+	path := filepath.Join(dir, "crash-"+now.UTC().Format(crashFileTimeLayout)+".log")
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	if err != nil {
+		return "", err
+	}
+	err = m.DumpCrash(f, recovered, stack)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	return path, err
+}
+
+// crashStderr 是 HandlePanic 输出报告的位置，测试中可以替换
+var crashStderr io.Writer = os.Stderr
+
+// HandlePanic 用于在 main 函数中 defer，为框架之外的 panic（例如 main 中的初始化代码）生成崩溃报告：
+//
+//	m := log.MustNewManager(cfg)
+//	defer log.HandlePanic(m)
+//
+// 发生 panic 时先刷新日志，将 DumpCrash 的报告写入标准错误输出，Config.CrashDir 不为空时同时写入其中的崩溃报告文件，
+// 然后以原来的值重新 panic，进程仍然以 panic 的方式退出。没有 panic 时不做任何事。
+// 它必须直接被 defer 调用，在其他函数中调用无法恢复 panic；只能恢复当前 goroutine 中的 panic。
+func HandlePanic(m *Manager) {
+	r := recover()
+	if r == nil {
+		return
+	}
+	stack := debug.Stack()
+	if m != nil {
+		_ = m.Sync()
+	}
+	_ = m.DumpCrash(crashStderr, r, stack)
+	if m != nil && m.cfg.CrashDir != "" {
+		if path, err := m.WriteCrashFile("", r, stack); err != nil {
+			fmt.Fprintf(crashStderr, "write crash file failed: %v\n", err)
+		} else {
+			fmt.Fprintf(crashStderr, "crash report written to %s\n", path)
+		}
+	}
+	panic(r)
+}
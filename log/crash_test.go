@@ -0,0 +1,191 @@
+package log
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/qq1060656096/drugo/pkg/clock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// newCrashManager 创建只有文件输出、启用崩溃日志缓冲区的 Manager
+func newCrashManager(t *testing.T, ring int) *Manager {
+	t.Helper()
+	m, err := NewManager(Config{
+		Level:     "info",
+		Outputs:   []OutputConfig{{Type: OutputTypeFile, Format: FormatJSON, File: &FileOutputConfig{Dir: t.TempDir()}}},
+		CrashRing: ring,
+		CrashDir:  t.TempDir(),
+		Clock:     clock.NewFake(time.Date(2026, 1, 2, 3, 4, 5, 6, time.UTC)),
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = m.Close() })
+	return m
+}
+
+// recentLogs 返回崩溃报告中 recent logs 之后的日志行
+func recentLogs(t *testing.T, report string) []string {
+	t.Helper()
+	_, logs, ok := strings.Cut(report, "\nrecent logs: ")
+	require.True(t, ok, report)
+	lines := strings.Split(strings.TrimSpace(logs), "\n")
+	return lines[1:]
+}
+
+// TestManager_DumpCrash 测试缓冲区写满后只保留最近的日志并按写入顺序输出，报告包含 panic 的值与堆栈
+func TestManager_DumpCrash(t *testing.T) {
+	m := newCrashManager(t, 5)
+	orders, payments := m.MustGet("orders"), m.MustGet("payments").With(zap.String("tenant", "t1"))
+	for i := range 8 {
+		if i%2 == 0 {
+			orders.Info(fmt.Sprintf("order %d", i))
+		} else {
+			payments.Info(fmt.Sprintf("payment %d", i))
+		}
+	}
+	orders.Debug("filtered by level")
+
+	var buf bytes.Buffer
+	cause := errors.New("nil map")
+	require.NoError(t, m.DumpCrash(&buf, fmt.Errorf("boom: %w", cause), []byte("goroutine 1 [running]:\nmain.main()")))
+	report := buf.String()
+
+	assert.True(t, strings.HasPrefix(report, "=== crash report ===\ntime: 2026-01-02T03:04:05.000000006Z\n"), report)
+	assert.Contains(t, report, "\npanic: boom: nil map [*fmt.wrapError]\n  caused by: nil map\n")
+	assert.Contains(t, report, "\nstack:\ngoroutine 1 [running]:\nmain.main()\n")
+	assert.Contains(t, report, "\nrecent logs: 5 entries, 3 earlier entries dropped\n")
+
+	lines := recentLogs(t, report)
+	require.Len(t, lines, 5)
+	for i, line := range lines {
+		n := i + 3
+		if n%2 == 0 {
+			assert.True(t, strings.HasPrefix(line, "[orders] {"), line)
+			assert.Contains(t, line, fmt.Sprintf(`"msg":"order %d"`, n))
+		} else {
+			assert.True(t, strings.HasPrefix(line, "[payments] {"), line)
+			assert.Contains(t, line, fmt.Sprintf(`"msg":"payment %d"`, n))
+			assert.Contains(t, line, `"tenant":"t1"`)
+		}
+	}
+	assert.NotContains(t, report, "filtered by level")
+}
+
+// TestManager_DumpCrash_PartialAndDisabled 测试缓冲区未写满、未启用缓冲区与 nil Manager 的报告
+func TestManager_DumpCrash_PartialAndDisabled(t *testing.T) {
+	m := newCrashManager(t, 10)
+	m.MustGet("app").Warn("first")
+	m.MustGet("app").Error("second")
+	var buf bytes.Buffer
+	require.NoError(t, m.DumpCrash(&buf, "oops", nil))
+	assert.Contains(t, buf.String(), "\npanic: oops [string]\n")
+	assert.Contains(t, buf.String(), "\nstack:\n(no stack)\n")
+	lines := recentLogs(t, buf.String())
+	require.Len(t, lines, 2)
+	assert.Contains(t, lines[0], `"msg":"first"`)
+	assert.Contains(t, lines[1], `"msg":"second"`)
+
+	disabled := newCrashManager(t, 0)
+	disabled.MustGet("app").Info("not kept")
+	buf.Reset()
+	require.NoError(t, disabled.DumpCrash(&buf, 42, []byte("stack")))
+	assert.Contains(t, buf.String(), "recent logs: disabled (crash_ring is 0)")
+	assert.NotContains(t, buf.String(), "not kept")
+
+	var nilManager *Manager
+	buf.Reset()
+	require.NoError(t, nilManager.DumpCrash(&buf, 42, []byte("stack")))
+	assert.Contains(t, buf.String(), "panic: 42 [int]")
+	assert.NotContains(t, buf.String(), "recent logs")
+
+	assert.True(t, IsInvalidConfigValue((&Config{Outputs: []OutputConfig{{Type: OutputTypeConsole}}, CrashRing: -1}).Validate()))
+}
+
+// TestManager_WriteCrashFile 测试崩溃报告文件的路径与内容
+func TestManager_WriteCrashFile(t *testing.T) {
+	m := newCrashManager(t, 3)
+	m.MustGet("app").Info("before crash")
+
+	path, err := m.WriteCrashFile("", "boom", []byte("stack"))
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(m.cfg.CrashDir, "crash-20260102T030405.000000006Z.log"), path)
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "panic: boom [string]")
+	assert.Contains(t, string(data), `"msg":"before crash"`)
+
+	// 同一时刻的第二份报告不覆盖第一份
+	_, err = m.WriteCrashFile("", "again", nil)
+	assert.ErrorIs(t, err, os.ErrExist)
+
+	dir := filepath.Join(t.TempDir(), "nested", "crash")
+	path, err = m.WriteCrashFile(dir, "boom", nil)
+	require.NoError(t, err)
+	assert.Equal(t, dir, filepath.Dir(path))
+
+	var nilManager *Manager
+	_, err = nilManager.WriteCrashFile("", "boom", nil)
+	assert.ErrorIs(t, err, ErrEmptyLogDir)
+}
+
+// TestHandlePanic 测试 HandlePanic 输出报告、写入报告文件并重新 panic
+func TestHandlePanic(t *testing.T) {
+	var stderr bytes.Buffer
+	orig := crashStderr
+	crashStderr = &stderr
+	t.Cleanup(func() { crashStderr = orig })
+
+	m := newCrashManager(t, 3)
+	m.MustGet("app").Info("starting")
+
+	assert.PanicsWithValue(t, "fatal init", func() {
+		defer HandlePanic(m)
+		panic("fatal init")
+	})
+	assert.Contains(t, stderr.String(), "panic: fatal init [string]")
+	assert.Contains(t, stderr.String(), "TestHandlePanic")
+	assert.Contains(t, stderr.String(), `"msg":"starting"`)
+	assert.Contains(t, stderr.String(), "crash report written to "+filepath.Join(m.cfg.CrashDir, "crash-"))
+
+	stderr.Reset()
+	assert.NotPanics(t, func() {
+		defer HandlePanic(m)
+	})
+	assert.Empty(t, stderr.String())
+
+	assert.PanicsWithValue(t, "no manager", func() {
+		defer HandlePanic(nil)
+		panic("no manager")
+	})
+	assert.Contains(t, stderr.String(), "panic: no manager [string]")
+}
+
+// BenchmarkManager_CrashRing 对比启用崩溃日志缓冲区前后 info 日志的写入开销
+func BenchmarkManager_CrashRing(b *testing.B) {
+	for _, ring := range []int{0, 1000} {
+		b.Run(fmt.Sprintf("ring=%d", ring), func(b *testing.B) {
+			m := MustNewManager(Config{
+				Level:     "info",
+				Outputs:   []OutputConfig{{Type: OutputTypeFile, Format: FormatJSON, File: &FileOutputConfig{Dir: b.TempDir()}}},
+				CrashRing: ring,
+			})
+			defer m.Close()
+			l := m.MustGet("bench")
+			b.ReportAllocs()
+			b.ResetTimer()
+			b.RunParallel(func(pb *testing.PB) {
+				for pb.Next() {
+					l.Info("request handled", zap.String("path", "/orders"), zap.Int("status", 200))
+				}
+			})
+		})
+	}
+}
@@ -0,0 +1,180 @@
+package log
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+// 日志配置项的来源，见 EnvOverrides.Sources
+const (
+	SourceFile = "file" // 来自配置文件（或代码中的默认值）
+	SourceEnv  = "env"  // 来自环境变量，Sources 中的值为 "env:<变量名>"
+)
+
+// EnvOverride 记录 Config.ApplyEnvOverrides 读取的一个环境变量
+type EnvOverride struct {
+	Var   string // 环境变量名称，例如 DRUGO_LOG_LEVEL
+	Field string // 覆盖的配置项: level、format、console、dir，或按业务覆盖级别时的 levels.<业务名称>
+	Value string // 环境变量的值
+	Err   error  // 值无效时的原因，此时配置没有被修改
+}
+
+// EnvOverrides 是 Config.ApplyEnvOverrides 读取的环境变量列表
+type EnvOverrides []EnvOverride
+
+// Invalid 返回值无效、被忽略的环境变量
+func (o EnvOverrides) Invalid() EnvOverrides {
+	var invalid EnvOverrides
+	for _, e := range o {
+		if e.Err != nil {
+			invalid = append(invalid, e)
+		}
+	}
+	return invalid
+}
+
+// Sources 返回可被环境变量覆盖的配置项的来源：level、format、console、dir 默认为 SourceFile，
+// 被环境变量覆盖时为 "env:<变量名>"；按业务覆盖的级别以 levels.<业务名称> 为键
+func (o EnvOverrides) Sources() map[string]string {
+	sources := map[string]string{"level": SourceFile, "format": SourceFile, "console": SourceFile, "dir": SourceFile}
+	for _, e := range o {
+		if e.Err == nil {
+			sources[e.Field] = SourceEnv + ":" + e.Var
+		}
+	}
+	return sources
+}
+
+// ApplyEnvOverrides 使用环境变量覆盖配置，用于不修改镜像中的配置文件临时调整日志，例如排查问题时重启为 debug 级别：
+//   - <prefix>_LEVEL 覆盖 Level
+//   - <prefix>_FORMAT 覆盖所有输出的 Format（json 或 text）
+//   - <prefix>_CONSOLE 为 true 时在没有控制台输出时添加一个，为 false 时移除所有控制台输出（不能移除全部输出）
+//   - <prefix>_DIR 覆盖所有文件输出的目录
+//   - <prefix>_LEVEL_<业务名称> 固定该业务的级别（见 Config.Levels），业务名称不区分大小写，
+//     其中的 "-"、"." 等字符写作 "_"，例如 DRUGO_LOG_LEVEL_GIN=debug
+//
+// 未设置或为空的环境变量不生效。值无效时不修改配置，错误记录在返回值对应项的 Err 中：
+// 应急开关应当宽容，调用方通常只记录警告而不是启动失败。返回读取的所有环境变量，按变量名称排序
+func (c *Config) ApplyEnvOverrides(prefix string) EnvOverrides {
+	var overrides EnvOverrides
+	apply := func(name, field string, fn func(value string) error) {
+		value := strings.TrimSpace(os.Getenv(name))
+		if value == "" {
+			return
+		}
+		overrides = append(overrides, EnvOverride{Var: name, Field: field, Value: value, Err: fn(value)})
+	}
+
+	apply(prefix+"_LEVEL", "level", func(value string) error {
+		level := strings.ToLower(value)
+		if _, err := zap.ParseAtomicLevel(level); err != nil {
+			return fmt.Errorf("%w: %s", ErrInvalidLogLevel, value)
+		}
+		c.Level = level
+		return nil
+	})
+	apply(prefix+"_FORMAT", "format", func(value string) error {
+		format := strings.ToLower(value)
+		if !isValidOutputFormat(format) {
+			return fmt.Errorf("%w: %s", ErrInvalidLogFormat, value)
+		}
+		for i := range c.Outputs {
+			c.Outputs[i].Format = format
+		}
+		return nil
+	})
+	apply(prefix+"_CONSOLE", "console", func(value string) error {
+		enable, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("%w: console=%s", ErrInvalidConfigValue, value)
+		}
+		return c.setConsole(enable)
+	})
+	apply(prefix+"_DIR", "dir", func(value string) error {
+		found := false
+		for i := range c.Outputs {
+			if out := &c.Outputs[i]; out.Type == OutputTypeFile && out.File != nil {
+				out.File.Dir = value
+				found = true
+			}
+		}
+		if !found {
+			return ErrNoFileOutput
+		}
+		return nil
+	})
+
+	levelPrefix := prefix + "_LEVEL_"
+	var names []string
+	for _, kv := range os.Environ() {
+		if name, _, ok := strings.Cut(kv, "="); ok && strings.HasPrefix(name, levelPrefix) && len(name) > len(levelPrefix) {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+	for _, name := range names {
+		bizKey := envBizKey(name[len(levelPrefix):])
+		apply(name, "levels."+bizKey, func(value string) error {
+			level := strings.ToLower(value)
+			if _, err := zap.ParseAtomicLevel(level); err != nil {
+				return fmt.Errorf("%w: %s", ErrInvalidLogLevel, value)
+			}
+			if c.Levels == nil {
+				c.Levels = make(map[string]string)
+			}
+			c.Levels[bizKey] = level
+			return nil
+		})
+	}
+
+	slices.SortFunc(overrides, func(a, b EnvOverride) int { return strings.Compare(a.Var, b.Var) })
+	return overrides
+}
+
+// setConsole 添加或移除控制台输出，新增的控制台输出使用已有输出的格式
+func (c *Config) setConsole(enable bool) error {
+	hasConsole := slices.ContainsFunc(c.Outputs, func(o OutputConfig) bool { return o.Type == OutputTypeConsole })
+	if enable {
+		if !hasConsole {
+			format := FormatText
+			if len(c.Outputs) > 0 && c.Outputs[0].Format != "" {
+				format = c.Outputs[0].Format
+			}
+			c.Outputs = append(c.Outputs, OutputConfig{Type: OutputTypeConsole, Format: format})
+		}
+		return nil
+	}
+	outputs := slices.DeleteFunc(slices.Clone(c.Outputs), func(o OutputConfig) bool { return o.Type == OutputTypeConsole })
+	if len(outputs) == 0 {
+		return fmt.Errorf("%w: console=false would remove every output", ErrEmptyLogOutputs)
+	}
+	c.Outputs = outputs
+	return nil
+}
+
+// envBizKey 返回业务名称在环境变量中的形式（小写，字母与数字之外的字符替换为 "_"），用于匹配 Config.Levels
+func envBizKey(bizName string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+			return r
+		case r >= 'A' && r <= 'Z':
+			return r + 'a' - 'A'
+		}
+		return '_'
+	}, bizName)
+}
+
+// levelFor 返回 Config.Levels 为 bizName 固定的级别，先按原名称查找，再按 envBizKey 的形式查找
+func (c Config) levelFor(bizName string) (string, bool) {
+	if level, ok := c.Levels[bizName]; ok {
+		return level, true
+	}
+	level, ok := c.Levels[envBizKey(bizName)]
+	return level, ok
+}
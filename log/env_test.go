@@ -0,0 +1,126 @@
+package log
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// envTestConfig 返回包含 json 文件输出的配置，模拟配置文件中的值
+func envTestConfig() Config {
+	return Config{
+		Level:   "warn",
+		Outputs: []OutputConfig{{Type: OutputTypeFile, Format: FormatJSON, File: &FileOutputConfig{Dir: "/var/log/app"}}},
+	}
+}
+
+// TestConfig_ApplyEnvOverrides 测试环境变量覆盖配置文件中的值
+func TestConfig_ApplyEnvOverrides(t *testing.T) {
+	t.Setenv("TEST_LOG_LEVEL", "DEBUG")
+	t.Setenv("TEST_LOG_FORMAT", "text")
+	t.Setenv("TEST_LOG_CONSOLE", "true")
+	t.Setenv("TEST_LOG_DIR", "/tmp/debug-logs")
+
+	cfg := envTestConfig()
+	overrides := cfg.ApplyEnvOverrides("TEST_LOG")
+	assert.Empty(t, overrides.Invalid())
+	assert.Equal(t, []string{"TEST_LOG_CONSOLE", "TEST_LOG_DIR", "TEST_LOG_FORMAT", "TEST_LOG_LEVEL"},
+		[]string{overrides[0].Var, overrides[1].Var, overrides[2].Var, overrides[3].Var})
+
+	assert.Equal(t, "debug", cfg.Level)
+	require.Len(t, cfg.Outputs, 2)
+	assert.Equal(t, "/tmp/debug-logs", cfg.Outputs[0].File.Dir)
+	assert.Equal(t, FormatText, cfg.Outputs[0].Format)
+	assert.Equal(t, OutputConfig{Type: OutputTypeConsole, Format: FormatText}, cfg.Outputs[1])
+	assert.NoError(t, cfg.Validate())
+
+	assert.Equal(t, map[string]string{
+		"level":   "env:TEST_LOG_LEVEL",
+		"format":  "env:TEST_LOG_FORMAT",
+		"console": "env:TEST_LOG_CONSOLE",
+		"dir":     "env:TEST_LOG_DIR",
+	}, overrides.Sources())
+
+	// 没有设置环境变量时配置不变，所有来源都是配置文件
+	cfg = envTestConfig()
+	overrides = cfg.ApplyEnvOverrides("OTHER_LOG")
+	assert.Empty(t, overrides)
+	assert.Equal(t, envTestConfig(), cfg)
+	assert.Equal(t, map[string]string{"level": SourceFile, "format": SourceFile, "console": SourceFile, "dir": SourceFile}, overrides.Sources())
+}
+
+// TestConfig_ApplyEnvOverrides_Invalid 测试无效的值被忽略并记录原因，不影响其他环境变量
+func TestConfig_ApplyEnvOverrides_Invalid(t *testing.T) {
+	t.Setenv("TEST_LOG_LEVEL", "verbose")
+	t.Setenv("TEST_LOG_FORMAT", "xml")
+	t.Setenv("TEST_LOG_CONSOLE", "false")
+	t.Setenv("TEST_LOG_LEVEL_GIN", "loud")
+	t.Setenv("TEST_LOG_LEVEL_ORDERS", "error")
+
+	cfg := envTestConfig()
+	cfg.Outputs = []OutputConfig{{Type: OutputTypeConsole, Format: FormatJSON}}
+	overrides := cfg.ApplyEnvOverrides("TEST_LOG")
+	invalid := overrides.Invalid()
+	require.Len(t, invalid, 4)
+	assert.True(t, IsEmptyLogOutputs(invalid[0].Err), "console=false would remove the only output")
+	assert.True(t, IsInvalidLogFormat(invalid[1].Err))
+	assert.True(t, IsInvalidLogLevel(invalid[2].Err))
+	assert.Equal(t, "TEST_LOG_LEVEL_GIN", invalid[3].Var)
+	assert.True(t, IsInvalidLogLevel(invalid[3].Err))
+
+	assert.Equal(t, "warn", cfg.Level)
+	assert.Equal(t, []OutputConfig{{Type: OutputTypeConsole, Format: FormatJSON}}, cfg.Outputs)
+	assert.Equal(t, map[string]string{"orders": "error"}, cfg.Levels)
+	sources := overrides.Sources()
+	assert.Equal(t, SourceFile, sources["level"])
+	assert.Equal(t, "env:TEST_LOG_LEVEL_ORDERS", sources["levels.orders"])
+	assert.NotContains(t, sources, "levels.gin")
+
+	// 没有文件输出时 DIR 无效
+	t.Setenv("TEST_LOG_DIR", "/tmp/logs")
+	cfg = Config{Outputs: []OutputConfig{{Type: OutputTypeConsole}}}
+	for _, o := range cfg.ApplyEnvOverrides("TEST_LOG").Invalid() {
+		if o.Var == "TEST_LOG_DIR" {
+			assert.ErrorIs(t, o.Err, ErrNoFileOutput)
+		}
+	}
+}
+
+// TestManager_EnvBizLevel 测试按业务覆盖的级别在 Get 时固定，优先于全局级别
+func TestManager_EnvBizLevel(t *testing.T) {
+	t.Setenv("TEST_LOG_LEVEL", "error")
+	t.Setenv("TEST_LOG_LEVEL_GIN", "debug")
+	t.Setenv("TEST_LOG_LEVEL_TENANT_ACME", "warn")
+
+	cfg := Config{Outputs: []OutputConfig{{Type: OutputTypeConsole}}}
+	cfg.ApplyEnvOverrides("TEST_LOG")
+	m, err := NewManager(cfg)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = m.Close() })
+
+	m.MustGet("gin")
+	m.MustGet("tenant-acme")
+	m.MustGet("orders")
+	for biz, want := range map[string]struct {
+		level  string
+		pinned bool
+	}{
+		"gin":         {"debug", true},
+		"tenant-acme": {"warn", true},
+		"orders":      {"error", false},
+	} {
+		level, pinned, err := m.LevelInfo(biz)
+		require.NoError(t, err)
+		assert.Equal(t, want.level, level, biz)
+		assert.Equal(t, want.pinned, pinned, biz)
+	}
+
+	// 固定的级别不受默认级别影响
+	require.NoError(t, m.SetDefaultLevel("info"))
+	level, _ := m.GetLevel("gin")
+	assert.Equal(t, "debug", level)
+
+	_, err = NewManager(Config{Outputs: cfg.Outputs, Levels: map[string]string{"gin": "loud"}})
+	assert.True(t, IsInvalidLogLevel(err))
+}
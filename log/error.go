@@ -22,6 +22,10 @@ var (
 	ErrInvalidOutputType = errors.New("invalid log output type")
 	// ErrLoggerNotFound logger 不存在错误
 	ErrLoggerNotFound = errors.New("logger not found")
+	// ErrNoFileOutput 没有配置文件输出错误
+	ErrNoFileOutput = errors.New("log has no file output")
+	// ErrAuditClosed 审计日志已关闭错误
+	ErrAuditClosed = errors.New("audit logger is closed")
 )
 
 // IsInvalidLogLevel 检查是否为无效日志级别错误
@@ -68,3 +72,13 @@ func IsInvalidOutputType(err error) bool {
 func IsLoggerNotFound(err error) bool {
 	return errors.Is(err, ErrLoggerNotFound)
 }
+
+// IsNoFileOutput 检查是否为没有配置文件输出错误
+func IsNoFileOutput(err error) bool {
+	return errors.Is(err, ErrNoFileOutput)
+}
+
+// IsAuditClosed 检查是否为审计日志已关闭错误
+func IsAuditClosed(err error) bool {
+	return errors.Is(err, ErrAuditClosed)
+}
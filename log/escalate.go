@@ -0,0 +1,111 @@
+package log
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// allLevels 放行所有级别，输出核心只负责分流，级别由 levelCore 统一过滤
+var allLevels = zap.LevelEnablerFunc(func(zapcore.Level) bool { return true })
+
+// levelCore 按 level 过滤日志条目，再交给内部的输出核心写入。
+// 放宽级别时只替换 level，内部核心与字段保持不变。
+type levelCore struct {
+	zapcore.Core
+	level zapcore.LevelEnabler
+}
+
+// Enabled 实现 zapcore.LevelEnabler
+func (c *levelCore) Enabled(lvl zapcore.Level) bool {
+	return c.level.Enabled(lvl)
+}
+
+// Level 返回当前生效的最低级别，供 zapcore.LevelOf 使用
+func (c *levelCore) Level() zapcore.Level {
+	return zapcore.LevelOf(c.level)
+}
+
+// With 添加字段，返回的核心保留同一个级别控制器
+func (c *levelCore) With(fields []zapcore.Field) zapcore.Core {
+	return &levelCore{Core: c.Core.With(fields), level: c.level}
+}
+
+// Check 级别未启用时直接跳过，否则交给内部核心
+func (c *levelCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if !c.Enabled(ent.Level) {
+		return ce
+	}
+	return c.Core.Check(ent, ce)
+}
+
+// escalatedLevel 在原级别之外额外放行不低于 min 的条目
+type escalatedLevel struct {
+	base zapcore.LevelEnabler
+	min  zapcore.Level
+}
+
+// Enabled 实现 zapcore.LevelEnabler
+func (l escalatedLevel) Enabled(lvl zapcore.Level) bool {
+	return lvl >= l.min || l.base.Enabled(lvl)
+}
+
+// Level 返回两者中较低的级别
+func (l escalatedLevel) Level() zapcore.Level {
+	return min(l.min, zapcore.LevelOf(l.base))
+}
+
+type minLevelCtxKey struct{}
+
+// WithMinLevel 返回携带请求级最低日志级别的上下文。
+// 通过 Manager.For 或 Escalate 获取的日志实例会输出不低于 level 的条目，即使配置的级别更高；
+// 只影响基于该上下文获取的日志实例，不会修改 Manager 的级别。
+func WithMinLevel(ctx context.Context, level zapcore.Level) context.Context {
+	return context.WithValue(ctx, minLevelCtxKey{}, level)
+}
+
+// MinLevelFromContext 返回 WithMinLevel 设置的级别
+func MinLevelFromContext(ctx context.Context) (zapcore.Level, bool) {
+	if ctx == nil {
+		return zapcore.InvalidLevel, false
+	}
+	lvl, ok := ctx.Value(minLevelCtxKey{}).(zapcore.Level)
+	return lvl, ok
+}
+
+// Escalate 按上下文中的 WithMinLevel 放宽日志实例的级别。
+// 上下文没有设置级别、级别已经启用，或 logger 不是 Manager 创建的实例时，原样返回 logger。
+func Escalate(ctx context.Context, logger *zap.Logger) *zap.Logger {
+	lvl, ok := MinLevelFromContext(ctx)
+	if !ok || logger == nil || logger.Core().Enabled(lvl) {
+		return logger
+	}
+	if _, ok := logger.Core().(*levelCore); !ok {
+		return logger
+	}
+	return logger.WithOptions(zap.WrapCore(func(c zapcore.Core) zapcore.Core {
+		lc, ok := c.(*levelCore)
+		if !ok {
+			return c
+		}
+		return &levelCore{Core: lc.Core, level: escalatedLevel{base: lc.level, min: lvl}}
+	}))
+}
+
+// For 获取指定业务名称的日志实例，并按上下文中的 WithMinLevel 放宽级别。
+// Config.Trace 为 true 且上下文中有有效的 span 时（见 SetTraceExtractor），添加 trace_id 与 span_id 字段。
+// 上下文没有设置级别也没有 span 时直接返回缓存的实例，与 Get 相同。
+// ctx: 请求上下文
+// bizName: 业务名称
+// 返回: zap日志实例和可能的错误
+func (m *Manager) For(ctx context.Context, bizName string) (*zap.Logger, error) {
+	l, err := m.Get(bizName)
+	if err != nil {
+		return nil, err
+	}
+	if m.cfg.Trace {
+		l = WithTrace(ctx, l)
+	}
+	return Escalate(ctx, l), nil
+}
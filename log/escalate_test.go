@@ -0,0 +1,98 @@
+package log
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// TestManager_For 测试携带请求级别的上下文输出低于配置级别的条目，普通上下文不受影响
+func TestManager_For(t *testing.T) {
+	dir := t.TempDir()
+	m, err := NewManager(Config{
+		Level:   "info",
+		Outputs: []OutputConfig{{Type: OutputTypeFile, Format: FormatJSON, File: &FileOutputConfig{Dir: dir}}},
+	})
+	require.NoError(t, err)
+	defer m.Close()
+
+	ctx := context.Background()
+	escalated := WithMinLevel(ctx, zapcore.DebugLevel)
+
+	normal, err := m.For(ctx, "order")
+	require.NoError(t, err)
+	assert.Same(t, m.MustGet("order"), normal, "没有设置级别时返回缓存的实例")
+
+	debug, err := m.For(escalated, "order")
+	require.NoError(t, err)
+	assert.True(t, debug.Core().Enabled(zapcore.DebugLevel))
+	assert.Equal(t, zapcore.DebugLevel, zapcore.LevelOf(debug.Core()))
+
+	normal.Debug("normal debug")
+	debug.Debug("escalated debug")
+	debug.Info("escalated info")
+	require.NoError(t, m.Sync())
+
+	entries := readLogEntries(t, filepath.Join(dir, "order.log"))
+	require.Len(t, entries, 2)
+	assert.Equal(t, "escalated debug", entries[0]["msg"])
+	assert.Equal(t, "debug", entries[0]["level"])
+	assert.Equal(t, "order", entries[0]["biz"])
+	assert.Equal(t, "escalated info", entries[1]["msg"])
+
+	// 共享级别不受影响
+	assert.Equal(t, "info", m.DefaultLevel())
+	assert.False(t, m.MustGet("order").Core().Enabled(zapcore.DebugLevel))
+}
+
+// TestManager_For_Errors 测试 For 的错误与无需放宽的情况
+func TestManager_For_Errors(t *testing.T) {
+	m, err := NewManager(Config{Level: "warn", Outputs: []OutputConfig{{Type: OutputTypeConsole}}})
+	require.NoError(t, err)
+
+	_, err = m.For(context.Background(), "")
+	assert.ErrorIs(t, err, ErrEmptyBizName)
+
+	// 请求级别高于配置级别时返回缓存的实例
+	l, err := m.For(WithMinLevel(context.Background(), zapcore.ErrorLevel), "app")
+	require.NoError(t, err)
+	assert.Same(t, m.MustGet("app"), l)
+
+	// 放宽后的实例继续跟随 Manager 的级别变化
+	l, err = m.For(WithMinLevel(context.Background(), zapcore.InfoLevel), "app")
+	require.NoError(t, err)
+	assert.False(t, l.Core().Enabled(zapcore.DebugLevel))
+	require.NoError(t, m.SetDefaultLevel("debug"))
+	assert.True(t, l.Core().Enabled(zapcore.DebugLevel))
+}
+
+// TestEscalate 测试非 Manager 创建的日志实例原样返回
+func TestEscalate(t *testing.T) {
+	ctx := WithMinLevel(context.Background(), zapcore.DebugLevel)
+	nop := zap.NewNop()
+	assert.Same(t, nop, Escalate(ctx, nop))
+	assert.Nil(t, Escalate(ctx, nil))
+
+	lvl, ok := MinLevelFromContext(ctx)
+	assert.True(t, ok)
+	assert.Equal(t, zapcore.DebugLevel, lvl)
+	_, ok = MinLevelFromContext(context.Background())
+	assert.False(t, ok)
+}
+
+// BenchmarkManager_For 测试没有设置请求级别时 For 的开销
+func BenchmarkManager_For(b *testing.B) {
+	m := MustNewManager(Config{Level: "info", Outputs: []OutputConfig{{Type: OutputTypeConsole}}})
+	ctx := context.Background()
+	m.MustGet("app")
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = m.For(ctx, "app")
+	}
+}
@@ -0,0 +1,137 @@
+package log
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// teeCore 将日志条目依次写入所有输出。
+//
+// 与 zapcore.NewTee 不同，同一个日志实例（包括 With 派生的实例）的写入由同一把锁串行化：
+// 一条日志写入所有输出之后才会写入下一条，因此控制台与文件中的日志顺序一致，
+// 不会出现控制台已经输出后一条而文件中还缺少前一条的情况。
+// 写入采用尽力而为的语义：某个输出写入失败不会跳过其他输出，所有输出的错误合并后返回。
+type teeCore struct {
+	cores []zapcore.Core
+	mu    *sync.Mutex
+}
+
+func newTeeCore(cores ...zapcore.Core) *teeCore {
+	return &teeCore{cores: cores, mu: &sync.Mutex{}}
+}
+
+func (t *teeCore) Enabled(lvl zapcore.Level) bool {
+	for _, c := range t.cores {
+		if c.Enabled(lvl) {
+			return true
+		}
+	}
+	return false
+}
+
+func (t *teeCore) With(fields []zapcore.Field) zapcore.Core {
+	cores := make([]zapcore.Core, len(t.cores))
+	for i, c := range t.cores {
+		cores[i] = c.With(fields)
+	}
+	return &teeCore{cores: cores, mu: t.mu}
+}
+
+func (t *teeCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if t.Enabled(ent.Level) {
+		return ce.AddCore(ent, t)
+	}
+	return ce
+}
+
+// Write 按配置顺序写入所有接受该级别的输出，返回合并后的错误
+func (t *teeCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var errs []error
+	for _, c := range t.cores {
+		if !c.Enabled(ent.Level) {
+			continue
+		}
+		if err := c.Write(ent, fields); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Sync 同步所有输出，返回合并后的错误
+func (t *teeCore) Sync() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var errs []error
+	for _, c := range t.cores {
+		if err := c.Sync(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// consoleSyncer 同步控制台输出但忽略其错误：
+// 标准输出为终端或管道时 fsync 通常返回 EINVAL，这并不代表日志丢失。
+type consoleSyncer struct {
+	zapcore.WriteSyncer
+}
+
+func (w consoleSyncer) Sync() error {
+	_ = w.WriteSyncer.Sync()
+	return nil
+}
+
+// Flush 同步刷新所有日志实例的所有输出：文件输出调用 Sync 落盘，控制台输出同样会尝试同步但忽略其错误。
+// 返回 nil 时，Flush 之前写入的日志都已经交给操作系统并完成同步，可以直接读取日志文件进行断言。
+//
+// ctx 到期时 Flush 立即返回 ctx 的错误（与已经得到的同步错误合并），未完成的同步在后台继续执行。
+// 各业务的同步错误以 "flush logger '<biz>'" 为前缀合并返回。nil Manager 没有需要刷新的输出，直接返回 nil。
+func (m *Manager) Flush(ctx context.Context) error {
+	if m == nil {
+		return nil
+	}
+
+	m.mu.RLock()
+	names := make([]string, 0, len(m.loggers))
+	loggers := make(map[string]*zap.Logger, len(m.loggers))
+	for name, l := range m.loggers {
+		names = append(names, name)
+		loggers[name] = l
+	}
+	m.mu.RUnlock()
+	sort.Strings(names)
+
+	var mu sync.Mutex
+	var errs []error
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for _, name := range names {
+			if err := loggers[name].Sync(); err != nil {
+				mu.Lock()
+				errs = append(errs, fmt.Errorf("flush logger '%s': %w", name, err))
+				mu.Unlock()
+			}
+		}
+	}()
+
+	select {
+	case <-done:
+		return errors.Join(errs...)
+	case <-ctx.Done():
+		mu.Lock()
+		defer mu.Unlock()
+		return errors.Join(append([]error{ctx.Err()}, errs...)...)
+	}
+}
@@ -0,0 +1,152 @@
+package log
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+	"gopkg.in/natefinch/lumberjack.v2"
+)
+
+// TestManager_Flush 测试 Flush 返回后无需等待即可读取到所有日志，且顺序与写入顺序一致
+func TestManager_Flush(t *testing.T) {
+	dir := t.TempDir()
+	m, err := NewManager(Config{
+		Level: "info",
+		Outputs: []OutputConfig{
+			{Type: OutputTypeConsole},
+			{Type: OutputTypeFile, Format: FormatJSON, File: &FileOutputConfig{Dir: dir}},
+		},
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = m.Close() })
+
+	order := m.MustGet("order")
+	user := m.MustGet("user")
+	for i := range 3 {
+		order.Info("order entry", zap.Int("i", i))
+	}
+	user.Error("user entry")
+
+	require.NoError(t, m.Flush(context.Background()))
+
+	data, err := os.ReadFile(filepath.Join(dir, "order.log"))
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	require.Len(t, lines, 3)
+	for i, line := range lines {
+		assert.Contains(t, line, fmt.Sprintf(`"i":%d`, i))
+	}
+	data, err = os.ReadFile(filepath.Join(dir, "user.log"))
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "user entry")
+
+	var nilManager *Manager
+	assert.NoError(t, nilManager.Flush(context.Background()))
+}
+
+// TestManager_Flush_SinkFailure 测试一个文件输出失败时另一个输出仍然收到日志，Flush 按业务返回同步错误
+func TestManager_Flush_SinkFailure(t *testing.T) {
+	broken, healthy := t.TempDir(), t.TempDir()
+	sink := &failingSink{}
+	sink.failing.Store(true)
+	orig := newFileSink
+	newFileSink = func(file *lumberjack.Logger) zapcore.WriteSyncer {
+		if filepath.Dir(file.Filename) == broken {
+			return sink
+		}
+		return orig(file)
+	}
+	t.Cleanup(func() { newFileSink = orig })
+
+	m, err := NewManager(Config{
+		Outputs: []OutputConfig{
+			{Type: OutputTypeFile, File: &FileOutputConfig{Dir: broken}},
+			{Type: OutputTypeFile, File: &FileOutputConfig{Dir: healthy}},
+		},
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = m.Close() })
+
+	m.MustGet("order").Error("still delivered")
+	err = m.Flush(context.Background())
+	assert.ErrorIs(t, err, errDiskFull)
+	assert.ErrorContains(t, err, "flush logger 'order'")
+
+	data, err := os.ReadFile(filepath.Join(healthy, "order.log"))
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "still delivered")
+	assert.ErrorIs(t, m.WriteErrors()["order"].LastErr, errDiskFull)
+}
+
+// blockingSink 的 Sync 阻塞直到 release 关闭
+type blockingSink struct {
+	release chan struct{}
+}
+
+func (s *blockingSink) Write(p []byte) (int, error) { return len(p), nil }
+
+func (s *blockingSink) Sync() error {
+	<-s.release
+	return nil
+}
+
+// TestManager_Flush_Deadline 测试同步未在 ctx 截止时间内完成时返回 ctx 的错误
+func TestManager_Flush_Deadline(t *testing.T) {
+	sink := &blockingSink{release: make(chan struct{})}
+	orig := newFileSink
+	newFileSink = func(*lumberjack.Logger) zapcore.WriteSyncer { return sink }
+	t.Cleanup(func() { newFileSink = orig })
+
+	m, err := NewManager(Config{Outputs: []OutputConfig{{Type: OutputTypeFile, File: &FileOutputConfig{Dir: t.TempDir()}}}})
+	require.NoError(t, err)
+	m.MustGet("order").Info("pending")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, m.Flush(ctx), context.DeadlineExceeded)
+	close(sink.release)
+}
+
+// errorCore 是写入总是失败的 zapcore.Core
+type errorCore struct {
+	zapcore.LevelEnabler
+}
+
+func (c errorCore) With([]zapcore.Field) zapcore.Core { return c }
+func (c errorCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	return ce.AddCore(ent, c)
+}
+func (c errorCore) Write(zapcore.Entry, []zapcore.Field) error { return errDiskFull }
+func (c errorCore) Sync() error                                { return errDiskFull }
+
+// TestTeeCore 测试写入失败的输出不影响其他输出、错误合并返回，以及按输出级别分流
+func TestTeeCore(t *testing.T) {
+	first, firstLogs := observer.New(zapcore.InfoLevel)
+	last, lastLogs := observer.New(zapcore.ErrorLevel)
+	tee := newTeeCore(first, errorCore{zapcore.DebugLevel}, last)
+
+	ent := zapcore.Entry{Level: zapcore.ErrorLevel, Message: "boom"}
+	err := tee.With([]zapcore.Field{zap.String("k", "v")}).Write(ent, nil)
+	assert.ErrorIs(t, err, errDiskFull)
+	require.Equal(t, 1, firstLogs.Len())
+	require.Equal(t, 1, lastLogs.Len(), "a failing sink must not skip the sinks after it")
+	assert.Equal(t, map[string]any{"k": "v"}, lastLogs.All()[0].ContextMap())
+
+	assert.NoError(t, newTeeCore(first, last).Write(zapcore.Entry{Level: zapcore.InfoLevel, Message: "info"}, nil))
+	assert.Equal(t, 2, firstLogs.Len())
+	assert.Equal(t, 1, lastLogs.Len(), "entries below a sink's level are not written to it")
+
+	assert.True(t, tee.Enabled(zapcore.DebugLevel))
+	assert.False(t, newTeeCore(first, last).Enabled(zapcore.DebugLevel))
+	assert.ErrorIs(t, tee.Sync(), errDiskFull)
+}
@@ -0,0 +1,83 @@
+package log
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+
+	"go.uber.org/zap"
+)
+
+// LabelsWildcard 是 Config.Labels 中应用于所有业务的键
+const LabelsWildcard = "*"
+
+// validateLabels 校验业务名称与字段名称不为空，并且字段不能覆盖每条日志都有的 biz 字段
+func validateLabels(labels map[string]map[string]string) error {
+	for bizName, set := range labels {
+		if bizName == "" {
+			return fmt.Errorf("%w: labels: empty biz name", ErrInvalidConfigValue)
+		}
+		for key := range set {
+			if key == "" {
+				return fmt.Errorf("%w: labels.%s: empty key", ErrInvalidConfigValue, bizName)
+			}
+			if key == "biz" {
+				return fmt.Errorf("%w: labels.%s.biz: reserved key", ErrInvalidConfigValue, bizName)
+			}
+		}
+	}
+	return nil
+}
+
+// labelsFor 返回 bizName 生效的静态字段：LabelsWildcard 的字段与 bizName 的字段合并，同名时以 bizName 为准
+func (c Config) labelsFor(bizName string) map[string]string {
+	wildcard, specific := c.Labels[LabelsWildcard], c.Labels[bizName]
+	if len(wildcard) == 0 && len(specific) == 0 {
+		return nil
+	}
+	labels := make(map[string]string, len(wildcard)+len(specific))
+	maps.Copy(labels, wildcard)
+	maps.Copy(labels, specific)
+	return labels
+}
+
+// labelFields 将 bizName 生效的静态字段按名称排序转换为 zap 字段
+func (c Config) labelFields(bizName string) []zap.Field {
+	labels := c.labelsFor(bizName)
+	fields := make([]zap.Field, 0, len(labels))
+	for _, key := range slices.Sorted(maps.Keys(labels)) {
+		fields = append(fields, zap.String(key, labels[key]))
+	}
+	return fields
+}
+
+// LabelsFor 返回 bizName 的日志实例添加的静态字段（见 Config.Labels），没有字段时返回 nil。
+// 返回的 map 是副本
+func (m *Manager) LabelsFor(bizName string) map[string]string {
+	if m == nil {
+		return nil
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.cfg.labelsFor(bizName)
+}
+
+// SetLabels 替换 Config.Labels，之后新创建的日志实例使用新的静态字段。
+// zap 的字段在创建时固定，已经创建的日志实例保持原来的字段，可以先调用 Remove 使其在下次 Get 时重新创建。
+// labels 无效时返回 ErrInvalidConfigValue，配置不变
+func (m *Manager) SetLabels(labels map[string]map[string]string) error {
+	if m == nil {
+		return ErrNilManager
+	}
+	if err := validateLabels(labels); err != nil {
+		return err
+	}
+	cloned := make(map[string]map[string]string, len(labels))
+	for bizName, set := range labels {
+		cloned[bizName] = maps.Clone(set)
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.cfg.Labels = cloned
+	return nil
+}
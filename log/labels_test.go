@@ -0,0 +1,93 @@
+package log
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// readJSONLine 读取 dir 中 bizName 的日志文件并解析最后一行
+func readJSONLine(t *testing.T, dir, bizName string) map[string]any {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join(dir, bizName+".log"))
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	var entry map[string]any
+	require.NoError(t, json.Unmarshal([]byte(lines[len(lines)-1]), &entry))
+	return entry
+}
+
+// TestManager_Labels 测试带静态字段与不带静态字段的业务日志，以及具体业务的字段覆盖通配字段
+func TestManager_Labels(t *testing.T) {
+	dir := t.TempDir()
+	m, err := NewManager(Config{
+		Outputs: []OutputConfig{{Type: OutputTypeFile, Format: FormatJSON, File: &FileOutputConfig{Dir: dir}}},
+		Labels: map[string]map[string]string{
+			"payments": {"team": "payments", "pci": "true"},
+		},
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = m.Close() })
+
+	m.MustGet("payments").Info("charged")
+	m.MustGet("orders").Info("created")
+	require.NoError(t, m.Flush(context.Background()))
+
+	payments := readJSONLine(t, dir, "payments")
+	assert.Equal(t, "payments", payments["biz"])
+	assert.Equal(t, "payments", payments["team"])
+	assert.Equal(t, "true", payments["pci"])
+	orders := readJSONLine(t, dir, "orders")
+	assert.Equal(t, "orders", orders["biz"])
+	assert.NotContains(t, orders, "team")
+	assert.NotContains(t, orders, "pci")
+
+	// 通配字段应用于所有业务，同名时以具体业务为准；之后新创建的日志实例使用新的字段
+	require.NoError(t, m.SetLabels(map[string]map[string]string{
+		LabelsWildcard: {"team": "platform", "region": "eu"},
+		"payments":     {"team": "payments"},
+	}))
+	assert.Equal(t, map[string]string{"team": "payments", "region": "eu"}, m.LabelsFor("payments"))
+	assert.Equal(t, map[string]string{"team": "platform", "region": "eu"}, m.LabelsFor("users"))
+
+	m.MustGet("users").Info("signed up")
+	require.NoError(t, m.Remove("payments"))
+	m.MustGet("payments").Info("refunded")
+	require.NoError(t, m.Flush(context.Background()))
+
+	users := readJSONLine(t, dir, "users")
+	assert.Equal(t, "platform", users["team"])
+	assert.Equal(t, "eu", users["region"])
+	payments = readJSONLine(t, dir, "payments")
+	assert.Equal(t, "refunded", payments["msg"])
+	assert.Equal(t, "payments", payments["team"])
+	assert.Equal(t, "eu", payments["region"])
+	assert.NotContains(t, payments, "pci")
+}
+
+// TestConfig_Validate_Labels 测试静态字段的校验
+func TestConfig_Validate_Labels(t *testing.T) {
+	outputs := []OutputConfig{{Type: OutputTypeConsole}}
+	for _, labels := range []map[string]map[string]string{
+		{"": {"team": "a"}},
+		{"payments": {"": "a"}},
+		{LabelsWildcard: {"biz": "a"}},
+	} {
+		cfg := Config{Outputs: outputs, Labels: labels}
+		assert.True(t, IsInvalidConfigValue(cfg.Validate()), "%v", labels)
+	}
+
+	m := MustNewManager(Config{Outputs: outputs})
+	assert.Nil(t, m.LabelsFor("payments"))
+	assert.True(t, IsInvalidConfigValue(m.SetLabels(map[string]map[string]string{"payments": {"": "a"}})))
+
+	var nilManager *Manager
+	assert.Nil(t, nilManager.LabelsFor("payments"))
+	assert.True(t, IsNilManager(nilManager.SetLabels(nil)))
+}
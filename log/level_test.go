@@ -0,0 +1,75 @@
+package log
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+)
+
+// assertLevel 断言业务日志当前的级别以及是否固定
+func assertLevel(t *testing.T, m *Manager, bizName, want string, wantPinned bool) {
+	t.Helper()
+	level, pinned, err := m.LevelInfo(bizName)
+	require.NoError(t, err)
+	assert.Equal(t, want, level, bizName)
+	assert.Equal(t, wantPinned, pinned, bizName)
+}
+
+// TestManager_LevelInheritance 测试未固定级别的日志继承默认级别，SetLevel 固定、ClearLevel 恢复继承
+func TestManager_LevelInheritance(t *testing.T) {
+	m, err := NewManager(Config{Level: "info", Outputs: []OutputConfig{{Type: OutputTypeConsole}}})
+	require.NoError(t, err)
+	assert.Equal(t, "info", m.DefaultLevel())
+
+	// 修改默认级别之前创建的日志
+	before := m.MustGet("before")
+	named, err := m.Named("before", "sub")
+	require.NoError(t, err)
+	assert.False(t, before.Core().Enabled(zapcore.DebugLevel))
+
+	require.NoError(t, m.SetDefaultLevel("debug"))
+	assert.Equal(t, "debug", m.DefaultLevel())
+	assert.True(t, before.Core().Enabled(zapcore.DebugLevel))
+	assert.True(t, named.Core().Enabled(zapcore.DebugLevel))
+	assertLevel(t, m, "before", "debug", false)
+
+	// 修改默认级别之后创建的日志
+	after := m.MustGet("after")
+	assert.True(t, after.Core().Enabled(zapcore.DebugLevel))
+	assertLevel(t, m, "after", "debug", false)
+
+	// 固定的日志不受默认级别影响
+	require.NoError(t, m.SetLevel("after", "error"))
+	require.NoError(t, m.SetDefaultLevel("warn"))
+	assertLevel(t, m, "before", "warn", false)
+	assertLevel(t, m, "after", "error", true)
+	assert.False(t, after.Core().Enabled(zapcore.WarnLevel))
+	assert.True(t, before.Core().Enabled(zapcore.WarnLevel))
+
+	// 清除后重新跟随默认级别
+	require.NoError(t, m.ClearLevel("after"))
+	assertLevel(t, m, "after", "warn", false)
+	assert.True(t, after.Core().Enabled(zapcore.WarnLevel))
+	require.NoError(t, m.SetDefaultLevel("debug"))
+	assert.True(t, after.Core().Enabled(zapcore.DebugLevel))
+
+	// 重复清除是安全的
+	require.NoError(t, m.ClearLevel("after"))
+}
+
+// TestManager_LevelInheritance_Errors 测试级别相关方法的参数校验
+func TestManager_LevelInheritance_Errors(t *testing.T) {
+	m, err := NewManager(Config{Outputs: []OutputConfig{{Type: OutputTypeConsole}}})
+	require.NoError(t, err)
+
+	assert.ErrorIs(t, m.SetDefaultLevel("loud"), ErrInvalidLogLevel)
+	assert.Equal(t, "info", m.DefaultLevel())
+	assert.ErrorIs(t, m.ClearLevel(""), ErrEmptyBizName)
+	assert.ErrorIs(t, m.ClearLevel("missing"), ErrLoggerNotFound)
+
+	var nilManager *Manager
+	assert.ErrorIs(t, nilManager.SetDefaultLevel("debug"), ErrNilManager)
+	assert.Empty(t, nilManager.DefaultLevel())
+}
@@ -0,0 +1,260 @@
+package log
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"unicode/utf8"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/buffer"
+	"go.uber.org/zap/zapcore"
+)
+
+// DefaultNewlineReplacement 是未设置 NewlineReplacement 时 text 格式替换消息中换行符使用的字符串
+const DefaultNewlineReplacement = `\n`
+
+// EntryTruncatedKey 是日志超过 MaxEntryBytes 时替代原有字段的摘要字段名
+const EntryTruncatedKey = "entry_truncated"
+
+// TruncationStats 是单行安全保护（Config.MaxFieldBytes 与 Config.MaxEntryBytes）的统计
+type TruncationStats struct {
+	Fields       uint64 // 累计截断的字段数量
+	FieldBytes   int64  // 字段截断累计丢弃的字节数
+	Entries      uint64 // 累计因超过 MaxEntryBytes 而被替换字段的日志条数
+	EntryBytes   int64  // 被替换字段的日志原本编码后的累计字节数
+	LastEntryLen int    // 最近一次被替换字段的日志原本编码后的字节数
+}
+
+// truncStats 是 TruncationStats 的并发计数器，所有业务日志共享同一份
+type truncStats struct {
+	fields       atomic.Uint64
+	fieldBytes   atomic.Int64
+	entries      atomic.Uint64
+	entryBytes   atomic.Int64
+	lastEntryLen atomic.Int64
+}
+
+// snapshot 返回计数器的快照
+func (s *truncStats) snapshot() TruncationStats {
+	return TruncationStats{
+		Fields:       s.fields.Load(),
+		FieldBytes:   s.fieldBytes.Load(),
+		Entries:      s.entries.Load(),
+		EntryBytes:   s.entryBytes.Load(),
+		LastEntryLen: int(s.lastEntryLen.Load()),
+	}
+}
+
+// Truncations 返回字段截断与超长日志替换的统计，所有业务日志合计；未启用对应限制时为零值
+func (m *Manager) Truncations() TruncationStats {
+	if m == nil || m.truncs == nil {
+		return TruncationStats{}
+	}
+	return m.truncs.snapshot()
+}
+
+// newlineReplacer 返回 text 格式替换消息中换行符的 Replacer，replacement 为空时使用 DefaultNewlineReplacement
+func newlineReplacer(replacement string) *strings.Replacer {
+	if replacement == "" {
+		replacement = DefaultNewlineReplacement
+	}
+	return strings.NewReplacer("\r\n", replacement, "\n", replacement, "\r", replacement)
+}
+
+// lineSafeEncoder 包装 zap 的编码器，保证每条日志编码后仍然是一行且大小有界：
+//   - newline 不为 nil 时（text 格式）替换消息与堆栈中的换行符；字段值由 zap 按 JSON 规则转义，本身不会换行
+//   - maxField 大于 0 时截断超长的字符串与字节字段，包括通过 With 添加的字段
+//   - maxEntry 大于 0 时，编码后超过该大小的日志改为只包含一个 EntryTruncatedKey 摘要字段并丢弃堆栈，
+//     通过 With 添加的字段已经编码在编码器中，只受 maxField 约束
+//
+// 消息与字段的检查都是编码前的长度比较，maxEntry 只比较编码结果的长度，没有超限的日志不会产生额外的分配
+type lineSafeEncoder struct {
+	zapcore.Encoder
+	newline  *strings.Replacer
+	maxField int
+	maxEntry int
+	stats    *truncStats
+}
+
+// wrapLineSafe 按 cfg 包装 enc，text 为 true 表示 text 格式；没有需要执行的检查时原样返回 enc
+func wrapLineSafe(enc zapcore.Encoder, cfg Config, text bool, stats *truncStats) zapcore.Encoder {
+	if !text && cfg.MaxFieldBytes <= 0 && cfg.MaxEntryBytes <= 0 {
+		return enc
+	}
+	e := &lineSafeEncoder{Encoder: enc, maxField: cfg.MaxFieldBytes, maxEntry: cfg.MaxEntryBytes, stats: stats}
+	if text {
+		e.newline = newlineReplacer(cfg.NewlineReplacement)
+	}
+	return e
+}
+
+// Clone 实现 zapcore.Encoder，克隆后的编码器仍然执行相同的检查
+func (e *lineSafeEncoder) Clone() zapcore.Encoder {
+	c := *e
+	c.Encoder = e.Encoder.Clone()
+	return &c
+}
+
+// AddString 截断通过 With 添加的超长字符串字段
+func (e *lineSafeEncoder) AddString(key, value string) {
+	e.Encoder.AddString(key, e.truncateString(value))
+}
+
+// AddByteString 截断通过 With 添加的超长 UTF-8 字节字段
+func (e *lineSafeEncoder) AddByteString(key string, value []byte) {
+	e.Encoder.AddByteString(key, e.truncateBytes(value))
+}
+
+// AddBinary 截断通过 With 添加的超长二进制字段，截断后改为字符串字段，见 truncateBinary
+func (e *lineSafeEncoder) AddBinary(key string, value []byte) {
+	if e.maxField <= 0 || len(value) <= e.maxField {
+		e.Encoder.AddBinary(key, value)
+		return
+	}
+	e.Encoder.AddString(key, e.truncateBinary(value))
+}
+
+// EncodeEntry 实现 zapcore.Encoder
+func (e *lineSafeEncoder) EncodeEntry(ent zapcore.Entry, fields []zapcore.Field) (*buffer.Buffer, error) {
+	if e.newline != nil {
+		if strings.ContainsAny(ent.Message, "\r\n") {
+			ent.Message = e.newline.Replace(ent.Message)
+		}
+		if strings.ContainsAny(ent.Stack, "\r\n") {
+			ent.Stack = e.newline.Replace(ent.Stack)
+		}
+	}
+	fields = e.truncateFields(fields)
+	buf, err := e.Encoder.EncodeEntry(ent, fields)
+	if err == nil && e.newline != nil && ent.Stack != "" {
+		joinStackLine(buf, ent.Stack)
+	}
+	if err != nil || e.maxEntry <= 0 || buf.Len() <= e.maxEntry {
+		return buf, err
+	}
+
+	size := buf.Len()
+	buf.Free()
+	e.stats.entries.Add(1)
+	e.stats.entryBytes.Add(int64(size))
+	e.stats.lastEntryLen.Store(int64(size))
+	// 消息本身也可能很长，保留前一半的空间给其他部分
+	if limit := e.maxEntry / 2; len(ent.Message) > limit {
+		ent.Message = truncateString(ent.Message, limit)
+	}
+	reason := fmt.Sprintf("entry of %d bytes exceeds max_entry_bytes %d, %d fields dropped", size, e.maxEntry, len(fields))
+	// 堆栈通常比其余部分加起来还长，直接丢弃
+	if ent.Stack != "" {
+		ent.Stack = ""
+		reason += ", stack dropped"
+	}
+	return e.Encoder.EncodeEntry(ent, []zapcore.Field{zap.String(EntryTruncatedKey, reason)})
+}
+
+// joinStackLine 把 console 编码器写在堆栈之前的换行符改为空格。
+// 堆栈已经替换了换行符，字段值又由编码器转义，因此 buf 中紧接着 stack 的换行符就是这个分隔符
+func joinStackLine(buf *buffer.Buffer, stack string) {
+	b := buf.Bytes()
+	if i := bytes.LastIndex(b, []byte("\n"+stack)); i >= 0 {
+		b[i] = ' '
+	}
+}
+
+// truncateFields 返回截断了超长字符串与字节字段的字段列表，没有字段超长时原样返回 fields
+func (e *lineSafeEncoder) truncateFields(fields []zapcore.Field) []zapcore.Field {
+	if e.maxField <= 0 {
+		return fields
+	}
+	var out []zapcore.Field
+	for i, f := range fields {
+		switch f.Type {
+		case zapcore.StringType:
+			if len(f.String) <= e.maxField {
+				continue
+			}
+			f.String = e.truncateString(f.String)
+		case zapcore.ByteStringType:
+			b, _ := f.Interface.([]byte)
+			if len(b) <= e.maxField {
+				continue
+			}
+			f.Interface = e.truncateBytes(b)
+		case zapcore.BinaryType:
+			b, _ := f.Interface.([]byte)
+			if len(b) <= e.maxField {
+				continue
+			}
+			f = zap.String(f.Key, e.truncateBinary(b))
+		default:
+			continue
+		}
+		// 第一次需要修改时才复制，不修改调用方的字段
+		if out == nil {
+			out = make([]zapcore.Field, len(fields))
+			copy(out, fields)
+		}
+		out[i] = f
+	}
+	if out == nil {
+		return fields
+	}
+	return out
+}
+
+// truncateString 在 s 超过 maxField 时截断并计数
+func (e *lineSafeEncoder) truncateString(s string) string {
+	if e.maxField <= 0 || len(s) <= e.maxField {
+		return s
+	}
+	n := runeBoundary(s, e.maxField)
+	e.countField(len(s) - n)
+	return truncateString(s, n)
+}
+
+// truncateBytes 在 UTF-8 字节字段 b 超过 maxField 时截断并计数
+func (e *lineSafeEncoder) truncateBytes(b []byte) []byte {
+	if e.maxField <= 0 || len(b) <= e.maxField {
+		return b
+	}
+	n := runeBoundary(b, e.maxField)
+	e.countField(len(b) - n)
+	suffix := truncatedSuffix(len(b) - n)
+	out := make([]byte, 0, n+len(suffix))
+	out = append(out, b[:n]...)
+	return append(out, suffix...)
+}
+
+// truncateBinary 截断超过 maxField 的二进制字段并计数，返回前 maxField 个字节的 base64 编码（与 zap 编码二进制字段的方式相同）
+// 加上截断后缀；后缀不能放进 base64 编码的内容中，因此截断后的二进制字段改为字符串字段
+func (e *lineSafeEncoder) truncateBinary(b []byte) string {
+	e.countField(len(b) - e.maxField)
+	return base64.StdEncoding.EncodeToString(b[:e.maxField]) + truncatedSuffix(len(b)-e.maxField)
+}
+
+// countField 记录一次字段截断，dropped 为丢弃的字节数
+func (e *lineSafeEncoder) countField(dropped int) {
+	e.stats.fields.Add(1)
+	e.stats.fieldBytes.Add(int64(dropped))
+}
+
+// truncateString 保留 s 的前 max 个字节（不截断在多字节字符中间），并追加被截断的字节数
+func truncateString(s string, max int) string {
+	n := runeBoundary(s, max)
+	return s[:n] + truncatedSuffix(len(s)-n)
+}
+
+// runeBoundary 返回不大于 n 且不在 b 的多字节字符中间的位置
+func runeBoundary[T string | []byte](b T, n int) int {
+	for n > 0 && n < len(b) && !utf8.RuneStart(b[n]) {
+		n--
+	}
+	return n
+}
+
+// truncatedSuffix 返回截断后追加的后缀，例如 "...(truncated 42 bytes)"
+func truncatedSuffix(n int) string {
+	return fmt.Sprintf("...(truncated %d bytes)", n)
+}
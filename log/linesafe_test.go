@@ -0,0 +1,187 @@
+package log
+
+import (
+	"context"
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// newLineSafeManager 创建只有一个 format 格式文件输出的 Manager，apply 修改其余配置
+func newLineSafeManager(t *testing.T, format string, apply func(*Config)) (*Manager, string) {
+	t.Helper()
+	dir := t.TempDir()
+	cfg := Config{Outputs: []OutputConfig{{Type: OutputTypeFile, Format: format, File: &FileOutputConfig{Dir: dir}}}}
+	if apply != nil {
+		apply(&cfg)
+	}
+	m, err := NewManager(cfg)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = m.Close() })
+	return m, dir
+}
+
+// readLogLines 刷新 m 并返回 dir 中 bizName 日志文件的所有行
+func readLogLines(t *testing.T, m *Manager, dir, bizName string) []string {
+	t.Helper()
+	require.NoError(t, m.Flush(context.Background()))
+	data, err := os.ReadFile(filepath.Join(dir, bizName+".log"))
+	require.NoError(t, err)
+	return strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+}
+
+// TestLineSafe_NewlineText 测试 text 格式替换消息中的换行符，字段值中的换行符由编码器转义
+func TestLineSafe_NewlineText(t *testing.T) {
+	m, dir := newLineSafeManager(t, FormatText, nil)
+	m.MustGet("app").Info("first\nsecond\r\nthird\rfourth", zap.String("body", "a\nb"))
+
+	lines := readLogLines(t, m, dir, "app")
+	require.Len(t, lines, 1)
+	assert.Contains(t, lines[0], `first\nsecond\nthird\nfourth`)
+	assert.Contains(t, lines[0], `"body": "a\nb"`)
+	assert.Equal(t, TruncationStats{}, m.Truncations())
+}
+
+// TestLineSafe_NewlineReplacement 测试自定义换行符的替换字符串
+func TestLineSafe_NewlineReplacement(t *testing.T) {
+	m, dir := newLineSafeManager(t, FormatText, func(c *Config) { c.NewlineReplacement = " | " })
+	m.MustGet("app").Info("first\nsecond")
+
+	lines := readLogLines(t, m, dir, "app")
+	require.Len(t, lines, 1)
+	assert.Contains(t, lines[0], "first | second")
+}
+
+// TestLineSafe_NewlineJSON 测试 json 格式不替换消息，由编码器转义换行符
+func TestLineSafe_NewlineJSON(t *testing.T) {
+	m, dir := newLineSafeManager(t, FormatJSON, nil)
+	m.MustGet("app").Info("first\nsecond", zap.String("body", "a\nb"))
+
+	lines := readLogLines(t, m, dir, "app")
+	require.Len(t, lines, 1)
+	entry := readJSONLine(t, dir, "app")
+	assert.Equal(t, "first\nsecond", entry["msg"])
+	assert.Equal(t, "a\nb", entry["body"])
+}
+
+// TestLineSafe_MaxFieldBytes 测试超长的字符串、字节与二进制字段被精确截断并计数，未超长的字段不受影响
+func TestLineSafe_MaxFieldBytes(t *testing.T) {
+	m, dir := newLineSafeManager(t, FormatJSON, func(c *Config) { c.MaxFieldBytes = 10 })
+	blob := []byte(strings.Repeat("x", 4096))
+	m.MustGet("app").With(zap.String("ctx", strings.Repeat("c", 15))).Info("upload",
+		zap.String("short", "0123456789"),
+		zap.String("long", "0123456789abcdef"),
+		zap.String("utf8", "一二三四"), // 每个字符 3 个字节，第 10 个字节位于第 4 个字符中间
+		zap.ByteString("bytes", []byte("0123456789ab")),
+		zap.Binary("blob", blob),
+	)
+
+	entry := readJSONLine(t, dir, "app")
+	assert.Equal(t, "0123456789", entry["short"])
+	assert.Equal(t, "0123456789...(truncated 6 bytes)", entry["long"])
+	assert.Equal(t, "一二三...(truncated 3 bytes)", entry["utf8"])
+	assert.Equal(t, "0123456789...(truncated 2 bytes)", entry["bytes"])
+	assert.Equal(t, base64.StdEncoding.EncodeToString(blob[:10])+"...(truncated 4086 bytes)", entry["blob"])
+	assert.Equal(t, strings.Repeat("c", 10)+"...(truncated 5 bytes)", entry["ctx"])
+
+	s := m.Truncations()
+	assert.Equal(t, uint64(5), s.Fields)
+	assert.Equal(t, int64(6+3+2+4086+5), s.FieldBytes)
+	assert.Equal(t, uint64(0), s.Entries)
+}
+
+// TestLineSafe_MaxEntryBytes 测试编码后超长的日志被替换为摘要字段，未超长的日志不受影响
+func TestLineSafe_MaxEntryBytes(t *testing.T) {
+	for _, format := range []string{FormatJSON, FormatText} {
+		t.Run(format, func(t *testing.T) {
+			m, dir := newLineSafeManager(t, format, func(c *Config) { c.MaxEntryBytes = 512 })
+			l := m.MustGet("app")
+			l.Info("small", zap.String("k", "v"))
+			l.Info("huge", zap.String("a", strings.Repeat("a", 400)), zap.String("b", strings.Repeat("b", 400)))
+			l.Info(strings.Repeat("m", 2048))
+
+			lines := readLogLines(t, m, dir, "app")
+			require.Len(t, lines, 3)
+			assert.Contains(t, lines[0], "small")
+			for _, line := range lines {
+				assert.LessOrEqual(t, len(line), 512)
+			}
+			assert.Contains(t, lines[1], EntryTruncatedKey)
+			assert.Contains(t, lines[1], "exceeds max_entry_bytes 512, 2 fields dropped")
+			assert.NotContains(t, lines[1], "aaaa")
+			assert.Contains(t, lines[2], "...(truncated")
+
+			s := m.Truncations()
+			assert.Equal(t, uint64(2), s.Entries)
+			assert.Greater(t, s.LastEntryLen, 2048)
+			assert.Greater(t, s.EntryBytes, int64(2048+800))
+			assert.Equal(t, uint64(0), s.Fields)
+		})
+	}
+}
+
+// TestLineSafe_StackText 测试 text 格式的堆栈与消息一样替换换行符，整条日志仍然是一行
+func TestLineSafe_StackText(t *testing.T) {
+	m, dir := newLineSafeManager(t, FormatText, func(c *Config) { c.StacktraceLevel = "error" })
+	m.MustGet("app").Error("boom")
+
+	lines := readLogLines(t, m, dir, "app")
+	require.Len(t, lines, 1)
+	assert.Contains(t, lines[0], "boom")
+	assert.Contains(t, lines[0], `TestLineSafe_StackText`)
+	assert.Contains(t, lines[0], `\n`)
+}
+
+// TestLineSafe_MaxEntryBytesStack 测试超长日志的摘要丢弃了堆栈，替换后的日志不超过限制
+func TestLineSafe_MaxEntryBytesStack(t *testing.T) {
+	for _, format := range []string{FormatJSON, FormatText} {
+		t.Run(format, func(t *testing.T) {
+			m, dir := newLineSafeManager(t, format, func(c *Config) {
+				c.StacktraceLevel = "error"
+				c.MaxEntryBytes = 512
+			})
+			errorAtDepth(m.MustGet("app"), 20)
+
+			lines := readLogLines(t, m, dir, "app")
+			require.Len(t, lines, 1)
+			assert.LessOrEqual(t, len(lines[0]), 512)
+			assert.Contains(t, lines[0], "0 fields dropped, stack dropped")
+			assert.NotContains(t, lines[0], "TestLineSafe_MaxEntryBytesStack")
+			assert.Equal(t, uint64(1), m.Truncations().Entries)
+		})
+	}
+}
+
+// errorAtDepth 在 depth 层嵌套调用中记录一条 Error 日志，使堆栈足够长
+func errorAtDepth(l *zap.Logger, depth int) {
+	if depth > 0 {
+		errorAtDepth(l, depth-1)
+		return
+	}
+	l.Error("boom")
+}
+
+// TestLineSafe_Disabled 测试 json 格式未设置限制时不包装编码器
+func TestLineSafe_Disabled(t *testing.T) {
+	m, dir := newLineSafeManager(t, FormatJSON, nil)
+	long := strings.Repeat("x", 1<<16)
+	m.MustGet("app").Info("big", zap.String("long", long))
+
+	assert.Equal(t, long, readJSONLine(t, dir, "app")["long"])
+	assert.Equal(t, TruncationStats{}, m.Truncations())
+}
+
+// TestConfig_ValidateLineLimits 测试负数的限制无效
+func TestConfig_ValidateLineLimits(t *testing.T) {
+	outputs := []OutputConfig{{Type: OutputTypeConsole}}
+	cfg := Config{Outputs: outputs, MaxFieldBytes: -1}
+	assert.ErrorIs(t, cfg.Validate(), ErrInvalidConfigValue)
+	cfg = Config{Outputs: outputs, MaxEntryBytes: -1}
+	assert.ErrorIs(t, cfg.Validate(), ErrInvalidConfigValue)
+}
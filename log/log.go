@@ -59,6 +59,18 @@ func NewZapLogger(cfg Config, bizName string) (*zap.Logger, zap.AtomicLevel, err
 	if err != nil {
 		return nil, zap.AtomicLevel{}, fmt.Errorf("failed to parse log level for '%s' (%v): %w", bizName, err, ErrInvalidLogLevel)
 	}
+	logger, _, err := newZapLogger(cfg, bizName, level, nil, nil, &truncStats{})
+	if err != nil {
+		return nil, zap.AtomicLevel{}, err
+	}
+	return logger, level, nil
+}
+
+// newZapLogger 创建使用 level 控制级别的 zap 日志实例，并返回其使用的文件写入器，便于 Manager 执行轮转和关闭。
+// stats 不为 nil 时，文件输出的写入和同步失败会记录到 stats；ring 不为 nil 时日志同时写入崩溃报告的环形缓冲区。
+// 所有编码器都按 cfg 执行单行安全保护（见 Config.MaxFieldBytes），截断与替换计入 truncs。
+func newZapLogger(cfg Config, bizName string, level zapcore.LevelEnabler, stats *writeStats, ring *crashRing, truncs *truncStats) (*zap.Logger, []*lumberjack.Logger, error) {
+	cfg = cfg.forBiz(bizName)
 
 	encoderConfig := zapcore.EncoderConfig{
 		TimeKey:        "ts",
@@ -73,7 +85,11 @@ func NewZapLogger(cfg Config, bizName string) (*zap.Logger, zap.AtomicLevel, err
 		EncodeDuration: zapcore.SecondsDurationEncoder,
 	}
 
+	// 只有控制台输出可能着色，文件输出从不包含颜色控制字符
+	color := useColor(cfg.Color, stdoutIsTerminal)
+
 	var cores []zapcore.Core
+	var files []*lumberjack.Logger
 	for _, out := range cfg.Outputs {
 		format := out.Format
 		if format == "" {
@@ -83,54 +99,79 @@ func NewZapLogger(cfg Config, bizName string) (*zap.Logger, zap.AtomicLevel, err
 		var enc zapcore.Encoder
 		switch format {
 		case FormatJSON:
-			enc = zapcore.NewJSONEncoder(encoderConfig)
+			enc = wrapLineSafe(zapcore.NewJSONEncoder(encoderConfig), cfg, false, truncs)
 		case FormatText:
 			textCfg := encoderConfig
 			textCfg.EncodeTime = zapcore.TimeEncoderOfLayout("2006-01-02 15:04:05")
 			textCfg.EncodeLevel = zapcore.CapitalLevelEncoder
+			if color && out.Type == OutputTypeConsole {
+				textCfg.EncodeLevel = zapcore.CapitalColorLevelEncoder
+			}
 			textCfg.EncodeCaller = zapcore.ShortCallerEncoder
 			textCfg.ConsoleSeparator = " "
-			enc = zapcore.NewConsoleEncoder(textCfg)
+			enc = wrapLineSafe(zapcore.NewConsoleEncoder(textCfg), cfg, true, truncs)
 		default:
-			return nil, zap.AtomicLevel{}, fmt.Errorf("unsupported log format '%s' for '%s': %w (supported formats: %s, %s)", format, bizName, ErrInvalidLogFormat, FormatJSON, FormatText)
+			return nil, nil, fmt.Errorf("unsupported log format '%s' for '%s': %w (supported formats: %s, %s)", format, bizName, ErrInvalidLogFormat, FormatJSON, FormatText)
 		}
 
 		switch out.Type {
 		case "file":
 			if out.File == nil {
-				return nil, zap.AtomicLevel{}, fmt.Errorf("file output config missing for '%s': %w", bizName, ErrInvalidConfigValue)
+				return nil, nil, fmt.Errorf("file output config missing for '%s': %w", bizName, ErrInvalidConfigValue)
 			}
-			fileWriter := zapcore.AddSync(&lumberjack.Logger{
+			file := &lumberjack.Logger{
 				Filename:   filepath.Join(out.File.Dir, bizName+".log"),
 				MaxSize:    out.File.MaxSize,
 				MaxBackups: out.File.MaxBackups,
 				MaxAge:     out.File.MaxAge,
 				Compress:   out.File.Compress,
-			})
-			cores = append(cores, zapcore.NewCore(enc, fileWriter, level))
+			}
+			files = append(files, file)
+			fileWriter := wrapSink(newFileSink(file), stats)
+			cores = append(cores, zapcore.NewCore(enc, fileWriter, allLevels))
 		case "console":
 			stdoutLevel := zap.LevelEnablerFunc(func(lvl zapcore.Level) bool {
-				return lvl < zapcore.ErrorLevel && lvl >= level.Level()
+				return lvl < zapcore.ErrorLevel
 			})
 			stderrLevel := zap.LevelEnablerFunc(func(lvl zapcore.Level) bool {
-				return lvl >= zapcore.ErrorLevel && lvl >= level.Level()
+				return lvl >= zapcore.ErrorLevel
 			})
 			cores = append(cores,
-				zapcore.NewCore(enc, zapcore.AddSync(os.Stdout), stdoutLevel),
-				zapcore.NewCore(enc, zapcore.AddSync(os.Stderr), stderrLevel),
+				zapcore.NewCore(enc, consoleSyncer{zapcore.AddSync(os.Stdout)}, stdoutLevel),
+				zapcore.NewCore(enc, consoleSyncer{zapcore.AddSync(os.Stderr)}, stderrLevel),
 			)
 		}
 	}
 
-	core := zapcore.NewTee(cores...)
+	if ring != nil {
+		cores = append(cores, &ringCore{enc: wrapLineSafe(zapcore.NewJSONEncoder(encoderConfig), cfg, false, truncs), biz: bizName, ring: ring})
+	}
+
+	// 各输出只负责分流，级别统一由外层的 levelCore 过滤，便于 Manager.For 按请求放宽级别；
+	// teeCore 保证一条日志写入所有输出后才写入下一条，某个输出失败不影响其他输出
+	core := &levelCore{Core: newTeeCore(cores...), level: level}
 
-	logger := zap.New(core,
+	// 业务名称字段与静态字段（见 Config.Labels）
+	fields := append([]zap.Field{zap.String("biz", bizName)}, cfg.labelFields(bizName)...)
+	zapOpts := []zap.Option{
 		zap.AddCaller(),
-		zap.AddCallerSkip(0),                   // 跳过一层调用栈，显示正确的调用位置
-		zap.Fields(zap.String("biz", bizName)), // 添加业务名称字段
-	)
+		zap.AddCallerSkip(cfg.CallerSkip), // 跳过封装函数的调用栈，显示正确的调用位置
+		zap.Fields(fields...),
+	}
+	if cfg.StacktraceLevel != "" {
+		// 堆栈阈值使用固定级别，不随 SetLevel 调整的日志级别变化
+		stackLevel, err := zapcore.ParseLevel(cfg.StacktraceLevel)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to parse stacktrace level for '%s' (%v): %w", bizName, err, ErrInvalidLogLevel)
+		}
+		zapOpts = append(zapOpts, zap.AddStacktrace(stackLevel))
+	}
+	if cfg.Development {
+		zapOpts = append(zapOpts, zap.Development())
+	}
+	logger := zap.New(core, zapOpts...)
 
-	return logger, level, nil
+	return logger, files, nil
 }
 
 // Data 返回一个zap.Field，用于记录任意类型的数据
@@ -4,16 +4,41 @@ import (
 	"errors"
 	"fmt"
 	"sync"
+	"sync/atomic"
 
+	"github.com/qq1060656096/drugo/pkg/clock"
 	"go.uber.org/zap"
+	"gopkg.in/natefinch/lumberjack.v2"
 )
 
 // 日志管理器，用于管理多个业务模块的日志实例
 type Manager struct {
-	mu      sync.RWMutex               // 读写锁，用于并发安全
-	cfg     Config                     // 日志配置
-	loggers map[string]*zap.Logger     // 日志实例缓存，按业务名称分组
-	levels  map[string]zap.AtomicLevel // 日志级别控制器，用于动态调整级别
+	mu      sync.RWMutex                    // 读写锁，用于并发安全
+	cfg     Config                          // 日志配置
+	loggers map[string]*zap.Logger          // 日志实例缓存，按业务名称分组
+	levels  map[string]*bizLevel            // 日志级别控制器，用于动态调整级别
+	files   map[string][]*lumberjack.Logger // 文件写入器，用于轮转和关闭文件
+
+	defaultLevel zap.AtomicLevel // 默认日志级别，未固定级别的日志实例共享它
+
+	archMu sync.Mutex // 保护归档协程的启动与停止
+	arch   *archiver  // 归档协程，SetArchiveHook 时启动
+
+	writeMu        sync.Mutex                           // 保护写入失败统计
+	writeStats     map[string]*writeStats               // 文件输出的写入失败统计，按业务名称分组
+	onWriteFailure atomic.Pointer[WriteFailureCallback] // 连续写入失败超过阈值时的回调
+
+	quotaMu   sync.Mutex    // 保护目录配额协程的停止与触发
+	quota     *quotaWatcher // 目录配额协程，启用 MaxTotalSizeMB 时启动
+	evictMu   sync.Mutex    // 串行化配额检查并保护淘汰统计
+	evictions EvictionStats // 目录配额的淘汰统计
+
+	auditMu sync.Mutex              // 保护审计日志实例缓存
+	audits  map[string]*AuditLogger // 审计日志实例缓存，按业务名称分组，见 Audit
+
+	ring *crashRing // 所有业务日志最近的日志，启用 CrashRing 时创建，见 DumpCrash
+
+	truncs *truncStats // 字段截断与超长日志替换的统计，见 Truncations
 }
 
 var (
@@ -42,11 +67,26 @@ func NewManager(cfg Config) (*Manager, error) {
 	if err := cfg.Validate(); err != nil {
 		return nil, err
 	}
-	return &Manager{
-		cfg:     cfg,
-		loggers: make(map[string]*zap.Logger),     // 初始化日志实例缓存
-		levels:  make(map[string]zap.AtomicLevel), // 初始化日志级别控制器
-	}, nil
+	level, err := zap.ParseAtomicLevel(cfg.Level)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidLogLevel, cfg.Level)
+	}
+	m := &Manager{
+		cfg:          cfg,
+		loggers:      make(map[string]*zap.Logger), // 初始化日志实例缓存
+		levels:       make(map[string]*bizLevel),   // 初始化日志级别控制器
+		files:        make(map[string][]*lumberjack.Logger),
+		defaultLevel: level,
+		ring:         newCrashRing(cfg.CrashRing),
+		truncs:       &truncStats{},
+	}
+	m.startQuota()
+	return m, nil
+}
+
+// clock 返回定期任务使用的时钟，见 Config.Clock
+func (m *Manager) clock() clock.Clock {
+	return clock.OrReal(m.cfg.Clock)
 }
 
 // MustNewManager 类似于 NewManager，但如果发生错误会 panic。
@@ -62,6 +102,11 @@ func MustNewManager(cfg Config) *Manager {
 // bizName: 业务名称，用于标识不同的日志实例
 // 返回: zap日志实例和可能的错误
 func (m *Manager) Get(bizName string) (*zap.Logger, error) {
+	// nil Manager（例如 kernel.BaseKernel 的默认 Logger）返回不输出任何内容的日志实例
+	if m == nil {
+		return zap.NewNop(), nil
+	}
+
 	// 验证业务名称不为空
 	if bizName == "" {
 		return nil, ErrEmptyBizName
@@ -84,15 +129,25 @@ func (m *Manager) Get(bizName string) (*zap.Logger, error) {
 		return logger, nil
 	}
 
-	// 创建新的zap日志实例
-	l, level, err := NewZapLogger(m.cfg, bizName)
+	// 创建新的zap日志实例，级别继承默认级别，Config.Levels 中配置了该业务时固定为配置的级别
+	level := &bizLevel{inherited: m.defaultLevel}
+	if name, ok := m.cfg.levelFor(bizName); ok && name != "" {
+		if lvl, err := zap.ParseAtomicLevel(name); err == nil {
+			level.pin(lvl.Level())
+		}
+	}
+	l, files, err := newZapLogger(m.cfg, bizName, level, m.statsFor(bizName), m.ring, m.truncs)
 	if err != nil {
 		return nil, err
 	}
 
-	// 将新创建的日志实例和级别控制器存入缓存
+	// 将新创建的日志实例、级别控制器和文件写入器存入缓存
 	m.loggers[bizName] = l
 	m.levels[bizName] = level
+	if m.files == nil {
+		m.files = make(map[string][]*lumberjack.Logger)
+	}
+	m.files[bizName] = files
 	return l, nil
 }
 
@@ -107,6 +162,33 @@ func (m *Manager) MustGet(bizName string) *zap.Logger {
 	return l
 }
 
+// GetWith 获取指定业务名称的日志实例，并应用额外的 zap 选项
+// 返回的日志实例不会被缓存，但与缓存实例共享输出和级别控制器，SetLevel 同样生效
+// 常用于在封装函数中调整调用栈层数，例如 m.GetWith("order", zap.AddCallerSkip(1))
+// bizName: 业务名称
+// opts: 额外的 zap 选项
+// 返回: zap日志实例和可能的错误
+func (m *Manager) GetWith(bizName string, opts ...zap.Option) (*zap.Logger, error) {
+	l, err := m.Get(bizName)
+	if err != nil {
+		return nil, err
+	}
+	return l.WithOptions(opts...), nil
+}
+
+// Named 获取指定业务日志的命名子日志实例
+// 子日志写入同一个业务日志文件，通过 "logger" 字段区分子系统，SetLevel 同样生效
+// bizName: 业务名称
+// name: 子日志名称，如 "payment.callback"
+// 返回: zap日志实例和可能的错误
+func (m *Manager) Named(bizName, name string) (*zap.Logger, error) {
+	l, err := m.Get(bizName)
+	if err != nil {
+		return nil, err
+	}
+	return l.Named(name), nil
+}
+
 // Sync 同步所有日志实例，将缓冲区的日志刷新到磁盘
 // 建议在程序退出前调用此方法，确保所有日志都被写入
 // 返回: 同步过程中的所有错误（合并后）
@@ -124,21 +206,26 @@ func (m *Manager) Sync() error {
 			}
 		}
 	}
+	errs = append(errs, m.syncAudits()...)
 	if len(errs) > 0 {
 		return errors.Join(errs...)
 	}
 	return nil
 }
 
-// Close 关闭所有日志实例，同步缓冲区并释放资源
-// 调用后将清空日志实例缓存，后续调用 Get() 会创建新的实例
+// Close 关闭所有日志实例与审计日志实例，同步缓冲区并释放资源，同时停止 SetArchiveHook 启动的归档协程与目录配额协程
+// 调用后将清空日志实例缓存，后续调用 Get() 会创建新的实例，但目录配额不再检查
 // 建议在程序退出时调用此方法
 // 返回: 关闭过程中的所有错误（合并后）
 func (m *Manager) Close() error {
+	// 先停止归档协程与配额协程，避免它们与文件关闭并发执行
+	m.stopArchiver()
+	m.stopQuota()
+	errs := m.closeAudits()
+
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
-	var errs []error
 	for bizName, logger := range m.loggers {
 		if err := logger.Sync(); err != nil {
 			// 忽略 stdout/stderr 的 sync 错误（在某些系统上是正常的）
@@ -147,10 +234,18 @@ func (m *Manager) Close() error {
 			}
 		}
 	}
+	for bizName, files := range m.files {
+		for _, f := range files {
+			if err := f.Close(); err != nil {
+				errs = append(errs, fmt.Errorf("close logger '%s' file: %w", bizName, err))
+			}
+		}
+	}
 
-	// 清空日志实例缓存和级别控制器
+	// 清空日志实例缓存、级别控制器和文件写入器
 	m.loggers = make(map[string]*zap.Logger)
-	m.levels = make(map[string]zap.AtomicLevel)
+	m.levels = make(map[string]*bizLevel)
+	m.files = make(map[string][]*lumberjack.Logger)
 
 	if len(errs) > 0 {
 		return errors.Join(errs...)
@@ -179,13 +274,20 @@ func (m *Manager) Remove(bizName string) error {
 		if err := logger.Sync(); err != nil {
 			return err
 		}
+		for _, f := range m.files[bizName] {
+			if err := f.Close(); err != nil {
+				return err
+			}
+		}
 		delete(m.loggers, bizName)
 		delete(m.levels, bizName)
+		delete(m.files, bizName)
 	}
 	return nil
 }
 
-// SetLevel 动态更新指定业务的日志级别
+// SetLevel 动态更新指定业务的日志级别，并将其固定：之后 SetDefaultLevel 不再影响该业务，
+// 直到调用 ClearLevel
 // bizName: 业务名称
 // level: 新的日志级别字符串，如 "debug", "info", "warn", "error"
 // 返回: 可能的错误
@@ -201,16 +303,13 @@ func (m *Manager) SetLevel(bizName, level string) error {
 		return fmt.Errorf("invalid log level '%s': %w", level, ErrInvalidLogLevel)
 	}
 
-	m.mu.RLock()
-	atomicLevel, ok := m.levels[bizName]
-	m.mu.RUnlock()
-
-	if !ok {
-		return fmt.Errorf("logger '%s': %w", bizName, ErrLoggerNotFound)
+	l, err := m.level(bizName)
+	if err != nil {
+		return err
 	}
 
-	// 动态更新日志级别
-	atomicLevel.SetLevel(newLevel.Level())
+	// 动态更新并固定日志级别
+	l.pin(newLevel.Level())
 	return nil
 }
 
@@ -218,18 +317,17 @@ func (m *Manager) SetLevel(bizName, level string) error {
 // bizName: 业务名称
 // 返回: 日志级别字符串和可能的错误
 func (m *Manager) GetLevel(bizName string) (string, error) {
-	// 验证业务名称不为空
-	if bizName == "" {
-		return "", ErrEmptyBizName
-	}
-
-	m.mu.RLock()
-	atomicLevel, ok := m.levels[bizName]
-	m.mu.RUnlock()
+	level, _, err := m.LevelInfo(bizName)
+	return level, err
+}
 
-	if !ok {
-		return "", fmt.Errorf("logger '%s': %w", bizName, ErrLoggerNotFound)
+// LevelInfo 获取指定业务的当前日志级别，以及级别是否通过 SetLevel 固定
+// bizName: 业务名称
+// 返回: 日志级别字符串、是否固定（false 表示继承默认级别）和可能的错误
+func (m *Manager) LevelInfo(bizName string) (level string, pinned bool, err error) {
+	l, err := m.level(bizName)
+	if err != nil {
+		return "", false, err
 	}
-
-	return atomicLevel.Level().String(), nil
+	return l.Level().String(), l.isPinned(), nil
 }
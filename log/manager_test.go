@@ -1,9 +1,12 @@
 package log
 
 import (
+	"bufio"
+	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
+	"runtime"
 	"sync"
 	"testing"
 
@@ -678,3 +681,170 @@ func BenchmarkManager_SetLevel(b *testing.B) {
 		m.SetLevel("benchmark_service", level)
 	}
 }
+
+// readLogEntries 读取 JSON 日志文件中的所有日志条目
+func readLogEntries(t *testing.T, path string) []map[string]any {
+	t.Helper()
+	f, err := os.Open(path)
+	require.NoError(t, err)
+	defer f.Close()
+
+	var entries []map[string]any
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var entry map[string]any
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &entry))
+		entries = append(entries, entry)
+	}
+	require.NoError(t, scanner.Err())
+	return entries
+}
+
+// logWrapper 模拟业务侧的一层日志封装函数
+func logWrapper(l *zap.Logger, msg string) {
+	l.Info(msg)
+}
+
+// callerLine 返回调用方所在的 "文件名:行号"
+func callerLine(t *testing.T) string {
+	t.Helper()
+	_, file, line, ok := runtime.Caller(1)
+	require.True(t, ok)
+	return fmt.Sprintf("%s:%d", filepath.Base(file), line+1)
+}
+
+// TestManager_GetWith 测试通过 GetWith 调整调用栈层数
+func TestManager_GetWith(t *testing.T) {
+	dir := t.TempDir()
+	m := newFileTestManager(t, dir)
+
+	l, err := m.GetWith("app", zap.AddCallerSkip(1))
+	require.NoError(t, err)
+	expected := callerLine(t)
+	logWrapper(l, "through wrapper")
+	require.NoError(t, m.Sync())
+
+	entries := readLogEntries(t, filepath.Join(dir, "app.log"))
+	require.Len(t, entries, 1)
+	assert.Equal(t, "log/"+expected, entries[0]["caller"])
+
+	// GetWith 不缓存派生实例
+	cached := m.MustGet("app")
+	assert.NotSame(t, cached, l)
+
+	_, err = m.GetWith("")
+	assert.ErrorIs(t, err, ErrEmptyBizName)
+}
+
+// TestConfig_CallerSkip 测试通过配置全局调整调用栈层数
+func TestConfig_CallerSkip(t *testing.T) {
+	dir := t.TempDir()
+	m, err := NewManager(Config{
+		Level:      "info",
+		CallerSkip: 1,
+		Outputs: []OutputConfig{
+			{Type: OutputTypeFile, Format: FormatJSON, File: &FileOutputConfig{Dir: dir}},
+		},
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = m.Close() })
+
+	expected := callerLine(t)
+	logWrapper(m.MustGet("app"), "through wrapper")
+	require.NoError(t, m.Sync())
+
+	entries := readLogEntries(t, filepath.Join(dir, "app.log"))
+	require.Len(t, entries, 1)
+	assert.Equal(t, "log/"+expected, entries[0]["caller"])
+
+	_, err = NewManager(Config{
+		CallerSkip: -1,
+		Outputs:    []OutputConfig{{Type: OutputTypeConsole}},
+	})
+	assert.ErrorIs(t, err, ErrInvalidConfigValue)
+}
+
+// TestConfig_StacktraceLevel 测试只有达到堆栈阈值的日志附带 stacktrace 字段
+func TestConfig_StacktraceLevel(t *testing.T) {
+	dir := t.TempDir()
+	m, err := NewManager(Config{
+		Level:           "info",
+		StacktraceLevel: "error",
+		Outputs: []OutputConfig{
+			{Type: OutputTypeFile, Format: FormatJSON, File: &FileOutputConfig{Dir: dir}},
+		},
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = m.Close() })
+
+	logger := m.MustGet("app")
+	logger.Warn("warn entry")
+	logger.Error("error entry")
+
+	// 调整日志级别不影响堆栈阈值
+	require.NoError(t, m.SetLevel("app", "debug"))
+	logger.Warn("warn after set level")
+	require.NoError(t, m.Sync())
+
+	entries := readLogEntries(t, filepath.Join(dir, "app.log"))
+	require.Len(t, entries, 3)
+	assert.NotContains(t, entries[0], "stacktrace")
+	assert.NotEmpty(t, entries[1]["stacktrace"])
+	assert.Contains(t, entries[1]["stacktrace"], "TestConfig_StacktraceLevel")
+	assert.NotContains(t, entries[2], "stacktrace")
+
+	_, err = NewManager(Config{
+		StacktraceLevel: "bogus",
+		Outputs:         []OutputConfig{{Type: OutputTypeConsole}},
+	})
+	assert.True(t, IsInvalidLogLevel(err))
+}
+
+// TestConfig_Development 测试开发模式下 DPanic 触发 panic
+func TestConfig_Development(t *testing.T) {
+	cfg := Config{Outputs: []OutputConfig{{Type: OutputTypeFile, File: &FileOutputConfig{Dir: t.TempDir()}}}}
+	m, err := NewManager(cfg)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = m.Close() })
+	assert.NotPanics(t, func() { m.MustGet("app").DPanic("dpanic") })
+
+	cfg.Development = true
+	dev, err := NewManager(cfg)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = dev.Close() })
+	assert.Panics(t, func() { dev.MustGet("app").DPanic("dpanic") })
+}
+
+// TestManager_Named 测试命名子日志共享业务日志文件和级别
+func TestManager_Named(t *testing.T) {
+	dir := t.TempDir()
+	m := newFileTestManager(t, dir)
+
+	child, err := m.Named("app", "payment")
+	require.NoError(t, err)
+
+	child.Debug("debug before SetLevel")
+	require.NoError(t, m.SetLevel("app", "debug"))
+	child.Debug("debug after SetLevel")
+	require.NoError(t, m.Sync())
+
+	entries := readLogEntries(t, filepath.Join(dir, "app.log"))
+	require.Len(t, entries, 1)
+	assert.Equal(t, "debug after SetLevel", entries[0]["msg"])
+	assert.Equal(t, "payment", entries[0]["logger"])
+	assert.Equal(t, "app", entries[0]["biz"])
+
+	_, err = m.Named("", "payment")
+	assert.ErrorIs(t, err, ErrEmptyBizName)
+}
+
+// TestManager_NilGet 测试 nil Manager 返回不输出任何内容的日志实例
+func TestManager_NilGet(t *testing.T) {
+	var m *Manager
+	l, err := m.Get("app")
+	require.NoError(t, err)
+	assert.NotPanics(t, func() {
+		l.Info("discarded")
+		m.MustGet("app").Warn("discarded")
+	})
+}
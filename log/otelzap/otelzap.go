@@ -0,0 +1,26 @@
+// Package otelzap 将 OpenTelemetry 的 span 上下文接入 log 包，
+// 使 Config.Trace 启用时 Manager.For 返回的日志实例带有 trace_id 与 span_id 字段。
+// 它是独立的子包，不使用 OpenTelemetry 的应用无需引入该依赖。
+package otelzap
+
+import (
+	"context"
+
+	"github.com/qq1060656096/drugo/log"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Extract 从上下文中提取 OpenTelemetry span 的 trace ID 与 span ID（小写十六进制），
+// 上下文中没有有效的 span 时 ok 为 false。它实现了 log.TraceExtractor。
+func Extract(ctx context.Context) (traceID, spanID string, ok bool) {
+	sc := trace.SpanContextFromContext(ctx)
+	if !sc.IsValid() {
+		return "", "", false
+	}
+	return sc.TraceID().String(), sc.SpanID().String(), true
+}
+
+// Install 将 Extract 设置为 log 包的链路追踪提取函数，通常在程序启动时调用一次。
+func Install() {
+	log.SetTraceExtractor(Extract)
+}
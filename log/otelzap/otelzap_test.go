@@ -0,0 +1,58 @@
+package otelzap
+
+import (
+	"context"
+	"testing"
+
+	"github.com/qq1060656096/drugo/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// spanContext 构造一个有效的 span 上下文
+func spanContext(t *testing.T) context.Context {
+	t.Helper()
+	traceID, err := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	require.NoError(t, err)
+	spanID, err := trace.SpanIDFromHex("00f067aa0ba902b7")
+	require.NoError(t, err)
+	sc := trace.NewSpanContext(trace.SpanContextConfig{TraceID: traceID, SpanID: spanID, TraceFlags: trace.FlagsSampled})
+	return trace.ContextWithSpanContext(context.Background(), sc)
+}
+
+// TestExtract 测试提取十六进制的 trace ID 与 span ID，没有有效 span 时返回 false
+func TestExtract(t *testing.T) {
+	traceID, spanID, ok := Extract(spanContext(t))
+	assert.True(t, ok)
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", traceID)
+	assert.Equal(t, "00f067aa0ba902b7", spanID)
+
+	_, _, ok = Extract(context.Background())
+	assert.False(t, ok)
+
+	invalid := trace.ContextWithSpanContext(context.Background(), trace.SpanContext{})
+	_, _, ok = Extract(invalid)
+	assert.False(t, ok)
+}
+
+// TestInstall 测试安装后 log.WithTrace 为有 span 的上下文添加字段
+func TestInstall(t *testing.T) {
+	Install()
+	t.Cleanup(func() { log.SetTraceExtractor(nil) })
+
+	core, logs := observer.New(zap.InfoLevel)
+	logger := zap.New(core)
+	log.WithTrace(spanContext(t), logger).Info("traced")
+	log.WithTrace(context.Background(), logger).Info("plain")
+
+	entries := logs.All()
+	require.Len(t, entries, 2)
+	assert.Equal(t, map[string]any{
+		log.TraceIDField: "4bf92f3577b34da6a3ce929d0e0e4736",
+		log.SpanIDField:  "00f067aa0ba902b7",
+	}, entries[0].ContextMap())
+	assert.Empty(t, entries[1].ContextMap())
+}
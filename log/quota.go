@@ -0,0 +1,182 @@
+package log
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// DefaultQuotaScanInterval 是未设置 QuotaScanInterval 时目录配额的扫描间隔
+const DefaultQuotaScanInterval = time.Minute
+
+// quotaLogf 输出配额淘汰日志，默认写入标准错误，测试中可以替换
+var quotaLogf = func(format string, args ...any) {
+	fmt.Fprintf(os.Stderr, format+"\n", args...)
+}
+
+// EvictionStats 是目录配额（Config.MaxTotalSizeMB）淘汰轮转文件的统计
+type EvictionStats struct {
+	Count      uint64    // 累计淘汰的文件数量
+	FreedBytes int64     // 累计释放的字节数
+	LastFile   string    // 最近一次淘汰的文件路径
+	LastAt     time.Time // 最近一次淘汰的时间
+}
+
+// quotaWatcher 是定期检查目录配额的协程，Rotate 之后也会触发一次检查
+type quotaWatcher struct {
+	trigger chan struct{}
+	cancel  context.CancelFunc
+	done    chan struct{}
+}
+
+// rotatedFile 是一个待检查的轮转文件
+type rotatedFile struct {
+	path    string
+	size    int64
+	modTime time.Time
+}
+
+// startQuota 启动目录配额协程，MaxTotalSizeMB 为 0 时不启动
+func (m *Manager) startQuota() {
+	if m.cfg.MaxTotalSizeMB <= 0 {
+		return
+	}
+	interval := m.cfg.QuotaScanInterval
+	if interval <= 0 {
+		interval = DefaultQuotaScanInterval
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	q := &quotaWatcher{
+		trigger: make(chan struct{}, 1),
+		cancel:  cancel,
+		done:    make(chan struct{}),
+	}
+	m.quota = q
+	go func() {
+		defer close(q.done)
+		m.enforceQuota()
+		timer := m.clock().NewTimer(interval)
+		defer timer.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-timer.C():
+				timer.Reset(interval)
+			case <-q.trigger:
+			}
+			m.enforceQuota()
+		}
+	}()
+}
+
+// stopQuota 停止目录配额协程并等待其退出，可以重复调用
+func (m *Manager) stopQuota() {
+	m.quotaMu.Lock()
+	q := m.quota
+	m.quota = nil
+	m.quotaMu.Unlock()
+	if q == nil {
+		return
+	}
+	q.cancel()
+	<-q.done
+}
+
+// triggerQuota 请求配额协程立即检查一次，协程未启动或已有待处理的请求时不做任何操作
+func (m *Manager) triggerQuota() {
+	m.quotaMu.Lock()
+	defer m.quotaMu.Unlock()
+	if m.quota == nil {
+		return
+	}
+	select {
+	case m.quota.trigger <- struct{}{}:
+	default:
+	}
+}
+
+// enforceQuota 检查所有文件输出目录（包括路由目录），轮转文件的总大小超过 MaxTotalSizeMB 时
+// 按修改时间从旧到新删除轮转文件，直到不超过配额。正在写入的 .log 文件不会被删除。
+func (m *Manager) enforceQuota() {
+	limit := int64(m.cfg.MaxTotalSizeMB) << 20
+	if limit <= 0 {
+		return
+	}
+	var dirs []string
+	for dir := range m.cfg.archiveDirs() {
+		dirs = append(dirs, dir)
+	}
+	sort.Strings(dirs)
+
+	m.evictMu.Lock()
+	defer m.evictMu.Unlock()
+	for _, dir := range dirs {
+		m.enforceDirQuota(dir, limit)
+	}
+}
+
+// enforceDirQuota 对单个目录执行配额检查，调用方需持有 evictMu
+func (m *Manager) enforceDirQuota(dir string, limit int64) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		// 目录可能尚未创建（还没有写入过日志）
+		return
+	}
+	var files []rotatedFile
+	var total int64
+	for _, e := range entries {
+		if e.IsDir() || !rotatedFilePattern.MatchString(e.Name()) {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			continue // 文件已被删除，例如归档后删除
+		}
+		files = append(files, rotatedFile{path: filepath.Join(dir, e.Name()), size: info.Size(), modTime: info.ModTime()})
+		total += info.Size()
+	}
+	if total <= limit {
+		return
+	}
+
+	sort.Slice(files, func(i, j int) bool {
+		if !files[i].modTime.Equal(files[j].modTime) {
+			return files[i].modTime.Before(files[j].modTime)
+		}
+		return files[i].path < files[j].path
+	})
+	for _, f := range files {
+		if total <= limit {
+			return
+		}
+		if err := os.Remove(f.path); err != nil {
+			if os.IsNotExist(err) {
+				total -= f.size
+			} else {
+				quotaLogf("log quota: remove %s failed: %v", f.path, err)
+			}
+			continue
+		}
+		total -= f.size
+		m.evictions.Count++
+		m.evictions.FreedBytes += f.size
+		m.evictions.LastFile = f.path
+		m.evictions.LastAt = m.clock().Now()
+		quotaLogf("log quota: evicted %s, freed %d bytes", f.path, f.size)
+	}
+}
+
+// Evictions 返回目录配额淘汰轮转文件的统计，未启用 MaxTotalSizeMB 时为零值。
+// 与 WriteErrors 一起用于发现日志占满磁盘之类的问题。
+func (m *Manager) Evictions() EvictionStats {
+	if m == nil {
+		return EvictionStats{}
+	}
+	m.evictMu.Lock()
+	defer m.evictMu.Unlock()
+	return m.evictions
+}
@@ -0,0 +1,198 @@
+package log
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/qq1060656096/drugo/pkg/clock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// quotaRecorder 替换 quotaLogf，记录淘汰日志
+type quotaRecorder struct {
+	mu   sync.Mutex
+	msgs []string
+}
+
+func recordQuotaLogs(t *testing.T) *quotaRecorder {
+	t.Helper()
+	rec := &quotaRecorder{}
+	orig := quotaLogf
+	quotaLogf = func(format string, args ...any) {
+		rec.mu.Lock()
+		defer rec.mu.Unlock()
+		rec.msgs = append(rec.msgs, fmt.Sprintf(format, args...))
+	}
+	t.Cleanup(func() { quotaLogf = orig })
+	return rec
+}
+
+func (r *quotaRecorder) Messages() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string(nil), r.msgs...)
+}
+
+// writeSizedFile 创建大小为 size 字节、修改时间为 age 之前的文件
+func writeSizedFile(t *testing.T, dir, name string, size int64, age time.Duration) {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	require.NoError(t, os.WriteFile(path, make([]byte, size), 0644))
+	mtime := time.Now().Add(-age)
+	require.NoError(t, os.Chtimes(path, mtime, mtime))
+}
+
+// listDir 返回目录中按名称排序的文件名
+func listDir(t *testing.T, dir string) []string {
+	t.Helper()
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	var names []string
+	for _, e := range entries {
+		names = append(names, e.Name())
+	}
+	sort.Strings(names)
+	return names
+}
+
+func newQuotaTestManager(t *testing.T, dir string, maxTotalMB int) *Manager {
+	t.Helper()
+	m, err := NewManager(Config{
+		Outputs: []OutputConfig{
+			{Type: OutputTypeFile, Format: FormatJSON, File: &FileOutputConfig{Dir: dir}},
+		},
+		MaxTotalSizeMB:    maxTotalMB,
+		QuotaScanInterval: time.Hour,
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = m.Close() })
+	return m
+}
+
+// TestManager_Quota_EvictsOldest 测试跨业务按修改时间从旧到新淘汰轮转文件，活动文件不受影响
+func TestManager_Quota_EvictsOldest(t *testing.T) {
+	rec := recordQuotaLogs(t)
+	dir := t.TempDir()
+	const kb = 1 << 10
+	writeSizedFile(t, dir, "app.log", 2<<20, 0) // 活动文件不计入配额，也不会被删除
+	writeSizedFile(t, dir, "app-2024-01-01T00-00-00.000.log", 300*kb, 4*time.Hour)
+	writeSizedFile(t, dir, "order-2024-01-01T01-00-00.000.log.gz", 200*kb, 3*time.Hour)
+	writeSizedFile(t, dir, "app-2024-01-01T02-00-00.000.log", 400*kb, 2*time.Hour)
+	writeSizedFile(t, dir, "order-2024-01-01T03-00-00.000.log", 500*kb, time.Hour)
+	writeSizedFile(t, dir, "notes.txt", 2<<20, 5*time.Hour)
+
+	m := newQuotaTestManager(t, dir, 1)
+	m.enforceQuota()
+
+	// 总计 1400KB，删除最旧的 300KB 与 200KB 后为 900KB，不超过 1MB
+	assert.Equal(t, []string{
+		"app-2024-01-01T02-00-00.000.log",
+		"app.log",
+		"notes.txt",
+		"order-2024-01-01T03-00-00.000.log",
+	}, listDir(t, dir))
+	assert.Equal(t, []string{
+		"log quota: evicted " + filepath.Join(dir, "app-2024-01-01T00-00-00.000.log") + ", freed 307200 bytes",
+		"log quota: evicted " + filepath.Join(dir, "order-2024-01-01T01-00-00.000.log.gz") + ", freed 204800 bytes",
+	}, rec.Messages())
+
+	stats := m.Evictions()
+	assert.Equal(t, uint64(2), stats.Count)
+	assert.Equal(t, int64(500*kb), stats.FreedBytes)
+	assert.Equal(t, filepath.Join(dir, "order-2024-01-01T01-00-00.000.log.gz"), stats.LastFile)
+
+	// 已经不超过配额，再次检查不删除任何文件
+	m.enforceQuota()
+	assert.Equal(t, uint64(2), m.Evictions().Count)
+}
+
+// TestManager_Quota_RotateTriggers 测试 Rotate 之后触发配额检查，Close 停止配额协程
+func TestManager_Quota_RotateTriggers(t *testing.T) {
+	recordQuotaLogs(t)
+	dir := t.TempDir()
+	m := newQuotaTestManager(t, dir, 1)
+	m.MustGet("app").Info("hello")
+
+	// 使用近期的时间戳，避免被 lumberjack 按 MaxAge 清理
+	older := "app-" + time.Now().Add(-2*time.Hour).UTC().Format("2006-01-02T15-04-05.000") + ".log"
+	newer := "app-" + time.Now().Add(-time.Hour).UTC().Format("2006-01-02T15-04-05.000") + ".log"
+	writeSizedFile(t, dir, older, 700<<10, 2*time.Hour)
+	writeSizedFile(t, dir, newer, 700<<10, time.Hour)
+	require.NoError(t, m.Rotate("app"))
+
+	assert.Eventually(t, func() bool { return m.Evictions().Count == 1 }, time.Second, 5*time.Millisecond)
+	assert.NoFileExists(t, filepath.Join(dir, older))
+	assert.FileExists(t, filepath.Join(dir, newer))
+	assert.FileExists(t, filepath.Join(dir, "app.log"))
+
+	q := m.quota
+	require.NotNil(t, q)
+	require.NoError(t, m.Close())
+	assert.Nil(t, m.quota)
+	select {
+	case <-q.done:
+	default:
+		t.Fatal("quota goroutine must stop on Close")
+	}
+	assert.NotPanics(t, m.triggerQuota)
+}
+
+// TestManager_Quota_Clock 测试配额协程按 Config.Clock 的时间定期检查
+func TestManager_Quota_Clock(t *testing.T) {
+	recordQuotaLogs(t)
+	dir := t.TempDir()
+	c := clock.NewFake(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	m, err := NewManager(Config{
+		Outputs: []OutputConfig{
+			{Type: OutputTypeFile, Format: FormatJSON, File: &FileOutputConfig{Dir: dir}},
+		},
+		MaxTotalSizeMB:    1,
+		QuotaScanInterval: time.Hour,
+		Clock:             c,
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = m.Close() })
+
+	c.BlockUntilWaiters(1) // 首次检查完成，等待下一个检查间隔
+	writeSizedFile(t, dir, "app-2024-01-01T00-00-00.000.log", 700<<10, 2*time.Hour)
+	writeSizedFile(t, dir, "app-2024-01-01T01-00-00.000.log", 700<<10, time.Hour)
+	c.Advance(time.Hour - time.Second)
+	assert.Zero(t, m.Evictions().Count)
+
+	c.Advance(time.Second)
+	require.Eventually(t, func() bool { return m.Evictions().Count == 1 }, time.Second, time.Millisecond)
+	assert.Equal(t, c.Now(), m.Evictions().LastAt)
+	assert.Equal(t, []string{"app-2024-01-01T01-00-00.000.log"}, listDir(t, dir))
+}
+
+// TestManager_Quota_Disabled 测试未设置 MaxTotalSizeMB 时不启动协程也不删除文件
+func TestManager_Quota_Disabled(t *testing.T) {
+	dir := t.TempDir()
+	writeSizedFile(t, dir, "app-2024-01-01T00-00-00.000.log", 2<<20, time.Hour)
+	m := newQuotaTestManager(t, dir, 0)
+	assert.Nil(t, m.quota)
+
+	m.enforceQuota()
+	assert.FileExists(t, filepath.Join(dir, "app-2024-01-01T00-00-00.000.log"))
+	assert.Zero(t, m.Evictions())
+
+	var nilManager *Manager
+	assert.Zero(t, nilManager.Evictions())
+}
+
+// TestConfig_Validate_Quota 测试配额配置不能为负数
+func TestConfig_Validate_Quota(t *testing.T) {
+	outputs := []OutputConfig{{Type: OutputTypeConsole}}
+	for _, cfg := range []Config{
+		{Outputs: outputs, MaxTotalSizeMB: -1},
+		{Outputs: outputs, QuotaScanInterval: -time.Second},
+	} {
+		assert.ErrorIs(t, cfg.Validate(), ErrInvalidConfigValue)
+	}
+}
@@ -0,0 +1,50 @@
+package log
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Rotate 立即轮转指定业务的日志文件。
+// 当前文件会被重命名为带时间戳的备份文件，并创建新的日志文件继续写入；
+// 启用 MaxTotalSizeMB 时轮转后会触发一次目录配额检查。
+// 未输出到文件的日志实例调用此方法不做任何操作。
+// bizName: 业务名称
+// 返回: 业务不存在时返回 ErrLoggerNotFound
+func (m *Manager) Rotate(bizName string) error {
+	if bizName == "" {
+		return ErrEmptyBizName
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	defer m.triggerQuota()
+
+	logger, ok := m.loggers[bizName]
+	if !ok {
+		return fmt.Errorf("logger '%s': %w", bizName, ErrLoggerNotFound)
+	}
+	// 先刷新缓冲区，保证轮转前的日志写入旧文件
+	_ = logger.Sync()
+
+	var errs []error
+	for _, f := range m.files[bizName] {
+		if err := f.Rotate(); err != nil {
+			errs = append(errs, fmt.Errorf("rotate logger '%s': %w", bizName, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// RotateAll 轮转所有已创建日志实例的日志文件。
+// 返回: 轮转过程中的所有错误（合并后）
+func (m *Manager) RotateAll() error {
+	var errs []error
+	for _, bizName := range m.List() {
+		// 并发 Remove 的日志实例直接跳过
+		if err := m.Rotate(bizName); err != nil && !IsLoggerNotFound(err) {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,92 @@
+package log
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newFileTestManager(t *testing.T, dir string) *Manager {
+	t.Helper()
+	m, err := NewManager(Config{
+		Level: "info",
+		Outputs: []OutputConfig{
+			{Type: OutputTypeFile, Format: FormatJSON, File: &FileOutputConfig{Dir: dir}},
+		},
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = m.Close() })
+	return m
+}
+
+// TestManager_Rotate 测试手动轮转日志文件
+func TestManager_Rotate(t *testing.T) {
+	dir := t.TempDir()
+	m := newFileTestManager(t, dir)
+
+	l := m.MustGet("app")
+	l.Info("before rotate 1")
+	l.Info("before rotate 2")
+
+	require.NoError(t, m.Rotate("app"))
+
+	l.Info("after rotate")
+	require.NoError(t, m.Sync())
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+
+	var current, backup string
+	for _, e := range entries {
+		content, err := os.ReadFile(filepath.Join(dir, e.Name()))
+		require.NoError(t, err)
+		if e.Name() == "app.log" {
+			current = string(content)
+		} else {
+			assert.True(t, strings.HasPrefix(e.Name(), "app-"), e.Name())
+			backup = string(content)
+		}
+	}
+
+	assert.Contains(t, backup, "before rotate 1")
+	assert.Contains(t, backup, "before rotate 2")
+	assert.NotContains(t, backup, "after rotate")
+	assert.Contains(t, current, "after rotate")
+	assert.NotContains(t, current, "before rotate")
+}
+
+// TestManager_Rotate_Errors 测试轮转的错误场景
+func TestManager_Rotate_Errors(t *testing.T) {
+	m := newFileTestManager(t, t.TempDir())
+
+	assert.True(t, IsEmptyBizName(m.Rotate("")))
+	assert.True(t, IsLoggerNotFound(m.Rotate("unknown")))
+}
+
+// TestManager_Rotate_Console 测试仅控制台输出时轮转不做任何操作
+func TestManager_Rotate_Console(t *testing.T) {
+	m, err := NewManager(Config{Outputs: []OutputConfig{{Type: OutputTypeConsole}}})
+	require.NoError(t, err)
+	m.MustGet("app")
+	assert.NoError(t, m.Rotate("app"))
+}
+
+// TestManager_RotateAll 测试轮转所有日志文件
+func TestManager_RotateAll(t *testing.T) {
+	dir := t.TempDir()
+	m := newFileTestManager(t, dir)
+
+	m.MustGet("a").Info("a1")
+	m.MustGet("b").Info("b1")
+
+	require.NoError(t, m.RotateAll())
+
+	matches, err := filepath.Glob(filepath.Join(dir, "*-*.log"))
+	require.NoError(t, err)
+	assert.Len(t, matches, 2)
+}
@@ -0,0 +1,114 @@
+package log
+
+import (
+	"fmt"
+	"strings"
+)
+
+// routeWildcard 是路由匹配规则中的前缀通配后缀
+const routeWildcard = ".*"
+
+// Route 按业务名称路由文件输出，例如将审计日志写入单独的目录。
+// 路由只影响 type 为 file 的输出，控制台输出保持不变。
+type Route struct {
+	// Match 匹配规则：精确的业务名称（如 "audit"），或以 ".*" 结尾的前缀（如 "audit.*" 匹配 "audit.login"）
+	Match string `yaml:"match" mapstructure:"match"`
+	// Dir 匹配的业务日志写入的目录，不能为空
+	Dir string `yaml:"dir" mapstructure:"dir"`
+	// Format 覆盖文件输出的格式，为空时沿用输出本身的配置
+	Format string `yaml:"format" mapstructure:"format"`
+	// 以下轮转配置为 0 或 nil 时沿用输出本身的配置
+	MaxSize    int   `yaml:"max_size" mapstructure:"max_size"`
+	MaxBackups int   `yaml:"max_backups" mapstructure:"max_backups"`
+	MaxAge     int   `yaml:"max_age" mapstructure:"max_age"`
+	Compress   *bool `yaml:"compress" mapstructure:"compress"`
+}
+
+// Matches 判断业务名称是否匹配该路由
+func (r Route) Matches(bizName string) bool {
+	if prefix, ok := strings.CutSuffix(r.Match, routeWildcard); ok {
+		return strings.HasPrefix(bizName, prefix+".")
+	}
+	return r.Match == bizName
+}
+
+func (r *Route) validateAt(i int) error {
+	prefix, _ := strings.CutSuffix(r.Match, routeWildcard)
+	if prefix == "" || strings.Contains(prefix, "*") {
+		return fmt.Errorf("%w: routes[%d].match=%q", ErrInvalidConfigValue, i, r.Match)
+	}
+	if r.Dir == "" {
+		return fmt.Errorf("%w: routes[%d].dir", ErrEmptyLogDir, i)
+	}
+	if r.Format != "" && !isValidOutputFormat(r.Format) {
+		return fmt.Errorf("%w: routes[%d].format=%s", ErrInvalidLogFormat, i, r.Format)
+	}
+	if r.MaxSize < 0 || r.MaxBackups < 0 || r.MaxAge < 0 {
+		return fmt.Errorf("%w: routes[%d]", ErrInvalidConfigValue, i)
+	}
+	return nil
+}
+
+// route 返回第一个匹配 bizName 的路由，没有匹配时返回 nil
+func (c Config) route(bizName string) *Route {
+	for i := range c.Routes {
+		if c.Routes[i].Matches(bizName) {
+			return &c.Routes[i]
+		}
+	}
+	return nil
+}
+
+// forBiz 返回应用了路由规则后 bizName 实际使用的配置，不修改 c
+func (c Config) forBiz(bizName string) Config {
+	r := c.route(bizName)
+	if r == nil {
+		return c
+	}
+
+	outputs := make([]OutputConfig, len(c.Outputs))
+	copy(outputs, c.Outputs)
+	for i := range outputs {
+		out := &outputs[i]
+		if out.Type != OutputTypeFile || out.File == nil {
+			continue
+		}
+		file := *out.File
+		file.Dir = r.Dir
+		if r.MaxSize > 0 {
+			file.MaxSize = r.MaxSize
+		}
+		if r.MaxBackups > 0 {
+			file.MaxBackups = r.MaxBackups
+		}
+		if r.MaxAge > 0 {
+			file.MaxAge = r.MaxAge
+		}
+		if r.Compress != nil {
+			file.Compress = *r.Compress
+		}
+		out.File = &file
+		if r.Format != "" {
+			out.Format = r.Format
+		}
+	}
+	c.Outputs = outputs
+	return c
+}
+
+// PathFor 返回 bizName 的日志文件实际写入的目录（第一个文件输出的目录，已应用路由规则）。
+// 没有文件输出时返回 ErrNoFileOutput。
+func (m *Manager) PathFor(bizName string) (string, error) {
+	if m == nil {
+		return "", ErrNilManager
+	}
+	if bizName == "" {
+		return "", ErrEmptyBizName
+	}
+	for _, out := range m.cfg.forBiz(bizName).Outputs {
+		if out.Type == OutputTypeFile && out.File != nil {
+			return out.File.Dir, nil
+		}
+	}
+	return "", ErrNoFileOutput
+}
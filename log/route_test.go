@@ -0,0 +1,121 @@
+package log
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestRoute_Matches 测试精确匹配与前缀匹配
+func TestRoute_Matches(t *testing.T) {
+	exact := Route{Match: "audit"}
+	prefix := Route{Match: "audit.*"}
+
+	assert.True(t, exact.Matches("audit"))
+	assert.False(t, exact.Matches("audit.login"))
+	assert.True(t, prefix.Matches("audit.login"))
+	assert.True(t, prefix.Matches("audit.a.b"))
+	assert.False(t, prefix.Matches("audit"))
+	assert.False(t, prefix.Matches("auditor.login"))
+}
+
+// TestConfig_ValidateRoutes 测试路由规则的校验
+func TestConfig_ValidateRoutes(t *testing.T) {
+	base := func(routes ...Route) Config {
+		return Config{
+			Outputs: []OutputConfig{{Type: OutputTypeFile, File: &FileOutputConfig{Dir: t.TempDir()}}},
+			Routes:  routes,
+		}
+	}
+
+	tests := []struct {
+		name  string
+		route Route
+		check func(error) bool
+	}{
+		{"empty match", Route{Dir: "/tmp"}, IsInvalidConfigValue},
+		{"bare wildcard", Route{Match: ".*", Dir: "/tmp"}, IsInvalidConfigValue},
+		{"wildcard in middle", Route{Match: "a*.b", Dir: "/tmp"}, IsInvalidConfigValue},
+		{"empty dir", Route{Match: "audit"}, IsEmptyLogDir},
+		{"invalid format", Route{Match: "audit", Dir: "/tmp", Format: "xml"}, IsInvalidLogFormat},
+		{"negative rotation", Route{Match: "audit", Dir: "/tmp", MaxAge: -1}, IsInvalidConfigValue},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := base(tt.route)
+			err := cfg.Validate()
+			require.Error(t, err)
+			assert.True(t, tt.check(err), err.Error())
+		})
+	}
+
+	cfg := base(Route{Match: "audit.*", Dir: "/tmp", Format: FormatJSON})
+	assert.NoError(t, cfg.Validate())
+}
+
+// TestManager_Routes 测试匹配路由的业务日志写入路由目录，其余写入默认目录
+func TestManager_Routes(t *testing.T) {
+	appDir := t.TempDir()
+	auditDir := filepath.Join(t.TempDir(), "secure")
+	compress := true
+	m, err := NewManager(Config{
+		Level: "info",
+		Outputs: []OutputConfig{
+			{Type: OutputTypeFile, Format: FormatText, File: &FileOutputConfig{Dir: appDir, MaxAge: 7}},
+		},
+		Routes: []Route{
+			{Match: "audit.*", Dir: auditDir, Format: FormatJSON, MaxAge: 365, Compress: &compress},
+			{Match: "audit.login", Dir: t.TempDir()}, // 前面的路由优先
+		},
+	})
+	require.NoError(t, err)
+	defer m.Close()
+
+	for _, biz := range []string{"order", "audit.login"} {
+		m.MustGet(biz).Info("hello")
+	}
+	require.NoError(t, m.Sync())
+
+	assert.FileExists(t, filepath.Join(appDir, "order.log"))
+	assert.FileExists(t, filepath.Join(auditDir, "audit.login.log"))
+	assert.NoFileExists(t, filepath.Join(appDir, "audit.login.log"))
+
+	entries := readLogEntries(t, filepath.Join(auditDir, "audit.login.log"))
+	require.Len(t, entries, 1)
+	assert.Equal(t, "hello", entries[0]["msg"])
+
+	path, err := m.PathFor("audit.login")
+	require.NoError(t, err)
+	assert.Equal(t, auditDir, path)
+	path, err = m.PathFor("order")
+	require.NoError(t, err)
+	assert.Equal(t, appDir, path)
+
+	// 路由覆盖轮转配置，不修改原始配置
+	routed := m.cfg.forBiz("audit.login").Outputs[0].File
+	assert.Equal(t, 365, routed.MaxAge)
+	assert.True(t, routed.Compress)
+	assert.Equal(t, 7, m.cfg.Outputs[0].File.MaxAge)
+
+	// List/SetLevel/Remove 与路由无关
+	assert.ElementsMatch(t, []string{"order", "audit.login"}, m.List())
+	require.NoError(t, m.SetLevel("audit.login", "error"))
+	require.NoError(t, m.Remove("audit.login"))
+	assert.Equal(t, []string{"order"}, m.List())
+}
+
+// TestManager_PathFor_Errors 测试 PathFor 的错误情况
+func TestManager_PathFor_Errors(t *testing.T) {
+	var nilManager *Manager
+	_, err := nilManager.PathFor("app")
+	assert.True(t, IsNilManager(err))
+
+	m, err := NewManager(Config{Outputs: []OutputConfig{{Type: OutputTypeConsole}}})
+	require.NoError(t, err)
+	_, err = m.PathFor("")
+	assert.True(t, IsEmptyBizName(err))
+	_, err = m.PathFor("app")
+	assert.True(t, IsNoFileOutput(err))
+}
@@ -0,0 +1,51 @@
+package log
+
+import (
+	"context"
+	"sync/atomic"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// TraceIDField 是 Config.Trace 启用时记录 trace ID 的字段名
+	TraceIDField = "trace_id"
+	// SpanIDField 是 Config.Trace 启用时记录 span ID 的字段名
+	SpanIDField = "span_id"
+)
+
+// TraceExtractor 从上下文中提取当前 span 的 trace ID 与 span ID（十六进制字符串），
+// 上下文中没有有效的 span 时 ok 为 false。
+type TraceExtractor func(ctx context.Context) (traceID, spanID string, ok bool)
+
+// traceExtractor 是 SetTraceExtractor 设置的全局提取函数
+var traceExtractor atomic.Pointer[TraceExtractor]
+
+// SetTraceExtractor 设置 Manager.For 使用的链路追踪提取函数，传入 nil 时清除。
+// log 包本身不依赖任何追踪实现，OpenTelemetry 用户可以使用 log/otelzap 提供的适配器：
+//
+//	otelzap.Install() // 等同于 log.SetTraceExtractor(otelzap.Extract)
+//
+// 只有 Config.Trace 为 true 的 Manager 才会调用该函数。此函数是并发安全的。
+func SetTraceExtractor(fn TraceExtractor) {
+	if fn == nil {
+		traceExtractor.Store(nil)
+		return
+	}
+	traceExtractor.Store(&fn)
+}
+
+// WithTrace 使用 SetTraceExtractor 设置的函数从上下文中提取 trace ID 与 span ID，
+// 并以 TraceIDField 与 SpanIDField 字段添加到 logger。
+// 没有设置提取函数、上下文中没有有效的 span 或 logger 为 nil 时原样返回 logger。
+func WithTrace(ctx context.Context, logger *zap.Logger) *zap.Logger {
+	fn := traceExtractor.Load()
+	if fn == nil || ctx == nil || logger == nil {
+		return logger
+	}
+	traceID, spanID, ok := (*fn)(ctx)
+	if !ok {
+		return logger
+	}
+	return logger.With(zap.String(TraceIDField, traceID), zap.String(SpanIDField, spanID))
+}
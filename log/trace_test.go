@@ -0,0 +1,84 @@
+package log
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+)
+
+type traceCtxKey struct{}
+
+// setTestTraceExtractor 设置从 traceCtxKey 读取 [trace ID, span ID] 的提取函数，测试结束后清除
+func setTestTraceExtractor(t *testing.T) {
+	t.Helper()
+	SetTraceExtractor(func(ctx context.Context) (string, string, bool) {
+		ids, ok := ctx.Value(traceCtxKey{}).([2]string)
+		return ids[0], ids[1], ok
+	})
+	t.Cleanup(func() { SetTraceExtractor(nil) })
+}
+
+// TestManager_For_Trace 测试启用 Trace 时有 span 的上下文添加 trace_id 与 span_id，没有 span 时不添加
+func TestManager_For_Trace(t *testing.T) {
+	setTestTraceExtractor(t)
+	dir := t.TempDir()
+	m, err := NewManager(Config{
+		Level:   "info",
+		Trace:   true,
+		Outputs: []OutputConfig{{Type: OutputTypeFile, Format: FormatJSON, File: &FileOutputConfig{Dir: dir}}},
+	})
+	require.NoError(t, err)
+	defer m.Close()
+
+	traced := context.WithValue(context.Background(), traceCtxKey{},
+		[2]string{"4bf92f3577b34da6a3ce929d0e0e4736", "00f067aa0ba902b7"})
+	l, err := m.For(traced, "order")
+	require.NoError(t, err)
+	l.Info("traced")
+
+	plain, err := m.For(context.Background(), "order")
+	require.NoError(t, err)
+	assert.Same(t, m.MustGet("order"), plain, "没有 span 时返回缓存的实例")
+	plain.Info("plain")
+
+	// 与 WithMinLevel 同时使用
+	escalated, err := m.For(WithMinLevel(traced, zapcore.DebugLevel), "order")
+	require.NoError(t, err)
+	escalated.Debug("escalated")
+	require.NoError(t, m.Sync())
+
+	entries := readLogEntries(t, filepath.Join(dir, "order.log"))
+	require.Len(t, entries, 3)
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", entries[0][TraceIDField])
+	assert.Equal(t, "00f067aa0ba902b7", entries[0][SpanIDField])
+	assert.NotContains(t, entries[1], TraceIDField)
+	assert.NotContains(t, entries[1], SpanIDField)
+	assert.Equal(t, "escalated", entries[2]["msg"])
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", entries[2][TraceIDField])
+}
+
+// TestManager_For_TraceDisabled 测试未启用 Trace 或未设置提取函数时不添加字段
+func TestManager_For_TraceDisabled(t *testing.T) {
+	traced := context.WithValue(context.Background(), traceCtxKey{}, [2]string{"t", "s"})
+
+	t.Run("未启用 Trace", func(t *testing.T) {
+		setTestTraceExtractor(t)
+		m, err := NewManager(Config{Level: "info", Outputs: []OutputConfig{{Type: OutputTypeConsole}}})
+		require.NoError(t, err)
+		l, err := m.For(traced, "order")
+		require.NoError(t, err)
+		assert.Same(t, m.MustGet("order"), l)
+	})
+
+	t.Run("未设置提取函数", func(t *testing.T) {
+		m, err := NewManager(Config{Level: "info", Trace: true, Outputs: []OutputConfig{{Type: OutputTypeConsole}}})
+		require.NoError(t, err)
+		l, err := m.For(traced, "order")
+		require.NoError(t, err)
+		assert.Same(t, m.MustGet("order"), l)
+	})
+}
@@ -0,0 +1,165 @@
+package log
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+	"gopkg.in/natefinch/lumberjack.v2"
+)
+
+// DefaultWriteFailureThreshold 是 Config.WriteFailureThreshold 为 0 时使用的连续写入失败阈值
+const DefaultWriteFailureThreshold = 5
+
+// WriteErrorStats 是单个业务日志文件输出的写入/同步失败统计
+type WriteErrorStats struct {
+	Count       uint64    // 累计失败次数
+	Consecutive uint64    // 当前连续失败次数，写入成功后清零
+	LastErr     error     // 最近一次失败的错误
+	LastAt      time.Time // 最近一次失败的时间
+}
+
+// WriteFailureCallback 在业务日志连续写入失败超过阈值时调用
+type WriteFailureCallback func(bizName string, err error)
+
+// newFileSink 将文件写入器包装为 zapcore.WriteSyncer，测试中可替换以注入写入失败
+var newFileSink = func(file *lumberjack.Logger) zapcore.WriteSyncer {
+	return zapcore.AddSync(file)
+}
+
+// writeStats 记录单个业务的写入失败，计数使用原子操作，
+// 成功路径上只有一次原子读取（连续失败计数不为 0 时再清零）。
+type writeStats struct {
+	bizName     string
+	m           *Manager
+	count       atomic.Uint64
+	consecutive atomic.Uint64
+
+	mu      sync.Mutex // 保护 lastErr 与 lastAt，只在失败路径上使用
+	lastErr error
+	lastAt  time.Time
+}
+
+// succeed 记录一次成功的写入，重置连续失败计数
+func (s *writeStats) succeed() {
+	if s.consecutive.Load() != 0 {
+		s.consecutive.Store(0)
+	}
+}
+
+// fail 记录一次失败，连续失败次数首次超过阈值时调用 OnWriteFailure 注册的回调
+func (s *writeStats) fail(err error) {
+	s.count.Add(1)
+	n := s.consecutive.Add(1)
+
+	s.mu.Lock()
+	s.lastErr = err
+	s.lastAt = time.Now()
+	s.mu.Unlock()
+
+	if n != uint64(s.m.writeFailureThreshold())+1 {
+		return
+	}
+	if fn := s.m.onWriteFailure.Load(); fn != nil {
+		(*fn)(s.bizName, err)
+	}
+}
+
+// snapshot 返回当前统计的副本
+func (s *writeStats) snapshot() WriteErrorStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return WriteErrorStats{
+		Count:       s.count.Load(),
+		Consecutive: s.consecutive.Load(),
+		LastErr:     s.lastErr,
+		LastAt:      s.lastAt,
+	}
+}
+
+// statsSyncer 在写入和同步时将结果记录到 writeStats
+type statsSyncer struct {
+	zapcore.WriteSyncer
+	stats *writeStats
+}
+
+func (w *statsSyncer) Write(p []byte) (int, error) {
+	n, err := w.WriteSyncer.Write(p)
+	if err != nil {
+		w.stats.fail(err)
+	} else {
+		w.stats.succeed()
+	}
+	return n, err
+}
+
+func (w *statsSyncer) Sync() error {
+	err := w.WriteSyncer.Sync()
+	if err != nil {
+		w.stats.fail(err)
+	}
+	return err
+}
+
+// wrapSink 为文件输出附加失败统计，stats 为 nil 时原样返回
+func wrapSink(ws zapcore.WriteSyncer, stats *writeStats) zapcore.WriteSyncer {
+	if stats == nil {
+		return ws
+	}
+	return &statsSyncer{WriteSyncer: ws, stats: stats}
+}
+
+// WriteErrors 返回发生过写入失败的业务日志的失败统计，键为业务名称。
+// 只统计文件输出：磁盘写满、目录权限变化等导致的失败会被 zap 丢弃，可以通过此方法发现。
+func (m *Manager) WriteErrors() map[string]WriteErrorStats {
+	if m == nil {
+		return nil
+	}
+	m.writeMu.Lock()
+	defer m.writeMu.Unlock()
+	result := make(map[string]WriteErrorStats)
+	for name, s := range m.writeStats {
+		if s.count.Load() > 0 {
+			result[name] = s.snapshot()
+		}
+	}
+	return result
+}
+
+// OnWriteFailure 设置业务日志连续写入失败超过 Config.WriteFailureThreshold 次时调用的回调，
+// 例如发送告警或切换为只输出到控制台。每轮连续失败只回调一次，写入恢复后重新计数。
+// 回调在写日志的 goroutine 中同步执行，不应阻塞；传入 nil 取消回调。
+func (m *Manager) OnWriteFailure(fn WriteFailureCallback) {
+	if m == nil {
+		return
+	}
+	if fn == nil {
+		m.onWriteFailure.Store(nil)
+		return
+	}
+	m.onWriteFailure.Store(&fn)
+}
+
+// statsFor 返回 bizName 的失败统计，首次调用时创建；统计在 Remove 和 Close 之后保留
+func (m *Manager) statsFor(bizName string) *writeStats {
+	m.writeMu.Lock()
+	defer m.writeMu.Unlock()
+	if m.writeStats == nil {
+		m.writeStats = make(map[string]*writeStats)
+	}
+	s, ok := m.writeStats[bizName]
+	if !ok {
+		s = &writeStats{bizName: bizName, m: m}
+		m.writeStats[bizName] = s
+	}
+	return s
+}
+
+// writeFailureThreshold 返回连续写入失败阈值
+func (m *Manager) writeFailureThreshold() int {
+	if m.cfg.WriteFailureThreshold > 0 {
+		return m.cfg.WriteFailureThreshold
+	}
+	return DefaultWriteFailureThreshold
+}
@@ -0,0 +1,156 @@
+package log
+
+import (
+	"errors"
+	"io"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+	"gopkg.in/natefinch/lumberjack.v2"
+)
+
+var errDiskFull = errors.New("no space left on device")
+
+// failingSink 是可以切换为失败状态的 WriteSyncer
+type failingSink struct {
+	failing atomic.Bool
+}
+
+func (s *failingSink) Write(p []byte) (int, error) {
+	if s.failing.Load() {
+		return 0, errDiskFull
+	}
+	return len(p), nil
+}
+
+func (s *failingSink) Sync() error {
+	if s.failing.Load() {
+		return errDiskFull
+	}
+	return nil
+}
+
+// useFailingSink 将文件输出替换为 failingSink，测试结束后恢复
+func useFailingSink(t *testing.T) *failingSink {
+	t.Helper()
+	sink := &failingSink{}
+	orig := newFileSink
+	newFileSink = func(*lumberjack.Logger) zapcore.WriteSyncer { return sink }
+	t.Cleanup(func() { newFileSink = orig })
+	return sink
+}
+
+// TestManager_WriteErrors 测试写入失败计数、回调触发以及恢复后重置连续失败计数
+func TestManager_WriteErrors(t *testing.T) {
+	sink := useFailingSink(t)
+	m, err := NewManager(Config{
+		Outputs:               []OutputConfig{{Type: OutputTypeFile, File: &FileOutputConfig{Dir: t.TempDir()}}},
+		WriteFailureThreshold: 2,
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = m.Close() })
+
+	var mu sync.Mutex
+	var calls []string
+	m.OnWriteFailure(func(bizName string, err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		assert.ErrorIs(t, err, errDiskFull)
+		calls = append(calls, bizName)
+	})
+	callCount := func() int {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(calls)
+	}
+
+	l := m.MustGet("order")
+	m.MustGet("user").Info("ok")
+	l.Info("ok")
+	assert.Empty(t, m.WriteErrors())
+
+	sink.failing.Store(true)
+	before := time.Now()
+	l.Info("lost 1")
+	l.Info("lost 2")
+	assert.Equal(t, 0, callCount(), "未超过阈值时不回调")
+	l.Info("lost 3")
+	l.Info("lost 4")
+	assert.Equal(t, 1, callCount(), "每轮连续失败只回调一次")
+
+	stats := m.WriteErrors()
+	require.Contains(t, stats, "order")
+	assert.NotContains(t, stats, "user")
+	assert.Equal(t, uint64(4), stats["order"].Count)
+	assert.Equal(t, uint64(4), stats["order"].Consecutive)
+	assert.ErrorIs(t, stats["order"].LastErr, errDiskFull)
+	assert.False(t, stats["order"].LastAt.Before(before))
+
+	// 写入恢复后连续失败计数清零，累计计数保留
+	sink.failing.Store(false)
+	l.Info("recovered")
+	stats = m.WriteErrors()
+	assert.Equal(t, uint64(4), stats["order"].Count)
+	assert.Zero(t, stats["order"].Consecutive)
+
+	// 新一轮连续失败再次回调；同步失败同样计数
+	sink.failing.Store(true)
+	l.Info("lost 5")
+	l.Info("lost 6")
+	assert.Error(t, l.Sync())
+	assert.Equal(t, 2, callCount())
+	assert.Equal(t, uint64(7), m.WriteErrors()["order"].Count)
+	assert.Equal(t, []string{"order", "order"}, calls)
+
+	// 取消回调
+	sink.failing.Store(false)
+	l.Info("recovered")
+	m.OnWriteFailure(nil)
+	sink.failing.Store(true)
+	for range 4 {
+		l.Info("lost")
+	}
+	assert.Equal(t, 2, callCount())
+}
+
+// TestManager_WriteErrors_Nil 测试 nil Manager
+func TestManager_WriteErrors_Nil(t *testing.T) {
+	var m *Manager
+	assert.Nil(t, m.WriteErrors())
+	m.OnWriteFailure(func(string, error) {})
+}
+
+// TestConfig_Validate_WriteFailureThreshold 测试连续写入失败阈值不能为负数
+func TestConfig_Validate_WriteFailureThreshold(t *testing.T) {
+	cfg := Config{Outputs: []OutputConfig{{Type: OutputTypeConsole}}, WriteFailureThreshold: -1}
+	assert.True(t, IsInvalidConfigValue(cfg.Validate()))
+}
+
+// BenchmarkStatsSyncer_Write 对比写入成功时统计包装的额外开销
+func BenchmarkStatsSyncer_Write(b *testing.B) {
+	p := []byte(`{"level":"info","msg":"hello"}` + "\n")
+	raw := zapcore.AddSync(io.Discard)
+
+	b.Run("raw", func(b *testing.B) {
+		b.ReportAllocs()
+		b.RunParallel(func(pb *testing.PB) {
+			for pb.Next() {
+				_, _ = raw.Write(p)
+			}
+		})
+	})
+	b.Run("stats", func(b *testing.B) {
+		ws := wrapSink(raw, &writeStats{bizName: "bench", m: &Manager{}})
+		b.ReportAllocs()
+		b.RunParallel(func(pb *testing.PB) {
+			for pb.Next() {
+				_, _ = ws.Write(p)
+			}
+		})
+	})
+}
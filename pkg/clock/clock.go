@@ -0,0 +1,127 @@
+// Package clock 提供可替换的时钟，框架中基于时间的行为（停机超时、防抖、定期扫描等）都通过它获取时间与定时器，
+// 测试中使用 Fake 推进时间即可得到确定的结果，不需要真实的 sleep。
+//
+// 该包不依赖框架的其他包，kernel、config 与 log 都可以使用它；kernel.Clock 等是该包类型的别名。
+package clock
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Clock 是时间来源的抽象，Real 使用系统时间，Fake 由测试手动推进。
+type Clock interface {
+	// Now 返回当前时间
+	Now() time.Time
+	// After 返回在 d 之后接收到当前时间的通道，等同于 NewTimer(d).C()
+	After(d time.Duration) <-chan time.Time
+	// NewTimer 创建在 d 之后触发的定时器
+	NewTimer(d time.Duration) Timer
+	// Sleep 阻塞 d
+	Sleep(d time.Duration)
+}
+
+// Timer 是 time.Timer 的抽象，语义与 Go 1.23 起的 time.Timer 相同：
+// Stop 与 Reset 返回后通道中不会残留过期的值。
+type Timer interface {
+	// C 返回定时器触发时接收时间的通道
+	C() <-chan time.Time
+	// Stop 停止定时器，定时器仍处于等待状态时返回 true
+	Stop() bool
+	// Reset 使定时器在 d 之后重新触发，定时器仍处于等待状态时返回 true
+	Reset(d time.Duration) bool
+}
+
+// Real 是使用系统时间的 Clock，零值即可使用。
+type Real struct{}
+
+// Now 返回 time.Now()
+func (Real) Now() time.Time {
+	return time.Now()
+}
+
+// After 返回 time.After(d)
+func (Real) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
+
+// NewTimer 返回包装了 time.NewTimer(d) 的定时器
+func (Real) NewTimer(d time.Duration) Timer {
+	return realTimer{time.NewTimer(d)}
+}
+
+// Sleep 调用 time.Sleep(d)
+func (Real) Sleep(d time.Duration) {
+	time.Sleep(d)
+}
+
+// realTimer 将 *time.Timer 适配为 Timer
+type realTimer struct {
+	t *time.Timer
+}
+
+func (r realTimer) C() <-chan time.Time        { return r.t.C }
+func (r realTimer) Stop() bool                 { return r.t.Stop() }
+func (r realTimer) Reset(d time.Duration) bool { return r.t.Reset(d) }
+
+// OrReal 返回 c，c 为 nil 时返回 Real。
+func OrReal(c Clock) Clock {
+	if c == nil {
+		return Real{}
+	}
+	return c
+}
+
+// WithTimeout 类似于 context.WithTimeout，但超时由 c 计时。
+// c 为 nil 或 Real 时直接调用 context.WithTimeout，不引入额外的开销；
+// 其他时钟（例如 Fake）在定时器触发时取消上下文，此时 Err 返回 context.DeadlineExceeded，
+// Deadline 返回按 c 计算的截止时间。
+func WithTimeout(parent context.Context, c Clock, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if c == nil {
+		return context.WithTimeout(parent, timeout)
+	}
+	if _, ok := c.(Real); ok {
+		return context.WithTimeout(parent, timeout)
+	}
+
+	ctx, cancel := context.WithCancel(parent)
+	tc := &timerCtx{Context: ctx, deadline: c.Now().Add(timeout)}
+	timer := c.NewTimer(timeout)
+	go func() {
+		select {
+		case <-timer.C():
+			tc.mu.Lock()
+			if ctx.Err() == nil {
+				tc.err = context.DeadlineExceeded
+			}
+			tc.mu.Unlock()
+			cancel()
+		case <-ctx.Done():
+			timer.Stop()
+		}
+	}()
+	return tc, cancel
+}
+
+// timerCtx 是 WithTimeout 在非 Real 时钟下返回的上下文
+type timerCtx struct {
+	context.Context
+	deadline time.Time
+
+	mu  sync.Mutex
+	err error // 超时后为 context.DeadlineExceeded
+}
+
+func (c *timerCtx) Deadline() (time.Time, bool) {
+	return c.deadline, true
+}
+
+func (c *timerCtx) Err() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.err != nil {
+		return c.err
+	}
+	return c.Context.Err()
+}
@@ -0,0 +1,112 @@
+package clock
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestReal 测试 Real 使用系统时间
+func TestReal(t *testing.T) {
+	var c Clock = Real{}
+	before := time.Now()
+	assert.False(t, c.Now().Before(before))
+
+	timer := c.NewTimer(time.Millisecond)
+	<-timer.C()
+	assert.False(t, timer.Stop())
+	assert.False(t, timer.Reset(time.Hour))
+	assert.True(t, timer.Stop())
+
+	<-c.After(time.Millisecond)
+	c.Sleep(time.Millisecond)
+	assert.GreaterOrEqual(t, time.Since(before), 2*time.Millisecond)
+}
+
+// TestOrReal 测试 nil 时钟回退到 Real
+func TestOrReal(t *testing.T) {
+	assert.Equal(t, Real{}, OrReal(nil))
+	f := NewFake(time.Time{})
+	assert.Same(t, f, OrReal(f))
+}
+
+// TestWithTimeout_Real 测试 Real 与 nil 时钟直接使用 context.WithTimeout
+func TestWithTimeout_Real(t *testing.T) {
+	for _, c := range []Clock{nil, Real{}} {
+		ctx, cancel := WithTimeout(context.Background(), c, time.Millisecond)
+		<-ctx.Done()
+		assert.ErrorIs(t, ctx.Err(), context.DeadlineExceeded)
+		cancel()
+	}
+}
+
+// TestWithTimeout_Fake 测试 Fake 时钟推进到截止时间时上下文超时
+func TestWithTimeout_Fake(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	c := NewFake(start)
+	ctx, cancel := WithTimeout(context.Background(), c, 10*time.Second)
+	defer cancel()
+
+	deadline, ok := ctx.Deadline()
+	require.True(t, ok)
+	assert.Equal(t, start.Add(10*time.Second), deadline)
+
+	c.Advance(9 * time.Second)
+	assert.NoError(t, ctx.Err())
+
+	c.Advance(time.Second)
+	<-ctx.Done()
+	assert.ErrorIs(t, ctx.Err(), context.DeadlineExceeded)
+}
+
+// TestWithTimeout_FakeCancel 测试取消时返回 context.Canceled 并停止定时器
+func TestWithTimeout_FakeCancel(t *testing.T) {
+	c := NewFake(time.Time{})
+	parent, cancelParent := context.WithCancel(context.Background())
+	ctx, cancel := WithTimeout(parent, c, time.Second)
+	defer cancel()
+	assert.Equal(t, 1, c.Waiters())
+
+	cancelParent()
+	<-ctx.Done()
+	assert.ErrorIs(t, ctx.Err(), context.Canceled)
+	assert.Eventually(t, func() bool { return c.Waiters() == 0 }, time.Second, time.Millisecond)
+
+	c.Advance(time.Second)
+	assert.ErrorIs(t, ctx.Err(), context.Canceled)
+}
+
+// BenchmarkReal_Now 基准测试通过 Clock 接口获取时间的开销
+func BenchmarkReal_Now(b *testing.B) {
+	var c Clock = Real{}
+	b.Run("Clock", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			_ = c.Now()
+		}
+	})
+	b.Run("time", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			_ = time.Now()
+		}
+	})
+}
+
+// BenchmarkWithTimeout 基准测试 Real 时钟下 WithTimeout 与 context.WithTimeout 的开销
+func BenchmarkWithTimeout(b *testing.B) {
+	ctx := context.Background()
+	b.Run("Clock", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			_, cancel := WithTimeout(ctx, Real{}, time.Second)
+			cancel()
+		}
+	})
+	b.Run("context", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			_, cancel := context.WithTimeout(ctx, time.Second)
+			cancel()
+		}
+	})
+}
@@ -0,0 +1,164 @@
+package clock
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// Fake 是由测试手动推进的 Clock，时间只在调用 Advance 时前进。
+// 定时器、After 与 Sleep 在 Advance 越过其触发时间时触发；
+// BlockUntilWaiters 用于等待被测代码创建好定时器之后再推进时间，避免竞态：
+//
+//	c := clock.NewFake(time.Time{})
+//	go svc.Run(c) // 内部调用 c.After(time.Second)
+//	c.BlockUntilWaiters(1)
+//	c.Advance(time.Second)
+//
+// Fake 的方法是并发安全的。
+type Fake struct {
+	mu      sync.Mutex
+	cond    *sync.Cond // 等待中的定时器数量变化时广播
+	now     time.Time
+	waiters []*fakeTimer // 等待触发的定时器
+}
+
+// NewFake 创建当前时间为 now 的 Fake。
+func NewFake(now time.Time) *Fake {
+	f := &Fake{now: now}
+	f.cond = sync.NewCond(&f.mu)
+	return f
+}
+
+// Now 返回 Fake 的当前时间
+func (f *Fake) Now() time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.now
+}
+
+// After 返回在时间推进 d 之后接收到当前时间的通道
+func (f *Fake) After(d time.Duration) <-chan time.Time {
+	return f.NewTimer(d).C()
+}
+
+// NewTimer 创建在时间推进 d 之后触发的定时器，d 小于等于 0 时立即触发
+func (f *Fake) NewTimer(d time.Duration) Timer {
+	t := &fakeTimer{f: f, c: make(chan time.Time, 1)}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.arm(t, d)
+	return t
+}
+
+// Sleep 阻塞直到时间推进 d
+func (f *Fake) Sleep(d time.Duration) {
+	<-f.After(d)
+}
+
+// Advance 将时间推进 d，并按触发时间的顺序触发所有到期的定时器。
+func (f *Fake) Advance(d time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.now = f.now.Add(d)
+
+	var due, pending []*fakeTimer
+	for _, t := range f.waiters {
+		if t.when.After(f.now) {
+			pending = append(pending, t)
+		} else {
+			due = append(due, t)
+		}
+	}
+	if len(due) == 0 {
+		return
+	}
+	f.waiters = pending
+	sort.SliceStable(due, func(i, j int) bool { return due[i].when.Before(due[j].when) })
+	for _, t := range due {
+		t.fire(f.now)
+	}
+	f.cond.Broadcast()
+}
+
+// BlockUntilWaiters 阻塞直到至少有 n 个定时器（包括 After 与 Sleep）在等待触发。
+func (f *Fake) BlockUntilWaiters(n int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for len(f.waiters) < n {
+		f.cond.Wait()
+	}
+}
+
+// Waiters 返回等待触发的定时器数量
+func (f *Fake) Waiters() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.waiters)
+}
+
+// arm 使 t 在 d 之后触发，调用方需要持有 f.mu
+func (f *Fake) arm(t *fakeTimer, d time.Duration) {
+	t.when = f.now.Add(d)
+	if d <= 0 {
+		t.fire(f.now)
+		return
+	}
+	f.waiters = append(f.waiters, t)
+	f.cond.Broadcast()
+}
+
+// remove 从等待列表中移除 t 并返回 t 是否在等待，调用方需要持有 f.mu
+func (f *Fake) remove(t *fakeTimer) bool {
+	for i, w := range f.waiters {
+		if w == t {
+			f.waiters = append(f.waiters[:i], f.waiters[i+1:]...)
+			f.cond.Broadcast()
+			return true
+		}
+	}
+	return false
+}
+
+// fakeTimer 是 Fake 创建的定时器
+type fakeTimer struct {
+	f    *Fake
+	when time.Time
+	c    chan time.Time
+}
+
+func (t *fakeTimer) C() <-chan time.Time {
+	return t.c
+}
+
+func (t *fakeTimer) Stop() bool {
+	t.f.mu.Lock()
+	defer t.f.mu.Unlock()
+	t.drain()
+	return t.f.remove(t)
+}
+
+func (t *fakeTimer) Reset(d time.Duration) bool {
+	t.f.mu.Lock()
+	defer t.f.mu.Unlock()
+	t.drain()
+	active := t.f.remove(t)
+	t.f.arm(t, d)
+	return active
+}
+
+// fire 向通道发送触发时间，通道已满时丢弃
+func (t *fakeTimer) fire(now time.Time) {
+	select {
+	case t.c <- now:
+	default:
+	}
+}
+
+// drain 丢弃通道中未读取的值，保证 Stop 与 Reset 之后不会收到过期的触发
+func (t *fakeTimer) drain() {
+	select {
+	case <-t.c:
+	default:
+	}
+}
@@ -0,0 +1,93 @@
+package clock
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// received 报告通道中是否有可以立即读取的值
+func received(c <-chan time.Time) bool {
+	select {
+	case <-c:
+		return true
+	default:
+		return false
+	}
+}
+
+// TestFake_Timer 测试定时器只在 Advance 越过触发时间时触发
+func TestFake_Timer(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	c := NewFake(start)
+	timer := c.NewTimer(time.Second)
+	assert.Equal(t, 1, c.Waiters())
+
+	c.Advance(999 * time.Millisecond)
+	assert.False(t, received(timer.C()))
+
+	c.Advance(time.Millisecond)
+	select {
+	case now := <-timer.C():
+		assert.Equal(t, start.Add(time.Second), now)
+	default:
+		t.Fatal("timer should fire")
+	}
+	assert.Equal(t, start.Add(time.Second), c.Now())
+	assert.Zero(t, c.Waiters())
+	assert.False(t, timer.Stop())
+}
+
+// TestFake_StopReset 测试 Stop 与 Reset 之后不会收到过期的触发
+func TestFake_StopReset(t *testing.T) {
+	c := NewFake(time.Time{})
+	timer := c.NewTimer(time.Second)
+	assert.True(t, timer.Stop())
+	c.Advance(time.Second)
+	assert.False(t, received(timer.C()))
+
+	// 已触发但未读取的值在 Reset 时被丢弃
+	assert.False(t, timer.Reset(time.Second))
+	c.Advance(time.Second)
+	assert.False(t, timer.Reset(2*time.Second))
+	assert.False(t, received(timer.C()))
+	c.Advance(time.Second)
+	assert.False(t, received(timer.C()))
+	c.Advance(time.Second)
+	assert.True(t, received(timer.C()))
+
+	assert.False(t, c.NewTimer(0).Stop(), "non-positive duration fires immediately")
+	assert.True(t, received(c.After(-time.Second)))
+}
+
+// TestFake_AdvanceMany 测试一次 Advance 触发所有到期的定时器，未到期的继续等待
+func TestFake_AdvanceMany(t *testing.T) {
+	c := NewFake(time.Time{})
+	first, second, third := c.NewTimer(time.Second), c.NewTimer(2*time.Second), c.NewTimer(time.Minute)
+	c.Advance(3 * time.Second)
+	assert.True(t, received(first.C()))
+	assert.True(t, received(second.C()))
+	assert.False(t, received(third.C()))
+	assert.Equal(t, 1, c.Waiters())
+}
+
+// TestFake_SleepBlockUntilWaiters 测试 Sleep 阻塞到时间推进，BlockUntilWaiters 等待 Sleep 开始
+func TestFake_SleepBlockUntilWaiters(t *testing.T) {
+	c := NewFake(time.Time{})
+	done := make(chan struct{})
+	go func() {
+		c.Sleep(time.Minute)
+		close(done)
+	}()
+
+	c.BlockUntilWaiters(1)
+	c.Advance(30 * time.Second)
+	select {
+	case <-done:
+		t.Fatal("Sleep returned early")
+	default:
+	}
+	c.Advance(30 * time.Second)
+	<-done
+}
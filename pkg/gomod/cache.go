@@ -0,0 +1,82 @@
+package gomod
+
+import (
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+const (
+	// CacheTTL 是 ProjectRootCached 缓存项的有效期，过期后重新查找。
+	CacheTTL = 5 * time.Minute
+	// CacheMaxEntries 是 ProjectRootCached 缓存的最大条目数。
+	// 达到上限时先清理过期条目，仍然已满则清空整个缓存。
+	CacheMaxEntries = 1024
+)
+
+// rootEntry 是一条缓存的查找结果，found 为 false 表示负缓存（未找到 go.mod）。
+type rootEntry struct {
+	root    string
+	found   bool
+	expires time.Time
+}
+
+var (
+	rootCacheMu sync.RWMutex
+	rootCache   = make(map[string]rootEntry)
+	// now 返回当前时间，测试中可替换以模拟过期
+	now = time.Now
+)
+
+// ProjectRootCached 是 ProjectRoot 的带缓存版本，适用于需要频繁获取项目根目录的热点路径。
+// 缓存以 runDir 的绝对路径为键，同时缓存找到与未找到 go.mod 的结果，
+// 缓存项在 CacheTTL 后过期，条目数不超过 CacheMaxEntries。
+// 文件系统发生变化（例如新建 go.mod）后可调用 InvalidateCache 立即失效。
+// 该函数可并发调用。
+func ProjectRootCached(runDir string) string {
+	abs, err := filepath.Abs(runDir)
+	if err != nil {
+		return runDir
+	}
+
+	t := now()
+	rootCacheMu.RLock()
+	entry, ok := rootCache[abs]
+	rootCacheMu.RUnlock()
+
+	if !ok || !t.Before(entry.expires) {
+		entry.root, entry.found = FindGoModRoot(abs)
+		entry.expires = t.Add(CacheTTL)
+		storeRoot(abs, entry, t)
+	}
+
+	if !entry.found {
+		return runDir
+	}
+	return entry.root
+}
+
+// InvalidateCache 清空 ProjectRootCached 的全部缓存。
+func InvalidateCache() {
+	rootCacheMu.Lock()
+	defer rootCacheMu.Unlock()
+	clear(rootCache)
+}
+
+// storeRoot 写入缓存项，缓存已满时先清理过期条目，仍然已满则清空缓存。
+func storeRoot(key string, entry rootEntry, t time.Time) {
+	rootCacheMu.Lock()
+	defer rootCacheMu.Unlock()
+
+	if _, ok := rootCache[key]; !ok && len(rootCache) >= CacheMaxEntries {
+		for k, e := range rootCache {
+			if !t.Before(e.expires) {
+				delete(rootCache, k)
+			}
+		}
+		if len(rootCache) >= CacheMaxEntries {
+			clear(rootCache)
+		}
+	}
+	rootCache[key] = entry
+}
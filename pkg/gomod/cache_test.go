@@ -0,0 +1,114 @@
+package gomod
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// cacheLen 返回当前缓存条目数
+func cacheLen() int {
+	rootCacheMu.RLock()
+	defer rootCacheMu.RUnlock()
+	return len(rootCache)
+}
+
+// TestProjectRootCached 测试 ProjectRootCached 的缓存命中、负缓存与失效
+func TestProjectRootCached(t *testing.T) {
+	t.Cleanup(InvalidateCache)
+
+	t.Run("缓存命中", func(t *testing.T) {
+		InvalidateCache()
+		projectRoot := t.TempDir()
+		gomodPath := filepath.Join(projectRoot, "go.mod")
+		require.NoError(t, os.WriteFile(gomodPath, []byte("module test"), 0644))
+		subDir := filepath.Join(projectRoot, "cmd", "app")
+		require.NoError(t, os.MkdirAll(subDir, 0755))
+
+		assert.Equal(t, projectRoot, ProjectRootCached(subDir))
+
+		// 删除 go.mod 后仍然返回缓存结果
+		require.NoError(t, os.Remove(gomodPath))
+		assert.Equal(t, projectRoot, ProjectRootCached(subDir))
+		assert.Equal(t, 1, cacheLen())
+	})
+
+	t.Run("负缓存与失效", func(t *testing.T) {
+		InvalidateCache()
+		projectRoot := t.TempDir()
+		subDir := filepath.Join(projectRoot, "pkg")
+		require.NoError(t, os.MkdirAll(subDir, 0755))
+
+		// 临时目录的上级目录可能存在 go.mod，以未缓存版本的结果为准
+		before := ProjectRoot(subDir)
+		assert.Equal(t, before, ProjectRootCached(subDir))
+
+		// 新建 go.mod 后命中负缓存，结果不变
+		require.NoError(t, os.WriteFile(filepath.Join(projectRoot, "go.mod"), []byte("module test"), 0644))
+		assert.Equal(t, before, ProjectRootCached(subDir))
+
+		InvalidateCache()
+		assert.Equal(t, projectRoot, ProjectRootCached(subDir))
+	})
+
+	t.Run("过期后重新查找", func(t *testing.T) {
+		InvalidateCache()
+		base := time.Now()
+		current := base
+		now = func() time.Time { return current }
+		t.Cleanup(func() { now = time.Now })
+
+		projectRoot := t.TempDir()
+		subDir := filepath.Join(projectRoot, "internal")
+		require.NoError(t, os.MkdirAll(subDir, 0755))
+		before := ProjectRootCached(subDir)
+
+		require.NoError(t, os.WriteFile(filepath.Join(projectRoot, "go.mod"), []byte("module test"), 0644))
+		current = base.Add(CacheTTL - time.Second)
+		assert.Equal(t, before, ProjectRootCached(subDir))
+
+		current = base.Add(CacheTTL)
+		assert.Equal(t, projectRoot, ProjectRootCached(subDir))
+	})
+
+	t.Run("条目数上限", func(t *testing.T) {
+		InvalidateCache()
+		tempDir := t.TempDir()
+		for i := 0; i <= CacheMaxEntries; i++ {
+			ProjectRootCached(filepath.Join(tempDir, fmt.Sprintf("dir-%d", i)))
+		}
+		assert.LessOrEqual(t, cacheLen(), CacheMaxEntries)
+	})
+
+	t.Run("相对路径返回原值", func(t *testing.T) {
+		InvalidateCache()
+		assert.Equal(t, ProjectRoot("."), ProjectRootCached("."))
+	})
+}
+
+// BenchmarkProjectRootCached 对比深层目录下带缓存与不带缓存的性能
+func BenchmarkProjectRootCached(b *testing.B) {
+	projectRoot := b.TempDir()
+	_ = os.WriteFile(filepath.Join(projectRoot, "go.mod"), []byte("module bench"), 0644)
+	deepDir := filepath.Join(projectRoot, "a/b/c/d/e/f/g/h")
+	_ = os.MkdirAll(deepDir, 0755)
+
+	b.Run("uncached", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			ProjectRoot(deepDir)
+		}
+	})
+
+	b.Run("cached", func(b *testing.B) {
+		InvalidateCache()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			ProjectRootCached(deepDir)
+		}
+	})
+}
@@ -0,0 +1,157 @@
+package gomod
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// MainPkg 描述项目中的一个 main 包。
+type MainPkg struct {
+	Dir         string // 包所在目录，即 rootDir 与相对路径拼接的结果
+	ImportPath  string // 导入路径，由 module 名称与相对目录组成
+	HasMainFunc bool   // 包中是否声明了 main 函数
+}
+
+// MainOption 是 MainPackages 的选项。
+type MainOption func(*mainOptions)
+
+type mainOptions struct {
+	maxDepth int
+	cmdFirst bool
+}
+
+// WithMaxDepth 限制 MainPackages 查找的目录深度，rootDir 本身的深度为 0。
+// depth 小于 0 时不限制深度（默认）。
+func WithMaxDepth(depth int) MainOption {
+	return func(o *mainOptions) {
+		o.maxDepth = depth
+	}
+}
+
+// WithCmdFirst 使 MainPackages 返回的结果中 cmd/ 目录下的包排在其他包之前。
+func WithCmdFirst() MainOption {
+	return func(o *mainOptions) {
+		o.cmdFirst = true
+	}
+}
+
+// MainPackages 查找 rootDir 所在模块中的所有 main 包，rootDir 必须包含 go.mod 文件。
+// 它遍历目录树并用 go/parser 解析包声明，不依赖 go/build 或 go list 子进程，因此速度快且不受环境影响：
+// 跳过 vendor、testdata、以 "." 或 "_" 开头的目录以及包含 go.mod 的嵌套模块，
+// 忽略 _test.go 文件，只要目录中有文件声明 package main 即返回该包，
+// HasMainFunc 报告包中是否有文件声明了 main 函数。
+//
+// 结果按相对目录排序，使用 WithCmdFirst 时 cmd/ 目录下的包排在前面。
+// rootDir 中没有 go.mod 或无法读取时返回对应的错误。
+func MainPackages(rootDir string, opts ...MainOption) ([]MainPkg, error) {
+	o := &mainOptions{maxDepth: -1}
+	for _, opt := range opts {
+		opt(o)
+	}
+	modPath, err := ModuleName(rootDir)
+	if err != nil {
+		return nil, err
+	}
+
+	type found struct {
+		rel string
+		pkg MainPkg
+	}
+	var pkgs []found
+	fset := token.NewFileSet()
+	err = filepath.WalkDir(rootDir, func(dir string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() {
+			return nil
+		}
+		rel, err := filepath.Rel(rootDir, dir)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if rel != "." {
+			name := d.Name()
+			if name == "vendor" || name == "testdata" || strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_") ||
+				HasGoMod(dir) {
+				return filepath.SkipDir
+			}
+			if o.maxDepth >= 0 && strings.Count(rel, "/")+1 > o.maxDepth {
+				return filepath.SkipDir
+			}
+		}
+
+		isMain, hasMainFunc, err := scanMainPackage(fset, dir)
+		if err != nil || !isMain {
+			return err
+		}
+		importPath := modPath
+		if rel != "." {
+			importPath = path.Join(modPath, rel)
+		}
+		pkgs = append(pkgs, found{rel: rel, pkg: MainPkg{Dir: dir, ImportPath: importPath, HasMainFunc: hasMainFunc}})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	inCmd := func(rel string) bool { return rel == "cmd" || strings.HasPrefix(rel, "cmd/") }
+	sort.SliceStable(pkgs, func(i, j int) bool {
+		if o.cmdFirst {
+			if ci, cj := inCmd(pkgs[i].rel), inCmd(pkgs[j].rel); ci != cj {
+				return ci
+			}
+		}
+		return pkgs[i].rel < pkgs[j].rel
+	})
+	result := make([]MainPkg, len(pkgs))
+	for i, p := range pkgs {
+		result[i] = p.pkg
+	}
+	return result, nil
+}
+
+// scanMainPackage 检查目录中的 Go 文件（不包括 _test.go）是否声明了 package main，
+// 以及是否声明了 main 函数。先只解析包声明，只有 main 包的文件才完整解析。
+func scanMainPackage(fset *token.FileSet, dir string) (isMain, hasMainFunc bool, err error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return false, false, err
+	}
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, ".go") || strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+		file := filepath.Join(dir, name)
+		f, err := parser.ParseFile(fset, file, nil, parser.PackageClauseOnly)
+		if err != nil || f.Name.Name != "main" {
+			// 无法解析的文件交给编译器报告，这里只关心包声明
+			continue
+		}
+		isMain = true
+		if hasMainFunc {
+			continue
+		}
+		f, err = parser.ParseFile(fset, file, nil, parser.SkipObjectResolution)
+		if err != nil {
+			continue
+		}
+		for _, decl := range f.Decls {
+			if fn, ok := decl.(*ast.FuncDecl); ok && fn.Recv == nil && fn.Name.Name == "main" {
+				hasMainFunc = true
+				break
+			}
+		}
+	}
+	return isMain, hasMainFunc, nil
+}
@@ -0,0 +1,104 @@
+package gomod
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeFixture 在 root 下按相对路径写入文件
+func writeFixture(t *testing.T, root string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	}
+}
+
+// newMainFixture 创建包含多个 main 包、名称近似 main 的普通包以及应被忽略的目录的项目
+func newMainFixture(t *testing.T) string {
+	root := t.TempDir()
+	const mainFile = "package main\n\nfunc main() {}\n"
+	writeFixture(t, root, map[string]string{
+		"go.mod":                     "module example.com/shop\n",
+		"main.go":                    mainFile,
+		"cmd/api/main.go":            "package main\n\nimport \"fmt\"\n\nfunc main() { fmt.Println(run()) }\n",
+		"cmd/api/run.go":             "package main\n\nfunc run() string { return \"api\" }\n",
+		"cmd/worker/worker.go":       mainFile,
+		"tools/gen/gen.go":           mainFile,
+		"scripts/helper.go":          "package main\n\nfunc helper() {}\n",
+		"internal/mainutil/util.go":  "package mainutil\n\nfunc main() {}\n",
+		"internal/main/main.go":      "package mainpkg\n",
+		"internal/recv/recv.go":      "package main\n\ntype T struct{}\n\nfunc (T) main() {}\n",
+		"internal/test/x_test.go":    mainFile,
+		"vendor/example.com/x/x.go":  mainFile,
+		"testdata/fixture/main.go":   mainFile,
+		".hidden/main.go":            mainFile,
+		"_skip/main.go":              mainFile,
+		"nested/go.mod":              "module example.com/nested\n",
+		"nested/main.go":             mainFile,
+		"docs/README.md":             "package main\n",
+		"cmd/broken/broken.go":       "package main\n\nfunc main( {\n",
+		"cmd/api/internal/x/x.go":    "package x\n",
+		"cmd/api/internal/x/main.go": "package x\n\nfunc main() {}\n",
+	})
+	return root
+}
+
+// TestMainPackages 测试查找 main 包：忽略 vendor、testdata、隐藏目录、嵌套模块、测试文件与非 main 包
+func TestMainPackages(t *testing.T) {
+	root := newMainFixture(t)
+
+	pkgs, err := MainPackages(root)
+	require.NoError(t, err)
+	assert.Equal(t, []MainPkg{
+		{Dir: root, ImportPath: "example.com/shop", HasMainFunc: true},
+		{Dir: filepath.Join(root, "cmd", "api"), ImportPath: "example.com/shop/cmd/api", HasMainFunc: true},
+		{Dir: filepath.Join(root, "cmd", "broken"), ImportPath: "example.com/shop/cmd/broken", HasMainFunc: false},
+		{Dir: filepath.Join(root, "cmd", "worker"), ImportPath: "example.com/shop/cmd/worker", HasMainFunc: true},
+		{Dir: filepath.Join(root, "internal", "recv"), ImportPath: "example.com/shop/internal/recv", HasMainFunc: false},
+		{Dir: filepath.Join(root, "scripts"), ImportPath: "example.com/shop/scripts", HasMainFunc: false},
+		{Dir: filepath.Join(root, "tools", "gen"), ImportPath: "example.com/shop/tools/gen", HasMainFunc: true},
+	}, pkgs)
+}
+
+// TestMainPackages_Options 测试限制深度与 cmd/ 优先的排序
+func TestMainPackages_Options(t *testing.T) {
+	root := newMainFixture(t)
+	importPaths := func(pkgs []MainPkg) []string {
+		var paths []string
+		for _, p := range pkgs {
+			if p.HasMainFunc {
+				paths = append(paths, p.ImportPath)
+			}
+		}
+		return paths
+	}
+
+	pkgs, err := MainPackages(root, WithMaxDepth(1))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"example.com/shop"}, importPaths(pkgs))
+
+	pkgs, err = MainPackages(root, WithMaxDepth(0))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"example.com/shop"}, importPaths(pkgs))
+
+	pkgs, err = MainPackages(root, WithCmdFirst())
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"example.com/shop/cmd/api",
+		"example.com/shop/cmd/worker",
+		"example.com/shop",
+		"example.com/shop/tools/gen",
+	}, importPaths(pkgs))
+}
+
+// TestMainPackages_NoGoMod 测试 rootDir 中没有 go.mod 时返回错误
+func TestMainPackages_NoGoMod(t *testing.T) {
+	_, err := MainPackages(t.TempDir())
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
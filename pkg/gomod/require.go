@@ -0,0 +1,31 @@
+package gomod
+
+import (
+	"golang.org/x/mod/modfile"
+)
+
+// AddRequire 在 go.mod 文件内容 content 中添加对 path 的 require。
+// 参数 path 为依赖的模块路径，version 为版本号。
+// 返回值为修改后的内容、是否修改以及可能的错误。
+// 如果已经依赖 path（无论版本，包括间接依赖），原样返回 content 和 false；
+// 如果内容不是有效的 go.mod，返回相应错误。
+func AddRequire(content []byte, path, version string) ([]byte, bool, error) {
+	f, err := modfile.Parse("go.mod", content, nil)
+	if err != nil {
+		return nil, false, err
+	}
+	for _, r := range f.Require {
+		if r.Mod.Path == path {
+			return content, false, nil
+		}
+	}
+	if err := f.AddRequire(path, version); err != nil {
+		return nil, false, err
+	}
+	f.Cleanup()
+	out, err := f.Format()
+	if err != nil {
+		return nil, false, err
+	}
+	return out, true, nil
+}
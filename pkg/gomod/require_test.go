@@ -0,0 +1,30 @@
+package gomod
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestAddRequire 测试添加依赖，已经依赖的模块保持不变
+func TestAddRequire(t *testing.T) {
+	content := []byte("module github.com/acme/shop\n\ngo 1.25.0\n\nrequire (\n\tgithub.com/qq1060656096/drugo v1.0.0\n)\n")
+
+	out, changed, err := AddRequire(content, "github.com/qq1060656096/drugo-provider", "v0.0.8")
+	require.NoError(t, err)
+	assert.True(t, changed)
+	assert.Contains(t, string(out), "github.com/qq1060656096/drugo-provider v0.0.8")
+	assert.Contains(t, string(out), "github.com/qq1060656096/drugo v1.0.0")
+	name, err := ParseModuleName(out)
+	require.NoError(t, err)
+	assert.Equal(t, "github.com/acme/shop", name)
+
+	again, changed, err := AddRequire(out, "github.com/qq1060656096/drugo-provider", "v0.0.9")
+	require.NoError(t, err)
+	assert.False(t, changed)
+	assert.Equal(t, out, again)
+
+	_, _, err = AddRequire([]byte("require (\n"), "github.com/acme/x", "v1.0.0")
+	assert.Error(t, err)
+}
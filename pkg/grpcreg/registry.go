@@ -0,0 +1,23 @@
+// Package grpcreg 提供 gRPC 服务的注册表，用法与 pkg/router 一致：
+// 模块在 init 中通过 Default().Register 注册服务，应用创建 grpc.Server 后调用 Default().Setup 完成注册。
+package grpcreg
+
+import (
+	"github.com/qq1060656096/drugo/pkg/router"
+	"google.golang.org/grpc"
+)
+
+// Registry 是 gRPC 服务注册表，注册函数接收 grpc.ServiceRegistrar（*grpc.Server 实现了该接口）
+type Registry = router.Registry[grpc.ServiceRegistrar]
+
+// New 创建一个新的 gRPC 服务注册表
+func New() *Registry {
+	return router.New[grpc.ServiceRegistrar]()
+}
+
+var defaultRegistry = New()
+
+// Default 返回全局默认的 gRPC 服务注册表
+func Default() *Registry {
+	return defaultRegistry
+}
@@ -0,0 +1,28 @@
+package grpcreg
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+func TestRegistry_Setup(t *testing.T) {
+	registry := New()
+	registry.Register(func(s grpc.ServiceRegistrar) {
+		healthpb.RegisterHealthServer(s, health.NewServer())
+	})
+
+	server := grpc.NewServer()
+	registry.Setup(server)
+
+	_, ok := server.GetServiceInfo()[healthpb.Health_ServiceDesc.ServiceName]
+	assert.True(t, ok)
+}
+
+func TestDefault(t *testing.T) {
+	assert.NotNil(t, Default())
+	assert.Same(t, Default(), Default())
+}
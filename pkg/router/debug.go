@@ -0,0 +1,105 @@
+package router
+
+import (
+	"crypto/subtle"
+	"expvar"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/pprof"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DefaultDebugPrefix 是调试路由的默认前缀
+const DefaultDebugPrefix = "/debug"
+
+// DebugOptions 是 RegisterDebug 的配置
+type DebugOptions struct {
+	// Prefix 是路由前缀，为空时使用 DefaultDebugPrefix
+	Prefix string
+	// AllowCIDRs 允许访问的客户端网段，例如 "127.0.0.1/32"、"10.0.0.0/8"；为空时不限制。
+	// 客户端地址取自连接的对端地址（gin.Context.RemoteIP），不信任 X-Forwarded-For 等请求头
+	AllowCIDRs []string
+	// Token 不为空时要求请求携带 "Authorization: Bearer <Token>"
+	Token string
+}
+
+// RegisterDebug 向 reg 注册调试路由：
+//   - {Prefix}/pprof/*：net/http/pprof 的全部性能分析接口
+//   - {Prefix}/vars：expvar 导出的变量
+//
+// 配置了 AllowCIDRs 时不在网段内的客户端返回 403，配置了 Token 时令牌不匹配返回 401。
+// 两者都未配置时调试路由对所有客户端开放，生产环境应至少配置其中一项。
+// AllowCIDRs 中存在无效网段时返回错误且不注册；同一个 Prefix 重复调用只会注册一次
+func RegisterDebug(reg *Registry[*gin.Engine], opts DebugOptions) error {
+	nets := make([]*net.IPNet, 0, len(opts.AllowCIDRs))
+	for _, cidr := range opts.AllowCIDRs {
+		_, n, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return fmt.Errorf("router: invalid debug allow cidr %q: %w", cidr, err)
+		}
+		nets = append(nets, n)
+	}
+	prefix := opts.Prefix
+	if prefix == "" {
+		prefix = DefaultDebugPrefix
+	}
+
+	reg.RegisterOnce("debug:"+prefix, func(r *gin.Engine) {
+		g := r.Group(prefix, debugGuard(nets, opts.Token))
+		g.Any("/pprof/*name", servePprof)
+		g.GET("/vars", gin.WrapH(expvar.Handler()))
+	})
+	return nil
+}
+
+// debugGuard 返回校验客户端网段和令牌的中间件
+func debugGuard(nets []*net.IPNet, token string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if len(nets) > 0 && !containsIP(nets, net.ParseIP(c.RemoteIP())) {
+			c.AbortWithStatus(http.StatusForbidden)
+			return
+		}
+		if token != "" {
+			got, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+			if !ok || subtle.ConstantTimeCompare([]byte(got), []byte(token)) != 1 {
+				c.AbortWithStatus(http.StatusUnauthorized)
+				return
+			}
+		}
+		c.Next()
+	}
+}
+
+// containsIP 判断 ip 是否在任一网段内
+func containsIP(nets []*net.IPNet, ip net.IP) bool {
+	if ip == nil {
+		return false
+	}
+	for _, n := range nets {
+		if n.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// servePprof 按路径分发 pprof 接口，不依赖固定的 /debug/pprof/ 前缀
+func servePprof(c *gin.Context) {
+	switch name := strings.TrimPrefix(c.Param("name"), "/"); name {
+	case "":
+		pprof.Index(c.Writer, c.Request)
+	case "cmdline":
+		pprof.Cmdline(c.Writer, c.Request)
+	case "profile":
+		pprof.Profile(c.Writer, c.Request)
+	case "symbol":
+		pprof.Symbol(c.Writer, c.Request)
+	case "trace":
+		pprof.Trace(c.Writer, c.Request)
+	default:
+		pprof.Handler(name).ServeHTTP(c.Writer, c.Request)
+	}
+}
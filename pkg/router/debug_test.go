@@ -0,0 +1,69 @@
+package router
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestRegisterDebug 测试 pprof 与 expvar 路由
+func TestRegisterDebug(t *testing.T) {
+	reg := New[*gin.Engine]()
+	require.NoError(t, RegisterDebug(reg, DebugOptions{}))
+	require.NoError(t, RegisterDebug(reg, DebugOptions{})) // 重复注册只生效一次
+
+	tests := []struct {
+		path     string
+		contains string
+	}{
+		{path: "/debug/pprof/", contains: "goroutine"},
+		{path: "/debug/pprof/goroutine?debug=1", contains: "goroutine profile"},
+		{path: "/debug/pprof/cmdline", contains: ""},
+		{path: "/debug/vars", contains: `"memstats"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			w := serve(reg, httptest.NewRequest(http.MethodGet, tt.path, nil))
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.Contains(t, w.Body.String(), tt.contains)
+		})
+	}
+}
+
+// TestRegisterDebug_Guard 测试网段白名单、令牌与自定义前缀
+func TestRegisterDebug_Guard(t *testing.T) {
+	reg := New[*gin.Engine]()
+	require.NoError(t, RegisterDebug(reg, DebugOptions{
+		Prefix:     "/ops",
+		AllowCIDRs: []string{"10.0.0.0/8", "::1/128"},
+		Token:      "s3cret",
+	}))
+
+	request := func(remoteAddr, token string) int {
+		req := httptest.NewRequest(http.MethodGet, "/ops/vars", nil)
+		req.RemoteAddr = remoteAddr
+		req.Header.Set("X-Forwarded-For", "10.0.0.2") // 不信任转发头
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		return serve(reg, req).Code
+	}
+
+	assert.Equal(t, http.StatusForbidden, request("192.168.1.5:4000", "s3cret"))
+	assert.Equal(t, http.StatusUnauthorized, request("10.1.2.3:4000", ""))
+	assert.Equal(t, http.StatusUnauthorized, request("10.1.2.3:4000", "wrong"))
+	assert.Equal(t, http.StatusOK, request("10.1.2.3:4000", "s3cret"))
+	assert.Equal(t, http.StatusOK, request("[::1]:4000", "s3cret"))
+}
+
+// TestRegisterDebug_InvalidCIDR 测试无效网段返回错误且不注册路由
+func TestRegisterDebug_InvalidCIDR(t *testing.T) {
+	reg := New[*gin.Engine]()
+	err := RegisterDebug(reg, DebugOptions{AllowCIDRs: []string{"10.0.0.1"}})
+	assert.ErrorContains(t, err, `"10.0.0.1"`)
+	assert.Equal(t, http.StatusNotFound, serve(reg, httptest.NewRequest(http.MethodGet, "/debug/vars", nil)).Code)
+}
@@ -0,0 +1,75 @@
+package router
+
+import (
+	"bytes"
+	_ "embed"
+	"errors"
+	"html/template"
+	"io/fs"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 文档路由的默认配置
+const (
+	DefaultDocsPrefix = "/docs"
+	DefaultDocsFile   = "docs/openapi.yaml"
+)
+
+//go:embed assets/swagger.html
+var swaggerHTML string
+
+// swaggerTpl 渲染 Swagger UI 页面，页面从 CDN 加载 swagger-ui-dist
+var swaggerTpl = template.Must(template.New("swagger").Parse(swaggerHTML))
+
+// DocsOptions 是 RegisterDocs 的配置
+type DocsOptions struct {
+	// Prefix 是路由前缀，为空时使用 DefaultDocsPrefix
+	Prefix string
+	// File 是 OpenAPI 文档（由 drugo openapi 生成）的路径，为空时使用 DefaultDocsFile；
+	// 每次请求时读取，重新生成文档后无需重启
+	File string
+}
+
+// RegisterDocs 向 reg 注册 API 文档路由：
+//   - GET {Prefix}/openapi.yaml：返回 opts.File 的内容，文件不存在时返回 404
+//   - GET {Prefix}、{Prefix}/：返回加载该文档的 Swagger UI 页面
+//
+// 同一个 Prefix 重复调用只会注册一次
+func RegisterDocs(reg *Registry[*gin.Engine], opts DocsOptions) {
+	prefix := strings.TrimSuffix(opts.Prefix, "/")
+	if prefix == "" {
+		prefix = DefaultDocsPrefix
+	}
+	file := opts.File
+	if file == "" {
+		file = DefaultDocsFile
+	}
+
+	reg.RegisterOnce("docs:"+prefix, func(r *gin.Engine) {
+		specURL := prefix + "/openapi.yaml"
+		var page bytes.Buffer
+		_ = swaggerTpl.Execute(&page, struct{ SpecURL string }{specURL})
+
+		ui := func(c *gin.Context) {
+			c.Data(http.StatusOK, "text/html; charset=utf-8", page.Bytes())
+		}
+		r.GET(prefix, ui)
+		r.GET(prefix+"/", ui)
+		r.GET(specURL, func(c *gin.Context) {
+			data, err := os.ReadFile(file)
+			if errors.Is(err, fs.ErrNotExist) {
+				c.JSON(http.StatusNotFound, gin.H{"code": 404, "message": "openapi spec not found, run drugo openapi"})
+				return
+			}
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"code": 500, "message": err.Error()})
+				return
+			}
+			c.Data(http.StatusOK, "application/yaml; charset=utf-8", data)
+		})
+	})
+}
@@ -0,0 +1,47 @@
+package router
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestRegisterDocs 测试 OpenAPI 文档与 Swagger UI 页面
+func TestRegisterDocs(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "openapi.yaml")
+	reg := New[*gin.Engine]()
+	RegisterDocs(reg, DocsOptions{File: file})
+	RegisterDocs(reg, DocsOptions{Prefix: "/docs/"}) // 重复注册不会导致路由冲突 panic
+
+	w := serve(reg, httptest.NewRequest(http.MethodGet, "/docs/openapi.yaml", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	require.NoError(t, os.WriteFile(file, []byte("openapi: 3.0.3\n"), 0644))
+	w = serve(reg, httptest.NewRequest(http.MethodGet, "/docs/openapi.yaml", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "openapi: 3.0.3\n", w.Body.String())
+	assert.Contains(t, w.Header().Get("Content-Type"), "yaml")
+
+	for _, path := range []string{"/docs", "/docs/"} {
+		w = serve(reg, httptest.NewRequest(http.MethodGet, path, nil))
+		assert.Equal(t, http.StatusOK, w.Code, path)
+		assert.Contains(t, w.Body.String(), "SwaggerUIBundle")
+		assert.Contains(t, w.Body.String(), `url: "/docs/openapi.yaml"`)
+	}
+}
+
+// TestRegisterDocs_Prefix 测试自定义前缀
+func TestRegisterDocs_Prefix(t *testing.T) {
+	reg := New[*gin.Engine]()
+	RegisterDocs(reg, DocsOptions{Prefix: "/internal/api-docs"})
+
+	w := serve(reg, httptest.NewRequest(http.MethodGet, "/internal/api-docs", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `url: "/internal/api-docs/openapi.yaml"`)
+}
@@ -0,0 +1,48 @@
+package router
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 健康检查路由的默认路径
+const (
+	DefaultLivenessPath  = "/healthz"
+	DefaultReadinessPath = "/readyz"
+)
+
+// HealthOptions 是 RegisterHealth 的配置
+type HealthOptions struct {
+	// Prefix 是路由前缀，例如 "/internal"，为空时挂载在根路径
+	Prefix string
+	// Ready 检查应用是否可以接收流量，返回 error 时 /readyz 响应 503；为 nil 时视为始终就绪
+	Ready func() error
+}
+
+// RegisterHealth 向 reg 注册健康检查路由：
+//   - GET/HEAD {Prefix}/healthz：存活检查，进程启动后始终返回 200
+//   - GET/HEAD {Prefix}/readyz：就绪检查，委托给 opts.Ready，失败时返回 503 与错误信息
+//
+// 同一个 Prefix 重复调用只会注册一次
+func RegisterHealth(reg *Registry[*gin.Engine], opts HealthOptions) {
+	reg.RegisterOnce("health:"+opts.Prefix, func(r *gin.Engine) {
+		g := r.Group(opts.Prefix)
+		live := func(c *gin.Context) {
+			c.JSON(http.StatusOK, gin.H{"status": "ok"})
+		}
+		ready := func(c *gin.Context) {
+			if opts.Ready != nil {
+				if err := opts.Ready(); err != nil {
+					c.JSON(http.StatusServiceUnavailable, gin.H{"status": "unavailable", "error": err.Error()})
+					return
+				}
+			}
+			c.JSON(http.StatusOK, gin.H{"status": "ok"})
+		}
+		g.GET(DefaultLivenessPath, live)
+		g.HEAD(DefaultLivenessPath, live)
+		g.GET(DefaultReadinessPath, ready)
+		g.HEAD(DefaultReadinessPath, ready)
+	})
+}
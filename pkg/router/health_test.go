@@ -0,0 +1,53 @@
+package router
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// serve 使用 reg 注册的路由处理一次请求
+func serve(reg *Registry[*gin.Engine], req *http.Request) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	reg.Setup(engine)
+	w := httptest.NewRecorder()
+	engine.ServeHTTP(w, req)
+	return w
+}
+
+// TestRegisterHealth 测试存活与就绪检查
+func TestRegisterHealth(t *testing.T) {
+	var readyErr error
+	reg := New[*gin.Engine]()
+	RegisterHealth(reg, HealthOptions{Ready: func() error { return readyErr }})
+	RegisterHealth(reg, HealthOptions{}) // 重复注册不会导致路由冲突 panic
+
+	w := serve(reg, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"status":"ok"}`, w.Body.String())
+
+	w = serve(reg, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	readyErr = errors.New("db not connected")
+	w = serve(reg, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.JSONEq(t, `{"status":"unavailable","error":"db not connected"}`, w.Body.String())
+
+	w = serve(reg, httptest.NewRequest(http.MethodHead, "/healthz", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+// TestRegisterHealth_Prefix 测试路由前缀与未设置 Ready 时始终就绪
+func TestRegisterHealth_Prefix(t *testing.T) {
+	reg := New[*gin.Engine]()
+	RegisterHealth(reg, HealthOptions{Prefix: "/internal"})
+
+	assert.Equal(t, http.StatusOK, serve(reg, httptest.NewRequest(http.MethodGet, "/internal/readyz", nil)).Code)
+	assert.Equal(t, http.StatusNotFound, serve(reg, httptest.NewRequest(http.MethodGet, "/healthz", nil)).Code)
+}
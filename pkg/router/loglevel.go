@@ -0,0 +1,26 @@
+package router
+
+import (
+	"crypto/subtle"
+
+	"github.com/gin-gonic/gin"
+	"github.com/qq1060656096/drugo/log"
+	"go.uber.org/zap/zapcore"
+)
+
+// DebugTokenHeader 是携带调试令牌的 HTTP 头
+const DebugTokenHeader = "X-Debug-Token"
+
+// LogLevelMiddleware 返回按请求放宽日志级别的 gin 中间件。
+// 请求头 X-Debug-Token 与 secret 一致（常量时间比较）时，通过 log.WithMinLevel 将请求上下文的日志级别放宽到 level，
+// 之后经 log.Manager.For 或 kernel.LoggerFromContext 获取的日志实例会输出该请求的调试日志；
+// 不会修改全局的日志级别。secret 为空时中间件不做任何操作。
+func LogLevelMiddleware(secret string, level zapcore.Level) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		token := c.GetHeader(DebugTokenHeader)
+		if secret != "" && token != "" && subtle.ConstantTimeCompare([]byte(token), []byte(secret)) == 1 {
+			c.Request = c.Request.WithContext(log.WithMinLevel(c.Request.Context(), level))
+		}
+		c.Next()
+	}
+}
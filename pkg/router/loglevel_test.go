@@ -0,0 +1,52 @@
+package router
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/qq1060656096/drugo/log"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zapcore"
+)
+
+// TestLogLevelMiddleware 测试只有令牌正确时才放宽请求的日志级别
+func TestLogLevelMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	serve := func(secret, token string) (zapcore.Level, bool) {
+		engine := gin.New()
+		engine.Use(LogLevelMiddleware(secret, zapcore.DebugLevel))
+
+		var (
+			level zapcore.Level
+			ok    bool
+		)
+		engine.GET("/", func(c *gin.Context) {
+			level, ok = log.MinLevelFromContext(c.Request.Context())
+		})
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		if token != "" {
+			req.Header.Set(DebugTokenHeader, token)
+		}
+		engine.ServeHTTP(httptest.NewRecorder(), req)
+		return level, ok
+	}
+
+	level, ok := serve("s3cret", "s3cret")
+	assert.True(t, ok)
+	assert.Equal(t, zapcore.DebugLevel, level)
+
+	_, ok = serve("s3cret", "wrong")
+	assert.False(t, ok)
+
+	_, ok = serve("s3cret", "")
+	assert.False(t, ok)
+
+	// 未配置密钥时不放宽
+	_, ok = serve("", "")
+	assert.False(t, ok)
+	_, ok = serve("", "anything")
+	assert.False(t, ok)
+}
@@ -0,0 +1,40 @@
+package router
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+
+	"github.com/gin-gonic/gin"
+	"github.com/qq1060656096/drugo/kernel"
+)
+
+// RequestIDHeader 是携带请求 ID 的 HTTP 头
+const RequestIDHeader = "X-Request-ID"
+
+// MetaMiddleware 返回将请求元数据写入请求上下文的 gin 中间件：
+//   - kernel.MetaRequestID：优先使用请求头 X-Request-ID，缺失时生成新的 ID，并写回响应头
+//   - kernel.MetaClientIP：gin 解析的客户端 IP
+//
+// 处理函数中通过 kernel.Meta(c.Request.Context(), key) 读取，
+// 或使用 kernel.LoggerFromContext 输出带元数据字段的日志。
+func MetaMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := c.GetHeader(RequestIDHeader)
+		if requestID == "" {
+			requestID = newRequestID()
+		}
+		c.Header(RequestIDHeader, requestID)
+
+		ctx := kernel.WithMeta(c.Request.Context(), kernel.MetaRequestID, requestID)
+		ctx = kernel.WithMeta(ctx, kernel.MetaClientIP, c.ClientIP())
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
+
+// newRequestID 生成 32 位十六进制的随机请求 ID
+func newRequestID() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
@@ -0,0 +1,44 @@
+package router
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/qq1060656096/drugo/kernel"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestMetaMiddleware 测试中间件写入请求 ID 与客户端 IP
+func TestMetaMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	engine.Use(MetaMiddleware())
+
+	var meta map[string]string
+	engine.GET("/", func(c *gin.Context) {
+		meta = kernel.AllMeta(c.Request.Context())
+	})
+
+	t.Run("generated request id", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.RemoteAddr = "10.0.0.1:1234"
+		engine.ServeHTTP(w, req)
+
+		assert.Len(t, meta[kernel.MetaRequestID], 32)
+		assert.Equal(t, meta[kernel.MetaRequestID], w.Header().Get(RequestIDHeader))
+		assert.Equal(t, "10.0.0.1", meta[kernel.MetaClientIP])
+	})
+
+	t.Run("request id from header", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set(RequestIDHeader, "abc")
+		engine.ServeHTTP(w, req)
+
+		assert.Equal(t, "abc", meta[kernel.MetaRequestID])
+		assert.Equal(t, "abc", w.Header().Get(RequestIDHeader))
+	})
+}
@@ -0,0 +1,91 @@
+package router
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 常用的具名中间件名称，模块通过 RequireMiddleware 引用，由 main.go 或 provider 通过 ProvideMiddleware 提供实现
+const (
+	MiddlewareAuth      = "auth"
+	MiddlewareRateLimit = "ratelimit"
+	MiddlewareTenant    = "tenant"
+)
+
+// ErrMissingMiddleware 表示模块通过 RequireMiddleware 声明的中间件在 Setup 时没有提供
+var ErrMissingMiddleware = errors.New("router: missing middleware")
+
+// ProvideMiddleware 以 name 提供一个具名中间件实现，同名中间件以最后一次提供的为准。
+// 只需要在 Setup 之前提供，与模块注册路由的先后顺序无关
+func (r *Registry[T]) ProvideMiddleware(name string, mw gin.HandlerFunc) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.mws == nil {
+		r.mws = make(map[string]gin.HandlerFunc)
+	}
+	r.mws[name] = mw
+}
+
+// ProvideMiddleware 向默认注册表提供具名中间件，见 Registry.ProvideMiddleware
+func ProvideMiddleware(name string, mw gin.HandlerFunc) {
+	defaultRegistry.ProvideMiddleware(name, mw)
+}
+
+// middleware 按 names 的顺序查找具名中间件，返回找到的中间件与缺失的名称
+func (r *Registry[T]) middleware(names []string) ([]gin.HandlerFunc, []string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var handlers []gin.HandlerFunc
+	var missing []string
+	for _, name := range names {
+		mw, ok := r.mws[name]
+		if !ok || mw == nil {
+			missing = append(missing, name)
+			continue
+		}
+		handlers = append(handlers, mw)
+	}
+	return handlers, missing
+}
+
+// GroupOption 是 RegisterGroup 的可选配置
+type GroupOption func(*groupOptions)
+
+// groupOptions 保存 RegisterGroup 的可选配置
+type groupOptions struct {
+	middleware []string // 依赖的具名中间件，按声明顺序执行
+}
+
+// RequireMiddleware 声明模块路由依赖的具名中间件，多次使用时按声明顺序追加。
+// 模块只引用名称，无需导入中间件所在的包，避免循环依赖
+func RequireMiddleware(names ...string) GroupOption {
+	return func(o *groupOptions) {
+		o.middleware = append(o.middleware, names...)
+	}
+}
+
+// RegisterGroup 向 reg 注册模块 module 的路由，f 在 Setup 时收到一个路由分组。
+// 通过 RequireMiddleware 声明的中间件在 Setup 时按名称查找（见 ProvideMiddleware），
+// 按声明顺序包裹 f 注册的所有路由，在引擎全局中间件之后执行。
+//
+// 任一声明的中间件没有提供时，Setup panic、SetupE 返回包装了 ErrMissingMiddleware 的错误，
+// 错误中包含模块名称与缺失的中间件名称，不会注册任何路由，避免在缺少鉴权等中间件时对外提供服务
+func RegisterGroup(reg *Registry[*gin.Engine], module string, f func(r gin.IRouter), opts ...GroupOption) {
+	o := &groupOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	reg.add(func() (func(*gin.Engine), error) {
+		handlers, missing := reg.middleware(o.middleware)
+		if len(missing) > 0 {
+			return nil, fmt.Errorf("%w: module %q requires %s", ErrMissingMiddleware, module, strings.Join(missing, ", "))
+		}
+		return func(e *gin.Engine) {
+			f(e.Group("", handlers...))
+		}, nil
+	})
+}
@@ -0,0 +1,88 @@
+package router
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// traceMiddleware 返回将 name 追加到 X-Trace 响应头的中间件
+func traceMiddleware(name string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Writer.Header().Add("X-Trace", name)
+		c.Next()
+	}
+}
+
+// TestRegisterGroup_RequireMiddleware 测试按声明顺序包裹模块路由，且只作用于该模块
+func TestRegisterGroup_RequireMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	reg := New[*gin.Engine]()
+	reg.ProvideMiddleware(MiddlewareTenant, traceMiddleware("tenant"))
+	reg.ProvideMiddleware(MiddlewareAuth, traceMiddleware("auth"))
+
+	RegisterGroup(reg, "order", func(r gin.IRouter) {
+		r.POST("/orders", func(c *gin.Context) { c.Status(http.StatusCreated) })
+	}, RequireMiddleware(MiddlewareAuth), RequireMiddleware(MiddlewareTenant))
+	reg.Register(func(r *gin.Engine) {
+		r.GET("/public", func(c *gin.Context) { c.Status(http.StatusOK) })
+	})
+
+	engine := gin.New()
+	engine.Use(traceMiddleware("global"))
+	require.NoError(t, reg.SetupE(engine))
+
+	w := httptest.NewRecorder()
+	engine.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/orders", nil))
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, []string{"global", "auth", "tenant"}, w.Header().Values("X-Trace"))
+
+	w = httptest.NewRecorder()
+	engine.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/public", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, []string{"global"}, w.Header().Values("X-Trace"))
+}
+
+// TestRegisterGroup_MissingMiddleware 测试缺少中间件时 Setup 失败且不注册任何路由
+func TestRegisterGroup_MissingMiddleware(t *testing.T) {
+	reg := New[*gin.Engine]()
+	reg.ProvideMiddleware(MiddlewareAuth, traceMiddleware("auth"))
+	reg.Register(func(r *gin.Engine) {
+		r.GET("/public", func(c *gin.Context) {})
+	})
+	RegisterGroup(reg, "order", func(r gin.IRouter) {
+		r.POST("/orders", func(c *gin.Context) {})
+	}, RequireMiddleware(MiddlewareAuth, MiddlewareTenant, MiddlewareRateLimit))
+	RegisterGroup(reg, "user", func(r gin.IRouter) {}, RequireMiddleware(MiddlewareTenant))
+
+	engine := gin.New()
+	err := reg.SetupE(engine)
+	require.ErrorIs(t, err, ErrMissingMiddleware)
+	assert.ErrorContains(t, err, `module "order" requires tenant, ratelimit`)
+	assert.ErrorContains(t, err, `module "user" requires tenant`)
+	assert.Empty(t, engine.Routes(), "no routes are registered when a requirement is missing")
+
+	assert.PanicsWithError(t, err.Error(), func() { reg.Setup(gin.New()) })
+}
+
+// TestRegisterGroup_ProvideAfterRegister 测试在注册路由之后、Setup 之前提供中间件
+func TestRegisterGroup_ProvideAfterRegister(t *testing.T) {
+	reg := New[*gin.Engine]()
+	RegisterGroup(reg, "order", func(r gin.IRouter) {
+		r.GET("/orders", func(c *gin.Context) { c.Status(http.StatusOK) })
+	}, RequireMiddleware(MiddlewareAuth))
+
+	reg.ProvideMiddleware(MiddlewareAuth, func(c *gin.Context) {
+		c.AbortWithStatus(http.StatusUnauthorized)
+	})
+	engine := gin.New()
+	require.NoError(t, reg.SetupE(engine))
+
+	w := httptest.NewRecorder()
+	engine.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/orders", nil))
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
@@ -1,6 +1,7 @@
 package router
 
 import (
+	"errors"
 	"sync"
 
 	"github.com/gin-gonic/gin"
@@ -8,8 +9,18 @@ import (
 
 // Registry 是一个函数注册表，注册的函数会在 Setup 时统一执行。
 type Registry[T any] struct {
-	mu sync.Mutex
-	fs []func(T)
+	mu   sync.Mutex
+	fs   []setupFunc[T]
+	keys map[string]struct{}        // RegisterOnce 已使用的键
+	mws  map[string]gin.HandlerFunc // ProvideMiddleware 提供的具名中间件
+}
+
+// setupFunc 在 Setup 时返回需要执行的注册函数，返回错误时 Setup 不执行任何注册函数
+type setupFunc[T any] func() (func(T), error)
+
+// plain 将不依赖任何条件的注册函数包装为 setupFunc
+func plain[T any](f func(T)) setupFunc[T] {
+	return func() (func(T), error) { return f, nil }
 }
 
 // New 创建一个新的 Registry
@@ -19,21 +30,66 @@ func New[T any]() *Registry[T] {
 
 // Register 添加一个注册函数
 func (r *Registry[T]) Register(f func(T)) {
+	r.add(plain(f))
+}
+
+// add 添加一个 setupFunc
+func (r *Registry[T]) add(f setupFunc[T]) {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 	r.fs = append(r.fs, f)
 }
 
-// Setup 执行所有注册函数，将 p 透传给每个函数
+// RegisterOnce 以 key 去重添加一个注册函数，同一个 key 只有第一次注册生效。
+// 返回是否添加成功，常用于提供可重复调用的路由包（见 RegisterHealth、RegisterDebug）
+func (r *Registry[T]) RegisterOnce(key string, f func(T)) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.keys[key]; ok {
+		return false
+	}
+	if r.keys == nil {
+		r.keys = make(map[string]struct{})
+	}
+	r.keys[key] = struct{}{}
+	r.fs = append(r.fs, plain(f))
+	return true
+}
+
+// Setup 执行所有注册函数，将 p 透传给每个函数。
+// 注册函数的依赖无法满足时（例如 RequireMiddleware 声明的中间件没有提供）panic，需要处理错误时使用 SetupE
 func (r *Registry[T]) Setup(p T) {
+	if err := r.SetupE(p); err != nil {
+		panic(err)
+	}
+}
+
+// SetupE 与 Setup 相同，但依赖无法满足时返回用 errors.Join 合并的全部错误。
+// 执行任何注册函数之前会先检查所有依赖，返回错误时 p 不会被修改
+func (r *Registry[T]) SetupE(p T) error {
 	r.mu.Lock()
-	fs := make([]func(T), len(r.fs))
+	fs := make([]setupFunc[T], len(r.fs))
 	copy(fs, r.fs) // 拷贝一份，避免在执行时被修改
 	r.mu.Unlock()
 
-	for _, f := range fs {
+	run := make([]func(T), 0, len(fs))
+	var errs []error
+	for _, prepare := range fs {
+		f, err := prepare()
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		run = append(run, f)
+	}
+	if err := errors.Join(errs...); err != nil {
+		return err
+	}
+
+	for _, f := range run {
 		f(p)
 	}
+	return nil
 }
 
 // defaultRegistry 是默认的注册表实例，用于存放所有注册的路由
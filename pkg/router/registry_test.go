@@ -372,3 +372,17 @@ func BenchmarkRegistry_ConcurrentSetup(b *testing.B) {
 		}
 	})
 }
+
+// TestRegistry_RegisterOnce 测试同一个键只注册一次
+func TestRegistry_RegisterOnce(t *testing.T) {
+	registry := New[*int]()
+	f := func(p *int) { *p++ }
+
+	assert.True(t, registry.RegisterOnce("a", f))
+	assert.False(t, registry.RegisterOnce("a", f))
+	assert.True(t, registry.RegisterOnce("b", f))
+
+	var count int
+	registry.Setup(&count)
+	assert.Equal(t, 2, count)
+}
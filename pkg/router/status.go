@@ -0,0 +1,47 @@
+package router
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DefaultStatusPath 是状态快照路由的默认路径
+const DefaultStatusPath = "/status"
+
+// StatusOptions 是 RegisterStatus 的配置
+type StatusOptions struct {
+	// Prefix 是路由前缀，例如 "/internal"，为空时挂载在根路径
+	Prefix string
+	// Snapshot 返回序列化为 JSON 的状态快照，必填；health 为 true 表示请求要求执行健康检查（?health=true）。
+	// 通常直接使用 drugo.Drugo.StatusSnapshot
+	Snapshot func(ctx context.Context, health bool) any
+	// Token 不为空时请求必须携带 "Authorization: Bearer <Token>"，否则返回 401
+	Token string
+}
+
+// RegisterStatus 向 reg 注册状态快照路由 GET {Prefix}/status，响应 opts.Snapshot 返回值的 JSON。
+// 查询参数 health 为 true 时执行健康检查，无效的取值返回 400。
+// opts.Snapshot 为 nil 时返回错误且不注册；同一个 Prefix 重复调用只会注册一次
+func RegisterStatus(reg *Registry[*gin.Engine], opts StatusOptions) error {
+	if opts.Snapshot == nil {
+		return errors.New("router: status snapshot func is nil")
+	}
+	reg.RegisterOnce("status:"+opts.Prefix, func(r *gin.Engine) {
+		r.Group(opts.Prefix, debugGuard(nil, opts.Token)).GET(DefaultStatusPath, func(c *gin.Context) {
+			health := false
+			if v := c.Query("health"); v != "" {
+				var err error
+				if health, err = strconv.ParseBool(v); err != nil {
+					c.JSON(http.StatusBadRequest, gin.H{"error": "invalid health parameter: " + v})
+					return
+				}
+			}
+			c.JSON(http.StatusOK, opts.Snapshot(c.Request.Context(), health))
+		})
+	})
+	return nil
+}
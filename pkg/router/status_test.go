@@ -0,0 +1,40 @@
+package router
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestRegisterStatus 测试状态快照路由的 JSON 响应、health 参数与令牌校验
+func TestRegisterStatus(t *testing.T) {
+	reg := New[*gin.Engine]()
+	require.Error(t, RegisterStatus(reg, StatusOptions{}))
+
+	snapshot := func(ctx context.Context, health bool) any {
+		return map[string]any{"schema_version": 1, "health": health}
+	}
+	require.NoError(t, RegisterStatus(reg, StatusOptions{Prefix: "/internal", Snapshot: snapshot, Token: "secret"}))
+	require.NoError(t, RegisterStatus(reg, StatusOptions{Prefix: "/internal", Snapshot: snapshot})) // 重复注册不会导致路由冲突 panic
+
+	req := httptest.NewRequest(http.MethodGet, "/internal/status", nil)
+	assert.Equal(t, http.StatusUnauthorized, serve(reg, req).Code)
+
+	req.Header.Set("Authorization", "Bearer secret")
+	w := serve(reg, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"schema_version":1,"health":false}`, w.Body.String())
+
+	req = httptest.NewRequest(http.MethodGet, "/internal/status?health=true", nil)
+	req.Header.Set("Authorization", "Bearer secret")
+	assert.JSONEq(t, `{"schema_version":1,"health":true}`, serve(reg, req).Body.String())
+
+	req = httptest.NewRequest(http.MethodGet, "/internal/status?health=maybe", nil)
+	req.Header.Set("Authorization", "Bearer secret")
+	assert.Equal(t, http.StatusBadRequest, serve(reg, req).Code)
+}
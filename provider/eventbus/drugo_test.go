@@ -0,0 +1,46 @@
+package eventbus_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/qq1060656096/drugo/drugo"
+	"github.com/qq1060656096/drugo/kernel"
+	"github.com/qq1060656096/drugo/provider/eventbus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type userDeleted struct {
+	UserID int
+}
+
+// TestBus_Drugo 测试通过携带内核的上下文订阅与发布，停机时排空队列，状态快照中包含计数器
+func TestBus_Drugo(t *testing.T) {
+	bus := eventbus.New()
+	app := drugo.New(drugo.WithService(bus), drugo.WithRoot(t.TempDir()))
+	require.NoError(t, app.Boot(context.Background()))
+	ctx := kernel.WithContext(context.Background(), app)
+
+	var got []int
+	_, err := eventbus.Subscribe(ctx, "user.deleted", func(_ context.Context, e userDeleted) error {
+		got = append(got, e.UserID)
+		return nil
+	})
+	require.NoError(t, err)
+	for i := range 3 {
+		require.NoError(t, eventbus.Publish(ctx, "user.deleted", userDeleted{UserID: i}))
+	}
+
+	require.NoError(t, app.Shutdown(context.Background()))
+	assert.Equal(t, []int{0, 1, 2}, got)
+	assert.Equal(t, int64(3), app.Status()[eventbus.Name].Metrics["delivered"])
+	assert.ErrorIs(t, eventbus.Publish(ctx, "user.deleted", userDeleted{}), eventbus.ErrBusClosed)
+}
+
+// TestBus_NoKernel 测试 ctx 中没有内核时返回错误
+func TestBus_NoKernel(t *testing.T) {
+	assert.Error(t, eventbus.Publish(context.Background(), "user.deleted", userDeleted{}))
+	_, err := eventbus.Subscribe(context.Background(), "user.deleted", func(context.Context, userDeleted) error { return nil })
+	assert.Error(t, err)
+}
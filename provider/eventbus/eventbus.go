@@ -0,0 +1,569 @@
+// Package eventbus 提供进程内的事件总线，模块之间通过主题发布与订阅事件，而不必互相导入或使用全局变量。
+// 事件总线实现 kernel.Service，注册到应用后由框架管理生命周期：
+// Boot 时读取配置，Close 时拒绝新事件并在排空超时内投递完队列中的事件。
+//
+//	bus := eventbus.New()
+//	app := drugo.MustNewApp(drugo.WithServices(bus, users, goods))
+//
+//	// goods 模块
+//	unsubscribe, err := eventbus.Subscribe(ctx, "user.deleted", func(ctx context.Context, e UserDeleted) error {
+//	    return goods.removeByOwner(ctx, e.UserID)
+//	})
+//
+//	// users 模块
+//	err := eventbus.Publish(ctx, "user.deleted", UserDeleted{UserID: id})
+//
+// 每个主题只承载一种事件类型，由第一次 Subscribe 或 Publish 确定，之后类型不同的 Subscribe 与 Publish 返回 ErrTypeMismatch。
+// 每个订阅者拥有独立的有界队列与投递 goroutine，同一主题的事件按发布顺序投递给每个订阅者。
+package eventbus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/qq1060656096/drugo/kernel"
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+)
+
+const (
+	// Name 是事件总线默认的服务名称与配置段名称，Publish 与 Subscribe 按该名称从内核中获取事件总线
+	Name = "eventbus"
+	// DefaultQueueSize 是每个订阅者队列容量的默认值
+	DefaultQueueSize = 256
+	// DefaultDrainTimeout 是 Close 排空队列的默认超时时间
+	DefaultDrainTimeout = 10 * time.Second
+)
+
+var (
+	// ErrTypeMismatch 表示 Subscribe 或 Publish 的事件类型与主题已确定的事件类型不同
+	ErrTypeMismatch = errors.New("eventbus: event type mismatch")
+	// ErrBusClosed 表示事件总线已经关闭，不再接收事件与订阅
+	ErrBusClosed = errors.New("eventbus: bus closed")
+	// ErrNotBooted 表示事件总线尚未 Boot
+	ErrNotBooted = errors.New("eventbus: bus not booted")
+	// ErrNilHandler 表示订阅的处理函数为 nil
+	ErrNilHandler = errors.New("eventbus: nil handler")
+	// ErrHandlerPanic 表示处理函数执行时发生了 panic，panic 已被恢复
+	ErrHandlerPanic = errors.New("eventbus: handler panicked")
+	// ErrDrainTimeout 表示 Close 在排空超时内没有投递完所有事件
+	ErrDrainTimeout = errors.New("eventbus: drain timeout")
+	// ErrInvalidConfig 表示事件总线的配置无效
+	ErrInvalidConfig = errors.New("eventbus: invalid config")
+)
+
+// Overflow 是订阅者队列已满时 Publish 的处理策略
+type Overflow string
+
+const (
+	// OverflowBlock 阻塞 Publish 直到队列有空位或 Publish 的 ctx 取消，不丢失事件，默认策略
+	OverflowBlock Overflow = "block"
+	// OverflowDrop 丢弃该订阅者的这条事件并计入 Dropped，Publish 不会阻塞
+	OverflowDrop Overflow = "drop"
+)
+
+// Config 是事件总线的配置，对应与事件总线同名的配置段，例如：
+//
+//	eventbus:
+//	  queue_size: 256     # 每个订阅者的队列容量
+//	  overflow: block     # 队列已满时的策略：block 或 drop
+//	  drain_timeout: 10s  # Close 排空队列的超时时间
+//
+// 配置段中出现的配置项覆盖 BusOption 设置的值。
+type Config struct {
+	QueueSize    int           `mapstructure:"queue_size"`    // 每个订阅者的队列容量
+	Overflow     Overflow      `mapstructure:"overflow"`      // 队列已满时的策略
+	DrainTimeout time.Duration `mapstructure:"drain_timeout"` // Close 排空队列的超时时间，0 表示只受 Close 的 ctx 约束
+}
+
+// validate 校验配置
+func (c Config) validate() error {
+	switch {
+	case c.QueueSize <= 0:
+		return fmt.Errorf("%w: queue_size must be positive, got %d", ErrInvalidConfig, c.QueueSize)
+	case c.Overflow != OverflowBlock && c.Overflow != OverflowDrop:
+		return fmt.Errorf("%w: overflow must be %q or %q, got %q", ErrInvalidConfig, OverflowBlock, OverflowDrop, c.Overflow)
+	case c.DrainTimeout < 0:
+		return fmt.Errorf("%w: drain_timeout must not be negative, got %s", ErrInvalidConfig, c.DrainTimeout)
+	}
+	return nil
+}
+
+// BusOption 配置事件总线
+type BusOption func(*Bus)
+
+// WithName 设置服务名称与配置段名称，默认为 Name。
+// 使用其他名称时 Publish 与 Subscribe 无法从内核中找到事件总线，需要使用 PublishTo 与 SubscribeTo
+func WithName(name string) BusOption {
+	return func(b *Bus) {
+		b.name = name
+	}
+}
+
+// WithQueueSize 设置每个订阅者的队列容量，默认为 DefaultQueueSize
+func WithQueueSize(n int) BusOption {
+	return func(b *Bus) {
+		b.cfg.QueueSize = n
+	}
+}
+
+// WithOverflow 设置队列已满时的策略，默认为 OverflowBlock
+func WithOverflow(o Overflow) BusOption {
+	return func(b *Bus) {
+		b.cfg.Overflow = o
+	}
+}
+
+// WithDrainTimeout 设置 Close 排空队列的超时时间，默认为 DefaultDrainTimeout
+func WithDrainTimeout(d time.Duration) BusOption {
+	return func(b *Bus) {
+		b.cfg.DrainTimeout = d
+	}
+}
+
+// Stats 是事件总线的计数器快照
+type Stats struct {
+	Published   int64 // 被接收的 Publish 调用数
+	Delivered   int64 // 处理函数返回 nil 的投递数
+	Failed      int64 // 处理函数返回错误或发生 panic 的投递数
+	Panicked    int64 // 处理函数发生 panic 的投递数，同时计入 Failed
+	Dropped     int64 // 因订阅者队列已满（OverflowDrop）被丢弃的投递数
+	Discarded   int64 // 因取消订阅或排空超时没有投递的事件数
+	Queued      int64 // 所有订阅者队列中等待投递的事件数
+	Subscribers int64 // 当前的订阅者数
+	Topics      int64 // 已确定事件类型的主题数
+}
+
+var (
+	_ kernel.Service         = (*Bus)(nil)
+	_ kernel.Configurable    = (*Bus)(nil)
+	_ kernel.LoggerAware     = (*Bus)(nil)
+	_ kernel.MetricsReporter = (*Bus)(nil)
+)
+
+// Bus 是进程内的事件总线，使用 New 创建
+type Bus struct {
+	name   string
+	cfg    Config
+	logger *zap.Logger
+
+	mu      sync.RWMutex
+	booted  bool
+	closed  bool
+	closing chan struct{}      // 进行中的 Publish 全部返回后由 Close 关闭，订阅者排空队列后退出
+	abort   context.Context    // 排空超时后取消，未投递的事件不再投递，正在执行的处理函数的 ctx 被取消
+	cancel  context.CancelFunc // 取消 abort
+
+	topicsMu sync.Mutex
+	topics   map[string]*topic
+
+	publishing  sync.WaitGroup // 进行中的 Publish
+	subscribers sync.WaitGroup // 投递 goroutine
+
+	published atomic.Int64
+	delivered atomic.Int64
+	failed    atomic.Int64
+	panicked  atomic.Int64
+	dropped   atomic.Int64
+	discarded atomic.Int64
+}
+
+// topic 是一个主题的订阅者列表，mu 串行化同一主题的 Publish 以保证所有订阅者看到相同的顺序
+type topic struct {
+	typ  reflect.Type
+	mu   sync.Mutex
+	subs []*subscriber
+}
+
+// envelope 是订阅者队列中的一条事件
+type envelope struct {
+	ctx   context.Context
+	event any
+}
+
+// subscriber 是一个订阅者，拥有独立的队列与投递 goroutine
+type subscriber struct {
+	topic   string
+	handler func(ctx context.Context, event any) error
+	queue   chan envelope
+	done    chan struct{} // 取消订阅时关闭
+	once    sync.Once
+}
+
+// New 创建事件总线
+func New(opts ...BusOption) *Bus {
+	b := &Bus{
+		name: Name,
+		cfg: Config{
+			QueueSize:    DefaultQueueSize,
+			Overflow:     OverflowBlock,
+			DrainTimeout: DefaultDrainTimeout,
+		},
+		topics:  make(map[string]*topic),
+		closing: make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(b)
+	}
+	b.abort, b.cancel = context.WithCancel(context.Background())
+	return b
+}
+
+// Name 返回服务名称
+func (b *Bus) Name() string {
+	return b.name
+}
+
+// Config 返回事件总线当前的配置
+func (b *Bus) Config() Config {
+	return b.cfg
+}
+
+// SetLogger 设置记录处理函数错误的 logger，实现 kernel.LoggerAware
+func (b *Bus) SetLogger(l *zap.Logger) {
+	b.logger = l
+}
+
+// Configure 读取与事件总线同名的配置段，实现 kernel.Configurable。
+// 配置段不存在时（v 为 nil）使用 BusOption 设置的值
+func (b *Bus) Configure(v *viper.Viper) error {
+	if v == nil {
+		return nil
+	}
+	cfg := b.cfg
+	if err := v.Unmarshal(&cfg); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidConfig, err)
+	}
+	if err := cfg.validate(); err != nil {
+		return err
+	}
+	b.cfg = cfg
+	return nil
+}
+
+// Boot 校验配置，之后即可订阅与发布事件。
+// 在其他服务的 Boot 中订阅时，事件总线需要先于这些服务注册
+func (b *Bus) Boot(ctx context.Context) error {
+	if err := b.cfg.validate(); err != nil {
+		return err
+	}
+	if b.logger == nil {
+		b.logger = kernel.ServiceLoggerFromContext(ctx)
+	}
+	b.mu.Lock()
+	b.booted = true
+	b.mu.Unlock()
+	return nil
+}
+
+// Close 立即拒绝新事件与订阅，并在排空超时（与 ctx）内等待所有订阅者投递完队列中的事件。
+// 超时后取消处理函数的 ctx，尚未投递的事件不再投递并计入 Discarded，返回包装了 ErrDrainTimeout 的错误。
+// Close 是幂等的
+func (b *Bus) Close(ctx context.Context) error {
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		return nil
+	}
+	b.closed = true
+	b.mu.Unlock()
+
+	// 先等待进行中的 Publish 入队，再通知订阅者排空队列，保证已被接收的事件不会遗漏
+	done := make(chan struct{})
+	go func() {
+		b.publishing.Wait()
+		close(b.closing)
+		b.subscribers.Wait()
+		close(done)
+	}()
+
+	var timeout <-chan time.Time
+	if b.cfg.DrainTimeout > 0 {
+		timer := time.NewTimer(b.cfg.DrainTimeout)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+	select {
+	case <-done:
+		b.cancel()
+		return nil
+	case <-timeout:
+	case <-ctx.Done():
+	}
+	queued := b.queued()
+	b.cancel()
+	return fmt.Errorf("%w: %d events queued", ErrDrainTimeout, queued)
+}
+
+// Stats 返回计数器快照
+func (b *Bus) Stats() Stats {
+	s := Stats{
+		Published: b.published.Load(),
+		Delivered: b.delivered.Load(),
+		Failed:    b.failed.Load(),
+		Panicked:  b.panicked.Load(),
+		Dropped:   b.dropped.Load(),
+		Discarded: b.discarded.Load(),
+	}
+	b.topicsMu.Lock()
+	defer b.topicsMu.Unlock()
+	s.Topics = int64(len(b.topics))
+	for _, t := range b.topics {
+		t.mu.Lock()
+		s.Subscribers += int64(len(t.subs))
+		for _, sub := range t.subs {
+			s.Queued += int64(len(sub.queue))
+		}
+		t.mu.Unlock()
+	}
+	return s
+}
+
+// Metrics 以指标的形式返回 Stats，实现 kernel.MetricsReporter
+func (b *Bus) Metrics() map[string]int64 {
+	s := b.Stats()
+	return map[string]int64{
+		"published":   s.Published,
+		"delivered":   s.Delivered,
+		"failed":      s.Failed,
+		"panicked":    s.Panicked,
+		"dropped":     s.Dropped,
+		"discarded":   s.Discarded,
+		"queued":      s.Queued,
+		"subscribers": s.Subscribers,
+		"topics":      s.Topics,
+	}
+}
+
+// Publish 从 ctx 携带的内核中获取名为 Name 的事件总线，将 event 发布到 topic，见 PublishTo
+func Publish[T any](ctx context.Context, topic string, event T) error {
+	b, err := kernel.ServiceFromContext[*Bus](ctx, Name)
+	if err != nil {
+		return err
+	}
+	return PublishTo(ctx, b, topic, event)
+}
+
+// Subscribe 从 ctx 携带的内核中获取名为 Name 的事件总线，订阅 topic 上类型为 T 的事件，见 SubscribeTo
+func Subscribe[T any](ctx context.Context, topic string, handler func(ctx context.Context, event T) error) (unsubscribe func(), err error) {
+	b, err := kernel.ServiceFromContext[*Bus](ctx, Name)
+	if err != nil {
+		return nil, err
+	}
+	return SubscribeTo(b, topic, handler)
+}
+
+// PublishTo 将 event 放入 topic 每个订阅者的队列，返回时事件已入队而不一定已投递；没有订阅者时直接返回 nil。
+// 订阅者的队列已满时按 Config.Overflow 阻塞或丢弃；阻塞时 ctx 取消则返回 ctx.Err()，已入队的订阅者仍会收到事件。
+// block 策略下处理函数向自己订阅的主题发布事件可能因队列已满而永久阻塞，这种场景应使用 drop 策略或另起 goroutine 发布。
+// 处理函数的 ctx 保留 ctx 中的值（内核、logger、链路追踪等），但不随其取消。
+// T 与主题已确定的事件类型不同时返回 ErrTypeMismatch，事件总线已关闭时返回 ErrBusClosed，尚未 Boot 时返回 ErrNotBooted
+func PublishTo[T any](ctx context.Context, b *Bus, topic string, event T) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	b.mu.RLock()
+	err := b.usable()
+	if err == nil {
+		b.publishing.Add(1)
+	}
+	b.mu.RUnlock()
+	if err != nil {
+		return err
+	}
+	defer b.publishing.Done()
+
+	t, err := b.topic(topic, reflect.TypeFor[T]())
+	if err != nil {
+		return err
+	}
+	b.published.Add(1)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	env := envelope{ctx: context.WithoutCancel(ctx), event: event}
+	for _, sub := range t.subs {
+		if b.cfg.Overflow == OverflowDrop {
+			select {
+			case sub.queue <- env:
+			case <-sub.done:
+			default:
+				b.dropped.Add(1)
+			}
+			continue
+		}
+		select {
+		case sub.queue <- env:
+		case <-sub.done:
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-b.abort.Done():
+			return ErrBusClosed
+		}
+	}
+	return nil
+}
+
+// SubscribeTo 订阅 topic 上类型为 T 的事件，handler 在订阅者独立的 goroutine 中按发布顺序逐个执行。
+// handler 返回的错误与 panic 被记录并计为失败，不影响后续事件与其他订阅者。
+// 返回的 unsubscribe 是幂等的，可以在 handler 中调用：正在执行的 handler 照常完成，队列中剩余的事件不再投递。
+// T 与主题已确定的事件类型不同时返回 ErrTypeMismatch，事件总线已关闭时返回 ErrBusClosed，尚未 Boot 时返回 ErrNotBooted
+func SubscribeTo[T any](b *Bus, topic string, handler func(ctx context.Context, event T) error) (unsubscribe func(), err error) {
+	if handler == nil {
+		return nil, ErrNilHandler
+	}
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	if err := b.usable(); err != nil {
+		return nil, err
+	}
+	t, err := b.topic(topic, reflect.TypeFor[T]())
+	if err != nil {
+		return nil, err
+	}
+	sub := &subscriber{
+		topic: topic,
+		handler: func(ctx context.Context, event any) error {
+			return handler(ctx, event.(T))
+		},
+		queue: make(chan envelope, b.cfg.QueueSize),
+		done:  make(chan struct{}),
+	}
+	t.mu.Lock()
+	t.subs = append(t.subs, sub)
+	t.mu.Unlock()
+
+	b.subscribers.Add(1)
+	go b.deliverLoop(sub)
+	return func() { b.unsubscribe(t, sub) }, nil
+}
+
+// usable 在事件总线不能订阅或发布时返回错误，调用方需持有 b.mu 的读锁
+func (b *Bus) usable() error {
+	switch {
+	case b.closed:
+		return ErrBusClosed
+	case !b.booted:
+		return ErrNotBooted
+	}
+	return nil
+}
+
+// topic 返回名为 name 的主题，主题不存在时以 typ 为事件类型创建，主题的事件类型之后不再改变
+func (b *Bus) topic(name string, typ reflect.Type) (*topic, error) {
+	b.topicsMu.Lock()
+	defer b.topicsMu.Unlock()
+	t, ok := b.topics[name]
+	if !ok {
+		t = &topic{typ: typ}
+		b.topics[name] = t
+	}
+	if t.typ != typ {
+		return nil, fmt.Errorf("%w: topic %q carries %s, got %s", ErrTypeMismatch, name, t.typ, typ)
+	}
+	return t, nil
+}
+
+// unsubscribe 停止向 sub 投递事件并将其从主题中移除
+func (b *Bus) unsubscribe(t *topic, sub *subscriber) {
+	sub.once.Do(func() {
+		// 先关闭 done 使阻塞在该订阅者队列上的 Publish 返回，再获取主题的锁
+		close(sub.done)
+		t.mu.Lock()
+		t.subs = slices.DeleteFunc(t.subs, func(s *subscriber) bool { return s == sub })
+		t.mu.Unlock()
+	})
+}
+
+// deliverLoop 按顺序投递 sub 队列中的事件，直到取消订阅，或事件总线关闭且队列已排空
+func (b *Bus) deliverLoop(sub *subscriber) {
+	defer b.subscribers.Done()
+	for {
+		select {
+		case <-sub.done:
+			b.discard(sub)
+			return
+		case env := <-sub.queue:
+			b.deliver(sub, env)
+		case <-b.closing:
+			for {
+				select {
+				case env := <-sub.queue:
+					b.deliver(sub, env)
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+// discard 丢弃 sub 队列中剩余的事件并计入 Discarded
+func (b *Bus) discard(sub *subscriber) {
+	for {
+		select {
+		case <-sub.queue:
+			b.discarded.Add(1)
+		default:
+			return
+		}
+	}
+}
+
+// deliver 执行一次投递并更新计数器；已取消订阅或排空超时后不再投递
+func (b *Bus) deliver(sub *subscriber, env envelope) {
+	select {
+	case <-sub.done:
+		b.discarded.Add(1)
+		return
+	default:
+	}
+	if b.abort.Err() != nil {
+		b.discarded.Add(1)
+		return
+	}
+
+	ctx, cancel := context.WithCancel(env.ctx)
+	stop := context.AfterFunc(b.abort, cancel)
+	err := safeHandle(ctx, sub, env.event)
+	stop()
+	cancel()
+	if err == nil {
+		b.delivered.Add(1)
+		return
+	}
+	b.failed.Add(1)
+	if errors.Is(err, ErrHandlerPanic) {
+		b.panicked.Add(1)
+	}
+	b.log().Error("eventbus handler failed", zap.String("bus", b.name), zap.String("topic", sub.topic), zap.Error(err))
+}
+
+// safeHandle 执行处理函数，将 panic 转换为包装了 ErrHandlerPanic 的错误
+func safeHandle(ctx context.Context, sub *subscriber, event any) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%w: %v", ErrHandlerPanic, r)
+		}
+	}()
+	return sub.handler(ctx, event)
+}
+
+// queued 返回所有订阅者队列中等待投递的事件数
+func (b *Bus) queued() int64 {
+	return b.Stats().Queued
+}
+
+// log 返回记录处理函数错误的 logger
+func (b *Bus) log() *zap.Logger {
+	if b.logger == nil {
+		return zap.NewNop()
+	}
+	return b.logger
+}
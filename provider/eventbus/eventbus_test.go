@@ -0,0 +1,344 @@
+package eventbus
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type userDeleted struct {
+	UserID int
+}
+
+// newBootedBus 创建并 Boot 一个事件总线，测试结束时关闭
+func newBootedBus(t *testing.T, opts ...BusOption) *Bus {
+	t.Helper()
+	b := New(opts...)
+	require.NoError(t, b.Boot(context.Background()))
+	t.Cleanup(func() { _ = b.Close(context.Background()) })
+	return b
+}
+
+// TestBus_Ordering 测试同一主题的事件按发布顺序投递
+func TestBus_Ordering(t *testing.T) {
+	b := newBootedBus(t)
+	var mu sync.Mutex
+	var got []int
+	_, err := SubscribeTo(b, "user.deleted", func(_ context.Context, e userDeleted) error {
+		mu.Lock()
+		got = append(got, e.UserID)
+		mu.Unlock()
+		return nil
+	})
+	require.NoError(t, err)
+
+	want := make([]int, 100)
+	for i := range want {
+		want[i] = i
+		require.NoError(t, PublishTo(context.Background(), b, "user.deleted", userDeleted{UserID: i}))
+	}
+	require.NoError(t, b.Close(context.Background()))
+	assert.Equal(t, want, got)
+	assert.Equal(t, int64(100), b.Stats().Delivered)
+}
+
+// TestBus_FanOut 测试每个订阅者都收到主题上的每条事件
+func TestBus_FanOut(t *testing.T) {
+	b := newBootedBus(t)
+	var counts [3]int
+	for i := range counts {
+		_, err := SubscribeTo(b, "user.deleted", func(context.Context, userDeleted) error {
+			counts[i]++
+			return nil
+		})
+		require.NoError(t, err)
+	}
+	for i := range 5 {
+		require.NoError(t, PublishTo(context.Background(), b, "user.deleted", userDeleted{UserID: i}))
+	}
+	require.NoError(t, b.Close(context.Background()))
+
+	assert.Equal(t, [3]int{5, 5, 5}, counts)
+	s := b.Stats()
+	assert.Equal(t, int64(5), s.Published)
+	assert.Equal(t, int64(15), s.Delivered)
+	assert.Equal(t, int64(3), s.Subscribers)
+	assert.Equal(t, int64(1), s.Topics)
+}
+
+// TestBus_NoSubscribers 测试没有订阅者时发布直接成功
+func TestBus_NoSubscribers(t *testing.T) {
+	b := newBootedBus(t)
+	require.NoError(t, PublishTo(context.Background(), b, "user.deleted", userDeleted{}))
+	assert.Equal(t, int64(1), b.Stats().Published)
+}
+
+// TestBus_TypeMismatch 测试主题的事件类型由第一次使用确定，之后类型不同的订阅与发布返回错误
+func TestBus_TypeMismatch(t *testing.T) {
+	b := newBootedBus(t)
+	_, err := SubscribeTo(b, "user.deleted", func(context.Context, userDeleted) error { return nil })
+	require.NoError(t, err)
+
+	_, err = SubscribeTo(b, "user.deleted", func(context.Context, string) error { return nil })
+	assert.ErrorIs(t, err, ErrTypeMismatch)
+	assert.ErrorContains(t, err, `topic "user.deleted" carries eventbus.userDeleted, got string`)
+
+	err = PublishTo(context.Background(), b, "user.deleted", &userDeleted{})
+	assert.ErrorIs(t, err, ErrTypeMismatch)
+	assert.ErrorContains(t, err, "*eventbus.userDeleted")
+
+	require.NoError(t, PublishTo(context.Background(), b, "order.paid", 42))
+	_, err = SubscribeTo(b, "order.paid", func(context.Context, int64) error { return nil })
+	assert.ErrorIs(t, err, ErrTypeMismatch)
+}
+
+// TestBus_UnsubscribeDuringDelivery 测试在处理函数中取消订阅：当前事件正常完成，剩余事件不再投递
+func TestBus_UnsubscribeDuringDelivery(t *testing.T) {
+	b := newBootedBus(t)
+	var unsubscribe func()
+	var got []int
+	entered := make(chan struct{})
+	release := make(chan struct{})
+	unsubscribe, err := SubscribeTo(b, "user.deleted", func(_ context.Context, e userDeleted) error {
+		got = append(got, e.UserID)
+		if e.UserID == 0 {
+			close(entered)
+			<-release
+			unsubscribe()
+			unsubscribe()
+		}
+		return nil
+	})
+	require.NoError(t, err)
+
+	for i := range 4 {
+		require.NoError(t, PublishTo(context.Background(), b, "user.deleted", userDeleted{UserID: i}))
+	}
+	<-entered
+	close(release)
+	require.NoError(t, b.Close(context.Background()))
+
+	assert.Equal(t, []int{0}, got)
+	s := b.Stats()
+	assert.Equal(t, int64(1), s.Delivered)
+	assert.Equal(t, int64(3), s.Discarded)
+	assert.Equal(t, int64(0), s.Subscribers)
+}
+
+// TestBus_UnsubscribeUnblocksPublish 测试取消订阅使阻塞在该订阅者队列上的发布返回
+func TestBus_UnsubscribeUnblocksPublish(t *testing.T) {
+	b := newBootedBus(t, WithQueueSize(1))
+	entered := make(chan struct{})
+	release := make(chan struct{})
+	var once sync.Once
+	unsubscribe, err := SubscribeTo(b, "user.deleted", func(context.Context, userDeleted) error {
+		once.Do(func() { close(entered) })
+		<-release
+		return nil
+	})
+	require.NoError(t, err)
+	defer close(release)
+
+	require.NoError(t, PublishTo(context.Background(), b, "user.deleted", userDeleted{UserID: 1}))
+	<-entered
+	require.NoError(t, PublishTo(context.Background(), b, "user.deleted", userDeleted{UserID: 2}))
+
+	done := make(chan error, 1)
+	go func() { done <- PublishTo(context.Background(), b, "user.deleted", userDeleted{UserID: 3}) }()
+	select {
+	case <-done:
+		t.Fatal("publish should block while the queue is full")
+	case <-time.After(20 * time.Millisecond):
+	}
+	unsubscribe()
+	select {
+	case err := <-done:
+		assert.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("publish still blocked after unsubscribe")
+	}
+}
+
+// TestBus_OverflowBlock 测试 block 策略下队列已满时发布阻塞，ctx 取消时返回 ctx 的错误
+func TestBus_OverflowBlock(t *testing.T) {
+	b := newBootedBus(t, WithQueueSize(1), WithOverflow(OverflowBlock))
+	entered := make(chan struct{})
+	release := make(chan struct{})
+	var once sync.Once
+	_, err := SubscribeTo(b, "user.deleted", func(context.Context, userDeleted) error {
+		once.Do(func() { close(entered) })
+		<-release
+		return nil
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, PublishTo(context.Background(), b, "user.deleted", userDeleted{UserID: 1}))
+	<-entered
+	require.NoError(t, PublishTo(context.Background(), b, "user.deleted", userDeleted{UserID: 2}))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	err = PublishTo(ctx, b, "user.deleted", userDeleted{UserID: 3})
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	close(release)
+	require.NoError(t, b.Close(context.Background()))
+	s := b.Stats()
+	assert.Equal(t, int64(2), s.Delivered)
+	assert.Equal(t, int64(0), s.Dropped)
+}
+
+// TestBus_OverflowDrop 测试 drop 策略下队列已满时丢弃事件并计数，发布不阻塞
+func TestBus_OverflowDrop(t *testing.T) {
+	b := newBootedBus(t, WithQueueSize(2), WithOverflow(OverflowDrop))
+	entered := make(chan struct{})
+	release := make(chan struct{})
+	var once sync.Once
+	_, err := SubscribeTo(b, "user.deleted", func(context.Context, userDeleted) error {
+		once.Do(func() { close(entered) })
+		<-release
+		return nil
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, PublishTo(context.Background(), b, "user.deleted", userDeleted{UserID: 0}))
+	<-entered
+	for i := 1; i <= 5; i++ {
+		require.NoError(t, PublishTo(context.Background(), b, "user.deleted", userDeleted{UserID: i}))
+	}
+	assert.Equal(t, int64(3), b.Stats().Dropped)
+	assert.Equal(t, int64(2), b.Stats().Queued)
+
+	close(release)
+	require.NoError(t, b.Close(context.Background()))
+	s := b.Stats()
+	assert.Equal(t, int64(6), s.Published)
+	assert.Equal(t, int64(3), s.Delivered)
+	assert.Equal(t, int64(3), s.Dropped)
+}
+
+// TestBus_PanicIsolation 测试处理函数 panic 被恢复并计为失败，不影响后续事件与其他订阅者
+func TestBus_PanicIsolation(t *testing.T) {
+	b := newBootedBus(t)
+	var panicking, healthy []int
+	_, err := SubscribeTo(b, "user.deleted", func(_ context.Context, e userDeleted) error {
+		panicking = append(panicking, e.UserID)
+		if e.UserID == 1 {
+			panic("boom")
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	_, err = SubscribeTo(b, "user.deleted", func(_ context.Context, e userDeleted) error {
+		healthy = append(healthy, e.UserID)
+		if e.UserID == 2 {
+			return errors.New("failed")
+		}
+		return nil
+	})
+	require.NoError(t, err)
+
+	for i := range 3 {
+		require.NoError(t, PublishTo(context.Background(), b, "user.deleted", userDeleted{UserID: i}))
+	}
+	require.NoError(t, b.Close(context.Background()))
+
+	assert.Equal(t, []int{0, 1, 2}, panicking)
+	assert.Equal(t, []int{0, 1, 2}, healthy)
+	s := b.Stats()
+	assert.Equal(t, int64(4), s.Delivered)
+	assert.Equal(t, int64(2), s.Failed)
+	assert.Equal(t, int64(1), s.Panicked)
+}
+
+// TestBus_DrainOnClose 测试 Close 投递完队列中的事件后返回，之后拒绝发布与订阅
+func TestBus_DrainOnClose(t *testing.T) {
+	b := newBootedBus(t)
+	var mu sync.Mutex
+	var got int
+	_, err := SubscribeTo(b, "user.deleted", func(context.Context, userDeleted) error {
+		time.Sleep(time.Millisecond)
+		mu.Lock()
+		got++
+		mu.Unlock()
+		return nil
+	})
+	require.NoError(t, err)
+	for i := range 20 {
+		require.NoError(t, PublishTo(context.Background(), b, "user.deleted", userDeleted{UserID: i}))
+	}
+
+	require.NoError(t, b.Close(context.Background()))
+	assert.Equal(t, 20, got)
+	assert.Equal(t, int64(0), b.Stats().Queued)
+
+	assert.ErrorIs(t, PublishTo(context.Background(), b, "user.deleted", userDeleted{}), ErrBusClosed)
+	_, err = SubscribeTo(b, "user.deleted", func(context.Context, userDeleted) error { return nil })
+	assert.ErrorIs(t, err, ErrBusClosed)
+	assert.NoError(t, b.Close(context.Background()))
+}
+
+// TestBus_DrainTimeout 测试排空超时后取消处理函数的 ctx，剩余事件计入 Discarded
+func TestBus_DrainTimeout(t *testing.T) {
+	b := newBootedBus(t, WithDrainTimeout(20*time.Millisecond))
+	entered := make(chan struct{})
+	cancelled := make(chan struct{})
+	var once sync.Once
+	_, err := SubscribeTo(b, "user.deleted", func(ctx context.Context, _ userDeleted) error {
+		once.Do(func() { close(entered) })
+		<-ctx.Done()
+		close(cancelled)
+		return ctx.Err()
+	})
+	require.NoError(t, err)
+	require.NoError(t, PublishTo(context.Background(), b, "user.deleted", userDeleted{UserID: 1}))
+	<-entered
+	require.NoError(t, PublishTo(context.Background(), b, "user.deleted", userDeleted{UserID: 2}))
+	require.NoError(t, PublishTo(context.Background(), b, "user.deleted", userDeleted{UserID: 3}))
+
+	err = b.Close(context.Background())
+	assert.ErrorIs(t, err, ErrDrainTimeout)
+	assert.ErrorContains(t, err, "2 events queued")
+	<-cancelled
+	assert.Eventually(t, func() bool {
+		s := b.Stats()
+		return s.Failed == 1 && s.Discarded == 2
+	}, time.Second, time.Millisecond)
+}
+
+// TestBus_NotBooted 测试 Boot 之前不能订阅与发布
+func TestBus_NotBooted(t *testing.T) {
+	b := New()
+	assert.ErrorIs(t, PublishTo(context.Background(), b, "user.deleted", userDeleted{}), ErrNotBooted)
+	_, err := SubscribeTo(b, "user.deleted", func(context.Context, userDeleted) error { return nil })
+	assert.ErrorIs(t, err, ErrNotBooted)
+	_, err = SubscribeTo[userDeleted](b, "user.deleted", nil)
+	assert.ErrorIs(t, err, ErrNilHandler)
+}
+
+// TestBus_Configure 测试配置段覆盖选项，无效配置返回 ErrInvalidConfig
+func TestBus_Configure(t *testing.T) {
+	b := New(WithQueueSize(8))
+	require.NoError(t, b.Configure(nil))
+	assert.Equal(t, 8, b.Config().QueueSize)
+
+	v := viper.New()
+	v.Set("overflow", "drop")
+	v.Set("drain_timeout", "3s")
+	require.NoError(t, b.Configure(v))
+	assert.Equal(t, Config{QueueSize: 8, Overflow: OverflowDrop, DrainTimeout: 3 * time.Second}, b.Config())
+
+	v = viper.New()
+	v.Set("overflow", "spill")
+	assert.ErrorIs(t, b.Configure(v), ErrInvalidConfig)
+	v = viper.New()
+	v.Set("queue_size", 0)
+	assert.ErrorIs(t, b.Configure(v), ErrInvalidConfig)
+	assert.Equal(t, 8, b.Config().QueueSize)
+}
@@ -0,0 +1,28 @@
+package workerpool_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/qq1060656096/drugo/drugo"
+	"github.com/qq1060656096/drugo/provider/workerpool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestPool_Drugo 测试任务池由应用管理生命周期：停机时排空队列，状态快照中包含计数器
+func TestPool_Drugo(t *testing.T) {
+	pool := workerpool.New("jobs", workerpool.WithWorkers(2))
+	app := drugo.New(drugo.WithService(pool), drugo.WithRoot(t.TempDir()))
+	require.NoError(t, app.Boot(context.Background()))
+
+	for range 5 {
+		require.NoError(t, pool.Submit(context.Background(), func(context.Context) error { return nil }))
+	}
+	assert.Equal(t, int64(5), app.Status()["jobs"].Metrics["queued"])
+
+	require.NoError(t, app.Shutdown(context.Background()))
+	assert.Equal(t, int64(5), pool.Stats().Completed)
+	assert.Equal(t, int64(5), app.Status()["jobs"].Metrics["completed"])
+	assert.ErrorIs(t, pool.Submit(context.Background(), func(context.Context) error { return nil }), workerpool.ErrPoolClosed)
+}
@@ -0,0 +1,389 @@
+// Package workerpool 提供由有界任务队列与固定数量 worker 组成的后台任务池。
+// 任务池实现 kernel.Runner，注册到应用后由框架管理生命周期：
+// Boot 时读取配置并创建队列，Run 时启动 worker，Close 时拒绝新任务并在排空超时内执行完队列中的任务。
+//
+//	pool := workerpool.New("mailer", workerpool.WithWorkers(4))
+//	app := drugo.MustNewApp(drugo.WithService(pool))
+//
+//	err := pool.Submit(ctx, func(ctx context.Context) error {
+//	    return sendMail(ctx, to)
+//	})
+//	if errors.Is(err, workerpool.ErrQueueFull) {
+//	    // 队列已满，由调用方决定重试或降级
+//	}
+package workerpool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/qq1060656096/drugo/kernel"
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+)
+
+const (
+	// DefaultSize 是队列容量的默认值
+	DefaultSize = 1024
+	// DefaultDrainTimeout 是 Close 排空队列的默认超时时间
+	DefaultDrainTimeout = 10 * time.Second
+)
+
+var (
+	// ErrQueueFull 表示队列已满，任务没有被接收
+	ErrQueueFull = errors.New("workerpool: queue full")
+	// ErrPoolClosed 表示任务池已经关闭，不再接收任务
+	ErrPoolClosed = errors.New("workerpool: pool closed")
+	// ErrNotBooted 表示任务池尚未 Boot，队列还没有创建
+	ErrNotBooted = errors.New("workerpool: pool not booted")
+	// ErrNilTask 表示提交的任务为 nil
+	ErrNilTask = errors.New("workerpool: nil task")
+	// ErrTaskPanic 表示任务执行时发生了 panic，panic 已被恢复
+	ErrTaskPanic = errors.New("workerpool: task panicked")
+	// ErrDrainTimeout 表示 Close 在排空超时内没有执行完所有任务
+	ErrDrainTimeout = errors.New("workerpool: drain timeout")
+	// ErrInvalidConfig 表示任务池的配置无效
+	ErrInvalidConfig = errors.New("workerpool: invalid config")
+)
+
+// Task 是提交到任务池的任务。ctx 在排空超时后取消，任务应尽快返回。
+type Task func(ctx context.Context) error
+
+// Config 是任务池的配置，对应与任务池同名的配置段，例如：
+//
+//	mailer:
+//	  size: 1024          # 队列容量
+//	  workers: 8          # worker 数量
+//	  drain_timeout: 10s  # Close 排空队列的超时时间
+//
+// 配置段中出现的配置项覆盖 PoolOption 设置的值。
+type Config struct {
+	Size         int           `mapstructure:"size"`          // 队列容量
+	Workers      int           `mapstructure:"workers"`       // worker 数量，即同时执行的任务数上限
+	DrainTimeout time.Duration `mapstructure:"drain_timeout"` // Close 排空队列的超时时间，0 表示只受 Close 的 ctx 约束
+}
+
+// validate 校验配置
+func (c Config) validate() error {
+	switch {
+	case c.Size <= 0:
+		return fmt.Errorf("%w: size must be positive, got %d", ErrInvalidConfig, c.Size)
+	case c.Workers <= 0:
+		return fmt.Errorf("%w: workers must be positive, got %d", ErrInvalidConfig, c.Workers)
+	case c.DrainTimeout < 0:
+		return fmt.Errorf("%w: drain_timeout must not be negative, got %s", ErrInvalidConfig, c.DrainTimeout)
+	}
+	return nil
+}
+
+// PoolOption 配置任务池
+type PoolOption func(*Config)
+
+// WithSize 设置队列容量，默认为 DefaultSize
+func WithSize(n int) PoolOption {
+	return func(c *Config) {
+		c.Size = n
+	}
+}
+
+// WithWorkers 设置 worker 数量，默认为 runtime.NumCPU()
+func WithWorkers(n int) PoolOption {
+	return func(c *Config) {
+		c.Workers = n
+	}
+}
+
+// WithDrainTimeout 设置 Close 排空队列的超时时间，默认为 DefaultDrainTimeout
+func WithDrainTimeout(d time.Duration) PoolOption {
+	return func(c *Config) {
+		c.DrainTimeout = d
+	}
+}
+
+// Stats 是任务池的计数器快照
+type Stats struct {
+	Submitted int64 // 被接收的任务数
+	Completed int64 // 执行成功（返回 nil）的任务数
+	Failed    int64 // 返回错误或发生 panic 的任务数
+	Panicked  int64 // 发生 panic 的任务数，同时计入 Failed
+	Rejected  int64 // 因队列已满或任务池已关闭被拒绝的提交数
+	Dropped   int64 // 排空超时后没有执行的任务数
+	InFlight  int64 // 正在执行的任务数
+	Queued    int64 // 队列中等待执行的任务数
+}
+
+var (
+	_ kernel.Runner          = (*Pool)(nil)
+	_ kernel.Configurable    = (*Pool)(nil)
+	_ kernel.LoggerAware     = (*Pool)(nil)
+	_ kernel.MetricsReporter = (*Pool)(nil)
+)
+
+// Pool 是有界任务队列与固定数量 worker 组成的任务池，使用 New 创建
+type Pool struct {
+	name   string
+	cfg    Config
+	logger *zap.Logger
+
+	mu     sync.RWMutex
+	queue  chan Task
+	closed bool
+
+	startOnce sync.Once
+	workers   sync.WaitGroup
+	taskCtx   context.Context
+	cancel    context.CancelFunc
+
+	submitted atomic.Int64
+	completed atomic.Int64
+	failed    atomic.Int64
+	panicked  atomic.Int64
+	rejected  atomic.Int64
+	dropped   atomic.Int64
+	inFlight  atomic.Int64
+}
+
+// New 创建名为 name 的任务池，name 同时是服务名称与配置段名称
+func New(name string, opts ...PoolOption) *Pool {
+	cfg := Config{
+		Size:         DefaultSize,
+		Workers:      runtime.NumCPU(),
+		DrainTimeout: DefaultDrainTimeout,
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return &Pool{name: name, cfg: cfg}
+}
+
+// Name 返回服务名称
+func (p *Pool) Name() string {
+	return p.name
+}
+
+// Config 返回任务池当前的配置
+func (p *Pool) Config() Config {
+	return p.cfg
+}
+
+// SetLogger 设置记录任务错误的 logger，实现 kernel.LoggerAware
+func (p *Pool) SetLogger(l *zap.Logger) {
+	p.logger = l
+}
+
+// Configure 读取与任务池同名的配置段，实现 kernel.Configurable。
+// 配置段不存在时（v 为 nil）使用 PoolOption 设置的值
+func (p *Pool) Configure(v *viper.Viper) error {
+	if v == nil {
+		return nil
+	}
+	cfg := p.cfg
+	if err := v.Unmarshal(&cfg); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidConfig, err)
+	}
+	if err := cfg.validate(); err != nil {
+		return err
+	}
+	p.cfg = cfg
+	return nil
+}
+
+// Boot 校验配置并创建队列，之后即可提交任务；任务在 Run 启动 worker 后开始执行
+func (p *Pool) Boot(ctx context.Context) error {
+	if err := p.cfg.validate(); err != nil {
+		return err
+	}
+	if p.logger == nil {
+		p.logger = kernel.ServiceLoggerFromContext(ctx)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.queue != nil {
+		return nil
+	}
+	p.queue = make(chan Task, p.cfg.Size)
+	// 任务上下文保留 Boot 上下文中的内核与 logger，但不随其取消，只在排空超时后取消
+	p.taskCtx, p.cancel = context.WithCancel(context.WithoutCancel(ctx))
+	return nil
+}
+
+// Run 启动 worker 并阻塞直到 ctx 取消。
+// ctx 取消后 worker 继续执行队列中的任务，直到 Close 排空队列
+func (p *Pool) Run(ctx context.Context) error {
+	p.mu.RLock()
+	booted := p.queue != nil
+	p.mu.RUnlock()
+	if !booted {
+		return ErrNotBooted
+	}
+	p.start()
+	<-ctx.Done()
+	return nil
+}
+
+// Close 立即拒绝新任务，并在排空超时（与 ctx）内等待队列中的任务执行完毕。
+// 超时后取消任务的 ctx，尚未执行的任务不再执行并计入 Dropped，返回包装了 ErrDrainTimeout 的错误。
+// Close 是幂等的
+func (p *Pool) Close(ctx context.Context) error {
+	p.mu.Lock()
+	if p.closed || p.queue == nil {
+		p.closed = true
+		p.mu.Unlock()
+		return nil
+	}
+	p.closed = true
+	close(p.queue)
+	p.mu.Unlock()
+
+	// Run 没有执行时（例如启动失败）由 Close 启动 worker 排空队列
+	p.start()
+	done := make(chan struct{})
+	go func() {
+		p.workers.Wait()
+		close(done)
+	}()
+
+	var timeout <-chan time.Time
+	if p.cfg.DrainTimeout > 0 {
+		timer := time.NewTimer(p.cfg.DrainTimeout)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+	select {
+	case <-done:
+		p.cancel()
+		return nil
+	case <-timeout:
+	case <-ctx.Done():
+	}
+	p.cancel()
+	return fmt.Errorf("%w: %d tasks in flight, %d queued", ErrDrainTimeout, p.inFlight.Load(), p.queued())
+}
+
+// Submit 将 task 放入队列，队列已满时立即返回 ErrQueueFull 而不会阻塞。
+// 任务池已关闭时返回 ErrPoolClosed，尚未 Boot 时返回 ErrNotBooted，ctx 已取消时返回 ctx.Err()
+func (p *Pool) Submit(ctx context.Context, task Task) error {
+	if task == nil {
+		return ErrNilTask
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if p.closed {
+		p.rejected.Add(1)
+		return ErrPoolClosed
+	}
+	if p.queue == nil {
+		return ErrNotBooted
+	}
+	select {
+	case p.queue <- task:
+		p.submitted.Add(1)
+		return nil
+	default:
+		p.rejected.Add(1)
+		return ErrQueueFull
+	}
+}
+
+// Stats 返回计数器快照
+func (p *Pool) Stats() Stats {
+	return Stats{
+		Submitted: p.submitted.Load(),
+		Completed: p.completed.Load(),
+		Failed:    p.failed.Load(),
+		Panicked:  p.panicked.Load(),
+		Rejected:  p.rejected.Load(),
+		Dropped:   p.dropped.Load(),
+		InFlight:  p.inFlight.Load(),
+		Queued:    p.queued(),
+	}
+}
+
+// Metrics 以指标的形式返回 Stats，实现 kernel.MetricsReporter
+func (p *Pool) Metrics() map[string]int64 {
+	s := p.Stats()
+	return map[string]int64{
+		"submitted": s.Submitted,
+		"completed": s.Completed,
+		"failed":    s.Failed,
+		"panicked":  s.Panicked,
+		"rejected":  s.Rejected,
+		"dropped":   s.Dropped,
+		"in_flight": s.InFlight,
+		"queued":    s.Queued,
+	}
+}
+
+// queued 返回队列中等待执行的任务数
+func (p *Pool) queued() int64 {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return int64(len(p.queue))
+}
+
+// start 启动 worker，只执行一次
+func (p *Pool) start() {
+	p.startOnce.Do(func() {
+		for range p.cfg.Workers {
+			p.workers.Add(1)
+			go p.work()
+		}
+	})
+}
+
+// work 从队列中取出任务执行，直到队列关闭并排空；排空超时后丢弃剩余的任务
+func (p *Pool) work() {
+	defer p.workers.Done()
+	for task := range p.queue {
+		if p.taskCtx.Err() != nil {
+			p.dropped.Add(1)
+			continue
+		}
+		p.execute(task)
+	}
+}
+
+// execute 执行单个任务并更新计数器，任务的 panic 被恢复并计为失败
+func (p *Pool) execute(task Task) {
+	p.inFlight.Add(1)
+	defer p.inFlight.Add(-1)
+
+	err := p.safeRun(task)
+	if err == nil {
+		p.completed.Add(1)
+		return
+	}
+	p.failed.Add(1)
+	if errors.Is(err, ErrTaskPanic) {
+		p.panicked.Add(1)
+	}
+	p.log().Error("workerpool task failed", zap.String("pool", p.name), zap.Error(err))
+}
+
+// safeRun 执行任务，将 panic 转换为包装了 ErrTaskPanic 的错误
+func (p *Pool) safeRun(task Task) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%w: %v", ErrTaskPanic, r)
+		}
+	}()
+	return task(p.taskCtx)
+}
+
+// log 返回记录任务错误的 logger
+func (p *Pool) log() *zap.Logger {
+	if p.logger == nil {
+		return zap.NewNop()
+	}
+	return p.logger
+}
@@ -0,0 +1,269 @@
+package workerpool
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/qq1060656096/drugo/kernel"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newBootedPool 创建并 Boot 一个任务池，测试结束时关闭
+func newBootedPool(t *testing.T, opts ...PoolOption) *Pool {
+	t.Helper()
+	p := New("jobs", opts...)
+	require.NoError(t, p.Boot(context.Background()))
+	t.Cleanup(func() { _ = p.Close(context.Background()) })
+	return p
+}
+
+// runPool 在后台运行任务池，返回停止 Run 的函数
+func runPool(t *testing.T, p *Pool) func() {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- p.Run(ctx) }()
+	return func() {
+		cancel()
+		require.NoError(t, <-done)
+	}
+}
+
+// TestPool_ConcurrencyLimit 测试同时执行的任务数不超过 worker 数量
+func TestPool_ConcurrencyLimit(t *testing.T) {
+	p := newBootedPool(t, WithWorkers(2), WithSize(10))
+	stop := runPool(t, p)
+	defer stop()
+
+	var running, peak atomic.Int64
+	release := make(chan struct{})
+	started := make(chan struct{}, 10)
+	for range 6 {
+		require.NoError(t, p.Submit(context.Background(), func(context.Context) error {
+			n := running.Add(1)
+			for {
+				old := peak.Load()
+				if n <= old || peak.CompareAndSwap(old, n) {
+					break
+				}
+			}
+			started <- struct{}{}
+			<-release
+			running.Add(-1)
+			return nil
+		}))
+	}
+
+	<-started
+	<-started
+	assert.Eventually(t, func() bool { return p.Stats().InFlight == 2 }, time.Second, time.Millisecond)
+	assert.Equal(t, int64(4), p.Stats().Queued)
+	close(release)
+	assert.Eventually(t, func() bool { return p.Stats().Completed == 6 }, time.Second, time.Millisecond)
+	assert.Equal(t, int64(2), peak.Load())
+}
+
+// TestPool_QueueFull 测试队列已满时立即拒绝任务
+func TestPool_QueueFull(t *testing.T) {
+	p := newBootedPool(t, WithWorkers(1), WithSize(2))
+	noop := func(context.Context) error { return nil }
+
+	require.NoError(t, p.Submit(context.Background(), noop))
+	require.NoError(t, p.Submit(context.Background(), noop))
+	assert.ErrorIs(t, p.Submit(context.Background(), noop), ErrQueueFull)
+
+	s := p.Stats()
+	assert.Equal(t, int64(2), s.Submitted)
+	assert.Equal(t, int64(1), s.Rejected)
+	assert.Equal(t, int64(2), s.Queued)
+}
+
+// TestPool_CloseDrains 测试 Close 执行完队列中的任务，之后的提交被拒绝
+func TestPool_CloseDrains(t *testing.T) {
+	p := newBootedPool(t, WithWorkers(2), WithSize(20))
+	stop := runPool(t, p)
+
+	var done atomic.Int64
+	for range 20 {
+		require.NoError(t, p.Submit(context.Background(), func(context.Context) error {
+			time.Sleep(time.Millisecond)
+			done.Add(1)
+			return nil
+		}))
+	}
+	// Run 结束后 worker 继续执行队列中的任务，直到 Close 排空
+	stop()
+	require.NoError(t, p.Close(context.Background()))
+	assert.Equal(t, int64(20), done.Load())
+
+	assert.ErrorIs(t, p.Submit(context.Background(), func(context.Context) error { return nil }), ErrPoolClosed)
+	s := p.Stats()
+	assert.Equal(t, int64(20), s.Completed)
+	assert.Equal(t, int64(1), s.Rejected)
+	assert.Zero(t, s.Queued)
+	assert.NoError(t, p.Close(context.Background()), "Close is idempotent")
+}
+
+// TestPool_CloseWithoutRun 测试没有执行 Run 时 Close 仍然执行队列中的任务
+func TestPool_CloseWithoutRun(t *testing.T) {
+	p := newBootedPool(t, WithWorkers(1))
+	var ran atomic.Bool
+	require.NoError(t, p.Submit(context.Background(), func(context.Context) error {
+		ran.Store(true)
+		return nil
+	}))
+	require.NoError(t, p.Close(context.Background()))
+	assert.True(t, ran.Load())
+}
+
+// TestPool_DrainTimeout 测试排空超时后取消任务的 ctx 并丢弃尚未执行的任务
+func TestPool_DrainTimeout(t *testing.T) {
+	p := newBootedPool(t, WithWorkers(1), WithDrainTimeout(20*time.Millisecond))
+	stop := runPool(t, p)
+	defer stop()
+
+	started := make(chan struct{})
+	require.NoError(t, p.Submit(context.Background(), func(ctx context.Context) error {
+		close(started)
+		<-ctx.Done()
+		return ctx.Err()
+	}))
+	for range 3 {
+		require.NoError(t, p.Submit(context.Background(), func(context.Context) error { return nil }))
+	}
+	<-started
+
+	err := p.Close(context.Background())
+	assert.ErrorIs(t, err, ErrDrainTimeout)
+	assert.Eventually(t, func() bool {
+		s := p.Stats()
+		return s.Dropped == 3 && s.Failed == 1 && s.InFlight == 0
+	}, time.Second, time.Millisecond)
+	assert.Zero(t, p.Stats().Completed)
+}
+
+// TestPool_CloseContext 测试 Close 的 ctx 到期时同样停止等待
+func TestPool_CloseContext(t *testing.T) {
+	p := newBootedPool(t, WithWorkers(1), WithDrainTimeout(0))
+	require.NoError(t, p.Submit(context.Background(), func(ctx context.Context) error {
+		<-ctx.Done()
+		return nil
+	}))
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, p.Close(ctx), ErrDrainTimeout)
+}
+
+// TestPool_PanicIsolation 测试任务的 panic 被恢复并计数，worker 继续执行后续任务
+func TestPool_PanicIsolation(t *testing.T) {
+	p := newBootedPool(t, WithWorkers(1))
+	stop := runPool(t, p)
+	defer stop()
+
+	errBoom := errors.New("boom")
+	require.NoError(t, p.Submit(context.Background(), func(context.Context) error { panic("kaboom") }))
+	require.NoError(t, p.Submit(context.Background(), func(context.Context) error { return errBoom }))
+	require.NoError(t, p.Submit(context.Background(), func(context.Context) error { return nil }))
+
+	assert.Eventually(t, func() bool { return p.Stats().Completed == 1 }, time.Second, time.Millisecond)
+	s := p.Stats()
+	assert.Equal(t, int64(2), s.Failed)
+	assert.Equal(t, int64(1), s.Panicked)
+	assert.Equal(t, map[string]int64{
+		"submitted": 3, "completed": 1, "failed": 2, "panicked": 1,
+		"rejected": 0, "dropped": 0, "in_flight": 0, "queued": 0,
+	}, p.Metrics())
+}
+
+// TestPool_Lifecycle 测试 Boot 之前与无效任务的提交，以及 ctx 已取消的提交
+func TestPool_Lifecycle(t *testing.T) {
+	p := New("jobs")
+	assert.Equal(t, "jobs", p.Name())
+	noop := func(context.Context) error { return nil }
+	assert.ErrorIs(t, p.Submit(context.Background(), noop), ErrNotBooted)
+	assert.ErrorIs(t, p.Run(context.Background()), ErrNotBooted)
+
+	require.NoError(t, p.Boot(kernel.WithServiceLogger(context.Background(), nil)))
+	assert.ErrorIs(t, p.Submit(context.Background(), nil), ErrNilTask)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.ErrorIs(t, p.Submit(ctx, noop), context.Canceled)
+	assert.Zero(t, p.Stats().Submitted)
+	require.NoError(t, p.Close(context.Background()))
+}
+
+// TestPool_Configure 测试配置段覆盖选项，以及无效配置
+func TestPool_Configure(t *testing.T) {
+	p := New("jobs", WithWorkers(2), WithSize(5))
+	require.NoError(t, p.Configure(nil))
+	assert.Equal(t, Config{Size: 5, Workers: 2, DrainTimeout: DefaultDrainTimeout}, p.Config())
+
+	v := viper.New()
+	v.Set("workers", 8)
+	v.Set("drain_timeout", "2s")
+	require.NoError(t, p.Configure(v))
+	assert.Equal(t, Config{Size: 5, Workers: 8, DrainTimeout: 2 * time.Second}, p.Config())
+
+	for key, value := range map[string]any{"size": 0, "workers": -1, "drain_timeout": "-1s"} {
+		v := viper.New()
+		v.Set(key, value)
+		assert.ErrorIs(t, New("jobs").Configure(v), ErrInvalidConfig, key)
+	}
+	assert.ErrorIs(t, New("jobs", WithWorkers(0)).Boot(context.Background()), ErrInvalidConfig)
+}
+
+// TestPool_Stress 在竞态检测下并发提交、执行与关闭，计数器保持一致
+func TestPool_Stress(t *testing.T) {
+	p := newBootedPool(t, WithWorkers(8), WithSize(64))
+	stop := runPool(t, p)
+
+	const producers, perProducer = 8, 500
+	var accepted, executed atomic.Int64
+	var wg sync.WaitGroup
+	for range producers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range perProducer {
+				err := p.Submit(context.Background(), func(context.Context) error {
+					executed.Add(1)
+					if i%50 == 0 {
+						panic("stress")
+					}
+					if i%10 == 0 {
+						return errors.New("stress")
+					}
+					return nil
+				})
+				switch {
+				case err == nil:
+					accepted.Add(1)
+				case errors.Is(err, ErrQueueFull), errors.Is(err, ErrPoolClosed):
+				default:
+					t.Errorf("unexpected error: %v", err)
+				}
+				_ = p.Stats()
+			}
+		}()
+	}
+	// 在提交过程中关闭，之后的提交被拒绝
+	time.Sleep(time.Millisecond)
+	stop()
+	require.NoError(t, p.Close(context.Background()))
+	wg.Wait()
+
+	s := p.Stats()
+	assert.Equal(t, accepted.Load(), s.Submitted)
+	assert.Equal(t, int64(producers*perProducer), s.Submitted+s.Rejected)
+	assert.Equal(t, s.Submitted, executed.Load())
+	assert.Equal(t, s.Submitted, s.Completed+s.Failed)
+	assert.LessOrEqual(t, s.Panicked, s.Failed)
+	assert.Zero(t, s.InFlight)
+	assert.Zero(t, s.Queued)
+}